@@ -15,6 +15,7 @@
 package backend
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
@@ -30,6 +31,9 @@ import (
 // - regular: for unified accounts
 // - split: for the individual accounts split from a unified account, if the keystore does not support unified accounts, such as the BitBox01.
 // - erc20: for ERC20 token accounts
+// - multisig: for multisig accounts
+// - custom: for accounts at a user-provided keypath
+// - descriptor: for watch-only accounts imported from an output descriptor
 
 // regularAccountCode returns an account code based on a keystore root fingerprint, a coin code and
 // an account number.
@@ -37,6 +41,34 @@ func regularAccountCode(rootFingerprint []byte, coinCode coin.Code, accountNumbe
 	return accountsTypes.Code(fmt.Sprintf("v0-%x-%s-%d", rootFingerprint, coinCode, accountNumber))
 }
 
+// multisigAccountCode returns the account code of a multisig account. The account number is the
+// account number of the BIP-48 keypath of our own key.
+func multisigAccountCode(rootFingerprint []byte, coinCode coin.Code, accountNumber uint16) accountsTypes.Code {
+	return accountsTypes.Code(fmt.Sprintf("v0-%x-%s-multisig-%d", rootFingerprint, coinCode, accountNumber))
+}
+
+// customAccountCode returns the account code of an account at a custom keypath. The keypath is
+// hashed so that the code can be used in URLs and file names.
+func customAccountCode(
+	rootFingerprint []byte,
+	coinCode coin.Code,
+	scriptType signing.ScriptType,
+	keypath signing.AbsoluteKeypath) accountsTypes.Code {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s%s", scriptType, keypath.Encode())))
+	return accountsTypes.Code(fmt.Sprintf("v0-%x-%s-custom-%x", rootFingerprint, coinCode, hash[:8]))
+}
+
+// descriptorAccountCode returns the account code of a watch-only account imported from an output
+// descriptor. The signing configuration is hashed so that the code can be used in URLs and file
+// names.
+func descriptorAccountCode(
+	rootFingerprint []byte,
+	coinCode coin.Code,
+	configuration *signing.Configuration) accountsTypes.Code {
+	hash := sha256.Sum256([]byte(configuration.String()))
+	return accountsTypes.Code(fmt.Sprintf("v0-%x-%s-descriptor-%x", rootFingerprint, coinCode, hash[:8]))
+}
+
 // splitAccountCode returns an account code for split accounts, made by exploding a unified account
 // into one account per signing configuration. This only applies to BTC/LTC.
 func splitAccountCode(parentCode accountsTypes.Code, scriptType signing.ScriptType) accountsTypes.Code {
@@ -15,6 +15,8 @@
 package backend
 
 import (
+	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"math"
@@ -28,10 +30,12 @@ import (
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/bitsurance"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
@@ -78,7 +82,7 @@ func (a AccountsList) lookup(code accountsTypes.Code) accounts.Interface {
 // account transactions.
 func (a AccountsList) lookupByTransactionInternalID(internalID string) (accounts.Interface, error) {
 	for _, account := range a {
-		if account.FatalError() {
+		if account.Config().Config.Inactive || account.FatalError() {
 			continue
 		}
 		if err := account.Initialize(); err != nil {
@@ -104,8 +108,9 @@ func sortAccounts(accounts []accounts.Interface) {
 			order, ok := map[coinpkg.Code]int{
 				coinpkg.CodeBTC:  0,
 				coinpkg.CodeTBTC: 1,
-				coinpkg.CodeLTC:  2,
-				coinpkg.CodeTLTC: 3,
+				coinpkg.CodeTSIG: 2,
+				coinpkg.CodeLTC:  3,
+				coinpkg.CodeTLTC: 4,
 			}[c.Code()]
 			if ok {
 				return order, true
@@ -116,9 +121,9 @@ func sortAccounts(accounts []accounts.Interface) {
 			if ok {
 				switch ethCoin.ChainID() {
 				case params.MainnetChainConfig.ChainID.Uint64():
-					return 4, true
-				case params.SepoliaChainConfig.ChainID.Uint64():
 					return 5, true
+				case params.SepoliaChainConfig.ChainID.Uint64():
+					return 6, true
 				}
 			}
 			return 0, false
@@ -200,7 +205,7 @@ func (backend *Backend) filterAccounts(accountsConfig *config.AccountsConfig, fi
 // SupportedCoins returns the list of coins that can be used with the given keystore.
 func (backend *Backend) SupportedCoins(keystore keystore.Keystore) []coinpkg.Code {
 	allCoins := []coinpkg.Code{
-		coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeRBTC,
+		coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC,
 		coinpkg.CodeLTC, coinpkg.CodeTLTC,
 		coinpkg.CodeETH, coinpkg.CodeSEPETH,
 	}
@@ -312,7 +317,8 @@ func (backend *Backend) AccountsTotalBalanceByKeystore() (map[string]KeystoreTot
 // function persists the change, reinitializes the accounts, and emits a status change event.
 // Additionally, if an account's insurance is canceled or inactive, the account code is added
 // to the frontend config for notifying the user.
-func (backend *Backend) LookupInsuredAccounts(accountCode accountsTypes.Code) ([]bitsurance.AccountDetails, error) {
+func (backend *Backend) LookupInsuredAccounts(
+	ctx context.Context, accountCode accountsTypes.Code) ([]bitsurance.AccountDetails, error) {
 	var accountList []accounts.Interface
 
 	if len(accountCode) > 0 {
@@ -333,7 +339,7 @@ func (backend *Backend) LookupInsuredAccounts(accountCode accountsTypes.Code) ([
 	}
 
 	// check the insurance status of the selected accounts.
-	bitsuranceAccounts, err := bitsurance.LookupBitsuranceAccounts(backend.DevServers(), accountList, backend.httpClient)
+	bitsuranceAccounts, err := bitsurance.LookupBitsuranceAccounts(ctx, backend.DevServers(), accountList, backend.httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -433,7 +439,7 @@ func (backend *Backend) createAndPersistAccountConfig(
 	accountNumberHardened := uint32(accountNumber) + hardenedKeystart
 
 	switch coinCode {
-	case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeRBTC:
+	case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC:
 		bip44Coin := 1 + hardenedKeystart
 		if coinCode == coinpkg.CodeBTC {
 			bip44Coin = hardenedKeystart
@@ -502,7 +508,7 @@ func findHiddenAccount(
 		if !account.SigningConfigurations.ContainsRootFingerprint(rootFingerprint) {
 			continue
 		}
-		if len(account.SigningConfigurations) == 0 {
+		if len(account.SigningConfigurations) == 0 || account.SigningConfigurations.IsMultisig() {
 			continue
 		}
 		accountNumber, err := account.SigningConfigurations[0].AccountNumber()
@@ -532,7 +538,7 @@ func nextAccountNumber(coinCode coinpkg.Code, keystore keystore.Keystore, accoun
 		if !account.SigningConfigurations.ContainsRootFingerprint(rootFingerprint) {
 			continue
 		}
-		if len(account.SigningConfigurations) == 0 {
+		if len(account.SigningConfigurations) == 0 || account.SigningConfigurations.IsMultisig() {
 			continue
 		}
 		accountNumber, err := account.SigningConfigurations[0].AccountNumber()
@@ -631,7 +637,312 @@ func (backend *Backend) CreateAndPersistAccountConfig(
 	return accountCode, nil
 }
 
-// SetAccountActive activates/deactivates an account.
+// CreateAndPersistMultisigAccountConfig adds a P2WSH m-of-n multisig account to the accounts
+// database. Our own key is derived from the keystore at the BIP-48 keypath
+// m/48'/coin'/account'/2', with the next free multisig account number. The keys of the other
+// cosigners are provided with their key origin, e.g. `[d34db33f/48'/0'/0'/2']xpub...`.
+//
+// `name` is the account name, shown to the user. If empty, a default name will be set.
+func (backend *Backend) CreateAndPersistMultisigAccountConfig(
+	coinCode coinpkg.Code,
+	name string,
+	threshold uint32,
+	cosignerKeys []string,
+	keystore keystore.Keystore,
+) (accountsTypes.Code, error) {
+	coin, err := backend.Coin(coinCode)
+	if err != nil {
+		return "", err
+	}
+	var bip44Coin uint32
+	switch coinCode {
+	case coinpkg.CodeBTC:
+		bip44Coin = hardenedKeystart
+	case coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC:
+		bip44Coin = 1 + hardenedKeystart
+	default:
+		return "", errp.Newf("multisig not supported for %s", coinCode)
+	}
+	if !keystore.SupportsAccount(coin, signing.ScriptTypeP2WSH) {
+		return "", errp.New("keystore does not support multisig")
+	}
+	rootFingerprint, err := keystore.RootFingerprint()
+	if err != nil {
+		return "", err
+	}
+	btcCoin, ok := coin.(*btc.Coin)
+	if !ok {
+		return "", errp.Newf("multisig not supported for %s", coinCode)
+	}
+	cosigners := make([]signing.KeyInfo, 0, len(cosignerKeys)+1)
+	for i, cosignerKey := range cosignerKeys {
+		keyInfo, err := signing.NewKeyInfoFromString(cosignerKey)
+		if err != nil {
+			return "", err
+		}
+		// The key is parsed with the standard version of its network, xpub or tpub.
+		if !bytes.Equal(keyInfo.ExtendedPublicKey.Version(), btcCoin.Net().HDPublicKeyID[:]) {
+			return "", errp.Newf("cosigner key %d is not for the %s network", i+1, coin.Name())
+		}
+		cosigners = append(cosigners, *keyInfo)
+	}
+
+	var accountCode accountsTypes.Code
+	err = backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		accountNumber := uint16(0)
+		for _, account := range accountsConfig.Accounts {
+			if account.CoinCode != coinCode || !account.SigningConfigurations.IsMultisig() ||
+				!account.SigningConfigurations.ContainsRootFingerprint(rootFingerprint) {
+				continue
+			}
+			number, err := account.SigningConfigurations[0].AccountNumber()
+			if err != nil {
+				continue
+			}
+			if number+1 > accountNumber {
+				accountNumber = number + 1
+			}
+		}
+		if accountNumber >= accountsHardLimit {
+			return errp.WithStack(errAccountLimitReached)
+		}
+
+		// Script type 2' denotes P2WSH, see BIP-48.
+		keypath := signing.NewAbsoluteKeypathFromUint32(
+			48+hardenedKeystart, bip44Coin, uint32(accountNumber)+hardenedKeystart, 2+hardenedKeystart)
+		extendedPublicKey, err := keystore.ExtendedPublicKey(coin, keypath)
+		if err != nil {
+			return err
+		}
+		ourKey := signing.KeyInfo{
+			RootFingerprint:   rootFingerprint,
+			AbsoluteKeypath:   keypath,
+			ExtendedPublicKey: extendedPublicKey,
+		}
+		signingConfiguration, err := signing.NewBitcoinMultisigConfiguration(
+			signing.ScriptTypeP2WSH, threshold, append([]signing.KeyInfo{ourKey}, cosigners...), 0)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = fmt.Sprintf("%s %d-of-%d", coin.Name(), threshold, len(cosigners)+1)
+		}
+		var accountWatch *bool
+		if accountsConfig.IsKeystoreWatchonly(rootFingerprint) {
+			t := true
+			accountWatch = &t
+		}
+		accountCode = multisigAccountCode(rootFingerprint, coinCode, accountNumber)
+		backend.log.
+			WithField("accountCode", accountCode).
+			WithField("configuration", signingConfiguration.String()).
+			Info("Persisting new multisig account config")
+		return backend.persistAccount(config.Account{
+			Watch:                 accountWatch,
+			CoinCode:              coinCode,
+			Name:                  name,
+			Code:                  accountCode,
+			SigningConfigurations: signing.Configurations{signingConfiguration},
+		}, accountsConfig)
+	})
+	if err != nil {
+		return "", err
+	}
+	backend.ReinitializeAccounts()
+	return accountCode, nil
+}
+
+// CreateAndPersistCustomKeypathAccountConfig adds an account at a keypath chosen by the user, for
+// example to access funds of a wallet that used a nonstandard derivation. For BTC/LTC, `keypath` is
+// the account-level keypath (e.g. m/84'/0'/0') and `scriptType` must be provided. For ETH,
+// `keypath` is the keypath of the address (e.g. m/44'/60'/0'/0/0) and `scriptType` is ignored.
+//
+// `name` is the account name, shown to the user. If empty, a default name will be set.
+func (backend *Backend) CreateAndPersistCustomKeypathAccountConfig(
+	coinCode coinpkg.Code,
+	name string,
+	keypath signing.AbsoluteKeypath,
+	scriptType signing.ScriptType,
+	keystore keystore.Keystore,
+) (accountsTypes.Code, error) {
+	if len(keypath) == 0 {
+		return "", errp.New("keypath cannot be empty")
+	}
+	coin, err := backend.Coin(coinCode)
+	if err != nil {
+		return "", err
+	}
+	rootFingerprint, err := keystore.RootFingerprint()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s %s", coin.Name(), keypath.Encode())
+	}
+
+	var accountCode accountsTypes.Code
+	err = backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		switch coinCode {
+		case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC, coinpkg.CodeLTC, coinpkg.CodeTLTC:
+			switch scriptType {
+			case signing.ScriptTypeP2PKH, signing.ScriptTypeP2WPKHP2SH, signing.ScriptTypeP2WPKH, signing.ScriptTypeP2TR:
+			default:
+				return errp.Newf("unsupported script type: %q", scriptType)
+			}
+			if !keystore.SupportsAccount(coin, scriptType) {
+				return errp.Newf("keystore does not support %s accounts for %s", scriptType, coinCode)
+			}
+			accountCode = customAccountCode(rootFingerprint, coinCode, scriptType, keypath)
+			extendedPublicKey, err := keystore.ExtendedPublicKey(coin, keypath)
+			if err != nil {
+				return err
+			}
+			var accountWatch *bool
+			if accountsConfig.IsKeystoreWatchonly(rootFingerprint) {
+				t := true
+				accountWatch = &t
+			}
+			return backend.persistAccount(config.Account{
+				Watch:    accountWatch,
+				CoinCode: coinCode,
+				Name:     name,
+				Code:     accountCode,
+				SigningConfigurations: signing.Configurations{
+					signing.NewBitcoinConfiguration(scriptType, rootFingerprint, keypath, extendedPublicKey),
+				},
+			}, accountsConfig)
+		case coinpkg.CodeETH, coinpkg.CodeSEPETH:
+			accountCode = customAccountCode(rootFingerprint, coinCode, "", keypath)
+			return backend.persistETHAccountConfig(
+				keystore, coin, accountCode, false, keypath.Encode(), name, nil, accountsConfig)
+		default:
+			return errp.Newf("Unrecognized coin code: %s", coinCode)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	backend.ReinitializeAccounts()
+	return accountCode, nil
+}
+
+// CreateAndPersistDescriptorAccountConfig adds a watch-only BTC/LTC account from an output
+// descriptor, see signing.NewConfigurationFromDescriptor(). No keystore needs to be connected. If
+// the keystore of the descriptor (identified by the root fingerprint of the first key) is not known
+// yet, it is added with the watch-only setting enabled, so that the account is loaded.
+//
+// `name` is the account name, shown to the user. If empty, a default name will be set.
+func (backend *Backend) CreateAndPersistDescriptorAccountConfig(
+	coinCode coinpkg.Code,
+	name string,
+	descriptor string,
+) (accountsTypes.Code, error) {
+	switch coinCode {
+	case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC, coinpkg.CodeLTC, coinpkg.CodeTLTC:
+	default:
+		return "", errp.Newf("descriptors are not supported for %s", coinCode)
+	}
+	coin, err := backend.Coin(coinCode)
+	if err != nil {
+		return "", err
+	}
+	signingConfiguration, err := signing.NewConfigurationFromDescriptor(descriptor)
+	if err != nil {
+		return "", err
+	}
+	return backend.persistWatchonlyAccountConfig(coin, name, signing.Configurations{signingConfiguration})
+}
+
+// CreateAndPersistExtendedPublicKeyAccountConfig adds a watch-only BTC/LTC account from an
+// account-level extended public key in any SLIP-132 encoding (xpub, ypub, zpub, Ltub, Mtub, ...),
+// optionally with origin info, see signing.NewKeyInfoFromExtendedPublicKey(). If `scriptType` is
+// empty, it is inferred from the version of the key.
+//
+// `name` is the account name, shown to the user. If empty, a default name will be set.
+func (backend *Backend) CreateAndPersistExtendedPublicKeyAccountConfig(
+	coinCode coinpkg.Code,
+	name string,
+	extendedPublicKey string,
+	scriptType signing.ScriptType,
+) (accountsTypes.Code, error) {
+	switch coinCode {
+	case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC, coinpkg.CodeLTC, coinpkg.CodeTLTC:
+	default:
+		return "", errp.Newf("extended public keys are not supported for %s", coinCode)
+	}
+	coin, err := backend.Coin(coinCode)
+	if err != nil {
+		return "", err
+	}
+	keyInfo, impliedScriptType, err := signing.NewKeyInfoFromExtendedPublicKey(extendedPublicKey)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case scriptType == "":
+		scriptType = impliedScriptType
+	case impliedScriptType != "" && impliedScriptType != scriptType:
+		return "", errp.Newf("the extended public key is for %s, not %s", impliedScriptType, scriptType)
+	}
+	switch scriptType {
+	case signing.ScriptTypeP2PKH, signing.ScriptTypeP2WPKHP2SH, signing.ScriptTypeP2WPKH, signing.ScriptTypeP2TR:
+	case "":
+		return "", errp.New("the script type is required for this extended public key")
+	default:
+		return "", errp.Newf("unsupported script type: %q", scriptType)
+	}
+	signingConfiguration := signing.NewBitcoinConfiguration(
+		scriptType, keyInfo.RootFingerprint, keyInfo.AbsoluteKeypath, keyInfo.ExtendedPublicKey)
+	return backend.persistWatchonlyAccountConfig(coin, name, signing.Configurations{signingConfiguration})
+}
+
+// persistWatchonlyAccountConfig adds a watch-only account with the given signing configurations,
+// which must share the root fingerprint. The account code is derived from the first configuration.
+// If the keystore of the configurations is not known yet, it is added with the watch-only setting
+// enabled, so that the account is loaded.
+func (backend *Backend) persistWatchonlyAccountConfig(
+	coin coinpkg.Coin,
+	name string,
+	signingConfigurations signing.Configurations,
+) (accountsTypes.Code, error) {
+	coinCode := coin.Code()
+	rootFingerprint, err := signingConfigurations.RootFingerprint()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s watch-only", coin.Name())
+	}
+
+	accountCode := descriptorAccountCode(rootFingerprint, coinCode, signingConfigurations[0])
+	err = backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		if _, err := accountsConfig.LookupKeystore(rootFingerprint); err != nil {
+			keystore := accountsConfig.GetOrAddKeystore(rootFingerprint)
+			keystore.Name = "Watch-only"
+			keystore.Watchonly = true
+		}
+		t := true
+		backend.log.
+			WithField("accountCode", accountCode).
+			WithField("configurations", signingConfigurations).
+			Info("Persisting new watch-only account config")
+		return backend.persistAccount(config.Account{
+			Watch:                 &t,
+			CoinCode:              coinCode,
+			Name:                  name,
+			Code:                  accountCode,
+			SigningConfigurations: signingConfigurations,
+		}, accountsConfig)
+	})
+	if err != nil {
+		return "", err
+	}
+	backend.ReinitializeAccounts()
+	return accountCode, nil
+}
+
+// SetAccountActive activates/deactivates an account. Inactive (archived) accounts are not synced
+// until they are activated again.
 func (backend *Backend) SetAccountActive(accountCode accountsTypes.Code, active bool) error {
 	err := backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
 		acct := accountsConfig.Lookup(accountCode)
@@ -685,6 +996,71 @@ func (backend *Backend) RenameAccount(accountCode accountsTypes.Code, name strin
 	return nil
 }
 
+// SetAccountGapLimit persists the minimum gap limit of a BTC-based account, see
+// `config.Account.GapLimit`, and reinitializes the accounts so the account is scanned with it. A
+// zero gap limit restores the default gap limits.
+func (backend *Backend) SetAccountGapLimit(accountCode accountsTypes.Code, gapLimit uint16) error {
+	err := backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		acct := accountsConfig.Lookup(accountCode)
+		if acct == nil {
+			return errp.Newf("Could not find account %s", accountCode)
+		}
+		switch acct.CoinCode {
+		case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC, coinpkg.CodeLTC, coinpkg.CodeTLTC:
+		default:
+			return errp.New("The gap limit only applies to BTC-based accounts")
+		}
+		acct.GapLimit = gapLimit
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	backend.ReinitializeAccounts()
+	return nil
+}
+
+// SetAccountCoinSelection sets the algorithm selecting the coins spent by new transactions of the
+// BTC-based account, see maketx.CoinSelection. An empty string resets it to the default.
+func (backend *Backend) SetAccountCoinSelection(accountCode accountsTypes.Code, coinSelection string) error {
+	if _, err := maketx.NewCoinSelection(coinSelection); err != nil {
+		return err
+	}
+	return backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		acct := accountsConfig.Lookup(accountCode)
+		if acct == nil {
+			return errp.Newf("Could not find account %s", accountCode)
+		}
+		switch acct.CoinCode {
+		case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC, coinpkg.CodeLTC, coinpkg.CodeTLTC:
+		default:
+			return errp.New("The coin selection only applies to BTC-based accounts")
+		}
+		acct.CoinSelection = coinSelection
+		return nil
+	})
+}
+
+// RescanAccount drops the transactions and address histories cached for the BTC-based account and
+// reloads the accounts, so the account is scanned again from scratch, e.g. after an Electrum server
+// returned bad data. The progress is reported with the sync events of the reloaded account.
+func (backend *Backend) RescanAccount(accountCode accountsTypes.Code) error {
+	defer backend.accountsAndKeystoreLock.Lock()()
+	account := backend.accounts.lookup(accountCode)
+	if account == nil {
+		return errp.Newf("Could not find account %s", accountCode)
+	}
+	btcAccount, ok := account.(*btc.Account)
+	if !ok {
+		return errp.New("Only BTC-based accounts can be rescanned")
+	}
+	backend.log.WithField("code", accountCode).Info("Rescanning account")
+	btcAccount.Close()
+	err := btcAccount.DeleteDB()
+	backend.initAccounts(true)
+	return err
+}
+
 // copyBool makes a copy, so that multiple values do not share the same reference. This avoids
 // potential future bugs if someone modified a flag like `*account.Watch = X`,
 // accidentally changing the value for many accounts that share the same reference.
@@ -760,6 +1136,7 @@ func (backend *Backend) createAndAddAccount(coin coinpkg.Coin, persistedConfig *
 		Config:      persistedConfig,
 		DBFolder:    backend.arguments.CacheDirectoryPath(),
 		NotesFolder: backend.arguments.NotesDirectoryPath(),
+		Vault:       backend.vault,
 		ConnectKeystore: func() (keystore.Keystore, error) {
 			type data struct {
 				Type         string `json:"typ"`
@@ -788,8 +1165,10 @@ func (backend *Backend) createAndAddAccount(coin coinpkg.Coin, persistedConfig *
 						KeystoreName: keystoreName,
 					},
 				})
+				// If the keystore is not registered yet, wait for it even if other keystores
+				// are registered.
 				ks, err = backend.connectKeystore.connect(
-					backend.Keystore(),
+					backend.KeystoreByRootFingerprint(accountRootFingerprint),
 					accountRootFingerprint,
 					timeout,
 				)
@@ -863,6 +1242,8 @@ func (backend *Backend) createAndAddAccount(coin coinpkg.Coin, persistedConfig *
 			}
 			if account != nil && event == accountsTypes.EventSyncDone {
 				backend.notifyNewTxs(account)
+				backend.notifyDustOutputs(account)
+				backend.notifyDroppedTxs(account)
 			}
 		},
 		RateUpdater: backend.ratesUpdater,
@@ -872,6 +1253,13 @@ func (backend *Backend) createAndAddAccount(coin coinpkg.Coin, persistedConfig *
 		GetSaveFilename:  backend.environment.GetSaveFilename,
 		UnsafeSystemOpen: backend.environment.SystemOpen,
 		BtcCurrencyUnit:  backend.config.AppConfig().Backend.BtcUnit,
+		MainFiat: func() string {
+			return backend.config.AppConfig().Backend.MainFiat
+		},
+		DustThreshold: func() int64 {
+			return backend.config.AppConfig().Backend.DustThreshold
+		},
+		SyncScheduler: backend.syncScheduler,
 	}
 
 	switch specificCoin := coin.(type) {
@@ -1130,16 +1518,7 @@ func (backend *Backend) initPersistedAccounts() {
 			return true
 		}
 
-		if backend.keystore == nil {
-			return false
-		}
-		rootFingerprint, err := backend.keystore.RootFingerprint()
-		if err != nil {
-			backend.log.WithError(err).Error("Could not retrieve root fingerprint")
-			return false
-		}
-
-		return account.SigningConfigurations.ContainsRootFingerprint(rootFingerprint)
+		return backend.keystores.owner(account) != nil
 	}
 
 	persistedAccounts := backend.config.AccountsConfig()
@@ -1161,7 +1540,7 @@ outer:
 		// Watch-only accounts are loaded regardless, and if later e.g. a BitBox02 BTC-only is
 		// inserted with the same seed as a Multi, we will need to catch that mismatch when the
 		// keystore will be used to e.g. display an Ethereum address etc.
-		if backend.keystore != nil {
+		if ks := backend.keystores.owner(account); ks != nil {
 			isWatch, err := persistedAccounts.IsAccountWatchonly(account)
 			if err != nil {
 				backend.log.WithError(err).Error("Could not retrieve root fingerprint")
@@ -1171,12 +1550,12 @@ outer:
 				switch coin.(type) {
 				case *btc.Coin:
 					for _, cfg := range account.SigningConfigurations {
-						if !backend.keystore.SupportsAccount(coin, cfg.ScriptType()) {
+						if !ks.SupportsAccount(coin, cfg.ScriptType()) {
 							continue outer
 						}
 					}
 				default:
-					if !backend.keystore.SupportsAccount(coin, nil) {
+					if !ks.SupportsAccount(coin, nil) {
 						continue
 					}
 				}
@@ -1253,14 +1632,16 @@ func (backend *Backend) maybeAddP2TR(keystore keystore.Keystore, accounts []*con
 	for _, account := range accounts {
 		if account.CoinCode == coinpkg.CodeBTC ||
 			account.CoinCode == coinpkg.CodeTBTC ||
+			account.CoinCode == coinpkg.CodeTSIG ||
 			account.CoinCode == coinpkg.CodeRBTC {
 			coin, err := backend.Coin(account.CoinCode)
 			if err != nil {
 				return err
 			}
 			if keystore.SupportsAccount(coin, signing.ScriptTypeP2TR) &&
+				!account.SigningConfigurations.IsMultisig() &&
 				account.SigningConfigurations.FindScriptType(signing.ScriptTypeP2TR) == -1 {
-				rootFingerprint, err := backend.keystore.RootFingerprint()
+				rootFingerprint, err := keystore.RootFingerprint()
 				if err != nil {
 					return err
 				}
@@ -1338,15 +1719,7 @@ func (backend *Backend) uninitAccounts(force bool) {
 	keep := []accounts.Interface{}
 	for _, account := range backend.accounts {
 
-		belongsToKeystore := false
-		if backend.keystore != nil {
-			fingerprint, err := backend.keystore.RootFingerprint()
-			if err != nil {
-				backend.log.WithError(err).Error("could not retrieve keystore fingerprint")
-			} else {
-				belongsToKeystore = account.Config().Config.SigningConfigurations.ContainsRootFingerprint(fingerprint)
-			}
-		}
+		belongsToKeystore := backend.keystores.owner(account.Config().Config) != nil
 
 		isWatchonly, err := backend.config.AccountsConfig().IsAccountWatchonly(account.Config().Config)
 		if err != nil {
@@ -1393,64 +1766,82 @@ func (backend *Backend) maybeAddHiddenUnusedAccounts() {
 		defer backend.tstMaybeAddHiddenUnusedAccounts()
 	}
 	defer backend.accountsAndKeystoreLock.Lock()()
-	if backend.keystore == nil {
-		return
-	}
 	// Only load accounts which belong to connected keystores.
-	rootFingerprint, err := backend.keystore.RootFingerprint()
-	if err != nil {
-		backend.log.WithError(err).Error("Could not retrieve root fingerprint")
-		return
+	for _, registered := range backend.keystores {
+		backend.addHiddenUnusedAccounts(registered.rootFingerprint, registered.keystore)
 	}
+}
 
-	do := func(cfg *config.AccountsConfig, coinCode coinpkg.Code) *accountsTypes.Code {
+// addHiddenUnusedAccounts adds the hidden accounts for scanning of one keystore, see
+// `maybeAddHiddenUnusedAccounts()`. The accountsAndKeystoreLock must be held when calling this
+// function.
+func (backend *Backend) addHiddenUnusedAccounts(rootFingerprint []byte, keystore keystore.Keystore) {
+	// accountNumber returns the account number of a single-sig account of the given coin belonging
+	// to this keystore, or false if the account is not such an account.
+	accountNumber := func(accountConfig *config.Account, coinCode coinpkg.Code) (uint16, bool) {
+		if coinCode != accountConfig.CoinCode {
+			return 0, false
+		}
+		if !accountConfig.SigningConfigurations.ContainsRootFingerprint(rootFingerprint) {
+			return 0, false
+		}
+		if accountConfig.SigningConfigurations.IsMultisig() {
+			return 0, false
+		}
+		number, err := accountConfig.SigningConfigurations[0].AccountNumber()
+		if err != nil {
+			return 0, false
+		}
+		return number, true
+	}
+	// do returns the account number of the added hidden account, or -1 if none was added.
+	do := func(cfg *config.AccountsConfig, coinCode coinpkg.Code) int {
 		log := backend.log.
 			WithField("rootFingerprint", hex.EncodeToString(rootFingerprint)).
 			WithField("coinCode", coinCode)
 
 		maxAccountNumber := -1
-		var maxAccount *config.Account
+		maxAccountUsed := false
 		for _, accountConfig := range cfg.Accounts {
-			if coinCode != accountConfig.CoinCode {
-				continue
-			}
-			if !accountConfig.SigningConfigurations.ContainsRootFingerprint(rootFingerprint) {
+			number, ok := accountNumber(accountConfig, coinCode)
+			if !ok {
 				continue
 			}
-			accountNumber, err := accountConfig.SigningConfigurations[0].AccountNumber()
-			if err != nil {
-				continue
-			}
-			if maxAccount == nil || int(accountNumber) > maxAccountNumber {
-				maxAccountNumber = int(accountNumber)
-				maxAccount = accountConfig
+			switch {
+			case int(number) > maxAccountNumber:
+				maxAccountNumber = int(number)
+				maxAccountUsed = accountConfig.Used
+			case int(number) == maxAccountNumber:
+				// Keystores without unified accounts have one account per script type, all sharing
+				// the same account number. The account number is used if any of them is used.
+				maxAccountUsed = maxAccountUsed || accountConfig.Used
 			}
 		}
 		// Account scan gap limit:
 		// - Previous account must be used for the next one to be scanned, but:
 		// - The first 5 accounts are always scanned as before we had accounts discovery, the
 		//   BitBoxApp allowed manual creation of 5 accounts, so we need to always scan these.
-		if maxAccount == nil || maxAccount.Used || maxAccountNumber < accountsHardLimit {
+		if maxAccountNumber == -1 || maxAccountUsed || maxAccountNumber < accountsHardLimit {
 			accountCode, err := backend.createAndPersistAccountConfig(
 				coinCode,
 				uint16(maxAccountNumber+1),
 				true,
 				"",
-				backend.keystore,
+				keystore,
 				nil,
 				cfg,
 			)
 			if err != nil {
 				log.WithError(err).Error("adding hidden account failed")
-				return nil
+				return -1
 			}
 			log.
 				WithField("accountCode", accountCode).
 				WithField("accountNumber", maxAccountNumber+1).
 				Info("automatically created hidden account")
-			return &accountCode
+			return maxAccountNumber + 1
 		}
-		return nil
+		return -1
 	}
 
 	// Enable accounts discovery for these coins.
@@ -1464,9 +1855,14 @@ func (backend *Backend) maybeAddHiddenUnusedAccounts() {
 		coinCodes = []coinpkg.Code{coinpkg.CodeBTC, coinpkg.CodeLTC}
 	}
 	for _, coinCode := range coinCodes {
-		var newAccountCode *accountsTypes.Code
+		coin, err := backend.Coin(coinCode)
+		if err != nil {
+			backend.log.Errorf("could not find coin %s", coinCode)
+			continue
+		}
+		newAccountNumber := -1
 		err = backend.config.ModifyAccountsConfig(func(cfg *config.AccountsConfig) error {
-			newAccountCode = do(cfg, coinCode)
+			newAccountNumber = do(cfg, coinCode)
 			return nil
 		})
 		if err != nil {
@@ -1476,29 +1872,37 @@ func (backend *Backend) maybeAddHiddenUnusedAccounts() {
 				Error("maybeAddHiddenUnusedAccounts failed")
 			continue
 		}
-		if newAccountCode != nil {
-			coin, err := backend.Coin(coinCode)
-			if err != nil {
-				backend.log.Errorf("could not find coin %s", coinCode)
-				continue
-			}
-			accountConfig := backend.config.AccountsConfig().Lookup(*newAccountCode)
-			if accountConfig == nil {
-				backend.log.Errorf("could not find newly persisted account %s", *newAccountCode)
+		if newAccountNumber == -1 {
+			continue
+		}
+		// Load the new accounts. Without unified accounts, there is one new account per script type.
+		// There is none if the keystore does not support the coin.
+		added := false
+		for _, accountConfig := range backend.config.AccountsConfig().Accounts {
+			number, ok := accountNumber(accountConfig, coinCode)
+			if !ok || int(number) != newAccountNumber || backend.accounts.lookup(accountConfig.Code) != nil {
 				continue
 			}
 			backend.createAndAddAccount(coin, accountConfig)
+			added = true
+		}
+		if added {
 			backend.emitAccountsStatusChanged()
 		}
 	}
 }
 
 func (backend *Backend) checkAccountUsed(account accounts.Interface) {
+	defer crashreport.Recover()
 	if backend.tstCheckAccountUsed != nil {
 		if !backend.tstCheckAccountUsed(account) {
 			return
 		}
 	}
+	if account.Config().Config.Inactive {
+		// Archived accounts are not synced.
+		return
+	}
 	log := backend.log.WithField("accountCode", account.Config().Config.Code)
 	if err := account.Initialize(); err != nil {
 		log.WithError(err).Error("error initializing account")
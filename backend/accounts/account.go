@@ -16,6 +16,7 @@
 package accounts
 
 import (
+	"context"
 	"io"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
@@ -58,6 +59,21 @@ type TxProposalArgs struct {
 	SelectedUTXOs  map[wire.OutPoint]struct{}
 	Note           string
 	PaymentRequest *PaymentRequest
+	// PayjoinURL is the payjoin endpoint given in the `pj=` parameter of a BIP-21 URI. If not
+	// empty, the transaction is sent using Payjoin (BIP-78) if possible. Only applies to BTC/LTC.
+	PayjoinURL string
+	// Recipients, if not empty, are the recipients of a transaction paying multiple parties at
+	// once, and RecipientAddress and Amount are ignored. Only supported for BTC/LTC.
+	Recipients []TxRecipient
+	// CoinSelection is the algorithm selecting the coins to spend, see maketx.CoinSelection. If
+	// empty, the algorithm configured for the account is used. Only applies to BTC/LTC.
+	CoinSelection string
+}
+
+// TxRecipient is a recipient of a transaction with multiple recipients.
+type TxRecipient struct {
+	Address string
+	Amount  coin.SendAmount
 }
 
 // Interface is the API of a Account.
@@ -91,9 +107,9 @@ type Interface interface {
 	Balance() (*Balance, error)
 	// SendTx signs and sends the active tx proposal, set by TxProposal. Errors if none
 	// available.
-	SendTx(txNote string) error
-	FeeTargets() ([]FeeTarget, FeeTargetCode)
-	TxProposal(*TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error)
+	SendTx(ctx context.Context, txNote string) error
+	FeeTargets(ctx context.Context) ([]FeeTarget, FeeTargetCode)
+	TxProposal(context.Context, *TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error)
 	// GetUnusedReceiveAddresses gets a list of list of receive addresses. The result can be one
 	// list of addresses, or if there are multiple types of addresses (e.g. `bc1...` vs `3...`), a
 	// list of lists.
@@ -106,8 +122,9 @@ type Interface interface {
 	// SetTxNote sets a tx note and refreshes the account.
 	SetTxNote(txID string, note string) error
 
-	// ExportCSV exports the given transaction in CSV format (comma-separated).
-	ExportCSV(w io.Writer, transactions []*TransactionData) error
+	// ExportCSV exports the given transaction in CSV format (comma-separated), using the given
+	// format.
+	ExportCSV(w io.Writer, transactions []*TransactionData, format ExportFormat) error
 }
 
 // Info holds account information.
@@ -14,12 +14,17 @@
 
 package accounts
 
-import "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+import (
+	"math/big"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+)
 
 // Balance contains the available and incoming balance of an account.
 type Balance struct {
 	available coin.Amount
 	incoming  coin.Amount
+	frozen    coin.Amount
 }
 
 // NewBalance creates a new balance with the given amounts.
@@ -27,6 +32,17 @@ func NewBalance(available coin.Amount, incoming coin.Amount) *Balance {
 	return &Balance{
 		available: available,
 		incoming:  incoming,
+		frozen:    coin.NewAmountFromInt64(0),
+	}
+}
+
+// NewBalanceWithFrozen creates a new balance with the given amounts. frozen is the part of the
+// available amount the user marked as "do not spend".
+func NewBalanceWithFrozen(available coin.Amount, incoming coin.Amount, frozen coin.Amount) *Balance {
+	return &Balance{
+		available: available,
+		incoming:  incoming,
+		frozen:    frozen,
 	}
 }
 
@@ -40,3 +56,14 @@ func (balance *Balance) Available() coin.Amount {
 func (balance *Balance) Incoming() coin.Amount {
 	return balance.incoming
 }
+
+// Frozen returns the sum of the available coins marked as "do not spend".
+func (balance *Balance) Frozen() coin.Amount {
+	return balance.frozen
+}
+
+// Spendable returns the available balance without the frozen coins, which is what can be spent
+// without explicitly selecting frozen coins.
+func (balance *Balance) Spendable() coin.Amount {
+	return coin.NewAmount(new(big.Int).Sub(balance.available.BigInt(), balance.frozen.BigInt()))
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"path"
 	"sync/atomic"
@@ -31,11 +32,18 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/rates"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/syncscheduler"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/metrics"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
 	"github.com/sirupsen/logrus"
 )
 
+// syncDurationMetric measures how long it takes to sync accounts, by coin.
+var syncDurationMetric = metrics.NewSummaryVec(
+	"account_sync_duration_seconds", "Duration of account syncs.", "coin")
+
 // AccountConfig holds account configuration.
 type AccountConfig struct {
 	// Pointer to persisted config. Do not modify this directly. Use
@@ -43,7 +51,9 @@ type AccountConfig struct {
 	Config   *config.Account
 	DBFolder string
 	// NotesFolder is the folder where the transaction notes are stored. Full path.
-	NotesFolder     string
+	NotesFolder string
+	// Vault encrypts the notes if the encryption is enabled. Can be nil.
+	Vault           *vault.Vault
 	ConnectKeystore func() (keystore.Keystore, error)
 	OnEvent         func(types.Event)
 	RateUpdater     *rates.RateUpdater
@@ -53,6 +63,15 @@ type AccountConfig struct {
 	UnsafeSystemOpen func(filename string) error
 	// BtcCurrencyUnit is the unit which should be used to format fiat amounts values expressed in BTC..
 	BtcCurrencyUnit coin.BtcUnit
+	// MainFiat returns the fiat currency selected by the user, used e.g. to value transactions in
+	// exports. Can be nil.
+	MainFiat func() string
+	// DustThreshold returns the value in satoshi below which incoming coins on already used
+	// addresses are flagged as a possible dusting attack. 0 disables the detection. Can be nil.
+	// Only used by BTC-based accounts.
+	DustThreshold func() int64
+	// SyncScheduler throttles background polling on battery or metered networks. Can be nil.
+	SyncScheduler *syncscheduler.Scheduler
 }
 
 // BaseAccount is an account struct with common functionality to all coin accounts.
@@ -68,6 +87,11 @@ type BaseAccount struct {
 	// addresses.
 	synced  atomic.Bool
 	offline error
+	// syncStartedAt is when the current sync started. It is only accessed in the callbacks of the
+	// synchronizer, which are not called concurrently.
+	syncStartedAt time.Time
+	// onSyncDone is called each time a sync finishes, see SetOnSyncDone().
+	onSyncDone func()
 
 	// notes handles transaction notes.
 	notes *notes.Notes
@@ -83,8 +107,15 @@ func NewBaseAccount(config *AccountConfig, coin coin.Coin, log *logrus.Entry) *B
 		log:    log,
 	}
 	account.Synchronizer = synchronizer.NewSynchronizer(
-		func() { config.OnEvent(types.EventSyncStarted) },
 		func() {
+			account.syncStartedAt = time.Now()
+			config.OnEvent(types.EventSyncStarted)
+		},
+		func() {
+			syncDurationMetric.Observe(string(coin.Code()), time.Since(account.syncStartedAt).Seconds())
+			if account.onSyncDone != nil {
+				account.onSyncDone()
+			}
 			if account.synced.CompareAndSwap(false, true) {
 				config.OnEvent(types.EventStatusChanged)
 			}
@@ -105,6 +136,12 @@ func (account *BaseAccount) Coin() coin.Coin {
 	return account.coin
 }
 
+// SetOnSyncDone sets a function which is called each time a sync finishes, before the events
+// signaling it are emitted. It must be set before the account starts syncing.
+func (account *BaseAccount) SetOnSyncDone(onSyncDone func()) {
+	account.onSyncDone = onSyncDone
+}
+
 // Synced implements Interface.
 func (account *BaseAccount) Synced() bool {
 	return account.synced.Load()
@@ -135,10 +172,10 @@ func (account *BaseAccount) SetOffline(offline error) {
 // Initialize initializes the account. `accountIdentifier` is used as part of the filename of
 // account databases.
 func (account *BaseAccount) Initialize(accountIdentifier string) error {
-	txNotes, err := notes.LoadNotes(path.Join(
+	txNotes, err := notes.LoadEncryptedNotes(path.Join(
 		account.config.NotesFolder,
 		fmt.Sprintf("%s.json", accountIdentifier),
-	))
+	), account.config.Vault)
 	if err != nil {
 		return err
 	}
@@ -206,10 +243,10 @@ func (account *BaseAccount) migrateLegacyNotes() error {
 			continue // skip nonexistent legacy notes file
 		}
 
-		legacyNotes, err := notes.LoadNotes(path.Join(
+		legacyNotes, err := notes.LoadEncryptedNotes(path.Join(
 			account.config.NotesFolder,
 			fmt.Sprintf("%s.json", identifier),
-		))
+		), account.config.Vault)
 		if err != nil {
 			return err
 		}
@@ -231,14 +268,88 @@ func (account *BaseAccount) SetTxNote(txID string, note string) error {
 	return nil
 }
 
+// SetUTXOFrozen marks a coin as "do not spend", so that it is not used in new transactions, or
+// removes the mark.
+func (account *BaseAccount) SetUTXOFrozen(outPoint string, frozen bool) error {
+	if _, err := account.notes.SetUTXOFrozen(outPoint, frozen); err != nil {
+		return err
+	}
+	// Prompt refresh.
+	account.config.OnEvent(types.EventStatusChanged)
+	return nil
+}
+
+// UTXOFrozen returns true if the coin with the given outpoint is marked as "do not spend".
+func (account *BaseAccount) UTXOFrozen(outPoint string) bool {
+	return account.notes.UTXOFrozen(outPoint)
+}
+
+// SetAddressNote sets the label of a receive address, identified by its encoding, and refreshes the
+// account.
+func (account *BaseAccount) SetAddressNote(address string, note string) error {
+	if _, err := account.notes.SetAddressNote(address, note); err != nil {
+		return err
+	}
+	// Prompt refresh.
+	account.config.OnEvent(types.EventStatusChanged)
+	return nil
+}
+
+// AddressNote fetches the label of a receive address. Returns the empty string if no label was
+// found.
+func (account *BaseAccount) AddressNote(address string) string {
+	return account.notes.AddressNote(address)
+}
+
 // TxNote fetches a note for a transaction. Returns the empty string if no note was found.
 func (account *BaseAccount) TxNote(txID string) string {
 	return account.notes.TxNote(txID)
 }
 
+// fiatValueAt returns the value of the amount in the given fiat currency using the historical
+// exchange rate at the given time. The empty string is returned if no rate is available.
+func (account *BaseAccount) fiatValueAt(amount coin.Amount, fiat string, at *time.Time) string {
+	if account.config.RateUpdater == nil || fiat == "" || at == nil {
+		return ""
+	}
+	price := account.config.RateUpdater.HistoricalPriceAt(string(account.coin.Code()), fiat, *at)
+	if price == 0 {
+		return ""
+	}
+	value := new(big.Rat).Mul(
+		new(big.Rat).SetFloat64(account.coin.ToUnit(amount, false)),
+		new(big.Rat).SetFloat64(price),
+	)
+	return coin.FormatAsPlainCurrency(value, fiat)
+}
+
 // ExportCSV implements accounts.Account.
-func (account *BaseAccount) ExportCSV(w io.Writer, transactions []*TransactionData) error {
+func (account *BaseAccount) ExportCSV(
+	w io.Writer, transactions []*TransactionData, format ExportFormat) error {
+	fiat := ""
+	if account.config.MainFiat != nil {
+		fiat = account.config.MainFiat()
+	}
 	writer := csv.NewWriter(w)
+	var err error
+	switch format {
+	case ExportFormatCoinTracking:
+		err = account.exportCoinTrackingCSV(writer, transactions, fiat)
+	case ExportFormatKoinly:
+		err = account.exportKoinlyCSV(writer, transactions, fiat)
+	default:
+		err = account.exportDefaultCSV(writer, transactions, fiat)
+	}
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportDefaultCSV writes the transactions in the app's own format, one row per output.
+func (account *BaseAccount) exportDefaultCSV(
+	writer *csv.Writer, transactions []*TransactionData, fiat string) error {
 	err := writer.Write([]string{
 		"Time",
 		"Type",
@@ -249,6 +360,8 @@ func (account *BaseAccount) ExportCSV(w io.Writer, transactions []*TransactionDa
 		"Address",
 		"Transaction ID",
 		"Note",
+		"Fiat Value",
+		"Fiat Unit",
 	})
 	if err != nil {
 		return errp.WithStack(err)
@@ -282,6 +395,11 @@ func (account *BaseAccount) ExportCSV(w io.Writer, transactions []*TransactionDa
 			}
 
 			amount := addressAndAmount.Amount.BigInt().String()
+			fiatValue := account.fiatValueAt(addressAndAmount.Amount, fiat, transaction.Timestamp)
+			fiatUnit := ""
+			if fiatValue != "" {
+				fiatUnit = fiat
+			}
 
 			// When dealing with ERC20 tokens, we need to format the amount
 			// based on the number of decimals for that token.
@@ -298,6 +416,8 @@ func (account *BaseAccount) ExportCSV(w io.Writer, transactions []*TransactionDa
 				addressAndAmount.Address,
 				transaction.TxID,
 				account.TxNote(transaction.InternalID),
+				fiatValue,
+				fiatUnit,
 			})
 			if err != nil {
 				return errp.WithStack(err)
@@ -308,6 +428,5 @@ func (account *BaseAccount) ExportCSV(w io.Writer, transactions []*TransactionDa
 			feeUnit = ""
 		}
 	}
-	writer.Flush()
-	return writer.Error()
+	return nil
 }
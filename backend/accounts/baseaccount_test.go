@@ -26,6 +26,7 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin/mocks"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/rates"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
@@ -191,11 +192,11 @@ func TestBaseAccount(t *testing.T) {
 	// Setup export tests
 	export := func(account *BaseAccount, transactions []*TransactionData) string {
 		var result bytes.Buffer
-		require.NoError(t, account.ExportCSV(&result, transactions))
+		require.NoError(t, account.ExportCSV(&result, transactions, ExportFormatDefault))
 		return result.String()
 	}
 
-	const header = "Time,Type,Amount,Unit,Fee,Fee Unit,Address,Transaction ID,Note\n"
+	const header = "Time,Type,Amount,Unit,Fee,Fee Unit,Address,Transaction ID,Note,Fiat Value,Fiat Unit\n"
 	fee := coin.NewAmountFromInt64(101)
 	timestamp := time.Date(2020, 2, 30, 16, 44, 20, 0, time.UTC)
 
@@ -206,9 +207,9 @@ func TestBaseAccount(t *testing.T) {
 		require.NoError(t, account.SetTxNote("some-internal-tx-id", "some note, with a comma"))
 		require.Equal(t,
 			header+
-				`2020-03-01T16:44:20Z,sent,123,satoshi,101,satoshi,some-address,some-tx-id,"some note, with a comma"
-2020-03-01T16:44:20Z,sent_to_yourself,456,satoshi,,,another-address,some-tx-id,"some note, with a comma"
-2020-03-01T16:44:20Z,received,789,satoshi,,,some-address-2,some-tx-id-2,
+				`2020-03-01T16:44:20Z,sent,123,satoshi,101,satoshi,some-address,some-tx-id,"some note, with a comma",,
+2020-03-01T16:44:20Z,sent_to_yourself,456,satoshi,,,another-address,some-tx-id,"some note, with a comma",,
+2020-03-01T16:44:20Z,received,789,satoshi,,,some-address-2,some-tx-id-2,,,
 `,
 			export(account, []*TransactionData{
 				{
@@ -272,9 +273,9 @@ func TestBaseAccount(t *testing.T) {
 		require.NoError(t, account.SetTxNote("some-internal-tx-id", "some note, with a comma"))
 		require.Equal(t,
 			header+
-				`2020-03-01T16:44:20Z,sent,123,USDT,101,wei,some-address,some-tx-id,"some note, with a comma"
-2020-03-01T16:44:20Z,sent_to_yourself,456,USDT,,,another-address,some-tx-id,"some note, with a comma"
-2020-03-01T16:44:20Z,received,789,USDT,,,some-address-2,some-tx-id-2,
+				`2020-03-01T16:44:20Z,sent,123,USDT,101,wei,some-address,some-tx-id,"some note, with a comma",,
+2020-03-01T16:44:20Z,sent_to_yourself,456,USDT,,,another-address,some-tx-id,"some note, with a comma",,
+2020-03-01T16:44:20Z,received,789,USDT,,,some-address-2,some-tx-id-2,,,
 `,
 			export(account, []*TransactionData{
 				{
@@ -314,4 +315,143 @@ func TestBaseAccount(t *testing.T) {
 				},
 			}))
 	})
+	t.Run("exportCSV with fiat values", func(t *testing.T) {
+		ratesUpdater := rates.MockRateUpdater()
+		defer ratesUpdater.Stop()
+		cfg := *cfg
+		cfg.RateUpdater = ratesUpdater
+		cfg.MainFiat = func() string { return rates.USD.String() }
+		mockCoin := &mocks.CoinMock{
+			CodeFunc: func() coin.Code {
+				return coin.CodeBTC
+			},
+			SmallestUnitFunc: func() string {
+				return "satoshi"
+			},
+			ToUnitFunc: func(amount coin.Amount, isFee bool) float64 {
+				return float64(amount.BigInt().Int64()) / 1e8
+			},
+		}
+		account := NewBaseAccount(&cfg, mockCoin, logging.Get().WithGroup("baseaccount_test"))
+		require.NoError(t, account.Initialize(accountIdentifier))
+
+		// The rate is 2 USD/BTC at this time, see rates.MockRateUpdater().
+		timestamp := time.Unix(1598918700, 0).UTC()
+		// No historical rate available.
+		otherTimestamp := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.Equal(t,
+			header+
+				`2020-09-01T00:05:00Z,received,150000000,satoshi,,,some-address,some-tx-id,,3.00,USD
+2015-01-01T00:00:00Z,received,100000000,satoshi,,,some-address-2,some-tx-id-2,,,
+`,
+			export(account, []*TransactionData{
+				{
+					Type:       TxTypeReceive,
+					TxID:       "some-tx-id",
+					InternalID: "some-internal-tx-id-3",
+					Timestamp:  &timestamp,
+					Addresses: []AddressAndAmount{
+						{
+							Address: "some-address",
+							Amount:  coin.NewAmountFromInt64(150000000),
+						},
+					},
+				},
+				{
+					Type:       TxTypeReceive,
+					TxID:       "some-tx-id-2",
+					InternalID: "some-internal-tx-id-4",
+					Timestamp:  &otherTimestamp,
+					Addresses: []AddressAndAmount{
+						{
+							Address: "some-address-2",
+							Amount:  coin.NewAmountFromInt64(100000000),
+						},
+					},
+				},
+			}))
+	})
+	t.Run("export tax formats", func(t *testing.T) {
+		ratesUpdater := rates.MockRateUpdater()
+		defer ratesUpdater.Stop()
+		cfg := *cfg
+		cfg.RateUpdater = ratesUpdater
+		cfg.MainFiat = func() string { return rates.USD.String() }
+		mockCoin := &mocks.CoinMock{
+			CodeFunc: func() coin.Code {
+				return coin.CodeBTC
+			},
+			UnitFunc: func(isFee bool) string {
+				return "BTC"
+			},
+			DecimalsFunc: func(isFee bool) uint {
+				return 8
+			},
+			ToUnitFunc: func(amount coin.Amount, isFee bool) float64 {
+				return float64(amount.BigInt().Int64()) / 1e8
+			},
+		}
+		account := NewBaseAccount(&cfg, mockCoin, logging.Get().WithGroup("baseaccount_test"))
+		require.NoError(t, account.Initialize(accountIdentifier))
+		require.NoError(t, account.SetTxNote("tax-internal-tx-id", "rent"))
+
+		// The rate is 2 USD/BTC at this time, see rates.MockRateUpdater().
+		timestamp := time.Unix(1598918700, 0).UTC()
+		transactions := []*TransactionData{
+			{
+				Type:       TxTypeReceive,
+				TxID:       "tx-id-1",
+				InternalID: "internal-tx-id-1",
+				Timestamp:  &timestamp,
+				Addresses: []AddressAndAmount{
+					{Address: "address-1", Amount: coin.NewAmountFromInt64(150000000), Ours: true},
+				},
+			},
+			{
+				Type:       TxTypeSend,
+				TxID:       "tx-id-2",
+				InternalID: "tax-internal-tx-id",
+				Fee:        &fee,
+				Timestamp:  &timestamp,
+				Addresses: []AddressAndAmount{
+					{Address: "address-2", Amount: coin.NewAmountFromInt64(50000000)},
+					{Address: "address-3", Amount: coin.NewAmountFromInt64(1000)},
+				},
+			},
+			{
+				Type:       TxTypeSendSelf,
+				TxID:       "tx-id-3",
+				InternalID: "internal-tx-id-3",
+				Fee:        &fee,
+				Addresses: []AddressAndAmount{
+					{Address: "address-4", Amount: coin.NewAmountFromInt64(1000), Ours: true},
+				},
+			},
+		}
+
+		var result bytes.Buffer
+		require.NoError(t, account.ExportCSV(&result, transactions, ExportFormatKoinly))
+		require.Equal(t,
+			`Date,Sent Amount,Sent Currency,Received Amount,Received Currency,Fee Amount,Fee Currency,Net Worth Amount,Net Worth Currency,Label,Description,TxHash
+2020-09-01 00:05:00 UTC,,,1.5,BTC,,,3.00,USD,,,tx-id-1
+2020-09-01 00:05:00 UTC,0.5,BTC,,,0.00000101,BTC,1.00,USD,,rent,tx-id-2
+2020-09-01 00:05:00 UTC,0.00001,BTC,,,,,0.00,USD,,rent,tx-id-2
+,0.00000101,BTC,,,,,,,cost,,tx-id-3
+`,
+			result.String())
+
+		result.Reset()
+		require.NoError(t, account.ExportCSV(&result, transactions, ExportFormatCoinTracking))
+		require.Equal(t,
+			`Type,Buy Amount,Buy Currency,Sell Amount,Sell Currency,Fee,Fee Currency,Exchange,Trade-Group,Comment,Date,Tx-ID,Buy Value in Account Currency,Sell Value in Account Currency
+Deposit,1.5,BTC,,,,,BitBoxApp,,,2020-09-01 00:05:00,tx-id-1,3.00,
+Withdrawal,,,0.5,BTC,0.00000101,BTC,BitBoxApp,,rent,2020-09-01 00:05:00,tx-id-2,,1.00
+Withdrawal,,,0.00001,BTC,,,BitBoxApp,,rent,2020-09-01 00:05:00,tx-id-2,,0.00
+Other Fee,,,0.00000101,BTC,,,BitBoxApp,,,,tx-id-3,,
+`,
+			result.String())
+
+		_, err := NewExportFormat("unknown")
+		require.Error(t, err)
+	})
 }
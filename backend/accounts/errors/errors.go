@@ -16,6 +16,7 @@ package errors
 
 import (
 	errpkg "errors"
+	"fmt"
 )
 
 // TxValidationError represents errors in the tx proposal input data.
@@ -25,6 +26,18 @@ func (err TxValidationError) Error() string {
 	return string(err)
 }
 
+// TxOutputError is a validation error of one of the outputs of a transaction with multiple
+// recipients.
+type TxOutputError struct {
+	// Index is the index of the recipient in the proposal.
+	Index int
+	Err   TxValidationError
+}
+
+func (err *TxOutputError) Error() string {
+	return fmt.Sprintf("output %d: %s", err.Index, err.Err)
+}
+
 var (
 	// ErrFeesNotAvailable is returned when there was an error estimating fees.
 	ErrFeesNotAvailable = TxValidationError("feesNotAvailable")
@@ -39,8 +52,21 @@ var (
 	// ErrFeeTooLow is returned when the custom fee the user entered is too low to be able to
 	// broadcast the transaction.
 	ErrFeeTooLow = TxValidationError("feeTooLow")
+	// ErrFeeTooHigh is returned when the custom fee the user entered results in a fee which is
+	// absurdly high compared to the amount sent, which is most likely a typo.
+	ErrFeeTooHigh = TxValidationError("feeTooHigh")
 	// ErrAccountNotsynced is used when the account sync has not successfully finished.
 	ErrAccountNotsynced = TxValidationError("accountNotSynced")
+	// ErrContactMismatch is returned when the recipient address does not match the address of the
+	// selected address book contact.
+	ErrContactMismatch = TxValidationError("contactMismatch")
+	// ErrMultisigCosignersRequired is returned when sending from a multisig account which needs the
+	// signatures of cosigners in addition to ours. The transaction has to be exported as a PSBT,
+	// co-signed and then sent as a PSBT instead.
+	ErrMultisigCosignersRequired = TxValidationError("multisigCosignersRequired")
+	// ErrInvalidPrivateKey is returned when a private key to be swept is malformatted or does not
+	// match the network.
+	ErrInvalidPrivateKey = TxValidationError("invalidPrivateKey")
 
 	// ErrNotAvailable is returned if data required is not available yet. Example: the headers are
 	// not synced yet, which is a prerequisite to making a timeseries of the portfolio.
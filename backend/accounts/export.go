@@ -0,0 +1,266 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accounts
+
+import (
+	"encoding/csv"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// ExportFormat is the CSV format of a transactions export.
+type ExportFormat string
+
+const (
+	// ExportFormatDefault is the app's own format, listing each output of a transaction.
+	ExportFormatDefault ExportFormat = ""
+	// ExportFormatCoinTracking is the CoinTracking CSV import format.
+	ExportFormatCoinTracking ExportFormat = "cointracking"
+	// ExportFormatKoinly is the Koinly universal CSV import format.
+	ExportFormatKoinly ExportFormat = "koinly"
+)
+
+// NewExportFormat parses an export format, e.g. as provided by the frontend.
+func NewExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(format) {
+	case ExportFormatDefault, ExportFormatCoinTracking, ExportFormatKoinly:
+		return ExportFormat(format), nil
+	}
+	return "", errp.Newf("unknown export format: %s", format)
+}
+
+// taxExportEntry is a single movement of funds, as listed by accounting tools.
+type taxExportEntry struct {
+	timestamp *time.Time
+	received  bool
+	// amount is nil if the entry only accounts for a paid fee, e.g. when sending to ourselves.
+	amount    *coin.Amount
+	fee       *coin.Amount
+	fiatValue string
+	txID      string
+	note      string
+}
+
+// taxExportEntries converts the transactions to incoming and outgoing movements of funds. Outputs
+// sent to ourselves are not listed, only the fee paid for them.
+func (account *BaseAccount) taxExportEntries(
+	transactions []*TransactionData, fiat string) []taxExportEntry {
+	entries := []taxExportEntry{}
+	for _, transaction := range transactions {
+		note := account.TxNote(transaction.InternalID)
+		txEntries := []taxExportEntry{}
+		for _, addressAndAmount := range transaction.Addresses {
+			if transaction.Type != TxTypeReceive &&
+				(transaction.Type == TxTypeSendSelf || addressAndAmount.Ours) {
+				continue
+			}
+			amount := addressAndAmount.Amount
+			txEntries = append(txEntries, taxExportEntry{
+				timestamp: transaction.Timestamp,
+				received:  transaction.Type == TxTypeReceive,
+				amount:    &amount,
+				fiatValue: account.fiatValueAt(amount, fiat, transaction.Timestamp),
+				txID:      transaction.TxID,
+				note:      note,
+			})
+		}
+		if transaction.Type != TxTypeReceive && transaction.Fee != nil {
+			if len(txEntries) > 0 {
+				// Show the tx fee only in the first entry.
+				txEntries[0].fee = transaction.Fee
+			} else {
+				fiatValue := ""
+				// The fee of ERC20 transactions is not paid in the token.
+				if !transaction.IsErc20 {
+					fiatValue = account.fiatValueAt(*transaction.Fee, fiat, transaction.Timestamp)
+				}
+				txEntries = append(txEntries, taxExportEntry{
+					timestamp: transaction.Timestamp,
+					fee:       transaction.Fee,
+					fiatValue: fiatValue,
+					txID:      transaction.TxID,
+					note:      note,
+				})
+			}
+		}
+		entries = append(entries, txEntries...)
+	}
+	return entries
+}
+
+// formatExportAmount formats an amount in the default unit of the coin, without thousands
+// separators and trailing zeros, as expected by accounting tools.
+func (account *BaseAccount) formatExportAmount(amount coin.Amount, isFee bool) string {
+	decimals := account.coin.Decimals(isFee)
+	value := new(big.Rat).SetFrac(
+		amount.BigInt(),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil),
+	)
+	formatted := value.FloatString(int(decimals))
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+	return formatted
+}
+
+// exportKoinlyCSV writes the transactions in the Koinly universal CSV format.
+func (account *BaseAccount) exportKoinlyCSV(
+	writer *csv.Writer, transactions []*TransactionData, fiat string) error {
+	err := writer.Write([]string{
+		"Date",
+		"Sent Amount",
+		"Sent Currency",
+		"Received Amount",
+		"Received Currency",
+		"Fee Amount",
+		"Fee Currency",
+		"Net Worth Amount",
+		"Net Worth Currency",
+		"Label",
+		"Description",
+		"TxHash",
+	})
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	unit := account.coin.Unit(false)
+	feeUnit := account.coin.Unit(true)
+	for _, entry := range account.taxExportEntries(transactions, fiat) {
+		timeString := ""
+		if entry.timestamp != nil {
+			timeString = entry.timestamp.UTC().Format("2006-01-02 15:04:05") + " UTC"
+		}
+		var sentAmount, sentUnit, receivedAmount, receivedUnit, fee, feeCurrency, label string
+		switch {
+		case entry.amount == nil:
+			// Koinly expects fees not attached to a transfer as a cost.
+			sentAmount = account.formatExportAmount(*entry.fee, true)
+			sentUnit = feeUnit
+			label = "cost"
+		case entry.received:
+			receivedAmount = account.formatExportAmount(*entry.amount, false)
+			receivedUnit = unit
+		default:
+			sentAmount = account.formatExportAmount(*entry.amount, false)
+			sentUnit = unit
+		}
+		if entry.amount != nil && entry.fee != nil {
+			fee = account.formatExportAmount(*entry.fee, true)
+			feeCurrency = feeUnit
+		}
+		fiatUnit := ""
+		if entry.fiatValue != "" {
+			fiatUnit = fiat
+		}
+		err := writer.Write([]string{
+			timeString,
+			sentAmount,
+			sentUnit,
+			receivedAmount,
+			receivedUnit,
+			fee,
+			feeCurrency,
+			entry.fiatValue,
+			fiatUnit,
+			label,
+			entry.note,
+			entry.txID,
+		})
+		if err != nil {
+			return errp.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// exportCoinTrackingCSV writes the transactions in the CoinTracking CSV import format. The fiat
+// values are given in the main fiat currency, which should match the account currency configured
+// in CoinTracking.
+func (account *BaseAccount) exportCoinTrackingCSV(
+	writer *csv.Writer, transactions []*TransactionData, fiat string) error {
+	err := writer.Write([]string{
+		"Type",
+		"Buy Amount",
+		"Buy Currency",
+		"Sell Amount",
+		"Sell Currency",
+		"Fee",
+		"Fee Currency",
+		"Exchange",
+		"Trade-Group",
+		"Comment",
+		"Date",
+		"Tx-ID",
+		"Buy Value in Account Currency",
+		"Sell Value in Account Currency",
+	})
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	unit := account.coin.Unit(false)
+	feeUnit := account.coin.Unit(true)
+	for _, entry := range account.taxExportEntries(transactions, fiat) {
+		timeString := ""
+		if entry.timestamp != nil {
+			timeString = entry.timestamp.UTC().Format("2006-01-02 15:04:05")
+		}
+		var txType, buyAmount, buyUnit, buyValue, sellAmount, sellUnit, sellValue, fee, feeCurrency string
+		switch {
+		case entry.amount == nil:
+			txType = "Other Fee"
+			sellAmount = account.formatExportAmount(*entry.fee, true)
+			sellUnit = feeUnit
+			sellValue = entry.fiatValue
+		case entry.received:
+			txType = "Deposit"
+			buyAmount = account.formatExportAmount(*entry.amount, false)
+			buyUnit = unit
+			buyValue = entry.fiatValue
+		default:
+			txType = "Withdrawal"
+			sellAmount = account.formatExportAmount(*entry.amount, false)
+			sellUnit = unit
+			sellValue = entry.fiatValue
+		}
+		if entry.amount != nil && entry.fee != nil {
+			fee = account.formatExportAmount(*entry.fee, true)
+			feeCurrency = feeUnit
+		}
+		err := writer.Write([]string{
+			txType,
+			buyAmount,
+			buyUnit,
+			sellAmount,
+			sellUnit,
+			fee,
+			feeCurrency,
+			"BitBoxApp",
+			"",
+			entry.note,
+			timeString,
+			entry.txID,
+			buyValue,
+			sellValue,
+		})
+		if err != nil {
+			return errp.WithStack(err)
+		}
+	}
+	return nil
+}
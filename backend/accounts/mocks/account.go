@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
@@ -37,13 +38,13 @@ var _ accounts.Interface = &InterfaceMock{}
 //			ConfigFunc: func() *accounts.AccountConfig {
 //				panic("mock out the Config method")
 //			},
-//			ExportCSVFunc: func(w io.Writer, transactions []*accounts.TransactionData) error {
+//			ExportCSVFunc: func(w io.Writer, transactions []*accounts.TransactionData, format accounts.ExportFormat) error {
 //				panic("mock out the ExportCSV method")
 //			},
 //			FatalErrorFunc: func() bool {
 //				panic("mock out the FatalError method")
 //			},
-//			FeeTargetsFunc: func() ([]accounts.FeeTarget, accounts.FeeTargetCode) {
+//			FeeTargetsFunc: func(ctx context.Context) ([]accounts.FeeTarget, accounts.FeeTargetCode) {
 //				panic("mock out the FeeTargets method")
 //			},
 //			FilesFolderFunc: func() string {
@@ -73,7 +74,7 @@ var _ accounts.Interface = &InterfaceMock{}
 //			ProposeTxNoteFunc: func(s string)  {
 //				panic("mock out the ProposeTxNote method")
 //			},
-//			SendTxFunc: func(txNote string) error {
+//			SendTxFunc: func(ctx context.Context, txNote string) error {
 //				panic("mock out the SendTx method")
 //			},
 //			SetTxNoteFunc: func(txID string, note string) error {
@@ -88,7 +89,7 @@ var _ accounts.Interface = &InterfaceMock{}
 //			TxNoteFunc: func(txID string) string {
 //				panic("mock out the TxNote method")
 //			},
-//			TxProposalFunc: func(txProposalArgs *accounts.TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error) {
+//			TxProposalFunc: func(ctx context.Context, txProposalArgs *accounts.TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error) {
 //				panic("mock out the TxProposal method")
 //			},
 //			VerifyAddressFunc: func(addressID string) (bool, error) {
@@ -117,13 +118,13 @@ type InterfaceMock struct {
 	ConfigFunc func() *accounts.AccountConfig
 
 	// ExportCSVFunc mocks the ExportCSV method.
-	ExportCSVFunc func(w io.Writer, transactions []*accounts.TransactionData) error
+	ExportCSVFunc func(w io.Writer, transactions []*accounts.TransactionData, format accounts.ExportFormat) error
 
 	// FatalErrorFunc mocks the FatalError method.
 	FatalErrorFunc func() bool
 
 	// FeeTargetsFunc mocks the FeeTargets method.
-	FeeTargetsFunc func() ([]accounts.FeeTarget, accounts.FeeTargetCode)
+	FeeTargetsFunc func(ctx context.Context) ([]accounts.FeeTarget, accounts.FeeTargetCode)
 
 	// FilesFolderFunc mocks the FilesFolder method.
 	FilesFolderFunc func() string
@@ -153,7 +154,7 @@ type InterfaceMock struct {
 	ProposeTxNoteFunc func(s string)
 
 	// SendTxFunc mocks the SendTx method.
-	SendTxFunc func(txNote string) error
+	SendTxFunc func(ctx context.Context, txNote string) error
 
 	// SetTxNoteFunc mocks the SetTxNote method.
 	SetTxNoteFunc func(txID string, note string) error
@@ -168,7 +169,7 @@ type InterfaceMock struct {
 	TxNoteFunc func(txID string) string
 
 	// TxProposalFunc mocks the TxProposal method.
-	TxProposalFunc func(txProposalArgs *accounts.TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error)
+	TxProposalFunc func(ctx context.Context, txProposalArgs *accounts.TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error)
 
 	// VerifyAddressFunc mocks the VerifyAddress method.
 	VerifyAddressFunc func(addressID string) (bool, error)
@@ -196,12 +197,16 @@ type InterfaceMock struct {
 			W io.Writer
 			// Transactions is the transactions argument value.
 			Transactions []*accounts.TransactionData
+			// Format is the format argument value.
+			Format accounts.ExportFormat
 		}
 		// FatalError holds details about calls to the FatalError method.
 		FatalError []struct {
 		}
 		// FeeTargets holds details about calls to the FeeTargets method.
 		FeeTargets []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 		}
 		// FilesFolder holds details about calls to the FilesFolder method.
 		FilesFolder []struct {
@@ -236,6 +241,10 @@ type InterfaceMock struct {
 		}
 		// SendTx holds details about calls to the SendTx method.
 		SendTx []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TxNote is the txNote argument value.
+			TxNote string
 		}
 		// SetTxNote holds details about calls to the SetTxNote method.
 		SetTxNote []struct {
@@ -257,6 +266,8 @@ type InterfaceMock struct {
 		}
 		// TxProposal holds details about calls to the TxProposal method.
 		TxProposal []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// TxProposalArgs is the txProposalArgs argument value.
 			TxProposalArgs *accounts.TxProposalArgs
 		}
@@ -428,21 +439,23 @@ func (mock *InterfaceMock) ConfigCalls() []struct {
 }
 
 // ExportCSV calls ExportCSVFunc.
-func (mock *InterfaceMock) ExportCSV(w io.Writer, transactions []*accounts.TransactionData) error {
+func (mock *InterfaceMock) ExportCSV(w io.Writer, transactions []*accounts.TransactionData, format accounts.ExportFormat) error {
 	if mock.ExportCSVFunc == nil {
 		panic("InterfaceMock.ExportCSVFunc: method is nil but Interface.ExportCSV was just called")
 	}
 	callInfo := struct {
 		W            io.Writer
 		Transactions []*accounts.TransactionData
+		Format       accounts.ExportFormat
 	}{
 		W:            w,
 		Transactions: transactions,
+		Format:       format,
 	}
 	mock.lockExportCSV.Lock()
 	mock.calls.ExportCSV = append(mock.calls.ExportCSV, callInfo)
 	mock.lockExportCSV.Unlock()
-	return mock.ExportCSVFunc(w, transactions)
+	return mock.ExportCSVFunc(w, transactions, format)
 }
 
 // ExportCSVCalls gets all the calls that were made to ExportCSV.
@@ -452,10 +465,12 @@ func (mock *InterfaceMock) ExportCSV(w io.Writer, transactions []*accounts.Trans
 func (mock *InterfaceMock) ExportCSVCalls() []struct {
 	W            io.Writer
 	Transactions []*accounts.TransactionData
+	Format       accounts.ExportFormat
 } {
 	var calls []struct {
 		W            io.Writer
 		Transactions []*accounts.TransactionData
+		Format       accounts.ExportFormat
 	}
 	mock.lockExportCSV.RLock()
 	calls = mock.calls.ExportCSV
@@ -491,16 +506,19 @@ func (mock *InterfaceMock) FatalErrorCalls() []struct {
 }
 
 // FeeTargets calls FeeTargetsFunc.
-func (mock *InterfaceMock) FeeTargets() ([]accounts.FeeTarget, accounts.FeeTargetCode) {
+func (mock *InterfaceMock) FeeTargets(ctx context.Context) ([]accounts.FeeTarget, accounts.FeeTargetCode) {
 	if mock.FeeTargetsFunc == nil {
 		panic("InterfaceMock.FeeTargetsFunc: method is nil but Interface.FeeTargets was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
 	mock.lockFeeTargets.Lock()
 	mock.calls.FeeTargets = append(mock.calls.FeeTargets, callInfo)
 	mock.lockFeeTargets.Unlock()
-	return mock.FeeTargetsFunc()
+	return mock.FeeTargetsFunc(ctx)
 }
 
 // FeeTargetsCalls gets all the calls that were made to FeeTargets.
@@ -508,8 +526,10 @@ func (mock *InterfaceMock) FeeTargets() ([]accounts.FeeTarget, accounts.FeeTarge
 //
 //	len(mockedInterface.FeeTargetsCalls())
 func (mock *InterfaceMock) FeeTargetsCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
+		Ctx context.Context
 	}
 	mock.lockFeeTargets.RLock()
 	calls = mock.calls.FeeTargets
@@ -771,16 +791,21 @@ func (mock *InterfaceMock) ProposeTxNoteCalls() []struct {
 }
 
 // SendTx calls SendTxFunc.
-func (mock *InterfaceMock) SendTx(txNote string) error {
+func (mock *InterfaceMock) SendTx(ctx context.Context, txNote string) error {
 	if mock.SendTxFunc == nil {
 		panic("InterfaceMock.SendTxFunc: method is nil but Interface.SendTx was just called")
 	}
 	callInfo := struct {
-	}{}
+		Ctx    context.Context
+		TxNote string
+	}{
+		Ctx:    ctx,
+		TxNote: txNote,
+	}
 	mock.lockSendTx.Lock()
 	mock.calls.SendTx = append(mock.calls.SendTx, callInfo)
 	mock.lockSendTx.Unlock()
-	return mock.SendTxFunc("")
+	return mock.SendTxFunc(ctx, txNote)
 }
 
 // SendTxCalls gets all the calls that were made to SendTx.
@@ -788,8 +813,12 @@ func (mock *InterfaceMock) SendTx(txNote string) error {
 //
 //	len(mockedInterface.SendTxCalls())
 func (mock *InterfaceMock) SendTxCalls() []struct {
+	Ctx    context.Context
+	TxNote string
 } {
 	var calls []struct {
+		Ctx    context.Context
+		TxNote string
 	}
 	mock.lockSendTx.RLock()
 	calls = mock.calls.SendTx
@@ -920,19 +949,21 @@ func (mock *InterfaceMock) TxNoteCalls() []struct {
 }
 
 // TxProposal calls TxProposalFunc.
-func (mock *InterfaceMock) TxProposal(txProposalArgs *accounts.TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error) {
+func (mock *InterfaceMock) TxProposal(ctx context.Context, txProposalArgs *accounts.TxProposalArgs) (coin.Amount, coin.Amount, coin.Amount, error) {
 	if mock.TxProposalFunc == nil {
 		panic("InterfaceMock.TxProposalFunc: method is nil but Interface.TxProposal was just called")
 	}
 	callInfo := struct {
+		Ctx            context.Context
 		TxProposalArgs *accounts.TxProposalArgs
 	}{
+		Ctx:            ctx,
 		TxProposalArgs: txProposalArgs,
 	}
 	mock.lockTxProposal.Lock()
 	mock.calls.TxProposal = append(mock.calls.TxProposal, callInfo)
 	mock.lockTxProposal.Unlock()
-	return mock.TxProposalFunc(txProposalArgs)
+	return mock.TxProposalFunc(ctx, txProposalArgs)
 }
 
 // TxProposalCalls gets all the calls that were made to TxProposal.
@@ -940,9 +971,11 @@ func (mock *InterfaceMock) TxProposal(txProposalArgs *accounts.TxProposalArgs) (
 //
 //	len(mockedInterface.TxProposalCalls())
 func (mock *InterfaceMock) TxProposalCalls() []struct {
+	Ctx            context.Context
 	TxProposalArgs *accounts.TxProposalArgs
 } {
 	var calls []struct {
+		Ctx            context.Context
 		TxProposalArgs *accounts.TxProposalArgs
 	}
 	mock.lockTxProposal.RLock()
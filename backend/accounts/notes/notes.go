@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
 )
 
 // MaxNoteLen is the maximum length per note.
@@ -28,60 +29,66 @@ const MaxNoteLen = 1024
 
 // Data is the notes JSON data serialized to disk.
 type Data struct {
-	// More fields to be added when we can label more stuff, e.g. receive addresses, utxos, etc.
+	// More fields to be added when we can label more stuff, e.g. utxos, etc.
 
 	// a map of transaction ID to transaction note.
 	TransactionNotes map[string]string `json:"transactions"`
+	// AddressNotes is a map of an encoded receive address to its label.
+	AddressNotes map[string]string `json:"addresses,omitempty"`
+	// FrozenUTXOs are the outpoints of the coins the user marked as "do not spend".
+	FrozenUTXOs map[string]bool `json:"frozenUTXOs,omitempty"`
 }
 
 // read deserializes the json files into notes. If the file does not exist yet, no error is
 // returned, and the struct is retruned with default values.
-func read(filename string) (*Data, error) {
-	file, err := os.Open(filename)
+func read(filename string, vault *vault.Vault) (*Data, error) {
+	jsonBytes, err := vault.ReadFile(filename)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &Data{}, nil
 		}
 		return nil, errp.WithStack(err)
 	}
-	defer file.Close() //nolint:errcheck
 	var notes Data
-	if err := json.NewDecoder(file).Decode(&notes); err != nil {
+	if err := json.Unmarshal(jsonBytes, &notes); err != nil {
 		return nil, errp.WithStack(err)
 	}
 	return &notes, nil
 }
 
-func write(data *Data, filename string) error {
-	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+func write(data *Data, filename string, vault *vault.Vault) error {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		return err
-	}
-	defer func() { _ = file.Close() }()
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(data); err != nil {
 		return errp.WithStack(err)
 	}
-	return nil
+	return vault.WriteFile(filename, append(jsonBytes, '\n'), 0600)
 }
 
 // Notes is a high level helper for notes, allowing you to read and set notes for transactions.
 type Notes struct {
 	filename string
-	data     *Data
-	dataMu   sync.RWMutex
+	// vault encrypts the notes file if the encryption is enabled. Can be nil.
+	vault  *vault.Vault
+	data   *Data
+	dataMu sync.RWMutex
 }
 
 // LoadNotes makes a new Notes instance, already pre-loading all notes into RAM. If the file does
 // not exist, no error is returned.  Returns an error for other kinds of file read errors.
 func LoadNotes(filename string) (*Notes, error) {
-	data, err := read(filename)
+	return LoadEncryptedNotes(filename, nil)
+}
+
+// LoadEncryptedNotes is like LoadNotes, but reads and writes the notes file through the vault, so
+// that it is encrypted if the vault encryption is enabled.
+func LoadEncryptedNotes(filename string, vault *vault.Vault) (*Notes, error) {
+	data, err := read(filename, vault)
 	if err != nil {
 		return nil, err
 	}
 	return &Notes{
 		filename: filename,
+		vault:    vault,
 		data:     data,
 	}, nil
 }
@@ -108,7 +115,7 @@ func (notes *Notes) SetTxNote(txID string, note string) (bool, error) {
 	} else {
 		notes.data.TransactionNotes[txID] = note
 	}
-	return changed, write(notes.data, notes.filename)
+	return changed, write(notes.data, notes.filename, notes.vault)
 }
 
 // TxNote fetches a note for a transaction. Returns the empty string if no note was found.
@@ -119,6 +126,62 @@ func (notes *Notes) TxNote(txID string) string {
 	return notes.data.TransactionNotes[txID]
 }
 
+// SetAddressNote stores a label for an address, identified by its encoding. Like with
+// `SetTxNote()`, an empty note deletes the entry. Returns whether the note was modified.
+func (notes *Notes) SetAddressNote(address string, note string) (bool, error) {
+	notes.dataMu.Lock()
+	defer notes.dataMu.Unlock()
+
+	if len(note) > MaxNoteLen {
+		return false, errp.Newf("Length of note must be smaller than %d. Got %d", MaxNoteLen, len(note))
+	}
+
+	if notes.data.AddressNotes == nil {
+		notes.data.AddressNotes = map[string]string{}
+	}
+	changed := notes.data.AddressNotes[address] != note
+	if note == "" {
+		delete(notes.data.AddressNotes, address)
+	} else {
+		notes.data.AddressNotes[address] = note
+	}
+	return changed, write(notes.data, notes.filename, notes.vault)
+}
+
+// AddressNote fetches the label of an address. Returns the empty string if no label was found.
+func (notes *Notes) AddressNote(address string) string {
+	notes.dataMu.RLock()
+	defer notes.dataMu.RUnlock()
+
+	return notes.data.AddressNotes[address]
+}
+
+// SetUTXOFrozen marks a coin, identified by its outpoint, as "do not spend", or removes the mark.
+// Returns whether the mark was modified.
+func (notes *Notes) SetUTXOFrozen(outPoint string, frozen bool) (bool, error) {
+	notes.dataMu.Lock()
+	defer notes.dataMu.Unlock()
+
+	if notes.data.FrozenUTXOs == nil {
+		notes.data.FrozenUTXOs = map[string]bool{}
+	}
+	changed := notes.data.FrozenUTXOs[outPoint] != frozen
+	if frozen {
+		notes.data.FrozenUTXOs[outPoint] = true
+	} else {
+		delete(notes.data.FrozenUTXOs, outPoint)
+	}
+	return changed, write(notes.data, notes.filename, notes.vault)
+}
+
+// UTXOFrozen returns true if the coin with the given outpoint is marked as "do not spend".
+func (notes *Notes) UTXOFrozen(outPoint string) bool {
+	notes.dataMu.RLock()
+	defer notes.dataMu.RUnlock()
+
+	return notes.data.FrozenUTXOs[outPoint]
+}
+
 // Data retrieves all stored notes. You must not modify the returned object.
 func (notes *Notes) Data() *Data {
 	notes.dataMu.RLock()
@@ -126,6 +189,15 @@ func (notes *Notes) Data() *Data {
 	return notes.data
 }
 
+// SetData replaces all stored notes, e.g. when restoring a backup.
+func (notes *Notes) SetData(data *Data) error {
+	notes.dataMu.Lock()
+	defer notes.dataMu.Unlock()
+
+	notes.data = data
+	return write(notes.data, notes.filename, notes.vault)
+}
+
 // MergeLegacy merges the notes from an older/legacy notes file. Current/new notes take priority in
 // case of conflict.
 func (notes *Notes) MergeLegacy(legacy *Notes) error {
@@ -142,5 +214,5 @@ func (notes *Notes) MergeLegacy(legacy *Notes) error {
 			notes.data.TransactionNotes[txID] = note
 		}
 	}
-	return write(notes.data, notes.filename)
+	return write(notes.data, notes.filename, notes.vault)
 }
@@ -78,6 +78,59 @@ func TestNotesPersisted(t *testing.T) {
 	require.Equal(t, "", notes.TxNote("some-tx-id"))
 }
 
+// TestUTXOFrozen checks that coins can be marked as "do not spend", and that the mark is persisted.
+func TestUTXOFrozen(t *testing.T) {
+	filename := test.TstTempFile("account-notes")
+	notes, err := LoadNotes(filename)
+	require.NoError(t, err)
+
+	require.False(t, notes.UTXOFrozen("txid:0"))
+	changed, err := notes.SetUTXOFrozen("txid:0", true)
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = notes.SetUTXOFrozen("txid:0", true)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.True(t, notes.UTXOFrozen("txid:0"))
+	require.False(t, notes.UTXOFrozen("txid:1"))
+
+	notes, err = LoadNotes(filename)
+	require.NoError(t, err)
+	require.True(t, notes.UTXOFrozen("txid:0"))
+
+	changed, err = notes.SetUTXOFrozen("txid:0", false)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.False(t, notes.UTXOFrozen("txid:0"))
+	require.Empty(t, notes.Data().FrozenUTXOs)
+}
+
+// TestAddressNotes checks that addresses can be labeled, and that the labels are persisted.
+func TestAddressNotes(t *testing.T) {
+	filename := test.TstTempFile("account-notes")
+	notes, err := LoadNotes(filename)
+	require.NoError(t, err)
+
+	require.Equal(t, "", notes.AddressNote("bc1q-address"))
+	changed, err := notes.SetAddressNote("bc1q-address", "donations")
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = notes.SetAddressNote("bc1q-address", "donations")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	notes, err = LoadNotes(filename)
+	require.NoError(t, err)
+	require.Equal(t, "donations", notes.AddressNote("bc1q-address"))
+
+	_, err = notes.SetAddressNote("bc1q-address", strings.Repeat("x", 1025))
+	require.Error(t, err)
+	changed, err = notes.SetAddressNote("bc1q-address", "")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Empty(t, notes.Data().AddressNotes)
+}
+
 // TestMaxLen checks that notes that are too long are rejected.
 func TestMaxLen(t *testing.T) {
 	filename := test.TstTempFile("account-notes")
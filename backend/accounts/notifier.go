@@ -24,6 +24,8 @@ type Notifier interface {
 	Delete(id []byte) error
 	// UnnotifiedCount returns the number ids in the 'unnotified' set.
 	UnnotifiedCount() (int, error)
+	// UnnotifiedIDs returns the ids in the 'unnotified' set.
+	UnnotifiedIDs() ([][]byte, error)
 	// MarkAllNotified moves all ids from the 'unnotified' set to the 'seen' set.
 	MarkAllNotified() error
 }
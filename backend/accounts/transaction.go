@@ -50,8 +50,19 @@ const (
 	// TxStatusFailed means the tx is confirmed but considered failed, e.g. a ETH transaction with a
 	// too low gas limit.
 	TxStatusFailed TxStatus = "failed"
+	// TxStatusDropped means the tx was unconfirmed and disappeared from the mempool, e.g. because
+	// it was evicted due to a low fee. It does not affect the balance.
+	TxStatusDropped TxStatus = "dropped"
+	// TxStatusDoubleSpent means the tx was unconfirmed and was replaced by another tx spending some
+	// of the same coins. It does not affect the balance.
+	TxStatusDoubleSpent TxStatus = "doubleSpent"
 )
 
+// Dropped returns true if the tx never made it into a block and was removed from the mempool.
+func (status TxStatus) Dropped() bool {
+	return status == TxStatusDropped || status == TxStatusDoubleSpent
+}
+
 // AddressAndAmount holds an address and the corresponding amount.
 type AddressAndAmount struct {
 	Address string
@@ -61,6 +72,17 @@ type AddressAndAmount struct {
 	Ours bool
 }
 
+// TxInput is an input of a transaction.
+type TxInput struct {
+	// OutPoint is the spent output in the format `<txid>:<index>`.
+	OutPoint string
+	// Address and Amount are only set if Ours is true, i.e. if the input spends an output of the
+	// account.
+	Address string
+	Amount  coin.Amount
+	Ours    bool
+}
+
 // TransactionData holds transaction data to be shown to the user. It is as coin-agnostic as
 // possible, but contains some fields that are only used by certain coins.
 type TransactionData struct {
@@ -120,6 +142,16 @@ type TransactionData struct {
 	// Weight is the tx weight.
 	Weight           int64
 	CreatedTimestamp *time.Time
+	// Unverified is true if the tx is confirmed according to the server, but its inclusion in the
+	// block was not (yet) verified against the synced headers using a merkle proof.
+	Unverified bool
+	// ConflictingTxID is the ID of the tx which replaced this tx if the status is
+	// TxStatusDoubleSpent.
+	ConflictingTxID string
+	// Inputs are all inputs of the tx.
+	Inputs []TxInput
+	// Outputs are all outputs of the tx, in order.
+	Outputs []AddressAndAmount
 
 	// --- Fields only used for ETH follow
 
@@ -169,6 +201,11 @@ func NewOrderedTransactions(txs []*TransactionData) OrderedTransactions {
 	for i := len(txs) - 1; i >= 0; i-- {
 		deductedAmount := coin.NewAmountFromInt64(0)
 		tx := txs[i]
+		if tx.Status.Dropped() {
+			tx.Balance = coin.NewAmount(balance)
+			tx.DeductedAmount = deductedAmount
+			continue
+		}
 		switch tx.Type {
 		case TxTypeReceive:
 			if tx.Status != TxStatusFailed {
@@ -278,3 +315,17 @@ func (txs OrderedTransactions) Timeseries(
 	}
 	return result, nil
 }
+
+// BalanceHistory returns the balance of the account at the start of each interval (e.g. each day)
+// from the interval of the earliest transaction until `end`. An empty result is returned if there
+// are no transactions yet. Returns `errors.ErrNotAvailable` if timestamp data is missing.
+func (txs OrderedTransactions) BalanceHistory(end time.Time, interval time.Duration) ([]TimeseriesEntry, error) {
+	earliestTime, err := txs.EarliestTime()
+	if err != nil {
+		return nil, err
+	}
+	if earliestTime.IsZero() {
+		return []TimeseriesEntry{}, nil
+	}
+	return txs.Timeseries(earliestTime.Truncate(interval), end, interval)
+}
@@ -168,6 +168,49 @@ func TestOrderedTransactions(t *testing.T) {
 	}, timeseries)
 }
 
+func TestBalanceHistory(t *testing.T) {
+	tt := func(t time.Time) *time.Time { return &t }
+	history, err := NewOrderedTransactions(nil).BalanceHistory(time.Now(), 24*time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	ordered := NewOrderedTransactions([]*TransactionData{
+		{
+			Timestamp: tt(time.Date(2020, 9, 10, 12, 0, 0, 0, time.UTC)),
+			Height:    10,
+			Type:      TxTypeReceive,
+			Amount:    coin.NewAmountFromInt64(200),
+		},
+		{
+			Timestamp: tt(time.Date(2020, 9, 11, 12, 0, 0, 0, time.UTC)),
+			Height:    11,
+			Type:      TxTypeSend,
+			Amount:    coin.NewAmountFromInt64(10),
+		},
+	})
+	history, err = ordered.BalanceHistory(time.Date(2020, 9, 12, 0, 0, 0, 0, time.UTC), 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []TimeseriesEntry{
+		{
+			Time:  time.Date(2020, 9, 10, 0, 0, 0, 0, time.UTC),
+			Value: coin.NewAmountFromInt64(0),
+		},
+		{
+			Time:  time.Date(2020, 9, 11, 0, 0, 0, 0, time.UTC),
+			Value: coin.NewAmountFromInt64(200),
+		},
+		{
+			Time:  time.Date(2020, 9, 12, 0, 0, 0, 0, time.UTC),
+			Value: coin.NewAmountFromInt64(190),
+		},
+	}, history)
+
+	// Confirmed transactions without a timestamp (e.g. headers not synced yet).
+	ordered[0].Timestamp = nil
+	_, err = ordered.BalanceHistory(time.Now(), 24*time.Hour)
+	require.Error(t, err)
+}
+
 // TestOrderedTransactionsWithFailedTransactions tests that the cumulative balance takes into
 // account failed transactions.  Ethereum transactions can be mined and fail anyway due to a too low
 // gas limit, in which case the amount is not transferred, but the fees are still paid.
@@ -232,6 +275,43 @@ func TestOrderedTransactionsWithFailedTransactions(t *testing.T) {
 	}
 }
 
+func TestOrderedTransactionsWithDroppedTransactions(t *testing.T) {
+	tt := func(t time.Time) *time.Time { return &t }
+	fee := coin.NewAmountFromInt64(1)
+	txs := []*TransactionData{
+		{
+			Timestamp: tt(time.Date(2020, 9, 15, 12, 0, 0, 0, time.UTC)),
+			Height:    15,
+			Type:      TxTypeReceive,
+			Amount:    coin.NewAmountFromInt64(100),
+		},
+		{
+			CreatedTimestamp: tt(time.Date(2020, 9, 16, 12, 0, 0, 0, time.UTC)),
+			Type:             TxTypeSend,
+			Amount:           coin.NewAmountFromInt64(50),
+			Fee:              &fee,
+			Status:           TxStatusDropped,
+		},
+		{
+			CreatedTimestamp: tt(time.Date(2020, 9, 17, 12, 0, 0, 0, time.UTC)),
+			Type:             TxTypeReceive,
+			Amount:           coin.NewAmountFromInt64(1000),
+			Status:           TxStatusDoubleSpent,
+		},
+	}
+
+	ordered := NewOrderedTransactions(txs)
+	expectedBalances := []int64{
+		100, // double-spent receive tx, nothing changes
+		100, // dropped send tx, nothing changes, not even the fee
+		100,
+	}
+	for i := range ordered {
+		require.Equal(t, coin.NewAmountFromInt64(expectedBalances[i]), ordered[i].Balance, i)
+		requireAmountIsEqualTo(t, ordered[i].DeductedAmount, 0)
+	}
+}
+
 func requireAmountIsEqualTo(t *testing.T, amount coin.Amount, total int64) {
 	t.Helper()
 	value, err := amount.Int64()
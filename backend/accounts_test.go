@@ -18,10 +18,12 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
@@ -175,6 +177,7 @@ func TestSortAccounts(t *testing.T) {
 		{Code: "acct-ltc", CoinCode: coinpkg.CodeLTC},
 		{Code: "acct-tltc", CoinCode: coinpkg.CodeTLTC},
 		{Code: "acct-tbtc", CoinCode: coinpkg.CodeTBTC},
+		{Code: "acct-tsig", CoinCode: coinpkg.CodeTSIG},
 	}
 	backend := newBackend(t, testnetDisabled, regtestDisabled)
 	for i := range accountConfigs {
@@ -188,6 +191,7 @@ func TestSortAccounts(t *testing.T) {
 		"acct-btc-2",
 		"acct-btc-3",
 		"acct-tbtc",
+		"acct-tsig",
 		"acct-ltc",
 		"acct-tltc",
 		"acct-eth-1",
@@ -310,7 +314,7 @@ func TestSupportedCoins(t *testing.T) {
 		b := newBackend(t, testnetEnabled, regtestDisabled)
 		defer b.Close()
 		require.Equal(t,
-			[]coinpkg.Code{coinpkg.CodeTBTC, coinpkg.CodeTLTC, coinpkg.CodeSEPETH},
+			[]coinpkg.Code{coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeTLTC, coinpkg.CodeSEPETH},
 			b.SupportedCoins(&keystoremock.KeystoreMock{
 				SupportsCoinFunc: func(coin coinpkg.Coin) bool {
 					return true
@@ -1056,6 +1060,277 @@ func TestRenameAccount(t *testing.T) {
 	require.Equal(t, "renamed", b.config.AccountsConfig().Lookup("v0-55555555-btc-0").Name)
 }
 
+func TestSetAccountGapLimit(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	bitbox02LikeKeystore := makeBitBox02Multi()
+	bitbox02LikeKeystore.RootFingerprintFunc = func() ([]byte, error) {
+		return rootFingerprint1, nil
+	}
+	b.registerKeystore(bitbox02LikeKeystore)
+
+	require.NoError(t, b.SetAccountGapLimit("v0-55555555-btc-0", 100))
+	require.Equal(t, uint16(100), b.config.AccountsConfig().Lookup("v0-55555555-btc-0").GapLimit)
+	// The accounts were reloaded with the new config.
+	require.Equal(t, uint16(100), b.Accounts().lookup("v0-55555555-btc-0").Config().Config.GapLimit)
+
+	require.Error(t, b.SetAccountGapLimit("v0-55555555-eth-0", 100))
+	require.Error(t, b.SetAccountGapLimit("unknown", 100))
+
+	// The mocked accounts are not BTC accounts.
+	require.Error(t, b.RescanAccount("v0-55555555-btc-0"))
+	require.Error(t, b.RescanAccount("unknown"))
+}
+
+func TestSetAccountCoinSelection(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	bitbox02LikeKeystore := makeBitBox02Multi()
+	bitbox02LikeKeystore.RootFingerprintFunc = func() ([]byte, error) {
+		return rootFingerprint1, nil
+	}
+	b.registerKeystore(bitbox02LikeKeystore)
+
+	require.NoError(t, b.SetAccountCoinSelection("v0-55555555-btc-0", "branchAndBound"))
+	require.Equal(t, "branchAndBound", b.config.AccountsConfig().Lookup("v0-55555555-btc-0").CoinSelection)
+	require.Equal(t, "branchAndBound", b.Accounts().lookup("v0-55555555-btc-0").Config().Config.CoinSelection)
+
+	require.NoError(t, b.SetAccountCoinSelection("v0-55555555-btc-0", ""))
+	require.Equal(t, "", b.config.AccountsConfig().Lookup("v0-55555555-btc-0").CoinSelection)
+
+	require.Error(t, b.SetAccountCoinSelection("v0-55555555-btc-0", "unknown"))
+	require.Error(t, b.SetAccountCoinSelection("v0-55555555-eth-0", "branchAndBound"))
+	require.Error(t, b.SetAccountCoinSelection("unknown", "branchAndBound"))
+}
+
+func TestCreateAndPersistCustomKeypathAccountConfig(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	bitbox02LikeKeystore := makeBitBox02Multi()
+	bitbox02LikeKeystore.RootFingerprintFunc = func() ([]byte, error) {
+		return rootFingerprint1, nil
+	}
+	b.registerKeystore(bitbox02LikeKeystore)
+
+	keypath := mustKeypath("m/84'/0'/100'")
+	acctCode, err := b.CreateAndPersistCustomKeypathAccountConfig(
+		coinpkg.CodeBTC, "", keypath, signing.ScriptTypeP2WPKH, bitbox02LikeKeystore)
+	require.NoError(t, err)
+	require.Equal(t, customAccountCode(rootFingerprint1, coinpkg.CodeBTC, signing.ScriptTypeP2WPKH, keypath), acctCode)
+	acct := b.Config().AccountsConfig().Lookup(acctCode)
+	require.NotNil(t, acct)
+	require.Equal(t, "Bitcoin m/84'/0'/100'", acct.Name)
+	require.Len(t, acct.SigningConfigurations, 1)
+	require.Equal(t, signing.ScriptTypeP2WPKH, acct.SigningConfigurations[0].ScriptType())
+	require.Equal(t, keypath, acct.SigningConfigurations[0].AbsoluteKeypath())
+	require.NotNil(t, b.Accounts().lookup(acctCode))
+
+	// The same account cannot be added twice.
+	_, err = b.CreateAndPersistCustomKeypathAccountConfig(
+		coinpkg.CodeBTC, "", keypath, signing.ScriptTypeP2WPKH, bitbox02LikeKeystore)
+	require.ErrorIs(t, errp.Cause(err), errAccountAlreadyExists)
+
+	_, err = b.CreateAndPersistCustomKeypathAccountConfig(
+		coinpkg.CodeBTC, "", keypath, signing.ScriptTypeP2WSH, bitbox02LikeKeystore)
+	require.Error(t, err)
+
+	ethKeypath := mustKeypath("m/44'/60'/1'/0/0")
+	acctCode, err = b.CreateAndPersistCustomKeypathAccountConfig(
+		coinpkg.CodeETH, "my eth", ethKeypath, "", bitbox02LikeKeystore)
+	require.NoError(t, err)
+	acct = b.Config().AccountsConfig().Lookup(acctCode)
+	require.NotNil(t, acct)
+	require.Equal(t, ethKeypath, acct.SigningConfigurations[0].AbsoluteKeypath())
+}
+
+func TestCreateAndPersistMultisigAccountConfig(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	bitbox02LikeKeystore := makeBitBox02Multi()
+	bitbox02LikeKeystore.RootFingerprintFunc = func() ([]byte, error) {
+		return rootFingerprint1, nil
+	}
+	b.registerKeystore(bitbox02LikeKeystore)
+
+	const xpub = "xpub6DJ2dNUysrn5Vt36jH2KLBT2i1auw1tTSSomg8PhqNiUtx8QX2SvC9nrHu81fT41fvDUnhMjEzQgXnQjKEu3oaqMSzhSrHMxyyoEAmUHQbY"
+	extendedPublicKey, _, err := signing.ParseExtendedPublicKey(xpub)
+	require.NoError(t, err)
+	tpub, err := extendedPublicKey.CloneWithVersion(chaincfg.TestNet3Params.HDPublicKeyID[:])
+	require.NoError(t, err)
+
+	// Testnet keys can't be cosigners of a mainnet account.
+	_, err = b.CreateAndPersistMultisigAccountConfig(
+		coinpkg.CodeBTC, "", 2, []string{"[d34db33f/48h/1h/0h/2h]" + tpub.String()}, bitbox02LikeKeystore)
+	require.ErrorContains(t, err, "network")
+
+	acctCode, err := b.CreateAndPersistMultisigAccountConfig(
+		coinpkg.CodeBTC, "", 2, []string{"[d34db33f/48h/0h/0h/2h]" + xpub}, bitbox02LikeKeystore)
+	require.NoError(t, err)
+	acct := b.Config().AccountsConfig().Lookup(acctCode)
+	require.NotNil(t, acct)
+	require.Equal(t, "Bitcoin 2-of-2", acct.Name)
+	require.True(t, acct.SigningConfigurations.IsMultisig())
+}
+
+func TestCreateAndPersistDescriptorAccountConfig(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	descriptor := "wpkh([d34db33f/84h/0h/0h]xpub6DJ2dNUysrn5Vt36jH2KLBT2i1auw1tTSSomg8PhqNiUtx8QX2SvC9nrHu81fT41fvDUnhMjEzQgXnQjKEu3oaqMSzhSrHMxyyoEAmUHQbY/0/*)#cjjspncu"
+	acctCode, err := b.CreateAndPersistDescriptorAccountConfig(coinpkg.CodeBTC, "", descriptor)
+	require.NoError(t, err)
+	acct := b.Config().AccountsConfig().Lookup(acctCode)
+	require.NotNil(t, acct)
+	require.Equal(t, "Bitcoin watch-only", acct.Name)
+	require.True(t, *acct.Watch)
+	require.True(t, b.Config().AccountsConfig().IsKeystoreWatchonly([]byte{0xd3, 0x4d, 0xb3, 0x3f}))
+	// Loaded without a connected keystore.
+	require.NotNil(t, b.Accounts().lookup(acctCode))
+
+	_, err = b.CreateAndPersistDescriptorAccountConfig(coinpkg.CodeBTC, "", descriptor)
+	require.ErrorIs(t, errp.Cause(err), errAccountAlreadyExists)
+	_, err = b.CreateAndPersistDescriptorAccountConfig(coinpkg.CodeETH, "", descriptor)
+	require.Error(t, err)
+}
+
+func TestCreateAndPersistExtendedPublicKeyAccountConfig(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	const xpub = "xpub6DJ2dNUysrn5Vt36jH2KLBT2i1auw1tTSSomg8PhqNiUtx8QX2SvC9nrHu81fT41fvDUnhMjEzQgXnQjKEu3oaqMSzhSrHMxyyoEAmUHQbY"
+	extendedPublicKey, _, err := signing.ParseExtendedPublicKey(xpub)
+	require.NoError(t, err)
+	zpub, err := signing.EncodeExtendedPublicKey(extendedPublicKey, "zpub")
+	require.NoError(t, err)
+
+	// The script type is inferred from the zpub.
+	acctCode, err := b.CreateAndPersistExtendedPublicKeyAccountConfig(
+		coinpkg.CodeBTC, "", "[d34db33f/84h/0h/0h]"+zpub, "")
+	require.NoError(t, err)
+	acct := b.Config().AccountsConfig().Lookup(acctCode)
+	require.NotNil(t, acct)
+	require.True(t, *acct.Watch)
+	require.Len(t, acct.SigningConfigurations, 1)
+	require.Equal(t, signing.ScriptTypeP2WPKH, acct.SigningConfigurations[0].ScriptType())
+	require.Equal(t, xpub, acct.SigningConfigurations[0].ExtendedPublicKey().String())
+	require.True(t, b.Config().AccountsConfig().IsKeystoreWatchonly([]byte{0xd3, 0x4d, 0xb3, 0x3f}))
+	require.NotNil(t, b.Accounts().lookup(acctCode))
+
+	// The same account as from the equivalent descriptor.
+	_, err = b.CreateAndPersistDescriptorAccountConfig(coinpkg.CodeBTC, "",
+		"wpkh([d34db33f/84h/0h/0h]"+xpub+"/<0;1>/*)")
+	require.ErrorIs(t, errp.Cause(err), errAccountAlreadyExists)
+
+	// xpubs need an explicit script type.
+	const otherXpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+	const wasabiXpub = "xpub6CC9Tsi4eJvmRsGuXwKBfHDWUWN66voNeZFmXRJhYZS6yYgXKZmtz5qnxK9WL2FZP8uF3abyFZ29d7RfMks4FjCCu4LMh3edyeCoyEFuZLZ"
+	const wasabiTaprootXpub = "xpub6CC9Tsi4eJvmSBj5xoU4sKnFGF9nF8qwExB3axxu2F7oWKFH5RucWQUfrgVGfnTDr6p5acBGpAqAMKb2A7ek8SbAUvDEXtvj37pM1S9X2km"
+	_, err = b.CreateAndPersistExtendedPublicKeyAccountConfig(coinpkg.CodeBTC, "", otherXpub, "")
+	require.Error(t, err)
+	acctCode, err = b.CreateAndPersistExtendedPublicKeyAccountConfig(
+		coinpkg.CodeBTC, "", otherXpub, signing.ScriptTypeP2TR)
+	require.NoError(t, err)
+	acct = b.Config().AccountsConfig().Lookup(acctCode)
+	require.Equal(t, signing.ScriptTypeP2TR, acct.SigningConfigurations[0].ScriptType())
+	require.Equal(t, []byte{0, 0, 0, 0}, acct.SigningConfigurations[0].BitcoinSimple.KeyInfo.RootFingerprint)
+
+	// The script type must match the zpub.
+	_, err = b.CreateAndPersistExtendedPublicKeyAccountConfig(
+		coinpkg.CodeBTC, "", zpub, signing.ScriptTypeP2PKH)
+	require.Error(t, err)
+	_, err = b.CreateAndPersistExtendedPublicKeyAccountConfig(coinpkg.CodeETH, "", zpub, "")
+	require.Error(t, err)
+}
+
+func TestCreateAndPersistWalletFileAccountConfig(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+	b.makeBtcAccount = func(config *accounts.AccountConfig, coin *btc.Coin, gapLimits *types.GapLimits, log *logrus.Entry) accounts.Interface {
+		accountMock := MockBtcAccount(t, config, coin, gapLimits, log)
+		accountNotes, err := notes.LoadNotes(test.TstTempFile("notes"))
+		require.NoError(t, err)
+		accountMock.NotesFunc = func() *notes.Notes { return accountNotes }
+		return accountMock
+	}
+
+	const xpub = "xpub6DJ2dNUysrn5Vt36jH2KLBT2i1auw1tTSSomg8PhqNiUtx8QX2SvC9nrHu81fT41fvDUnhMjEzQgXnQjKEu3oaqMSzhSrHMxyyoEAmUHQbY"
+	const otherXpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+	const wasabiXpub = "xpub6CC9Tsi4eJvmRsGuXwKBfHDWUWN66voNeZFmXRJhYZS6yYgXKZmtz5qnxK9WL2FZP8uF3abyFZ29d7RfMks4FjCCu4LMh3edyeCoyEFuZLZ"
+	const wasabiTaprootXpub = "xpub6CC9Tsi4eJvmSBj5xoU4sKnFGF9nF8qwExB3axxu2F7oWKFH5RucWQUfrgVGfnTDr6p5acBGpAqAMKb2A7ek8SbAUvDEXtvj37pM1S9X2km"
+	extendedPublicKey, _, err := signing.ParseExtendedPublicKey(xpub)
+	require.NoError(t, err)
+	zpub, err := signing.EncodeExtendedPublicKey(extendedPublicKey, "zpub")
+	require.NoError(t, err)
+	txID := strings.Repeat("ab", 32)
+
+	electrumFile := fmt.Sprintf(`{
+    "keystore": {"type": "bip32", "xpub": %q, "xprv": null, "derivation": "m/84'/0'/0'", "root_fingerprint": "d34db33f"},
+    "wallet_type": "standard",
+    "seed_version": 18,
+    "labels": {%q: "rent", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq": "donations"}
+}`, zpub, txID)
+	result, err := b.CreateAndPersistWalletFileAccountConfig(coinpkg.CodeBTC, "Electrum", []byte(electrumFile))
+	require.NoError(t, err)
+	require.Equal(t, WalletFileTypeElectrum, result.Type)
+	require.Equal(t, 1, result.TransactionCount)
+	require.Equal(t, 1, result.IgnoredLabelCount)
+	acct := b.Config().AccountsConfig().Lookup(result.AccountCode)
+	require.NotNil(t, acct)
+	require.Equal(t, "Electrum", acct.Name)
+	require.True(t, *acct.Watch)
+	require.Len(t, acct.SigningConfigurations, 1)
+	require.Equal(t, signing.ScriptTypeP2WPKH, acct.SigningConfigurations[0].ScriptType())
+	require.Equal(t, "m/84'/0'/0'", acct.SigningConfigurations[0].AbsoluteKeypath().Encode())
+	require.Equal(t, "rent", b.Accounts().lookup(result.AccountCode).Notes().TxNote(txID))
+
+	// Electrum uses xpubs for legacy wallets.
+	electrumFile = fmt.Sprintf(`{"keystore": {"type": "bip32", "xpub": %q}, "wallet_type": "standard"}`, otherXpub)
+	result, err = b.CreateAndPersistWalletFileAccountConfig(coinpkg.CodeBTC, "", []byte(electrumFile))
+	require.NoError(t, err)
+	acct = b.Config().AccountsConfig().Lookup(result.AccountCode)
+	require.Equal(t, signing.ScriptTypeP2PKH, acct.SigningConfigurations[0].ScriptType())
+
+	// Multisig wallets are not supported.
+	_, err = b.CreateAndPersistWalletFileAccountConfig(
+		coinpkg.CodeBTC, "", []byte(`{"wallet_type": "2of3", "x1/": {}}`))
+	require.Error(t, err)
+
+	wasabiFile := fmt.Sprintf(`{
+  "EncryptedSecret": "6PYSomething",
+  "ChainCode": "AAAA",
+  "MasterFingerprint": "0a0b0c0d",
+  "ExtPubKey": %q,
+  "TaprootExtPubKey": %q,
+  "AccountKeyPath": "84'/0'/0'",
+  "TaprootAccountKeyPath": "86'/0'/0'",
+  "HdPubKeys": [{"FullKeyPath": "84'/0'/0'/0/0", "Label": "Alice"}, {"FullKeyPath": "84'/0'/0'/0/1", "Label": ""}]
+}`, wasabiXpub, wasabiTaprootXpub)
+	result, err = b.CreateAndPersistWalletFileAccountConfig(coinpkg.CodeBTC, "", []byte(wasabiFile))
+	require.NoError(t, err)
+	require.Equal(t, WalletFileTypeWasabi, result.Type)
+	require.Equal(t, 0, result.TransactionCount)
+	require.Equal(t, 1, result.IgnoredLabelCount)
+	acct = b.Config().AccountsConfig().Lookup(result.AccountCode)
+	require.Len(t, acct.SigningConfigurations, 2)
+	require.Equal(t, signing.ScriptTypeP2WPKH, acct.SigningConfigurations[0].ScriptType())
+	require.Equal(t, signing.ScriptTypeP2TR, acct.SigningConfigurations[1].ScriptType())
+	require.Equal(t, "m/86'/0'/0'", acct.SigningConfigurations[1].AbsoluteKeypath().Encode())
+	require.True(t, b.Config().AccountsConfig().IsKeystoreWatchonly([]byte{0x0a, 0x0b, 0x0c, 0x0d}))
+
+	// Encrypted Electrum wallet files are not JSON.
+	_, err = b.CreateAndPersistWalletFileAccountConfig(coinpkg.CodeBTC, "", []byte("QklFMQ=="))
+	require.Error(t, err)
+	_, err = b.CreateAndPersistWalletFileAccountConfig(coinpkg.CodeETH, "", []byte(wasabiFile))
+	require.Error(t, err)
+	// Importing the same wallet again fails.
+	_, err = b.CreateAndPersistWalletFileAccountConfig(coinpkg.CodeBTC, "", []byte(wasabiFile))
+	require.Equal(t, errAccountAlreadyExists, errp.Cause(err))
+}
+
 func TestMaybeAddHiddenUnusedAccounts(t *testing.T) {
 	b := newBackend(t, testnetDisabled, regtestDisabled)
 	defer b.Close()
@@ -1109,6 +1384,64 @@ func TestMaybeAddHiddenUnusedAccounts(t *testing.T) {
 	require.NotNil(t, b.config.AccountsConfig().Lookup("v0-55555555-btc-6"))
 }
 
+func TestMaybeAddHiddenUnusedAccountsSplitAccounts(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	// A Bitcoin-only keystore without unified accounts, so there is one account per script type.
+	ks := &keystoremock.KeystoreMock{
+		NameFunc: func() (string, error) {
+			return "Mock split accounts", nil
+		},
+		RootFingerprintFunc: func() ([]byte, error) {
+			return rootFingerprint1, nil
+		},
+		SupportsAccountFunc: func(coin coinpkg.Coin, meta interface{}) bool {
+			switch coin.(type) {
+			case *btc.Coin:
+				scriptType := meta.(signing.ScriptType)
+				return coin.Code() == coinpkg.CodeBTC &&
+					(scriptType == signing.ScriptTypeP2WPKH || scriptType == signing.ScriptTypeP2WPKHP2SH)
+			default:
+				return false
+			}
+		},
+		SupportsMultipleAccountsFunc: func() bool {
+			return true
+		},
+		SupportsUnifiedAccountsFunc: func() bool {
+			return false
+		},
+		ExtendedPublicKeyFunc: keystoreHelper1().ExtendedPublicKey,
+	}
+	b.registerKeystore(ks)
+	checkShownAccountsLen(t, b, 2, 2)
+
+	for i := 1; i <= 10; i++ {
+		b.maybeAddHiddenUnusedAccounts()
+	}
+
+	// The hidden accounts of all script types are added and loaded.
+	require.Len(t, b.Accounts(), 2+2*5)
+	for i := 1; i <= 5; i++ {
+		for _, scriptType := range []string{"p2wpkh", "p2wpkh-p2sh"} {
+			code := accountsTypes.Code(fmt.Sprintf("v0-55555555-btc-%d-%s", i, scriptType))
+			require.NotNil(t, b.Accounts().lookup(code))
+			require.True(t, b.config.AccountsConfig().Lookup(code).HiddenBecauseUnused)
+		}
+	}
+
+	// The account number is used if any of the accounts with that number is used.
+	require.NoError(t, b.config.ModifyAccountsConfig(func(cfg *config.AccountsConfig) error {
+		cfg.Lookup("v0-55555555-btc-5-p2wpkh-p2sh").Used = true
+		return nil
+	}))
+	b.maybeAddHiddenUnusedAccounts()
+	require.Len(t, b.Accounts(), 2+2*6)
+	require.NotNil(t, b.Accounts().lookup("v0-55555555-btc-6-p2wpkh"))
+	require.NotNil(t, b.Accounts().lookup("v0-55555555-btc-6-p2wpkh-p2sh"))
+}
+
 func TestWatchonly(t *testing.T) {
 	filterAcct := func(code accountsTypes.Code) func(acct *config.Account) bool {
 		return func(acct *config.Account) bool {
@@ -0,0 +1,67 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/addressbook"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable/action"
+)
+
+// AddressBook returns the address book with the user's contacts.
+func (backend *Backend) AddressBook() *addressbook.AddressBook {
+	return backend.addressBook
+}
+
+func (backend *Backend) emitAddressBookChanged() {
+	backend.Notify(observable.Event{
+		Subject: "addressbook",
+		Action:  action.Reload,
+	})
+}
+
+// AddContact adds a contact to the address book. The contact's coin must be supported.
+func (backend *Backend) AddContact(contact addressbook.Contact) (*addressbook.Contact, error) {
+	if _, err := backend.Coin(contact.CoinCode); err != nil {
+		return nil, err
+	}
+	added, err := backend.addressBook.Add(contact)
+	if err != nil {
+		return nil, err
+	}
+	backend.emitAddressBookChanged()
+	return added, nil
+}
+
+// UpdateContact updates a contact in the address book.
+func (backend *Backend) UpdateContact(contact addressbook.Contact) error {
+	if _, err := backend.Coin(contact.CoinCode); err != nil {
+		return err
+	}
+	if err := backend.addressBook.Update(contact); err != nil {
+		return err
+	}
+	backend.emitAddressBookChanged()
+	return nil
+}
+
+// DeleteContact removes a contact from the address book.
+func (backend *Backend) DeleteContact(id string) error {
+	if err := backend.addressBook.Delete(id); err != nil {
+		return err
+	}
+	backend.emitAddressBookChanged()
+	return nil
+}
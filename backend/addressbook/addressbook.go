@@ -0,0 +1,216 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package addressbook provides a persistent store of contacts the user sends coins to.
+package addressbook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+const (
+	// MaxNameLen is the maximum length of a contact name.
+	MaxNameLen = 256
+	// MaxAddressLen is the maximum length of a contact address or xpub.
+	MaxAddressLen = 1024
+	// MaxNoteLen is the maximum length of a contact note.
+	MaxNoteLen = 1024
+)
+
+// Contact is an entry in the address book.
+type Contact struct {
+	// ID uniquely identifies the contact. It is assigned when the contact is added.
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	CoinCode coin.Code `json:"coinCode"`
+	// Address is a receive address or an xpub of the contact.
+	Address string `json:"address"`
+	Note    string `json:"note"`
+}
+
+func (contact *Contact) validate() error {
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.Address = strings.TrimSpace(contact.Address)
+	switch {
+	case contact.Name == "":
+		return errp.New("Name cannot be empty")
+	case contact.Address == "":
+		return errp.New("Address cannot be empty")
+	case contact.CoinCode == "":
+		return errp.New("Coin cannot be empty")
+	case len(contact.Name) > MaxNameLen:
+		return errp.Newf("Length of name must be smaller than %d", MaxNameLen)
+	case len(contact.Address) > MaxAddressLen:
+		return errp.Newf("Length of address must be smaller than %d", MaxAddressLen)
+	case len(contact.Note) > MaxNoteLen:
+		return errp.Newf("Length of note must be smaller than %d", MaxNoteLen)
+	}
+	return nil
+}
+
+// normalizeAddress returns the address in a form that can be compared. Bech32 and Ethereum addresses
+// are case-insensitive, other addresses (base58) are compared as they are.
+func normalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	lower := strings.ToLower(address)
+	for _, prefix := range []string{"0x", "bc1", "tb1", "bcrt1", "ltc1", "tltc1", "rltc1"} {
+		if strings.HasPrefix(lower, prefix) {
+			return lower
+		}
+	}
+	return address
+}
+
+// data is serialized to disk.
+type data struct {
+	Contacts []*Contact `json:"contacts"`
+}
+
+// AddressBook is a high level helper to read and modify the contacts. All contacts are kept in
+// RAM and written to the file on every change.
+type AddressBook struct {
+	filename string
+	data     *data
+	dataMu   sync.RWMutex
+}
+
+// Load makes a new AddressBook instance, loading all contacts from the file. If the file does not
+// exist, no error is returned. Returns an error for other kinds of file read errors.
+func Load(filename string) (*AddressBook, error) {
+	result := &data{Contacts: []*Contact{}}
+	file, err := os.Open(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errp.WithStack(err)
+	}
+	if err == nil {
+		defer file.Close() //nolint:errcheck
+		if err := json.NewDecoder(file).Decode(result); err != nil {
+			return nil, errp.WithStack(err)
+		}
+	}
+	return &AddressBook{filename: filename, data: result}, nil
+}
+
+// write must be called with dataMu held.
+func (addressBook *AddressBook) write() error {
+	file, err := os.OpenFile(addressBook.filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return errp.WithStack(encoder.Encode(addressBook.data))
+}
+
+// lookup must be called with dataMu held.
+func (addressBook *AddressBook) lookup(id string) (int, *Contact) {
+	for i, contact := range addressBook.data.Contacts {
+		if contact.ID == id {
+			return i, contact
+		}
+	}
+	return -1, nil
+}
+
+// Contacts returns a copy of all contacts.
+func (addressBook *AddressBook) Contacts() []Contact {
+	addressBook.dataMu.RLock()
+	defer addressBook.dataMu.RUnlock()
+	contacts := make([]Contact, len(addressBook.data.Contacts))
+	for i, contact := range addressBook.data.Contacts {
+		contacts[i] = *contact
+	}
+	return contacts
+}
+
+// Contact returns a copy of the contact with the given ID, or nil if it does not exist.
+func (addressBook *AddressBook) Contact(id string) *Contact {
+	addressBook.dataMu.RLock()
+	defer addressBook.dataMu.RUnlock()
+	_, contact := addressBook.lookup(id)
+	if contact == nil {
+		return nil
+	}
+	contactCopy := *contact
+	return &contactCopy
+}
+
+// Add adds a new contact. The ID of the contact is ignored and a new one is assigned. The added
+// contact is returned.
+func (addressBook *AddressBook) Add(contact Contact) (*Contact, error) {
+	if err := contact.validate(); err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	contact.ID = hex.EncodeToString(id)
+
+	addressBook.dataMu.Lock()
+	defer addressBook.dataMu.Unlock()
+	addressBook.data.Contacts = append(addressBook.data.Contacts, &contact)
+	if err := addressBook.write(); err != nil {
+		return nil, err
+	}
+	contactCopy := contact
+	return &contactCopy, nil
+}
+
+// Update replaces the contact with the same ID.
+func (addressBook *AddressBook) Update(contact Contact) error {
+	if err := contact.validate(); err != nil {
+		return err
+	}
+	addressBook.dataMu.Lock()
+	defer addressBook.dataMu.Unlock()
+	i, existing := addressBook.lookup(contact.ID)
+	if existing == nil {
+		return errp.Newf("Could not find contact %s", contact.ID)
+	}
+	addressBook.data.Contacts[i] = &contact
+	return addressBook.write()
+}
+
+// Delete removes the contact with the given ID.
+func (addressBook *AddressBook) Delete(id string) error {
+	addressBook.dataMu.Lock()
+	defer addressBook.dataMu.Unlock()
+	i, existing := addressBook.lookup(id)
+	if existing == nil {
+		return errp.Newf("Could not find contact %s", id)
+	}
+	addressBook.data.Contacts = append(addressBook.data.Contacts[:i], addressBook.data.Contacts[i+1:]...)
+	return addressBook.write()
+}
+
+// Matches returns true if the contact with the given ID exists, is for the given coin and has the
+// given address. Contacts with an xpub never match, as the address can't be checked without
+// deriving and scanning the xpub's addresses.
+func (addressBook *AddressBook) Matches(id string, coinCode coin.Code, address string) bool {
+	contact := addressBook.Contact(id)
+	if contact == nil || contact.CoinCode != coinCode {
+		return false
+	}
+	return normalizeAddress(contact.Address) == normalizeAddress(address)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addressbook
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressBook(t *testing.T) {
+	filename := test.TstTempFile("addressbook")
+	addressBook, err := Load(filename)
+	require.NoError(t, err)
+	require.Empty(t, addressBook.Contacts())
+
+	alice, err := addressBook.Add(Contact{
+		Name:     " Alice ",
+		CoinCode: coin.CodeBTC,
+		Address:  "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq",
+		Note:     "friend",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, alice.ID)
+	require.Equal(t, "Alice", alice.Name)
+	bob, err := addressBook.Add(Contact{
+		Name:     "Bob",
+		CoinCode: coin.CodeETH,
+		Address:  "0x9e4D6A2FA9bD0A1f7b1F2dE2a1A0eC6a3E4b5C6d",
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, alice.ID, bob.ID)
+
+	_, err = addressBook.Add(Contact{Name: "", CoinCode: coin.CodeBTC, Address: "x"})
+	require.Error(t, err)
+	_, err = addressBook.Add(Contact{Name: "Carol", CoinCode: coin.CodeBTC})
+	require.Error(t, err)
+
+	require.True(t, addressBook.Matches(alice.ID, coin.CodeBTC, "BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ"))
+	require.False(t, addressBook.Matches(alice.ID, coin.CodeLTC, "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"))
+	require.False(t, addressBook.Matches(alice.ID, coin.CodeBTC, "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh"))
+	require.True(t, addressBook.Matches(bob.ID, coin.CodeETH, "0x9e4d6a2fa9bd0a1f7b1f2de2a1a0ec6a3e4b5c6d"))
+	require.False(t, addressBook.Matches("unknown", coin.CodeETH, "0x9e4d6a2fa9bd0a1f7b1f2de2a1a0ec6a3e4b5c6d"))
+
+	bob.Name = "Bobby"
+	require.NoError(t, addressBook.Update(*bob))
+	require.Error(t, addressBook.Update(Contact{ID: "unknown", Name: "x", CoinCode: coin.CodeBTC, Address: "x"}))
+	require.NoError(t, addressBook.Delete(alice.ID))
+	require.Error(t, addressBook.Delete(alice.ID))
+
+	// The changes are persisted.
+	reloaded, err := Load(filename)
+	require.NoError(t, err)
+	require.Equal(t, []Contact{*bob}, reloaded.Contacts())
+	require.Nil(t, reloaded.Contact(alice.ID))
+}
@@ -174,7 +174,9 @@ func (backend *Backend) aoppKeystoreRegistered() {
 	if backend.aopp.State != aoppStateAwaitingKeystore {
 		return
 	}
-	if !backend.keystore.CanSignMessage(backend.aopp.coinCode) {
+	// Only the accounts of the keystore that was registered first are offered.
+	ks := backend.keystores.main()
+	if !ks.CanSignMessage(backend.aopp.coinCode) {
 		backend.aoppSetError(errAOPPUnsupportedKeystore)
 		return
 	}
@@ -188,7 +190,7 @@ func (backend *Backend) aoppKeystoreRegistered() {
 			return
 		}
 
-		if err := compareRootFingerprint(backend.keystore, accountFingerprint); err != nil {
+		if err := compareRootFingerprint(ks, accountFingerprint); err != nil {
 			continue
 		}
 		if acct.Config().Config.Inactive || acct.Config().Config.HiddenBecauseUnused {
@@ -298,7 +300,7 @@ func (backend *Backend) AOPPApprove() {
 		return
 	}
 	backend.aopp.State = aoppStateAwaitingKeystore
-	if backend.keystore == nil {
+	if backend.keystores.main() == nil {
 		backend.notifyAOPP()
 		return
 	}
@@ -329,6 +331,12 @@ func (backend *Backend) aoppChooseAccount(code accountsTypes.Code) {
 		backend.aoppSetError(errAOPPUnknown)
 		return
 	}
+	ks := backend.keystores.owner(account.Config().Config)
+	if ks == nil {
+		log.Error("aopp: keystore of the account is not connected")
+		backend.aoppSetError(errAOPPUnknown)
+		return
+	}
 	if err := account.Initialize(); err != nil {
 		log.
 			WithError(err).
@@ -370,7 +378,7 @@ func (backend *Backend) aoppChooseAccount(code accountsTypes.Code) {
 	}
 	switch account.Coin().Code() {
 	case coinpkg.CodeBTC:
-		sig, err := backend.keystore.SignBTCMessage(
+		sig, err := ks.SignBTCMessage(
 			[]byte(backend.aopp.Message),
 			addr.AbsoluteKeypath(),
 			account.Config().Config.SigningConfigurations[signingConfigIdx].ScriptType(),
@@ -387,7 +395,7 @@ func (backend *Backend) aoppChooseAccount(code accountsTypes.Code) {
 		}
 		signature = sig
 	case coinpkg.CodeETH:
-		sig, err := backend.keystore.SignETHMessage(
+		sig, err := ks.SignETHMessage(
 			[]byte(backend.aopp.Message),
 			addr.AbsoluteKeypath(),
 		)
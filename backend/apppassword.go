@@ -0,0 +1,138 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/random"
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrWrongAppPassword is returned if the app password does not match.
+var ErrWrongAppPassword = errors.New("wrong app password")
+
+const (
+	// argon2id parameters, the second recommended option of RFC 9106.
+	appPasswordTime    = 3
+	appPasswordMemory  = 64 * 1024
+	appPasswordThreads = 4
+
+	appPasswordHashSize = 32
+	appPasswordSaltSize = 16
+)
+
+func hashAppPassword(password string, salt []byte) []byte {
+	return argon2.IDKey(
+		[]byte(password), salt, appPasswordTime, appPasswordMemory, appPasswordThreads,
+		appPasswordHashSize)
+}
+
+// checkAppPassword returns ErrWrongAppPassword if the password does not match the configured one.
+func checkAppPassword(appPassword *config.AppPassword, password string) error {
+	if subtle.ConstantTimeCompare(hashAppPassword(password, appPassword.Salt), appPassword.Hash) != 1 {
+		return errp.WithStack(ErrWrongAppPassword)
+	}
+	return nil
+}
+
+// AppPasswordStatus describes whether the app is protected with a password.
+type AppPasswordStatus struct {
+	Enabled bool `json:"enabled"`
+	// Locked is true if the password is enabled and was not entered yet. Until then, only the
+	// endpoints of the lock screen are served and no events are sent to websocket clients.
+	Locked bool `json:"locked"`
+}
+
+// AppPasswordStatus returns the current app password status.
+func (backend *Backend) AppPasswordStatus() AppPasswordStatus {
+	defer backend.appLockedLock.RLock()()
+	return AppPasswordStatus{
+		Enabled: backend.config.AppConfig().Backend.AppPassword != nil,
+		Locked:  backend.appLocked,
+	}
+}
+
+// AppLocked returns true if the app password is enabled and was not entered yet.
+func (backend *Backend) AppLocked() bool {
+	defer backend.appLockedLock.RLock()()
+	return backend.appLocked
+}
+
+// UnlockApp unlocks the app with the app password.
+func (backend *Backend) UnlockApp(password string) error {
+	appPassword := backend.config.AppConfig().Backend.AppPassword
+	if appPassword == nil {
+		return errp.New("app password not enabled")
+	}
+	if err := checkAppPassword(appPassword, password); err != nil {
+		return err
+	}
+	defer backend.appLockedLock.Lock()()
+	backend.appLocked = false
+	backend.log.Info("App unlocked")
+	return nil
+}
+
+// lockApp locks the app if the app password is enabled.
+func (backend *Backend) lockApp() {
+	if backend.config.AppConfig().Backend.AppPassword == nil {
+		return
+	}
+	defer backend.appLockedLock.Lock()()
+	backend.appLocked = true
+}
+
+// EnableAppPassword protects the app with the password, which has to be entered after each start
+// and after the app was locked, see Lock().
+func (backend *Backend) EnableAppPassword(password string) error {
+	if password == "" {
+		return errp.New("empty password")
+	}
+	salt := random.BytesOrPanic(appPasswordSaltSize)
+	return backend.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		if appConfig.Backend.AppPassword != nil {
+			return errp.New("app password already enabled")
+		}
+		appConfig.Backend.AppPassword = &config.AppPassword{
+			Salt: salt,
+			Hash: hashAppPassword(password, salt),
+		}
+		return nil
+	})
+}
+
+// DisableAppPassword removes the app password. The current password has to be provided.
+func (backend *Backend) DisableAppPassword(password string) error {
+	err := backend.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		if appConfig.Backend.AppPassword == nil {
+			return errp.New("app password not enabled")
+		}
+		if err := checkAppPassword(appConfig.Backend.AppPassword, password); err != nil {
+			return err
+		}
+		appConfig.Backend.AppPassword = nil
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer backend.appLockedLock.Lock()()
+	backend.appLocked = false
+	return nil
+}
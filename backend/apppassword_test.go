@@ -0,0 +1,49 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppPassword(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	require.Equal(t, AppPasswordStatus{}, b.AppPasswordStatus())
+	require.Error(t, b.UnlockApp("password"))
+	// Locking without a password does not lock the app.
+	b.Lock()
+	require.False(t, b.AppLocked())
+
+	require.Error(t, b.EnableAppPassword(""))
+	require.NoError(t, b.EnableAppPassword("password"))
+	require.Error(t, b.EnableAppPassword("other"))
+	require.Equal(t, AppPasswordStatus{Enabled: true}, b.AppPasswordStatus())
+
+	b.Lock()
+	require.Equal(t, AppPasswordStatus{Enabled: true, Locked: true}, b.AppPasswordStatus())
+	require.Equal(t, ErrWrongAppPassword, errp.Cause(b.UnlockApp("wrong")))
+	require.True(t, b.AppLocked())
+	require.NoError(t, b.UnlockApp("password"))
+	require.False(t, b.AppLocked())
+
+	require.Equal(t, ErrWrongAppPassword, errp.Cause(b.DisableAppPassword("wrong")))
+	require.NoError(t, b.DisableAppPassword("password"))
+	require.Equal(t, AppPasswordStatus{}, b.AppPasswordStatus())
+}
@@ -58,6 +58,10 @@ type Arguments struct {
 	// gapLimits optionally forces the gap limits used in btc/ltc.
 	gapLimits *btctypes.GapLimits
 
+	// regtestElectrumServers optionally overrides the Electrum servers of the regtest coin, e.g.
+	// to connect to a local electrs instance.
+	regtestElectrumServers []string
+
 	// log is the logger for this context
 	log *logrus.Entry
 }
@@ -158,6 +162,21 @@ func (arguments *Arguments) Regtest() bool {
 	return arguments.regtest
 }
 
+// SetRegtestElectrumServers sets the plain TCP Electrum servers (host:port) used for regtest
+// instead of the default ones. Only has an effect in regtest mode and must be called before the
+// backend is created.
+func (arguments *Arguments) SetRegtestElectrumServers(servers []string) {
+	if !arguments.regtest {
+		panic("Regtest Electrum servers require -regtest.")
+	}
+	arguments.regtestElectrumServers = servers
+}
+
+// RegtestElectrumServers returns the Electrum servers set by SetRegtestElectrumServers.
+func (arguments *Arguments) RegtestElectrumServers() []string {
+	return arguments.regtestElectrumServers
+}
+
 // GapLimits returns the gap limits to be used in btc/ltc (all account types).
 // This is optional, so nil is a valid return value.
 func (arguments *Arguments) GapLimits() *btctypes.GapLimits {
@@ -0,0 +1,66 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"time"
+)
+
+// RecordActivity restarts the auto-lock timer. It is called on each API request, so the keystores
+// are locked after the configured period without any activity in the UI.
+func (backend *Backend) RecordActivity() {
+	defer backend.autoLockTimerLock.Lock()()
+	minutes := backend.config.AppConfig().Backend.AutoLockMinutes
+	if minutes <= 0 {
+		if backend.autoLockTimer != nil {
+			backend.autoLockTimer.Stop()
+			backend.autoLockTimer = nil
+		}
+		return
+	}
+	timeout := time.Duration(minutes) * time.Minute
+	if backend.autoLockTimer != nil && backend.autoLockTimer.Stop() {
+		backend.autoLockTimer.Reset(timeout)
+		return
+	}
+	backend.autoLockTimer = time.AfterFunc(timeout, backend.autoLock)
+}
+
+func (backend *Backend) stopAutoLockTimer() {
+	defer backend.autoLockTimerLock.Lock()()
+	if backend.autoLockTimer != nil {
+		backend.autoLockTimer.Stop()
+		backend.autoLockTimer = nil
+	}
+}
+
+// autoLock is called by the auto-lock timer.
+func (backend *Backend) autoLock() {
+	if len(backend.Keystores()) == 0 && (backend.AppLocked() || !backend.AppPasswordStatus().Enabled) {
+		return
+	}
+	backend.log.Info("locking after inactivity")
+	backend.Lock()
+}
+
+// Lock deregisters all keystores, so the accounts are hidden until the devices are connected and
+// unlocked again. If the app password is enabled, it has to be entered again as well. A `locked`
+// event is sent so the frontend can leave the screens showing wallet data.
+func (backend *Backend) Lock() {
+	backend.log.Info("lock")
+	backend.DeregisterKeystore()
+	backend.lockApp()
+	backend.events <- backendEvent{Type: "backend", Data: "locked"}
+}
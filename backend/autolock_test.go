@@ -0,0 +1,66 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/software"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoLock(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	// Disabled by default.
+	b.RecordActivity()
+	require.Nil(t, b.autoLockTimer)
+
+	require.NoError(t, b.config.ModifyAppConfig(func(c *config.AppConfig) error {
+		c.Backend.AutoLockMinutes = 5
+		return nil
+	}))
+	b.RecordActivity()
+	require.NotNil(t, b.autoLockTimer)
+
+	ks := software.NewKeystore(test.TstMustXKey("xprv9s21ZrQH143K3gie3VFLgx8JcmqZNsBcBc6vAdJrsf4bPRhx69U8qZe3EYAyvRWyQdEfz7ZpyYtL8jW2d2Lfkfh6g2zivq8JdZPQqxoxLwB"))
+	b.registerKeystore(ks)
+	require.Len(t, b.Keystores(), 1)
+
+	// Called when the timer fires.
+	b.autoLock()
+	require.Empty(t, b.Keystores())
+	locked := false
+	for len(b.events) > 0 {
+		if event, ok := (<-b.events).(backendEvent); ok && event.Data == "locked" {
+			locked = true
+		}
+	}
+	require.True(t, locked)
+
+	// Nothing to lock.
+	b.autoLock()
+	require.Empty(t, b.events)
+
+	require.NoError(t, b.config.ModifyAppConfig(func(c *config.AppConfig) error {
+		c.Backend.AutoLockMinutes = 0
+		return nil
+	}))
+	b.RecordActivity()
+	require.Nil(t, b.autoLockTimer)
+}
@@ -16,6 +16,7 @@
 package backend
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,9 +28,12 @@ import (
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/addressbook"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/arguments"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/banners"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/bitcoind"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/electrum"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
 	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
@@ -37,6 +41,7 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/etherscan"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/ltc"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bluetooth"
@@ -44,8 +49,11 @@ import (
 	deviceevent "github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device/event"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/usb"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/hwi"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/software"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/notifications"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/rates"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/syncscheduler"
 	utilConfig "github.com/BitBoxSwiss/bitbox-wallet-app/util/config"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
@@ -54,7 +62,9 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable/action"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/ratelimit"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/socksproxy"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/sirupsen/logrus"
 )
@@ -63,6 +73,19 @@ func init() {
 	electrum.SetClientSoftwareVersion(Version)
 }
 
+// blockExplorerCoinCodes are the coins with a configurable block explorer. ERC20 tokens use the
+// block explorer of ETH.
+var blockExplorerCoinCodes = []coinpkg.Code{
+	coinpkg.CodeBTC,
+	coinpkg.CodeTBTC,
+	coinpkg.CodeTSIG,
+	coinpkg.CodeRBTC,
+	coinpkg.CodeLTC,
+	coinpkg.CodeTLTC,
+	coinpkg.CodeETH,
+	coinpkg.CodeSEPETH,
+}
+
 // fixedURLWhitelist is always allowed by SystemOpen, in addition to some
 // adhoc URLs. See SystemOpen for details.
 var fixedURLWhitelist = []string{
@@ -72,13 +95,6 @@ var fixedURLWhitelist = []string{
 	"https://shiftcrypto.support/",
 	// Exchange rates.
 	"https://www.coingecko.com/",
-	// Block explorers.
-	"https://blockstream.info/tx/",
-	"https://blockstream.info/testnet/tx/",
-	"https://sochain.com/tx/LTCTEST/",
-	"https://blockchair.com/litecoin/transaction/",
-	"https://etherscan.io/tx/",
-	"https://sepolia.etherscan.io/tx/",
 	// Moonpay onramp
 	"https://www.moonpay.com/",
 	"https://support.moonpay.com/",
@@ -203,13 +219,34 @@ type Backend struct {
 
 	accountsAndKeystoreLock locker.Locker
 	accounts                AccountsList
-	// keystore is nil if no keystore is connected.
-	keystore keystore.Keystore
+	// keystores are the connected keystores. Each one has its own set of accounts.
+	keystores keystoreRegistry
+	// deviceRootFingerprints maps the ID of a registered device to the root fingerprint of its
+	// keystore, so it can be deregistered when the device is unplugged.
+	deviceRootFingerprints map[string][]byte
+
+	testSimulatorLock locker.Locker
+	// testSimulator is the simulated device created by CreateTestSimulator, nil if there is none.
+	testSimulator *software.Simulator
 
 	connectKeystore connectKeystore
 
 	aopp AOPP
 
+	paymentURILock locker.Locker
+	// paymentURI is the payment request of the last opened `bitcoin:` or `litecoin:` URI, nil if
+	// there is none.
+	paymentURI *PaymentURI
+
+	appLockedLock locker.Locker
+	// appLocked is true while the app password was not entered, see UnlockApp().
+	appLocked bool
+
+	autoLockTimerLock locker.Locker
+	// autoLockTimer locks the keystores when it fires. It is restarted on each API request, see
+	// RecordActivity(). nil if the auto-lock is disabled.
+	autoLockTimer *time.Timer
+
 	// makeBtcAccount creates a BTC account. In production this is `btc.NewAccount`, but can be
 	// overridden in unit tests for mocking.
 	makeBtcAccount func(*accounts.AccountConfig, *btc.Coin, *types.GapLimits, *logrus.Entry) accounts.Interface
@@ -233,11 +270,19 @@ type Backend struct {
 	etherScanHTTPClient *http.Client
 	ratesUpdater        *rates.RateUpdater
 	banners             *banners.Banners
+	syncScheduler       *syncscheduler.Scheduler
+	addressBook         *addressbook.AddressBook
+	crashReports        *crashreport.Reports
+	notifications       *notifications.Notifications
+	// vault encrypts the accounts config and the notes if the user enabled the encryption.
+	vault *vault.Vault
 
 	// For unit tests, called when `backend.checkAccountUsed()` is called.
 	tstCheckAccountUsed func(accounts.Interface) bool
 	// For unit tests, called when `backend.maybeAddHiddenUnusedAccounts()` has run.
 	tstMaybeAddHiddenUnusedAccounts func()
+	// For unit tests, replaces calling the HWI executable.
+	tstHWIRunner hwi.Runner
 
 	// testing tells us whether the app is in testing mode
 	testing bool
@@ -246,7 +291,16 @@ type Backend struct {
 // NewBackend creates a new backend with the given arguments.
 func NewBackend(arguments *arguments.Arguments, environment Environment) (*Backend, error) {
 	log := logging.Get().WithGroup("backend")
-	backendConfig, err := config.NewConfig(arguments.AppConfigFilename(), arguments.AccountsConfigFilename())
+	filenames, err := encryptedFilenames(arguments)
+	if err != nil {
+		return nil, err
+	}
+	configVault, err := vault.New(filepath.Join(arguments.MainDirectoryPath(), "encryption.json"), filenames...)
+	if err != nil {
+		return nil, err
+	}
+	backendConfig, err := config.NewConfig(
+		arguments.AppConfigFilename(), arguments.AccountsConfigFilename(), configVault)
 	if err != nil {
 		return nil, errp.WithStack(err)
 	}
@@ -267,10 +321,11 @@ func NewBackend(arguments *arguments.Arguments, environment Environment) (*Backe
 		config:      backendConfig,
 		events:      make(chan interface{}, 1000),
 
-		devices:  map[string]device.Interface{},
-		coins:    map[coinpkg.Code]coinpkg.Coin{},
-		accounts: []accounts.Interface{},
-		aopp:     AOPP{State: aoppStateInactive},
+		devices:                map[string]device.Interface{},
+		deviceRootFingerprints: map[string][]byte{},
+		coins:                  map[coinpkg.Code]coinpkg.Coin{},
+		accounts:               []accounts.Interface{},
+		aopp:                   AOPP{State: aoppStateInactive},
 		makeBtcAccount: func(config *accounts.AccountConfig, coin *btc.Coin, gapLimits *types.GapLimits, log *logrus.Entry) accounts.Interface {
 			return btc.NewAccount(config, coin, gapLimits, log, hclient)
 		},
@@ -281,6 +336,8 @@ func NewBackend(arguments *arguments.Arguments, environment Environment) (*Backe
 		log: log,
 
 		testing: backendConfig.AppConfig().Backend.StartInTestnet || arguments.Testing(),
+
+		appLocked: backendConfig.AppConfig().Backend.AppPassword != nil,
 	}
 
 	notifier, err := NewNotifier(filepath.Join(arguments.MainDirectoryPath(), "notifier.db"))
@@ -288,6 +345,24 @@ func NewBackend(arguments *arguments.Arguments, environment Environment) (*Backe
 		return nil, err
 	}
 	backend.notifier = notifier
+	addressBook, err := addressbook.Load(filepath.Join(arguments.MainDirectoryPath(), "addressbook.json"))
+	if err != nil {
+		return nil, err
+	}
+	backend.addressBook = addressBook
+	crashReports, err := crashreport.Load(filepath.Join(arguments.MainDirectoryPath(), "crashreports.json"))
+	if err != nil {
+		return nil, err
+	}
+	backend.crashReports = crashReports
+	// Panics of background goroutines are reported like the ones in API handlers.
+	crashreport.SetHandler(backend.ReportCrash)
+	storedNotifications, err := notifications.Load(filepath.Join(arguments.MainDirectoryPath(), "notifications.json"))
+	if err != nil {
+		return nil, err
+	}
+	backend.notifications = storedNotifications
+	backend.vault = configVault
 	backend.socksProxy = backendProxy
 	backend.httpClient = hclient
 	backend.etherScanHTTPClient = ratelimit.FromTransport(hclient.Transport, etherscan.CallInterval)
@@ -297,11 +372,22 @@ func NewBackend(arguments *arguments.Arguments, environment Environment) (*Backe
 		log.Errorf("RateUpdater DB cache dir: %v", err)
 	}
 	backend.ratesUpdater = rates.NewRateUpdater(hclient, ratesCache)
+	backend.ratesUpdater.SetPreferredProvider(func() string {
+		return backend.config.AppConfig().Backend.RatesProvider
+	})
+	backend.ratesUpdater.SetFiats(func() []string {
+		backendConfig := backend.config.AppConfig().Backend
+		return append([]string{backendConfig.MainFiat}, backendConfig.FiatList...)
+	})
 	backend.ratesUpdater.Observe(backend.Notify)
 
 	backend.banners = banners.NewBanners()
 	backend.banners.Observe(backend.Notify)
 
+	backend.syncScheduler = syncscheduler.NewScheduler(func() config.SyncThrottling {
+		return backend.config.AppConfig().Backend.SyncThrottling
+	})
+
 	backend.bluetooth = bluetooth.New(log)
 	backend.bluetooth.Observe(backend.Notify)
 
@@ -337,6 +423,7 @@ func (backend *Backend) notifyNewTxs(account accounts.Interface) {
 			"count":       unnotifiedCount,
 			"accountName": account.Config().Config.Name,
 		}}
+		backend.notifyIncomingTxs(account, notifier)
 
 		if err := notifier.MarkAllNotified(); err != nil {
 			backend.log.WithError(err).Error("error marking notified")
@@ -344,6 +431,126 @@ func (backend *Backend) notifyNewTxs(account accounts.Interface) {
 	}
 }
 
+// notifyDustOutputs emits a `dustOutputs` event if new coins which look like a dusting attack were
+// received by a BTC-based account, so the user can mark them as "do not spend".
+func (backend *Backend) notifyDustOutputs(account accounts.Interface) {
+	btcAccount, ok := account.(*btc.Account)
+	if !ok {
+		return
+	}
+	dustOutputs := btcAccount.NewDustOutputs()
+	if len(dustOutputs) == 0 {
+		return
+	}
+	outPoints := make([]string, len(dustOutputs))
+	for i, output := range dustOutputs {
+		outPoints[i] = output.OutPoint.String()
+	}
+	backend.events <- backendEvent{Type: "backend", Data: "dustOutputs", Meta: map[string]interface{}{
+		"count":       len(dustOutputs),
+		"accountCode": account.Config().Config.Code,
+		"accountName": account.Config().Config.Name,
+		"outPoints":   outPoints,
+	}}
+}
+
+// notifyDroppedTxs emits a `txDropped` event for each unconfirmed transaction of a BTC-based
+// account which was newly dropped from the mempool or double-spent.
+func (backend *Backend) notifyDroppedTxs(account accounts.Interface) {
+	btcAccount, ok := account.(*btc.Account)
+	if !ok {
+		return
+	}
+	droppedTxs, err := btcAccount.NewDroppedTransactions()
+	if err != nil {
+		backend.log.WithError(err).Error("error getting dropped transactions")
+		return
+	}
+	for _, transaction := range droppedTxs {
+		backend.events <- backendEvent{Type: "backend", Data: "txDropped", Meta: map[string]interface{}{
+			"accountCode": account.Config().Config.Code,
+			"accountName": account.Config().Config.Name,
+			"txID":        transaction.TxID,
+			"status":      transaction.Status,
+		}}
+		backend.addNotification(notifications.TypeTxDropped, map[string]string{
+			"accountCode": string(account.Config().Config.Code),
+			"accountName": account.Config().Config.Name,
+			"txID":        transaction.TxID,
+			"status":      string(transaction.Status),
+		})
+	}
+}
+
+// maxIncomingTxEvents is the maximum number of new incoming transactions of an account for which
+// individual events are emitted. More are usually historical transactions found when an account is
+// synced for the first time, which should not result in a flood of notifications.
+const maxIncomingTxEvents = 5
+
+// notifierIDs returns the ids under which a transaction can be stored in the notifier. BTC-based
+// accounts store the raw tx hash, ETH-based accounts the tx ID.
+func notifierIDs(txID string) []string {
+	ids := []string{txID}
+	if hash, err := chainhash.NewHashFromStr(txID); err == nil {
+		ids = append(ids, string(hash[:]))
+	}
+	return ids
+}
+
+// notifyIncomingTxs emits an `incomingTx` event for each unnotified incoming transaction of the
+// account, so that the frontend can show a system notification.
+func (backend *Backend) notifyIncomingTxs(account accounts.Interface, notifier accounts.Notifier) {
+	unnotifiedIDs, err := notifier.UnnotifiedIDs()
+	if err != nil {
+		backend.log.WithError(err).Error("error getting unnotified ids")
+		return
+	}
+	unnotified := map[string]struct{}{}
+	for _, id := range unnotifiedIDs {
+		unnotified[string(id)] = struct{}{}
+	}
+	transactions, err := account.Transactions()
+	if err != nil {
+		backend.log.WithError(err).Error("error getting transactions")
+		return
+	}
+	incoming := []*accounts.TransactionData{}
+	for _, transaction := range transactions {
+		if transaction.Type != accounts.TxTypeReceive {
+			continue
+		}
+		for _, id := range notifierIDs(transaction.TxID) {
+			if _, ok := unnotified[id]; ok {
+				incoming = append(incoming, transaction)
+				break
+			}
+		}
+	}
+	if len(incoming) > maxIncomingTxEvents {
+		return
+	}
+	accountCoin := account.Coin()
+	for _, transaction := range incoming {
+		backend.events <- backendEvent{Type: "backend", Data: "incomingTx", Meta: map[string]interface{}{
+			"accountCode": account.Config().Config.Code,
+			"accountName": account.Config().Config.Name,
+			"coinCode":    accountCoin.Code(),
+			"txID":        transaction.TxID,
+			"amount":      accountCoin.FormatAmount(transaction.Amount, false),
+			"unit":        accountCoin.GetFormatUnit(false),
+			"pending":     transaction.Status == accounts.TxStatusPending,
+		}}
+		backend.addNotification(notifications.TypeIncomingTx, map[string]string{
+			"accountCode": string(account.Config().Config.Code),
+			"accountName": account.Config().Config.Name,
+			"coinCode":    string(accountCoin.Code()),
+			"txID":        transaction.TxID,
+			"amount":      accountCoin.FormatAmount(transaction.Amount, false),
+			"unit":        accountCoin.GetFormatUnit(false),
+		})
+	}
+}
+
 // Config returns the app config.
 func (backend *Backend) Config() *config.Config {
 	return backend.config
@@ -433,6 +640,8 @@ func (backend *Backend) defaultProdServers(code coinpkg.Code) []*config.ServerIn
 		return backend.config.AppConfig().Backend.BTC.ElectrumServers
 	case coinpkg.CodeTBTC:
 		return backend.config.AppConfig().Backend.TBTC.ElectrumServers
+	case coinpkg.CodeTSIG:
+		return backend.config.AppConfig().Backend.TSIG.ElectrumServers
 	case coinpkg.CodeRBTC:
 		return backend.config.AppConfig().Backend.RBTC.ElectrumServers
 	case coinpkg.CodeLTC:
@@ -444,6 +653,49 @@ func (backend *Backend) defaultProdServers(code coinpkg.Code) []*config.ServerIn
 	}
 }
 
+// prioritizeElectrumServers returns true if the Electrum servers of the coin are configured to be
+// tried in order. Always false for non-BTC-based coins and the dev servers.
+func (backend *Backend) prioritizeElectrumServers(code coinpkg.Code) bool {
+	if backend.arguments.DevServers() {
+		return false
+	}
+	backendConfig := backend.config.AppConfig().Backend
+	switch code {
+	case coinpkg.CodeBTC:
+		return backendConfig.BTC.PrioritizeElectrumServers
+	case coinpkg.CodeTBTC:
+		return backendConfig.TBTC.PrioritizeElectrumServers
+	case coinpkg.CodeTSIG:
+		return backendConfig.TSIG.PrioritizeElectrumServers
+	case coinpkg.CodeRBTC:
+		return backendConfig.RBTC.PrioritizeElectrumServers
+	case coinpkg.CodeLTC:
+		return backendConfig.LTC.PrioritizeElectrumServers
+	case coinpkg.CodeTLTC:
+		return backendConfig.TLTC.PrioritizeElectrumServers
+	default:
+		return false
+	}
+}
+
+// bitcoinCoreConfig returns the Bitcoin Core node config of the coin, or nil if the coin cannot use
+// a Bitcoin Core node.
+func (backend *Backend) bitcoinCoreConfig(code coinpkg.Code) *config.BitcoinCoreConfig {
+	backendConfig := backend.config.AppConfig().Backend
+	switch code {
+	case coinpkg.CodeBTC:
+		return &backendConfig.BTC.BitcoinCore
+	case coinpkg.CodeTBTC:
+		return &backendConfig.TBTC.BitcoinCore
+	case coinpkg.CodeTSIG:
+		return &backendConfig.TSIG.BitcoinCore
+	case coinpkg.CodeRBTC:
+		return &backendConfig.RBTC.BitcoinCore
+	default:
+		return nil
+	}
+}
+
 func defaultDevServers(code coinpkg.Code) []*config.ServerInfo {
 	// O=Shift Crypto, CN=ShiftCrypto DEV R1
 	// Serial: f67ab2bc7470c90ce027ce778a274384
@@ -464,6 +716,9 @@ mCMuGBNHsbrs6rI1hbI4Qq6GYazLaDRqdCufTA==
 		return []*config.ServerInfo{{Server: "btc1.shiftcrypto.dev:50001", TLS: true, PEMCert: devShiftCA}}
 	case coinpkg.CodeTBTC:
 		return []*config.ServerInfo{{Server: "tbtc1.shiftcrypto.dev:51001", TLS: true, PEMCert: devShiftCA}}
+	case coinpkg.CodeTSIG:
+		// There is no signet dev server, the public servers are used.
+		return config.NewDefaultAppConfig().Backend.TSIG.ElectrumServers
 	case coinpkg.CodeRBTC:
 		return []*config.ServerInfo{
 			{Server: "127.0.0.1:52001", TLS: false, PEMCert: ""},
@@ -479,6 +734,13 @@ mCMuGBNHsbrs6rI1hbI4Qq6GYazLaDRqdCufTA==
 }
 
 func (backend *Backend) defaultElectrumXServers(code coinpkg.Code) []*config.ServerInfo {
+	if regtestServers := backend.arguments.RegtestElectrumServers(); code == coinpkg.CodeRBTC && len(regtestServers) > 0 {
+		servers := make([]*config.ServerInfo, len(regtestServers))
+		for i, server := range regtestServers {
+			servers[i] = &config.ServerInfo{Server: server, TLS: false, PEMCert: ""}
+		}
+		return servers
+	}
 	if backend.arguments.DevServers() {
 		return defaultDevServers(code)
 	}
@@ -502,48 +764,78 @@ func (backend *Backend) Coin(code coinpkg.Code) (coinpkg.Coin, error) {
 
 	erc20Token := erc20TokenByCode(code)
 	btcFormatUnit := backend.config.AppConfig().Backend.BtcUnit
+	blockExplorerTxPrefix := backend.config.AppConfig().Backend.BlockExplorerTxPrefix
 	switch {
 	case code == coinpkg.CodeRBTC:
 		servers := backend.defaultElectrumXServers(code)
-		coin = btc.NewCoin(coinpkg.CodeRBTC, "Bitcoin Regtest", "RBTC", coinpkg.BtcUnitDefault, &chaincfg.RegressionNetParams, dbFolder, servers, "", backend.socksProxy)
+		coin = btc.NewCoin(coinpkg.CodeRBTC, "Bitcoin Regtest", "RBTC", coinpkg.BtcUnitDefault, &chaincfg.RegressionNetParams, dbFolder, servers,
+			blockExplorerTxPrefix(code), backend.socksProxy)
 	case code == coinpkg.CodeTBTC:
 		servers := backend.defaultElectrumXServers(code)
 		coin = btc.NewCoin(coinpkg.CodeTBTC, "Bitcoin Testnet", "TBTC", btcFormatUnit, &chaincfg.TestNet3Params, dbFolder, servers,
-			"https://blockstream.info/testnet/tx/", backend.socksProxy)
+			blockExplorerTxPrefix(code), backend.socksProxy)
+	case code == coinpkg.CodeTSIG:
+		servers := backend.defaultElectrumXServers(code)
+		coin = btc.NewCoin(coinpkg.CodeTSIG, "Bitcoin Signet", "TSIG", btcFormatUnit, &chaincfg.SigNetParams, dbFolder, servers,
+			blockExplorerTxPrefix(code), backend.socksProxy)
 	case code == coinpkg.CodeBTC:
 		servers := backend.defaultElectrumXServers(code)
 		coin = btc.NewCoin(coinpkg.CodeBTC, "Bitcoin", "BTC", btcFormatUnit, &chaincfg.MainNetParams, dbFolder, servers,
-			"https://blockstream.info/tx/", backend.socksProxy)
+			blockExplorerTxPrefix(code), backend.socksProxy)
 	case code == coinpkg.CodeTLTC:
 		servers := backend.defaultElectrumXServers(code)
 		coin = btc.NewCoin(coinpkg.CodeTLTC, "Litecoin Testnet", "TLTC", coinpkg.BtcUnitDefault, &ltc.TestNet4Params, dbFolder, servers,
-			"https://sochain.com/tx/LTCTEST/", backend.socksProxy)
+			blockExplorerTxPrefix(code), backend.socksProxy)
 	case code == coinpkg.CodeLTC:
 		servers := backend.defaultElectrumXServers(code)
 		coin = btc.NewCoin(coinpkg.CodeLTC, "Litecoin", "LTC", coinpkg.BtcUnitDefault, &ltc.MainNetParams, dbFolder, servers,
-			"https://blockchair.com/litecoin/transaction/", backend.socksProxy)
+			blockExplorerTxPrefix(code), backend.socksProxy)
 	case code == coinpkg.CodeETH:
 		etherScan := etherscan.NewEtherScan("https://api.etherscan.io/api", backend.etherScanHTTPClient)
 		coin = eth.NewCoin(etherScan, code, "Ethereum", "ETH", "ETH", params.MainnetChainConfig,
-			"https://etherscan.io/tx/",
+			blockExplorerTxPrefix(code),
 			etherScan,
 			nil)
 	case code == coinpkg.CodeSEPETH:
 		etherScan := etherscan.NewEtherScan("https://api-sepolia.etherscan.io/api", backend.etherScanHTTPClient)
 		coin = eth.NewCoin(etherScan, code, "Ethereum Sepolia", "SEPETH", "SEPETH", params.SepoliaChainConfig,
-			"https://sepolia.etherscan.io/tx/",
+			blockExplorerTxPrefix(code),
 			etherScan,
 			nil)
 	case erc20Token != nil:
 		etherScan := etherscan.NewEtherScan("https://api.etherscan.io/api", backend.etherScanHTTPClient)
 		coin = eth.NewCoin(etherScan, erc20Token.code, erc20Token.name, erc20Token.unit, "ETH", params.MainnetChainConfig,
-			"https://etherscan.io/tx/",
+			blockExplorerTxPrefix(coinpkg.CodeETH),
 			etherScan,
 			erc20Token.token,
 		)
 	default:
 		return nil, errp.Newf("unknown coin code %s", code)
 	}
+	if btcCoin, ok := coin.(*btc.Coin); ok {
+		btcCoin.SetPruneHeaders(backend.config.AppConfig().Backend.PruneHeaders)
+	}
+	switch bitcoinCore := backend.bitcoinCoreConfig(code); {
+	case bitcoinCore != nil && bitcoinCore.Enabled:
+		backend.log.WithField("code", code).Info("Using Bitcoin Core node")
+		coin.(*btc.Coin).SetMakeBlockchain(func() blockchain.Interface {
+			return bitcoind.NewClient(
+				bitcoinCore,
+				backend.log.WithField("code", code),
+				backend.socksProxy.GetTCPProxyDialer(),
+			)
+		})
+	case backend.prioritizeElectrumServers(code):
+		servers := backend.defaultElectrumXServers(code)
+		coin.(*btc.Coin).SetMakeBlockchain(func() blockchain.Interface {
+			return electrum.NewElectrumConnection(
+				servers,
+				true,
+				backend.log.WithField("code", code),
+				backend.socksProxy.GetTCPProxyDialer(),
+			)
+		})
+	}
 	backend.coins[code] = coin
 	coin.Observe(backend.Notify)
 	return coin, nil
@@ -557,6 +849,7 @@ func (backend *Backend) ManualReconnect() {
 	if backend.Testing() {
 		electrumCoinCodes = []coinpkg.Code{
 			coinpkg.CodeTBTC,
+			coinpkg.CodeTSIG,
 			coinpkg.CodeTLTC,
 		}
 	} else {
@@ -644,6 +937,8 @@ func (backend *Backend) Start() <-chan interface{} {
 		go backend.banners.Init(httpClient)
 	}
 
+	backend.checkPinnedCerts()
+
 	defer backend.accountsAndKeystoreLock.Lock()()
 	backend.initPersistedAccounts()
 	backend.emitAccountsStatusChanged()
@@ -678,25 +973,19 @@ func (backend *Backend) HTTPClient() *http.Client {
 	return backend.httpClient
 }
 
-// Keystore returns the keystore registered at this backend, or nil if no keystore is registered.
-func (backend *Backend) Keystore() keystore.Keystore {
-	defer backend.accountsAndKeystoreLock.RLock()()
-	return backend.keystore
-}
-
-// registerKeystore registers the given keystore at this backend.
-// if another keystore is already registered, it will be replaced.
-func (backend *Backend) registerKeystore(keystore keystore.Keystore) {
+// registerKeystore registers the given keystore at this backend, alongside the other registered
+// keystores. If a keystore with the same root fingerprint is already registered, it is replaced.
+// The root fingerprint is returned, or nil if the keystore could not be registered.
+func (backend *Backend) registerKeystore(keystore keystore.Keystore) []byte {
 	defer backend.accountsAndKeystoreLock.Lock()()
-	// Only for logging, if there is an error we continue anyway.
 	fingerprint, err := keystore.RootFingerprint()
 	if err != nil {
 		backend.log.WithError(err).Error("could not retrieve keystore fingerprint")
-		return
+		return nil
 	}
 	log := backend.log.WithField("rootFingerprint", fingerprint)
 	log.Info("registering keystore")
-	backend.keystore = keystore
+	backend.keystores = backend.keystores.add(fingerprint, keystore)
 	backend.Notify(observable.Event{
 		Subject: "keystores",
 		Action:  action.Reload,
@@ -739,50 +1028,89 @@ func (backend *Backend) registerKeystore(keystore keystore.Keystore) {
 
 	backend.aoppKeystoreRegistered()
 
-	backend.connectKeystore.onConnect(backend.keystore)
+	backend.connectKeystore.onConnect(keystore)
 
-	go backend.maybeAddHiddenUnusedAccounts()
+	go func() {
+		defer crashreport.Recover()
+		backend.maybeAddHiddenUnusedAccounts()
+	}()
+	return fingerprint
 }
 
-// DeregisterKeystore removes the registered keystore.
-func (backend *Backend) DeregisterKeystore() {
+// deregisterKeystores removes the registered keystores with the given root fingerprints and
+// unloads their accounts, unless they are watch-only.
+func (backend *Backend) deregisterKeystores(rootFingerprints [][]byte) {
 	defer backend.accountsAndKeystoreLock.Lock()()
 
-	if backend.keystore == nil {
+	if len(rootFingerprints) == 0 {
 		backend.log.Error("deregistering keystore, but no keystore found")
 		return
 	}
-	// Only for logging, if there is an error we continue anyway.
-	fingerprint, _ := backend.keystore.RootFingerprint()
-	backend.log.WithField("rootFingerprint", fingerprint).Info("deregistering keystore")
-	backend.keystore = nil
+	for _, fingerprint := range rootFingerprints {
+		backend.log.WithField("rootFingerprint", fingerprint).Info("deregistering keystore")
+		backend.keystores = backend.keystores.remove(fingerprint)
+	}
 	backend.Notify(observable.Event{
 		Subject: "keystores",
 		Action:  action.Reload,
 	})
 
+	// Removes the accounts that don't belong to a registered keystore anymore, then re-adds the
+	// watch-only ones.
 	backend.uninitAccounts(false)
-	// TODO: classify accounts by keystore, remove only the ones belonging to the deregistered
-	// keystore. For now we just remove all, then re-add the rest.
 	backend.initPersistedAccounts()
 	backend.emitAccountsStatusChanged()
 	backend.connectKeystore.onDisconnect()
 }
 
+// DeregisterKeystore removes all registered keystores.
+func (backend *Backend) DeregisterKeystore() {
+	rootFingerprints := func() [][]byte {
+		defer backend.accountsAndKeystoreLock.RLock()()
+		result := [][]byte{}
+		for _, registered := range backend.keystores {
+			result = append(result, registered.rootFingerprint)
+		}
+		return result
+	}()
+	backend.deregisterKeystores(rootFingerprints)
+}
+
+// registerDeviceKeystore registers the keystore of the device with the given ID.
+func (backend *Backend) registerDeviceKeystore(deviceID string, keystore keystore.Keystore) {
+	fingerprint := backend.registerKeystore(keystore)
+	if fingerprint == nil {
+		return
+	}
+	defer backend.accountsAndKeystoreLock.Lock()()
+	backend.deviceRootFingerprints[deviceID] = fingerprint
+}
+
+// deregisterDeviceKeystore removes the keystore of the device with the given ID, keeping the
+// keystores of other devices.
+func (backend *Backend) deregisterDeviceKeystore(deviceID string) {
+	rootFingerprints := func() [][]byte {
+		defer backend.accountsAndKeystoreLock.Lock()()
+		fingerprint, ok := backend.deviceRootFingerprints[deviceID]
+		if !ok {
+			return nil
+		}
+		delete(backend.deviceRootFingerprints, deviceID)
+		return [][]byte{fingerprint}
+	}()
+	backend.deregisterKeystores(rootFingerprints)
+}
+
 // Register registers the given device at this backend.
 func (backend *Backend) Register(theDevice device.Interface) error {
 	backend.devices[theDevice.Identifier()] = theDevice
 
-	mainKeystore := len(backend.devices) == 1
 	theDevice.SetOnEvent(func(event deviceevent.Event, data interface{}) {
 		switch event {
 		case deviceevent.EventKeystoreGone:
-			backend.DeregisterKeystore()
+			backend.deregisterDeviceKeystore(theDevice.Identifier())
 		case deviceevent.EventKeystoreAvailable:
-			if mainKeystore {
-				// HACK: for device based, only one is supported at the moment.
-				backend.registerKeystore(theDevice.Keystore())
-			}
+			backend.registerDeviceKeystore(theDevice.Identifier(), theDevice.Keystore())
 		}
 		backend.events <- deviceEvent{
 			DeviceID: theDevice.Identifier(),
@@ -790,6 +1118,7 @@ func (backend *Backend) Register(theDevice device.Interface) error {
 			Data:     string(event),
 			Meta:     data,
 		}
+		backend.notifyDeviceWarning(theDevice, event)
 	})
 
 	backend.onDeviceInit(theDevice)
@@ -827,7 +1156,7 @@ func (backend *Backend) Deregister(deviceID string) {
 	if device, ok := backend.devices[deviceID]; ok {
 		backend.onDeviceUninit(deviceID)
 		delete(backend.devices, deviceID)
-		backend.DeregisterKeystore()
+		backend.deregisterDeviceKeystore(deviceID)
 
 		// Old-school
 		backend.events <- backendEvent{Type: "devices", Data: "registeredChanged"}
@@ -852,15 +1181,15 @@ func (backend *Backend) RatesUpdater() *rates.RateUpdater {
 }
 
 // DownloadCert downloads the first element of the remote certificate chain.
-func (backend *Backend) DownloadCert(server string) (string, error) {
-	return electrum.DownloadCert(server, backend.socksProxy.GetTCPProxyDialer())
+func (backend *Backend) DownloadCert(ctx context.Context, server string) (string, error) {
+	return electrum.DownloadCert(ctx, server, backend.socksProxy.GetTCPProxyDialer())
 }
 
 // CheckElectrumServer checks if a connection can be established with the electrum server, and
 // whether the server is an electrum server.
-func (backend *Backend) CheckElectrumServer(serverInfo *config.ServerInfo) error {
+func (backend *Backend) CheckElectrumServer(ctx context.Context, serverInfo *config.ServerInfo) error {
 	return electrum.CheckElectrumServer(
-		serverInfo, backend.log, backend.socksProxy.GetTCPProxyDialer())
+		ctx, serverInfo, backend.log, backend.socksProxy.GetTCPProxyDialer())
 }
 
 // RegisterTestKeystore adds a keystore derived deterministically from a PIN, for convenience in
@@ -870,14 +1199,52 @@ func (backend *Backend) RegisterTestKeystore(pin string) {
 	backend.registerKeystore(softwareBasedKeystore)
 }
 
+// CreateTestSimulator creates a simulated device with the given PIN, replacing the previous one.
+// Its keystore is registered once it is unlocked with UnlockTestSimulator.
+func (backend *Backend) CreateTestSimulator(pin string) {
+	if backend.Keystore() != nil {
+		backend.DeregisterKeystore()
+	}
+	defer backend.testSimulatorLock.Lock()()
+	if backend.testSimulator != nil {
+		backend.testSimulator.Close()
+	}
+	backend.testSimulator = software.NewSimulator(pin, func() {
+		backend.Notify(observable.Event{
+			Subject: "test/simulator",
+			Action:  action.Reload,
+		})
+	})
+	backend.log.Info("Created test simulator")
+}
+
+// TestSimulator returns the simulated device, nil if none was created.
+func (backend *Backend) TestSimulator() *software.Simulator {
+	defer backend.testSimulatorLock.RLock()()
+	return backend.testSimulator
+}
+
+// UnlockTestSimulator unlocks the simulated device and registers its keystore.
+func (backend *Backend) UnlockTestSimulator(pin string) error {
+	simulator := backend.TestSimulator()
+	if simulator == nil {
+		return errp.New("no test simulator")
+	}
+	if err := simulator.Unlock(pin); err != nil {
+		return err
+	}
+	backend.registerKeystore(simulator)
+	return nil
+}
+
 // NotifyUser creates a desktop notification.
 func (backend *Backend) NotifyUser(text string) {
 	backend.environment.NotifyUser(text)
 }
 
 // SystemOpen opens the given URL using backend.environment.
-// It consults fixedURLWhitelist, matching the URL with each whitelist item.
-// If an item is a prefix of url, it is allowed to be openend.
+// It consults fixedURLWhitelist and the configured block explorers, matching the URL with each
+// item. If an item is a prefix of url, it is allowed to be openend.
 //
 // If none matched, an ad-hoc URL construction failed or opening a URL failed,
 // an error is returned.
@@ -888,6 +1255,13 @@ func (backend *Backend) SystemOpen(url string) error {
 			return backend.environment.SystemOpen(url)
 		}
 	}
+	backendConfig := backend.config.AppConfig().Backend
+	for _, code := range blockExplorerCoinCodes {
+		prefix := backendConfig.BlockExplorerTxPrefix(code)
+		if prefix != "" && strings.HasPrefix(url, prefix) {
+			return backend.environment.SystemOpen(url)
+		}
+	}
 
 	return errp.Newf("Blocked /open with url: %s", url)
 }
@@ -900,6 +1274,7 @@ func (backend *Backend) Environment() Environment {
 // Close shuts down the backend. After this, no other method should be called.
 func (backend *Backend) Close() error {
 	backend.ratesUpdater.Stop()
+	backend.stopAutoLockTimer()
 	// Call this without `accountsAndKeystoreLock` as it eventually calls `DeregisterKeystore()`,
 	// which acquires the same lock.
 	if backend.usbManager != nil {
@@ -931,9 +1306,9 @@ func (backend *Backend) Banners() *banners.Banners {
 	return backend.banners
 }
 
-// HandleURI handles an external URI click for registered protocols, e.g. 'aopp:?...' URIs.  The uri
-// param can be any string, as it is potentially passed without any validation from the calling
-// platform.
+// HandleURI handles an external URI click for registered protocols, e.g. 'aopp:?...' or
+// 'bitcoin:...' URIs. The uri param can be any string, as it is potentially passed without any
+// validation from the calling platform.
 func (backend *Backend) HandleURI(uri string) {
 	u, err := url.Parse(uri)
 	if err != nil {
@@ -943,6 +1318,8 @@ func (backend *Backend) HandleURI(uri string) {
 	switch u.Scheme {
 	case "aopp":
 		backend.handleAOPP(*u)
+	case "bitcoin", "litecoin":
+		backend.handlePaymentURI(*u)
 	default:
 		backend.log.Warningf("Unknown URI scheme: %s", uri)
 	}
@@ -36,6 +36,7 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/rpcclient/mocks"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/usb"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	keystoremock "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/mocks"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/software"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
@@ -282,6 +283,7 @@ func newBackend(t *testing.T, testing, regtest bool) *Backend {
 	for _, code := range []coinpkg.Code{
 		coinpkg.CodeBTC,
 		coinpkg.CodeTBTC,
+		coinpkg.CodeTSIG,
 		coinpkg.CodeRBTC,
 		coinpkg.CodeLTC,
 		coinpkg.CodeTLTC,
@@ -469,3 +471,90 @@ func TestRegisterKeystore(t *testing.T) {
 	require.Nil(t, b.Accounts().lookup("v0-66666666-ltc-0"))
 	require.NotNil(t, b.Accounts().lookup("v0-66666666-eth-0"))
 }
+
+func TestRegisterMultipleKeystores(t *testing.T) {
+	// From mnemonic: wisdom minute home employ west tail liquid mad deal catalog narrow mistake
+	ks1 := software.NewKeystore(test.TstMustXKey("xprv9s21ZrQH143K3gie3VFLgx8JcmqZNsBcBc6vAdJrsf4bPRhx69U8qZe3EYAyvRWyQdEfz7ZpyYtL8jW2d2Lfkfh6g2zivq8JdZPQqxoxLwB"))
+	// From mnemonic: lava scare swap mystery lawsuit army rubber clean mean bronze keen volcano
+	ks2 := software.NewKeystore(test.TstMustXKey("xprv9s21ZrQH143K3cfe2832UrUDA5jmFWvm3acoempvZofxin26VdqjosJfTjHsVgjgszDYHiEgepM7J7U9N7HpayNZDRPUoxGKQbJCuHzgnuy"))
+	rootFingerprint1, err := ks1.RootFingerprint()
+	require.NoError(t, err)
+	rootFingerprint2, err := ks2.RootFingerprint()
+	require.NoError(t, err)
+
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	b.registerDeviceKeystore("device1", ks1)
+	b.registerDeviceKeystore("device2", ks2)
+	require.Equal(t, ks1, b.Keystore())
+	require.Equal(t, []keystore.Keystore{ks1, ks2}, b.Keystores())
+	require.Equal(t, ks2, b.KeystoreByRootFingerprint(rootFingerprint2))
+	require.Nil(t, b.KeystoreByRootFingerprint([]byte{1, 2, 3, 4}))
+
+	// The accounts of both keystores are loaded. The hidden accounts for the accounts discovery are
+	// added in the background, so they are not counted.
+	countAccounts := func(rootFingerprint []byte) int {
+		count := 0
+		for _, account := range b.Accounts() {
+			accountConfig := account.Config().Config
+			if !accountConfig.HiddenBecauseUnused &&
+				accountConfig.SigningConfigurations.ContainsRootFingerprint(rootFingerprint) {
+				count++
+			}
+		}
+		return count
+	}
+	// BTC and LTC, the software keystore does not support ETH.
+	require.Equal(t, 2, countAccounts(rootFingerprint1))
+	require.Equal(t, 2, countAccounts(rootFingerprint2))
+
+	// Registering the same keystore again does not add it twice.
+	b.registerKeystore(ks2)
+	require.Len(t, b.Keystores(), 2)
+
+	// Unplugging one device only removes its keystore and its accounts.
+	b.deregisterDeviceKeystore("device1")
+	require.Equal(t, []keystore.Keystore{ks2}, b.Keystores())
+	require.Equal(t, ks2, b.Keystore())
+	require.Equal(t, 0, countAccounts(rootFingerprint1))
+	require.Equal(t, 2, countAccounts(rootFingerprint2))
+
+	b.deregisterDeviceKeystore("device2")
+	require.Empty(t, b.Keystores())
+	require.Nil(t, b.Keystore())
+	require.Empty(t, b.Accounts())
+}
+
+func TestRegtestElectrumServers(t *testing.T) {
+	b := newBackend(t, testnetEnabled, regtestEnabled)
+	defer b.Close()
+	require.Equal(t,
+		[]*config.ServerInfo{
+			{Server: "127.0.0.1:52001", TLS: false, PEMCert: ""},
+			{Server: "127.0.0.1:52002", TLS: false, PEMCert: ""},
+		},
+		b.defaultElectrumXServers(coinpkg.CodeRBTC))
+
+	b.arguments.SetRegtestElectrumServers([]string{"127.0.0.1:60401"})
+	require.Equal(t,
+		[]*config.ServerInfo{{Server: "127.0.0.1:60401", TLS: false, PEMCert: ""}},
+		b.defaultElectrumXServers(coinpkg.CodeRBTC))
+}
+
+func TestTestSimulator(t *testing.T) {
+	b := newBackend(t, testnetEnabled, regtestDisabled)
+	defer b.Close()
+	require.Error(t, b.UnlockTestSimulator("1234"))
+
+	b.CreateTestSimulator("1234")
+	require.Error(t, b.UnlockTestSimulator("0000"))
+	require.Nil(t, b.Keystore())
+	require.NoError(t, b.UnlockTestSimulator("1234"))
+	require.Equal(t, b.TestSimulator(), b.Keystore())
+
+	// Creating a new simulator disconnects the previous one.
+	b.CreateTestSimulator("5678")
+	require.Nil(t, b.Keystore())
+	require.False(t, b.TestSimulator().Status().Unlocked)
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
@@ -111,6 +112,7 @@ func (banners *Banners) init(httpClient *http.Client) error {
 
 // Init fetches the remote banners info. Should be called in a go-routine to be non-blocking.
 func (banners *Banners) Init(httpClient *http.Client) {
+	defer crashreport.Recover()
 	if err := banners.init(httpClient); err != nil {
 		banners.log.WithError(err).Warn("Check for banners failed.")
 	}
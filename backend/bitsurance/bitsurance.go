@@ -15,6 +15,7 @@
 package bitsurance
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"math/big"
@@ -75,14 +76,14 @@ type AccountDetails struct {
 // bitsuranceCheckId fetches and returns the account details of the passed accountId from the
 // Bitsurance server.
 // If an accountId is not retrieved at all, the endpoint return a 404 http code.
-func bitsuranceCheckId(devServer bool, httpClient *http.Client, accountId string) (AccountDetails, error) {
+func bitsuranceCheckId(ctx context.Context, devServer bool, httpClient *http.Client, accountId string) (AccountDetails, error) {
 	url := apiURL
 	if devServer {
 		url = testApiURL
 	}
 	endpoint := url + "accountDetails/" + accountId
 	account := AccountDetails{}
-	code, err := util.APIGet(httpClient, endpoint, apiKey, 1024, &account)
+	code, err := util.APIGet(ctx, httpClient, endpoint, apiKey, 1024, &account)
 	if err != nil && code != http.StatusNotFound {
 		return account, err
 	}
@@ -116,7 +117,7 @@ func WidgetURL(devServer bool, lang string) string {
 
 // LookupBitsuranceAccounts takes in input a slice of accounts. For each account, it interrogates the
 // Bitsurance server and returns a map with the given accounts' codes as keys and the insurance details as value.
-func LookupBitsuranceAccounts(devServer bool, accounts []accounts.Interface, httpClient *http.Client) ([]AccountDetails, error) {
+func LookupBitsuranceAccounts(ctx context.Context, devServer bool, accounts []accounts.Interface, httpClient *http.Client) ([]AccountDetails, error) {
 	insuredAccounts := []AccountDetails{}
 
 	for _, account := range accounts {
@@ -125,7 +126,7 @@ func LookupBitsuranceAccounts(devServer bool, accounts []accounts.Interface, htt
 			return nil, err
 		}
 
-		bitsuranceAccount, err := bitsuranceCheckId(devServer, httpClient, bitsuranceId)
+		bitsuranceAccount, err := bitsuranceCheckId(ctx, devServer, httpClient, bitsuranceId)
 		if err != nil {
 			return nil, err
 		}
@@ -16,13 +16,16 @@
 package btc
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
 	"os"
 	"path"
 	"sort"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
@@ -36,8 +39,8 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/ltc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
-	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/util"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
@@ -84,6 +87,9 @@ type Account struct {
 	*accounts.BaseAccount
 
 	coin *Coin
+	// blockchain is the connection to the Electrum servers configured for this account. If nil,
+	// the blockchain backend of the coin is used. See Blockchain().
+	blockchain blockchain.Interface
 	// folder for this specific account. It is a subfolder of dbFolder. Full path.
 	dbSubfolder    string
 	db             transactions.DBInterface
@@ -99,6 +105,9 @@ type Account struct {
 	// need an accurate count of addresses synced, this should probably be turned into a map (set)
 	// instead.
 	syncedAddressesCount uint32
+	// fetchedHistoriesCount is the number of address histories downloaded since the account was
+	// initialized or reconnected, i.e. of addresses which changed since they were last synced.
+	fetchedHistoriesCount uint32
 
 	transactions *transactions.Transactions
 
@@ -106,6 +115,11 @@ type Account struct {
 	activeTxProposal     *maketx.TxProposal
 	activeTxProposalLock locker.Locker
 
+	// reportedDustOutputs are the possible dusting attack coins already reported with a
+	// dustOutputs event, see NewDustOutputs().
+	reportedDustOutputs     map[wire.OutPoint]struct{}
+	reportedDustOutputsLock locker.Locker
+
 	// Access this only via getMinRelayFeeRate(). sat/kB.
 	minRelayFeeRate     *btcutil.Amount
 	minRelayFeeRateLock locker.Locker
@@ -154,6 +168,15 @@ func (account *Account) String() string {
 	return fmt.Sprintf("%s-%s", account.Coin().Code(), account.Config().Config.Code)
 }
 
+// Blockchain returns the blockchain backend used by this account, which is the one of the coin
+// unless the account is configured to use its own Electrum servers.
+func (account *Account) Blockchain() blockchain.Interface {
+	if account.blockchain != nil {
+		return account.blockchain
+	}
+	return account.coin.Blockchain()
+}
+
 // FilesFolder implements accounts.Interface.
 func (account *Account) FilesFolder() string {
 	if account.dbSubfolder == "" {
@@ -219,6 +242,10 @@ func (account *Account) gapLimits(
 			}
 			limits.Receive = defaultLimits.Receive
 		}
+		if configured := account.Config().Config.GapLimit; configured > 0 {
+			limits.Receive = max(limits.Receive, configured)
+			limits.Change = max(limits.Change, configured)
+		}
 		if limits.Receive > maxGapLimit {
 			if account.forceGapLimits != nil { // log only when it's interesting
 				account.log.Infof("receive gap limit decreased to maximum of %d", maxGapLimit)
@@ -247,14 +274,14 @@ func (account *Account) gapLimits(
 // getMinRelayFeeRate fetches the min relay fee from the server and returns it. The value is cached
 // so that subsequent calls are instant. This is important as this function can be called many times
 // in succession when validating tx proposals.
-func (account *Account) getMinRelayFeeRate() (btcutil.Amount, error) {
+func (account *Account) getMinRelayFeeRate(ctx context.Context) (btcutil.Amount, error) {
 	defer account.minRelayFeeRateLock.Lock()()
 	cached := account.minRelayFeeRate
 	if cached != nil {
 		return *cached, nil
 	}
 
-	feeRate, err := account.coin.Blockchain().RelayFee()
+	feeRate, err := account.Blockchain().RelayFee(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -301,12 +328,13 @@ func (account *Account) Initialize() error {
 
 	dbName := fmt.Sprintf("%s.db", accountIdentifier)
 	account.log.Debugf("Opening the database '%s' to persist the transactions.", dbName)
-	db, err := transactionsdb.NewDB(path.Join(account.Config().DBFolder, dbName))
+	db, err := transactionsdb.NewDB(account.dbFilename())
 	if err != nil {
 		return err
 	}
 	account.db = db
 	account.log.Debugf("Opened the database '%s' to persist the transactions.", dbName)
+	account.SetOnSyncDone(account.persistLastSynced)
 
 	onConnectionStatusChanged := func(err error) {
 		if err != nil {
@@ -316,23 +344,36 @@ func (account *Account) Initialize() error {
 			// when we have previously been offline, the initial sync status is set back
 			// as we need to synchronize with the new backend.
 			account.ResetSynced()
+			atomic.StoreUint32(&account.syncedAddressesCount, 0)
+			atomic.StoreUint32(&account.fetchedHistoriesCount, 0)
 			account.SetOffline(nil)
 			account.minRelayFeeRate = nil
 			account.log.Debug("Connection to blockchain backend established")
 		}
 	}
 	account.coin.Initialize()
-	account.SetOffline(account.coin.Blockchain().ConnectionError())
-	account.coin.Blockchain().RegisterOnConnectionErrorChangedEvent(onConnectionStatusChanged)
+	if servers := account.Config().Config.ElectrumServers; len(servers) > 0 {
+		account.log.Info("Using the Electrum servers configured for the account")
+		account.blockchain = account.coin.NewElectrumConnection(servers, account.log)
+	}
+	account.SetOffline(account.Blockchain().ConnectionError())
+	account.Blockchain().RegisterOnConnectionErrorChangedEvent(onConnectionStatusChanged)
 	theHeaders := account.coin.Headers()
 	theHeaders.SubscribeEvent(func(event headers.Event) {
-		if event == headers.EventSynced {
+		switch event {
+		case headers.EventSynced:
 			account.Config().OnEvent(accountsTypes.EventHeadersSynced)
+		case headers.EventReorg:
+			account.Notify(observable.Event{
+				Subject: fmt.Sprintf("account/%s/reorg", account.Config().Config.Code),
+				Action:  action.Replace,
+				Object:  theHeaders.LastReorg(),
+			})
 		}
 	})
 	account.transactions = transactions.NewTransactions(
 		account.coin.Net(), account.db, theHeaders, account.Synchronizer,
-		account.coin.Blockchain(), account.notifier, account.log)
+		account.Blockchain(), account.notifier, account.log)
 
 	for _, signingConfiguration := range signingConfigurations {
 
@@ -352,11 +393,73 @@ func (account *Account) Initialize() error {
 		account.subaccounts = append(account.subaccounts, subacc)
 	}
 
-	go account.ensureAddresses()
+	go func() {
+		defer crashreport.Recover()
+		account.ensureAddresses()
+	}()
 
 	return account.BaseAccount.Initialize(accountIdentifier)
 }
 
+// persistLastSynced stores the current time as the time the account was last fully synced.
+func (account *Account) persistLastSynced() {
+	if account.isClosed() {
+		return
+	}
+	err := transactions.DBUpdate(account.db, func(dbTx transactions.DBTxInterface) error {
+		return dbTx.PutLastSynced(time.Now())
+	})
+	if err != nil {
+		account.log.WithError(err).Error("could not persist the last sync time")
+	}
+}
+
+// LastSynced returns the time the account was last fully synced, which can be in a previous
+// session, or nil if it was never synced. The transactions and balance at that time are persisted,
+// so they can be shown before the account is synced again.
+func (account *Account) LastSynced() (*time.Time, error) {
+	if !account.isInitialized() {
+		return nil, errp.New("account not initialized")
+	}
+	return transactions.DBView(account.db, func(dbTx transactions.DBTxInterface) (*time.Time, error) {
+		return dbTx.LastSynced()
+	})
+}
+
+// ScanFurther extends the gap limits of the receive and change addresses by `extra` addresses and
+// scans the new addresses, e.g. to find funds of an imported wallet which left a larger gap between
+// used addresses than the gap limit. The extension lasts until the account is initialized again. To
+// keep a higher gap limit, configure the `GapLimit` of the account.
+func (account *Account) ScanFurther(extra int) error {
+	if !account.isInitialized() {
+		return errp.New("account must be initialized")
+	}
+	if extra <= 0 {
+		return errp.New("the number of addresses to scan must be positive")
+	}
+	defer account.Synchronizer.IncRequestsCounter()()
+	for _, subacc := range account.subaccounts {
+		for _, addressChain := range []*addresses.AddressChain{subacc.receiveAddresses, subacc.changeAddresses} {
+			if addressChain.GapLimit()+extra > maxGapLimit {
+				return errp.Newf("the gap limit cannot exceed %d", maxGapLimit)
+			}
+		}
+	}
+	for _, subacc := range account.subaccounts {
+		for _, addressChain := range []*addresses.AddressChain{subacc.receiveAddresses, subacc.changeAddresses} {
+			newAddresses, err := addressChain.ExtendGapLimit(extra)
+			if err != nil {
+				return err
+			}
+			account.log.Infof("scanning %d more addresses", len(newAddresses))
+			for _, address := range newAddresses {
+				account.subscribeAddress(address)
+			}
+		}
+	}
+	return nil
+}
+
 // XPubVersionForScriptType returns the xpub version bytes for the given coin and script type.
 func XPubVersionForScriptType(coin *Coin, scriptType signing.ScriptType) [4]byte {
 	switch coin.Net().Net {
@@ -396,6 +499,11 @@ func (account *Account) Info() *accounts.Info {
 		if isInsuredAccount && !isNativeSegwit {
 			continue
 		}
+		if subacc.signingConfiguration.BitcoinMultisig != nil {
+			// The cosigner xpubs are shown as they were provided.
+			signingConfigurations = append(signingConfigurations, subacc.signingConfiguration)
+			continue
+		}
 		xpub := subacc.signingConfiguration.ExtendedPublicKey()
 		if xpub.IsPrivate() {
 			panic("xpub can't be private")
@@ -444,6 +552,10 @@ func (account *Account) Close() {
 		account.transactions.Close()
 	}
 
+	if account.blockchain != nil {
+		account.blockchain.Close()
+	}
+
 	if account.db != nil {
 		if err := account.db.Close(); err != nil {
 			account.log.WithError(err).Error("couldn't close db")
@@ -460,26 +572,39 @@ func (account *Account) isClosed() bool {
 	return account.closed
 }
 
+// dbFilename returns the path of the database persisting the transactions and address histories.
+func (account *Account) dbFilename() string {
+	return path.Join(account.Config().DBFolder, fmt.Sprintf("account-%s.db", account.Config().Config.Code))
+}
+
+// DeleteDB removes the database of the closed account, so the transactions and address histories
+// are fetched again from scratch the next time the account is loaded.
+func (account *Account) DeleteDB() error {
+	if !account.isClosed() {
+		return errp.New("the account must be closed before deleting its database")
+	}
+	if err := os.Remove(account.dbFilename()); err != nil && !os.IsNotExist(err) {
+		return errp.WithStack(err)
+	}
+	return nil
+}
+
 // Notifier implements accounts.Interface.
 func (account *Account) Notifier() accounts.Notifier {
 	return account.notifier
 }
 
-// feeTargets fetches the available fees. For mainnet BTC it uses mempool.space estimation.
+// feeTargets fetches the available fees. For mainnet BTC it uses mempool.space estimation, falling
+// back to the other sources in feeEstimateSources.
 //
-// For the other coins or in case mempool.space is not available it fallbacks on Bitcoin Core.
+// For the other coins or in case none of them is available it fallbacks on Bitcoin Core.
 // The minimum relay fee is used as a last resource fallback in case also Bitcoin Core is
 // unavailable.
-func (account *Account) feeTargets() FeeTargets {
+func (account *Account) feeTargets(ctx context.Context) FeeTargets {
 	// for mainnet BTC we fetch mempool.space fees, as they should be more reliable.
 	var mempoolFees *accounts.MempoolSpaceFees
 	if account.coin.Code() == coin.CodeBTC {
-		mempoolFees = &accounts.MempoolSpaceFees{}
-		_, err := util.APIGet(account.httpClient, mempoolSpaceMirror, "", 1000, mempoolFees)
-		if err != nil {
-			mempoolFees = nil
-			account.log.WithError(err).Errorf("Fetching fees from %s failed", mempoolSpaceMirror)
-		}
+		mempoolFees = account.fetchMempoolFees(ctx)
 	}
 
 	// feeTargets must be sorted by ascending priority.
@@ -500,7 +625,7 @@ func (account *Account) feeTargets() FeeTargets {
 	}
 
 	var minRelayFeeRate *btcutil.Amount
-	minRelayFeeRateVal, err := account.getMinRelayFeeRate()
+	minRelayFeeRateVal, err := account.getMinRelayFeeRate(ctx)
 	if err == nil {
 		minRelayFeeRate = &minRelayFeeRateVal
 	}
@@ -513,7 +638,7 @@ func (account *Account) feeTargets() FeeTargets {
 		} else {
 			// If mempool.space fees are not available, we fallback on Bitcoin Core estimation.
 			// If even that one is not available, we just offer the min relay fee.
-			feeRatePerKb, err = account.coin.Blockchain().EstimateFee(feeTarget.blocks)
+			feeRatePerKb, err = account.Blockchain().EstimateFee(ctx, feeTarget.blocks)
 			if err != nil {
 				if account.coin.Code() != coin.CodeTLTC {
 					account.log.WithField("fee-target", feeTarget.blocks).
@@ -542,9 +667,9 @@ func (account *Account) feeTargets() FeeTargets {
 }
 
 // FeeTargets returns the fee targets and the default fee target.
-func (account *Account) FeeTargets() ([]accounts.FeeTarget, accounts.FeeTargetCode) {
+func (account *Account) FeeTargets(ctx context.Context) ([]accounts.FeeTarget, accounts.FeeTargetCode) {
 	// Return only fee targets with a valid fee rate (drop if fee could not be estimated).
-	fetchedFeeTargets := account.feeTargets()
+	fetchedFeeTargets := account.feeTargets(ctx)
 	feeTargets := []accounts.FeeTarget{}
 	defaultFee := accounts.FeeTargetCodeCustom
 
@@ -581,7 +706,21 @@ func (account *Account) Balance() (*accounts.Balance, error) {
 		// TODO
 		panic(err)
 	}
-	return balance, nil
+	utxos, err := account.transactions.SpendableOutputs()
+	if err != nil {
+		return nil, err
+	}
+	var frozen int64
+	for outPoint, txOut := range utxos {
+		if account.UTXOFrozen(outPoint.String()) {
+			frozen += txOut.Value
+		}
+	}
+	if frozen == 0 {
+		return balance, nil
+	}
+	return accounts.NewBalanceWithFrozen(
+		balance.Available(), balance.Incoming(), coin.NewAmountFromInt64(frozen)), nil
 }
 
 func (account *Account) incAndEmitSyncCounter() {
@@ -592,6 +731,11 @@ func (account *Account) incAndEmitSyncCounter() {
 			Action:  action.Replace,
 			Object:  synced,
 		})
+		account.Notify(observable.Event{
+			Subject: fmt.Sprintf("account/%s/sync-progress", account.Config().Config.Code),
+			Action:  action.Replace,
+			Object:  account.SyncProgress(),
+		})
 	}
 }
 
@@ -637,7 +781,8 @@ func (account *Account) onAddressStatus(address *addresses.AccountAddress, statu
 	account.log.Debug("Address status changed, fetching history.")
 
 	defer account.Synchronizer.IncRequestsCounter()()
-	history, err := account.coin.Blockchain().ScriptHashGetHistory(address.PubkeyScriptHashHex())
+	history, err := account.Blockchain().ScriptHashGetHistory(
+		context.Background(), address.PubkeyScriptHashHex())
 	if err != nil {
 		// We are not closing client.blockchain here, as it is reused per coin with
 		// different accounts.
@@ -652,6 +797,7 @@ func (account *Account) onAddressStatus(address *addresses.AccountAddress, statu
 	}
 
 	account.transactions.UpdateAddressHistory(address.PubkeyScriptHashHex(), history)
+	atomic.AddUint32(&account.fetchedHistoriesCount, 1)
 	account.incAndEmitSyncCounter()
 	account.ensureAddresses()
 }
@@ -689,11 +835,14 @@ func (account *Account) ensureAddresses() {
 }
 
 func (account *Account) subscribeAddress(address *addresses.AccountAddress) {
-	account.coin.Blockchain().ScriptHashSubscribe(
+	account.Blockchain().ScriptHashSubscribe(
 		account.Synchronizer.IncRequestsCounter,
 		address.PubkeyScriptHashHex(),
 		func(status string) {
-			go account.onAddressStatus(address, status)
+			go func() {
+				defer crashreport.Recover()
+				account.onAddressStatus(address, status)
+			}()
 		},
 	)
 }
@@ -709,6 +858,33 @@ func (account *Account) Transactions() (accounts.OrderedTransactions, error) {
 	return account.transactions.Transactions(account.IsChange)
 }
 
+// NewDroppedTransactions returns the unconfirmed transactions which were dropped from the mempool
+// or double-spent and which were not returned by a previous call.
+func (account *Account) NewDroppedTransactions() ([]*accounts.TransactionData, error) {
+	if !account.isInitialized() {
+		return nil, errp.New("account not initialized")
+	}
+	txHashes, err := account.transactions.MarkDroppedTransactionsNotified()
+	if err != nil || len(txHashes) == 0 {
+		return nil, err
+	}
+	txs, err := account.transactions.Transactions(account.IsChange)
+	if err != nil {
+		return nil, err
+	}
+	txIDs := map[string]struct{}{}
+	for _, txHash := range txHashes {
+		txIDs[txHash.String()] = struct{}{}
+	}
+	result := []*accounts.TransactionData{}
+	for _, tx := range txs {
+		if _, ok := txIDs[tx.TxID]; ok && tx.Status.Dropped() {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
 // GetUnusedReceiveAddresses returns a number of unused addresses. Returns nil if the account is not initialized.
 func (account *Account) GetUnusedReceiveAddresses() []accounts.AddressList {
 	if !account.isInitialized() {
@@ -747,6 +923,39 @@ func (account *Account) GetUnusedReceiveAddresses() []accounts.AddressList {
 // VerifyAddress verifies a receive address on a keystore. Returns false, nil if no secure output
 // exists.
 func (account *Account) VerifyAddress(addressID string) (bool, error) {
+	return account.verifyReceiveAddress(func(receiveAddresses *addresses.AddressChain) *addresses.AccountAddress {
+		return receiveAddresses.LookupByScriptHashHex(blockchain.ScriptHashHex(addressID))
+	})
+}
+
+// VerifyReceiveAddress verifies any previously derived receive address on a keystore, not only the
+// unused ones offered by GetUnusedReceiveAddresses(), so the user can check an address they handed
+// out earlier. The address is identified by its encoding, e.g. `bc1q...`, or by its absolute
+// keypath, e.g. `m/84'/0'/0'/0/5`. Returns false, nil if no secure output exists.
+func (account *Account) VerifyReceiveAddress(addressOrKeypath string) (bool, error) {
+	addressOrKeypath = strings.TrimSpace(addressOrKeypath)
+	if strings.HasPrefix(addressOrKeypath, "m/") {
+		keypath, err := signing.NewAbsoluteKeypath(addressOrKeypath)
+		if err != nil {
+			return false, err
+		}
+		return account.verifyReceiveAddress(func(receiveAddresses *addresses.AddressChain) *addresses.AccountAddress {
+			return receiveAddresses.LookupByKeypath(keypath)
+		})
+	}
+	pkScript, err := account.coin.AddressToPkScript(addressOrKeypath)
+	if err != nil {
+		return false, err
+	}
+	return account.verifyReceiveAddress(func(receiveAddresses *addresses.AddressChain) *addresses.AccountAddress {
+		return receiveAddresses.LookupByScriptHashHex(blockchain.NewScriptHashHex(pkScript))
+	})
+}
+
+// verifyReceiveAddress verifies the first address found by lookup in the receive address chains of
+// the subaccounts.
+func (account *Account) verifyReceiveAddress(
+	lookup func(*addresses.AddressChain) *addresses.AccountAddress) (bool, error) {
 	if !account.isInitialized() {
 		return false, errp.New("account must be initialized")
 	}
@@ -757,10 +966,9 @@ func (account *Account) VerifyAddress(addressID string) (bool, error) {
 		return false, err
 	}
 
-	scriptHashHex := blockchain.ScriptHashHex(addressID)
 	var address *addresses.AccountAddress
 	for _, subacc := range account.subaccounts {
-		if addr := subacc.receiveAddresses.LookupByScriptHashHex(scriptHashHex); addr != nil {
+		if addr := lookup(subacc.receiveAddresses); addr != nil {
 			address = addr
 			break
 		}
@@ -843,6 +1051,11 @@ type SpendableOutput struct {
 	OutPoint wire.OutPoint
 	Address  *addresses.AccountAddress
 	IsChange bool
+	// IsDust is true if the coin looks like a dusting attack, see isDustingOutput().
+	IsDust bool
+	// Frozen is true if the user marked the coin as "do not spend". Frozen coins are not used in
+	// new transactions.
+	Frozen bool
 }
 
 // SpendableOutputs returns the utxo set, sorted by the value descending.
@@ -854,16 +1067,18 @@ func (account *Account) SpendableOutputs() []*SpendableOutput {
 		// TODO
 		panic(err)
 	}
+	dustThreshold := account.dustThreshold()
 	for outPoint, txOut := range utxos {
 		scriptHashHex := blockchain.NewScriptHashHex(txOut.TxOut.PkScript)
-		result = append(
-			result,
-			&SpendableOutput{
-				OutPoint:        outPoint,
-				SpendableOutput: txOut,
-				Address:         account.getAddress(scriptHashHex),
-				IsChange:        account.IsChange(scriptHashHex),
-			})
+		output := &SpendableOutput{
+			OutPoint:        outPoint,
+			SpendableOutput: txOut,
+			Address:         account.getAddress(scriptHashHex),
+			IsChange:        account.IsChange(scriptHashHex),
+			Frozen:          account.UTXOFrozen(outPoint.String()),
+		}
+		output.IsDust = account.isDust(output, dustThreshold)
+		result = append(result, output)
 	}
 	return sortByAddresses(result)
 }
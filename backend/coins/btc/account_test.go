@@ -25,8 +25,10 @@ import (
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	blockchainMock "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain/mocks"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/electrum"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
@@ -93,6 +95,7 @@ func mockAccount(t *testing.T, accountConfig *config.Account) *Account {
 		&accounts.AccountConfig{
 			Config:          accountConfig,
 			DBFolder:        dbFolder,
+			NotesFolder:     test.TstTempDir("btc-notesfolder"),
 			OnEvent:         func(accountsTypes.Event) {},
 			RateUpdater:     nil,
 			GetNotifier:     func(signing.Configurations) accounts.Notifier { return nil },
@@ -113,6 +116,16 @@ func TestAccount(t *testing.T) {
 	require.NoError(t, account.Initialize())
 	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
 
+	lastSynced, err := account.LastSynced()
+	require.NoError(t, err)
+	require.NotNil(t, lastSynced)
+	require.WithinDuration(t, time.Now(), *lastSynced, time.Minute)
+
+	// The default gap limits of a P2WPKH account are 20 receive and 6 change addresses.
+	progress := account.SyncProgress()
+	require.Equal(t, 26, progress.TotalAddresses)
+	require.Equal(t, 100, progress.Percent)
+
 	balance, err := account.Balance()
 	require.NoError(t, err)
 	require.Equal(t, big.NewInt(0), balance.Available().BigInt())
@@ -125,6 +138,23 @@ func TestAccount(t *testing.T) {
 	require.Equal(t, []*SpendableOutput{}, account.SpendableOutputs())
 }
 
+func TestAccountElectrumServers(t *testing.T) {
+	account := mockAccount(t, nil)
+	require.NoError(t, account.Initialize())
+	require.Equal(t, account.coin.Blockchain(), account.Blockchain())
+	account.Close()
+
+	accountConfig := *account.Config().Config
+	accountConfig.ElectrumServers = []*config.ServerInfo{{Server: "127.0.0.1:1"}}
+	account = mockAccount(t, &accountConfig)
+	require.NoError(t, account.Initialize())
+	defer account.Close()
+	require.NotEqual(t, account.coin.Blockchain(), account.Blockchain())
+	reporter, ok := account.Blockchain().(electrum.StatusReporter)
+	require.True(t, ok)
+	require.Equal(t, []string{"127.0.0.1:1"}, reporter.Status().Servers)
+}
+
 func TestInsuredAccountAddresses(t *testing.T) {
 	net := &chaincfg.TestNet3Params
 
@@ -222,3 +252,84 @@ func TestIsChange(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyReceiveAddress(t *testing.T) {
+	account := mockAccount(t, nil)
+	var verified *signing.Configuration
+	account.Config().ConnectKeystore = func() (keystore.Keystore, error) {
+		return &keystoremock.KeystoreMock{
+			CanVerifyAddressFunc: func(coin.Coin) (bool, bool, error) { return true, false, nil },
+			VerifyAddressFunc: func(configuration *signing.Configuration, _ coin.Coin) error {
+				verified = configuration
+				return nil
+			},
+		}, nil
+	}
+	require.NoError(t, account.Initialize())
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+
+	address := account.GetUnusedReceiveAddresses()[0].Addresses[5].(*addresses.AccountAddress)
+
+	canVerify, err := account.VerifyReceiveAddress(address.EncodeForHumans())
+	require.NoError(t, err)
+	require.True(t, canVerify)
+	require.Equal(t, address.Configuration, verified)
+
+	verified = nil
+	canVerify, err = account.VerifyReceiveAddress("m/84'/1'/0'/0/5")
+	require.NoError(t, err)
+	require.True(t, canVerify)
+	require.Equal(t, address.Configuration, verified)
+
+	// Change addresses are not receive addresses.
+	_, err = account.VerifyReceiveAddress("m/84'/1'/0'/1/5")
+	require.Error(t, err)
+	// Not derived.
+	_, err = account.VerifyReceiveAddress("m/84'/1'/0'/0/1000")
+	require.Error(t, err)
+	_, err = account.VerifyReceiveAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx")
+	require.Error(t, err)
+	_, err = account.VerifyReceiveAddress("invalid")
+	require.Error(t, err)
+}
+
+func TestGapLimit(t *testing.T) {
+	account := mockAccount(t, nil)
+	require.Error(t, account.ScanFurther(10))
+	require.NoError(t, account.Initialize())
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+	receiveAddresses := account.subaccounts[0].receiveAddresses
+	changeAddresses := account.subaccounts[0].changeAddresses
+	require.Equal(t, 20, receiveAddresses.GapLimit())
+	require.Equal(t, 6, changeAddresses.GapLimit())
+
+	require.NoError(t, account.ScanFurther(100))
+	require.Equal(t, 120, receiveAddresses.GapLimit())
+	require.Equal(t, 106, changeAddresses.GapLimit())
+	unusedAddresses, err := receiveAddresses.GetUnused()
+	require.NoError(t, err)
+	require.Len(t, unusedAddresses, 120)
+	require.Error(t, account.ScanFurther(0))
+	require.Error(t, account.ScanFurther(maxGapLimit))
+
+	// Configured per account.
+	accountConfig := *account.Config().Config
+	accountConfig.GapLimit = 50
+	account = mockAccount(t, &accountConfig)
+	require.NoError(t, account.Initialize())
+	require.Equal(t, 50, account.subaccounts[0].receiveAddresses.GapLimit())
+	require.Equal(t, 50, account.subaccounts[0].changeAddresses.GapLimit())
+}
+
+func TestDeleteDB(t *testing.T) {
+	account := mockAccount(t, nil)
+	require.NoError(t, account.Initialize())
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+	require.FileExists(t, account.dbFilename())
+	require.Error(t, account.DeleteDB())
+	account.Close()
+	require.NoError(t, account.DeleteDB())
+	require.NoFileExists(t, account.dbFilename())
+	// Nothing to delete.
+	require.NoError(t, account.DeleteDB())
+}
@@ -15,7 +15,10 @@
 package addresses
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"sort"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
@@ -42,6 +45,8 @@ type AccountAddress struct {
 
 	// redeemScript stores the redeem script of a BIP16 P2SH output or nil if address type is P2PKH.
 	redeemScript []byte
+	// witnessScript stores the witness script of a P2WSH output or nil for other address types.
+	witnessScript []byte
 
 	log *logrus.Entry
 }
@@ -56,6 +61,7 @@ func NewAccountAddress(
 
 	var address btcutil.Address
 	var redeemScript []byte
+	var witnessScript []byte
 	configuration, err := accountConfiguration.Derive(keyPath)
 	if err != nil {
 		log.WithError(err).Panic("Failed to derive the configuration.")
@@ -98,6 +104,16 @@ func NewAccountAddress(
 		if err != nil {
 			log.WithError(err).Panic("Failed to get p2tr addr")
 		}
+	case signing.ScriptTypeP2WSH:
+		witnessScript, err = multisigScript(configuration, net)
+		if err != nil {
+			log.WithError(err).Panic("Failed to get the multisig witness script.")
+		}
+		witnessScriptHash := sha256.Sum256(witnessScript)
+		address, err = btcutil.NewAddressWitnessScriptHash(witnessScriptHash[:], net)
+		if err != nil {
+			log.WithError(err).Panic("Failed to get p2wsh addr. from witness script.")
+		}
 	default:
 		log.Panic(fmt.Sprintf("Unrecognized script type: %s", configuration.ScriptType()))
 	}
@@ -107,10 +123,40 @@ func NewAccountAddress(
 		AccountConfiguration: accountConfiguration,
 		Configuration:        configuration,
 		redeemScript:         redeemScript,
+		witnessScript:        witnessScript,
 		log:                  log,
 	}
 }
 
+// multisigScript returns the OP_CHECKMULTISIG script of a multisig configuration, with the
+// public keys sorted lexicographically as specified in BIP-67.
+func multisigScript(configuration *signing.Configuration, net *chaincfg.Params) ([]byte, error) {
+	publicKeys, err := configuration.CosignerPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	sortedPublicKeys := make([][]byte, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		sortedPublicKeys[i] = publicKey.SerializeCompressed()
+	}
+	sort.Slice(sortedPublicKeys, func(i, j int) bool {
+		return bytes.Compare(sortedPublicKeys[i], sortedPublicKeys[j]) < 0
+	})
+	addressPublicKeys := make([]*btcutil.AddressPubKey, len(sortedPublicKeys))
+	for i, publicKey := range sortedPublicKeys {
+		addressPublicKeys[i], err = btcutil.NewAddressPubKey(publicKey, net)
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+	}
+	script, err := txscript.MultiSigScript(
+		addressPublicKeys, int(configuration.BitcoinMultisig.Threshold))
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return script, nil
+}
+
 // ID implements accounts.Address.
 func (address *AccountAddress) ID() string {
 	return string(address.PubkeyScriptHashHex())
@@ -169,6 +215,8 @@ func (address *AccountAddress) ScriptForHashToSign() (bool, []byte) {
 		return true, address.redeemScript
 	case signing.ScriptTypeP2WPKH:
 		return true, address.PubkeyScript()
+	case signing.ScriptTypeP2WSH:
+		return true, address.witnessScript
 	default:
 		address.log.Panic("Unrecognized address type.")
 	}
@@ -222,3 +270,47 @@ func (address *AccountAddress) SignatureScript(
 	}
 	panic("The end of the function cannot be reached.")
 }
+
+// MultisigWitness returns the witness needed to spend from a P2WSH multisig address. The signatures
+// have to be provided in the order of the cosigners of the configuration (missing ones are nil).
+// At least as many signatures as the threshold of the configuration are required.
+func (address *AccountAddress) MultisigWitness(signatures []*types.Signature) (wire.TxWitness, error) {
+	multisig := address.Configuration.BitcoinMultisig
+	if multisig == nil {
+		return nil, errp.New("not a multisig address")
+	}
+	if len(signatures) != len(multisig.Cosigners) {
+		return nil, errp.New("expected one signature slot per cosigner")
+	}
+	publicKeys, err := address.Configuration.CosignerPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	type signatureWithPublicKey struct {
+		publicKey []byte
+		signature *types.Signature
+	}
+	var available []signatureWithPublicKey
+	for i, signature := range signatures {
+		if signature == nil {
+			continue
+		}
+		available = append(available, signatureWithPublicKey{
+			publicKey: publicKeys[i].SerializeCompressed(),
+			signature: signature,
+		})
+	}
+	if uint32(len(available)) < multisig.Threshold {
+		return nil, errp.Newf("need %d signatures, got %d", multisig.Threshold, len(available))
+	}
+	// OP_CHECKMULTISIG expects the signatures in the same order as the public keys in the script.
+	sort.Slice(available, func(i, j int) bool {
+		return bytes.Compare(available[i].publicKey, available[j].publicKey) < 0
+	})
+	// The first element is the dummy element consumed by OP_CHECKMULTISIG due to an off-by-one bug.
+	txWitness := wire.TxWitness{[]byte{}}
+	for _, entry := range available[:multisig.Threshold] {
+		txWitness = append(txWitness, append(entry.signature.SerializeDER(), byte(txscript.SigHashAll)))
+	}
+	return append(txWitness, address.witnessScript), nil
+}
@@ -15,18 +15,26 @@
 package addresses_test
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
+	"math/big"
 	"os"
 	"testing"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses/test"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
 	testlog "github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -116,3 +124,84 @@ func TestAddressP2TR(t *testing.T) {
 		require.Equal(t, test.expectedPkScript, hex.EncodeToString(addr.PubkeyScript()))
 	}
 }
+
+func TestAddressP2WSHMultisig(t *testing.T) {
+	keypath, err := signing.NewAbsoluteKeypath("m/48'/1'/0'/2'")
+	require.NoError(t, err)
+	relKeypath, err := signing.NewRelativeKeypath("0/0")
+	require.NoError(t, err)
+
+	// Three cosigners, each with their own seed.
+	var cosigners []signing.KeyInfo
+	var privateKeys []*btcec.PrivateKey
+	for i := byte(0); i < 3; i++ {
+		seed := make([]byte, 32)
+		seed[0] = i + 1
+		master, err := hdkeychain.NewMaster(seed, net)
+		require.NoError(t, err)
+		xprv, err := keypath.Derive(master)
+		require.NoError(t, err)
+		xpub, err := xprv.Neuter()
+		require.NoError(t, err)
+		cosigners = append(cosigners, signing.KeyInfo{
+			RootFingerprint:   []byte{1, 2, 3, i},
+			AbsoluteKeypath:   keypath,
+			ExtendedPublicKey: xpub,
+		})
+		addressXprv, err := relKeypath.Derive(xprv)
+		require.NoError(t, err)
+		privateKey, err := addressXprv.ECPrivKey()
+		require.NoError(t, err)
+		privateKeys = append(privateKeys, privateKey)
+	}
+
+	newAddress := func(cosigners []signing.KeyInfo) *addresses.AccountAddress {
+		configuration, err := signing.NewBitcoinMultisigConfiguration(
+			signing.ScriptTypeP2WSH, 2, cosigners, 0)
+		require.NoError(t, err)
+		return addresses.NewAccountAddress(
+			configuration, relKeypath, net, logging.Get().WithGroup("addresses_test"))
+	}
+	address := newAddress(cosigners)
+	_, ok := address.Address.(*btcutil.AddressWitnessScriptHash)
+	require.True(t, ok)
+	isSegwit, witnessScript := address.ScriptForHashToSign()
+	require.True(t, isSegwit)
+	witnessScriptHash := sha256.Sum256(witnessScript)
+	require.Equal(t, witnessScriptHash[:], address.ScriptAddress())
+
+	// The keys are sorted in the script, so the order of the cosigners does not matter.
+	require.Equal(t,
+		address.EncodeAddress(),
+		newAddress([]signing.KeyInfo{cosigners[2], cosigners[0], cosigners[1]}).EncodeAddress())
+
+	// Spend from the address with two of the three cosigners.
+	const value = 100000
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value-1000, address.PubkeyScript()))
+	prevOutputFetcher := txscript.NewCannedPrevOutputFetcher(address.PubkeyScript(), value)
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutputFetcher)
+	sigHash, err := txscript.CalcWitnessSigHash(
+		witnessScript, sigHashes, txscript.SigHashAll, tx, 0, value)
+	require.NoError(t, err)
+	sign := func(privateKey *btcec.PrivateKey) *types.Signature {
+		signature := ecdsa.SignCompact(privateKey, sigHash, true)
+		return &types.Signature{
+			R: new(big.Int).SetBytes(signature[1:33]),
+			S: new(big.Int).SetBytes(signature[33:]),
+		}
+	}
+
+	_, err = address.MultisigWitness([]*types.Signature{sign(privateKeys[0]), nil, nil})
+	require.Error(t, err)
+
+	witness, err := address.MultisigWitness(
+		[]*types.Signature{sign(privateKeys[0]), nil, sign(privateKeys[2])})
+	require.NoError(t, err)
+	tx.TxIn[0].Witness = witness
+	engine, err := txscript.NewEngine(address.PubkeyScript(), tx, 0,
+		txscript.StandardVerifyFlags, nil, sigHashes, value, prevOutputFetcher)
+	require.NoError(t, err)
+	require.NoError(t, engine.Execute())
+}
@@ -73,16 +73,42 @@ func (addresses *AddressChain) GetUnused() ([]*AccountAddress, error) {
 	return addresses.addresses[len(addresses.addresses)-unusedTailCount:], nil
 }
 
-// addAddress appends a new address at the end of the chain.
-func (addresses *AddressChain) addAddress() *AccountAddress {
-	addresses.log.Debug("Add new address to chain")
-	index := uint32(len(addresses.addresses))
-	address := NewAccountAddress(
+// PreviewUnused returns the next `count` unused addresses at the end of the chain. If the chain
+// has fewer unused addresses, the missing ones are derived without adding them to the chain, so
+// they are not watched until the gap limit reaches them. EnsureAddresses() must be called
+// beforehand.
+func (addresses *AddressChain) PreviewUnused(count int) ([]*AccountAddress, error) {
+	defer addresses.addressesLock.RLock()()
+	unusedTailCount, err := addresses.unusedTailCount()
+	if err != nil {
+		return nil, err
+	}
+	start := len(addresses.addresses) - unusedTailCount
+	result := []*AccountAddress{}
+	for index := start; index < start+count; index++ {
+		if index < len(addresses.addresses) {
+			result = append(result, addresses.addresses[index])
+		} else {
+			result = append(result, addresses.deriveAddress(uint32(index)))
+		}
+	}
+	return result, nil
+}
+
+// deriveAddress derives the address at the given index of the chain.
+func (addresses *AddressChain) deriveAddress(index uint32) *AccountAddress {
+	return NewAccountAddress(
 		addresses.accountConfiguration,
 		signing.NewEmptyRelativeKeypath().Child(addresses.chainIndex, signing.NonHardened).Child(index, signing.NonHardened),
 		addresses.net,
 		addresses.log,
 	)
+}
+
+// addAddress appends a new address at the end of the chain.
+func (addresses *AddressChain) addAddress() *AccountAddress {
+	addresses.log.Debug("Add new address to chain")
+	address := addresses.deriveAddress(uint32(len(addresses.addresses)))
 	addresses.addresses = append(addresses.addresses, address)
 	addresses.addressesLookup[address.PubkeyScriptHashHex()] = address
 	return address
@@ -112,10 +138,55 @@ func (addresses *AddressChain) LookupByScriptHashHex(hashHex blockchain.ScriptHa
 	return addresses.addressesLookup[hashHex]
 }
 
+// LookupByKeypath returns the address derived at the provided absolute keypath. Returns nil if not
+// found.
+func (addresses *AddressChain) LookupByKeypath(keypath signing.AbsoluteKeypath) *AccountAddress {
+	defer addresses.addressesLock.RLock()()
+	encoded := keypath.Encode()
+	for _, address := range addresses.addresses {
+		if address.AbsoluteKeypath().Encode() == encoded {
+			return address
+		}
+	}
+	return nil
+}
+
+// Addresses returns all addresses of the chain, in the order they were derived.
+func (addresses *AddressChain) Addresses() []*AccountAddress {
+	defer addresses.addressesLock.RLock()()
+	return append([]*AccountAddress{}, addresses.addresses...)
+}
+
+// Count returns the number of addresses in the chain.
+func (addresses *AddressChain) Count() int {
+	defer addresses.addressesLock.RLock()()
+	return len(addresses.addresses)
+}
+
+// GapLimit returns the number of unused addresses kept at the end of the chain.
+func (addresses *AddressChain) GapLimit() int {
+	defer addresses.addressesLock.RLock()()
+	return addresses.gapLimit
+}
+
+// ExtendGapLimit increases the gap limit by `extra` and appends the addresses needed to fill it,
+// returning the new addresses.
+func (addresses *AddressChain) ExtendGapLimit(extra int) ([]*AccountAddress, error) {
+	defer addresses.addressesLock.Lock()()
+	addresses.gapLimit += extra
+	addresses.log = addresses.log.WithField("gap-limit", addresses.gapLimit)
+	return addresses.ensureAddresses()
+}
+
 // EnsureAddresses appends addresses to the address chain until there are `gapLimit` unused
 // ones, and returns the new addresses.
 func (addresses *AddressChain) EnsureAddresses() ([]*AccountAddress, error) {
 	defer addresses.addressesLock.Lock()()
+	return addresses.ensureAddresses()
+}
+
+// ensureAddresses must be called with addressesLock held.
+func (addresses *AddressChain) ensureAddresses() ([]*AccountAddress, error) {
 	addedAddresses := []*AccountAddress{}
 	unusedAddressCount, err := addresses.unusedTailCount()
 	if err != nil {
@@ -111,6 +111,83 @@ func (s *addressChainTestSuite) TestLookupByScriptHashHex() {
 	s.Require().Nil(s.addresses.LookupByScriptHashHex(test.GetAddress(signing.ScriptTypeP2PKH).PubkeyScriptHashHex()))
 }
 
+func (s *addressChainTestSuite) TestLookupByKeypath() {
+	s.isAddressUsed = func(*addresses.AccountAddress) bool { return false }
+	newAddresses, err := s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	keypath, err := signing.NewAbsoluteKeypath("m/1/2")
+	s.Require().NoError(err)
+	s.Require().Equal(newAddresses[2], s.addresses.LookupByKeypath(keypath))
+	// Not derived yet.
+	keypath, err = signing.NewAbsoluteKeypath("m/1/6")
+	s.Require().NoError(err)
+	s.Require().Nil(s.addresses.LookupByKeypath(keypath))
+	// Other chain.
+	keypath, err = signing.NewAbsoluteKeypath("m/0/2")
+	s.Require().NoError(err)
+	s.Require().Nil(s.addresses.LookupByKeypath(keypath))
+}
+
+func (s *addressChainTestSuite) TestAddresses() {
+	s.isAddressUsed = func(*addresses.AccountAddress) bool { return false }
+	s.Require().Empty(s.addresses.Addresses())
+	newAddresses, err := s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	firstAddress := newAddresses[0]
+	s.isAddressUsed = func(addr *addresses.AccountAddress) bool {
+		return addr == firstAddress
+	}
+	moreAddresses, err := s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	// Used addresses are included.
+	s.Require().Equal(append(newAddresses, moreAddresses...), s.addresses.Addresses())
+}
+
+func (s *addressChainTestSuite) TestPreviewUnused() {
+	s.isAddressUsed = func(*addresses.AccountAddress) bool { return false }
+	newAddresses, err := s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	firstAddress := newAddresses[0]
+	s.isAddressUsed = func(addr *addresses.AccountAddress) bool {
+		return addr == firstAddress
+	}
+	_, err = s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	count := s.addresses.Count()
+
+	preview, err := s.addresses.PreviewUnused(2)
+	s.Require().NoError(err)
+	s.Require().Equal(newAddresses[1:3], preview)
+
+	// Addresses beyond the gap limit are derived, but not added to the chain.
+	preview, err = s.addresses.PreviewUnused(s.gapLimit + 3)
+	s.Require().NoError(err)
+	s.Require().Len(preview, s.gapLimit+3)
+	for i, address := range preview {
+		s.Require().Equal(uint32(i+1), address.Configuration.AbsoluteKeypath().ToUInt32()[1])
+	}
+	s.Require().Equal(count, s.addresses.Count())
+	s.Require().Nil(s.addresses.LookupByScriptHashHex(preview[len(preview)-1].PubkeyScriptHashHex()))
+}
+
+func (s *addressChainTestSuite) TestExtendGapLimit() {
+	s.isAddressUsed = func(*addresses.AccountAddress) bool { return false }
+	newAddresses, err := s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	s.Require().Len(newAddresses, s.gapLimit)
+	extendedAddresses, err := s.addresses.ExtendGapLimit(4)
+	s.Require().NoError(err)
+	s.Require().Len(extendedAddresses, 4)
+	s.Require().Equal(s.gapLimit+4, s.addresses.GapLimit())
+	unusedAddresses, err := s.addresses.GetUnused()
+	s.Require().NoError(err)
+	s.Require().Equal(append(newAddresses, extendedAddresses...), unusedAddresses)
+	// The extended gap limit is kept.
+	newAddresses, err = s.addresses.EnsureAddresses()
+	s.Require().NoError(err)
+	s.Require().Empty(newAddresses)
+}
+
 func (s *addressChainTestSuite) TestEnsureAddresses() {
 	// No addresses in the beginning.
 	s.isAddressUsed = func(*addresses.AccountAddress) bool { return false }
@@ -0,0 +1,357 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bitcoind implements blockchain.Interface using a user-provided Bitcoin Core node.
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/electrum"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/block-client-go/electrum/types"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// maxHeaders is the maximum number of headers returned by Headers(), same as with Electrum.
+	maxHeaders = 2016
+	// pollInterval is how often the node is checked for new blocks.
+	pollInterval = 10 * time.Second
+)
+
+// errNoAddressIndex is returned by calls that need an address index if no Electrum server is
+// configured next to the node.
+var errNoAddressIndex = errp.New(
+	"Bitcoin Core does not index addresses, an Electrum server (e.g. electrs) is needed")
+
+// Client talks to a Bitcoin Core node via JSON-RPC. Blocks, transactions, fees and broadcasting are
+// handled by the node. Address history and merkle proofs are fetched from an Electrum server
+// indexing the node's blockchain (e.g. electrs).
+type Client struct {
+	rpc *rpcClient
+	// index is the Electrum server connection. nil if not configured.
+	index blockchain.Interface
+	log   *logrus.Entry
+
+	// covers all fields below.
+	mu                                sync.RWMutex
+	rpcError                          error
+	onConnectionErrorChangedCallbacks []func(error)
+	tip                               int
+	headersSubscribers                []func(*types.Header)
+
+	poll   chan struct{}
+	closed chan struct{}
+}
+
+// NewClient connects to the Bitcoin Core node and, if configured, to the Electrum server.
+func NewClient(cfg *config.BitcoinCoreConfig, log *logrus.Entry, dialer proxy.Dialer) *Client {
+	log = log.WithFields(logrus.Fields{"group": "bitcoind", "url": cfg.RedactedRPCURL()})
+	client := &Client{
+		rpc: &rpcClient{
+			httpClient: &http.Client{
+				Timeout: time.Minute,
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+						return dialer.Dial(network, address)
+					},
+				},
+			},
+			url:      cfg.RPCURL,
+			user:     cfg.RPCUser,
+			password: cfg.RPCPassword,
+		},
+		log:    log,
+		poll:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+	if cfg.ElectrumServer != nil {
+		client.index = electrum.NewElectrumConnection(
+			[]*config.ServerInfo{cfg.ElectrumServer}, true, log, dialer)
+		client.index.RegisterOnConnectionErrorChangedEvent(func(error) {
+			client.notifyConnectionError()
+		})
+	}
+	go client.pollLoop()
+	return client
+}
+
+// pollLoop checks for new blocks until the client is closed.
+func (client *Client) pollLoop() {
+	defer crashreport.Recover()
+	for {
+		client.pollTip()
+		select {
+		case <-client.closed:
+			return
+		case <-client.poll:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (client *Client) pollTip() {
+	var tip int
+	err := client.rpc.call(context.Background(), "getblockcount", &tip)
+	if err != nil {
+		client.log.WithError(err).Error("getblockcount failed")
+	}
+	client.mu.Lock()
+	errorChanged := (err == nil) != (client.rpcError == nil)
+	client.rpcError = err
+	tipChanged := err == nil && tip != client.tip
+	if tipChanged {
+		client.tip = tip
+	}
+	subscribers := client.headersSubscribers
+	client.mu.Unlock()
+
+	if errorChanged {
+		client.notifyConnectionError()
+	}
+	if tipChanged {
+		for _, subscriber := range subscribers {
+			subscriber(&types.Header{Height: tip})
+		}
+	}
+}
+
+func (client *Client) notifyConnectionError() {
+	err := client.ConnectionError()
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	for _, callback := range client.onConnectionErrorChangedCallbacks {
+		go func() {
+			defer crashreport.Recover()
+			callback(err)
+		}()
+	}
+}
+
+// ConnectionError implements blockchain.Interface.
+func (client *Client) ConnectionError() error {
+	client.mu.RLock()
+	rpcError := client.rpcError
+	client.mu.RUnlock()
+	if rpcError != nil {
+		return rpcError
+	}
+	if client.index == nil {
+		return errNoAddressIndex
+	}
+	return client.index.ConnectionError()
+}
+
+// RegisterOnConnectionErrorChangedEvent implements blockchain.Interface.
+func (client *Client) RegisterOnConnectionErrorChangedEvent(callback func(error)) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.onConnectionErrorChangedCallbacks = append(client.onConnectionErrorChangedCallbacks, callback)
+}
+
+// ManualReconnect implements blockchain.Interface.
+func (client *Client) ManualReconnect() {
+	select {
+	case client.poll <- struct{}{}:
+	default:
+	}
+	if client.index != nil {
+		client.index.ManualReconnect()
+	}
+}
+
+// Close implements blockchain.Interface.
+func (client *Client) Close() {
+	close(client.closed)
+	if client.index != nil {
+		client.index.Close()
+	}
+}
+
+// Status implements electrum.StatusReporter.
+func (client *Client) Status() *electrum.Status {
+	if reporter, ok := client.index.(electrum.StatusReporter); ok {
+		return reporter.Status()
+	}
+	return &electrum.Status{Servers: []string{}}
+}
+
+// ScriptHashGetHistory implements blockchain.Interface.
+func (client *Client) ScriptHashGetHistory(
+	ctx context.Context, scriptHashHex blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
+	if client.index == nil {
+		return nil, errNoAddressIndex
+	}
+	return client.index.ScriptHashGetHistory(ctx, scriptHashHex)
+}
+
+// ScriptHashSubscribe implements blockchain.Interface.
+func (client *Client) ScriptHashSubscribe(
+	setupAndTeardown func() func(), scriptHashHex blockchain.ScriptHashHex, result func(string)) {
+	if client.index == nil {
+		client.log.Error(errNoAddressIndex)
+		return
+	}
+	client.index.ScriptHashSubscribe(setupAndTeardown, scriptHashHex, result)
+}
+
+// GetMerkle implements blockchain.Interface.
+func (client *Client) GetMerkle(
+	ctx context.Context, txHash chainhash.Hash, height int) (*blockchain.GetMerkleResult, error) {
+	if client.index == nil {
+		return nil, errNoAddressIndex
+	}
+	return client.index.GetMerkle(ctx, txHash, height)
+}
+
+// TransactionGet implements blockchain.Interface. Transactions not in the mempool can only be
+// fetched if the node has `txindex=1` or the transaction is in its wallet, so the Electrum server is
+// used as a fallback.
+func (client *Client) TransactionGet(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	var rawTxHex string
+	err := client.rpc.call(ctx, "getrawtransaction", &rawTxHex, txHash.String())
+	if err != nil {
+		if client.index != nil {
+			return client.index.TransactionGet(ctx, txHash)
+		}
+		return nil, err
+	}
+	rawTx, err := hex.DecodeString(rawTxHex)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	tx := &wire.MsgTx{}
+	if err := tx.BtcDecode(bytes.NewReader(rawTx), 0, wire.WitnessEncoding); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return tx, nil
+}
+
+// TransactionBroadcast implements blockchain.Interface.
+func (client *Client) TransactionBroadcast(ctx context.Context, transaction *wire.MsgTx) error {
+	rawTx := &bytes.Buffer{}
+	_ = transaction.BtcEncode(rawTx, 0, wire.WitnessEncoding)
+	var txID string
+	if err := client.rpc.call(ctx, "sendrawtransaction", &txID, hex.EncodeToString(rawTx.Bytes())); err != nil {
+		return err
+	}
+	if txID != transaction.TxHash().String() {
+		return errp.New("Response is unexpected (transaction hash mismatch)")
+	}
+	return nil
+}
+
+// RelayFee implements blockchain.Interface.
+func (client *Client) RelayFee(ctx context.Context) (btcutil.Amount, error) {
+	var networkInfo struct {
+		// In BTC/kvB.
+		RelayFee float64 `json:"relayfee"`
+	}
+	if err := client.rpc.call(ctx, "getnetworkinfo", &networkInfo); err != nil {
+		return 0, err
+	}
+	return btcutil.NewAmount(networkInfo.RelayFee)
+}
+
+// EstimateFee implements blockchain.Interface.
+func (client *Client) EstimateFee(ctx context.Context, number int) (btcutil.Amount, error) {
+	var estimate struct {
+		// In BTC/kvB. Missing if there is not enough data for an estimate.
+		FeeRate *float64 `json:"feerate"`
+		Errors  []string `json:"errors"`
+	}
+	if err := client.rpc.call(ctx, "estimatesmartfee", &estimate, number); err != nil {
+		return 0, err
+	}
+	if estimate.FeeRate == nil {
+		return 0, errp.Newf("bitcoind: no fee estimate available: %v", estimate.Errors)
+	}
+	return btcutil.NewAmount(*estimate.FeeRate)
+}
+
+// Headers implements blockchain.Interface.
+func (client *Client) Headers(ctx context.Context, startHeight int, count int) (*blockchain.HeadersResult, error) {
+	var tip int
+	if err := client.rpc.call(ctx, "getblockcount", &tip); err != nil {
+		return nil, err
+	}
+	count = min(count, maxHeaders, tip-startHeight+1)
+	result := &blockchain.HeadersResult{Headers: []*wire.BlockHeader{}, Max: maxHeaders}
+	if count <= 0 {
+		return result, nil
+	}
+
+	params := make([][]interface{}, count)
+	for i := range params {
+		params[i] = []interface{}{startHeight + i}
+	}
+	hashes, err := client.rpc.batchCall(ctx, "getblockhash", params)
+	if err != nil {
+		return nil, err
+	}
+	for i, hash := range hashes {
+		var hashHex string
+		if err := json.Unmarshal(hash, &hashHex); err != nil {
+			return nil, errp.WithStack(err)
+		}
+		// false: return the serialized header.
+		params[i] = []interface{}{hashHex, false}
+	}
+	rawHeaders, err := client.rpc.batchCall(ctx, "getblockheader", params)
+	if err != nil {
+		return nil, err
+	}
+	for _, rawHeader := range rawHeaders {
+		var headerHex string
+		if err := json.Unmarshal(rawHeader, &headerHex); err != nil {
+			return nil, errp.WithStack(err)
+		}
+		headerBytes, err := hex.DecodeString(headerHex)
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+		header := &wire.BlockHeader{}
+		if err := header.Deserialize(bytes.NewReader(headerBytes)); err != nil {
+			return nil, errp.WithStack(err)
+		}
+		result.Headers = append(result.Headers, header)
+	}
+	return result, nil
+}
+
+// HeadersSubscribe implements blockchain.Interface. The node is polled for new blocks.
+func (client *Client) HeadersSubscribe(result func(*types.Header)) {
+	client.mu.Lock()
+	client.headersSubscribers = append(client.headersSubscribers, result)
+	tip := client.tip
+	client.mu.Unlock()
+	if tip > 0 {
+		result(&types.Header{Height: tip})
+	}
+}
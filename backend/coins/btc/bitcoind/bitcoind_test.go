@@ -0,0 +1,183 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+)
+
+// fakeNode serves a minimal Bitcoin Core JSON-RPC interface for a chain of `tip+1` blocks. Batch
+// responses are returned in reverse order.
+func fakeNode(t *testing.T, tip int, tx *wire.MsgTx) *httptest.Server {
+	t.Helper()
+	headers := make([]*wire.BlockHeader, tip+1)
+	headers[0] = &chaincfg.RegressionNetParams.GenesisBlock.Header
+	for i := 1; i <= tip; i++ {
+		headers[i] = &wire.BlockHeader{PrevBlock: headers[i-1].BlockHash(), Nonce: uint32(i)}
+	}
+	handle := func(request rpcRequest) rpcResponse {
+		var result interface{}
+		switch request.Method {
+		case "getblockcount":
+			result = tip
+		case "getblockhash":
+			result = headers[int(request.Params[0].(float64))].BlockHash().String()
+		case "getblockheader":
+			for _, header := range headers {
+				if header.BlockHash().String() == request.Params[0] {
+					buf := &bytes.Buffer{}
+					require.NoError(t, header.Serialize(buf))
+					result = hex.EncodeToString(buf.Bytes())
+				}
+			}
+		case "estimatesmartfee":
+			if request.Params[0].(float64) == 1 {
+				result = map[string]interface{}{"errors": []string{"Insufficient data"}, "blocks": 2}
+			} else {
+				result = map[string]interface{}{"feerate": 0.00012, "blocks": 2}
+			}
+		case "getnetworkinfo":
+			result = map[string]interface{}{"relayfee": 0.00001}
+		case "getrawtransaction":
+			if request.Params[0] != tx.TxHash().String() {
+				return rpcResponse{ID: request.ID, Error: &rpcError{Code: -5, Message: "No such mempool transaction"}}
+			}
+			buf := &bytes.Buffer{}
+			require.NoError(t, tx.Serialize(buf))
+			result = hex.EncodeToString(buf.Bytes())
+		case "sendrawtransaction":
+			rawTx, err := hex.DecodeString(request.Params[0].(string))
+			require.NoError(t, err)
+			sent := &wire.MsgTx{}
+			require.NoError(t, sent.Deserialize(bytes.NewReader(rawTx)))
+			result = sent.TxHash().String()
+		default:
+			return rpcResponse{ID: request.ID, Error: &rpcError{Code: -32601, Message: "Method not found"}}
+		}
+		resultJSON, err := json.Marshal(result)
+		require.NoError(t, err)
+		return rpcResponse{ID: request.ID, Result: resultJSON}
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var response interface{}
+		if body[0] == '[' {
+			var requests []rpcRequest
+			require.NoError(t, json.Unmarshal(body, &requests))
+			responses := make([]rpcResponse, len(requests))
+			for i, request := range requests {
+				responses[len(requests)-1-i] = handle(request)
+			}
+			response = responses
+		} else {
+			var request rpcRequest
+			require.NoError(t, json.Unmarshal(body, &request))
+			response = handle(request)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+func newTestClient(t *testing.T, url string, password string) *Client {
+	t.Helper()
+	client := NewClient(
+		&config.BitcoinCoreConfig{Enabled: true, RPCURL: url, RPCUser: "user", RPCPassword: password},
+		logging.Get().WithGroup("bitcoind_test"),
+		proxy.Direct,
+	)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClient(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	node := fakeNode(t, 10, tx)
+	defer node.Close()
+	client := newTestClient(t, node.URL, "password")
+	ctx := context.Background()
+
+	result, err := client.Headers(ctx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, result.Headers, 11)
+	require.Equal(t, maxHeaders, result.Max)
+	for i := 1; i < len(result.Headers); i++ {
+		require.Equal(t, result.Headers[i-1].BlockHash(), result.Headers[i].PrevBlock)
+		require.Equal(t, uint32(i), result.Headers[i].Nonce)
+	}
+	result, err = client.Headers(ctx, 8, 2)
+	require.NoError(t, err)
+	require.Len(t, result.Headers, 2)
+	require.Equal(t, uint32(8), result.Headers[0].Nonce)
+	result, err = client.Headers(ctx, 11, 10)
+	require.NoError(t, err)
+	require.Empty(t, result.Headers)
+
+	fee, err := client.EstimateFee(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(12000), fee)
+	_, err = client.EstimateFee(ctx, 1)
+	require.Error(t, err)
+
+	relayFee, err := client.RelayFee(ctx)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1000), relayFee)
+
+	fetchedTx, err := client.TransactionGet(ctx, tx.TxHash())
+	require.NoError(t, err)
+	require.Equal(t, tx.TxHash(), fetchedTx.TxHash())
+	_, err = client.TransactionGet(ctx, chainhash.Hash{})
+	require.Error(t, err)
+
+	require.NoError(t, client.TransactionBroadcast(ctx, tx))
+
+	// Without an Electrum server, addresses cannot be looked up.
+	_, err = client.ScriptHashGetHistory(ctx, "00")
+	require.Equal(t, errNoAddressIndex, err)
+	require.Equal(t, errNoAddressIndex, client.ConnectionError())
+}
+
+func TestClientUnauthorized(t *testing.T) {
+	node := fakeNode(t, 0, wire.NewMsgTx(wire.TxVersion))
+	defer node.Close()
+	client := newTestClient(t, node.URL, "wrong")
+	_, err := client.RelayFee(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprint(http.StatusUnauthorized))
+}
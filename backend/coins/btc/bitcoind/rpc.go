@@ -0,0 +1,157 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// maxResponseSize limits the size of RPC responses. A batch of 2016 block headers is about 330kB.
+const maxResponseSize = 10 << 20
+
+// rpcRequest is a JSON-RPC 1.0 request as understood by Bitcoin Core.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcError is an error returned by Bitcoin Core.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (err *rpcError) Error() string {
+	return fmt.Sprintf("bitcoind error %d: %s", err.Code, err.Message)
+}
+
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rpcClient performs JSON-RPC calls to a Bitcoin Core node.
+type rpcClient struct {
+	httpClient *http.Client
+	url        string
+	user       string
+	password   string
+	nextID     atomic.Uint64
+}
+
+// post sends the JSON encoded body and decodes the response into result.
+func (c *rpcClient) post(ctx context.Context, body interface{}, result interface{}) error {
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(requestBody))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.user, c.password)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+	// Bitcoin Core responds to failed calls with an error status code and the error in the body,
+	// but unauthorized requests have no body.
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return errp.Newf("bitcoind: access denied (status %d), check the RPC credentials", res.StatusCode)
+	}
+	responseBody, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize+1))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	if len(responseBody) > maxResponseSize {
+		return errp.Newf("bitcoind: response too long (> %d bytes)", maxResponseSize)
+	}
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		return errp.WithMessage(err, fmt.Sprintf("bitcoind: unexpected response (status %d)", res.StatusCode))
+	}
+	return nil
+}
+
+// call performs a single RPC call and unmarshals the result.
+func (c *rpcClient) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	var response rpcResponse
+	err := c.post(ctx, &rpcRequest{
+		JSONRPC: "1.0",
+		ID:      c.nextID.Add(1),
+		Method:  method,
+		Params:  params,
+	}, &response)
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return errp.WithStack(json.Unmarshal(response.Result, result))
+}
+
+// batchCall performs one call of the method per params entry in a single request, and returns the
+// raw results in the same order.
+func (c *rpcClient) batchCall(ctx context.Context, method string, params [][]interface{}) ([]json.RawMessage, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	requests := make([]*rpcRequest, len(params))
+	indices := map[uint64]int{}
+	for i, p := range params {
+		id := c.nextID.Add(1)
+		requests[i] = &rpcRequest{JSONRPC: "1.0", ID: id, Method: method, Params: p}
+		indices[id] = i
+	}
+	var responses []rpcResponse
+	if err := c.post(ctx, requests, &responses); err != nil {
+		return nil, err
+	}
+	if len(responses) != len(requests) {
+		return nil, errp.Newf("bitcoind: expected %d responses, got %d", len(requests), len(responses))
+	}
+	results := make([]json.RawMessage, len(requests))
+	for _, response := range responses {
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		// Responses to batch requests are not necessarily in order.
+		index, ok := indices[response.ID]
+		if !ok {
+			return nil, errp.Newf("bitcoind: unexpected response id %d", response.ID)
+		}
+		results[index] = response.Result
+	}
+	return results, nil
+}
@@ -16,6 +16,7 @@ package blockchain
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -104,17 +105,20 @@ type GetMerkleResult struct {
 // Interface is the interface to a blockchain index backend. Currently geared to Electrum, though
 // other backends can implement the same interface.
 //
+// The context passed to the calls limits how long to wait for the response, e.g. to the deadline of
+// the API request which needs the result.
+//
 //go:generate mockery --name Interface
 type Interface interface {
-	ScriptHashGetHistory(ScriptHashHex) (TxHistory, error)
-	TransactionGet(chainhash.Hash) (*wire.MsgTx, error)
+	ScriptHashGetHistory(context.Context, ScriptHashHex) (TxHistory, error)
+	TransactionGet(context.Context, chainhash.Hash) (*wire.MsgTx, error)
 	ScriptHashSubscribe(func() func(), ScriptHashHex, func(string))
 	HeadersSubscribe(func(*types.Header))
-	TransactionBroadcast(*wire.MsgTx) error
-	RelayFee() (btcutil.Amount, error)
-	EstimateFee(int) (btcutil.Amount, error)
-	Headers(int, int) (*HeadersResult, error)
-	GetMerkle(chainhash.Hash, int) (*GetMerkleResult, error)
+	TransactionBroadcast(context.Context, *wire.MsgTx) error
+	RelayFee(context.Context) (btcutil.Amount, error)
+	EstimateFee(context.Context, int) (btcutil.Amount, error)
+	Headers(context.Context, int, int) (*HeadersResult, error)
+	GetMerkle(context.Context, chainhash.Hash, int) (*GetMerkleResult, error)
 	Close()
 	ConnectionError() error
 	RegisterOnConnectionErrorChangedEvent(func(error))
@@ -15,6 +15,7 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 
 	blockchain "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
@@ -24,7 +25,8 @@ import (
 	wire "github.com/btcsuite/btcd/wire"
 )
 
-// BlockchainMock implements blockchain.Interface for use in tests.
+// BlockchainMock implements blockchain.Interface for use in tests. The contexts passed to the calls
+// are ignored.
 type BlockchainMock struct {
 	MockScriptHashGetHistory func(blockchain.ScriptHashHex) (blockchain.TxHistory, error)
 	MockTransactionGet       func(chainhash.Hash) (*wire.MsgTx, error)
@@ -43,7 +45,7 @@ type BlockchainMock struct {
 }
 
 // ScriptHashGetHistory implements Interface.
-func (b *BlockchainMock) ScriptHashGetHistory(s blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
+func (b *BlockchainMock) ScriptHashGetHistory(_ context.Context, s blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
 	if b.MockScriptHashGetHistory != nil {
 		return b.MockScriptHashGetHistory(s)
 	}
@@ -51,7 +53,7 @@ func (b *BlockchainMock) ScriptHashGetHistory(s blockchain.ScriptHashHex) (block
 }
 
 // TransactionGet implements Interface.
-func (b *BlockchainMock) TransactionGet(h chainhash.Hash) (*wire.MsgTx, error) {
+func (b *BlockchainMock) TransactionGet(_ context.Context, h chainhash.Hash) (*wire.MsgTx, error) {
 	if b.MockTransactionGet != nil {
 		return b.MockTransactionGet(h)
 	}
@@ -73,7 +75,7 @@ func (b *BlockchainMock) HeadersSubscribe(success func(*types.Header)) {
 }
 
 // TransactionBroadcast implements Interface.
-func (b *BlockchainMock) TransactionBroadcast(msgTx *wire.MsgTx) error {
+func (b *BlockchainMock) TransactionBroadcast(_ context.Context, msgTx *wire.MsgTx) error {
 	if b.MockTransactionBroadcast != nil {
 		return b.MockTransactionBroadcast(msgTx)
 	}
@@ -81,7 +83,7 @@ func (b *BlockchainMock) TransactionBroadcast(msgTx *wire.MsgTx) error {
 }
 
 // RelayFee implements Interface.
-func (b *BlockchainMock) RelayFee() (btcutil.Amount, error) {
+func (b *BlockchainMock) RelayFee(context.Context) (btcutil.Amount, error) {
 	if b.MockRelayFee != nil {
 		return b.MockRelayFee()
 	}
@@ -89,7 +91,7 @@ func (b *BlockchainMock) RelayFee() (btcutil.Amount, error) {
 }
 
 // EstimateFee implements Interface.
-func (b *BlockchainMock) EstimateFee(i int) (btcutil.Amount, error) {
+func (b *BlockchainMock) EstimateFee(_ context.Context, i int) (btcutil.Amount, error) {
 	if b.MockEstimateFee != nil {
 		return b.MockEstimateFee(i)
 	}
@@ -97,7 +99,7 @@ func (b *BlockchainMock) EstimateFee(i int) (btcutil.Amount, error) {
 }
 
 // Headers implements Interface.
-func (b *BlockchainMock) Headers(i1 int, i2 int) (*blockchain.HeadersResult, error) {
+func (b *BlockchainMock) Headers(_ context.Context, i1 int, i2 int) (*blockchain.HeadersResult, error) {
 	if b.MockHeaders != nil {
 		return b.MockHeaders(i1, i2)
 	}
@@ -108,7 +110,7 @@ func (b *BlockchainMock) Headers(i1 int, i2 int) (*blockchain.HeadersResult, err
 }
 
 // GetMerkle implements Interface.
-func (b *BlockchainMock) GetMerkle(h chainhash.Hash, i int) (*blockchain.GetMerkleResult, error) {
+func (b *BlockchainMock) GetMerkle(_ context.Context, h chainhash.Hash, i int) (*blockchain.GetMerkleResult, error) {
 	if b.MockGetMerkle != nil {
 		return b.MockGetMerkle(h, i)
 	}
@@ -0,0 +1,54 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	btcdBlockchain "github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// BroadcastRawTransaction relays a hex encoded signed transaction to the network, e.g. one signed
+// on an air-gapped device. The transaction is decoded and sanity checked first. The transaction ID
+// is returned.
+func (coin *Coin) BroadcastRawTransaction(ctx context.Context, rawTxHex string) (string, error) {
+	rawTx, err := hex.DecodeString(strings.TrimSpace(rawTxHex))
+	if err != nil {
+		return "", errp.New("The transaction is not hex encoded")
+	}
+	transaction, err := deserializeTransaction(rawTx)
+	if err != nil {
+		return "", err
+	}
+	if err := btcdBlockchain.CheckTransactionSanity(btcutil.NewTx(transaction)); err != nil {
+		return "", errp.WithMessage(err, "The transaction is invalid")
+	}
+	for index, txIn := range transaction.TxIn {
+		if len(txIn.SignatureScript) == 0 && len(txIn.Witness) == 0 {
+			return "", errp.Newf("Input %d of the transaction is not signed", index)
+		}
+	}
+	coin.Initialize()
+	txID := transaction.TxHash().String()
+	coin.log.WithField("txID", txID).Info("Broadcasting raw transaction")
+	if err := coin.Blockchain().TransactionBroadcast(ctx, transaction); err != nil {
+		return "", err
+	}
+	return txID, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	blockchainMock "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain/mocks"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/socksproxy"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/BitBoxSwiss/block-client-go/electrum/types"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastRawTransaction(t *testing.T) {
+	var broadcasted []*wire.MsgTx
+	blockchainMock := &blockchainMock.BlockchainMock{
+		MockHeadersSubscribe: func(result func(*types.Header)) {},
+		MockTransactionBroadcast: func(transaction *wire.MsgTx) error {
+			broadcasted = append(broadcasted, transaction)
+			return nil
+		},
+	}
+	btcCoin := NewCoin(coin.CodeTBTC, "Bitcoin Testnet", "TBTC", coin.BtcUnitDefault,
+		&chaincfg.TestNet3Params, test.TstTempDir("btc-broadcast"), nil, explorer,
+		socksproxy.NewSocksProxy(false, ""))
+	btcCoin.TstSetMakeBlockchain(func() blockchain.Interface { return blockchainMock })
+
+	transaction := wire.NewMsgTx(wire.TxVersion)
+	transaction.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: chainhash.HashH([]byte("prev")), Index: 1}, nil, wire.TxWitness{{1}, {2}}))
+	transaction.AddTxOut(wire.NewTxOut(1000, []byte{0x00, 0x14}))
+	serialize := func(transaction *wire.MsgTx) string {
+		var buf bytes.Buffer
+		require.NoError(t, transaction.Serialize(&buf))
+		return hex.EncodeToString(buf.Bytes())
+	}
+	rawTx := serialize(transaction)
+
+	for _, invalid := range []string{"", "zz", "0100", rawTx + "00"} {
+		_, err := btcCoin.BroadcastRawTransaction(context.Background(), invalid)
+		require.Error(t, err, invalid)
+	}
+
+	unsigned := transaction.Copy()
+	unsigned.TxIn[0].Witness = nil
+	_, err := btcCoin.BroadcastRawTransaction(context.Background(), serialize(unsigned))
+	require.Error(t, err)
+
+	noOutputs := transaction.Copy()
+	noOutputs.TxOut = nil
+	_, err = btcCoin.BroadcastRawTransaction(context.Background(), serialize(noOutputs))
+	require.Error(t, err)
+	require.Empty(t, broadcasted)
+
+	txID, err := btcCoin.BroadcastRawTransaction(context.Background(), " "+rawTx+"\n")
+	require.NoError(t, err)
+	require.Equal(t, transaction.TxHash().String(), txID)
+	require.Len(t, broadcasted, 1)
+	require.Equal(t, transaction.TxHash(), broadcasted[0].TxHash())
+
+	blockchainMock.MockTransactionBroadcast = func(*wire.MsgTx) error {
+		return errp.New("rejected")
+	}
+	_, err = btcCoin.BroadcastRawTransaction(context.Background(), rawTx)
+	require.Error(t, err)
+}
@@ -54,11 +54,14 @@ type Coin struct {
 	dbFolder              string
 	makeBlockchain        func() blockchain.Interface
 	blockExplorerTxPrefix string
+	socksProxy            socksproxy.SocksProxy
 
 	observable.Implementation
 
 	blockchain blockchain.Interface
 	headers    *headers.Headers
+	// pruneHeaders enables keeping only the most recent headers on disk.
+	pruneHeaders bool
 
 	log *logrus.Entry
 }
@@ -84,8 +87,9 @@ func NewCoin(
 		net:                   net,
 		dbFolder:              dbFolder,
 		blockExplorerTxPrefix: blockExplorerTxPrefix,
+		socksProxy:            socksProxy,
 		makeBlockchain: func() blockchain.Interface {
-			return electrum.NewElectrumConnection(
+			return electrum.NewElectrumConnectionPool(
 				servers,
 				log,
 				socksProxy.GetTCPProxyDialer(),
@@ -96,6 +100,24 @@ func NewCoin(
 	return coin
 }
 
+// SetMakeBlockchain replaces the default Electrum blockchain backend, e.g. to connect to the user's
+// own node. Must be called before the coin is initialized.
+func (coin *Coin) SetMakeBlockchain(f func() blockchain.Interface) {
+	coin.makeBlockchain = f
+}
+
+// NewElectrumConnection connects to the given Electrum servers instead of the servers of the coin,
+// e.g. for an account that uses the user's own server. The servers are tried in the given order.
+func (coin *Coin) NewElectrumConnection(servers []*config.ServerInfo, log *logrus.Entry) blockchain.Interface {
+	return electrum.NewElectrumConnection(servers, true, log, coin.socksProxy.GetTCPProxyDialer())
+}
+
+// SetPruneHeaders enables keeping only the most recent block headers on disk. Must be called before
+// the coin is initialized.
+func (coin *Coin) SetPruneHeaders(pruneHeaders bool) {
+	coin.pruneHeaders = pruneHeaders
+}
+
 // TstSetMakeBlockchain must only be used in unit tests to provide a mock instance for the
 // blockchain interface.
 func (coin *Coin) TstSetMakeBlockchain(f func() blockchain.Interface) {
@@ -127,6 +149,9 @@ func (coin *Coin) Initialize() {
 			db,
 			coin.blockchain,
 			coin.log)
+		if coin.pruneHeaders {
+			coin.headers.SetPruning(headers.PrunedHeadersToKeep)
+		}
 		coin.headers.Initialize()
 		coin.headers.SubscribeEvent(func(event headers.Event) {
 			if event == headers.EventSyncing || event == headers.EventSynced {
@@ -175,7 +200,7 @@ func (coin *Coin) GetFormatUnit(bool) string {
 		switch coin.code {
 		case coinpkg.CodeBTC:
 			return "sat"
-		case coinpkg.CodeTBTC:
+		case coinpkg.CodeTBTC, coinpkg.CodeTSIG:
 			return "tsat"
 		}
 	}
@@ -263,7 +288,7 @@ func (coin *Coin) decodeAddress(address string) (btcutil.Address, error) {
 	}
 	if _, ok := btcAddress.(*btcutil.AddressTaproot); ok {
 		switch coin.code {
-		case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeRBTC:
+		case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC:
 			// Taproot activated on Bitcoin.
 		default:
 			// Taproot not activated on other coins.
@@ -188,6 +188,12 @@ func (s *testSuite) TestAddressToPkScript() {
 		// p2wpkh native segwit
 		{"ltc1qzr0n0a4xs0404fy5l7pl7pj8yj8q34ml27rlcs", "001410df37f6a683eafaa494ff83ff0647248e08d77f"},
 	}
+	// Segwit v1 outputs must be encoded using bech32m, see BIP-350. These are the p2tr addresses
+	// above encoded using bech32.
+	bech32TaprootAddresses := []test{
+		{"tb1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxq5dtq8w", ""},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqr9a0ap", ""},
+	}
 
 	var validAddresses []test
 	var invalidAddresses []test
@@ -215,6 +221,7 @@ func (s *testSuite) TestAddressToPkScript() {
 	default:
 		s.Require().Fail("not all cases tested")
 	}
+	invalidAddresses = append(invalidAddresses, bech32TaprootAddresses...)
 	for _, test := range validAddresses {
 		pkScript, err := s.coin.AddressToPkScript(test.address)
 		s.Require().NoError(err, test.address)
@@ -0,0 +1,148 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"context"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ConsolidationArgs selects the coins to consolidate and the fee rate of the consolidation.
+type ConsolidationArgs struct {
+	// SelectedUTXOs restricts the consolidated coins. If empty, all coins not marked as "do not spend"
+	// are considered.
+	SelectedUTXOs map[wire.OutPoint]struct{}
+	// MaxAmount, if not nil, restricts the consolidated coins to the ones with a value up to this
+	// amount.
+	MaxAmount     *coin.SendAmount
+	FeeTargetCode accounts.FeeTargetCode
+	// Only applies if FeeTargetCode == Custom. It is provided in sat/vB.
+	CustomFee string
+}
+
+// ConsolidationProposal describes a transaction spending many coins to a single new coin of the
+// account.
+type ConsolidationProposal struct {
+	// NumInputs is the number of consolidated coins.
+	NumInputs int
+	// Amount is the value of the consolidated coin.
+	Amount coin.Amount
+	Fee    coin.Amount
+	// FutureFeeRatePerKb is the fee rate assumed for future transactions, which is the fee rate of
+	// the default fee target.
+	FutureFeeRatePerKb btcutil.Amount
+	// FutureFeeSavings is the fee saved by spending the consolidated coin instead of all the
+	// consolidated coins in a future transaction.
+	FutureFeeSavings coin.Amount
+}
+
+// FormattedFutureFeeRate returns a string showing the fee rate assumed for future transactions.
+func (proposal *ConsolidationProposal) FormattedFutureFeeRate() string {
+	return formatFeeRate(&proposal.FutureFeeRatePerKb)
+}
+
+// ConsolidationProposal proposes a transaction spending the selected coins to an unused change
+// address of the account, usually at a low fee rate, so that future transactions need fewer
+// inputs. The proposal is stored internally and can be signed and sent with SendTx().
+func (account *Account) ConsolidationProposal(
+	ctx context.Context, args *ConsolidationArgs) (*ConsolidationProposal, error) {
+	defer account.activeTxProposalLock.Lock()()
+
+	var maxAmount int64
+	if args.MaxAmount != nil {
+		var err error
+		maxAmount, err = account.parseSendAmount(*args.MaxAmount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	utxo, err := account.transactions.SpendableOutputs()
+	if err != nil {
+		return nil, err
+	}
+	wireUTXO := map[wire.OutPoint]maketx.UTXO{}
+	for outPoint, txOut := range utxo {
+		// Coins marked as "do not spend" are only consolidated if explicitly selected.
+		if len(args.SelectedUTXOs) != 0 {
+			if _, ok := args.SelectedUTXOs[outPoint]; !ok {
+				continue
+			}
+		} else if account.UTXOFrozen(outPoint.String()) {
+			continue
+		}
+		if maxAmount != 0 && txOut.Value > maxAmount {
+			continue
+		}
+		wireUTXO[outPoint] = maketx.UTXO{
+			TxOut: txOut.TxOut,
+			Address: account.getAddress(
+				blockchain.NewScriptHashHex(txOut.TxOut.PkScript)),
+		}
+	}
+	if len(wireUTXO) < 2 {
+		return nil, errp.New("At least two coins are needed for a consolidation")
+	}
+
+	feeRatePerKb, err := account.getFeePerKb(ctx, &accounts.TxProposalArgs{
+		FeeTargetCode: args.FeeTargetCode,
+		CustomFee:     args.CustomFee,
+	})
+	if err != nil {
+		return nil, err
+	}
+	consolidationAddress, err := account.pickChangeAddress(wireUTXO)
+	if err != nil {
+		return nil, err
+	}
+	txProposal, err := maketx.NewTxSpendAll(
+		account.coin,
+		wireUTXO,
+		maketx.NewOutputInfo(consolidationAddress.PubkeyScript()),
+		feeRatePerKb,
+		account.log,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The output goes to the account itself, which the keystore verifies like a change output.
+	txProposal.ChangeAddress = consolidationAddress
+
+	futureFeeRatePerKb := feeRatePerKb
+	if _, defaultFeeTarget := account.FeeTargets(ctx); defaultFeeTarget != accounts.FeeTargetCodeCustom {
+		feeRate, err := account.getFeePerKb(ctx, &accounts.TxProposalArgs{FeeTargetCode: defaultFeeTarget})
+		if err == nil {
+			futureFeeRatePerKb = feeRate
+		}
+	}
+	savings := maketx.ConsolidationSavings(
+		wireUTXO, consolidationAddress.Configuration, futureFeeRatePerKb, account.log)
+
+	account.activeTxProposal = txProposal
+	account.log.WithField("inputs", len(wireUTXO)).WithField("fee", txProposal.Fee).
+		Debug("Proposing consolidation")
+	return &ConsolidationProposal{
+		NumInputs:          len(wireUTXO),
+		Amount:             coin.NewAmountFromInt64(int64(txProposal.Amount)),
+		Fee:                coin.NewAmountFromInt64(int64(txProposal.Fee)),
+		FutureFeeRatePerKb: futureFeeRatePerKb,
+		FutureFeeSavings:   coin.NewAmountFromInt64(int64(savings)),
+	}, nil
+}
@@ -16,6 +16,9 @@ package headersdb
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
 	"os"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
@@ -26,23 +29,35 @@ import (
 
 const headerSize = 80
 
+// prunedMagic starts the first record of a pruned database file. The rest of the record holds the
+// height of the first stored header. Unpruned files start with the genesis header or, if syncing
+// started at a checkpoint, with zero bytes.
+var prunedMagic = []byte("BBHDRDB1")
+
 // DB is a database for storing headers. The database is simply a file where headers are appended
 // to. Loolup is quick as each header is 80 bytes.
+//
+// If the database was pruned, the file starts with a record containing the height of the first
+// stored header, and headers before it are not available.
 type DB struct {
-	file *os.File
-	log  *logrus.Entry
-	lock locker.Locker
+	filename string
+	file     *os.File
+	// base is the height of the first stored header. 0 if not pruned.
+	base int
+	// dataOffset is the file offset of the first stored header.
+	dataOffset int64
+	log        *logrus.Entry
+	lock       locker.Locker
 }
 
 // NewDB creates/opens a new db.
 func NewDB(filename string, log *logrus.Entry) (*DB, error) {
-	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0600)
-	if err != nil {
-		return nil, errp.WithStack(err)
-	}
 	db := &DB{
-		file: file,
-		log:  log,
+		filename: filename,
+		log:      log,
+	}
+	if err := db.open(); err != nil {
+		return nil, err
 	}
 	if err := db.fixTrailingZeroesHeaders(); err != nil {
 		return nil, err
@@ -50,6 +65,29 @@ func NewDB(filename string, log *logrus.Entry) (*DB, error) {
 	return db, nil
 }
 
+// open opens the database file and reads the height of the first header if it was pruned.
+func (db *DB) open() error {
+	file, err := os.OpenFile(db.filename, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	db.file = file
+	db.base = 0
+	db.dataOffset = 0
+	record := make([]byte, headerSize)
+	n, err := file.ReadAt(record, 0)
+	if n == headerSize && bytes.HasPrefix(record, prunedMagic) {
+		db.base = int(binary.LittleEndian.Uint64(record[len(prunedMagic):]))
+		db.dataOffset = headerSize
+		return nil
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = file.Close()
+		return errp.WithStack(err)
+	}
+	return nil
+}
+
 // fixTrailingZeroesHeaders deletes trailing headers that are stored as zero bytes. Zero headers
 // don't exist in reality and could end up in the database file as a result of an interrupted
 // `file.WriteAt()` call.
@@ -81,7 +119,11 @@ func (db *DB) tip() (int, error) {
 	if err != nil {
 		return 0, errp.WithStack(err)
 	}
-	return int(fileInfo.Size()/headerSize) - 1, nil
+	return db.base + int((fileInfo.Size()-db.dataOffset)/headerSize) - 1, nil
+}
+
+func (db *DB) offset(height int) int64 {
+	return db.dataOffset + headerSize*int64(height-db.base)
 }
 
 // RevertTo implements headers.DBInterface.
@@ -97,7 +139,17 @@ func (db *DB) RevertTo(tip int) error {
 	if tip > currentTip {
 		panic("revert must go backwards")
 	}
-	if err := db.file.Truncate(headerSize * int64(tip+1)); err != nil {
+	if tip < db.base {
+		// Reverting to before the first stored header of a pruned database. Start from scratch,
+		// as the headers can only be appended to the stored ones.
+		if err := db.file.Truncate(0); err != nil {
+			return errp.WithStack(err)
+		}
+		db.base = 0
+		db.dataOffset = 0
+		return nil
+	}
+	if err := db.file.Truncate(db.offset(tip + 1)); err != nil {
 		return err
 	}
 	return nil
@@ -115,6 +167,9 @@ func (db *DB) PutHeader(height int, header *wire.BlockHeader) error {
 		panic("invalid height")
 	}
 	defer db.lock.Lock()()
+	if height < db.base {
+		return errp.Newf("cannot store header at %d, the headers before %d were pruned", height, db.base)
+	}
 	var headerSer bytes.Buffer
 	if err := header.Serialize(&headerSer); err != nil {
 		return errp.WithStack(err)
@@ -122,7 +177,7 @@ func (db *DB) PutHeader(height int, header *wire.BlockHeader) error {
 	// This call, if interrupted, can leave zero bytes at the end of the file without writing the
 	// data. We can't fix it here as the process may have ended. It is fixed at DB loading time, see
 	// `fixTrailingZeroesHeaders()`.
-	if _, err := db.file.WriteAt(headerSer.Bytes(), headerSize*int64(height)); err != nil {
+	if _, err := db.file.WriteAt(headerSer.Bytes(), db.offset(height)); err != nil {
 		return errp.WithStack(err)
 	}
 	return nil
@@ -135,11 +190,11 @@ func (db *DB) HeaderByHeight(height int) (*wire.BlockHeader, error) {
 	if err != nil {
 		return nil, err
 	}
-	if tip < height {
+	if tip < height || height < db.base {
 		return nil, nil
 	}
 	headerBytes := make([]byte, headerSize)
-	if _, err := db.file.ReadAt(headerBytes, headerSize*int64(height)); err != nil {
+	if _, err := db.file.ReadAt(headerBytes, db.offset(height)); err != nil {
 		return nil, errp.WithStack(err)
 	}
 	if bytes.Equal(headerBytes, bytes.Repeat([]byte{0}, headerSize)) {
@@ -152,6 +207,58 @@ func (db *DB) HeaderByHeight(height int) (*wire.BlockHeader, error) {
 	return header, nil
 }
 
+// Prune implements headers.DBInterface. The remaining headers are copied to a new file, which
+// replaces the current one.
+func (db *DB) Prune(height int) error {
+	defer db.lock.Lock()()
+	tip, err := db.tip()
+	if err != nil {
+		return err
+	}
+	if height <= db.base || height > tip {
+		return nil
+	}
+	tmpFilename := db.filename + ".tmp"
+	tmpFile, err := os.OpenFile(tmpFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	err = func() error {
+		defer tmpFile.Close() //nolint:errcheck
+		record := make([]byte, headerSize)
+		copy(record, prunedMagic)
+		binary.LittleEndian.PutUint64(record[len(prunedMagic):], uint64(height))
+		if _, err := tmpFile.Write(record); err != nil {
+			return errp.WithStack(err)
+		}
+		if _, err := db.file.Seek(db.offset(height), io.SeekStart); err != nil {
+			return errp.WithStack(err)
+		}
+		if _, err := io.Copy(tmpFile, db.file); err != nil {
+			return errp.WithStack(err)
+		}
+		return errp.WithStack(tmpFile.Sync())
+	}()
+	if err != nil {
+		_ = os.Remove(tmpFilename)
+		return err
+	}
+	// The file is closed before renaming, which is not possible on Windows otherwise.
+	if err := db.file.Close(); err != nil {
+		return errp.WithStack(err)
+	}
+	renameErr := os.Rename(tmpFilename, db.filename)
+	if err := db.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		_ = os.Remove(tmpFilename)
+		return errp.WithStack(renameErr)
+	}
+	db.log.Infof("Pruned headers before %d", height)
+	return nil
+}
+
 // Flush implements headers.DBInterface.
 func (db *DB) Flush() error {
 	return db.file.Sync()
@@ -77,3 +77,56 @@ func TestFixTrailingZeroes(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, tip)
 }
+
+func TestPrune(t *testing.T) {
+	filename := test.TstTempFile("headersdb")
+	db, err := NewDB(filename, log)
+	require.NoError(t, err)
+	for height := 0; height < 100; height++ {
+		require.NoError(t, db.PutHeader(height, &wire.BlockHeader{Nonce: uint32(height)}))
+	}
+	require.NoError(t, db.Prune(60))
+
+	checkPruned := func(db *DB) {
+		t.Helper()
+		tip, err := db.Tip()
+		require.NoError(t, err)
+		require.Equal(t, 99, tip)
+		header, err := db.HeaderByHeight(59)
+		require.NoError(t, err)
+		require.Nil(t, header)
+		for height := 60; height < 100; height++ {
+			header, err := db.HeaderByHeight(height)
+			require.NoError(t, err)
+			require.Equal(t, uint32(height), header.Nonce)
+		}
+	}
+	checkPruned(db)
+	require.Error(t, db.PutHeader(59, &wire.BlockHeader{}))
+	require.NoError(t, db.Close())
+
+	// The pruned state is persisted.
+	db, err = NewDB(filename, log)
+	require.NoError(t, err)
+	defer db.Close()
+	checkPruned(db)
+	fileInfo, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Equal(t, int64(41*headerSize), fileInfo.Size())
+
+	require.NoError(t, db.PutHeader(100, &wire.BlockHeader{Nonce: 100}))
+	require.NoError(t, db.RevertTo(80))
+	tip, err := db.Tip()
+	require.NoError(t, err)
+	require.Equal(t, 80, tip)
+
+	// Reverting before the first stored header empties the database.
+	require.NoError(t, db.RevertTo(10))
+	tip, err = db.Tip()
+	require.NoError(t, err)
+	require.Equal(t, -1, tip)
+	require.NoError(t, db.PutHeader(0, &wire.BlockHeader{Nonce: 1}))
+	header, err := db.HeaderByHeight(0)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), header.Nonce)
+}
@@ -33,6 +33,7 @@ import (
 const (
 	bucketTransactionsKey           = "transactions"
 	bucketUnverifiedTransactionsKey = "unverifiedTransactions"
+	bucketDroppedTransactionsKey    = "droppedTransactions"
 	bucketInputsKey                 = "inputs"
 	bucketOutputsKey                = "outputs"
 	bucketAddressHistoriesKey       = "addressHistories"
@@ -146,6 +147,11 @@ func (tx *Tx) TxInfo(txHash chainhash.Hash) (
 func (tx *Tx) PutTx(txHash chainhash.Hash, msgTx *wire.MsgTx, height int) error {
 	var verified *bool
 	err := tx.modifyTx(txHash[:], func(walletTx *transactions.DBTxInfo) {
+		if walletTx.Height != height {
+			// The tx was verified to be in a block at a different height (e.g. before a reorg).
+			walletTx.Verified = nil
+			walletTx.HeaderTimestamp = nil
+		}
 		verified = walletTx.Verified
 		walletTx.Tx = msgTx
 		walletTx.Height = height
@@ -236,6 +242,58 @@ func (tx *Tx) MarkTxVerified(txHash chainhash.Hash, headerTimestamp time.Time) e
 	})
 }
 
+// MarkTxUnverified implements transactions.DBTxInterface.
+func (tx *Tx) MarkTxUnverified(txHash chainhash.Hash) error {
+	bucketUnverifiedTransactions, err := tx.tx.CreateBucketIfNotExists([]byte(bucketUnverifiedTransactionsKey))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	if err := bucketUnverifiedTransactions.Put(txHash[:], nil); err != nil {
+		return errp.WithStack(err)
+	}
+	return tx.modifyTx(txHash[:], func(walletTx *transactions.DBTxInfo) {
+		walletTx.Verified = nil
+		walletTx.HeaderTimestamp = nil
+	})
+}
+
+// PutDroppedTx implements transactions.DBTxInterface.
+func (tx *Tx) PutDroppedTx(txHash chainhash.Hash, droppedTx *transactions.DBDroppedTxInfo) error {
+	bucketDroppedTransactions, err := tx.tx.CreateBucketIfNotExists([]byte(bucketDroppedTransactionsKey))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	return writeJSON(bucketDroppedTransactions, txHash[:], droppedTx)
+}
+
+// DroppedTx implements transactions.DBTxInterface.
+func (tx *Tx) DroppedTx(txHash chainhash.Hash) (*transactions.DBDroppedTxInfo, error) {
+	droppedTx := &transactions.DBDroppedTxInfo{}
+	found, err := readJSON(tx.tx.Bucket([]byte(bucketDroppedTransactionsKey)), txHash[:], droppedTx)
+	if err != nil || !found {
+		return nil, err
+	}
+	droppedTx.TxHash = txHash
+	return droppedTx, nil
+}
+
+// DroppedTransactions implements transactions.DBTxInterface.
+func (tx *Tx) DroppedTransactions() ([]chainhash.Hash, error) {
+	return getTransactions(tx.tx.Bucket([]byte(bucketDroppedTransactionsKey)))
+}
+
+// DeleteDroppedTx implements transactions.DBTxInterface. It panics if called from a read-only db
+// transaction.
+func (tx *Tx) DeleteDroppedTx(txHash chainhash.Hash) {
+	bucketDroppedTransactions, err := tx.tx.CreateBucketIfNotExists([]byte(bucketDroppedTransactionsKey))
+	if err != nil {
+		panic(errp.WithStack(err))
+	}
+	if err := bucketDroppedTransactions.Delete(txHash[:]); err != nil {
+		panic(errp.WithStack(err))
+	}
+}
+
 // PutInput implements transactions.DBTxInterface.
 func (tx *Tx) PutInput(outPoint wire.OutPoint, txHash chainhash.Hash) error {
 	bucketInputs, err := tx.tx.CreateBucketIfNotExists([]byte(bucketInputsKey))
@@ -382,3 +440,34 @@ func (tx *Tx) GapLimits() (types.GapLimits, error) {
 	}
 	return types.GapLimits{}, nil
 }
+
+// PutLastSynced implements transactions.DBTxInterface.
+func (tx *Tx) PutLastSynced(lastSynced time.Time) error {
+	bucketConfig, err := tx.tx.CreateBucketIfNotExists([]byte(bucketConfigKey))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.LittleEndian, lastSynced.Unix()); err != nil {
+		return errp.WithStack(err)
+	}
+	return bucketConfig.Put([]byte("lastSynced"), buf.Bytes())
+}
+
+// LastSynced implements transactions.DBTxInterface.
+func (tx *Tx) LastSynced() (*time.Time, error) {
+	bucketConfig := tx.tx.Bucket([]byte(bucketConfigKey))
+	if bucketConfig == nil {
+		return nil, nil
+	}
+	value := bucketConfig.Get([]byte("lastSynced"))
+	if value == nil {
+		return nil, nil
+	}
+	var unix int64
+	if err := binary.Read(bytes.NewReader(value), binary.LittleEndian, &unix); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	lastSynced := time.Unix(unix, 0)
+	return &lastSynced, nil
+}
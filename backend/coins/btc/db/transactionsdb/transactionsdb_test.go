@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/transactions"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
 	"github.com/btcsuite/btcd/btcutil"
@@ -267,6 +268,99 @@ func TestTxQuick(t *testing.T) {
 	})
 }
 
+// TestTxVerifiedHeightChange checks that a verified tx needs to be verified again if it is
+// reported at a different height.
+func TestTxVerifiedHeightChange(t *testing.T) {
+	testTx(func(tx *Tx) {
+		msgTx := &wire.MsgTx{
+			Version: wire.TxVersion,
+			TxIn: []*wire.TxIn{
+				wire.NewTxIn(&wire.OutPoint{Hash: chainhash.HashH(nil), Index: 0}, nil, nil),
+			},
+			TxOut: []*wire.TxOut{wire.NewTxOut(123, []byte("dummyPubKeyScript"))},
+		}
+		txHash := msgTx.TxHash()
+		require.NoError(t, tx.PutTx(txHash, msgTx, 10))
+		require.NoError(t, tx.MarkTxVerified(txHash, time.Unix(1700000000, 0)))
+
+		// Same height: stays verified.
+		require.NoError(t, tx.PutTx(txHash, msgTx, 10))
+		txInfo, err := tx.TxInfo(txHash)
+		require.NoError(t, err)
+		require.NotNil(t, txInfo.Verified)
+		require.NotNil(t, txInfo.HeaderTimestamp)
+		unverified, err := tx.UnverifiedTransactions()
+		require.NoError(t, err)
+		require.Empty(t, unverified)
+
+		// Different height: unverified.
+		require.NoError(t, tx.PutTx(txHash, msgTx, 11))
+		txInfo, err = tx.TxInfo(txHash)
+		require.NoError(t, err)
+		require.Nil(t, txInfo.Verified)
+		require.Nil(t, txInfo.HeaderTimestamp)
+		unverified, err = tx.UnverifiedTransactions()
+		require.NoError(t, err)
+		require.Equal(t, []chainhash.Hash{txHash}, unverified)
+
+		// Verified again and then invalidated by a reorg.
+		require.NoError(t, tx.MarkTxVerified(txHash, time.Unix(1700000000, 0)))
+		require.NoError(t, tx.MarkTxUnverified(txHash))
+		txInfo, err = tx.TxInfo(txHash)
+		require.NoError(t, err)
+		require.Nil(t, txInfo.Verified)
+		require.Nil(t, txInfo.HeaderTimestamp)
+		require.Equal(t, 11, txInfo.Height)
+		unverified, err = tx.UnverifiedTransactions()
+		require.NoError(t, err)
+		require.Equal(t, []chainhash.Hash{txHash}, unverified)
+	})
+}
+
+func TestDroppedTx(t *testing.T) {
+	testTx(func(tx *Tx) {
+		msgTx := &wire.MsgTx{
+			Version: wire.TxVersion,
+			TxIn: []*wire.TxIn{
+				wire.NewTxIn(&wire.OutPoint{Hash: chainhash.HashH(nil), Index: 0}, nil, nil),
+			},
+			TxOut: []*wire.TxOut{wire.NewTxOut(123, []byte("dummyPubKeyScript"))},
+		}
+		txHash := msgTx.TxHash()
+		droppedTx, err := tx.DroppedTx(txHash)
+		require.NoError(t, err)
+		require.Nil(t, droppedTx)
+		txHashes, err := tx.DroppedTransactions()
+		require.NoError(t, err)
+		require.Empty(t, txHashes)
+
+		created := time.Unix(1700000000, 0)
+		expected := &transactions.DBDroppedTxInfo{
+			Tx:               msgTx,
+			Outputs:          map[uint32]*wire.TxOut{0: msgTx.TxOut[0]},
+			CreatedTimestamp: &created,
+			DroppedTimestamp: time.Unix(1700000600, 0),
+		}
+		require.NoError(t, tx.PutDroppedTx(txHash, expected))
+		droppedTx, err = tx.DroppedTx(txHash)
+		require.NoError(t, err)
+		require.Equal(t, txHash, droppedTx.TxHash)
+		require.Equal(t, txHash, droppedTx.Tx.TxHash())
+		require.Equal(t, expected.Outputs, droppedTx.Outputs)
+		require.Equal(t, created.Unix(), droppedTx.CreatedTimestamp.Unix())
+		require.Equal(t, expected.DroppedTimestamp.Unix(), droppedTx.DroppedTimestamp.Unix())
+		require.False(t, droppedTx.Notified)
+		txHashes, err = tx.DroppedTransactions()
+		require.NoError(t, err)
+		require.Equal(t, []chainhash.Hash{txHash}, txHashes)
+
+		tx.DeleteDroppedTx(txHash)
+		droppedTx, err = tx.DroppedTx(txHash)
+		require.NoError(t, err)
+		require.Nil(t, droppedTx)
+	})
+}
+
 func TestInput(t *testing.T) {
 	testTx(func(tx *Tx) {
 		outpoint1 := wire.OutPoint{
@@ -548,3 +642,22 @@ func TestGapLimits(t *testing.T) {
 		require.Equal(t, uint16(123), limits.Change)
 	})
 }
+
+func TestLastSynced(t *testing.T) {
+	testTx(func(tx *Tx) {
+		lastSynced, err := tx.LastSynced()
+		require.NoError(t, err)
+		require.Nil(t, lastSynced)
+
+		require.NoError(t, tx.PutLastSynced(time.Unix(1700000000, 0)))
+		require.Equal(t,
+			"00f1536500000000",
+			hex.EncodeToString(getRawValue(tx, "config", []byte("lastSynced"))),
+		)
+
+		lastSynced, err = tx.LastSynced()
+		require.NoError(t, err)
+		require.NotNil(t, lastSynced)
+		require.Equal(t, int64(1700000000), lastSynced.Unix())
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/mempool"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtMagic is the prefix of every serialized PSBT, see BIP-174.
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// DecodedInput is an input of a decoded transaction.
+type DecodedInput struct {
+	PreviousOutPoint string `json:"previousOutPoint"`
+	Sequence         uint32 `json:"sequence"`
+	// Address and Amount describe the spent output. They are only known for PSBT inputs which
+	// include the previous output.
+	Address string `json:"address,omitempty"`
+	Amount  string `json:"amount,omitempty"`
+	Signed  bool   `json:"signed"`
+}
+
+// DecodedOutput is an output of a decoded transaction.
+type DecodedOutput struct {
+	// Address is empty if the output script does not correspond to an address, e.g. an OP_RETURN
+	// output.
+	Address  string `json:"address,omitempty"`
+	Amount   string `json:"amount"`
+	PkScript string `json:"pkScript"`
+}
+
+// DecodedTransaction describes a raw transaction or PSBT so that the user can review it.
+type DecodedTransaction struct {
+	TxID     string `json:"txID"`
+	Version  int32  `json:"version"`
+	LockTime uint32 `json:"lockTime"`
+	// RBF is true if the transaction signals replaceability (BIP-125).
+	RBF     bool             `json:"rbf"`
+	Inputs  []*DecodedInput  `json:"inputs"`
+	Outputs []*DecodedOutput `json:"outputs"`
+	// Fee is empty if not all spent outputs are known.
+	Fee  string `json:"fee,omitempty"`
+	Unit string `json:"unit"`
+	// VSize is only set for raw transactions, as the size of a PSBT changes when it is signed.
+	VSize int64 `json:"vsize,omitempty"`
+	PSBT  bool  `json:"psbt"`
+}
+
+// deserializeTransaction decodes a serialized transaction, rejecting trailing data.
+func deserializeTransaction(rawTx []byte) (*wire.MsgTx, error) {
+	transaction := wire.NewMsgTx(wire.TxVersion)
+	reader := bytes.NewReader(rawTx)
+	if err := transaction.Deserialize(reader); err != nil {
+		return nil, errp.WithMessage(err, "The transaction could not be decoded")
+	}
+	if reader.Len() != 0 {
+		return nil, errp.New("The transaction is followed by unexpected data")
+	}
+	return transaction, nil
+}
+
+// psbtPreviousOutput returns the output spent by the PSBT input, or nil if it is not included.
+func psbtPreviousOutput(packet *psbt.Packet, index int) *wire.TxOut {
+	input := packet.Inputs[index]
+	if input.WitnessUtxo != nil {
+		return input.WitnessUtxo
+	}
+	outPoint := packet.UnsignedTx.TxIn[index].PreviousOutPoint
+	if input.NonWitnessUtxo != nil && input.NonWitnessUtxo.TxHash() == outPoint.Hash &&
+		int(outPoint.Index) < len(input.NonWitnessUtxo.TxOut) {
+		return input.NonWitnessUtxo.TxOut[outPoint.Index]
+	}
+	return nil
+}
+
+func psbtInputSigned(input psbt.PInput) bool {
+	return len(input.FinalScriptSig) > 0 || len(input.FinalScriptWitness) > 0 ||
+		len(input.PartialSigs) > 0 || len(input.TaprootKeySpendSig) > 0
+}
+
+// DecodeTransaction decodes a hex encoded raw transaction, or a hex or base64 encoded PSBT.
+func (coin *Coin) DecodeTransaction(encoded string) (*DecodedTransaction, error) {
+	encoded = strings.TrimSpace(encoded)
+	var packet *psbt.Packet
+	var transaction *wire.MsgTx
+	raw, hexErr := hex.DecodeString(encoded)
+	switch {
+	case hexErr == nil && !bytes.HasPrefix(raw, psbtMagic):
+		var err error
+		transaction, err = deserializeTransaction(raw)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		var err error
+		if hexErr == nil {
+			packet, err = psbt.NewFromRawBytes(bytes.NewReader(raw), false)
+		} else {
+			packet, err = psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+		}
+		if err != nil {
+			return nil, errp.WithMessage(err, "The input is neither a raw transaction nor a PSBT")
+		}
+		transaction = packet.UnsignedTx
+	}
+
+	formatAmount := func(amount int64) string {
+		return coin.FormatAmount(coinpkg.NewAmountFromInt64(amount), false)
+	}
+	formatAddress := func(pkScript []byte) string {
+		address, err := util.AddressFromPkScript(pkScript, coin.Net())
+		if err != nil {
+			return ""
+		}
+		return address.String()
+	}
+
+	result := &DecodedTransaction{
+		TxID:     transaction.TxHash().String(),
+		Version:  transaction.Version,
+		LockTime: transaction.LockTime,
+		Inputs:   make([]*DecodedInput, len(transaction.TxIn)),
+		Outputs:  make([]*DecodedOutput, len(transaction.TxOut)),
+		Unit:     coin.GetFormatUnit(false),
+		PSBT:     packet != nil,
+	}
+	var sumInputs, sumOutputs int64
+	allInputsKnown := true
+	for index, txIn := range transaction.TxIn {
+		if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+			result.RBF = true
+		}
+		input := &DecodedInput{
+			PreviousOutPoint: txIn.PreviousOutPoint.String(),
+			Sequence:         txIn.Sequence,
+			Signed:           len(txIn.SignatureScript) > 0 || len(txIn.Witness) > 0,
+		}
+		var previousOutput *wire.TxOut
+		if packet != nil {
+			input.Signed = psbtInputSigned(packet.Inputs[index])
+			previousOutput = psbtPreviousOutput(packet, index)
+		}
+		if previousOutput != nil {
+			input.Address = formatAddress(previousOutput.PkScript)
+			input.Amount = formatAmount(previousOutput.Value)
+			sumInputs += previousOutput.Value
+		} else {
+			allInputsKnown = false
+		}
+		result.Inputs[index] = input
+	}
+	for index, txOut := range transaction.TxOut {
+		result.Outputs[index] = &DecodedOutput{
+			Address:  formatAddress(txOut.PkScript),
+			Amount:   formatAmount(txOut.Value),
+			PkScript: hex.EncodeToString(txOut.PkScript),
+		}
+		sumOutputs += txOut.Value
+	}
+	if allInputsKnown && sumInputs >= sumOutputs {
+		result.Fee = formatAmount(sumInputs - sumOutputs)
+	}
+	if packet == nil {
+		result.VSize = mempool.GetTxVirtualSize(btcutil.NewTx(transaction))
+	}
+	return result, nil
+}
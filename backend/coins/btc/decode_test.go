@@ -0,0 +1,105 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/socksproxy"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	net := &chaincfg.TestNet3Params
+	btcCoin := NewCoin(coin.CodeTBTC, "Bitcoin Testnet", "TBTC", coin.BtcUnitDefault,
+		net, test.TstTempDir("btc-decode"), nil, explorer, socksproxy.NewSocksProxy(false, ""))
+
+	address, err := btcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{0x01}, 20), net)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(address)
+	require.NoError(t, err)
+	opReturn, err := txscript.NullDataScript([]byte("hello"))
+	require.NoError(t, err)
+
+	prevOutPoint := wire.OutPoint{Hash: chainhash.HashH([]byte("prev")), Index: 1}
+	transaction := wire.NewMsgTx(2)
+	transaction.AddTxIn(wire.NewTxIn(&prevOutPoint, nil, wire.TxWitness{{1}, {2}}))
+	transaction.TxIn[0].Sequence = wire.MaxTxInSequenceNum - 2
+	transaction.AddTxOut(wire.NewTxOut(90000, pkScript))
+	transaction.AddTxOut(wire.NewTxOut(0, opReturn))
+	transaction.LockTime = 123
+	var buf bytes.Buffer
+	require.NoError(t, transaction.Serialize(&buf))
+
+	decoded, err := btcCoin.DecodeTransaction(hex.EncodeToString(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, &DecodedTransaction{
+		TxID:     transaction.TxHash().String(),
+		Version:  2,
+		LockTime: 123,
+		RBF:      true,
+		Inputs: []*DecodedInput{{
+			PreviousOutPoint: prevOutPoint.String(),
+			Sequence:         wire.MaxTxInSequenceNum - 2,
+			Signed:           true,
+		}},
+		Outputs: []*DecodedOutput{
+			{Address: address.String(), Amount: "0.00090000", PkScript: hex.EncodeToString(pkScript)},
+			{Amount: "0.00000000", PkScript: hex.EncodeToString(opReturn)},
+		},
+		Unit:  "TBTC",
+		VSize: decoded.VSize,
+	}, decoded)
+	require.NotZero(t, decoded.VSize)
+
+	// PSBT including the spent output, base64 and hex encoded.
+	unsignedTx := transaction.Copy()
+	unsignedTx.TxIn[0].Witness = nil
+	unsignedTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
+	packet, err := psbt.NewFromUnsignedTx(unsignedTx)
+	require.NoError(t, err)
+	packet.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, pkScript)
+	encoded, err := packet.B64Encode()
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, packet.Serialize(&buf))
+	for _, encoded := range []string{encoded, hex.EncodeToString(buf.Bytes())} {
+		decoded, err = btcCoin.DecodeTransaction(encoded)
+		require.NoError(t, err)
+		require.True(t, decoded.PSBT)
+		require.False(t, decoded.RBF)
+		require.Equal(t, unsignedTx.TxHash().String(), decoded.TxID)
+		require.Equal(t, "0.00010000", decoded.Fee)
+		require.Zero(t, decoded.VSize)
+		require.Equal(t, address.String(), decoded.Inputs[0].Address)
+		require.Equal(t, "0.00100000", decoded.Inputs[0].Amount)
+		require.False(t, decoded.Inputs[0].Signed)
+	}
+
+	for _, invalid := range []string{"", "zz", "0100", "cHNidP8="} {
+		_, err := btcCoin.DecodeTransaction(invalid)
+		require.Error(t, err, invalid)
+	}
+}
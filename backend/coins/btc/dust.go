@@ -0,0 +1,84 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// isDustingOutput returns true if a coin of the given value, created by the transaction txHash,
+// looks like a dusting attack: its value is below the threshold, and the address it was received
+// on (with the given history) was already used by other transactions. Spending such a coin
+// together with other coins would link the addresses.
+func isDustingOutput(
+	value int64, threshold int64, txHash chainhash.Hash, addressHistory blockchain.TxHistory) bool {
+	if value >= threshold {
+		return false
+	}
+	for _, txInfo := range addressHistory {
+		if txInfo.TXHash.Hash() != txHash {
+			return true
+		}
+	}
+	return false
+}
+
+// dustThreshold returns the configured dust threshold in satoshi, or 0 if dust detection is
+// disabled.
+func (account *Account) dustThreshold() int64 {
+	if account.Config().DustThreshold == nil {
+		return 0
+	}
+	return account.Config().DustThreshold()
+}
+
+// isDust returns true if the coin looks like a dusting attack, see isDustingOutput().
+func (account *Account) isDust(output *SpendableOutput, threshold int64) bool {
+	if output.Address == nil || output.TxOut.Value >= threshold {
+		return false
+	}
+	history, err := account.getAddressHistory(output.Address)
+	if err != nil {
+		account.log.WithError(err).Error("Could not get the address history")
+		return false
+	}
+	return isDustingOutput(output.TxOut.Value, threshold, output.OutPoint.Hash, history)
+}
+
+// NewDustOutputs returns the coins which look like a dusting attack and which were neither
+// reported by a previous call nor marked as "do not spend" yet.
+func (account *Account) NewDustOutputs() []*SpendableOutput {
+	if account.dustThreshold() == 0 {
+		return nil
+	}
+	defer account.reportedDustOutputsLock.Lock()()
+	if account.reportedDustOutputs == nil {
+		account.reportedDustOutputs = map[wire.OutPoint]struct{}{}
+	}
+	result := []*SpendableOutput{}
+	for _, output := range account.SpendableOutputs() {
+		if !output.IsDust || output.Frozen {
+			continue
+		}
+		if _, ok := account.reportedDustOutputs[output.OutPoint]; ok {
+			continue
+		}
+		account.reportedDustOutputs[output.OutPoint] = struct{}{}
+		result = append(result, output)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDustingOutput(t *testing.T) {
+	dustTx := chainhash.Hash{1}
+	otherTx := chainhash.Hash{2}
+	freshAddress := blockchain.TxHistory{
+		{Height: 10, TXHash: blockchain.TXHash(dustTx)},
+	}
+	usedAddress := blockchain.TxHistory{
+		{Height: 5, TXHash: blockchain.TXHash(otherTx)},
+		{Height: 10, TXHash: blockchain.TXHash(dustTx)},
+	}
+
+	require.True(t, isDustingOutput(546, 1000, dustTx, usedAddress))
+	// Fresh address: nothing to link.
+	require.False(t, isDustingOutput(546, 1000, dustTx, freshAddress))
+	// Not below the threshold.
+	require.False(t, isDustingOutput(1000, 1000, dustTx, usedAddress))
+	require.False(t, isDustingOutput(50000, 1000, dustTx, usedAddress))
+}
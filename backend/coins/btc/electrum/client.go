@@ -24,6 +24,7 @@ import (
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/metrics"
 	"github.com/BitBoxSwiss/block-client-go/electrum"
 	"github.com/BitBoxSwiss/block-client-go/electrum/types"
 	"github.com/btcsuite/btcd/btcutil"
@@ -31,22 +32,28 @@ import (
 	"github.com/btcsuite/btcd/wire"
 )
 
+// requestsMetric counts the requests to Electrum servers by method.
+var requestsMetric = metrics.NewCounterVec(
+	"electrum_requests_total", "Number of requests to Electrum servers.", "method")
+
 // client wraps electrum.Client to convert some method inputs and outputs to btcd/btcutil types. It
 // also implements blockchain.Interface.
 type client struct {
 	client *electrum.Client
 }
 
-func (c *client) EstimateFee(number int) (btcutil.Amount, error) {
-	fee, err := c.client.EstimateFee(context.Background(), number)
+func (c *client) EstimateFee(ctx context.Context, number int) (btcutil.Amount, error) {
+	requestsMetric.Inc("blockchain.estimatefee")
+	fee, err := c.client.EstimateFee(ctx, number)
 	if err != nil {
 		return 0, err
 	}
 	return btcutil.NewAmount(fee)
 }
 
-func (c *client) GetMerkle(txHash chainhash.Hash, height int) (*blockchain.GetMerkleResult, error) {
-	result, err := c.client.GetMerkle(context.Background(), txHash.String(), height)
+func (c *client) GetMerkle(ctx context.Context, txHash chainhash.Hash, height int) (*blockchain.GetMerkleResult, error) {
+	requestsMetric.Inc("blockchain.transaction.get_merkle")
+	result, err := c.client.GetMerkle(ctx, txHash.String(), height)
 	if err != nil {
 		return nil, err
 	}
@@ -61,8 +68,9 @@ func (c *client) GetMerkle(txHash chainhash.Hash, height int) (*blockchain.GetMe
 	return &blockchain.GetMerkleResult{Merkle: merkle, Pos: result.Pos}, nil
 }
 
-func (c *client) Headers(startHeight int, count int) (*blockchain.HeadersResult, error) {
-	headersResult, err := c.client.Headers(context.Background(), startHeight, count)
+func (c *client) Headers(ctx context.Context, startHeight int, count int) (*blockchain.HeadersResult, error) {
+	requestsMetric.Inc("blockchain.block.headers")
+	headersResult, err := c.client.Headers(ctx, startHeight, count)
 	if err != nil {
 		return nil, err
 	}
@@ -79,20 +87,23 @@ func (c *client) Headers(startHeight int, count int) (*blockchain.HeadersResult,
 }
 
 func (c *client) HeadersSubscribe(result func(*types.Header, error)) {
+	requestsMetric.Inc("blockchain.headers.subscribe")
 	c.client.HeadersSubscribe(context.Background(), result)
 }
 
-func (c *client) RelayFee() (btcutil.Amount, error) {
-	fee, err := c.client.RelayFee(context.Background())
+func (c *client) RelayFee(ctx context.Context) (btcutil.Amount, error) {
+	requestsMetric.Inc("blockchain.relayfee")
+	fee, err := c.client.RelayFee(ctx)
 	if err != nil {
 		return 0, err
 	}
 	return btcutil.NewAmount(fee)
 }
 
-func (c *client) ScriptHashGetHistory(scriptHashHex blockchain.ScriptHashHex) (
+func (c *client) ScriptHashGetHistory(ctx context.Context, scriptHashHex blockchain.ScriptHashHex) (
 	blockchain.TxHistory, error) {
-	historyA, err := c.client.ScriptHashGetHistory(context.Background(), string(scriptHashHex))
+	requestsMetric.Inc("blockchain.scripthash.get_history")
+	historyA, err := c.client.ScriptHashGetHistory(ctx, string(scriptHashHex))
 	if err != nil {
 		return nil, err
 	}
@@ -114,14 +125,16 @@ func (c *client) ScriptHashSubscribe(
 	scriptHashHex blockchain.ScriptHashHex,
 	success func(string, error),
 ) {
+	requestsMetric.Inc("blockchain.scripthash.subscribe")
 	c.client.ScriptHashSubscribe(context.Background(), string(scriptHashHex), success)
 }
 
-func (c *client) TransactionBroadcast(transaction *wire.MsgTx) error {
+func (c *client) TransactionBroadcast(ctx context.Context, transaction *wire.MsgTx) error {
 	rawTx := &bytes.Buffer{}
 	_ = transaction.BtcEncode(rawTx, 0, wire.WitnessEncoding)
 	rawTxHex := hex.EncodeToString(rawTx.Bytes())
-	txID, err := c.client.TransactionBroadcast(context.Background(), rawTxHex)
+	requestsMetric.Inc("blockchain.transaction.broadcast")
+	txID, err := c.client.TransactionBroadcast(ctx, rawTxHex)
 	if err != nil {
 		// Return a new error, stripping the rawTxHex from it, if it is there.
 		return errors.New(strings.ReplaceAll(err.Error(), fmt.Sprintf("[%s]", rawTxHex), ""))
@@ -132,8 +145,9 @@ func (c *client) TransactionBroadcast(transaction *wire.MsgTx) error {
 	return nil
 }
 
-func (c *client) TransactionGet(txHash chainhash.Hash) (*wire.MsgTx, error) {
-	rawTx, err := c.client.TransactionGet(context.Background(), txHash.String())
+func (c *client) TransactionGet(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	requestsMetric.Inc("blockchain.transaction.get")
+	rawTx, err := c.client.TransactionGet(ctx, txHash.String())
 	if err != nil {
 		return nil, err
 	}
@@ -16,6 +16,7 @@ package electrum
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -40,6 +41,15 @@ import (
 // It is set at the app startup in the backend and never changes during the runtime.
 var softwareVersion = "BitBoxApp/uninitialized"
 
+const (
+	// ErrConnectFailed is the code of errors connecting to a server. The `host` parameter is the
+	// server address.
+	ErrConnectFailed errp.ErrorCode = "electrum.connectFailed"
+	// ErrDownloadCertFailed is the code of errors downloading the certificate of a server. The
+	// `host` parameter is the server address.
+	ErrDownloadCertFailed errp.ErrorCode = "electrum.downloadCertFailed"
+)
+
 // SetClientSoftwareVersion updates an electrumx client software version string
 // sent to the servers during the protocol version negotiation.
 // This is purely informational and has no impact on supported protocol versions.
@@ -51,17 +61,17 @@ func SetClientSoftwareVersion(v *semver.SemVer) {
 // establishConnection connects to a backend and returns an rpc client
 // or an error if the connection could not be established.
 func establishConnection(
-	serverInfo *config.ServerInfo, dialer proxy.Dialer) (net.Conn, error) {
+	ctx context.Context, serverInfo *config.ServerInfo, dialer proxy.Dialer) (net.Conn, error) {
 	var conn net.Conn
 	if serverInfo.TLS {
 		var err error
-		conn, err = newTLSConnection(serverInfo.Server, serverInfo.PEMCert, dialer)
+		conn, err = newTLSConnection(ctx, serverInfo.Server, serverInfo.PEMCert, dialer)
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		var err error
-		conn, err = newTCPConnection(serverInfo.Server, dialer)
+		conn, err = newTCPConnection(ctx, serverInfo.Server, dialer)
 		if err != nil {
 			return nil, err
 		}
@@ -69,7 +79,8 @@ func establishConnection(
 	return conn, nil
 }
 
-func newTLSConnection(address string, rootCert string, dialer proxy.Dialer) (*tls.Conn, error) {
+func newTLSConnection(
+	ctx context.Context, address string, rootCert string, dialer proxy.Dialer) (*tls.Conn, error) {
 	// hostname is used as server name in SNI client hello during the handshake.
 	// It is set to empty string by tls.Client if address is an IP address.
 	hostname, _, err := net.SplitHostPort(address)
@@ -77,13 +88,23 @@ func newTLSConnection(address string, rootCert string, dialer proxy.Dialer) (*tl
 		return nil, errp.WithMessage(err, fmt.Sprintf("Invalid server address %q", address))
 	}
 
+	if rootCert == "" {
+		// No pinned certificate: the server has a certificate signed by a public CA, which is
+		// verified against the system roots, including the hostname.
+		conn, err := dialContext(ctx, dialer, address)
+		if err != nil {
+			return nil, err
+		}
+		return tls.Client(conn, &tls.Config{ServerName: hostname}), nil
+	}
+
 	caCertPool := x509.NewCertPool()
 	if ok := caCertPool.AppendCertsFromPEM([]byte(rootCert)); !ok {
 		return nil, errp.New("Failed to append CA cert as trusted cert")
 	}
-	conn, err := dialer.Dial("tcp", address)
+	conn, err := dialContext(ctx, dialer, address)
 	if err != nil {
-		return nil, errp.WithStack(err)
+		return nil, err
 	}
 	tlsConn := tls.Client(conn, &tls.Config{
 		ServerName: hostname,
@@ -127,17 +148,66 @@ func newTLSConnection(address string, rootCert string, dialer proxy.Dialer) (*tl
 	return tlsConn, nil
 }
 
-func newTCPConnection(address string, dialer proxy.Dialer) (net.Conn, error) {
-	conn, err := dialer.Dial("tcp", address)
+func newTCPConnection(ctx context.Context, address string, dialer proxy.Dialer) (net.Conn, error) {
+	return dialContext(ctx, dialer, address)
+}
+
+// dialContext connects to the address using the dialer. If ctx has a deadline, it also applies to
+// all reads and writes on the returned connection, so the connection can't hang beyond it.
+func dialContext(ctx context.Context, dialer proxy.Dialer, address string) (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		conn, err = contextDialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
 	if err != nil {
 		return nil, errp.WithStack(err)
 	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return nil, errp.WithStack(err)
+		}
+	}
 	return conn, nil
 }
 
+// Status is the state of the connection to the Electrum servers.
+type Status struct {
+	// Servers are the configured servers.
+	Servers []string `json:"servers"`
+	// ActiveServer is the server currently in use, or "" if no server is connected.
+	ActiveServer string `json:"activeServer"`
+	// ActiveServers are the servers used by each connection of a connection pool, "" for a
+	// connection which is not connected. ActiveServer is the one of the first connection. Only set
+	// for a connection pool.
+	ActiveServers []string `json:"activeServers,omitempty"`
+}
+
+// StatusReporter is implemented by blockchain backends connecting to Electrum servers.
+type StatusReporter interface {
+	Status() *Status
+}
+
 // NewElectrumConnection connects to an Electrum server and returns a ElectrumClient instance to
-// communicate with it.
-func NewElectrumConnection(serverInfos []*config.ServerInfo, log *logrus.Entry, dialer proxy.Dialer) blockchain.Interface {
+// communicate with it. If prioritized is true, the servers are tried in the given order, starting
+// with the first. Otherwise, the first server to try is chosen randomly to spread the load. In
+// both cases, the next server is used if the connection fails.
+func NewElectrumConnection(
+	serverInfos []*config.ServerInfo, prioritized bool, log *logrus.Entry, dialer proxy.Dialer) blockchain.Interface {
+	var startIndex func() int
+	if prioritized {
+		startIndex = func() int { return 0 }
+	}
+	return newElectrumConnection(serverInfos, startIndex, log, dialer)
+}
+
+// newElectrumConnection creates a failover client over the given servers, starting with the server
+// at the index returned by startIndex, or a random one if startIndex is nil.
+func newElectrumConnection(
+	serverInfos []*config.ServerInfo, startIndex func() int, log *logrus.Entry, dialer proxy.Dialer) *failoverClient {
 	var serverList string
 	for _, serverInfo := range serverInfos {
 		if serverList != "" {
@@ -164,7 +234,7 @@ func NewElectrumConnection(serverInfos []*config.ServerInfo, log *logrus.Entry,
 					MethodTimeout: 50 * time.Second,
 					PingInterval:  time.Minute,
 					Dial: func() (net.Conn, error) {
-						return establishConnection(serverInfo, dialer)
+						return establishConnection(context.Background(), serverInfo, dialer)
 					},
 				})
 				if err != nil {
@@ -181,11 +251,14 @@ func NewElectrumConnection(serverInfos []*config.ServerInfo, log *logrus.Entry,
 	var fclient *failoverClient
 	fclient = newFailoverClient(&failover.Options[*client]{
 		Servers:      servers,
+		StartIndex:   startIndex,
 		RetryTimeout: retryTimeout,
 		OnConnect: func(server *failover.Server[*client]) {
+			fclient.setActiveServer(server.Name)
 			fclient.setConnectionError(nil)
 		},
 		OnDisconnect: func(server *failover.Server[*client], err error) {
+			fclient.clearActiveServer(server.Name)
 			log.
 				WithError(err).
 				WithField("server", server.String()).
@@ -205,7 +278,15 @@ func NewElectrumConnection(serverInfos []*config.ServerInfo, log *logrus.Entry,
 }
 
 // DownloadCert downloads the first element of the remote certificate chain.
-func DownloadCert(server string, dialer proxy.Dialer) (string, error) {
+func DownloadCert(ctx context.Context, server string, dialer proxy.Dialer) (string, error) {
+	pemCert, err := downloadCert(ctx, server, dialer)
+	if err != nil {
+		return "", errp.WithCode(err, ErrDownloadCertFailed, errp.Context{"host": server})
+	}
+	return pemCert, nil
+}
+
+func downloadCert(ctx context.Context, server string, dialer proxy.Dialer) (string, error) {
 	// hostname is used as server name in SNI client hello during the handshake.
 	// It is set to empty string by tls.Client if address is an IP address.
 	hostname, _, err := net.SplitHostPort(server)
@@ -214,9 +295,9 @@ func DownloadCert(server string, dialer proxy.Dialer) (string, error) {
 	}
 
 	var pemCert []byte
-	conn, err := dialer.Dial("tcp", server)
+	conn, err := dialContext(ctx, dialer, server)
 	if err != nil {
-		return "", errp.WithStack(err)
+		return "", err
 	}
 
 	tlsConn := tls.Client(conn, &tls.Config{
@@ -238,7 +319,7 @@ func DownloadCert(server string, dialer proxy.Dialer) (string, error) {
 			return nil
 		},
 	})
-	err = tlsConn.Handshake()
+	err = tlsConn.HandshakeContext(ctx)
 	if err != nil {
 		return "", errp.WithStack(err)
 	}
@@ -248,17 +329,18 @@ func DownloadCert(server string, dialer proxy.Dialer) (string, error) {
 
 // CheckElectrumServer checks if a tls connection can be established with the electrum server, and
 // whether the server is an electrum server.
-func CheckElectrumServer(serverInfo *config.ServerInfo, log *logrus.Entry, dialer proxy.Dialer) error {
+func CheckElectrumServer(
+	ctx context.Context, serverInfo *config.ServerInfo, log *logrus.Entry, dialer proxy.Dialer) error {
 	client, err := electrum.Connect(&electrum.Options{
 		SoftwareVersion: softwareVersion,
 		MethodTimeout:   30 * time.Second,
 		PingInterval:    -1,
 		Dial: func() (net.Conn, error) {
-			return establishConnection(serverInfo, dialer)
+			return establishConnection(ctx, serverInfo, dialer)
 		},
 	})
 	if err != nil {
-		return err
+		return errp.WithCode(err, ErrConnectFailed, errp.Context{"host": serverInfo.Server})
 	}
 	client.Close()
 	return nil
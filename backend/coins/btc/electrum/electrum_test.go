@@ -15,6 +15,7 @@
 package electrum
 
 import (
+	"context"
 	"crypto/tls"
 	"io"
 	"net"
@@ -59,7 +60,7 @@ func TestDownloadCert(t *testing.T) {
 			// Run the test.
 			done := make(chan struct{})
 			go func() {
-				cert, err := DownloadCert(testcase.targetServer, dialer)
+				cert, err := DownloadCert(context.Background(), testcase.targetServer, dialer)
 				require.NoError(t, err, "DownloadCert")
 				expected, actual := strings.TrimSpace(test.TCPServerCertPub), strings.TrimSpace(cert)
 				assert.Equal(t, expected, actual, "DownloadCert")
@@ -113,7 +114,7 @@ func TestEstablishConnectionTLS(t *testing.T) {
 			}
 			done := make(chan struct{})
 			go func() {
-				conn, err := establishConnection(info, dialer)
+				conn, err := establishConnection(context.Background(), info, dialer)
 				require.NoError(t, err, "establishConnection")
 				conn.Write([]byte("hello"))
 				var buf = make([]byte, 5)
@@ -15,9 +15,12 @@
 package electrum
 
 import (
+	"context"
 	"sync"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/block-client-go/electrum/types"
 	"github.com/BitBoxSwiss/block-client-go/failover"
 	"github.com/btcsuite/btcd/btcutil"
@@ -30,28 +33,95 @@ import (
 // servers are tried again. Subscriptions are automatically re-subscribed on new servers.
 type failoverClient struct {
 	failover *failover.Failover[*client]
+	servers  []string
 
 	connectionError                   error
 	onConnectionErrorChangedCallbacks []func(error)
-	// covers connectionError and onConnectionErrorChangedCallbacks.
+	// activeServer is the name of the currently connected server, or "" if not connected.
+	activeServer string
+	// covers connectionError, onConnectionErrorChangedCallbacks and activeServer.
 	mu sync.RWMutex
 }
 
+// call is like failover.Call, but returns with the context error when ctx is done before a result
+// is available, e.g. while all servers are unreachable and the failover client is waiting to retry.
+// Connecting can't be canceled, so the failover client keeps trying in the background, but f is not
+// called anymore once ctx is done.
+func call[R any](ctx context.Context, f *failoverClient, do func(c *client) (R, error)) (R, error) {
+	type result struct {
+		value R
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer crashreport.Recover()
+		value, err := failover.Call(f.failover, func(c *client) (R, error) {
+			if err := ctx.Err(); err != nil {
+				var empty R
+				return empty, err
+			}
+			return do(c)
+		})
+		done <- result{value: value, err: err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var empty R
+		return empty, errp.WithStack(ctx.Err())
+	}
+}
+
 // newFailoverClient creates a new failover client.
 func newFailoverClient(opts *failover.Options[*client]) *failoverClient {
+	servers := make([]string, len(opts.Servers))
+	for i, server := range opts.Servers {
+		servers[i] = server.Name
+	}
 	return &failoverClient{
 		failover:                          failover.New[*client](opts),
+		servers:                           servers,
 		onConnectionErrorChangedCallbacks: []func(error){},
 	}
 }
 
+func (f *failoverClient) setActiveServer(server string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.activeServer = server
+}
+
+// clearActiveServer unsets the active server if it is the given server. The disconnect of a server
+// can be reported after the connect of the next server.
+func (f *failoverClient) clearActiveServer(server string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.activeServer == server {
+		f.activeServer = ""
+	}
+}
+
+// Status implements StatusReporter.
+func (f *failoverClient) Status() *Status {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return &Status{
+		Servers:      f.servers,
+		ActiveServer: f.activeServer,
+	}
+}
+
 func (f *failoverClient) setConnectionError(err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if err != f.connectionError {
 		f.connectionError = err
 		for _, callback := range f.onConnectionErrorChangedCallbacks {
-			go callback(err)
+			go func() {
+				defer crashreport.Recover()
+				callback(err)
+			}()
 		}
 	}
 }
@@ -68,21 +138,23 @@ func (f *failoverClient) RegisterOnConnectionErrorChangedEvent(callback func(err
 	f.onConnectionErrorChangedCallbacks = append(f.onConnectionErrorChangedCallbacks, callback)
 }
 
-func (f *failoverClient) EstimateFee(number int) (btcutil.Amount, error) {
-	return failover.Call(f.failover, func(c *client) (btcutil.Amount, error) {
-		return c.EstimateFee(number)
+func (f *failoverClient) EstimateFee(ctx context.Context, number int) (btcutil.Amount, error) {
+	return call(ctx, f, func(c *client) (btcutil.Amount, error) {
+		return c.EstimateFee(ctx, number)
 	})
 }
 
-func (f *failoverClient) GetMerkle(txHash chainhash.Hash, height int) (*blockchain.GetMerkleResult, error) {
-	return failover.Call(f.failover, func(c *client) (*blockchain.GetMerkleResult, error) {
-		return c.GetMerkle(txHash, height)
+func (f *failoverClient) GetMerkle(
+	ctx context.Context, txHash chainhash.Hash, height int) (*blockchain.GetMerkleResult, error) {
+	return call(ctx, f, func(c *client) (*blockchain.GetMerkleResult, error) {
+		return c.GetMerkle(ctx, txHash, height)
 	})
 }
 
-func (f *failoverClient) Headers(startHeight int, count int) (*blockchain.HeadersResult, error) {
-	return failover.Call(f.failover, func(c *client) (*blockchain.HeadersResult, error) {
-		return c.Headers(startHeight, count)
+func (f *failoverClient) Headers(
+	ctx context.Context, startHeight int, count int) (*blockchain.HeadersResult, error) {
+	return call(ctx, f, func(c *client) (*blockchain.HeadersResult, error) {
+		return c.Headers(ctx, startHeight, count)
 	})
 }
 
@@ -103,15 +175,16 @@ func (f *failoverClient) HeadersSubscribe(result func(header *types.Header)) {
 		})
 }
 
-func (f *failoverClient) RelayFee() (btcutil.Amount, error) {
-	return failover.Call(f.failover, func(c *client) (btcutil.Amount, error) {
-		return c.RelayFee()
+func (f *failoverClient) RelayFee(ctx context.Context) (btcutil.Amount, error) {
+	return call(ctx, f, func(c *client) (btcutil.Amount, error) {
+		return c.RelayFee(ctx)
 	})
 }
 
-func (f *failoverClient) ScriptHashGetHistory(scriptHashHex blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
-	return failover.Call(f.failover, func(c *client) (blockchain.TxHistory, error) {
-		return c.ScriptHashGetHistory(scriptHashHex)
+func (f *failoverClient) ScriptHashGetHistory(
+	ctx context.Context, scriptHashHex blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
+	return call(ctx, f, func(c *client) (blockchain.TxHistory, error) {
+		return c.ScriptHashGetHistory(ctx, scriptHashHex)
 	})
 }
 
@@ -143,16 +216,29 @@ func (f *failoverClient) ScriptHashSubscribe(
 		})
 }
 
-func (f *failoverClient) TransactionBroadcast(transaction *wire.MsgTx) error {
-	_, err := failover.Call(f.failover, func(c *client) (struct{}, error) {
-		return struct{}{}, c.TransactionBroadcast(transaction)
+func (f *failoverClient) TransactionBroadcast(ctx context.Context, transaction *wire.MsgTx) error {
+	_, err := call(ctx, f, func(c *client) (struct{}, error) {
+		return struct{}{}, c.TransactionBroadcast(ctx, transaction)
 	})
 	return err
 }
 
-func (f *failoverClient) TransactionGet(txHash chainhash.Hash) (*wire.MsgTx, error) {
-	return failover.Call(f.failover, func(c *client) (*wire.MsgTx, error) {
-		return c.TransactionGet(txHash)
+func (f *failoverClient) TransactionGet(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	return call(ctx, f, func(c *client) (*wire.MsgTx, error) {
+		return c.TransactionGet(ctx, txHash)
+	})
+}
+
+// switchServer disconnects from the current server and connects to the next one. It blocks until
+// the next server is connected.
+func (f *failoverClient) switchServer(reason error) {
+	switched := false
+	_, _ = failover.Call(f.failover, func(*client) (struct{}, error) {
+		if !switched {
+			switched = true
+			return struct{}{}, failover.NewFailoverError(reason)
+		}
+		return struct{}{}, nil
 	})
 }
 
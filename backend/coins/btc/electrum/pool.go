@@ -0,0 +1,313 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package electrum
+
+import (
+	"context"
+	"hash/crc32"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/block-client-go/electrum/types"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// poolSize is the maximum number of connections to different servers per coin.
+	poolSize = 3
+	// maxTipLag is the number of blocks the tip of a connection can lag behind the highest tip of
+	// the pool before the connection switches to another server.
+	maxTipLag = 2
+)
+
+// poolMember is a connection of a pool, usually a *failoverClient.
+type poolMember interface {
+	blockchain.Interface
+	StatusReporter
+	// switchServer disconnects from the current server and connects to the next one.
+	switchServer(reason error)
+}
+
+// pool is an Electrum client backed by several connections, each starting with a different server.
+// Address subscriptions are spread across the connections by script hash, so the load is shared and
+// no single server sees all addresses. All other calls use the first connection, the primary.
+//
+// The tip heights reported by the connections are cross-checked, and a connection whose server
+// lags behind switches to another server.
+type pool struct {
+	members []poolMember
+	log     *logrus.Entry
+	// monitor measures the servers. nil if the servers are not measured.
+	monitor *serverMonitor
+
+	// tips are the tip heights last reported by each member, 0 if unknown.
+	tips []int
+	// switching is true for members currently switching to another server.
+	switching                         []bool
+	connectionError                   error
+	onConnectionErrorChangedCallbacks []func(error)
+	// covers tips, switching, connectionError and onConnectionErrorChangedCallbacks.
+	mu sync.Mutex
+}
+
+// NewElectrumConnectionPool connects to up to three of the given servers at the same time and
+// returns a client spreading the requests across them. Each connection fails over to the next
+// server if its server fails. If there is only one server, a single connection is made, like
+// NewElectrumConnection().
+//
+// Before connecting, the latency and tip of all servers are measured, and the connections start
+// with the fastest servers which agree on the tip. If no server can be measured quickly, e.g.
+// when offline, the first server is chosen randomly to spread the load. The servers are measured
+// again periodically, see ServerMeasurements().
+func NewElectrumConnectionPool(
+	serverInfos []*config.ServerInfo, log *logrus.Entry, dialer proxy.Dialer) blockchain.Interface {
+	size := min(poolSize, len(serverInfos))
+	if size <= 1 {
+		return NewElectrumConnection(serverInfos, false, log, dialer)
+	}
+	monitor := newServerMonitor(
+		serverInfos,
+		func(ctx context.Context, serverInfo *config.ServerInfo) (time.Duration, int, error) {
+			return measureServer(ctx, serverInfo, dialer)
+		},
+		log.WithField("group", "electrum"),
+	)
+	monitor.measureAll(initialMeasureTimeout)
+	order := monitor.ranked()
+	if order == nil {
+		start := rand.Intn(len(serverInfos)) //nolint:gosec // Only used for load balancing.
+		order = make([]int, len(serverInfos))
+		for i := range order {
+			order[i] = (start + i) % len(serverInfos)
+		}
+	}
+	orderedServerInfos := make([]*config.ServerInfo, len(order))
+	for i, index := range order {
+		orderedServerInfos[i] = serverInfos[index]
+	}
+	members := make([]poolMember, size)
+	for i := range members {
+		members[i] = newElectrumConnection(
+			orderedServerInfos,
+			func() int { return i },
+			log.WithField("connection", i),
+			dialer,
+		)
+	}
+	p := newPool(members, log)
+	p.monitor = monitor
+	monitor.start()
+	return p
+}
+
+func newPool(members []poolMember, log *logrus.Entry) *pool {
+	p := &pool{
+		members:                           members,
+		log:                               log.WithField("group", "electrum"),
+		tips:                              make([]int, len(members)),
+		switching:                         make([]bool, len(members)),
+		onConnectionErrorChangedCallbacks: []func(error){},
+	}
+	for i, member := range members {
+		member.RegisterOnConnectionErrorChangedEvent(func(error) {
+			p.updateConnectionError()
+		})
+		member.HeadersSubscribe(func(header *types.Header) {
+			p.onTip(i, header.Height)
+		})
+	}
+	p.updateConnectionError()
+	return p
+}
+
+func (p *pool) primary() poolMember {
+	return p.members[0]
+}
+
+// member returns the member handling the given address. Status notifications and history of an
+// address always come from the same member, so they are consistent.
+func (p *pool) member(scriptHashHex blockchain.ScriptHashHex) poolMember {
+	return p.members[crc32.ChecksumIEEE([]byte(scriptHashHex))%uint32(len(p.members))]
+}
+
+// onTip records the tip reported by a member and lets members lagging behind the highest tip
+// switch to another server.
+func (p *pool) onTip(index int, height int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tips[index] = height
+	highest := 0
+	for _, tip := range p.tips {
+		highest = max(highest, tip)
+	}
+	for i, tip := range p.tips {
+		if tip == 0 || highest-tip <= maxTipLag || p.switching[i] {
+			continue
+		}
+		reason := errp.Newf("tip %d lags %d blocks behind the other servers", tip, highest-tip)
+		p.log.
+			WithField("server", p.members[i].Status().ActiveServer).
+			WithError(reason).
+			Warning("switching to another server")
+		p.switching[i] = true
+		go func() {
+			defer crashreport.Recover()
+			p.members[i].switchServer(reason)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.switching[i] = false
+			// The tip of the new server is reported by the renewed subscription.
+			p.tips[i] = 0
+		}()
+	}
+}
+
+// updateConnectionError sets the connection error to the error of the first member with an error.
+// The pool is offline if any member is, as the addresses of that member can't be synced.
+func (p *pool) updateConnectionError() {
+	var err error
+	for _, member := range p.members {
+		if memberErr := member.ConnectionError(); memberErr != nil {
+			err = memberErr
+			break
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == p.connectionError {
+		return
+	}
+	p.connectionError = err
+	for _, callback := range p.onConnectionErrorChangedCallbacks {
+		go func() {
+			defer crashreport.Recover()
+			callback(err)
+		}()
+	}
+}
+
+// Status implements StatusReporter.
+func (p *pool) Status() *Status {
+	status := p.primary().Status()
+	status.ActiveServers = make([]string, len(p.members))
+	for i, member := range p.members {
+		status.ActiveServers[i] = member.Status().ActiveServer
+	}
+	return status
+}
+
+// ServerMeasurements implements ServerMeasurementsReporter.
+func (p *pool) ServerMeasurements() []*ServerMeasurement {
+	if p.monitor == nil {
+		return []*ServerMeasurement{}
+	}
+	measurements := p.monitor.ServerMeasurements()
+	activeServers := p.Status().ActiveServers
+	for _, measurement := range measurements {
+		measurement.Active = slices.Contains(activeServers, measurement.Server)
+	}
+	return measurements
+}
+
+// ConnectionError implements blockchain.Interface.
+func (p *pool) ConnectionError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.connectionError
+}
+
+// RegisterOnConnectionErrorChangedEvent implements blockchain.Interface.
+func (p *pool) RegisterOnConnectionErrorChangedEvent(callback func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onConnectionErrorChangedCallbacks = append(p.onConnectionErrorChangedCallbacks, callback)
+}
+
+// ScriptHashGetHistory implements blockchain.Interface.
+func (p *pool) ScriptHashGetHistory(
+	ctx context.Context, scriptHashHex blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
+	return p.member(scriptHashHex).ScriptHashGetHistory(ctx, scriptHashHex)
+}
+
+// ScriptHashSubscribe implements blockchain.Interface.
+func (p *pool) ScriptHashSubscribe(
+	setupAndTeardown func() func(),
+	scriptHashHex blockchain.ScriptHashHex,
+	result func(status string)) {
+	p.member(scriptHashHex).ScriptHashSubscribe(setupAndTeardown, scriptHashHex, result)
+}
+
+// HeadersSubscribe implements blockchain.Interface.
+func (p *pool) HeadersSubscribe(result func(*types.Header)) {
+	p.primary().HeadersSubscribe(result)
+}
+
+// TransactionGet implements blockchain.Interface.
+func (p *pool) TransactionGet(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	return p.primary().TransactionGet(ctx, txHash)
+}
+
+// TransactionBroadcast implements blockchain.Interface.
+func (p *pool) TransactionBroadcast(ctx context.Context, transaction *wire.MsgTx) error {
+	return p.primary().TransactionBroadcast(ctx, transaction)
+}
+
+// RelayFee implements blockchain.Interface.
+func (p *pool) RelayFee(ctx context.Context) (btcutil.Amount, error) {
+	return p.primary().RelayFee(ctx)
+}
+
+// EstimateFee implements blockchain.Interface.
+func (p *pool) EstimateFee(ctx context.Context, number int) (btcutil.Amount, error) {
+	return p.primary().EstimateFee(ctx, number)
+}
+
+// Headers implements blockchain.Interface.
+func (p *pool) Headers(ctx context.Context, startHeight int, count int) (*blockchain.HeadersResult, error) {
+	return p.primary().Headers(ctx, startHeight, count)
+}
+
+// GetMerkle implements blockchain.Interface.
+func (p *pool) GetMerkle(
+	ctx context.Context, txHash chainhash.Hash, height int) (*blockchain.GetMerkleResult, error) {
+	return p.primary().GetMerkle(ctx, txHash, height)
+}
+
+// ManualReconnect implements blockchain.Interface.
+func (p *pool) ManualReconnect() {
+	for _, member := range p.members {
+		member.ManualReconnect()
+	}
+}
+
+// Close implements blockchain.Interface.
+func (p *pool) Close() {
+	if p.monitor != nil {
+		p.monitor.close()
+	}
+	for _, member := range p.members {
+		member.Close()
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package electrum
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain/mocks"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/BitBoxSwiss/block-client-go/electrum/types"
+	"github.com/stretchr/testify/require"
+)
+
+var errConnectionLost = errors.New("connection lost")
+
+type fakeMember struct {
+	*mocks.BlockchainMock
+	name string
+
+	mu              sync.Mutex
+	connectionError error
+	onHeader        func(*types.Header)
+	onErrorChanged  func(error)
+	subscribed      []blockchain.ScriptHashHex
+	switched        int
+}
+
+func newFakeMember(name string) *fakeMember {
+	member := &fakeMember{name: name}
+	member.BlockchainMock = &mocks.BlockchainMock{
+		MockHeadersSubscribe: func(result func(*types.Header)) {
+			member.mu.Lock()
+			defer member.mu.Unlock()
+			member.onHeader = result
+		},
+		MockScriptHashSubscribe: func(_ func() func(), scriptHashHex blockchain.ScriptHashHex, _ func(string)) {
+			member.mu.Lock()
+			defer member.mu.Unlock()
+			member.subscribed = append(member.subscribed, scriptHashHex)
+		},
+		MockConnectionError: func() error {
+			member.mu.Lock()
+			defer member.mu.Unlock()
+			return member.connectionError
+		},
+		MockRegisterOnConnectionErrorChangedEvent: func(callback func(error)) {
+			member.onErrorChanged = callback
+		},
+	}
+	return member
+}
+
+func (member *fakeMember) Status() *Status {
+	return &Status{Servers: []string{"a", "b", "c"}, ActiveServer: member.name}
+}
+
+func (member *fakeMember) switchServer(error) {
+	member.mu.Lock()
+	defer member.mu.Unlock()
+	member.switched++
+}
+
+func (member *fakeMember) tip(height int) {
+	member.onHeader(&types.Header{Height: height})
+}
+
+func (member *fakeMember) setConnectionError(err error) {
+	member.mu.Lock()
+	member.connectionError = err
+	member.mu.Unlock()
+	member.onErrorChanged(err)
+}
+
+func newTestPool() (*pool, []*fakeMember) {
+	fakes := []*fakeMember{newFakeMember("a"), newFakeMember("b"), newFakeMember("c")}
+	members := make([]poolMember, len(fakes))
+	for i, fake := range fakes {
+		members[i] = fake
+	}
+	return newPool(members, logging.Get().WithGroup("pool_test")), fakes
+}
+
+func TestPoolScriptHashSubscribe(t *testing.T) {
+	p, fakes := newTestPool()
+	for i := 0; i < 30; i++ {
+		scriptHashHex := blockchain.ScriptHashHex(fmt.Sprintf("%064x", i))
+		p.ScriptHashSubscribe(func() func() { return func() {} }, scriptHashHex, func(string) {})
+		// The same address always uses the same connection.
+		require.Equal(t, p.member(scriptHashHex), p.member(scriptHashHex))
+	}
+	total := 0
+	for _, fake := range fakes {
+		// The addresses are spread across all connections.
+		require.NotEmpty(t, fake.subscribed)
+		for _, scriptHashHex := range fake.subscribed {
+			require.Equal(t, poolMember(fake), p.member(scriptHashHex))
+		}
+		total += len(fake.subscribed)
+	}
+	require.Equal(t, 30, total)
+}
+
+func TestPoolTipCrossCheck(t *testing.T) {
+	p, fakes := newTestPool()
+	fakes[0].tip(100)
+	fakes[1].tip(99)
+	fakes[2].tip(98)
+	// Small differences are expected while a new block propagates.
+	for _, fake := range fakes {
+		require.Equal(t, 0, fake.switched)
+	}
+
+	fakes[0].tip(101)
+	require.Eventually(t, func() bool {
+		fakes[2].mu.Lock()
+		defer fakes[2].mu.Unlock()
+		return fakes[2].switched == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 0, fakes[0].switched)
+	require.Equal(t, 0, fakes[1].switched)
+
+	require.Equal(t, []string{"a", "b", "c"}, p.Status().ActiveServers)
+	require.Equal(t, "a", p.Status().ActiveServer)
+}
+
+func TestPoolConnectionError(t *testing.T) {
+	p, fakes := newTestPool()
+	require.NoError(t, p.ConnectionError())
+
+	changed := make(chan error, 10)
+	p.RegisterOnConnectionErrorChangedEvent(func(err error) { changed <- err })
+
+	// The pool is offline if any connection is offline.
+	fakes[1].setConnectionError(errConnectionLost)
+	require.Equal(t, errConnectionLost, <-changed)
+	require.Equal(t, errConnectionLost, p.ConnectionError())
+
+	fakes[1].setConnectionError(nil)
+	require.NoError(t, <-changed)
+	require.NoError(t, p.ConnectionError())
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package electrum
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/block-client-go/electrum"
+	"github.com/BitBoxSwiss/block-client-go/electrum/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// measureInterval is the time between two measurements of all servers.
+	measureInterval = 10 * time.Minute
+	// measureTimeout limits how long measuring a single server can take.
+	measureTimeout = 10 * time.Second
+	// initialMeasureTimeout limits the measurement made before connecting, which delays the
+	// connection. Servers which are slower than this are not preferred anyway.
+	initialMeasureTimeout = 2 * time.Second
+)
+
+// ServerMeasurement is the latency and tip height of an Electrum server.
+type ServerMeasurement struct {
+	Server string `json:"server"`
+	// LatencyMs is the round trip time of a request to the server in milliseconds.
+	LatencyMs int64 `json:"latencyMs"`
+	TipHeight int   `json:"tipHeight"`
+	// InConsensus is true if the tip of the server lags at most maxTipLag blocks behind the highest
+	// tip of all measured servers.
+	InConsensus bool `json:"inConsensus"`
+	// Active is true if the server is currently in use.
+	Active bool `json:"active"`
+	// Error is set if the server could not be measured. LatencyMs and TipHeight are 0 then.
+	Error string `json:"error,omitempty"`
+	// Time is when the server was measured, zero if it was not measured yet.
+	Time time.Time `json:"time"`
+}
+
+// ServerMeasurementsReporter is implemented by blockchain backends measuring their Electrum servers.
+type ServerMeasurementsReporter interface {
+	// ServerMeasurements returns the last measurement of each configured server, in the configured
+	// order.
+	ServerMeasurements() []*ServerMeasurement
+}
+
+// measureFunc measures the latency and tip height of a server.
+type measureFunc func(ctx context.Context, serverInfo *config.ServerInfo) (time.Duration, int, error)
+
+// measureServer connects to the server and measures the round trip time of requesting the tip.
+// Connecting is not included, as it takes several round trips depending on TLS and the proxy.
+func measureServer(
+	ctx context.Context, serverInfo *config.ServerInfo, dialer proxy.Dialer) (time.Duration, int, error) {
+	client, err := electrum.Connect(&electrum.Options{
+		SoftwareVersion: softwareVersion,
+		MethodTimeout:   measureTimeout,
+		PingInterval:    -1,
+		Dial: func() (net.Conn, error) {
+			return establishConnection(ctx, serverInfo, dialer)
+		},
+	})
+	if err != nil {
+		return 0, 0, errp.WithStack(err)
+	}
+	defer client.Close()
+
+	type result struct {
+		header *types.Header
+		err    error
+	}
+	// The callback is called again for each new block, which is ignored.
+	results := make(chan result, 1)
+	start := time.Now()
+	client.HeadersSubscribe(ctx, func(header *types.Header, err error) {
+		select {
+		case results <- result{header: header, err: err}:
+		default:
+		}
+	})
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return 0, 0, errp.WithStack(result.err)
+		}
+		return time.Since(start), result.header.Height, nil
+	case <-ctx.Done():
+		return 0, 0, errp.WithStack(ctx.Err())
+	}
+}
+
+// serverMonitor periodically measures the latency and tip height of a list of servers, so the
+// fastest servers which agree on the tip can be preferred.
+type serverMonitor struct {
+	serverInfos []*config.ServerInfo
+	measure     measureFunc
+	log         *logrus.Entry
+
+	// measurements are in the same order as serverInfos.
+	measurements []*ServerMeasurement
+	mu           sync.RWMutex
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func newServerMonitor(serverInfos []*config.ServerInfo, measure measureFunc, log *logrus.Entry) *serverMonitor {
+	measurements := make([]*ServerMeasurement, len(serverInfos))
+	for i, serverInfo := range serverInfos {
+		measurements[i] = &ServerMeasurement{Server: serverInfo.Server}
+	}
+	return &serverMonitor{
+		serverInfos:  serverInfos,
+		measure:      measure,
+		log:          log,
+		measurements: measurements,
+		quit:         make(chan struct{}),
+	}
+}
+
+// measureAll measures all servers in parallel and waits for the results, each limited by timeout.
+func (monitor *serverMonitor) measureAll(timeout time.Duration) {
+	now := time.Now()
+	measurements := make([]*ServerMeasurement, len(monitor.serverInfos))
+	var wg sync.WaitGroup
+	for i, serverInfo := range monitor.serverInfos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			measurement := &ServerMeasurement{Server: serverInfo.Server, Time: now}
+			latency, tipHeight, err := monitor.measure(ctx, serverInfo)
+			if err != nil {
+				monitor.log.WithField("server", serverInfo.Server).WithError(err).
+					Info("could not measure server")
+				measurement.Error = err.Error()
+			} else {
+				measurement.LatencyMs = latency.Milliseconds()
+				measurement.TipHeight = tipHeight
+			}
+			measurements[i] = measurement
+		}()
+	}
+	wg.Wait()
+
+	highest := 0
+	for _, measurement := range measurements {
+		highest = max(highest, measurement.TipHeight)
+	}
+	for _, measurement := range measurements {
+		measurement.InConsensus = measurement.Error == "" && highest-measurement.TipHeight <= maxTipLag
+	}
+
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	monitor.measurements = measurements
+}
+
+// start measures all servers every measureInterval until the monitor is closed.
+func (monitor *serverMonitor) start() {
+	go func() {
+		defer crashreport.Recover()
+		ticker := time.NewTicker(measureInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				monitor.measureAll(measureTimeout)
+			case <-monitor.quit:
+				return
+			}
+		}
+	}()
+}
+
+func (monitor *serverMonitor) close() {
+	monitor.closeOnce.Do(func() { close(monitor.quit) })
+}
+
+// ranked returns the indices of the servers, best first: the servers in consensus ordered by
+// latency, then the reachable servers which are not in consensus ordered by latency, then the
+// unreachable servers in the configured order. nil is returned if no server could be reached.
+func (monitor *serverMonitor) ranked() []int {
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	measurements := monitor.measurements
+	indices := make([]int, len(measurements))
+	reachable := false
+	for i, measurement := range measurements {
+		indices[i] = i
+		if !measurement.Time.IsZero() && measurement.Error == "" {
+			reachable = true
+		}
+	}
+	if !reachable {
+		return nil
+	}
+	rank := func(measurement *ServerMeasurement) int {
+		switch {
+		case measurement.InConsensus:
+			return 0
+		case measurement.Error == "":
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		a, b := measurements[indices[i]], measurements[indices[j]]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if rank(a) == 2 {
+			return false
+		}
+		return a.LatencyMs < b.LatencyMs
+	})
+	return indices
+}
+
+// ServerMeasurements implements ServerMeasurementsReporter. Active is not set.
+func (monitor *serverMonitor) ServerMeasurements() []*ServerMeasurement {
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	result := make([]*ServerMeasurement, len(monitor.measurements))
+	for i, measurement := range monitor.measurements {
+		measurementCopy := *measurement
+		result[i] = &measurementCopy
+	}
+	return result
+}
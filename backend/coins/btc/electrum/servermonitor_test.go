@@ -0,0 +1,113 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package electrum
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeServer struct {
+	latency   time.Duration
+	tipHeight int
+	err       error
+}
+
+func newTestMonitor(servers map[string]fakeServer, names ...string) *serverMonitor {
+	serverInfos := make([]*config.ServerInfo, len(names))
+	for i, name := range names {
+		serverInfos[i] = &config.ServerInfo{Server: name}
+	}
+	return newServerMonitor(
+		serverInfos,
+		func(_ context.Context, serverInfo *config.ServerInfo) (time.Duration, int, error) {
+			server := servers[serverInfo.Server]
+			return server.latency, server.tipHeight, server.err
+		},
+		logging.Get().WithGroup("electrum_test"),
+	)
+}
+
+func TestServerMonitor(t *testing.T) {
+	servers := map[string]fakeServer{
+		"slow":    {latency: 300 * time.Millisecond, tipHeight: 100},
+		"fast":    {latency: 50 * time.Millisecond, tipHeight: 99},
+		"lagging": {latency: 10 * time.Millisecond, tipHeight: 90},
+		"down":    {err: errConnectionLost},
+		"medium":  {latency: 100 * time.Millisecond, tipHeight: 98},
+	}
+	monitor := newTestMonitor(servers, "slow", "fast", "lagging", "down", "medium")
+	defer monitor.close()
+
+	// Nothing is known before the first measurement.
+	require.Nil(t, monitor.ranked())
+	for _, measurement := range monitor.ServerMeasurements() {
+		require.True(t, measurement.Time.IsZero())
+	}
+
+	monitor.measureAll(time.Second)
+	measurements := monitor.ServerMeasurements()
+	require.Len(t, measurements, 5)
+	require.Equal(t, "slow", measurements[0].Server)
+	require.Equal(t, int64(300), measurements[0].LatencyMs)
+	require.Equal(t, 100, measurements[0].TipHeight)
+	require.True(t, measurements[0].InConsensus)
+	require.True(t, measurements[4].InConsensus)
+	require.False(t, measurements[2].InConsensus)
+	require.False(t, measurements[3].InConsensus)
+	require.Equal(t, errConnectionLost.Error(), measurements[3].Error)
+
+	// fast, medium, slow are in consensus, then lagging, then down.
+	require.Equal(t, []int{1, 4, 0, 2, 3}, monitor.ranked())
+
+	// Measurements are copies.
+	measurements[0].LatencyMs = 0
+	require.Equal(t, int64(300), monitor.ServerMeasurements()[0].LatencyMs)
+}
+
+func TestServerMonitorUnreachable(t *testing.T) {
+	servers := map[string]fakeServer{
+		"a": {err: errConnectionLost},
+		"b": {err: errConnectionLost},
+	}
+	monitor := newTestMonitor(servers, "a", "b")
+	defer monitor.close()
+	monitor.measureAll(time.Second)
+	require.Nil(t, monitor.ranked())
+}
+
+func TestPoolServerMeasurements(t *testing.T) {
+	servers := map[string]fakeServer{
+		"a": {latency: 50 * time.Millisecond, tipHeight: 100},
+		"b": {latency: 20 * time.Millisecond, tipHeight: 100},
+		"c": {latency: 30 * time.Millisecond, tipHeight: 100},
+	}
+	p := newPool([]poolMember{newFakeMember("a"), newFakeMember("b")}, logging.Get().WithGroup("electrum_test"))
+	require.Empty(t, p.ServerMeasurements())
+
+	p.monitor = newTestMonitor(servers, "a", "b", "c")
+	p.monitor.measureAll(time.Second)
+	measurements := p.ServerMeasurements()
+	require.Len(t, measurements, 3)
+	require.True(t, measurements[0].Active)
+	require.True(t, measurements[1].Active)
+	require.False(t, measurements[2].Active)
+	p.Close()
+}
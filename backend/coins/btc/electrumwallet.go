@@ -0,0 +1,110 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/ltc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// electrumSeedVersion is the version of the wallet file format written by ElectrumWalletFile().
+// Electrum upgrades files of older versions when opening them, so an old version which all
+// Electrum 4 releases can read is used.
+const electrumSeedVersion = 18
+
+// electrumXPubPrefixes are the extended public key versions Electrum uses to tell the script type
+// of a wallet, for mainnet and testnet.
+var electrumXPubPrefixes = map[signing.ScriptType][2]string{
+	signing.ScriptTypeP2PKH:      {"xpub", "tpub"},
+	signing.ScriptTypeP2WPKHP2SH: {"ypub", "upub"},
+	signing.ScriptTypeP2WPKH:     {"zpub", "vpub"},
+}
+
+type electrumKeystore struct {
+	Type            string  `json:"type"`
+	XPub            string  `json:"xpub"`
+	XPrv            *string `json:"xprv"`
+	Derivation      string  `json:"derivation"`
+	RootFingerprint string  `json:"root_fingerprint"`
+	PwHashVersion   int     `json:"pw_hash_version"`
+}
+
+type electrumWallet struct {
+	Keystore      electrumKeystore  `json:"keystore"`
+	WalletType    string            `json:"wallet_type"`
+	UseEncryption bool              `json:"use_encryption"`
+	SeedVersion   int               `json:"seed_version"`
+	Labels        map[string]string `json:"labels"`
+}
+
+// ElectrumWalletFile returns a watch-only Electrum wallet file for the signing configuration at
+// the given index of Info().SigningConfigurations, so the account can be opened in Electrum. The
+// transaction notes are included as labels. Only single-sig configurations of script types
+// supported by Electrum can be exported, i.e. not Taproot.
+func (account *Account) ElectrumWalletFile(signingConfigIndex int) ([]byte, error) {
+	info := account.Info()
+	if info == nil {
+		return nil, errp.New("account not initialized")
+	}
+	if signingConfigIndex < 0 || signingConfigIndex >= len(info.SigningConfigurations) {
+		return nil, errp.New("invalid signing configuration index")
+	}
+	signingConfiguration := info.SigningConfigurations[signingConfigIndex]
+	if signingConfiguration.BitcoinSimple == nil {
+		return nil, errp.New("only single-sig accounts can be exported to Electrum")
+	}
+	prefixes, ok := electrumXPubPrefixes[signingConfiguration.ScriptType()]
+	if !ok {
+		return nil, errp.Newf("Electrum does not support %s accounts", signingConfiguration.ScriptType())
+	}
+	prefix := prefixes[1]
+	switch account.coin.Net().Net {
+	case chaincfg.MainNetParams.Net, ltc.MainNetParams.Net:
+		prefix = prefixes[0]
+	}
+	xpub, err := signing.EncodeExtendedPublicKey(signingConfiguration.ExtendedPublicKey(), prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{}
+	for txID, note := range account.Notes().Data().TransactionNotes {
+		if note != "" {
+			labels[txID] = note
+		}
+	}
+	wallet := electrumWallet{
+		Keystore: electrumKeystore{
+			Type:            "bip32",
+			XPub:            xpub,
+			Derivation:      signingConfiguration.AbsoluteKeypath().Encode(),
+			RootFingerprint: hex.EncodeToString(signingConfiguration.BitcoinSimple.KeyInfo.RootFingerprint),
+			PwHashVersion:   1,
+		},
+		WalletType:  "standard",
+		SeedVersion: electrumSeedVersion,
+		Labels:      labels,
+	}
+	walletFile, err := json.MarshalIndent(wallet, "", "    ")
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return walletFile, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestElectrumWalletFile(t *testing.T) {
+	account := mockAccount(t, nil)
+	_, err := account.ElectrumWalletFile(0)
+	require.Error(t, err)
+
+	require.NoError(t, account.Initialize())
+	defer account.Close()
+	txID := strings.Repeat("ab", 32)
+	_, err = account.Notes().SetTxNote(txID, "rent")
+	require.NoError(t, err)
+
+	walletFile, err := account.ElectrumWalletFile(0)
+	require.NoError(t, err)
+	var wallet electrumWallet
+	require.NoError(t, json.Unmarshal(walletFile, &wallet))
+	require.Equal(t, "standard", wallet.WalletType)
+	require.Equal(t, electrumSeedVersion, wallet.SeedVersion)
+	require.Equal(t, "bip32", wallet.Keystore.Type)
+	require.Nil(t, wallet.Keystore.XPrv)
+	// Electrum tells the script type of testnet P2WPKH wallets by the vpub version.
+	require.True(t, strings.HasPrefix(wallet.Keystore.XPub, "vpub"))
+	require.Equal(t, "m/84'/1'/0'", wallet.Keystore.Derivation)
+	require.Equal(t, "01020304", wallet.Keystore.RootFingerprint)
+	require.Equal(t, map[string]string{txID: "rent"}, wallet.Labels)
+	require.Contains(t, string(walletFile), `"xprv": null`)
+
+	_, err = account.ElectrumWalletFile(1)
+	require.Error(t, err)
+}
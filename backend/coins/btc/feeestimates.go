@@ -0,0 +1,182 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"context"
+	"math"
+	"net/http"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/util"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+const (
+	// mempoolSpaceFeesURL is the mempool.space recommended fees API, used if mempoolSpaceMirror is
+	// not available.
+	mempoolSpaceFeesURL = "https://mempool.space/api/v1/fees/recommended"
+	// bitcoinerLiveFeesURL is the bitcoiner.live fee estimates API, used if mempool.space is not
+	// available.
+	bitcoinerLiveFeesURL = "https://bitcoiner.live/api/fees/estimates/latest"
+
+	mempoolSpaceFeesMaxResponseSize = 1000
+	// bitcoiner.live returns estimates for many targets, so the response is larger.
+	bitcoinerLiveFeesMaxResponseSize = 100000
+)
+
+// bitcoinerLiveFees contains the bitcoiner.live fee estimates API response
+// (https://bitcoiner.live/doc/api). The estimates are keyed by the confirmation target in minutes.
+type bitcoinerLiveFees struct {
+	Estimates map[string]struct {
+		SatPerVbyte float64 `json:"sat_per_vbyte"`
+	} `json:"estimates"`
+}
+
+// mempoolSpaceFees converts the estimates to the mempool.space format. bitcoiner.live does not
+// estimate targets below 30 minutes, so the fastest fee is the same as the half hour fee.
+func (fees bitcoinerLiveFees) mempoolSpaceFees() (*accounts.MempoolSpaceFees, error) {
+	halfHour, ok := fees.Estimates["30"]
+	if !ok {
+		return nil, errp.New("missing 30 minute estimate")
+	}
+	hour, ok := fees.Estimates["60"]
+	if !ok {
+		return nil, errp.New("missing 60 minute estimate")
+	}
+	halfHourFee := int64(math.Ceil(halfHour.SatPerVbyte))
+	return &accounts.MempoolSpaceFees{
+		FastestFee:  halfHourFee,
+		HalfHourFee: halfHourFee,
+		HourFee:     int64(math.Ceil(hour.SatPerVbyte)),
+	}, nil
+}
+
+// feeEstimateSource is a fee API returning mempool.space-like fee estimates.
+type feeEstimateSource struct {
+	url   string
+	fetch func(ctx context.Context, httpClient *http.Client, url string) (*accounts.MempoolSpaceFees, error)
+}
+
+func fetchMempoolSpaceFees(
+	ctx context.Context, httpClient *http.Client, url string) (*accounts.MempoolSpaceFees, error) {
+	fees := &accounts.MempoolSpaceFees{}
+	if _, err := util.APIGet(ctx, httpClient, url, "", mempoolSpaceFeesMaxResponseSize, fees); err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+func fetchBitcoinerLiveFees(
+	ctx context.Context, httpClient *http.Client, url string) (*accounts.MempoolSpaceFees, error) {
+	var fees bitcoinerLiveFees
+	if _, err := util.APIGet(ctx, httpClient, url, "", bitcoinerLiveFeesMaxResponseSize, &fees); err != nil {
+		return nil, err
+	}
+	return fees.mempoolSpaceFees()
+}
+
+// feeEstimateSources are the fee APIs queried for mainnet BTC, in order of preference. If all of
+// them fail, the fees are estimated using the blockchain backend.
+var feeEstimateSources = []feeEstimateSource{
+	{url: mempoolSpaceMirror, fetch: fetchMempoolSpaceFees},
+	{url: mempoolSpaceFeesURL, fetch: fetchMempoolSpaceFees},
+	{url: bitcoinerLiveFeesURL, fetch: fetchBitcoinerLiveFees},
+}
+
+// fetchMempoolFees returns the fee estimates of the first available source in
+// feeEstimateSources, or nil if none is available. The account http client is used, so the
+// requests go through the proxy if one is configured.
+func (account *Account) fetchMempoolFees(ctx context.Context) *accounts.MempoolSpaceFees {
+	for _, source := range feeEstimateSources {
+		fees, err := source.fetch(ctx, account.httpClient, source.url)
+		if err == nil && fees.HourFee <= 0 {
+			err = errp.New("invalid fee estimates")
+		}
+		if err != nil {
+			account.log.WithError(err).Errorf("Fetching fees from %s failed", source.url)
+			continue
+		}
+		return fees
+	}
+	return nil
+}
+
+// FeeTier is a fee level offered to the user. See the FeeTier* constants.
+type FeeTier string
+
+const (
+	// FeeTierLow is the cheapest fee level, for transactions which are not urgent.
+	FeeTierLow FeeTier = "low"
+	// FeeTierMedium is the default fee level.
+	FeeTierMedium FeeTier = "medium"
+	// FeeTierHigh is the fee level for a fast confirmation.
+	FeeTierHigh FeeTier = "high"
+	// FeeTierCustom means the user supplies the fee rate.
+	FeeTierCustom FeeTier = "custom"
+)
+
+// feeTiers maps the fee targets to the fee tiers. Fee targets not listed here are not offered as
+// a tier.
+var feeTiers = map[accounts.FeeTargetCode]FeeTier{
+	accounts.FeeTargetCodeMempoolHour:     FeeTierLow,
+	accounts.FeeTargetCodeMempoolHalfHour: FeeTierMedium,
+	accounts.FeeTargetCodeMempoolFastest:  FeeTierHigh,
+	accounts.FeeTargetCodeEconomy:         FeeTierLow,
+	accounts.FeeTargetCodeNormal:          FeeTierMedium,
+	accounts.FeeTargetCodeHigh:            FeeTierHigh,
+}
+
+// FeeEstimate is the fee rate of a fee tier.
+type FeeEstimate struct {
+	Tier FeeTier
+	// Code is the fee target the estimate is based on. Empty for the custom tier.
+	Code accounts.FeeTargetCode
+	// Blocks is the expected number of blocks until the transaction confirms. 0 for the custom
+	// tier.
+	Blocks int
+	// FeeRatePerKb is the estimated fee rate. For the custom tier, it is the minimum fee rate that
+	// can be chosen, or nil if unknown.
+	FeeRatePerKb *btcutil.Amount
+}
+
+// FormattedFeeRate returns a string showing the fee rate.
+func (estimate FeeEstimate) FormattedFeeRate() string {
+	return formatFeeRate(estimate.FeeRatePerKb)
+}
+
+// FeeEstimates returns the low, medium and high fee tiers that could be estimated, ordered by
+// ascending priority, followed by the custom tier.
+func (account *Account) FeeEstimates(ctx context.Context) []FeeEstimate {
+	estimates := []FeeEstimate{}
+	for _, feeTarget := range account.feeTargets(ctx) {
+		tier, ok := feeTiers[feeTarget.code]
+		if !ok || feeTarget.feeRatePerKb == nil {
+			continue
+		}
+		estimates = append(estimates, FeeEstimate{
+			Tier:         tier,
+			Code:         feeTarget.code,
+			Blocks:       feeTarget.blocks,
+			FeeRatePerKb: feeTarget.feeRatePerKb,
+		})
+	}
+	custom := FeeEstimate{Tier: FeeTierCustom}
+	if minRelayFeeRate, err := account.getMinRelayFeeRate(ctx); err == nil {
+		custom.FeeRatePerKb = &minRelayFeeRate
+	}
+	return append(estimates, custom)
+}
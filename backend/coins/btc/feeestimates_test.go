@@ -0,0 +1,47 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitcoinerLiveFees(t *testing.T) {
+	var fees bitcoinerLiveFees
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "timestamp": 1700000000,
+  "estimates": {
+    "30": {"sat_per_vbyte": 12.3, "total": {}},
+    "60": {"sat_per_vbyte": 8.0, "total": {}},
+    "120": {"sat_per_vbyte": 5.1, "total": {}}
+  }
+}`), &fees))
+	mempoolFees, err := fees.mempoolSpaceFees()
+	require.NoError(t, err)
+	require.Equal(t, &accounts.MempoolSpaceFees{FastestFee: 13, HalfHourFee: 13, HourFee: 8}, mempoolFees)
+
+	delete(fees.Estimates, "60")
+	_, err = fees.mempoolSpaceFees()
+	require.Error(t, err)
+}
+
+func TestFeeEstimateFormattedFeeRate(t *testing.T) {
+	require.Equal(t, "", FeeEstimate{Tier: FeeTierCustom}.FormattedFeeRate())
+	require.Equal(t, "1.5 sat/vB", FeeEstimate{Tier: FeeTierLow, FeeRatePerKb: amt(1500)}.FormattedFeeRate())
+}
@@ -20,10 +20,13 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,11 +34,15 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/addressbook"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/electrum"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/signmessage"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/etherscan"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/config"
@@ -45,41 +52,64 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	qrcode "github.com/skip2/go-qrcode"
 )
 
 // Handlers provides a web api to the account.
 type Handlers struct {
-	account accounts.Interface
-	log     *logrus.Entry
+	account     accounts.Interface
+	addressBook *addressbook.AddressBook
+	log         *logrus.Entry
 }
 
 // NewHandlers creates a new Handlers instance.
 func NewHandlers(
-	handleFunc func(string, func(*http.Request) (interface{}, error)) *mux.Route, log *logrus.Entry) *Handlers {
-	handlers := &Handlers{log: log}
+	handleFunc func(string, func(*http.Request) (interface{}, error)) *mux.Route,
+	addressBook *addressbook.AddressBook,
+	log *logrus.Entry) *Handlers {
+	handlers := &Handlers{addressBook: addressBook, log: log}
 
 	handleFunc("/init", handlers.postInit).Methods("POST")
 	handleFunc("/status", handlers.getAccountStatus).Methods("GET")
 	handleFunc("/transactions", handlers.ensureAccountInitialized(handlers.getAccountTransactions)).Methods("GET")
 	handleFunc("/transaction", handlers.ensureAccountInitialized(handlers.getAccountTransaction)).Methods("GET")
+	handleFunc("/transaction/{txid}", handlers.ensureAccountInitialized(handlers.getAccountTransactionDetail)).Methods("GET")
 	handleFunc("/export", handlers.ensureAccountInitialized(handlers.postExportTransactions)).Methods("POST")
 	handleFunc("/info", handlers.ensureAccountInitialized(handlers.getAccountInfo)).Methods("GET")
+	handleFunc("/descriptor", handlers.getDescriptor).Methods("GET")
 	handleFunc("/utxos", handlers.ensureAccountInitialized(handlers.getUTXOs)).Methods("GET")
 	handleFunc("/balance", handlers.ensureAccountInitialized(handlers.getAccountBalance)).Methods("GET")
+	handleFunc("/balance-history", handlers.ensureAccountInitialized(handlers.getBalanceHistory)).Methods("GET")
 	handleFunc("/sendtx", handlers.ensureAccountInitialized(handlers.postAccountSendTx)).Methods("POST")
 	handleFunc("/fee-targets", handlers.ensureAccountInitialized(handlers.getAccountFeeTargets)).Methods("GET")
+	handleFunc("/fee-estimates", handlers.ensureAccountInitialized(handlers.getAccountFeeEstimates)).Methods("GET")
 	handleFunc("/tx-proposal", handlers.ensureAccountInitialized(handlers.postAccountTxProposal)).Methods("POST")
+	handleFunc("/psbt-export", handlers.ensureAccountInitialized(handlers.postExportPSBT)).Methods("POST")
+	handleFunc("/psbt-send", handlers.ensureAccountInitialized(handlers.postSendPSBT)).Methods("POST")
+	handleFunc("/bump-fee", handlers.ensureAccountInitialized(handlers.postBumpFee)).Methods("POST")
+	handleFunc("/sweep", handlers.ensureAccountInitialized(handlers.postSweepPrivateKey)).Methods("POST")
+	handleFunc("/consolidation-proposal", handlers.ensureAccountInitialized(handlers.postConsolidationProposal)).Methods("POST")
+	handleFunc("/utxo-frozen", handlers.ensureAccountInitialized(handlers.postSetUTXOFrozen)).Methods("POST")
 	handleFunc("/receive-addresses", handlers.ensureAccountInitialized(handlers.getReceiveAddresses)).Methods("GET")
+	handleFunc("/address-preview", handlers.ensureAccountInitialized(handlers.getAddressPreview)).Methods("GET")
 	handleFunc("/verify-address", handlers.ensureAccountInitialized(handlers.postVerifyAddress)).Methods("POST")
+	handleFunc("/verify-receive-address", handlers.ensureAccountInitialized(handlers.postVerifyReceiveAddress)).Methods("POST")
+	handleFunc("/scan-further", handlers.ensureAccountInitialized(handlers.postScanFurther)).Methods("POST")
 	handleFunc("/verify-extended-public-key", handlers.ensureAccountInitialized(handlers.postVerifyExtendedPublicKey)).Methods("POST")
+	handleFunc("/xpub-export", handlers.ensureAccountInitialized(handlers.postExportExtendedPublicKey)).Methods("POST")
+	handleFunc("/electrum-wallet-export", handlers.ensureAccountInitialized(handlers.postExportElectrumWallet)).Methods("POST")
 	handleFunc("/sign-address", handlers.ensureAccountInitialized(handlers.postSignBTCAddress)).Methods("POST")
+	handleFunc("/sign-message", handlers.ensureAccountInitialized(handlers.postSignMessage)).Methods("POST")
 	handleFunc("/has-secure-output", handlers.ensureAccountInitialized(handlers.getHasSecureOutput)).Methods("GET")
 	handleFunc("/has-payment-request", handlers.ensureAccountInitialized(handlers.getHasPaymentRequest)).Methods("GET")
 	handleFunc("/notes/tx", handlers.ensureAccountInitialized(handlers.postSetTxNote)).Methods("POST")
+	handleFunc("/notes/address", handlers.ensureAccountInitialized(handlers.postSetAddressNote)).Methods("POST")
 	handleFunc("/connect-keystore", handlers.ensureAccountInitialized(handlers.postConnectKeystore)).Methods("POST")
 	handleFunc("/eth-sign-msg", handlers.ensureAccountInitialized(handlers.postEthSignMsg)).Methods("POST")
 	handleFunc("/eth-sign-typed-msg", handlers.ensureAccountInitialized(handlers.postEthSignTypedMsg)).Methods("POST")
 	handleFunc("/eth-sign-wallet-connect-tx", handlers.ensureAccountInitialized(handlers.postEthSignWalletConnectTx)).Methods("POST")
+	handleFunc("/eth-queued-txs", handlers.ensureAccountInitialized(handlers.getEthQueuedTransactions)).Methods("GET")
+	handleFunc("/eth-replace-tx", handlers.ensureAccountInitialized(handlers.postEthReplaceTransaction)).Methods("POST")
 	return handlers
 }
 
@@ -162,16 +192,46 @@ type Transaction struct {
 	Note                     string            `json:"note"`
 
 	// BTC specific fields.
-	VSize        int64           `json:"vsize"`
-	Size         int64           `json:"size"`
-	Weight       int64           `json:"weight"`
-	FeeRatePerKb FormattedAmount `json:"feeRatePerKb"`
+	VSize           int64           `json:"vsize"`
+	Size            int64           `json:"size"`
+	Weight          int64           `json:"weight"`
+	FeeRatePerKb    FormattedAmount `json:"feeRatePerKb"`
+	Unverified      bool            `json:"unverified"`
+	ConflictingTxID string          `json:"conflictingTxID,omitempty"`
 
 	// ETH specific fields
 	Gas   uint64  `json:"gas"`
 	Nonce *uint64 `json:"nonce"`
 }
 
+// TransactionInput is an input of a transaction returned by the /transaction/{txid} endpoint.
+type TransactionInput struct {
+	OutPoint string `json:"outPoint"`
+	// Address and Amount are only set if the input spends a coin of the account.
+	Address string           `json:"address,omitempty"`
+	Amount  *FormattedAmount `json:"amount,omitempty"`
+	Ours    bool             `json:"ours"`
+}
+
+// TransactionOutput is an output of a transaction returned by the /transaction/{txid} endpoint.
+type TransactionOutput struct {
+	Address string          `json:"address"`
+	Amount  FormattedAmount `json:"amount"`
+	Ours    bool            `json:"ours"`
+}
+
+// TransactionDetail is the info returned by the /transaction/{txid} endpoint.
+type TransactionDetail struct {
+	Transaction
+	// Height is the block height the tx is confirmed in, or 0 if unconfirmed (-1 if unconfirmed
+	// with an unconfirmed parent).
+	Height int `json:"height"`
+	// BlockTime is the time of the block the tx is confirmed in, if known.
+	BlockTime *string             `json:"blockTime"`
+	Inputs    []TransactionInput  `json:"inputs"`
+	Outputs   []TransactionOutput `json:"outputs"`
+}
+
 func (handlers *Handlers) ensureAccountInitialized(h func(*http.Request) (interface{}, error)) func(*http.Request) (interface{}, error) {
 	return func(request *http.Request) (interface{}, error) {
 		if handlers.account == nil {
@@ -216,19 +276,17 @@ func (handlers *Handlers) getTxInfoJSON(txInfo *accounts.TransactionData, detail
 		InternalID:               txInfo.InternalID,
 		NumConfirmations:         txInfo.NumConfirmations,
 		NumConfirmationsComplete: txInfo.NumConfirmationsComplete,
-		Type: map[accounts.TxType]string{
-			accounts.TxTypeReceive:  "receive",
-			accounts.TxTypeSend:     "send",
-			accounts.TxTypeSendSelf: "send_to_self",
-		}[txInfo.Type],
-		Status:               txInfo.Status,
-		Amount:               amount,
-		AmountAtTime:         amountAtTime,
-		DeductedAmountAtTime: deductedAmountAtTime,
-		Time:                 formattedTime,
-		Addresses:            addresses,
-		Note:                 handlers.account.TxNote(txInfo.InternalID),
-		Fee:                  feeString,
+		Type:                     txTypeNames[txInfo.Type],
+		Status:                   txInfo.Status,
+		Amount:                   amount,
+		AmountAtTime:             amountAtTime,
+		DeductedAmountAtTime:     deductedAmountAtTime,
+		Time:                     formattedTime,
+		Addresses:                addresses,
+		Note:                     handlers.account.TxNote(txInfo.InternalID),
+		Fee:                      feeString,
+		Unverified:               txInfo.Unverified,
+		ConflictingTxID:          txInfo.ConflictingTxID,
 	}
 
 	if detail {
@@ -249,23 +307,48 @@ func (handlers *Handlers) getTxInfoJSON(txInfo *accounts.TransactionData, detail
 	return txInfoJSON
 }
 
-func (handlers *Handlers) getAccountTransactions(*http.Request) (interface{}, error) {
+// getAccountTransactions returns the transactions of the account, newest first. The optional
+// filter parameters (see parseTransactionFilter) select the listed transactions, the optional
+// `offset` and `limit` query parameters select a page of them, and `total` is the number of
+// transactions matching the filter.
+func (handlers *Handlers) getAccountTransactions(r *http.Request) (interface{}, error) {
 	var result struct {
 		Success      bool          `json:"success"`
+		ErrorMessage string        `json:"errorMessage,omitempty"`
 		Transactions []Transaction `json:"list"`
+		Total        int           `json:"total"`
+	}
+	page, err := parsePagination(r.URL.Query())
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result, nil
+	}
+	filter, err := parseTransactionFilter(r.URL.Query(), handlers.account.Coin().ParseAmount)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result, nil
 	}
 	txs, err := handlers.account.Transactions()
 	if err != nil {
 		return result, nil
 	}
-	result.Transactions = []Transaction{}
+	visibleTxs := make([]*accounts.TransactionData, 0, len(txs))
 	for _, txInfo := range txs {
 		if txInfo.IsErc20 && big.NewInt(0).Cmp(txInfo.Amount.BigInt()) == 0 {
 			// skipping 0 amount erc20 txs to mitigate Address Poisoning attack
 			continue
 		}
+		if !filter.matches(txInfo, handlers.account.TxNote(txInfo.InternalID)) {
+			continue
+		}
+		visibleTxs = append(visibleTxs, txInfo)
+	}
+	start, end := page.bounds(len(visibleTxs))
+	result.Transactions = []Transaction{}
+	for _, txInfo := range visibleTxs[start:end] {
 		result.Transactions = append(result.Transactions, handlers.getTxInfoJSON(txInfo, false))
 	}
+	result.Total = len(visibleTxs)
 	result.Success = true
 	return result, nil
 }
@@ -286,12 +369,63 @@ func (handlers *Handlers) getAccountTransaction(r *http.Request) (interface{}, e
 	return nil, nil
 }
 
-func (handlers *Handlers) postExportTransactions(*http.Request) (interface{}, error) {
+// getAccountTransactionDetail returns the transaction with the given ID including all its inputs
+// and outputs. The fiat values are those at the time the tx was confirmed.
+func (handlers *Handlers) getAccountTransactionDetail(r *http.Request) (interface{}, error) {
+	txID := mux.Vars(r)["txid"]
+	txs, err := handlers.account.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	for _, txInfo := range txs {
+		if txInfo.TxID != txID && txInfo.InternalID != txID {
+			continue
+		}
+		detail := TransactionDetail{
+			Transaction: handlers.getTxInfoJSON(txInfo, true),
+			Height:      txInfo.Height,
+			Inputs:      []TransactionInput{},
+			Outputs:     []TransactionOutput{},
+		}
+		if txInfo.Timestamp != nil {
+			blockTime := txInfo.Timestamp.Format(time.RFC3339)
+			detail.BlockTime = &blockTime
+		}
+		for _, input := range txInfo.Inputs {
+			transactionInput := TransactionInput{OutPoint: input.OutPoint, Ours: input.Ours}
+			if input.Ours {
+				amount := handlers.formatAmountAtTimeAsJSON(input.Amount, txInfo.Timestamp)
+				transactionInput.Address = input.Address
+				transactionInput.Amount = &amount
+			}
+			detail.Inputs = append(detail.Inputs, transactionInput)
+		}
+		for _, output := range txInfo.Outputs {
+			detail.Outputs = append(detail.Outputs, TransactionOutput{
+				Address: output.Address,
+				Amount:  handlers.formatAmountAtTimeAsJSON(output.Amount, txInfo.Timestamp),
+				Ours:    output.Ours,
+			})
+		}
+		return detail, nil
+	}
+	return nil, errp.Newf("transaction %s not found", txID)
+}
+
+func (handlers *Handlers) postExportTransactions(r *http.Request) (interface{}, error) {
 	type result struct {
 		Success      bool   `json:"success"`
 		ErrorMessage string `json:"errorMessage"`
 	}
-	name := fmt.Sprintf("%s-%s-export.csv", time.Now().Format("2006-01-02-at-15-04-05"), handlers.account.Config().Config.Code)
+	format, err := accounts.NewExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	suffix := "export"
+	if format != accounts.ExportFormatDefault {
+		suffix = fmt.Sprintf("%s-export", format)
+	}
+	name := fmt.Sprintf("%s-%s-%s.csv", time.Now().Format("2006-01-02-at-15-04-05"), handlers.account.Config().Config.Code, suffix)
 	exportsDir, err := config.ExportsDir()
 	if err != nil {
 		handlers.log.WithError(err).Error("error exporting account")
@@ -315,7 +449,7 @@ func (handlers *Handlers) postExportTransactions(*http.Request) (interface{}, er
 		handlers.log.WithError(err).Error("error creating file")
 		return result{Success: false, ErrorMessage: err.Error()}, nil
 	}
-	if err := handlers.account.ExportCSV(file, transactions); err != nil {
+	if err := handlers.account.ExportCSV(file, transactions, format); err != nil {
 		_ = file.Close()
 		handlers.log.WithError(err).Error("error writing file")
 		return result{Success: false, ErrorMessage: err.Error()}, nil
@@ -331,10 +465,87 @@ func (handlers *Handlers) postExportTransactions(*http.Request) (interface{}, er
 	return result{Success: true}, nil
 }
 
+// postExportElectrumWallet writes a watch-only Electrum wallet file of a signing configuration of
+// the account to a file chosen by the user.
+func (handlers *Handlers) postExportElectrumWallet(r *http.Request) (interface{}, error) {
+	type result struct {
+		Success      bool   `json:"success"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var input struct {
+		SigningConfigIndex int `json:"signingConfigIndex"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	btcAccount, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return result{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to export an Electrum wallet.",
+		}, nil
+	}
+	walletFile, err := btcAccount.ElectrumWalletFile(input.SigningConfigIndex)
+	if err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	exportsDir, err := config.ExportsDir()
+	if err != nil {
+		handlers.log.WithError(err).Error("error exporting Electrum wallet")
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	name := fmt.Sprintf("%s-%s-electrum-wallet", time.Now().Format("2006-01-02-at-15-04-05"), handlers.account.Config().Config.Code)
+	path := handlers.account.Config().GetSaveFilename(filepath.Join(exportsDir, name))
+	if path == "" {
+		return nil, nil
+	}
+	handlers.log.Infof("Export Electrum wallet to %s.", path)
+	if err := os.WriteFile(path, walletFile, 0600); err != nil {
+		handlers.log.WithError(err).Error("error writing file")
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return result{Success: true}, nil
+}
+
 func (handlers *Handlers) getAccountInfo(*http.Request) (interface{}, error) {
 	return handlers.account.Info(), nil
 }
 
+// getDescriptor returns the receive and change output descriptors of each signing configuration
+// of the account, so that it can be imported as a watch-only wallet in other software.
+func (handlers *Handlers) getDescriptor(*http.Request) (interface{}, error) {
+	type descriptor struct {
+		ScriptType signing.ScriptType `json:"scriptType"`
+		Receive    string             `json:"receive"`
+		Change     string             `json:"change"`
+	}
+	type result struct {
+		Success      bool         `json:"success"`
+		Descriptors  []descriptor `json:"descriptors,omitempty"`
+		ErrorMessage string       `json:"errorMessage,omitempty"`
+	}
+	if _, ok := handlers.account.(*btc.Account); !ok {
+		return result{Success: false, ErrorMessage: "Descriptors are only available for BTC based accounts."}, nil
+	}
+	descriptors := []descriptor{}
+	for _, signingConfiguration := range handlers.account.Config().Config.SigningConfigurations {
+		receive, err := signingConfiguration.Descriptor(0)
+		if err != nil {
+			return result{Success: false, ErrorMessage: err.Error()}, nil
+		}
+		change, err := signingConfiguration.Descriptor(1)
+		if err != nil {
+			return result{Success: false, ErrorMessage: err.Error()}, nil
+		}
+		descriptors = append(descriptors, descriptor{
+			ScriptType: signingConfiguration.ScriptType(),
+			Receive:    receive,
+			Change:     change,
+		})
+	}
+	return result{Success: true, Descriptors: descriptors}, nil
+}
+
 func (handlers *Handlers) getUTXOs(*http.Request) (interface{}, error) {
 	result := []map[string]interface{}{}
 
@@ -345,13 +556,14 @@ func (handlers *Handlers) getUTXOs(*http.Request) (interface{}, error) {
 	}
 
 	addressCounts := make(map[string]int)
+	spendableOutputs := t.SpendableOutputs()
 
-	for _, output := range t.SpendableOutputs() {
+	for _, output := range spendableOutputs {
 		address := output.Address.EncodeForHumans()
 		addressCounts[address]++
 	}
 
-	for _, output := range t.SpendableOutputs() {
+	for _, output := range spendableOutputs {
 		address := output.Address.EncodeForHumans()
 		addressReused := addressCounts[address] > 1
 
@@ -366,6 +578,8 @@ func (handlers *Handlers) getUTXOs(*http.Request) (interface{}, error) {
 				"note":          handlers.account.TxNote(output.OutPoint.Hash.String()),
 				"addressReused": addressReused,
 				"isChange":      output.IsChange,
+				"dust":          output.IsDust,
+				"frozen":        output.Frozen,
 			})
 	}
 
@@ -382,9 +596,57 @@ func (handlers *Handlers) getAccountBalance(*http.Request) (interface{}, error)
 		"available":    handlers.formatAmountAsJSON(balance.Available(), false),
 		"hasIncoming":  balance.Incoming().BigInt().Sign() > 0,
 		"incoming":     handlers.formatAmountAsJSON(balance.Incoming(), false),
+		"hasFrozen":    balance.Frozen().BigInt().Sign() > 0,
+		"frozen":       handlers.formatAmountAsJSON(balance.Frozen(), false),
+		"spendable":    handlers.formatAmountAsJSON(balance.Spendable(), false),
 	}, nil
 }
 
+// getBalanceHistory returns the balance of the account at the start of each day (or each hour with
+// `interval=hourly`), reconstructed from the transaction history, with the fiat conversions at
+// that time.
+func (handlers *Handlers) getBalanceHistory(r *http.Request) (interface{}, error) {
+	type entry struct {
+		Time   int64           `json:"time"`
+		Amount FormattedAmount `json:"amount"`
+	}
+	type response struct {
+		Success bool `json:"success"`
+		// DataMissing is true if the timestamps of some transactions are not known yet, e.g. because
+		// the block headers are not synced yet.
+		DataMissing bool    `json:"dataMissing"`
+		Entries     []entry `json:"entries"`
+	}
+	var interval time.Duration
+	switch r.URL.Query().Get("interval") {
+	case "", "daily":
+		interval = 24 * time.Hour
+	case "hourly":
+		interval = time.Hour
+	default:
+		return nil, errp.New("invalid interval")
+	}
+	txs, err := handlers.account.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	history, err := txs.BalanceHistory(time.Now(), interval)
+	if errp.Cause(err) == errors.ErrNotAvailable {
+		return response{Success: false, DataMissing: true, Entries: []entry{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]entry, len(history))
+	for i, e := range history {
+		entries[i] = entry{
+			Time:   e.Time.Unix(),
+			Amount: handlers.formatAmountAtTimeAsJSON(e.Value, &e.Time),
+		}
+	}
+	return response{Success: true, Entries: entries}, nil
+}
+
 type slip24Request struct {
 	RecipientName string `json:"recipientName"`
 	Nonce         string `json:"nonce"`
@@ -432,6 +694,9 @@ func (slip24 slip24Request) toPaymentRequest() (*accounts.PaymentRequest, error)
 
 type sendTxInput struct {
 	accounts.TxProposalArgs
+	// contactID is the ID of the address book contact the user selected as the recipient. Empty
+	// if the address was entered directly.
+	contactID string
 }
 
 func (input *sendTxInput) UnmarshalJSON(jsonBytes []byte) error {
@@ -446,11 +711,21 @@ func (input *sendTxInput) UnmarshalJSON(jsonBytes []byte) error {
 		Note           string         `json:"note"`
 		Counter        int            `json:"counter"`
 		PaymentRequest *slip24Request `json:"paymentRequest"`
+		ContactID      string         `json:"contactID"`
+		PayjoinURL     string         `json:"payjoinURL"`
+		// CoinSelection overrides the coin selection algorithm of the account for this proposal.
+		CoinSelection string `json:"coinSelection"`
+		// Recipients is set instead of Address and Amount to pay multiple recipients at once.
+		Recipients []struct {
+			Address string `json:"address"`
+			Amount  string `json:"amount"`
+		} `json:"recipients"`
 	}{}
 	if err := json.Unmarshal(jsonBytes, &jsonBody); err != nil {
 		return errp.WithStack(err)
 	}
 	input.RecipientAddress = jsonBody.Address
+	input.contactID = jsonBody.ContactID
 	var err error
 	input.FeeTargetCode, err = accounts.NewFeeTargetCode(jsonBody.FeeTarget)
 	if err != nil {
@@ -472,7 +747,15 @@ func (input *sendTxInput) UnmarshalJSON(jsonBytes []byte) error {
 		}
 		input.SelectedUTXOs[*outPoint] = struct{}{}
 	}
+	for _, recipient := range jsonBody.Recipients {
+		input.Recipients = append(input.Recipients, accounts.TxRecipient{
+			Address: recipient.Address,
+			Amount:  coin.NewSendAmount(recipient.Amount),
+		})
+	}
 	input.Note = jsonBody.Note
+	input.PayjoinURL = jsonBody.PayjoinURL
+	input.CoinSelection = jsonBody.CoinSelection
 	if jsonBody.PaymentRequest != nil {
 		paymentRequest, err := jsonBody.PaymentRequest.toPaymentRequest()
 		if err != nil {
@@ -491,7 +774,7 @@ func (handlers *Handlers) postAccountSendTx(r *http.Request) (interface{}, error
 		// not return but only log an error here.
 		handlers.log.WithError(err).Error("Failed to unmarshal transaction note")
 	}
-	err := handlers.account.SendTx(txNote)
+	err := handlers.account.SendTx(r.Context(), txNote)
 	if errp.Cause(err) == keystore.ErrSigningAborted || errp.Cause(err) == errp.ErrUserAbort {
 		return map[string]interface{}{"success": false, "aborted": true}, nil
 	}
@@ -507,6 +790,13 @@ func (handlers *Handlers) postAccountSendTx(r *http.Request) (interface{}, error
 }
 
 func txProposalError(err error) (interface{}, error) {
+	if outputErr, ok := errp.Cause(err).(*errors.TxOutputError); ok {
+		return map[string]interface{}{
+			"success":     false,
+			"errorCode":   outputErr.Err.Error(),
+			"outputIndex": outputErr.Index,
+		}, nil
+	}
 	if validationErr, ok := errp.Cause(err).(errors.TxValidationError); ok {
 		return map[string]interface{}{
 			"success":   false,
@@ -521,25 +811,59 @@ func (handlers *Handlers) postAccountTxProposal(r *http.Request) (interface{}, e
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		return txProposalError(errp.WithStack(err))
 	}
-	outputAmount, fee, total, err := handlers.account.TxProposal(&input.TxProposalArgs)
+	if input.contactID != "" {
+		coinCode := handlers.account.Coin().Code()
+		// Contacts are saved for ETH, and also used for ERC20 tokens.
+		if ethCoin, ok := handlers.account.Coin().(*eth.Coin); ok && ethCoin.ERC20Token() != nil {
+			coinCode = coin.CodeETH
+		}
+		if !handlers.addressBook.Matches(input.contactID, coinCode, input.RecipientAddress) {
+			return txProposalError(errors.ErrContactMismatch)
+		}
+	}
+	outputAmount, fee, total, err := handlers.account.TxProposal(r.Context(), &input.TxProposalArgs)
 	if err != nil {
 		return txProposalError(err)
 	}
-	return map[string]interface{}{
-		"success": true,
-		"amount":  handlers.formatAmountAsJSON(outputAmount, false),
-		"fee":     handlers.formatAmountAsJSON(fee, true),
-		"total":   handlers.formatAmountAsJSON(total, false),
-	}, nil
+	var percentage *float64
+	// The fee of ERC20 token transactions is paid in ETH, so it can't be compared to the amount.
+	if ethCoin, ok := handlers.account.Coin().(*eth.Coin); !ok || ethCoin.ERC20Token() == nil {
+		percentage = feePercentage(fee, outputAmount)
+	}
+	result := map[string]interface{}{
+		"success":       true,
+		"amount":        handlers.formatAmountAsJSON(outputAmount, false),
+		"fee":           handlers.formatAmountAsJSON(fee, true),
+		"feePercentage": percentage,
+		"total":         handlers.formatAmountAsJSON(total, false),
+	}
+	if btcAccount, ok := handlers.account.(*btc.Account); ok {
+		if coinSelection := btcAccount.TxProposalCoinSelection(); coinSelection != "" {
+			result["coinSelection"] = coinSelection
+		}
+	}
+	return result, nil
 }
 
-func (handlers *Handlers) getAccountFeeTargets(*http.Request) (interface{}, error) {
+// feePercentage returns the fee as a percentage of the amount sent, rounded to two decimals, or
+// nil if the amount is zero.
+func feePercentage(fee, amount coin.Amount) *float64 {
+	if amount.BigInt().Sign() == 0 {
+		return nil
+	}
+	ratio := new(big.Rat).SetFrac(fee.BigInt(), amount.BigInt())
+	percentage, _ := ratio.Mul(ratio, big.NewRat(100, 1)).Float64()
+	percentage = math.Round(percentage*100) / 100
+	return &percentage
+}
+
+func (handlers *Handlers) getAccountFeeTargets(r *http.Request) (interface{}, error) {
 	type jsonFeeTarget struct {
 		Code        accounts.FeeTargetCode `json:"code"`
 		FeeRateInfo string                 `json:"feeRateInfo"`
 	}
 
-	feeTargets, defaultFeeTarget := handlers.account.FeeTargets()
+	feeTargets, defaultFeeTarget := handlers.account.FeeTargets(r.Context())
 	result := []jsonFeeTarget{}
 	for _, feeTarget := range feeTargets {
 		result = append(result, jsonFeeTarget{
@@ -553,6 +877,46 @@ func (handlers *Handlers) getAccountFeeTargets(*http.Request) (interface{}, erro
 	}, nil
 }
 
+// getAccountFeeEstimates returns the low, medium and high fee tiers with their expected number of
+// blocks until confirmation, and the custom tier with the minimum fee rate. Only BTC based accounts
+// are supported.
+func (handlers *Handlers) getAccountFeeEstimates(r *http.Request) (interface{}, error) {
+	type jsonFeeEstimate struct {
+		Tier         btc.FeeTier            `json:"tier"`
+		Code         accounts.FeeTargetCode `json:"code,omitempty"`
+		Blocks       int                    `json:"blocks,omitempty"`
+		FeeRateInfo  string                 `json:"feeRateInfo"`
+		FeeRatePerKb *FormattedAmount       `json:"feeRatePerKb"`
+	}
+	type response struct {
+		Success      bool              `json:"success"`
+		ErrorMessage string            `json:"errorMessage,omitempty"`
+		FeeEstimates []jsonFeeEstimate `json:"feeEstimates,omitempty"`
+	}
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support fee estimates.",
+		}, nil
+	}
+	result := []jsonFeeEstimate{}
+	for _, estimate := range account.FeeEstimates(r.Context()) {
+		jsonEstimate := jsonFeeEstimate{
+			Tier:        estimate.Tier,
+			Code:        estimate.Code,
+			Blocks:      estimate.Blocks,
+			FeeRateInfo: estimate.FormattedFeeRate(),
+		}
+		if estimate.FeeRatePerKb != nil {
+			feeRatePerKb := handlers.formatBTCAmountAsJSON(*estimate.FeeRatePerKb, true)
+			jsonEstimate.FeeRatePerKb = &feeRatePerKb
+		}
+		result = append(result, jsonEstimate)
+	}
+	return response{Success: true, FeeEstimates: result}, nil
+}
+
 func (handlers *Handlers) postInit(*http.Request) (interface{}, error) {
 	if handlers.account == nil {
 		return nil, errp.New("/init called even though account was not added yet")
@@ -570,6 +934,17 @@ type statusResponse struct {
 	// FatalError indicates that there was a fatal error in handling the account. When this happens,
 	// an error is shown to the user and the account is made unusable.
 	FatalError bool `json:"fatalError"`
+	// Electrum contains the Electrum servers used by a BTC-based account and the one currently
+	// connected. The servers are the ones configured for the account, or the ones of the coin by
+	// default.
+	Electrum *electrum.Status `json:"electrum,omitempty"`
+	// LastSynced is the time a BTC-based account was last fully synced, also in a previous session.
+	// The persisted balance and transactions can be shown while the account is syncing again. nil
+	// if the account was never synced.
+	LastSynced *time.Time `json:"lastSynced"`
+	// SyncProgress is the progress of syncing a BTC-based account. Updates are sent with the
+	// `account/<code>/sync-progress` event.
+	SyncProgress *btc.SyncProgress `json:"syncProgress,omitempty"`
 }
 
 func (handlers *Handlers) getAccountStatus(*http.Request) (interface{}, error) {
@@ -582,24 +957,82 @@ func (handlers *Handlers) getAccountStatus(*http.Request) (interface{}, error) {
 		s := offlineErr.Error()
 		offlineError = &s
 	}
+	var electrumStatus *electrum.Status
+	var lastSynced *time.Time
+	var syncProgress *btc.SyncProgress
+	if btcAccount, ok := handlers.account.(*btc.Account); ok {
+		if reporter, ok := btcAccount.Blockchain().(electrum.StatusReporter); ok {
+			electrumStatus = reporter.Status()
+		}
+		// Errors are ignored, as the account might not be initialized yet.
+		lastSynced, _ = btcAccount.LastSynced()
+		progress := btcAccount.SyncProgress()
+		syncProgress = &progress
+	}
 	return statusResponse{
 		Synced:       handlers.account.Synced(),
 		OfflineError: offlineError,
 		FatalError:   handlers.account.FatalError(),
+		Electrum:     electrumStatus,
+		LastSynced:   lastSynced,
+		SyncProgress: syncProgress,
 	}, nil
 }
 
-func (handlers *Handlers) getReceiveAddresses(*http.Request) (interface{}, error) {
+// getReceiveAddresses returns the unused receive addresses offered to the user, per script type.
+// With the `all=true` query parameter, all derived receive addresses of a BTC based account are
+// returned, including the used ones, with their usage status, balance and label. Any unused address
+// can be picked instead of the first one and verified with `/verify-address`.
+func (handlers *Handlers) getReceiveAddresses(r *http.Request) (interface{}, error) {
 
 	type jsonAddress struct {
 		Address   string `json:"address"`
 		AddressID string `json:"addressID"`
+		// The fields below are only set with `all=true`.
+		Keypath string           `json:"keypath,omitempty"`
+		Index   *uint32          `json:"index,omitempty"`
+		Used    bool             `json:"used"`
+		Balance *FormattedAmount `json:"balance,omitempty"`
+		Label   string           `json:"label,omitempty"`
 	}
 	type jsonAddressList struct {
 		ScriptType *signing.ScriptType `json:"scriptType"`
 		Addresses  []jsonAddress       `json:"addresses"`
 	}
 	addressList := []jsonAddressList{}
+	if r.URL.Query().Get("all") == "true" {
+		btcAccount, ok := handlers.account.(*btc.Account)
+		if !ok {
+			return nil, errp.New("An account must be BTC based to list all receive addresses.")
+		}
+		receiveAddresses, err := btcAccount.ReceiveAddresses()
+		if err != nil {
+			return nil, err
+		}
+		for _, receiveAddress := range receiveAddresses {
+			if len(addressList) == 0 ||
+				*addressList[len(addressList)-1].ScriptType != receiveAddress.ScriptType {
+				scriptType := receiveAddress.ScriptType
+				addressList = append(addressList, jsonAddressList{
+					ScriptType: &scriptType,
+					Addresses:  []jsonAddress{},
+				})
+			}
+			index := receiveAddress.Index
+			balance := handlers.formatBTCAmountAsJSON(receiveAddress.Balance, false)
+			list := &addressList[len(addressList)-1]
+			list.Addresses = append(list.Addresses, jsonAddress{
+				Address:   receiveAddress.Address.EncodeForHumans(),
+				AddressID: receiveAddress.Address.ID(),
+				Keypath:   receiveAddress.Address.AbsoluteKeypath().Encode(),
+				Index:     &index,
+				Used:      receiveAddress.Used,
+				Balance:   &balance,
+				Label:     receiveAddress.Label,
+			})
+		}
+		return addressList, nil
+	}
 	for _, addresses := range handlers.account.GetUnusedReceiveAddresses() {
 		addrs := []jsonAddress{}
 		for _, address := range addresses.Addresses {
@@ -616,6 +1049,59 @@ func (handlers *Handlers) getReceiveAddresses(*http.Request) (interface{}, error
 	return addressList, nil
 }
 
+// getAddressPreview returns the next `count` unused receive and change addresses per script type,
+// without marking them used. `count` defaults to 10.
+func (handlers *Handlers) getAddressPreview(r *http.Request) (interface{}, error) {
+	type jsonAddress struct {
+		Address   string `json:"address"`
+		AddressID string `json:"addressID"`
+		Keypath   string `json:"keypath"`
+		Watched   bool   `json:"watched"`
+	}
+	type jsonAddressPreview struct {
+		ScriptType signing.ScriptType `json:"scriptType"`
+		Receive    []jsonAddress      `json:"receive"`
+		Change     []jsonAddress      `json:"change"`
+	}
+	count := 10
+	if countParam := r.URL.Query().Get("count"); countParam != "" {
+		var err error
+		count, err = strconv.Atoi(countParam)
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+	}
+	btcAccount, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return nil, errp.New("An account must be BTC based to preview addresses.")
+	}
+	previews, err := btcAccount.PreviewUnusedAddresses(count)
+	if err != nil {
+		return nil, err
+	}
+	toJSON := func(previewAddresses []*btc.PreviewAddress) []jsonAddress {
+		result := make([]jsonAddress, len(previewAddresses))
+		for i, previewAddress := range previewAddresses {
+			result[i] = jsonAddress{
+				Address:   previewAddress.Address.EncodeForHumans(),
+				AddressID: previewAddress.Address.ID(),
+				Keypath:   previewAddress.Address.AbsoluteKeypath().Encode(),
+				Watched:   previewAddress.Watched,
+			}
+		}
+		return result
+	}
+	result := []jsonAddressPreview{}
+	for _, preview := range previews {
+		result = append(result, jsonAddressPreview{
+			ScriptType: preview.ScriptType,
+			Receive:    toJSON(preview.Receive),
+			Change:     toJSON(preview.Change),
+		})
+	}
+	return result, nil
+}
+
 func (handlers *Handlers) postVerifyAddress(r *http.Request) (interface{}, error) {
 	var addressID string
 	if err := json.NewDecoder(r.Body).Decode(&addressID); err != nil {
@@ -624,6 +1110,34 @@ func (handlers *Handlers) postVerifyAddress(r *http.Request) (interface{}, error
 	return handlers.account.VerifyAddress(addressID)
 }
 
+// postVerifyReceiveAddress verifies a previously derived receive address, given by its encoding or
+// absolute keypath.
+func (handlers *Handlers) postVerifyReceiveAddress(r *http.Request) (interface{}, error) {
+	var addressOrKeypath string
+	if err := json.NewDecoder(r.Body).Decode(&addressOrKeypath); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	btcAccount, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return nil, errp.New("An account must be BTC based to verify previous receive addresses.")
+	}
+	return btcAccount.VerifyReceiveAddress(addressOrKeypath)
+}
+
+// postScanFurther extends the gap limits of the account by the number of addresses in the request
+// body until the account is reinitialized.
+func (handlers *Handlers) postScanFurther(r *http.Request) (interface{}, error) {
+	var extra int
+	if err := json.NewDecoder(r.Body).Decode(&extra); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	btcAccount, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return nil, errp.New("An account must be BTC based to scan more addresses.")
+	}
+	return nil, btcAccount.ScanFurther(extra)
+}
+
 func (handlers *Handlers) postVerifyExtendedPublicKey(r *http.Request) (interface{}, error) {
 	type result struct {
 		Success      bool   `json:"success"`
@@ -659,6 +1173,74 @@ func (handlers *Handlers) postVerifyExtendedPublicKey(r *http.Request) (interfac
 	return result{Success: true}, nil
 }
 
+// postExportExtendedPublicKey returns the xpub of a signing configuration of the account as text
+// and as a QR code. If `verify` is true, the xpub is also shown on the device at the same time, so
+// the user can compare it against the one displayed in the app.
+func (handlers *Handlers) postExportExtendedPublicKey(r *http.Request) (interface{}, error) {
+	type result struct {
+		Success      bool               `json:"success"`
+		ErrorMessage string             `json:"errorMessage,omitempty"`
+		ScriptType   signing.ScriptType `json:"scriptType,omitempty"`
+		Keypath      string             `json:"keypath,omitempty"`
+		XPub         string             `json:"xpub,omitempty"`
+		QRCode       string             `json:"qrCode,omitempty"`
+	}
+	var input struct {
+		SigningConfigIndex int  `json:"signingConfigIndex"`
+		Verify             bool `json:"verify"`
+		// Encoding is the SLIP-132 version of the exported key, e.g. "xpub" or "zpub". If empty,
+		// the version matching the script type is used.
+		Encoding string `json:"encoding"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	btcAccount, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return result{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to export xpubs.",
+		}, nil
+	}
+	// Info() contains the xpubs with the version bytes matching the script type (xpub, zpub, ...).
+	info := btcAccount.Info()
+	if input.SigningConfigIndex < 0 || input.SigningConfigIndex >= len(info.SigningConfigurations) {
+		return result{Success: false, ErrorMessage: "Invalid signing configuration index."}, nil
+	}
+	signingConfiguration := info.SigningConfigurations[input.SigningConfigIndex]
+	if signingConfiguration.BitcoinSimple == nil {
+		return result{Success: false, ErrorMessage: "Only single-sig xpubs can be exported."}, nil
+	}
+	xpub := signingConfiguration.ExtendedPublicKey().String()
+	if input.Encoding != "" {
+		var err error
+		xpub, err = signing.EncodeExtendedPublicKey(signingConfiguration.ExtendedPublicKey(), input.Encoding)
+		if err != nil {
+			return result{Success: false, ErrorMessage: err.Error()}, nil
+		}
+	}
+	qr, err := qrcode.Encode(xpub, qrcode.Medium, 256)
+	if err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	if input.Verify {
+		go func() {
+			defer crashreport.Recover()
+			if _, err := btcAccount.VerifyExtendedPublicKey(input.SigningConfigIndex); err != nil &&
+				errp.Cause(err) != context.Canceled {
+				handlers.log.WithError(err).Error("Could not verify the xpub on the device")
+			}
+		}()
+	}
+	return result{
+		Success:    true,
+		ScriptType: signingConfiguration.ScriptType(),
+		Keypath:    signingConfiguration.AbsoluteKeypath().Encode(),
+		XPub:       xpub,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr),
+	}, nil
+}
+
 func (handlers *Handlers) getHasSecureOutput(r *http.Request) (interface{}, error) {
 	hasSecureOutput, optional, err := handlers.account.CanVerifyAddresses()
 	if err != nil {
@@ -682,6 +1264,56 @@ func (handlers *Handlers) postSetTxNote(r *http.Request) (interface{}, error) {
 	return nil, handlers.account.SetTxNote(args.InternalTxID, args.Note)
 }
 
+// postSetAddressNote sets the label of a receive address.
+func (handlers *Handlers) postSetAddressNote(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var args struct {
+		Address string `json:"address"`
+		Note    string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{Success: false, ErrorMessage: "An account must be BTC based to label addresses."}, nil
+	}
+	if err := account.SetReceiveAddressNote(args.Address, args.Note); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true}, nil
+}
+
+// postSetUTXOFrozen marks a coin as "do not spend", or removes the mark.
+func (handlers *Handlers) postSetUTXOFrozen(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var args struct {
+		OutPoint string `json:"outPoint"`
+		Frozen   bool   `json:"frozen"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{Success: false, ErrorMessage: "An account must be BTC based to freeze coins."}, nil
+	}
+	outPoint, err := util.ParseOutPoint([]byte(args.OutPoint))
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	if err := account.SetUTXOFrozen(outPoint.String(), args.Frozen); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true}, nil
+}
+
 func (handlers *Handlers) postConnectKeystore(r *http.Request) (interface{}, error) {
 	type response struct {
 		Success bool `json:"success"`
@@ -770,7 +1402,7 @@ func (handlers *Handlers) postEthSignWalletConnectTx(r *http.Request) (interface
 	if !ok {
 		return signingResponse{Success: false, ErrorMessage: "Must be an ETH based account"}, nil
 	}
-	txHash, rawTx, err := ethAccount.EthSignWalletConnectTx(args.Send, args.ChainId, args.Tx)
+	txHash, rawTx, err := ethAccount.EthSignWalletConnectTx(r.Context(), args.Send, args.ChainId, args.Tx)
 	if errp.Cause(err) == keystore.ErrSigningAborted || errp.Cause(err) == errp.ErrUserAbort {
 		return signingResponse{Success: false, Aborted: true}, nil
 	}
@@ -786,6 +1418,73 @@ func (handlers *Handlers) postEthSignWalletConnectTx(r *http.Request) (interface
 	}, nil
 }
 
+// getEthQueuedTransactions returns the pending outgoing transactions of an ETH account.
+func (handlers *Handlers) getEthQueuedTransactions(*http.Request) (interface{}, error) {
+	type queuedTransaction struct {
+		TxID              string          `json:"txID"`
+		Nonce             uint64          `json:"nonce"`
+		MaxFee            FormattedAmount `json:"maxFee"`
+		MaxFeePerGas      string          `json:"maxFeePerGas"`
+		MaxPriorityFee    string          `json:"maxPriorityFeePerGas"`
+		BroadcastAttempts uint16          `json:"broadcastAttempts"`
+	}
+	ethAccount, ok := handlers.account.(*eth.Account)
+	if !ok {
+		return nil, errp.New("Must be an ETH based account")
+	}
+	queued, err := ethAccount.QueuedTransactions()
+	if err != nil {
+		return nil, err
+	}
+	gwei := big.NewInt(1e9)
+	result := make([]queuedTransaction, len(queued))
+	for i, tx := range queued {
+		result[i] = queuedTransaction{
+			TxID:              tx.TxID,
+			Nonce:             tx.Nonce,
+			MaxFee:            handlers.formatAmountAsJSON(tx.MaxFee, true),
+			MaxFeePerGas:      new(big.Rat).SetFrac(tx.GasFeeCap, gwei).FloatString(9),
+			MaxPriorityFee:    new(big.Rat).SetFrac(tx.GasTipCap, gwei).FloatString(9),
+			BroadcastAttempts: tx.BroadcastAttempts,
+		}
+	}
+	return result, nil
+}
+
+// postEthReplaceTransaction speeds up or cancels a pending outgoing transaction of an ETH account.
+func (handlers *Handlers) postEthReplaceTransaction(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		TxID         string `json:"txID,omitempty"`
+		Aborted      bool   `json:"aborted,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+		ErrorCode    string `json:"errorCode,omitempty"`
+	}
+	var request struct {
+		TxID   string `json:"txID"`
+		Cancel bool   `json:"cancel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	ethAccount, ok := handlers.account.(*eth.Account)
+	if !ok {
+		return response{Success: false, ErrorMessage: "Must be an ETH based account"}, nil
+	}
+	txID, err := ethAccount.ReplaceTransaction(r.Context(), request.TxID, request.Cancel)
+	if errp.Cause(err) == keystore.ErrSigningAborted || errp.Cause(err) == errp.ErrUserAbort {
+		return response{Success: false, Aborted: true}, nil
+	}
+	if err != nil {
+		if validationErr, ok := errp.Cause(err).(errors.TxValidationError); ok {
+			return response{Success: false, ErrorCode: validationErr.Error()}, nil
+		}
+		handlers.log.WithError(err).Error("Failed to replace transaction")
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true, TxID: txID}, nil
+}
+
 func (handlers *Handlers) postSignBTCAddress(r *http.Request) (interface{}, error) {
 	type response struct {
 		Success      bool   `json:"success"`
@@ -830,6 +1529,271 @@ func (handlers *Handlers) postSignBTCAddress(r *http.Request) (interface{}, erro
 	return response{Success: true, Address: address, Signature: signature}, nil
 }
 
+// postSignMessage signs a message with an address of the account.
+func (handlers *Handlers) postSignMessage(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		Signature    string `json:"signature,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+		ErrorCode    string `json:"errorCode,omitempty"`
+	}
+	var request struct {
+		Address string             `json:"address"`
+		Message string             `json:"message"`
+		Format  signmessage.Format `json:"format"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support message signing.",
+		}, nil
+	}
+	signature, err := account.SignMessage(request.Address, request.Message, request.Format)
+	if err != nil {
+		if firmware.IsErrorAbort(err) || errp.Cause(err) == keystore.ErrSigningAborted ||
+			errp.Cause(err) == errp.ErrUserAbort {
+			return response{Success: false, ErrorCode: errp.ErrUserAbort.Error()}, nil
+		}
+		handlers.log.WithError(err).Error("Failed to sign message")
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true, Signature: signature}, nil
+}
+
+// postExportPSBT exports the active tx proposal as a PSBT, optionally signed by the keystore.
+func (handlers *Handlers) postExportPSBT(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		PSBT         string `json:"psbt,omitempty"`
+		Aborted      bool   `json:"aborted,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var request struct {
+		Sign bool `json:"sign"`
+	}
+	// An empty body exports the PSBT unsigned.
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support PSBTs.",
+		}, nil
+	}
+	encodedPSBT, err := account.ExportPSBT(r.Context(), request.Sign)
+	if errp.Cause(err) == keystore.ErrSigningAborted || errp.Cause(err) == errp.ErrUserAbort {
+		return response{Success: false, Aborted: true}, nil
+	}
+	if err != nil {
+		handlers.log.WithError(err).Error("Failed to export PSBT")
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true, PSBT: encodedPSBT}, nil
+}
+
+// postSendPSBT finalizes and broadcasts a signed PSBT.
+func (handlers *Handlers) postSendPSBT(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		TxID         string `json:"txId,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var request struct {
+		PSBT string `json:"psbt"`
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support PSBTs.",
+		}, nil
+	}
+	txID, err := account.SendPSBT(r.Context(), request.PSBT, request.Note)
+	if err != nil {
+		handlers.log.WithError(err).Error("Failed to send PSBT")
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true, TxID: txID}, nil
+}
+
+// postBumpFee replaces an unconfirmed transaction with one paying a higher fee (RBF).
+func (handlers *Handlers) postBumpFee(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool   `json:"success"`
+		TxID         string `json:"txId,omitempty"`
+		Aborted      bool   `json:"aborted,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+		ErrorCode    string `json:"errorCode,omitempty"`
+	}
+	var request struct {
+		TxID      string `json:"txID"`
+		FeeTarget string `json:"feeTarget"`
+		// Provided in Sat/vByte.
+		CustomFee string `json:"customFee"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support fee bumping.",
+		}, nil
+	}
+	feeTargetCode, err := accounts.NewFeeTargetCode(request.FeeTarget)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	txID, err := account.BumpFee(r.Context(), request.TxID, feeTargetCode, request.CustomFee)
+	if errp.Cause(err) == keystore.ErrSigningAborted || errp.Cause(err) == errp.ErrUserAbort {
+		return response{Success: false, Aborted: true}, nil
+	}
+	if err != nil {
+		if validationErr, ok := errp.Cause(err).(errors.TxValidationError); ok {
+			return response{Success: false, ErrorCode: validationErr.Error()}, nil
+		}
+		handlers.log.WithError(err).Error("Failed to bump fee")
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return response{Success: true, TxID: txID}, nil
+}
+
+// postConsolidationProposal proposes a transaction consolidating the selected coins, or all coins
+// up to maxAmount, into one coin of the account. Like with /tx-proposal, the proposal is signed and
+// sent with /sendtx.
+func (handlers *Handlers) postConsolidationProposal(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success           bool             `json:"success"`
+		NumInputs         int              `json:"numInputs,omitempty"`
+		Amount            *FormattedAmount `json:"amount,omitempty"`
+		Fee               *FormattedAmount `json:"fee,omitempty"`
+		FutureFeeRateInfo string           `json:"futureFeeRateInfo,omitempty"`
+		FutureFeeSavings  *FormattedAmount `json:"futureFeeSavings,omitempty"`
+		ErrorMessage      string           `json:"errorMessage,omitempty"`
+		ErrorCode         string           `json:"errorCode,omitempty"`
+	}
+	var request struct {
+		SelectedUTXOs []string `json:"selectedUTXOs"`
+		// MaxAmount is in the format unit of the account. Empty for no limit.
+		MaxAmount string `json:"maxAmount"`
+		FeeTarget string `json:"feeTarget"`
+		// Provided in Sat/vByte.
+		CustomFee string `json:"customFee"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support consolidations.",
+		}, nil
+	}
+	args := &btc.ConsolidationArgs{
+		SelectedUTXOs: map[wire.OutPoint]struct{}{},
+		CustomFee:     request.CustomFee,
+	}
+	var err error
+	args.FeeTargetCode, err = accounts.NewFeeTargetCode(request.FeeTarget)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	for _, outPointString := range request.SelectedUTXOs {
+		outPoint, err := util.ParseOutPoint([]byte(outPointString))
+		if err != nil {
+			return response{Success: false, ErrorMessage: err.Error()}, nil
+		}
+		args.SelectedUTXOs[*outPoint] = struct{}{}
+	}
+	if request.MaxAmount != "" {
+		maxAmount := coin.NewSendAmount(request.MaxAmount)
+		args.MaxAmount = &maxAmount
+	}
+	proposal, err := account.ConsolidationProposal(r.Context(), args)
+	if err != nil {
+		if validationErr, ok := errp.Cause(err).(errors.TxValidationError); ok {
+			return response{Success: false, ErrorCode: validationErr.Error()}, nil
+		}
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	amount := handlers.formatAmountAsJSON(proposal.Amount, false)
+	fee := handlers.formatAmountAsJSON(proposal.Fee, true)
+	futureFeeSavings := handlers.formatAmountAsJSON(proposal.FutureFeeSavings, true)
+	return response{
+		Success:           true,
+		NumInputs:         proposal.NumInputs,
+		Amount:            &amount,
+		Fee:               &fee,
+		FutureFeeRateInfo: proposal.FormattedFutureFeeRate(),
+		FutureFeeSavings:  &futureFeeSavings,
+	}, nil
+}
+
+func (handlers *Handlers) postSweepPrivateKey(r *http.Request) (interface{}, error) {
+	type response struct {
+		Success      bool             `json:"success"`
+		Amount       *FormattedAmount `json:"amount,omitempty"`
+		Fee          *FormattedAmount `json:"fee,omitempty"`
+		TxID         string           `json:"txId,omitempty"`
+		ErrorMessage string           `json:"errorMessage,omitempty"`
+		ErrorCode    string           `json:"errorCode,omitempty"`
+	}
+	var request struct {
+		// PrivateKey is in WIF format.
+		PrivateKey string `json:"privateKey"`
+		FeeTarget  string `json:"feeTarget"`
+		// Provided in Sat/vByte.
+		CustomFee string `json:"customFee"`
+		// If false, the sweep transaction is not broadcasted, and only the amount and fee are
+		// returned.
+		Broadcast bool `json:"broadcast"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+
+	account, ok := handlers.account.(*btc.Account)
+	if !ok {
+		return response{
+			Success:      false,
+			ErrorMessage: "An account must be BTC based to support sweeping private keys.",
+		}, nil
+	}
+	feeTargetCode, err := accounts.NewFeeTargetCode(request.FeeTarget)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	result, err := account.SweepPrivateKey(
+		r.Context(), request.PrivateKey, feeTargetCode, request.CustomFee, request.Broadcast)
+	if err != nil {
+		if validationErr, ok := errp.Cause(err).(errors.TxValidationError); ok {
+			return response{Success: false, ErrorCode: validationErr.Error()}, nil
+		}
+		handlers.log.WithError(err).Error("Failed to sweep private key")
+		return response{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	amount := handlers.formatAmountAsJSON(result.Amount, false)
+	fee := handlers.formatAmountAsJSON(result.Fee, true)
+	return response{Success: true, Amount: &amount, Fee: &fee, TxID: result.TxID}, nil
+}
+
 func (handlers *Handlers) getHasPaymentRequest(r *http.Request) (interface{}, error) {
 	type response struct {
 		Success      bool   `json:"success"`
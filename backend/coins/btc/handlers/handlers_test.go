@@ -0,0 +1,59 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	accountsMocks "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/mocks"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeePercentage(t *testing.T) {
+	require.Nil(t, feePercentage(coin.NewAmountFromInt64(100), coin.NewAmountFromInt64(0)))
+	require.Equal(t, 1.0, *feePercentage(coin.NewAmountFromInt64(1000), coin.NewAmountFromInt64(100000)))
+	require.Equal(t, 33.33, *feePercentage(coin.NewAmountFromInt64(1), coin.NewAmountFromInt64(3)))
+	require.Equal(t, 200.0, *feePercentage(coin.NewAmountFromInt64(2), coin.NewAmountFromInt64(1)))
+}
+
+func TestPostExportPSBTRequest(t *testing.T) {
+	handlers := &Handlers{
+		account: &accountsMocks.InterfaceMock{},
+		log:     logging.Get().WithGroup("handlers_test"),
+	}
+	exportPSBT := func(body string) string {
+		result, err := handlers.postExportPSBT(
+			httptest.NewRequest(http.MethodPost, "/export-psbt", strings.NewReader(body)))
+		require.NoError(t, err)
+		var response struct {
+			ErrorMessage string `json:"errorMessage"`
+		}
+		jsonResult, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(jsonResult, &response))
+		return response.ErrorMessage
+	}
+	// The request is decoded and the handler gets as far as checking the account type.
+	const notBTC = "An account must be BTC based to support PSBTs."
+	require.Equal(t, notBTC, exportPSBT(""))
+	require.Equal(t, notBTC, exportPSBT(`{"sign":true}`))
+	require.NotEqual(t, notBTC, exportPSBT("{"))
+}
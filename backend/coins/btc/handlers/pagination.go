@@ -0,0 +1,56 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// pagination selects a page of a list. A limit of 0 means no limit.
+type pagination struct {
+	offset int
+	limit  int
+}
+
+// parsePagination reads the optional `offset` and `limit` query parameters. Without them, the
+// whole list is returned.
+func parsePagination(query url.Values) (pagination, error) {
+	var page pagination
+	for name, value := range map[string]*int{"offset": &page.offset, "limit": &page.limit} {
+		param := query.Get(name)
+		if param == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed < 0 {
+			return pagination{}, errp.Newf("invalid %s: %s", name, param)
+		}
+		*value = parsed
+	}
+	return page, nil
+}
+
+// bounds returns the start and end index of the page in a list of the given length.
+func (page pagination) bounds(length int) (int, int) {
+	start := min(page.offset, length)
+	end := length
+	if page.limit > 0 {
+		end = min(start+page.limit, length)
+	}
+	return start, end
+}
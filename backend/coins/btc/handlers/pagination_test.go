@@ -0,0 +1,59 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePagination(t *testing.T) {
+	page, err := parsePagination(url.Values{})
+	require.NoError(t, err)
+	require.Equal(t, pagination{}, page)
+
+	page, err = parsePagination(url.Values{"offset": {"20"}, "limit": {"10"}})
+	require.NoError(t, err)
+	require.Equal(t, pagination{offset: 20, limit: 10}, page)
+
+	for _, query := range []url.Values{
+		{"offset": {"-1"}},
+		{"limit": {"ten"}},
+	} {
+		_, err := parsePagination(query)
+		require.Error(t, err)
+	}
+}
+
+func TestPaginationBounds(t *testing.T) {
+	for _, test := range []struct {
+		page       pagination
+		length     int
+		start, end int
+	}{
+		{pagination{}, 5, 0, 5},
+		{pagination{limit: 2}, 5, 0, 2},
+		{pagination{offset: 2, limit: 2}, 5, 2, 4},
+		{pagination{offset: 4, limit: 2}, 5, 4, 5},
+		{pagination{offset: 10, limit: 2}, 5, 5, 5},
+		{pagination{offset: 3}, 5, 3, 5},
+	} {
+		start, end := test.page.bounds(test.length)
+		require.Equal(t, test.start, start)
+		require.Equal(t, test.end, end)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// txTypeNames are the transaction types as returned by the /transactions endpoint.
+var txTypeNames = map[accounts.TxType]string{
+	accounts.TxTypeReceive:  "receive",
+	accounts.TxTypeSend:     "send",
+	accounts.TxTypeSendSelf: "send_to_self",
+}
+
+// transactionFilter selects the transactions returned by the /transactions endpoint. Zero values
+// don't filter.
+type transactionFilter struct {
+	// types are the transaction types to include, using the names of txTypeNames.
+	types map[string]struct{}
+	// from and to limit the time of the transaction (inclusive).
+	from *time.Time
+	to   *time.Time
+	// minAmount and maxAmount limit the amount of the transaction (inclusive).
+	minAmount *coin.Amount
+	maxAmount *coin.Amount
+	status    accounts.TxStatus
+	// note is matched case-insensitively against the transaction note.
+	note string
+}
+
+// parseTransactionFilter reads the optional filter query parameters:
+//   - `type`: comma separated list of receive, send and send_to_self.
+//   - `from`, `to`: RFC3339 timestamps.
+//   - `minAmount`, `maxAmount`: amounts in the format unit of the account, parsed with parseAmount.
+//   - `status`: pending, complete, failed, dropped or doubleSpent.
+//   - `note`: text contained in the transaction note.
+func parseTransactionFilter(
+	query url.Values, parseAmount func(string) (coin.Amount, error)) (transactionFilter, error) {
+	var filter transactionFilter
+	if types := query.Get("type"); types != "" {
+		filter.types = map[string]struct{}{}
+		for _, txType := range strings.Split(types, ",") {
+			if !isTxTypeName(txType) {
+				return transactionFilter{}, errp.Newf("invalid type: %s", txType)
+			}
+			filter.types[txType] = struct{}{}
+		}
+	}
+	for name, value := range map[string]**time.Time{"from": &filter.from, "to": &filter.to} {
+		param := query.Get(name)
+		if param == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return transactionFilter{}, errp.Newf("invalid %s: %s", name, param)
+		}
+		*value = &parsed
+	}
+	for name, value := range map[string]**coin.Amount{"minAmount": &filter.minAmount, "maxAmount": &filter.maxAmount} {
+		param := query.Get(name)
+		if param == "" {
+			continue
+		}
+		parsed, err := parseAmount(param)
+		if err != nil {
+			return transactionFilter{}, errp.Newf("invalid %s: %s", name, param)
+		}
+		*value = &parsed
+	}
+	switch status := accounts.TxStatus(query.Get("status")); status {
+	case "", accounts.TxStatusPending, accounts.TxStatusComplete, accounts.TxStatusFailed,
+		accounts.TxStatusDropped, accounts.TxStatusDoubleSpent:
+		filter.status = status
+	default:
+		return transactionFilter{}, errp.Newf("invalid status: %s", status)
+	}
+	filter.note = strings.ToLower(strings.TrimSpace(query.Get("note")))
+	return filter, nil
+}
+
+func isTxTypeName(name string) bool {
+	for _, txTypeName := range txTypeNames {
+		if name == txTypeName {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if the transaction with the given note passes the filter.
+func (filter transactionFilter) matches(txInfo *accounts.TransactionData, note string) bool {
+	if filter.types != nil {
+		if _, ok := filter.types[txTypeNames[txInfo.Type]]; !ok {
+			return false
+		}
+	}
+	if filter.from != nil || filter.to != nil {
+		timestamp := txInfo.Timestamp
+		if timestamp == nil {
+			timestamp = txInfo.CreatedTimestamp
+		}
+		if timestamp == nil ||
+			(filter.from != nil && timestamp.Before(*filter.from)) ||
+			(filter.to != nil && timestamp.After(*filter.to)) {
+			return false
+		}
+	}
+	if filter.minAmount != nil && txInfo.Amount.BigInt().Cmp(filter.minAmount.BigInt()) < 0 {
+		return false
+	}
+	if filter.maxAmount != nil && txInfo.Amount.BigInt().Cmp(filter.maxAmount.BigInt()) > 0 {
+		return false
+	}
+	if filter.status != "" && txInfo.Status != filter.status {
+		return false
+	}
+	if filter.note != "" && !strings.Contains(strings.ToLower(note), filter.note) {
+		return false
+	}
+	return true
+}
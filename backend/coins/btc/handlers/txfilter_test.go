@@ -0,0 +1,90 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/stretchr/testify/require"
+)
+
+func parseSatAmount(amount string) (coin.Amount, error) {
+	return coin.NewAmountFromString(amount, big.NewInt(1))
+}
+
+func TestParseTransactionFilter(t *testing.T) {
+	filter, err := parseTransactionFilter(url.Values{}, parseSatAmount)
+	require.NoError(t, err)
+	require.Equal(t, transactionFilter{}, filter)
+
+	filter, err = parseTransactionFilter(url.Values{"status": {"doubleSpent"}}, parseSatAmount)
+	require.NoError(t, err)
+	require.Equal(t, accounts.TxStatusDoubleSpent, filter.status)
+
+	for _, query := range []url.Values{
+		{"type": {"receive,invalid"}},
+		{"from": {"2024-01-01"}},
+		{"minAmount": {"abc"}},
+		{"status": {"unknown"}},
+	} {
+		_, err := parseTransactionFilter(query, parseSatAmount)
+		require.Error(t, err, query)
+	}
+}
+
+func TestTransactionFilterMatches(t *testing.T) {
+	timestamp := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tx := &accounts.TransactionData{
+		Type:      accounts.TxTypeSend,
+		Status:    accounts.TxStatusComplete,
+		Amount:    coin.NewAmountFromInt64(5000),
+		Timestamp: &timestamp,
+	}
+	const note = "Rent for June"
+
+	for query, expected := range map[string]bool{
+		"":                                 true,
+		"type=send":                        true,
+		"type=receive,send_to_self":        false,
+		"from=2024-06-01T00:00:00Z":        true,
+		"from=2024-06-02T00:00:00Z":        false,
+		"to=2024-06-01T12:00:00Z":          true,
+		"to=2024-05-31T00:00:00Z":          false,
+		"minAmount=5000&maxAmount=5000":    true,
+		"minAmount=5001":                   false,
+		"maxAmount=4999":                   false,
+		"status=complete":                  true,
+		"status=pending":                   false,
+		"note=rent":                        true,
+		"note=groceries":                   false,
+		"type=send&status=complete&note=j": true,
+	} {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		filter, err := parseTransactionFilter(values, parseSatAmount)
+		require.NoError(t, err)
+		require.Equal(t, expected, filter.matches(tx, note), query)
+	}
+
+	// Transactions without a timestamp don't match a date range.
+	filter, err := parseTransactionFilter(url.Values{"from": {"2024-01-01T00:00:00Z"}}, parseSatAmount)
+	require.NoError(t, err)
+	require.False(t, filter.matches(&accounts.TransactionData{Amount: coin.NewAmountFromInt64(1)}, ""))
+}
@@ -25,6 +25,9 @@ type DBInterface interface {
 	HeaderByHeight(height int) (*wire.BlockHeader, error)
 	// RevertTo deletes all headers after tip.
 	RevertTo(tip int) error
+	// Prune deletes all headers before the given height. Afterwards, HeaderByHeight returns nil
+	// for them.
+	Prune(height int) error
 	// Tip retrieves the current max. height.
 	Tip() (int, error)
 	// Flush forces the db changes to the filesystem.
@@ -20,6 +20,7 @@ type dbMock struct {
 	putHeader      func(height int, header *wire.BlockHeader) error
 	headerByHeight func(height int) (*wire.BlockHeader, error)
 	revertTo       func(tip int) error
+	prune          func(height int) error
 	tip            func() (int, error)
 	flush          func() error
 	close          func() error
@@ -43,6 +44,12 @@ func (db *dbMock) RevertTo(tip int) error {
 	}
 	return nil
 }
+func (db *dbMock) Prune(height int) error {
+	if db.prune != nil {
+		return db.prune(height)
+	}
+	return nil
+}
 func (db *dbMock) Tip() (int, error) {
 	if db.tip != nil {
 		return db.tip()
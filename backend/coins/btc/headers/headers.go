@@ -16,13 +16,17 @@ package headers
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/ltc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
 	"github.com/BitBoxSwiss/block-client-go/electrum/types"
@@ -36,6 +40,18 @@ import (
 
 const reorgLimit = 100
 
+// PrunedHeadersToKeep is the number of most recent headers kept on disk if pruning is enabled. It
+// must cover at least two difficulty retarget windows and the reorg limit.
+const PrunedHeadersToKeep = 10000
+
+// pruneInterval is how many headers are added before pruning again, to not rewrite the database
+// with every new header.
+const pruneInterval = 2016
+
+// maxParallelBatches is the maximum number of header batches requested at the same time when
+// catching up with the tip.
+const maxParallelBatches = 8
+
 // Event instances are sent to the onEvent callback.
 type Event string
 
@@ -47,8 +63,29 @@ const (
 	EventSynced Event = "synced"
 	// EventNewTip is fired when a new tip is known.
 	EventNewTip Event = "newTip"
+	// EventReorg is fired when synced headers were replaced by a competing chain. The details are
+	// available with LastReorg().
+	EventReorg Event = "reorg"
 )
 
+// Reorg describes a chain reorganization, in which the most recent synced headers were replaced by
+// the headers of a competing chain.
+type Reorg struct {
+	// Height is the height of the first replaced header.
+	Height int `json:"height"`
+	// Depth is the number of replaced headers.
+	Depth int `json:"depth"`
+}
+
+// pendingReorg keeps the hashes of the headers reverted after a reorg was detected until the
+// competing chain is synced, to find out which headers were actually replaced.
+type pendingReorg struct {
+	oldTip    int
+	oldHashes map[int]chainhash.Hash
+	// forkHeight is the first height at which the competing chain differs, or -1 if not found yet.
+	forkHeight int
+}
+
 // Interface represents the public API of this package.
 //
 //go:generate mockery -name Interface
@@ -58,6 +95,7 @@ type Interface interface {
 	VerifiedHeaderByHeight(int) (*wire.BlockHeader, error)
 	TipHeight() int
 	Status() (*Status, error)
+	LastReorg() *Reorg
 }
 
 // Headers manages syncing blockchain headers.
@@ -68,17 +106,27 @@ type Headers struct {
 	db              DBInterface
 	blockchain      blockchain.Interface
 	headersPerBatch int
-	lock            locker.Locker
-	// targetHeight is the potential tip height we are syncing up to.
-	targetHeight int
+	// headersPerBatchKnown is true once headersPerBatch was set to the maximum allowed by the
+	// server.
+	headersPerBatchKnown bool
+	lock                 locker.Locker
+	// targetHeight is the potential tip height we are syncing up to. It is updated concurrently by
+	// the headers subscription.
+	targetHeight atomic.Int64
 	// tipAtInitTime is the tip at init time, i.e. the last tip known, loaded from the DB. It is
 	// used to show the sync progress since the last time (catch up).
 	tipAtInitTime int
 	kickChan      chan struct{}
 	quitChan      chan struct{}
+	// headersToKeep is the number of most recent headers kept in the database. 0 means all headers
+	// are kept.
+	headersToKeep int
 
 	eventCallbacks []func(Event)
 
+	pendingReorg *pendingReorg
+	lastReorg    *Reorg
+
 	closed bool
 
 	// Only for testing, must be nil in production.
@@ -109,7 +157,6 @@ func NewHeaders(
 		// We start with a small batch size and increase to the maximum allowed one with the first
 		// response.
 		headersPerBatch: 10,
-		targetHeight:    0,
 		tipAtInitTime:   0,
 		kickChan:        make(chan struct{}, 1),
 		quitChan:        make(chan struct{}),
@@ -118,41 +165,47 @@ func NewHeaders(
 	}
 }
 
-// checkpoint returns the latest checkpoint for the current chain. It panics if the network is
-// unknown.
-func (headers *Headers) checkpoint() *chaincfg.Checkpoint {
-	// We define our own checkpoints over using headers.net.Checkpoints, because they are defined in
-	// the vendored btcd dep, and we want to control it. Furthermore, the chaincfg.Params are evil
-	// globals registered in the lib's `init()`, so we can't replicate the instances ourselves.
+// We define our own checkpoints over using headers.net.Checkpoints, because they are defined in
+// the vendored btcd dep, and we want to control it. Furthermore, the chaincfg.Params are evil
+// globals registered in the lib's `init()`, so we can't replicate the instances ourselves.
+//
+// If there are no headers yet, syncing starts at the checkpoint instead of the genesis block.
+var checkpoints = map[wire.BitcoinNet]*chaincfg.Checkpoint{
+	chaincfg.MainNetParams.Net: { // BTC
+		Height: 810000,
+		Hash:   mustUnhex("000000000000000000028028ca82b6aa81ce789e4eb9e0321b74c3cbaf405dd1"),
+	},
+	chaincfg.TestNet3Params.Net: { // TBTC
+		Height: 2344474,
+		Hash:   mustUnhex("0000000000000004877fa2d36316398528de4f347df2f8a96f76613a298ce060"),
+	},
+	ltc.MainNetParams.Net: { // LTC
+		Height: 1837000,
+		Hash:   mustUnhex("43e55db47d6fdbc6e9f1d2f7a8974689f10bc3abf6e27355564dd5e18bfa53e4"),
+	},
+	ltc.TestNet4Params.Net: { // TLTC
+		Height: 1464330,
+		Hash:   mustUnhex("4329edb4d3eb20baded30bc67f59ce7d951de176012688124a65fe55e60244b4"),
+	},
+}
 
-	mustUnhex := func(s string) *chainhash.Hash {
-		hash, err := chainhash.NewHashFromStr(s)
-		if err != nil {
-			panic(err)
-		}
-		return hash
-	}
-	switch headers.net.Net {
-	case chaincfg.MainNetParams.Net: // BTC
-		return &chaincfg.Checkpoint{
-			Height: 629350,
-			Hash:   mustUnhex("000000000000000000076807241b4e0f830c638cc1122cb67bfb856c58a21017"),
-		}
-	case chaincfg.TestNet3Params.Net: // TBTC
-		return &chaincfg.Checkpoint{
-			Height: 1723210,
-			Hash:   mustUnhex("00000000a2aa46899e5eda73c816b55903799a4feb321c903d9baeacc9443925")}
-	case ltc.MainNetParams.Net: // LTC
-		return &chaincfg.Checkpoint{
-			Height: 1837000,
-			Hash:   mustUnhex("43e55db47d6fdbc6e9f1d2f7a8974689f10bc3abf6e27355564dd5e18bfa53e4")}
-	case ltc.TestNet4Params.Net: // TLTC
-		return &chaincfg.Checkpoint{
-			Height: 1464330,
-			Hash:   mustUnhex("4329edb4d3eb20baded30bc67f59ce7d951de176012688124a65fe55e60244b4")}
-	default:
-		return nil
+func mustUnhex(s string) *chainhash.Hash {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		panic(err)
 	}
+	return hash
+}
+
+// checkpoint returns the latest checkpoint for the current chain, or nil if there is none.
+func (headers *Headers) checkpoint() *chaincfg.Checkpoint {
+	return checkpoints[headers.net.Net]
+}
+
+// SetPruning enables deleting all but the most recent headersToKeep headers from the database.
+// Transactions in pruned blocks can't be verified anymore. Must be called before Initialize().
+func (headers *Headers) SetPruning(headersToKeep int) {
+	headers.headersToKeep = headersToKeep
 }
 
 // SubscribeEvent subscribes to header events. The provided callback will be notified of events. The
@@ -168,12 +221,16 @@ func (headers *Headers) SubscribeEvent(f func(event Event)) func() {
 
 // TipHeight returns the height of the tip.
 func (headers *Headers) TipHeight() int {
-	return headers.targetHeight
+	return int(headers.targetHeight.Load())
 }
 
 // Initialize starts the syncing process.
 func (headers *Headers) Initialize() {
 	headers.tipAtInitTime = headers.tip()
+	if checkpoint := headers.checkpoint(); checkpoint != nil && headers.tipAtInitTime == -1 {
+		// Syncing starts at the checkpoint.
+		headers.tipAtInitTime = int(checkpoint.Height) - 1
+	}
 	headers.log.Infof("last tip loaded: %d", headers.tipAtInitTime)
 	go headers.download()
 	go headers.blockchain.HeadersSubscribe(
@@ -185,6 +242,7 @@ func (headers *Headers) Initialize() {
 }
 
 func (headers *Headers) download() {
+	defer crashreport.Recover()
 	defer func() {
 		// Only for testing.
 		if headers.testDownloadFinished != nil {
@@ -205,16 +263,37 @@ func (headers *Headers) download() {
 			// TODO
 			return
 		}
-		headersResult, err := headers.blockchain.Headers(tip+1, headers.headersPerBatch)
-		if err != nil {
-			// TODO
-			headers.log.WithError(err).Error("blockchain.Headers")
-			return
+		if checkpoint := headers.checkpoint(); checkpoint != nil && tip == -1 {
+			// No headers yet, start syncing at the checkpoint instead of the genesis block.
+			headers.log.Infof("starting to sync headers at checkpoint %d", checkpoint.Height)
+			tip = int(checkpoint.Height) - 1
 		}
-		if err := headers.processBatch(db, tip, headersResult.Headers, headersResult.Max); err != nil {
-			// TODO
-			headers.log.WithError(err).Error("processBatch")
-			return
+		batchSize := headers.headersPerBatch
+		numBatches := 1
+		if remaining := headers.TipHeight() - tip; headers.headersPerBatchKnown && remaining > batchSize {
+			numBatches = min(maxParallelBatches, (remaining+batchSize-1)/batchSize)
+		}
+		for _, batch := range headers.fetchBatches(tip+1, batchSize, numBatches) {
+			if batch.err != nil {
+				// TODO
+				headers.log.WithError(batch.err).Error("blockchain.Headers")
+				return
+			}
+			if err := headers.processBatch(db, tip, batch.result.Headers, batch.result.Max); err != nil {
+				// TODO
+				headers.log.WithError(err).Error("processBatch")
+				return
+			}
+			newTip, err := db.Tip()
+			if err != nil {
+				return
+			}
+			// Stop at the end of the chain, after a reorg or if the server changed the batch size.
+			if newTip != tip+len(batch.result.Headers) || len(batch.result.Headers) != batchSize ||
+				headers.headersPerBatch != batchSize {
+				return
+			}
+			tip = newTip
 		}
 	}
 
@@ -233,8 +312,35 @@ func (headers *Headers) download() {
 	}
 }
 
+type headersBatch struct {
+	result *blockchain.HeadersResult
+	err    error
+}
+
+// fetchBatches requests numBatches consecutive batches of headers at the same time, starting at
+// startHeight. The batches are returned in order.
+func (headers *Headers) fetchBatches(startHeight int, batchSize int, numBatches int) []headersBatch {
+	batches := make([]headersBatch, numBatches)
+	var wg sync.WaitGroup
+	for i := range batches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := headers.blockchain.Headers(
+				context.Background(), startHeight+i*batchSize, batchSize)
+			batches[i] = headersBatch{result: result, err: err}
+		}(i)
+	}
+	wg.Wait()
+	return batches
+}
+
 var errPrevHash = errors.New("header prevhash does not match")
 
+// errRetargetHeadersMissing is returned by getTarget() if the headers needed to compute the target
+// are before the checkpoint and were not downloaded.
+var errRetargetHeadersMissing = errors.New("headers needed for the difficulty retarget are missing")
+
 func (headers *Headers) getTarget(db DBInterface, index int) (*big.Int, error) {
 	targetTimespan := int64(headers.net.TargetTimespan / time.Second)
 	targetTimePerBlock := int64(headers.net.TargetTimePerBlock / time.Second)
@@ -255,6 +361,9 @@ func (headers *Headers) getTarget(db DBInterface, index int) (*big.Int, error) {
 		return nil, err
 	}
 	if first == nil {
+		if checkpoint := headers.checkpoint(); checkpoint != nil && firstIndex < int(checkpoint.Height) {
+			return nil, errRetargetHeadersMissing
+		}
 		return nil, errp.Newf("header at %d not found", firstIndex)
 	}
 	lastIndex := (chunkIndex+1)*blocksPerRetarget - 1
@@ -283,9 +392,20 @@ func (headers *Headers) getTarget(db DBInterface, index int) (*big.Int, error) {
 	return newTarget, nil
 }
 
+// validateDifficulty returns true if the difficulty and proof of work of the headers are checked.
+// Testnets allow minimum difficulty blocks, which are not supported.
+func (headers *Headers) validateDifficulty() bool {
+	switch headers.net.Net {
+	case chaincfg.MainNetParams.Net, chaincfg.SigNetParams.Net, ltc.MainNetParams.Net:
+		return true
+	default:
+		return false
+	}
+}
+
 func (headers *Headers) powHash(msg []byte) chainhash.Hash {
 	switch headers.net.Net {
-	case chaincfg.MainNetParams.Net:
+	case chaincfg.MainNetParams.Net, chaincfg.SigNetParams.Net:
 		return chainhash.DoubleHashH(msg)
 	case ltc.MainNetParams.Net:
 		const (
@@ -314,21 +434,26 @@ func (headers *Headers) canConnect(db DBInterface, tip int, header *wire.BlockHe
 				header.BlockHash(), *headers.net.GenesisHash)
 		}
 	} else {
+		lastCheckpoint := headers.checkpoint()
 		previousHeader, err := db.HeaderByHeight(tip - 1)
 		if err != nil {
 			return err
 		}
-		if previousHeader == nil {
+		switch {
+		case previousHeader == nil && lastCheckpoint != nil && tip == int(lastCheckpoint.Height):
+			// Syncing started at the checkpoint, there is nothing to connect to. The checkpoint
+			// hash is checked below.
+		case previousHeader == nil:
 			return errp.Newf("header at %d not found", tip-1)
-		}
-		prevBlock := previousHeader.BlockHash()
-		if header.PrevBlock != prevBlock {
-			return errp.Wrap(errPrevHash,
-				fmt.Sprintf("%s (%d) does not connect to %s (%d)",
-					header.PrevBlock, tip, prevBlock, tip-1))
+		default:
+			prevBlock := previousHeader.BlockHash()
+			if header.PrevBlock != prevBlock {
+				return errp.Wrap(errPrevHash,
+					fmt.Sprintf("%s (%d) does not connect to %s (%d)",
+						header.PrevBlock, tip, prevBlock, tip-1))
+			}
 		}
 
-		lastCheckpoint := headers.checkpoint()
 		if lastCheckpoint != nil && tip == int(lastCheckpoint.Height) {
 			if *lastCheckpoint.Hash != header.BlockHash() {
 				return errp.Newf("checkpoint mismatch at %d. Expected %s, got %s",
@@ -336,9 +461,17 @@ func (headers *Headers) canConnect(db DBInterface, tip int, header *wire.BlockHe
 			}
 			headers.log.Infof("checkpoint at %d matches", tip)
 		}
-		// Check Difficulty, PoW.
-		if headers.net.Net == chaincfg.MainNetParams.Net || headers.net.Net == ltc.MainNetParams.Net {
+		// Check Difficulty, PoW. Signet follows the mainnet difficulty rules. Its block signatures
+		// are in the coinbase transaction and can't be checked with headers only.
+		if headers.validateDifficulty() {
 			newTarget, err := headers.getTarget(db, tip)
+			if errp.Cause(err) == errRetargetHeadersMissing && previousHeader != nil {
+				newTarget, err = headers.getTargetAfterCheckpoint(tip, header, previousHeader)
+			}
+			if errp.Cause(err) == errRetargetHeadersMissing {
+				// The checkpoint header itself. Its difficulty is implied by the checkpoint hash.
+				newTarget, err = btcdBlockchain.CompactToBig(header.Bits), nil
+			}
 			if err != nil {
 				return err
 			}
@@ -350,7 +483,7 @@ func (headers *Headers) canConnect(db DBInterface, tip int, header *wire.BlockHe
 				panic(errp.WithStack(err))
 			}
 			// Skip PoW check before the checkpoint for performance.
-			if lastCheckpoint != nil && tip > int(lastCheckpoint.Height) {
+			if lastCheckpoint == nil || tip > int(lastCheckpoint.Height) {
 				powHash := headers.powHash(headerSerialized.Bytes())
 				proofOfWork := btcdBlockchain.HashToBig(&powHash)
 				if proofOfWork.Cmp(newTarget) > 0 {
@@ -362,6 +495,32 @@ func (headers *Headers) canConnect(db DBInterface, tip int, header *wire.BlockHe
 	return nil
 }
 
+// getTargetAfterCheckpoint returns the target of a header if syncing started at the checkpoint and
+// the headers needed to compute the target are not available. Within a retarget window, the target
+// must not change. At the start of a window, the target may change at most by the retarget
+// adjustment factor, which is also what the full computation allows.
+func (headers *Headers) getTargetAfterCheckpoint(
+	index int, header *wire.BlockHeader, previousHeader *wire.BlockHeader) (*big.Int, error) {
+	targetTimespan := int64(headers.net.TargetTimespan / time.Second)
+	targetTimePerBlock := int64(headers.net.TargetTimePerBlock / time.Second)
+	blocksPerRetarget := int(targetTimespan / targetTimePerBlock)
+	previousTarget := btcdBlockchain.CompactToBig(previousHeader.Bits)
+	if index%blocksPerRetarget != 0 {
+		return previousTarget, nil
+	}
+	factor := big.NewInt(headers.net.RetargetAdjustmentFactor)
+	minTarget := new(big.Int).Div(previousTarget, factor)
+	maxTarget := new(big.Int).Mul(previousTarget, factor)
+	if maxTarget.Cmp(headers.net.PowLimit) > 0 {
+		maxTarget.Set(headers.net.PowLimit)
+	}
+	target := btcdBlockchain.CompactToBig(header.Bits)
+	if target.Cmp(minTarget) < 0 || target.Cmp(maxTarget) > 0 {
+		return nil, errp.Newf("header %d has an unexpected difficulty", index)
+	}
+	return target, nil
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -377,16 +536,84 @@ func (headers *Headers) reorg(db DBInterface, tip int) {
 	if newTip < -1 {
 		newTip = -1
 	}
+	// There can't be a reorg before the checkpoint.
+	if checkpoint := headers.checkpoint(); checkpoint != nil &&
+		tip >= int(checkpoint.Height) && newTip < int(checkpoint.Height) {
+		newTip = int(checkpoint.Height)
+	}
+	if headers.pendingReorg == nil {
+		headers.pendingReorg = &pendingReorg{
+			oldTip:     tip,
+			oldHashes:  map[int]chainhash.Hash{},
+			forkHeight: -1,
+		}
+	}
+	pending := headers.pendingReorg
+	pending.oldTip = max(pending.oldTip, tip)
+	for height := newTip + 1; height <= tip; height++ {
+		if _, ok := pending.oldHashes[height]; ok {
+			// Keep the hash of the header which was replaced first.
+			continue
+		}
+		header, err := db.HeaderByHeight(height)
+		if err != nil {
+			panic(err)
+		}
+		if header != nil {
+			pending.oldHashes[height] = header.BlockHash()
+		}
+	}
 	if err := db.RevertTo(newTip); err != nil {
 		panic(err)
 	}
 	headers.kick()
 }
 
+// checkReorg compares the header stored at height with the header reverted there, if any, to find
+// the first replaced header.
+func (headers *Headers) checkReorg(height int, header *wire.BlockHeader) {
+	pending := headers.pendingReorg
+	if pending == nil || pending.forkHeight != -1 {
+		return
+	}
+	if oldHash, ok := pending.oldHashes[height]; ok && oldHash != header.BlockHash() {
+		pending.forkHeight = height
+	}
+}
+
+// finishReorg is called once the competing chain is synced up to tip, and fires EventReorg if
+// headers were replaced.
+func (headers *Headers) finishReorg(tip int) {
+	pending := headers.pendingReorg
+	headers.pendingReorg = nil
+	forkHeight := pending.forkHeight
+	if forkHeight == -1 {
+		if tip >= pending.oldTip {
+			// The same headers were synced again.
+			return
+		}
+		// The competing chain is shorter.
+		forkHeight = tip + 1
+	}
+	headers.lastReorg = &Reorg{Height: forkHeight, Depth: pending.oldTip - forkHeight + 1}
+	headers.log.Infof("Reorg of depth %d at height %d", headers.lastReorg.Depth, forkHeight)
+	headers.notifyEvent(EventReorg)
+}
+
+// LastReorg returns the most recent reorg since the headers were initialized, or nil if there was
+// none.
+func (headers *Headers) LastReorg() *Reorg {
+	defer headers.lock.RLock()()
+	return headers.lastReorg
+}
+
 func (headers *Headers) notifyEvent(event Event) {
 	for _, f := range headers.eventCallbacks {
 		if f != nil {
-			go f(event)
+			go func() {
+				defer crashreport.Recover()
+				f(event)
+			}()
 		}
 	}
 }
@@ -407,11 +634,26 @@ func (headers *Headers) processBatch(
 		if err := db.PutHeader(tip, header); err != nil {
 			return err
 		}
+		headers.checkReorg(tip, header)
+	}
+	if pending := headers.pendingReorg; pending != nil &&
+		(tip >= pending.oldTip || len(blockHeaders) < min(max, headers.headersPerBatch)) {
+		headers.finishReorg(tip)
 	}
 	if err := db.Flush(); err != nil {
 		// Ignore error, not critical.
 		headers.log.WithError(err).Error("Failed to flush")
 	}
+	if headers.headersToKeep > 0 {
+		// Prune at multiples of pruneInterval, keeping at least headersToKeep headers.
+		pruneHeight := (tip - headers.headersToKeep + 1) / pruneInterval * pruneInterval
+		if pruneHeight > 0 {
+			if err := db.Prune(pruneHeight); err != nil {
+				// Ignore error, not critical.
+				headers.log.WithError(err).Error("Failed to prune")
+			}
+		}
+	}
 	if len(blockHeaders) == min(max, headers.headersPerBatch) {
 		// Received max number of headers per batch, so there might be more.
 		headers.kick()
@@ -422,11 +664,15 @@ func (headers *Headers) processBatch(
 		headers.notifyEvent(EventSynced)
 	}
 	headers.headersPerBatch = max
+	headers.headersPerBatchKnown = true
 	return nil
 }
 
 // VerifiedHeaderByHeight returns the header at the given height. Returns nil if the headers are not synced
-// up to this height yet OR if the headers are not synced up to the latest checkpoint yet.
+// up to this height yet OR if the headers are not synced up to the latest checkpoint yet OR if the
+// header is not stored, i.e. it is before the checkpoint and syncing started at the checkpoint, or
+// it was pruned. Such headers can't be linked to the stored headers, so transactions in them
+// remain unverified. Transactions that were verified before their header was pruned stay verified.
 func (headers *Headers) VerifiedHeaderByHeight(height int) (*wire.BlockHeader, error) {
 	defer headers.lock.RLock()()
 
@@ -454,7 +700,7 @@ func (headers *Headers) kick() {
 func (headers *Headers) update(blockHeight int) {
 	headers.log.Debugf("new target %d", blockHeight)
 	headers.kick()
-	headers.targetHeight = blockHeight
+	headers.targetHeight.Store(int64(blockHeight))
 	headers.notifyEvent(EventNewTip)
 }
 
@@ -488,7 +734,7 @@ func (headers *Headers) Status() (*Status, error) {
 	return &Status{
 		TipAtInitTime: headers.tipAtInitTime,
 		Tip:           tip,
-		TargetHeight:  headers.targetHeight,
+		TargetHeight:  headers.TipHeight(),
 		TipHashHex:    tipHashHex,
 	}, nil
 }
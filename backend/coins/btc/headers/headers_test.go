@@ -15,11 +15,16 @@
 package headers
 
 import (
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain/mocks"
+	"github.com/BitBoxSwiss/block-client-go/electrum/types"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
@@ -45,3 +50,305 @@ func TestClose(t *testing.T) {
 	}
 
 }
+
+// memDB is an in-memory DBInterface.
+type memDB struct {
+	mu      sync.Mutex
+	headers map[int]*wire.BlockHeader
+	tip     int
+}
+
+func (db *memDB) PutHeader(height int, header *wire.BlockHeader) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.headers[height] = header
+	if height > db.tip {
+		db.tip = height
+	}
+	return nil
+}
+func (db *memDB) HeaderByHeight(height int) (*wire.BlockHeader, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.headers[height], nil
+}
+func (db *memDB) RevertTo(tip int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for height := range db.headers {
+		if height > tip {
+			delete(db.headers, height)
+		}
+	}
+	db.tip = tip
+	return nil
+}
+func (db *memDB) Prune(height int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for h := range db.headers {
+		if h < height {
+			delete(db.headers, h)
+		}
+	}
+	return nil
+}
+func (db *memDB) Tip() (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.tip, nil
+}
+func (db *memDB) Flush() error { return nil }
+func (db *memDB) Close() error { return nil }
+
+func TestSyncFromCheckpoint(t *testing.T) {
+	net := &chaincfg.RegressionNetParams
+	chain := []*wire.BlockHeader{&net.GenesisBlock.Header}
+	for i := 1; i < 20; i++ {
+		chain = append(chain, &wire.BlockHeader{PrevBlock: chain[i-1].BlockHash(), Nonce: uint32(i)})
+	}
+	const checkpointHeight = 8
+	checkpointHash := chain[checkpointHeight].BlockHash()
+	checkpoints[net.Net] = &chaincfg.Checkpoint{Height: checkpointHeight, Hash: &checkpointHash}
+	defer delete(checkpoints, net.Net)
+
+	var mu sync.Mutex
+	var requestedHeights []int
+	blockchainMock := &mocks.BlockchainMock{
+		MockHeaders: func(startHeight int, count int) (*blockchain.HeadersResult, error) {
+			mu.Lock()
+			requestedHeights = append(requestedHeights, startHeight)
+			mu.Unlock()
+			end := min(startHeight+count, len(chain))
+			return &blockchain.HeadersResult{Headers: chain[startHeight:end], Max: 2016}, nil
+		},
+	}
+	db := &memDB{headers: map[int]*wire.BlockHeader{}, tip: -1}
+	headers := NewHeaders(net, db, blockchainMock, (&logrus.Logger{}).WithField("group", "headers_test"))
+	headers.Initialize()
+	defer func() { require.NoError(t, headers.Close()) }()
+
+	require.Eventually(t, func() bool {
+		tip, _ := db.Tip()
+		return tip == len(chain)-1
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.Equal(t, checkpointHeight, requestedHeights[0])
+	mu.Unlock()
+	require.Equal(t, checkpointHeight-1, headers.tipAtInitTime)
+	for height := 0; height < checkpointHeight; height++ {
+		header, err := db.HeaderByHeight(height)
+		require.NoError(t, err)
+		require.Nil(t, header)
+	}
+	header, err := headers.VerifiedHeaderByHeight(12)
+	require.NoError(t, err)
+	require.Equal(t, chain[12].BlockHash(), header.BlockHash())
+	// Headers before the checkpoint can't be linked to the checkpoint and are not fetched.
+	header, err = headers.VerifiedHeaderByHeight(3)
+	require.NoError(t, err)
+	require.Nil(t, header)
+	mu.Lock()
+	require.NotContains(t, requestedHeights, 3)
+	mu.Unlock()
+}
+
+func TestSyncFromCheckpointMismatch(t *testing.T) {
+	net := &chaincfg.RegressionNetParams
+	checkpointHash := chainhash.Hash{1}
+	checkpoints[net.Net] = &chaincfg.Checkpoint{Height: 1, Hash: &checkpointHash}
+	defer delete(checkpoints, net.Net)
+
+	fakeHeader := &wire.BlockHeader{PrevBlock: *net.GenesisHash}
+	db := &memDB{headers: map[int]*wire.BlockHeader{}, tip: -1}
+	headers := NewHeaders(net, db, &mocks.BlockchainMock{}, (&logrus.Logger{}).WithField("group", "headers_test"))
+	require.Error(t, headers.processBatch(db, 0, []*wire.BlockHeader{fakeHeader}, 2016))
+	tip, err := db.Tip()
+	require.NoError(t, err)
+	require.Equal(t, -1, tip)
+}
+
+func TestSignetDifficulty(t *testing.T) {
+	net := &chaincfg.SigNetParams
+	db := &memDB{headers: map[int]*wire.BlockHeader{}, tip: -1}
+	headers := NewHeaders(net, db, &mocks.BlockchainMock{}, (&logrus.Logger{}).WithField("group", "headers_test"))
+	require.NoError(t, headers.canConnect(db, 0, &net.GenesisBlock.Header))
+	require.NoError(t, db.PutHeader(0, &net.GenesisBlock.Header))
+
+	header := &wire.BlockHeader{
+		PrevBlock: *net.GenesisHash,
+		Timestamp: net.GenesisBlock.Header.Timestamp.Add(10 * time.Minute),
+		Bits:      net.GenesisBlock.Header.Bits - 1,
+	}
+	err := headers.canConnect(db, 1, header)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected difficulty")
+
+	header.Bits = net.GenesisBlock.Header.Bits
+	err = headers.canConnect(db, 1, header)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "insufficient proof of work")
+}
+
+func TestParallelDownload(t *testing.T) {
+	net := &chaincfg.RegressionNetParams
+	chain := []*wire.BlockHeader{&net.GenesisBlock.Header}
+	for i := 1; i < 200; i++ {
+		chain = append(chain, &wire.BlockHeader{PrevBlock: chain[i-1].BlockHash(), Nonce: uint32(i)})
+	}
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	blockchainMock := &mocks.BlockchainMock{
+		MockHeadersSubscribe: func(result func(*types.Header)) {
+			result(&types.Header{Height: len(chain) - 1})
+		},
+		MockHeaders: func(startHeight int, count int) (*blockchain.HeadersResult, error) {
+			mu.Lock()
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			end := min(startHeight+min(count, 20), len(chain))
+			if startHeight >= end {
+				return &blockchain.HeadersResult{Headers: []*wire.BlockHeader{}, Max: 20}, nil
+			}
+			return &blockchain.HeadersResult{Headers: chain[startHeight:end], Max: 20}, nil
+		},
+	}
+	db := &memDB{headers: map[int]*wire.BlockHeader{}, tip: -1}
+	headers := NewHeaders(net, db, blockchainMock, (&logrus.Logger{}).WithField("group", "headers_test"))
+	headers.Initialize()
+	defer func() { require.NoError(t, headers.Close()) }()
+
+	require.Eventually(t, func() bool {
+		tip, _ := db.Tip()
+		return tip == len(chain)-1
+	}, 5*time.Second, 10*time.Millisecond)
+	for height, header := range chain {
+		stored, err := db.HeaderByHeight(height)
+		require.NoError(t, err)
+		require.Equal(t, header.BlockHash(), stored.BlockHash())
+	}
+	mu.Lock()
+	require.Greater(t, maxInFlight, 1)
+	mu.Unlock()
+}
+
+func TestPruning(t *testing.T) {
+	net := &chaincfg.RegressionNetParams
+	chain := []*wire.BlockHeader{&net.GenesisBlock.Header}
+	for i := 1; i < 2200; i++ {
+		chain = append(chain, &wire.BlockHeader{PrevBlock: chain[i-1].BlockHash(), Nonce: uint32(i)})
+	}
+	blockchainMock := &mocks.BlockchainMock{
+		MockHeaders: func(startHeight int, count int) (*blockchain.HeadersResult, error) {
+			end := min(startHeight+count, len(chain))
+			if startHeight >= end {
+				return &blockchain.HeadersResult{Headers: []*wire.BlockHeader{}, Max: 2016}, nil
+			}
+			return &blockchain.HeadersResult{Headers: chain[startHeight:end], Max: 2016}, nil
+		},
+	}
+	db := &memDB{headers: map[int]*wire.BlockHeader{}, tip: -1}
+	headers := NewHeaders(net, db, blockchainMock, (&logrus.Logger{}).WithField("group", "headers_test"))
+	headers.SetPruning(100)
+	headers.Initialize()
+	defer func() { require.NoError(t, headers.Close()) }()
+
+	require.Eventually(t, func() bool {
+		tip, _ := db.Tip()
+		return tip == len(chain)-1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Pruned at the last multiple of the prune interval that keeps at least 100 headers.
+	header, err := db.HeaderByHeight(2015)
+	require.NoError(t, err)
+	require.Nil(t, header)
+	header, err = db.HeaderByHeight(2016)
+	require.NoError(t, err)
+	require.NotNil(t, header)
+
+	// Pruned headers can't be linked to the stored headers and are not fetched.
+	header, err = headers.VerifiedHeaderByHeight(100)
+	require.NoError(t, err)
+	require.Nil(t, header)
+	header, err = headers.VerifiedHeaderByHeight(2016)
+	require.NoError(t, err)
+	require.Equal(t, chain[2016].BlockHash(), header.BlockHash())
+}
+
+func TestReorg(t *testing.T) {
+	net := &chaincfg.RegressionNetParams
+	makeChain := func(chain []*wire.BlockHeader, length int, nonce uint32) []*wire.BlockHeader {
+		for i := len(chain); i < length; i++ {
+			chain = append(chain, &wire.BlockHeader{PrevBlock: chain[i-1].BlockHash(), Nonce: nonce + uint32(i)})
+		}
+		return chain
+	}
+	chain := makeChain([]*wire.BlockHeader{&net.GenesisBlock.Header}, 30, 0)
+	// The competing chain replaces the headers from height 25 and is two headers longer.
+	competingChain := makeChain(append([]*wire.BlockHeader{}, chain[:25]...), 32, 1000)
+
+	var mu sync.Mutex
+	serverChain := chain
+	var onHeader func(*types.Header)
+	blockchainMock := &mocks.BlockchainMock{
+		MockHeadersSubscribe: func(result func(*types.Header)) {
+			mu.Lock()
+			onHeader = result
+			mu.Unlock()
+		},
+		MockHeaders: func(startHeight int, count int) (*blockchain.HeadersResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			end := min(startHeight+count, len(serverChain))
+			if startHeight >= end {
+				return &blockchain.HeadersResult{Headers: []*wire.BlockHeader{}, Max: 2016}, nil
+			}
+			return &blockchain.HeadersResult{Headers: serverChain[startHeight:end], Max: 2016}, nil
+		},
+	}
+	db := &memDB{headers: map[int]*wire.BlockHeader{}, tip: -1}
+	headers := NewHeaders(net, db, blockchainMock, (&logrus.Logger{}).WithField("group", "headers_test"))
+	reorgs := make(chan struct{}, 1)
+	headers.SubscribeEvent(func(event Event) {
+		if event == EventReorg {
+			reorgs <- struct{}{}
+		}
+	})
+	headers.Initialize()
+	defer func() { require.NoError(t, headers.Close()) }()
+
+	require.Eventually(t, func() bool {
+		tip, _ := db.Tip()
+		return tip == len(chain)-1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, headers.LastReorg())
+
+	mu.Lock()
+	serverChain = competingChain
+	notify := onHeader
+	mu.Unlock()
+	require.NotNil(t, notify)
+	notify(&types.Header{Height: len(competingChain) - 1})
+
+	select {
+	case <-reorgs:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no reorg event")
+	}
+	require.Equal(t, &Reorg{Height: 25, Depth: 5}, headers.LastReorg())
+	require.Eventually(t, func() bool {
+		tip, _ := db.Tip()
+		return tip == len(competingChain)-1
+	}, 5*time.Second, 10*time.Millisecond)
+	for height, header := range competingChain {
+		stored, err := db.HeaderByHeight(height)
+		require.NoError(t, err)
+		require.Equal(t, header.BlockHash(), stored.BlockHash())
+	}
+}
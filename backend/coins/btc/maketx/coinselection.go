@@ -0,0 +1,260 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maketx
+
+import (
+	"sort"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// CoinSelection is the algorithm used to select the coins spent by a new transaction.
+type CoinSelection string
+
+const (
+	// CoinSelectionLargestFirst spends the coins with the largest values first. This is the
+	// default.
+	CoinSelectionLargestFirst CoinSelection = "largestFirst"
+	// CoinSelectionBranchAndBound searches for a set of coins paying the amount and fee without
+	// needing a change output. If there is no such set, it falls back to
+	// CoinSelectionLargestFirst.
+	CoinSelectionBranchAndBound CoinSelection = "branchAndBound"
+	// CoinSelectionSingleAddress spends all coins of a single address, so that the transaction
+	// does not link several addresses of the wallet.
+	CoinSelectionSingleAddress CoinSelection = "singleAddress"
+)
+
+// NewCoinSelection parses a coin selection algorithm. An empty string results in the default,
+// CoinSelectionLargestFirst.
+func NewCoinSelection(coinSelection string) (CoinSelection, error) {
+	switch CoinSelection(coinSelection) {
+	case "":
+		return CoinSelectionLargestFirst, nil
+	case CoinSelectionLargestFirst, CoinSelectionBranchAndBound, CoinSelectionSingleAddress:
+		return CoinSelection(coinSelection), nil
+	default:
+		return "", errp.Newf("unknown coin selection %q", coinSelection)
+	}
+}
+
+// bnbMaxTries limits the number of steps of the branch-and-bound search, as the number of coin
+// combinations grows exponentially.
+const bnbMaxTries = 100000
+
+type byValue struct {
+	outPoints []wire.OutPoint
+	outputs   map[wire.OutPoint]UTXO
+}
+
+func (p *byValue) Len() int { return len(p.outPoints) }
+func (p *byValue) Less(i, j int) bool {
+	if p.outputs[p.outPoints[i]].TxOut.Value == p.outputs[p.outPoints[j]].TxOut.Value {
+		// Secondary sort to make coin selection deterministic.
+		return chainhash.HashH(p.outputs[p.outPoints[i]].TxOut.PkScript).String() < chainhash.HashH(p.outputs[p.outPoints[j]].TxOut.PkScript).String()
+	}
+	return p.outputs[p.outPoints[i]].TxOut.Value < p.outputs[p.outPoints[j]].TxOut.Value
+}
+func (p *byValue) Swap(i, j int) { p.outPoints[i], p.outPoints[j] = p.outPoints[j], p.outPoints[i] }
+
+// sortedByValue returns the outpoints of the given outputs, largest value first.
+func sortedByValue(outputs map[wire.OutPoint]UTXO) []wire.OutPoint {
+	outPoints := []wire.OutPoint{}
+	for outPoint := range outputs {
+		outPoints = append(outPoints, outPoint)
+	}
+	sort.Sort(sort.Reverse(&byValue{outPoints, outputs}))
+	return outPoints
+}
+
+// selectCoins selects coins worth at least minAmount using the given algorithm.
+// CoinSelectionBranchAndBound is handled separately by branchAndBound(), as it does not work with
+// a fixed minimum amount. Here it behaves like its fallback, CoinSelectionLargestFirst.
+func selectCoins(
+	coinSelection CoinSelection,
+	minAmount btcutil.Amount,
+	outputs map[wire.OutPoint]UTXO,
+) (btcutil.Amount, []wire.OutPoint, error) {
+	if coinSelection == CoinSelectionSingleAddress {
+		return selectSingleAddress(minAmount, outputs)
+	}
+	return selectLargestFirst(minAmount, outputs)
+}
+
+func selectLargestFirst(
+	minAmount btcutil.Amount,
+	outputs map[wire.OutPoint]UTXO,
+) (btcutil.Amount, []wire.OutPoint, error) {
+	selectedOutPoints := []wire.OutPoint{}
+	outputsSum := btcutil.Amount(0)
+
+	for _, outPoint := range sortedByValue(outputs) {
+		if outputsSum >= minAmount {
+			break
+		}
+		selectedOutPoints = append(selectedOutPoints, outPoint)
+		outputsSum += btcutil.Amount(outputs[outPoint].TxOut.Value)
+	}
+	if outputsSum < minAmount {
+		return 0, nil, errp.WithStack(errors.ErrInsufficientFunds)
+	}
+	return outputsSum, selectedOutPoints, nil
+}
+
+// selectSingleAddress selects all coins of the address with the smallest balance that is at
+// least minAmount, keeping addresses with larger balances for larger payments.
+func selectSingleAddress(
+	minAmount btcutil.Amount,
+	outputs map[wire.OutPoint]UTXO,
+) (btcutil.Amount, []wire.OutPoint, error) {
+	balances := map[string]btcutil.Amount{}
+	for _, output := range outputs {
+		balances[string(output.TxOut.PkScript)] += btcutil.Amount(output.TxOut.Value)
+	}
+	found := false
+	var selectedPkScript string
+	for pkScript, balance := range balances {
+		if balance < minAmount {
+			continue
+		}
+		if !found || balance < balances[selectedPkScript] ||
+			(balance == balances[selectedPkScript] && pkScript < selectedPkScript) {
+			selectedPkScript = pkScript
+			found = true
+		}
+	}
+	if !found {
+		return 0, nil, errp.WithStack(errors.ErrInsufficientFunds)
+	}
+	selectedOutPoints := []wire.OutPoint{}
+	for _, outPoint := range sortedByValue(outputs) {
+		if string(outputs[outPoint].TxOut.PkScript) == selectedPkScript {
+			selectedOutPoints = append(selectedOutPoints, outPoint)
+		}
+	}
+	return balances[selectedPkScript], selectedOutPoints, nil
+}
+
+// inputSize returns the size of an input spending an output of the given configuration, in vbytes.
+func inputSize(configuration *signing.Configuration) int {
+	sigScriptSize, witnessSize := sigScriptWitnessSize(configuration)
+	// Round up to whole vbytes.
+	return (4*calcInputSize(sigScriptSize) + witnessSize + 3) / 4
+}
+
+// branchAndBound searches for a set of coins whose effective values, i.e. their values minus the
+// fee needed to spend them, sum up to at least target, and exceed it by at most costOfChange. Such
+// a transaction does not need a change output, and the excess is added to the fee, which is
+// cheaper than creating and later spending a change output. nil is returned if no such set is
+// found within bnbMaxTries steps.
+//
+// The coins are explored largest first, excluding branches which can't reach the target anymore
+// or already exceed it by more than costOfChange.
+func branchAndBound(
+	outputs map[wire.OutPoint]UTXO,
+	target btcutil.Amount,
+	costOfChange btcutil.Amount,
+	feePerKb btcutil.Amount,
+) []wire.OutPoint {
+	type candidate struct {
+		outPoint       wire.OutPoint
+		effectiveValue btcutil.Amount
+	}
+	candidates := []candidate{}
+	available := btcutil.Amount(0)
+	for _, outPoint := range sortedByValue(outputs) {
+		output := outputs[outPoint]
+		inputFee := feePerKb * btcutil.Amount(inputSize(output.Address.Configuration)) / 1000
+		effectiveValue := btcutil.Amount(output.TxOut.Value) - inputFee
+		if effectiveValue <= 0 {
+			// Spending this coin costs more than it is worth.
+			continue
+		}
+		candidates = append(candidates, candidate{outPoint: outPoint, effectiveValue: effectiveValue})
+		available += effectiveValue
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].effectiveValue > candidates[j].effectiveValue
+	})
+
+	selected := []wire.OutPoint{}
+	tries := 0
+	var search func(index int, sum btcutil.Amount, remaining btcutil.Amount) bool
+	search = func(index int, sum btcutil.Amount, remaining btcutil.Amount) bool {
+		tries++
+		if tries > bnbMaxTries || sum > target+costOfChange || sum+remaining < target {
+			return false
+		}
+		if sum >= target {
+			return true
+		}
+		if index == len(candidates) {
+			return false
+		}
+		current := candidates[index]
+		remaining -= current.effectiveValue
+		// Explore including the coin first, then excluding it.
+		selected = append(selected, current.outPoint)
+		if search(index+1, sum+current.effectiveValue, remaining) {
+			return true
+		}
+		selected = selected[:len(selected)-1]
+		return search(index+1, sum, remaining)
+	}
+	if !search(0, 0, available) || len(selected) == 0 {
+		return nil
+	}
+	return selected
+}
+
+// changelessSelection uses branchAndBound() to select coins paying targetAmount to outputs of the
+// given pkScript sizes and the fee of a transaction without a change output. The sum of the
+// selected coins is returned alongside. nil is returned if no such selection was found.
+func changelessSelection(
+	outputs map[wire.OutPoint]UTXO,
+	targetAmount btcutil.Amount,
+	outputPkScriptSizes []int,
+	changeAddress *addresses.AccountAddress,
+	feePerKb btcutil.Amount,
+	log *logrus.Entry,
+) (btcutil.Amount, []wire.OutPoint) {
+	// The fee of the transaction without inputs. The fee of the inputs is covered by the
+	// difference between the value and the effective value of the coins.
+	baseFee := feeForSerializeSize(feePerKb, estimateTxSizeOutputs(nil, outputPkScriptSizes, 0), log)
+	// Creating a change output and spending it later.
+	costOfChange := feePerKb * btcutil.Amount(
+		outputSize(len(changeAddress.PubkeyScript()))+inputSize(changeAddress.Configuration)) / 1000
+	selectedOutPoints := branchAndBound(outputs, targetAmount+baseFee, costOfChange, feePerKb)
+	if selectedOutPoints == nil {
+		return 0, nil
+	}
+	selectedOutputsSum := btcutil.Amount(0)
+	for _, outPoint := range selectedOutPoints {
+		selectedOutputsSum += btcutil.Amount(outputs[outPoint].TxOut.Value)
+	}
+	// The effective values are rounded per input, so check the fee of the whole transaction.
+	txSize := estimateTxSizeOutputs(
+		toInputConfigurations(outputs, selectedOutPoints), outputPkScriptSizes, 0)
+	if selectedOutputsSum-targetAmount < feeForSerializeSize(feePerKb, txSize, log) {
+		return 0, nil
+	}
+	return selectedOutputsSum, selectedOutPoints
+}
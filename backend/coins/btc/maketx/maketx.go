@@ -15,10 +15,10 @@
 package maketx
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
 	mrand "math/rand"
-	"sort"
 	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
@@ -28,7 +28,6 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/btcsuite/btcd/btcutil"
-	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/sirupsen/logrus"
@@ -60,6 +59,12 @@ type TxProposal struct {
 	SilentPaymentAddress string
 	// OutIndex is the index of the output we send to.
 	OutIndex int
+	// If not empty, the transaction is sent using Payjoin (BIP-78) with the receiver at this
+	// endpoint.
+	PayjoinURL string
+	// CoinSelection is the algorithm which selected the spent coins. It is empty if the coins were
+	// not selected by an algorithm, e.g. when spending all coins.
+	CoinSelection CoinSelection
 }
 
 // SigHashes computes the hashes cache to speed up per-input sighash computations.
@@ -78,46 +83,6 @@ type UTXO struct {
 	Address *addresses.AccountAddress
 }
 
-type byValue struct {
-	outPoints []wire.OutPoint
-	outputs   map[wire.OutPoint]UTXO
-}
-
-func (p *byValue) Len() int { return len(p.outPoints) }
-func (p *byValue) Less(i, j int) bool {
-	if p.outputs[p.outPoints[i]].TxOut.Value == p.outputs[p.outPoints[j]].TxOut.Value {
-		// Secondary sort to make coin selection deterministic.
-		return chainhash.HashH(p.outputs[p.outPoints[i]].TxOut.PkScript).String() < chainhash.HashH(p.outputs[p.outPoints[j]].TxOut.PkScript).String()
-	}
-	return p.outputs[p.outPoints[i]].TxOut.Value < p.outputs[p.outPoints[j]].TxOut.Value
-}
-func (p *byValue) Swap(i, j int) { p.outPoints[i], p.outPoints[j] = p.outPoints[j], p.outPoints[i] }
-
-func coinSelection(
-	minAmount btcutil.Amount,
-	outputs map[wire.OutPoint]UTXO,
-) (btcutil.Amount, []wire.OutPoint, error) {
-	outPoints := []wire.OutPoint{}
-	for outPoint := range outputs {
-		outPoints = append(outPoints, outPoint)
-	}
-	sort.Sort(sort.Reverse(&byValue{outPoints, outputs}))
-	selectedOutPoints := []wire.OutPoint{}
-	outputsSum := btcutil.Amount(0)
-
-	for _, outPoint := range outPoints {
-		if outputsSum >= minAmount {
-			break
-		}
-		selectedOutPoints = append(selectedOutPoints, outPoint)
-		outputsSum += btcutil.Amount(outputs[outPoint].TxOut.Value)
-	}
-	if outputsSum < minAmount {
-		return 0, nil, errp.WithStack(errors.ErrInsufficientFunds)
-	}
-	return outputsSum, selectedOutPoints, nil
-}
-
 // toInputConfigurations converts selected inputs to input configurations.
 // Currently, it just repeats one inputConfiguration, as all inputs are of the same type.
 // When mixing input types in a transaction, this function needs to be extended.
@@ -137,6 +102,7 @@ func setRBF(coin coinpkg.Coin, tx *wire.MsgTx) {
 	for _, txIn := range tx.TxIn {
 		if coin.Code() == coinpkg.CodeBTC ||
 			coin.Code() == coinpkg.CodeTBTC ||
+			coin.Code() == coinpkg.CodeTSIG ||
 			coin.Code() == coinpkg.CodeRBTC {
 			// Enable RBF
 			// https://github.com/bitcoin/bips/blob/master/bip-0125.mediawiki#summary
@@ -225,6 +191,9 @@ func NewTxSpendAll(
 // the unspent outputs is selected to cover the needed amount.
 //
 // changeAddress: a change output to this address is added if needed.
+//
+// coinSelection: the algorithm selecting the coins. The algorithm actually used is stored in the
+// proposal, as CoinSelectionBranchAndBound can fall back to CoinSelectionLargestFirst.
 func NewTx(
 	coin coinpkg.Coin,
 	spendableOutputs map[wire.OutPoint]UTXO,
@@ -232,92 +201,289 @@ func NewTx(
 	outputAmount int64,
 	feePerKb btcutil.Amount,
 	changeAddress *addresses.AccountAddress,
+	coinSelection CoinSelection,
 	log *logrus.Entry,
 ) (*TxProposal, error) {
-	output := wire.NewTxOut(outputAmount, outputInfo.pkScript)
+	return NewTxMultipleRecipients(
+		coin,
+		spendableOutputs,
+		[]Recipient{{OutputInfo: outputInfo, Amount: outputAmount}},
+		feePerKb,
+		changeAddress,
+		coinSelection,
+		log,
+	)
+}
 
-	targetAmount := btcutil.Amount(output.Value)
-	if targetAmount <= 0 {
-		panic("amount must be positive")
+// Recipient is an output of a new transaction paying the given amount.
+type Recipient struct {
+	OutputInfo *OutputInfo
+	Amount     int64
+}
+
+// NewTxMultipleRecipients is like NewTx, but pays several recipients in one transaction. The
+// amount of the returned proposal is the sum of all recipient amounts, and OutIndex is the index
+// of the first recipient's output. Silent payment recipients are only supported if there is a
+// single recipient.
+func NewTxMultipleRecipients(
+	coin coinpkg.Coin,
+	spendableOutputs map[wire.OutPoint]UTXO,
+	recipients []Recipient,
+	feePerKb btcutil.Amount,
+	changeAddress *addresses.AccountAddress,
+	coinSelection CoinSelection,
+	log *logrus.Entry,
+) (*TxProposal, error) {
+	if len(recipients) == 0 {
+		return nil, errp.New("at least one recipient is required")
 	}
-	outputs := []*wire.TxOut{output}
+	outputs := make([]*wire.TxOut, len(recipients))
+	outputPkScriptSizes := make([]int, len(recipients))
+	targetAmount := btcutil.Amount(0)
+	for i, recipient := range recipients {
+		if recipient.Amount <= 0 {
+			panic("amount must be positive")
+		}
+		if len(recipients) > 1 && recipient.OutputInfo.silentPaymentAddress != "" {
+			return nil, errp.New("silent payments are not supported in transactions with multiple recipients")
+		}
+		outputs[i] = wire.NewTxOut(recipient.Amount, recipient.OutputInfo.pkScript)
+		outputPkScriptSizes[i] = recipient.OutputInfo.pkScriptLen()
+		targetAmount += btcutil.Amount(recipient.Amount)
+	}
+	output := outputs[0]
 	changePKScript := changeAddress.PubkeyScript()
 
-	targetFee := btcutil.Amount(0)
-	for {
-		selectedOutputsSum, selectedOutPoints, err := coinSelection(
-			targetAmount+targetFee,
-			spendableOutputs,
-		)
-		if err != nil {
-			return nil, err
+	var selectedOutputsSum, finalFee btcutil.Amount
+	var selectedOutPoints []wire.OutPoint
+	if coinSelection == CoinSelectionBranchAndBound {
+		selectedOutputsSum, selectedOutPoints = changelessSelection(
+			spendableOutputs, targetAmount, outputPkScriptSizes, changeAddress, feePerKb, log)
+		if selectedOutPoints == nil {
+			log.Info("no changeless coin selection found, selecting largest coins first")
+			coinSelection = CoinSelectionLargestFirst
 		}
+	}
+	changeAmount := btcutil.Amount(0)
+	if selectedOutPoints != nil {
+		// The excess is smaller than the cost of a change output and is added to the fee.
+		finalFee = selectedOutputsSum - targetAmount
+	} else {
+		targetFee := btcutil.Amount(0)
+		for {
+			var err error
+			selectedOutputsSum, selectedOutPoints, err = selectCoins(
+				coinSelection,
+				targetAmount+targetFee,
+				spendableOutputs,
+			)
+			if err != nil {
+				return nil, err
+			}
 
-		txSize := estimateTxSize(
-			toInputConfigurations(spendableOutputs, selectedOutPoints),
-			outputInfo.pkScriptLen(),
-			len(changePKScript))
-		maxRequiredFee := feeForSerializeSize(feePerKb, txSize, log)
-		if selectedOutputsSum-targetAmount < maxRequiredFee {
-			targetFee = maxRequiredFee
-			continue
+			txSize := estimateTxSizeOutputs(
+				toInputConfigurations(spendableOutputs, selectedOutPoints),
+				outputPkScriptSizes,
+				len(changePKScript))
+			maxRequiredFee := feeForSerializeSize(feePerKb, txSize, log)
+			if selectedOutputsSum-targetAmount < maxRequiredFee {
+				targetFee = maxRequiredFee
+				continue
+			}
+			finalFee = maxRequiredFee
+			changeAmount = selectedOutputsSum - targetAmount - maxRequiredFee
+			if isDustAmount(changeAmount, len(changePKScript), changeAddress.Configuration, feePerKb) {
+				log.Info("change is dust")
+				finalFee = selectedOutputsSum - targetAmount
+				changeAmount = 0
+			}
+			break
 		}
+	}
 
-		inputs := make([]*wire.TxIn, len(selectedOutPoints))
-		previousOutputs := make(PreviousOutputs, len(selectedOutPoints))
-		for i, outPoint := range selectedOutPoints {
-			inputs[i] = wire.NewTxIn(&outPoint, nil, nil)
-			previousOutputs[outPoint] = spendableOutputs[outPoint]
-		}
-		unsignedTransaction := &wire.MsgTx{
-			Version:  wire.TxVersion,
-			TxIn:     inputs,
-			TxOut:    outputs,
-			LockTime: 0,
-		}
-		changeAmount := selectedOutputsSum - targetAmount - maxRequiredFee
-		changeIsDust := isDustAmount(
-			changeAmount, len(changePKScript), changeAddress.Configuration, feePerKb)
-		finalFee := maxRequiredFee
-		if changeIsDust {
-			log.Info("change is dust")
-			finalFee = selectedOutputsSum - targetAmount
-		}
-		if changeAmount != 0 && !changeIsDust {
-			unsignedTransaction.TxOut = append(unsignedTransaction.TxOut,
-				wire.NewTxOut(int64(changeAmount), changePKScript))
-		} else {
-			changeAddress = nil
+	inputs := make([]*wire.TxIn, len(selectedOutPoints))
+	previousOutputs := make(PreviousOutputs, len(selectedOutPoints))
+	for i, outPoint := range selectedOutPoints {
+		inputs[i] = wire.NewTxIn(&outPoint, nil, nil)
+		previousOutputs[outPoint] = spendableOutputs[outPoint]
+	}
+	unsignedTransaction := &wire.MsgTx{
+		Version:  wire.TxVersion,
+		TxIn:     inputs,
+		TxOut:    append([]*wire.TxOut{}, outputs...),
+		LockTime: 0,
+	}
+	if changeAmount != 0 {
+		unsignedTransaction.TxOut = append(unsignedTransaction.TxOut,
+			wire.NewTxOut(int64(changeAmount), changePKScript))
+	} else {
+		changeAddress = nil
+	}
+
+	secureRand := mrand.New(mrand.NewSource(secureSeed()))
+	shuffleTxInputsAndOutputs(unsignedTransaction, secureRand)
+
+	log.WithField("fee", finalFee).Debug("Preparing transaction")
+
+	outIndex := -1
+	for i, txOut := range unsignedTransaction.TxOut {
+		if txOut == output {
+			outIndex = i
+			break
 		}
+	}
+	if outIndex == -1 {
+		return nil, errp.New("could not identify output")
+	}
 
-		secureRand := mrand.New(mrand.NewSource(secureSeed()))
-		shuffleTxInputsAndOutputs(unsignedTransaction, secureRand)
+	setRBF(coin, unsignedTransaction)
+	return &TxProposal{
+		Coin:                 coin,
+		Amount:               targetAmount,
+		Fee:                  finalFee,
+		Transaction:          unsignedTransaction,
+		ChangeAddress:        changeAddress,
+		PreviousOutputs:      previousOutputs,
+		SilentPaymentAddress: recipients[0].OutputInfo.silentPaymentAddress,
+		OutIndex:             outIndex,
+		CoinSelection:        coinSelection,
+	}, nil
+}
+
+// ConsolidationSavings estimates the fee saved in a future transaction paying futureFeePerKb by
+// spending a single output of the consolidated configuration instead of all of the given outputs.
+func ConsolidationSavings(
+	outputs map[wire.OutPoint]UTXO,
+	consolidated *signing.Configuration,
+	futureFeePerKb btcutil.Amount,
+	log *logrus.Entry,
+) btcutil.Amount {
+	outPoints := make([]wire.OutPoint, 0, len(outputs))
+	for outPoint := range outputs {
+		outPoints = append(outPoints, outPoint)
+	}
+	sizeBefore := estimateTxSizeOutputs(toInputConfigurations(outputs, outPoints), nil, 0)
+	sizeAfter := estimateTxSizeOutputs([]*signing.Configuration{consolidated}, nil, 0)
+	return feeForSerializeSize(futureFeePerKb, sizeBefore, log) -
+		feeForSerializeSize(futureFeePerKb, sizeAfter, log)
+}
 
-		log.WithField("fee", finalFee).Debug("Preparing transaction")
+// IsDust returns true if an output paying the amount to the address would be dust, i.e. costing
+// more to spend than a third of its value at the given fee rate. Such outputs are not relayed.
+func IsDust(amount btcutil.Amount, address *addresses.AccountAddress, feePerKb btcutil.Amount) bool {
+	return isDustAmount(amount, len(address.PubkeyScript()), address.Configuration, feePerKb)
+}
 
-		outIndex := -1
-		for i, txOut := range unsignedTransaction.TxOut {
-			if txOut == output {
-				outIndex = i
-				break
-			}
+// NewTxBumpFee creates a replacement (BIP-125) of an unconfirmed transaction with a higher fee. The
+// replacement spends the same inputs to the same recipients, and the additional fee is deducted
+// from the change output. If the remaining change is dust, it is added to the fee. The inputs and
+// outputs are shuffled again, like in a new transaction. The amount of the returned proposal is the
+// sum of all recipient amounts, and OutIndex is the index of the output of the recipient appearing
+// first in the replaced transaction.
+//
+// previousOutputs must contain all outputs spent by the transaction. minRelayFeePerKb is the
+// incremental fee rate the replacement has to pay on top of the replaced fee, see BIP-125 rule 4.
+func NewTxBumpFee(
+	coin coinpkg.Coin,
+	transaction *wire.MsgTx,
+	previousOutputs PreviousOutputs,
+	changeAddress *addresses.AccountAddress,
+	feePerKb btcutil.Amount,
+	minRelayFeePerKb btcutil.Amount,
+	log *logrus.Entry,
+) (*TxProposal, error) {
+	if changeAddress == nil {
+		return nil, errp.New("only transactions with a change output can be bumped")
+	}
+	changePKScript := changeAddress.PubkeyScript()
+	var outputs []*wire.TxOut
+	var changeOutput *wire.TxOut
+	for _, txOut := range transaction.TxOut {
+		if changeOutput == nil && bytes.Equal(txOut.PkScript, changePKScript) {
+			changeOutput = txOut
+		} else {
+			outputs = append(outputs, wire.NewTxOut(txOut.Value, txOut.PkScript))
 		}
-		if outIndex == -1 {
-			return nil, errp.New("could not identify output")
+	}
+	if len(outputs) == 0 || changeOutput == nil {
+		return nil, errp.New("could not identify the change output")
+	}
+	output := outputs[0]
+
+	inputsSum := btcutil.Amount(0)
+	inputs := make([]*wire.TxIn, len(transaction.TxIn))
+	outPoints := make([]wire.OutPoint, len(transaction.TxIn))
+	for i, txIn := range transaction.TxIn {
+		previousOutput, ok := previousOutputs[txIn.PreviousOutPoint]
+		if !ok {
+			return nil, errp.New("There needs to be exactly one output being spent per input!")
 		}
+		inputsSum += btcutil.Amount(previousOutput.TxOut.Value)
+		inputs[i] = wire.NewTxIn(&txIn.PreviousOutPoint, nil, nil)
+		outPoints[i] = txIn.PreviousOutPoint
+	}
+	amount := btcutil.Amount(0)
+	outputPkScriptSizes := make([]int, len(outputs))
+	for i, txOut := range outputs {
+		amount += btcutil.Amount(txOut.Value)
+		outputPkScriptSizes[i] = len(txOut.PkScript)
+	}
+	oldFee := inputsSum - amount - btcutil.Amount(changeOutput.Value)
+
+	txSize := estimateTxSizeOutputs(
+		toInputConfigurations(previousOutputs, outPoints),
+		outputPkScriptSizes,
+		len(changePKScript))
+	fee := feeForSerializeSize(feePerKb, txSize, log)
+	if minFee := oldFee + feeForSerializeSize(minRelayFeePerKb, txSize, log); fee < minFee {
+		return nil, errp.WithStack(errors.ErrFeeTooLow)
+	}
+	changeAmount := btcutil.Amount(changeOutput.Value) - (fee - oldFee)
+	if changeAmount < 0 {
+		return nil, errp.WithStack(errors.ErrInsufficientFunds)
+	}
+
+	unsignedTransaction := &wire.MsgTx{
+		Version:  transaction.Version,
+		TxIn:     inputs,
+		TxOut:    outputs,
+		LockTime: transaction.LockTime,
+	}
+	if isDustAmount(changeAmount, len(changePKScript), changeAddress.Configuration, feePerKb) {
+		log.Info("change is dust")
+		fee += changeAmount
+		changeAddress = nil
+	} else {
+		unsignedTransaction.TxOut = append(unsignedTransaction.TxOut,
+			wire.NewTxOut(int64(changeAmount), changePKScript))
+	}
 
-		setRBF(coin, unsignedTransaction)
-		return &TxProposal{
-			Coin:                 coin,
-			Amount:               targetAmount,
-			Fee:                  finalFee,
-			Transaction:          unsignedTransaction,
-			ChangeAddress:        changeAddress,
-			PreviousOutputs:      previousOutputs,
-			SilentPaymentAddress: outputInfo.silentPaymentAddress,
-			OutIndex:             outIndex,
-		}, nil
+	secureRand := mrand.New(mrand.NewSource(secureSeed()))
+	shuffleTxInputsAndOutputs(unsignedTransaction, secureRand)
+
+	log.WithField("fee", fee).WithField("replacedFee", oldFee).Debug("Preparing replacement transaction")
+
+	outIndex := -1
+	for i, txOut := range unsignedTransaction.TxOut {
+		if txOut == output {
+			outIndex = i
+			break
+		}
+	}
+	if outIndex == -1 {
+		return nil, errp.New("could not identify output")
 	}
+
+	setRBF(coin, unsignedTransaction)
+	return &TxProposal{
+		Coin:            coin,
+		Amount:          amount,
+		Fee:             fee,
+		Transaction:     unsignedTransaction,
+		ChangeAddress:   changeAddress,
+		PreviousOutputs: previousOutputs,
+		OutIndex:        outIndex,
+	}, nil
 }
 
 // shuffleTxInputsAndOutputs shuffles both the TxIn and TxOut slices of a wire.MsgTx.
@@ -58,3 +58,20 @@ func TestShuffleTxInputsAndOutputs(t *testing.T) {
 	require.Equal(t, expectedSortedIns, tx.TxIn, "The transaction inputs were not successfully shuffled.")
 	require.Equal(t, expectedSortedOuts, tx.TxOut, "The transaction outputs were not successfully shuffled.")
 }
+
+func TestNewCoinSelection(t *testing.T) {
+	coinSelection, err := NewCoinSelection("")
+	require.NoError(t, err)
+	require.Equal(t, CoinSelectionLargestFirst, coinSelection)
+
+	for _, valid := range []CoinSelection{
+		CoinSelectionLargestFirst, CoinSelectionBranchAndBound, CoinSelectionSingleAddress,
+	} {
+		coinSelection, err := NewCoinSelection(string(valid))
+		require.NoError(t, err)
+		require.Equal(t, valid, coinSelection)
+	}
+
+	_, err = NewCoinSelection("random")
+	require.Error(t, err)
+}
@@ -108,6 +108,7 @@ func (s *newTxSuite) newTx(
 		int64(amount),
 		feePerKb,
 		s.changeAddress,
+		maketx.CoinSelectionLargestFirst,
 		s.log,
 	)
 }
@@ -347,3 +348,225 @@ func (s *newTxSuite) TestNewTxCoinSelection() {
 	s.check(true, btcutil.Amount(100299738), feePerKb, s.buildUTXO(mBTC, 2*mBTC, 1000*mBTC+txSizeOneInput), s.change(0), noDust, s.selectCoins(0, 1, 2))
 
 }
+
+func (s *newTxSuite) TestNewTxBranchAndBound() {
+	feePerKb := btcutil.Amount(1000) // 1 sat / vbyte
+	// Size of a transaction with one input and no change output.
+	const txSizeNoChange = txSizeOneInput - 34
+	newTx := func(utxo map[wire.OutPoint]maketx.UTXO) *maketx.TxProposal {
+		txProposal, err := maketx.NewTx(
+			s.coin,
+			utxo,
+			maketx.NewOutputInfo(s.outputPkScript),
+			100000,
+			feePerKb,
+			s.changeAddress,
+			maketx.CoinSelectionBranchAndBound,
+			s.log,
+		)
+		s.Require().NoError(err)
+		return txProposal
+	}
+
+	// The second coin pays the amount and fee exactly, without change.
+	txProposal := newTx(s.buildUTXO(200000, 100000+txSizeNoChange, 50000))
+	s.Require().Equal(maketx.CoinSelectionBranchAndBound, txProposal.CoinSelection)
+	s.Require().Equal(btcutil.Amount(txSizeNoChange), txProposal.Fee)
+	s.Require().Nil(txProposal.ChangeAddress)
+	s.Require().Len(txProposal.Transaction.TxIn, 1)
+	s.Require().Equal(s.outpoint(1), txProposal.Transaction.TxIn[0].PreviousOutPoint)
+	s.Require().Equal([]*wire.TxOut{s.output(100000)}, txProposal.Transaction.TxOut)
+
+	// Without a changeless solution, the largest coins are spent first.
+	txProposal = newTx(s.buildUTXO(200000, 50000))
+	s.Require().Equal(maketx.CoinSelectionLargestFirst, txProposal.CoinSelection)
+	s.Require().Equal(btcutil.Amount(txSizeOneInput), txProposal.Fee)
+	s.Require().Equal(s.changeAddress, txProposal.ChangeAddress)
+	s.Require().Equal(s.outpoint(0), txProposal.Transaction.TxIn[0].PreviousOutPoint)
+}
+
+func (s *newTxSuite) TestNewTxSingleAddress() {
+	feePerKb := btcutil.Amount(1000) // 1 sat / vbyte
+	utxo := map[wire.OutPoint]maketx.UTXO{}
+	for i, coin := range []struct {
+		address *addresses.AccountAddress
+		value   int64
+	}{
+		{s.someAddresses[0], 60000},
+		{s.someAddresses[0], 60000},
+		{s.someAddresses[1], 200000},
+	} {
+		utxo[s.outpoint(i)] = maketx.UTXO{
+			TxOut:   wire.NewTxOut(coin.value, coin.address.PubkeyScript()),
+			Address: coin.address,
+		}
+	}
+	newTx := func(amount btcutil.Amount) (*maketx.TxProposal, error) {
+		return maketx.NewTx(
+			s.coin,
+			utxo,
+			maketx.NewOutputInfo(s.outputPkScript),
+			int64(amount),
+			feePerKb,
+			s.changeAddress,
+			maketx.CoinSelectionSingleAddress,
+			s.log,
+		)
+	}
+	spent := func(txProposal *maketx.TxProposal) []wire.OutPoint {
+		outPoints := []wire.OutPoint{}
+		for _, txIn := range txProposal.Transaction.TxIn {
+			outPoints = append(outPoints, txIn.PreviousOutPoint)
+		}
+		return outPoints
+	}
+
+	// All coins of the smallest sufficient address are spent.
+	txProposal, err := newTx(100000)
+	s.Require().NoError(err)
+	s.Require().Equal(maketx.CoinSelectionSingleAddress, txProposal.CoinSelection)
+	s.Require().ElementsMatch([]wire.OutPoint{s.outpoint(0), s.outpoint(1)}, spent(txProposal))
+	s.Require().Equal(btcutil.Amount(txSizeTwoInputs), txProposal.Fee)
+	s.Require().Contains(txProposal.Transaction.TxOut,
+		wire.NewTxOut(120000-100000-txSizeTwoInputs, s.changeAddress.PubkeyScript()))
+
+	txProposal, err = newTx(150000)
+	s.Require().NoError(err)
+	s.Require().Equal([]wire.OutPoint{s.outpoint(2)}, spent(txProposal))
+
+	// The coins of different addresses are never combined.
+	_, err = newTx(250000)
+	s.Require().Equal(errors.ErrInsufficientFunds, errp.Cause(err))
+}
+
+func (s *newTxSuite) TestNewTxBumpFee() {
+	const mBTC = 100000
+	amount := btcutil.Amount(100 * mBTC)
+	minRelayFeePerKb := btcutil.Amount(1000)
+	utxo := s.buildUTXO(1000 * mBTC)
+
+	txProposal, err := s.newTx(amount, btcutil.Amount(1000), utxo)
+	s.Require().NoError(err)
+	s.Require().Equal(btcutil.Amount(txSizeOneInput), txProposal.Fee)
+
+	bumpFee := func(feePerKb btcutil.Amount) (*maketx.TxProposal, error) {
+		return maketx.NewTxBumpFee(
+			s.coin,
+			txProposal.Transaction,
+			utxo,
+			s.changeAddress,
+			feePerKb,
+			minRelayFeePerKb,
+			s.log,
+		)
+	}
+
+	// The replacement needs to pay for its own relay on top of the replaced fee.
+	_, err = bumpFee(btcutil.Amount(1000))
+	s.Require().Equal(errors.ErrFeeTooLow, errp.Cause(err))
+	_, err = bumpFee(btcutil.Amount(1999))
+	s.Require().Equal(errors.ErrFeeTooLow, errp.Cause(err))
+
+	bumped, err := bumpFee(btcutil.Amount(5000))
+	s.Require().NoError(err)
+	s.Require().Equal(btcutil.Amount(5*txSizeOneInput), bumped.Fee)
+	s.Require().Equal(amount, bumped.Amount)
+	s.Require().Equal(s.changeAddress, bumped.ChangeAddress)
+	s.Require().Len(bumped.Transaction.TxIn, 1)
+	s.Require().Equal(txProposal.Transaction.TxIn[0].PreviousOutPoint, bumped.Transaction.TxIn[0].PreviousOutPoint)
+	s.Require().Equal(txProposal.Transaction.TxIn[0].Sequence, bumped.Transaction.TxIn[0].Sequence)
+	s.Require().Len(bumped.Transaction.TxOut, 2)
+	s.Require().Equal(s.output(amount), bumped.Transaction.TxOut[bumped.OutIndex])
+	s.Require().Contains(bumped.Transaction.TxOut,
+		wire.NewTxOut(900*mBTC-5*txSizeOneInput, s.changeAddress.PubkeyScript()))
+
+	// The change does not cover the higher fee.
+	_, err = bumpFee(btcutil.Amount(10000 * mBTC))
+	s.Require().Equal(errors.ErrInsufficientFunds, errp.Cause(err))
+
+	// Transactions without change cannot be bumped.
+	_, err = maketx.NewTxBumpFee(
+		s.coin, txProposal.Transaction, utxo, nil, btcutil.Amount(5000), minRelayFeePerKb, s.log)
+	s.Require().Error(err)
+
+	// Transactions with several recipients keep all recipient outputs.
+	otherPkScript := s.someAddresses[1].PubkeyScript()
+	txProposal, err = maketx.NewTxMultipleRecipients(
+		s.coin,
+		utxo,
+		[]maketx.Recipient{
+			{OutputInfo: maketx.NewOutputInfo(s.outputPkScript), Amount: 100 * mBTC},
+			{OutputInfo: maketx.NewOutputInfo(otherPkScript), Amount: 200 * mBTC},
+		},
+		btcutil.Amount(1000),
+		s.changeAddress,
+		maketx.CoinSelectionLargestFirst,
+		s.log,
+	)
+	s.Require().NoError(err)
+	// One more output than a transaction with one recipient and change.
+	const txSize = txSizeOneInput + 34
+	bumped, err = bumpFee(btcutil.Amount(5000))
+	s.Require().NoError(err)
+	s.Require().Equal(btcutil.Amount(5*txSize), bumped.Fee)
+	s.Require().Equal(btcutil.Amount(300*mBTC), bumped.Amount)
+	s.Require().Len(bumped.Transaction.TxOut, 3)
+	s.Require().NotEqual(s.changeAddress.PubkeyScript(), bumped.Transaction.TxOut[bumped.OutIndex].PkScript)
+	s.Require().Contains(bumped.Transaction.TxOut, s.output(100*mBTC))
+	s.Require().Contains(bumped.Transaction.TxOut, wire.NewTxOut(200*mBTC, otherPkScript))
+	s.Require().Contains(bumped.Transaction.TxOut,
+		wire.NewTxOut(700*mBTC-5*txSize, s.changeAddress.PubkeyScript()))
+}
+
+func (s *newTxSuite) TestNewTxMultipleRecipients() {
+	feePerKb := btcutil.Amount(1000) // 1 sat / vbyte
+	otherPkScript := s.someAddresses[1].PubkeyScript()
+	txProposal, err := maketx.NewTxMultipleRecipients(
+		s.coin,
+		s.buildUTXO(100000),
+		[]maketx.Recipient{
+			{OutputInfo: maketx.NewOutputInfo(s.outputPkScript), Amount: 1000},
+			{OutputInfo: maketx.NewOutputInfo(otherPkScript), Amount: 2000},
+		},
+		feePerKb,
+		s.changeAddress,
+		maketx.CoinSelectionLargestFirst,
+		s.log,
+	)
+	s.Require().NoError(err)
+	// One more output than a transaction with one recipient and change.
+	const txSize = txSizeOneInput + 34
+	s.Require().Equal(btcutil.Amount(3000), txProposal.Amount)
+	s.Require().Equal(btcutil.Amount(txSize), txProposal.Fee)
+	s.Require().Len(txProposal.Transaction.TxOut, 3)
+	s.Require().Equal(s.output(1000), txProposal.Transaction.TxOut[txProposal.OutIndex])
+	s.Require().Contains(txProposal.Transaction.TxOut, wire.NewTxOut(2000, otherPkScript))
+	s.Require().Contains(txProposal.Transaction.TxOut,
+		wire.NewTxOut(100000-3000-txSize, s.changeAddress.PubkeyScript()))
+
+	_, err = maketx.NewTxMultipleRecipients(
+		s.coin,
+		s.buildUTXO(3000),
+		[]maketx.Recipient{
+			{OutputInfo: maketx.NewOutputInfo(s.outputPkScript), Amount: 1000},
+			{OutputInfo: maketx.NewOutputInfo(otherPkScript), Amount: 2000},
+		},
+		feePerKb,
+		s.changeAddress,
+		maketx.CoinSelectionLargestFirst,
+		s.log,
+	)
+	s.Require().Equal(errors.ErrInsufficientFunds, errp.Cause(err))
+}
+
+func (s *newTxSuite) TestConsolidationSavings() {
+	feePerKb := btcutil.Amount(1000) // 1 sat / vbyte
+	// Size of one input, see the reference tx sizes above.
+	const inputSize = txSizeTwoInputs - txSizeOneInput
+	s.Require().Equal(btcutil.Amount(0),
+		maketx.ConsolidationSavings(s.buildUTXO(1000), s.inputConfiguration, feePerKb, s.log))
+	s.Require().Equal(btcutil.Amount(2*inputSize),
+		maketx.ConsolidationSavings(s.buildUTXO(1000, 2000, 3000), s.inputConfiguration, feePerKb, s.log))
+	s.Require().Equal(btcutil.Amount(20*inputSize),
+		maketx.ConsolidationSavings(s.buildUTXO(1000, 2000, 3000), s.inputConfiguration, 10*feePerKb, s.log))
+}
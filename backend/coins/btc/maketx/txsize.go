@@ -65,6 +65,16 @@ func sigScriptWitnessSize(configuration *signing.Configuration) (int, int) {
 	case signing.ScriptTypeP2TR:
 		// Taproot key spend: <64 byte sig>
 		return 0, wire.VarIntSerializeSize(1) + wire.VarIntSerializeSize(64) + 64
+	case signing.ScriptTypeP2WSH:
+		// <empty> <sig_1> ... <sig_m> <witnessScript>, with the witness script being
+		// OP_m <pubkey_1> ... <pubkey_n> OP_n OP_CHECKMULTISIG.
+		threshold := int(configuration.BitcoinMultisig.Threshold)
+		numCosigners := len(configuration.BitcoinMultisig.Cosigners)
+		witnessScriptSize := 1 + numCosigners*(1+pubkeySize) + 1 + 1
+		return 0, wire.VarIntSerializeSize(uint64(threshold+2)) +
+			wire.VarIntSerializeSize(0) +
+			threshold*(wire.VarIntSerializeSize(signatureSize)+signatureSize) +
+			wire.VarIntSerializeSize(uint64(witnessScriptSize)) + witnessScriptSize
 	default:
 		panic("unknown address type")
 	}
@@ -85,9 +95,18 @@ func estimateTxSize(
 	inputConfigurations []*signing.Configuration,
 	outputPkScriptSize int,
 	changePkScriptSize int) int {
-	outputCount := 2 // 1 output + 1 change output
-	if changePkScriptSize == 0 {
-		outputCount = 1
+	return estimateTxSizeOutputs(inputConfigurations, []int{outputPkScriptSize}, changePkScriptSize)
+}
+
+// estimateTxSizeOutputs is like estimateTxSize, but for a transaction with one output per entry of
+// outputPkScriptSizes (apart from change).
+func estimateTxSizeOutputs(
+	inputConfigurations []*signing.Configuration,
+	outputPkScriptSizes []int,
+	changePkScriptSize int) int {
+	outputCount := len(outputPkScriptSizes)
+	if changePkScriptSize != 0 {
+		outputCount++
 	}
 
 	const (
@@ -97,10 +116,13 @@ func estimateTxSize(
 		nonWitness = 4
 	)
 
+	outputsSize := outputSize(changePkScriptSize)
+	for _, outputPkScriptSize := range outputPkScriptSizes {
+		outputsSize += outputSize(outputPkScriptSize)
+	}
 	txWeight := nonWitness * (versionSize + lockTimeSize + wire.VarIntSerializeSize(uint64(len(inputConfigurations))) +
 		wire.VarIntSerializeSize(uint64(outputCount)) +
-		outputSize(outputPkScriptSize) +
-		outputSize(changePkScriptSize))
+		outputsSize)
 
 	isSegwitTx := false
 	for _, inputConfiguration := range inputConfigurations {
@@ -0,0 +1,167 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/payjoin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// finalizePSBTInput sets the final scripts of a PSBT input from a signed transaction input.
+func finalizePSBTInput(input *psbt.PInput, txIn *wire.TxIn) error {
+	if len(txIn.SignatureScript) > 0 {
+		input.FinalScriptSig = txIn.SignatureScript
+	}
+	if len(txIn.Witness) > 0 {
+		var witness bytes.Buffer
+		if err := psbt.WriteTxWitness(&witness, txIn.Witness); err != nil {
+			return errp.WithStack(err)
+		}
+		input.FinalScriptWitness = witness.Bytes()
+	}
+	return nil
+}
+
+// payjoin performs the Payjoin (BIP-78) round-trip for a signed tx proposal. The original
+// transaction is sent to the receiver, and the receiver's proposal is checked and signed with the
+// keystore. The returned transaction is fully signed and can be broadcasted instead of the
+// original.
+func (account *Account) payjoin(ctx context.Context, txProposal *maketx.TxProposal) (*wire.MsgTx, error) {
+	signedTx := txProposal.Transaction
+	unsignedProposal := *txProposal
+	unsignedProposal.Transaction = signedTx.Copy()
+	for _, txIn := range unsignedProposal.Transaction.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+	original, err := account.newPSBT(ctx, &unsignedProposal)
+	if err != nil {
+		return nil, err
+	}
+	// The original needs to be finalized. The key origins are not included, as the receiver does
+	// not need to know them.
+	for index, txIn := range signedTx.TxIn {
+		input := psbt.PInput{
+			NonWitnessUtxo: original.Inputs[index].NonWitnessUtxo,
+			WitnessUtxo:    original.Inputs[index].WitnessUtxo,
+		}
+		if err := finalizePSBTInput(&input, txIn); err != nil {
+			return nil, err
+		}
+		original.Inputs[index] = input
+	}
+	for index := range original.Outputs {
+		original.Outputs[index] = psbt.POutput{}
+	}
+
+	// The fee rate of the original in sat/vbyte. The receiver must not lower it.
+	feeRate := float64(txProposal.Fee) / float64(util.VirtualSize(signedTx))
+	params := payjoin.Params{
+		AdditionalFeeOutputIndex: -1,
+		MinFeeRate:               btcutil.Amount(feeRate * 1000),
+	}
+	var changePkScript []byte
+	if txProposal.ChangeAddress != nil {
+		changePkScript = txProposal.ChangeAddress.PubkeyScript()
+		for index, txOut := range signedTx.TxOut {
+			if !bytes.Equal(txOut.PkScript, changePkScript) {
+				continue
+			}
+			// We pay for one additional input of the receiver at the fee rate of the original.
+			params.AdditionalFeeOutputIndex = index
+			params.MaxAdditionalFeeContribution = btcutil.Amount(
+				feeRate * float64(payjoin.InputVirtualSize(signedTx.TxIn[0])))
+		}
+	}
+
+	account.log.Info("Requesting payjoin proposal")
+	proposal, err := payjoin.Request(ctx, account.httpClient, txProposal.PayjoinURL, original, params)
+	if err != nil {
+		return nil, err
+	}
+	receiverOutputs, err := payjoin.CheckProposal(original, proposal, params, func(pkScript []byte) bool {
+		return account.getAddress(blockchain.NewScriptHashHex(pkScript)) != nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payjoinTx := proposal.UnsignedTx.Copy()
+	previousOutputs := maketx.PreviousOutputs{}
+	for outPoint, utxo := range txProposal.PreviousOutputs {
+		previousOutputs[outPoint] = utxo
+	}
+	for outPoint, txOut := range receiverOutputs {
+		// The receiver's inputs have no address of our account and are not signed by the keystore.
+		previousOutputs[outPoint] = maketx.UTXO{TxOut: txOut}
+	}
+	payjoinProposal := &maketx.TxProposal{
+		Coin:            txProposal.Coin,
+		Amount:          txProposal.Amount,
+		Fee:             txProposal.Fee,
+		Transaction:     payjoinTx,
+		ChangeAddress:   txProposal.ChangeAddress,
+		PreviousOutputs: previousOutputs,
+	}
+	recipientPkScript := signedTx.TxOut[txProposal.OutIndex].PkScript
+	for index, txOut := range payjoinTx.TxOut {
+		if bytes.Equal(txOut.PkScript, recipientPkScript) {
+			payjoinProposal.OutIndex = index
+		}
+		if params.AdditionalFeeOutputIndex >= 0 && bytes.Equal(txOut.PkScript, changePkScript) {
+			payjoinProposal.Fee += btcutil.Amount(
+				signedTx.TxOut[params.AdditionalFeeOutputIndex].Value - txOut.Value)
+		}
+	}
+
+	account.log.Info("Signing payjoin proposal")
+	proposedTransaction, err := account.keystoreSign(
+		payjoinProposal, account.transactionGetter(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := proposedTransaction.Finalize(); err != nil {
+		return nil, err
+	}
+	// Our inputs are signed now, the receiver's inputs were already finalized by the receiver.
+	for index, txIn := range payjoinTx.TxIn {
+		if _, ok := receiverOutputs[txIn.PreviousOutPoint]; ok {
+			continue
+		}
+		proposal.Inputs[index] = psbt.PInput{}
+		if err := finalizePSBTInput(&proposal.Inputs[index], txIn); err != nil {
+			return nil, err
+		}
+	}
+	transaction, err := psbt.Extract(proposal)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if err := TxValidityCheck(transaction, previousOutputs,
+		txscript.NewTxSigHashes(transaction, previousOutputs)); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
@@ -0,0 +1,350 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package payjoin implements the sender side of Payjoin (BIP-78). The sender sends a signed
+// original transaction as a PSBT to the receiver, who adds their own inputs and returns a proposal.
+// The sender checks the proposal, signs it again and broadcasts it. If anything goes wrong, the
+// sender broadcasts the original transaction instead.
+package payjoin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// requestTimeout is how long we wait for the receiver to respond with a proposal.
+	requestTimeout = 60 * time.Second
+	// maxResponseSize limits the size of the proposal returned by the receiver.
+	maxResponseSize = 1 << 20
+)
+
+// Params are the optional parameters sent to the receiver, see BIP-78.
+type Params struct {
+	// AdditionalFeeOutputIndex is the index of the output from which the receiver can deduct the
+	// additional fee incurred by their inputs, usually our change output. -1 if there is none.
+	AdditionalFeeOutputIndex int
+	// MaxAdditionalFeeContribution is the maximum amount that can be deducted from the output at
+	// AdditionalFeeOutputIndex.
+	MaxAdditionalFeeContribution btcutil.Amount
+	// MinFeeRate is the minimum fee rate of the proposal per 1000 vbytes. 0 if there is none.
+	MinFeeRate btcutil.Amount
+}
+
+// ValidateEndpoint checks that the endpoint given in the `pj=` parameter of a BIP-21 URI can be
+// used. BIP-78 requires https, or http for onion services.
+func ValidateEndpoint(endpoint string) (*url.URL, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	switch {
+	case parsed.Scheme == "https":
+	case parsed.Scheme == "http" && strings.HasSuffix(parsed.Hostname(), ".onion"):
+	default:
+		return nil, errp.Newf("payjoin endpoint must use https: %s", endpoint)
+	}
+	return parsed, nil
+}
+
+// requestURL adds the parameters to the endpoint. Output substitution is always disabled, so the
+// receiver can't replace the output we pay to.
+func requestURL(endpoint string, params Params) (string, error) {
+	parsed, err := ValidateEndpoint(endpoint)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("v", "1")
+	query.Set("disableoutputsubstitution", "true")
+	if params.AdditionalFeeOutputIndex >= 0 {
+		query.Set("additionalfeeoutputindex", strconv.Itoa(params.AdditionalFeeOutputIndex))
+		query.Set("maxadditionalfeecontribution",
+			strconv.FormatInt(int64(params.MaxAdditionalFeeContribution), 10))
+	}
+	if params.MinFeeRate > 0 {
+		// BIP-78 specifies the fee rate in sat/vbyte.
+		query.Set("minfeerate", strconv.FormatFloat(float64(params.MinFeeRate)/1000, 'f', -1, 64))
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// Request sends the finalized original PSBT to the receiver and returns the receiver's proposal.
+// The proposal must be checked with CheckProposal() before it is signed.
+func Request(
+	ctx context.Context, httpClient *http.Client, endpoint string, original *psbt.Packet, params Params,
+) (*psbt.Packet, error) {
+	requestURL, err := requestURL(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := original.B64Encode()
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, requestURL, strings.NewReader(encoded))
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+	responseBody, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if len(responseBody) > maxResponseSize {
+		return nil, errp.New("payjoin response too long")
+	}
+	if res.StatusCode != http.StatusOK {
+		var receiverError struct {
+			ErrorCode string `json:"errorCode"`
+			Message   string `json:"message"`
+		}
+		if err := json.Unmarshal(responseBody, &receiverError); err == nil && receiverError.ErrorCode != "" {
+			return nil, errp.Newf("payjoin receiver error %s: %s",
+				receiverError.ErrorCode, receiverError.Message)
+		}
+		return nil, errp.Newf("payjoin receiver responded with status code %d", res.StatusCode)
+	}
+	proposal, err := psbt.NewFromRawBytes(bytes.NewReader(bytes.TrimSpace(responseBody)), true)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return proposal, nil
+}
+
+// inputUTXO returns the output spent by the PSBT input at the given index.
+func inputUTXO(packet *psbt.Packet, index int) (*wire.TxOut, error) {
+	input := packet.Inputs[index]
+	if input.WitnessUtxo != nil {
+		return input.WitnessUtxo, nil
+	}
+	outPoint := packet.UnsignedTx.TxIn[index].PreviousOutPoint
+	if input.NonWitnessUtxo != nil && input.NonWitnessUtxo.TxHash() == outPoint.Hash &&
+		int(outPoint.Index) < len(input.NonWitnessUtxo.TxOut) {
+		return input.NonWitnessUtxo.TxOut[outPoint.Index], nil
+	}
+	return nil, errp.Newf("missing previous output of input %d", index)
+}
+
+// fee returns the fee of the PSBT. utxo returns the output spent by the input at the given index.
+func fee(packet *psbt.Packet, utxo func(index int) (*wire.TxOut, error)) (btcutil.Amount, error) {
+	var result int64
+	for index := range packet.UnsignedTx.TxIn {
+		txOut, err := utxo(index)
+		if err != nil {
+			return 0, err
+		}
+		result += txOut.Value
+	}
+	for _, txOut := range packet.UnsignedTx.TxOut {
+		result -= txOut.Value
+	}
+	return btcutil.Amount(result), nil
+}
+
+// virtualSize returns the virtual size of the PSBT once all inputs are finalized. finalInput
+// returns the finalized input at the given index.
+func virtualSize(packet *psbt.Packet, finalInput func(index int) psbt.PInput) (int, error) {
+	finalized := &psbt.Packet{
+		UnsignedTx: packet.UnsignedTx,
+		Inputs:     make([]psbt.PInput, len(packet.Inputs)),
+		Outputs:    packet.Outputs,
+	}
+	for index := range finalized.Inputs {
+		finalized.Inputs[index] = finalInput(index)
+	}
+	transaction, err := psbt.Extract(finalized)
+	if err != nil {
+		return 0, errp.WithStack(err)
+	}
+	return util.VirtualSize(transaction), nil
+}
+
+// CheckProposal performs the checks of the sender on the receiver's proposal as described in
+// BIP-78. isOurs must return true for output scripts belonging to the sender's account. On success,
+// the outputs spent by the receiver's inputs are returned, which are needed to sign the proposal.
+func CheckProposal(
+	original, proposal *psbt.Packet, params Params, isOurs func(pkScript []byte) bool,
+) (map[wire.OutPoint]*wire.TxOut, error) {
+	originalTx, proposalTx := original.UnsignedTx, proposal.UnsignedTx
+	if len(proposal.Inputs) != len(proposalTx.TxIn) || len(proposal.Outputs) != len(proposalTx.TxOut) {
+		return nil, errp.New("malformed payjoin proposal")
+	}
+	if proposalTx.Version != originalTx.Version || proposalTx.LockTime != originalTx.LockTime {
+		return nil, errp.New("payjoin proposal changed the transaction version or locktime")
+	}
+
+	originalInputs := map[wire.OutPoint]int{}
+	for index, txIn := range originalTx.TxIn {
+		originalInputs[txIn.PreviousOutPoint] = index
+	}
+	receiverOutputs := map[wire.OutPoint]*wire.TxOut{}
+	// utxo returns the output spent by the proposal input at the given index. The receiver might
+	// strip the info of our inputs, so we take it from the original.
+	utxo := func(index int) (*wire.TxOut, error) {
+		outPoint := proposalTx.TxIn[index].PreviousOutPoint
+		if originalIndex, ok := originalInputs[outPoint]; ok {
+			return inputUTXO(original, originalIndex)
+		}
+		return receiverOutputs[outPoint], nil
+	}
+	// If all our inputs are of the same script type, the receiver's inputs must be of that type too,
+	// so that the payjoin can't be told apart by the input types.
+	var scriptClass txscript.ScriptClass
+	for index := range originalTx.TxIn {
+		txOut, err := inputUTXO(original, index)
+		if err != nil {
+			return nil, err
+		}
+		class := txscript.GetScriptClass(txOut.PkScript)
+		if index > 0 && class != scriptClass {
+			scriptClass = txscript.NonStandardTy
+			break
+		}
+		scriptClass = class
+	}
+	sequence := originalTx.TxIn[0].Sequence
+	ourInputsCount := 0
+	for index, txIn := range proposalTx.TxIn {
+		if originalIndex, ok := originalInputs[txIn.PreviousOutPoint]; ok {
+			if txIn.Sequence != originalTx.TxIn[originalIndex].Sequence {
+				return nil, errp.New("payjoin proposal changed the sequence of our input")
+			}
+			ourInputsCount++
+			continue
+		}
+		input := proposal.Inputs[index]
+		if len(input.FinalScriptSig) == 0 && len(input.FinalScriptWitness) == 0 {
+			return nil, errp.New("payjoin proposal contains an input which is not finalized")
+		}
+		if txIn.Sequence != sequence {
+			return nil, errp.New("payjoin proposal contains an input with a different sequence")
+		}
+		txOut, err := inputUTXO(proposal, index)
+		if err != nil {
+			return nil, err
+		}
+		if isOurs(txOut.PkScript) {
+			return nil, errp.New("payjoin proposal contains an input of our account")
+		}
+		if scriptClass != txscript.NonStandardTy && txscript.GetScriptClass(txOut.PkScript) != scriptClass {
+			return nil, errp.New("payjoin proposal contains an input of a different script type")
+		}
+		receiverOutputs[txIn.PreviousOutPoint] = txOut
+	}
+	if ourInputsCount != len(originalTx.TxIn) {
+		return nil, errp.New("payjoin proposal is missing some of our inputs")
+	}
+
+	// All our outputs must be present. Except for the output designated to pay the additional fee,
+	// the amounts can't decrease.
+	used := make([]bool, len(proposalTx.TxOut))
+	var contribution btcutil.Amount
+	for originalIndex, originalOut := range originalTx.TxOut {
+		found := false
+		for index, txOut := range proposalTx.TxOut {
+			if used[index] || !bytes.Equal(txOut.PkScript, originalOut.PkScript) {
+				continue
+			}
+			used[index], found = true, true
+			if originalIndex == params.AdditionalFeeOutputIndex {
+				contribution = btcutil.Amount(originalOut.Value - txOut.Value)
+				if contribution < 0 {
+					contribution = 0
+				}
+			} else if txOut.Value < originalOut.Value {
+				return nil, errp.New("payjoin proposal decreased the amount of an output")
+			}
+			break
+		}
+		if !found {
+			return nil, errp.New("payjoin proposal is missing one of our outputs")
+		}
+	}
+	if contribution > params.MaxAdditionalFeeContribution {
+		return nil, errp.New("payjoin proposal takes too much additional fee from us")
+	}
+	originalFee, err := fee(original, func(index int) (*wire.TxOut, error) {
+		return inputUTXO(original, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+	proposalFee, err := fee(proposal, utxo)
+	if err != nil {
+		return nil, err
+	}
+	if contribution > 0 {
+		if contribution > proposalFee-originalFee {
+			return nil, errp.New("payjoin proposal uses our fee contribution for something else than fees")
+		}
+		if len(receiverOutputs) == 0 {
+			return nil, errp.New("payjoin proposal takes additional fee without adding inputs")
+		}
+	}
+
+	// The fee rate can't be lower than the fee rate of the original. Our inputs are not signed yet in
+	// the proposal, so their size is taken from the original, which is signed.
+	originalSize, err := virtualSize(original, func(index int) psbt.PInput {
+		return original.Inputs[index]
+	})
+	if err != nil {
+		return nil, err
+	}
+	proposalSize, err := virtualSize(proposal, func(index int) psbt.PInput {
+		if originalIndex, ok := originalInputs[proposalTx.TxIn[index].PreviousOutPoint]; ok {
+			return original.Inputs[originalIndex]
+		}
+		return proposal.Inputs[index]
+	})
+	if err != nil {
+		return nil, err
+	}
+	if int64(proposalFee)*int64(originalSize) < int64(originalFee)*int64(proposalSize) {
+		return nil, errp.New("payjoin proposal lowers the fee rate")
+	}
+	if int64(proposalFee)*1000 < int64(params.MinFeeRate)*int64(proposalSize) {
+		return nil, errp.New("payjoin proposal has a fee rate below the minimum")
+	}
+	return receiverOutputs, nil
+}
+
+// InputVirtualSize returns the virtual size of a signed transaction input in vbytes.
+func InputVirtualSize(txIn *wire.TxIn) int {
+	weight := txIn.SerializeSize()*4 + txIn.Witness.SerializeSize()
+	return (weight + 3) / 4
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package payjoin
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ourScript       = []byte{0x00, 0x14, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	changeScript    = []byte{0x00, 0x14, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}
+	recipientScript = []byte{0x00, 0x14, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3}
+	receiverScript  = []byte{0x00, 0x14, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4}
+	taprootScript   = append([]byte{0x51, 0x20}, bytes.Repeat([]byte{5}, 32)...)
+	ourOutPoint     = wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	receiverOutPt   = wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	params          = Params{
+		AdditionalFeeOutputIndex:     1,
+		MaxAdditionalFeeContribution: 700,
+		MinFeeRate:                   8000,
+	}
+)
+
+func isOurs(pkScript []byte) bool {
+	return bytes.Equal(pkScript, ourScript) || bytes.Equal(pkScript, changeScript)
+}
+
+// newOriginal returns an original PSBT spending 100000 sat to 60000 sat to the recipient, 39000 sat
+// change and a fee of 1000 sat.
+func newOriginal(t *testing.T) *psbt.Packet {
+	t.Helper()
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = 800000
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: ourOutPoint, Sequence: 0xfffffffd})
+	tx.AddTxOut(wire.NewTxOut(60000, recipientScript))
+	tx.AddTxOut(wire.NewTxOut(39000, changeScript))
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	require.NoError(t, err)
+	packet.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, ourScript)
+	packet.Inputs[0].FinalScriptWitness = []byte{0x01, 0x01, 0x01}
+	return packet
+}
+
+// newProposal returns a proposal adding a receiver input of 50000 sat to the payment output. The
+// receiver takes contribution sat from our change to pay for the additional input.
+func newProposal(t *testing.T, contribution int64) *psbt.Packet {
+	t.Helper()
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = 800000
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: receiverOutPt, Sequence: 0xfffffffd})
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: ourOutPoint, Sequence: 0xfffffffd})
+	tx.AddTxOut(wire.NewTxOut(39000-contribution, changeScript))
+	tx.AddTxOut(wire.NewTxOut(110000, recipientScript))
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	require.NoError(t, err)
+	packet.Inputs[0].WitnessUtxo = wire.NewTxOut(50000, receiverScript)
+	packet.Inputs[0].FinalScriptWitness = []byte{0x01, 0x01, 0x02}
+	return packet
+}
+
+func TestRequestURL(t *testing.T) {
+	result, err := requestURL("https://example.com/pj?id=1", params)
+	require.NoError(t, err)
+	parsed, err := url.Parse(result)
+	require.NoError(t, err)
+	require.Equal(t, url.Values{
+		"id":                           {"1"},
+		"v":                            {"1"},
+		"disableoutputsubstitution":    {"true"},
+		"additionalfeeoutputindex":     {"1"},
+		"maxadditionalfeecontribution": {"700"},
+		"minfeerate":                   {"8"},
+	}, parsed.Query())
+
+	result, err = requestURL("http://example.onion/pj", Params{AdditionalFeeOutputIndex: -1})
+	require.NoError(t, err)
+	require.Equal(t, "http://example.onion/pj?disableoutputsubstitution=true&v=1", result)
+
+	_, err = requestURL("http://example.com/pj", params)
+	require.Error(t, err)
+}
+
+func TestRequest(t *testing.T) {
+	proposal := newProposal(t, 500)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		original, err := newOriginal(t).B64Encode()
+		require.NoError(t, err)
+		if r.URL.Query().Get("v") != "1" || string(body) != original {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errorCode": "original-psbt-rejected", "message": "rejected"}`))
+			return
+		}
+		encoded, err := proposal.B64Encode()
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(encoded))
+	}))
+	defer server.Close()
+
+	result, err := Request(context.Background(), server.Client(), server.URL, newOriginal(t), params)
+	require.NoError(t, err)
+	require.Equal(t, proposal.UnsignedTx.TxHash(), result.UnsignedTx.TxHash())
+
+	_, err = Request(context.Background(), server.Client(), server.URL, newProposal(t, 0), params)
+	require.ErrorContains(t, err, "original-psbt-rejected")
+}
+
+func TestCheckProposal(t *testing.T) {
+	receiverOutputs, err := CheckProposal(newOriginal(t), newProposal(t, 500), params, isOurs)
+	require.NoError(t, err)
+	require.Equal(t, map[wire.OutPoint]*wire.TxOut{
+		receiverOutPt: wire.NewTxOut(50000, receiverScript),
+	}, receiverOutputs)
+
+	// The receiver did not add any inputs.
+	_, err = CheckProposal(newOriginal(t), newOriginal(t), params, isOurs)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(*psbt.Packet)
+	}{
+		{"locktime", func(p *psbt.Packet) { p.UnsignedTx.LockTime++ }},
+		{"version", func(p *psbt.Packet) { p.UnsignedTx.Version = 1 }},
+		{"our sequence", func(p *psbt.Packet) { p.UnsignedTx.TxIn[1].Sequence = 0xffffffff }},
+		{"receiver sequence", func(p *psbt.Packet) { p.UnsignedTx.TxIn[0].Sequence = 0xffffffff }},
+		{"not finalized", func(p *psbt.Packet) { p.Inputs[0].FinalScriptWitness = nil }},
+		{"missing utxo", func(p *psbt.Packet) { p.Inputs[0].WitnessUtxo = nil }},
+		{"our input added", func(p *psbt.Packet) { p.Inputs[0].WitnessUtxo.PkScript = changeScript }},
+		{"missing our input", func(p *psbt.Packet) {
+			p.UnsignedTx.TxIn[1].PreviousOutPoint = wire.OutPoint{Hash: chainhash.Hash{3}}
+			p.Inputs[1].WitnessUtxo = wire.NewTxOut(100000, receiverScript)
+			p.Inputs[1].FinalScriptWitness = []byte{0x01, 0x01, 0x03}
+		}},
+		{"payment decreased", func(p *psbt.Packet) { p.UnsignedTx.TxOut[1].Value = 59000 }},
+		{"missing output", func(p *psbt.Packet) { p.UnsignedTx.TxOut[1].PkScript = receiverScript }},
+		{"contribution not for fees", func(p *psbt.Packet) { p.UnsignedTx.TxOut[1].Value += 500 }},
+		{"script type", func(p *psbt.Packet) { p.Inputs[0].WitnessUtxo.PkScript = taprootScript }},
+		{"fee rate lowered", func(p *psbt.Packet) {
+			// The fee is unchanged, but the size increased because of the additional input.
+			p.UnsignedTx.TxOut[0].Value = 39000
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proposal := newProposal(t, 500)
+			test.modify(proposal)
+			_, err := CheckProposal(newOriginal(t), proposal, params, isOurs)
+			require.Error(t, err)
+		})
+	}
+
+	// Contribution larger than the allowed maximum.
+	_, err = CheckProposal(newOriginal(t), newProposal(t, 701), params, isOurs)
+	require.Error(t, err)
+
+	// Fee rate below the minimum, even though it is not lower than the fee rate of the original.
+	minFeeRateParams := params
+	minFeeRateParams.MinFeeRate = 10000
+	_, err = CheckProposal(newOriginal(t), newProposal(t, 500), minFeeRateParams, isOurs)
+	require.ErrorContains(t, err, "below the minimum")
+}
+
+func TestInputVirtualSize(t *testing.T) {
+	txIn := &wire.TxIn{PreviousOutPoint: ourOutPoint}
+	require.Equal(t, 42, InputVirtualSize(txIn))
+	txIn.Witness = wire.TxWitness{make([]byte, 72), make([]byte, 33)}
+	require.Equal(t, 68, InputVirtualSize(txIn))
+}
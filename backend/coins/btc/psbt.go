@@ -0,0 +1,240 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtFingerprint converts a root fingerprint to the representation used in PSBT key origins.
+func psbtFingerprint(rootFingerprint []byte) uint32 {
+	return binary.LittleEndian.Uint32(rootFingerprint)
+}
+
+// psbtKeyInfo fills the scripts and key origins of an address of this account, so that signers can
+// recognize and sign for it. It is used for both inputs and change outputs.
+func psbtKeyInfo(address *addresses.AccountAddress) (
+	redeemScript []byte,
+	witnessScript []byte,
+	derivations []*psbt.Bip32Derivation,
+	taprootDerivations []*psbt.TaprootBip32Derivation,
+	taprootInternalKey []byte,
+	err error,
+) {
+	configuration := address.Configuration
+	switch configuration.ScriptType() {
+	case signing.ScriptTypeP2WPKHP2SH:
+		_, redeemScript = address.ScriptForHashToSign()
+	case signing.ScriptTypeP2WSH:
+		_, witnessScript = address.ScriptForHashToSign()
+		publicKeys, err := configuration.CosignerPublicKeys()
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		for i, cosigner := range configuration.BitcoinMultisig.Cosigners {
+			derivations = append(derivations, &psbt.Bip32Derivation{
+				PubKey:               publicKeys[i].SerializeCompressed(),
+				MasterKeyFingerprint: psbtFingerprint(cosigner.RootFingerprint),
+				Bip32Path:            cosigner.AbsoluteKeypath.ToUInt32(),
+			})
+		}
+		return redeemScript, witnessScript, derivations, nil, nil, nil
+	case signing.ScriptTypeP2TR:
+		taprootInternalKey = schnorr.SerializePubKey(configuration.PublicKey())
+		taprootDerivations = []*psbt.TaprootBip32Derivation{{
+			XOnlyPubKey:          taprootInternalKey,
+			MasterKeyFingerprint: psbtFingerprint(configuration.BitcoinSimple.KeyInfo.RootFingerprint),
+			Bip32Path:            configuration.AbsoluteKeypath().ToUInt32(),
+		}}
+		return nil, nil, nil, taprootDerivations, taprootInternalKey, nil
+	}
+	derivations = []*psbt.Bip32Derivation{{
+		PubKey:               configuration.PublicKey().SerializeCompressed(),
+		MasterKeyFingerprint: psbtFingerprint(configuration.BitcoinSimple.KeyInfo.RootFingerprint),
+		Bip32Path:            configuration.AbsoluteKeypath().ToUInt32(),
+	}}
+	return redeemScript, witnessScript, derivations, nil, nil, nil
+}
+
+// newPSBT creates a PSBT (BIP-174) from a tx proposal, containing all the info needed by other
+// wallets to sign the inputs.
+func (account *Account) newPSBT(ctx context.Context, txProposal *maketx.TxProposal) (*psbt.Packet, error) {
+	return NewPSBT(txProposal, account.transactionGetter(ctx))
+}
+
+// NewPSBT creates a PSBT (BIP-174) from a tx proposal. getPrevTx is used to fetch the previous
+// transactions of non-taproot inputs. It is used by keystores which sign PSBTs, e.g. external
+// signers.
+func NewPSBT(
+	txProposal *maketx.TxProposal,
+	getPrevTx func(chainhash.Hash) (*wire.MsgTx, error),
+) (*psbt.Packet, error) {
+	if txProposal.SilentPaymentAddress != "" {
+		return nil, errp.New("PSBTs are not supported for silent payments")
+	}
+	packet, err := psbt.NewFromUnsignedTx(txProposal.Transaction.Copy())
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	for index, txIn := range txProposal.Transaction.TxIn {
+		spentOutput, ok := txProposal.PreviousOutputs[txIn.PreviousOutPoint]
+		if !ok {
+			return nil, errp.New("There needs to be exactly one output being spent per input!")
+		}
+		input := &packet.Inputs[index]
+		scriptType := spentOutput.Address.Configuration.ScriptType()
+		if scriptType != signing.ScriptTypeP2TR {
+			// Signers need the full previous transaction for non-taproot inputs to verify the
+			// input amounts.
+			prevTx, err := getPrevTx(txIn.PreviousOutPoint.Hash)
+			if err != nil {
+				return nil, err
+			}
+			input.NonWitnessUtxo = prevTx
+			input.SighashType = txscript.SigHashAll
+		}
+		if scriptType != signing.ScriptTypeP2PKH {
+			input.WitnessUtxo = spentOutput.TxOut
+		}
+		input.RedeemScript, input.WitnessScript, input.Bip32Derivation,
+			input.TaprootBip32Derivation, input.TaprootInternalKey, err = psbtKeyInfo(spentOutput.Address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if txProposal.ChangeAddress != nil {
+		changePkScript := txProposal.ChangeAddress.PubkeyScript()
+		for index, txOut := range txProposal.Transaction.TxOut {
+			if string(txOut.PkScript) != string(changePkScript) {
+				continue
+			}
+			output := &packet.Outputs[index]
+			output.RedeemScript, output.WitnessScript, output.Bip32Derivation,
+				output.TaprootBip32Derivation, output.TaprootInternalKey, err = psbtKeyInfo(
+				txProposal.ChangeAddress)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return packet, nil
+}
+
+// ExportPSBT returns the active tx proposal (see TxProposal()) as a base64 encoded PSBT (BIP-174),
+// so it can be signed by other wallets or air-gapped signers. If sign is true, the inputs are
+// signed with the keystore of the account first, which is needed e.g. to add our signature to a
+// multisig transaction.
+func (account *Account) ExportPSBT(ctx context.Context, sign bool) (string, error) {
+	unlock := account.activeTxProposalLock.RLock()
+	txProposal := account.activeTxProposal
+	unlock()
+	if txProposal == nil {
+		return "", errp.New("No active tx proposal")
+	}
+	packet, err := account.newPSBT(ctx, txProposal)
+	if err != nil {
+		return "", err
+	}
+	if sign {
+		account.log.Info("Signing PSBT")
+		proposedTransaction, err := account.keystoreSign(
+			txProposal, account.transactionGetter(ctx))
+		if err != nil {
+			return "", errp.WithMessage(err, "Failed to sign transaction")
+		}
+		for index, signature := range proposedTransaction.Signatures {
+			if signature == nil {
+				continue
+			}
+			txIn := txProposal.Transaction.TxIn[index]
+			address := txProposal.PreviousOutputs[txIn.PreviousOutPoint].Address
+			if address.Configuration.ScriptType() == signing.ScriptTypeP2TR {
+				packet.Inputs[index].TaprootKeySpendSig = signature.SerializeCompact()
+				continue
+			}
+			packet.Inputs[index].PartialSigs = append(packet.Inputs[index].PartialSigs,
+				&psbt.PartialSig{
+					PubKey:    address.Configuration.PublicKey().SerializeCompressed(),
+					Signature: append(signature.SerializeDER(), byte(txscript.SigHashAll)),
+				})
+		}
+	}
+	return packet.B64Encode()
+}
+
+// SendPSBT finalizes a signed base64 encoded PSBT (BIP-174) spending from this account and
+// broadcasts the resulting transaction. All inputs need to spend unspent outputs of this account
+// and need to be fully signed. The transaction ID is returned.
+func (account *Account) SendPSBT(ctx context.Context, encodedPSBT string, txNote string) (string, error) {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(strings.TrimSpace(encodedPSBT)), true)
+	if err != nil {
+		return "", errp.WithStack(err)
+	}
+	spendableOutputs, err := account.transactions.SpendableOutputs()
+	if err != nil {
+		return "", err
+	}
+	previousOutputs := maketx.PreviousOutputs{}
+	for index, txIn := range packet.UnsignedTx.TxIn {
+		spendableOutput, ok := spendableOutputs[txIn.PreviousOutPoint]
+		if !ok {
+			return "", errp.Newf("input %d does not spend an unspent output of this account", index)
+		}
+		previousOutputs[txIn.PreviousOutPoint] = maketx.UTXO{
+			TxOut:   spendableOutput.TxOut,
+			Address: account.getAddress(blockchain.NewScriptHashHex(spendableOutput.TxOut.PkScript)),
+		}
+		// Some signers strip the previous outputs, but the finalizer needs them.
+		input := &packet.Inputs[index]
+		if input.WitnessUtxo == nil && input.NonWitnessUtxo == nil {
+			input.WitnessUtxo = spendableOutput.TxOut
+		}
+	}
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return "", errp.WithMessage(err, "The PSBT is not fully signed")
+	}
+	transaction, err := psbt.Extract(packet)
+	if err != nil {
+		return "", errp.WithStack(err)
+	}
+	if err := TxValidityCheck(transaction, previousOutputs,
+		txscript.NewTxSigHashes(transaction, previousOutputs)); err != nil {
+		return "", err
+	}
+
+	account.log.Info("Signed PSBT transaction is broadcasted")
+	if err := account.Blockchain().TransactionBroadcast(ctx, transaction); err != nil {
+		return "", err
+	}
+	txID := transaction.TxHash().String()
+	if err := account.SetTxNote(txID, txNote); err != nil {
+		// Not critical.
+		account.log.WithError(err).Error("Failed to save transaction note when sending a tx")
+	}
+	return txID, nil
+}
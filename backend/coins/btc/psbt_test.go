@@ -0,0 +1,263 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
+	accountsMocks "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/mocks"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	blockchainMock "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain/mocks"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPSBT(t *testing.T) {
+	account := mockAccount(t, nil)
+	require.NoError(t, account.Initialize())
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+	account.ensureAddresses()
+
+	receiveAddresses, err := account.subaccounts[0].receiveAddresses.GetUnused()
+	require.NoError(t, err)
+	changeAddresses, err := account.subaccounts[0].changeAddresses.GetUnused()
+	require.NoError(t, err)
+	receiveAddress, changeAddress := receiveAddresses[0], changeAddresses[0]
+
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(100000, receiveAddress.PubkeyScript()))
+	account.Blockchain().(*blockchainMock.BlockchainMock).MockTransactionGet = func(
+		hash chainhash.Hash) (*wire.MsgTx, error) {
+		require.Equal(t, prevTx.TxHash(), hash)
+		return prevTx, nil
+	}
+
+	outPoint := wire.OutPoint{Hash: prevTx.TxHash(), Index: 0}
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&outPoint, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(50000, changeAddress.PubkeyScript()))
+	tx.AddTxOut(wire.NewTxOut(49000, []byte{0x00, 0x14, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}))
+	txProposal := &maketx.TxProposal{
+		Coin:          account.coin,
+		Transaction:   tx,
+		ChangeAddress: changeAddress,
+		PreviousOutputs: maketx.PreviousOutputs{
+			outPoint: {TxOut: prevTx.TxOut[0], Address: receiveAddress},
+		},
+	}
+
+	packet, err := account.newPSBT(context.Background(), txProposal)
+	require.NoError(t, err)
+	encoded, err := packet.B64Encode()
+	require.NoError(t, err)
+	packet, err = psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+	require.NoError(t, err)
+
+	require.Len(t, packet.Inputs, 1)
+	input := packet.Inputs[0]
+	require.Equal(t, prevTx.TxOut[0], input.WitnessUtxo)
+	require.Equal(t, prevTx.TxHash(), input.NonWitnessUtxo.TxHash())
+	require.Len(t, input.Bip32Derivation, 1)
+	require.Equal(t, uint32(0x04030201), input.Bip32Derivation[0].MasterKeyFingerprint)
+	require.Equal(t,
+		receiveAddress.Configuration.AbsoluteKeypath().ToUInt32(), input.Bip32Derivation[0].Bip32Path)
+	require.Equal(t,
+		receiveAddress.Configuration.PublicKey().SerializeCompressed(), input.Bip32Derivation[0].PubKey)
+
+	require.Len(t, packet.Outputs, 2)
+	require.Len(t, packet.Outputs[0].Bip32Derivation, 1)
+	require.Equal(t,
+		changeAddress.Configuration.AbsoluteKeypath().ToUInt32(),
+		packet.Outputs[0].Bip32Derivation[0].Bip32Path)
+	require.Empty(t, packet.Outputs[1].Bip32Derivation)
+
+	// The outputs spent by the PSBT are not known to the account.
+	_, err = account.SendPSBT(context.Background(), encoded, "")
+	require.Error(t, err)
+}
+
+// TestMultisigPSBT spends from a 2-of-3 multisig account. Our signature alone is not enough, so the
+// transaction is exported as a PSBT with our signature, co-signed and sent as a PSBT.
+func TestMultisigPSBT(t *testing.T) {
+	net := &chaincfg.TestNet3Params
+	keypath, err := signing.NewAbsoluteKeypath("m/48'/1'/0'/2'")
+	require.NoError(t, err)
+	masters := make([]*hdkeychain.ExtendedKey, 3)
+	cosigners := make([]signing.KeyInfo, 3)
+	for i := range masters {
+		masters[i], err = hdkeychain.NewMaster(append(make([]byte, 31), byte(i+1)), net)
+		require.NoError(t, err)
+		xprv, err := keypath.Derive(masters[i])
+		require.NoError(t, err)
+		xpub, err := xprv.Neuter()
+		require.NoError(t, err)
+		cosigners[i] = signing.KeyInfo{
+			RootFingerprint:   []byte{byte(i + 1), 0, 0, 0},
+			AbsoluteKeypath:   keypath,
+			ExtendedPublicKey: xpub,
+		}
+	}
+	// Our key is the first one.
+	signingConfiguration, err := signing.NewBitcoinMultisigConfiguration(
+		signing.ScriptTypeP2WSH, 2, cosigners, 0)
+	require.NoError(t, err)
+	account := mockAccount(t, &config.Account{
+		Code:                  "multisig",
+		Name:                  "multisig",
+		SigningConfigurations: signing.Configurations{signingConfiguration},
+	})
+	account.Config().ConnectKeystore = func() (keystore.Keystore, error) {
+		keystoreMock := mockKeystore()
+		keystoreMock.SignTransactionFunc = func(proposedTransaction interface{}) error {
+			btcProposedTx := proposedTransaction.(*ProposedTransaction)
+			txProposal := btcProposedTx.TXProposal
+			for index, txIn := range txProposal.Transaction.TxIn {
+				spentOutput := txProposal.PreviousOutputs[txIn.PreviousOutPoint]
+				xprv, err := spentOutput.Address.Configuration.AbsoluteKeypath().Derive(masters[0])
+				require.NoError(t, err)
+				prv, err := xprv.ECPrivKey()
+				require.NoError(t, err)
+				_, witnessScript := spentOutput.Address.ScriptForHashToSign()
+				signatureHash, err := txscript.CalcWitnessSigHash(witnessScript, txProposal.SigHashes(),
+					txscript.SigHashAll, txProposal.Transaction, index, spentOutput.TxOut.Value)
+				require.NoError(t, err)
+				signature := ecdsa.SignCompact(prv, signatureHash, true)
+				btcProposedTx.Signatures[index] = &types.Signature{
+					R: new(big.Int).SetBytes(signature[1:33]),
+					S: new(big.Int).SetBytes(signature[33:]),
+				}
+			}
+			return nil
+		}
+		return keystoreMock, nil
+	}
+
+	// Fund the first receive address of the account.
+	relativeKeypath, err := signing.NewRelativeKeypath("0/0")
+	require.NoError(t, err)
+	fundedAddress := addresses.NewAccountAddress(
+		signingConfiguration, relativeKeypath, net, logging.Get().WithGroup("psbt_test"))
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(100000, fundedAddress.PubkeyScript()))
+	history := blockchain.TxHistory{{Height: 10, TXHash: blockchain.TXHash(prevTx.TxHash())}}
+
+	notifier := &accountsMocks.Notifier{}
+	notifier.On("Put", mock.Anything).Return(nil)
+	account.Config().GetNotifier = func(signing.Configurations) accounts.Notifier { return notifier }
+	require.NoError(t, account.Initialize())
+	defer account.Close()
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+	chain := account.Blockchain().(*blockchainMock.BlockchainMock)
+	chain.MockScriptHashGetHistory = func(scriptHashHex blockchain.ScriptHashHex) (blockchain.TxHistory, error) {
+		require.Equal(t, fundedAddress.PubkeyScriptHashHex(), scriptHashHex)
+		return history, nil
+	}
+	chain.MockTransactionGet = func(hash chainhash.Hash) (*wire.MsgTx, error) {
+		require.Equal(t, prevTx.TxHash(), hash)
+		return prevTx, nil
+	}
+	chain.MockRelayFee = func() (btcutil.Amount, error) { return 1000, nil }
+	var broadcasted *wire.MsgTx
+	chain.MockTransactionBroadcast = func(transaction *wire.MsgTx) error {
+		broadcasted = transaction
+		return nil
+	}
+	account.onAddressStatus(account.getAddress(fundedAddress.PubkeyScriptHashHex()), history.Status())
+	require.Eventually(t, func() bool {
+		balance, err := account.Balance()
+		return err == nil && balance.Available().BigInt().Int64() == 100000
+	}, time.Second, time.Millisecond*50)
+
+	_, _, _, err = account.TxProposal(context.Background(), &accounts.TxProposalArgs{
+		RecipientAddress: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+		Amount:           coin.NewSendAmount("0.0005"),
+		FeeTargetCode:    accounts.FeeTargetCodeCustom,
+		CustomFee:        "2",
+	})
+	require.NoError(t, err)
+
+	// The transaction can't be sent without the signature of a cosigner.
+	err = account.SendTx(context.Background(), "")
+	require.Equal(t, errors.ErrMultisigCosignersRequired, errp.Cause(err))
+	require.Nil(t, broadcasted)
+
+	encoded, err := account.ExportPSBT(context.Background(), true)
+	require.NoError(t, err)
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+	require.NoError(t, err)
+	require.Len(t, packet.Inputs, 1)
+	require.Len(t, packet.Inputs[0].PartialSigs, 1)
+	// Only our signature is not enough.
+	_, err = account.SendPSBT(context.Background(), encoded, "")
+	require.Error(t, err)
+	require.Nil(t, broadcasted)
+
+	// The second cosigner signs.
+	input := packet.Inputs[0]
+	var cosignerPath []uint32
+	for _, derivation := range input.Bip32Derivation {
+		if derivation.MasterKeyFingerprint == psbtFingerprint(cosigners[1].RootFingerprint) {
+			cosignerPath = derivation.Bip32Path
+		}
+	}
+	xprv, err := signing.NewAbsoluteKeypathFromUint32(cosignerPath...).Derive(masters[1])
+	require.NoError(t, err)
+	prv, err := xprv.ECPrivKey()
+	require.NoError(t, err)
+	prevOutputFetcher := txscript.NewCannedPrevOutputFetcher(
+		input.WitnessUtxo.PkScript, input.WitnessUtxo.Value)
+	signature, err := txscript.RawTxInWitnessSignature(packet.UnsignedTx,
+		txscript.NewTxSigHashes(packet.UnsignedTx, prevOutputFetcher), 0, input.WitnessUtxo.Value,
+		input.WitnessScript, txscript.SigHashAll, prv)
+	require.NoError(t, err)
+	updater, err := psbt.NewUpdater(packet)
+	require.NoError(t, err)
+	signOutcome, err := updater.Sign(0, signature, prv.PubKey().SerializeCompressed(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, psbt.SignOutcome(psbt.SignSuccesful), signOutcome)
+	encoded, err = packet.B64Encode()
+	require.NoError(t, err)
+
+	txID, err := account.SendPSBT(context.Background(), encoded, "")
+	require.NoError(t, err)
+	require.NotNil(t, broadcasted)
+	require.Equal(t, broadcasted.TxHash().String(), txID)
+	require.Len(t, broadcasted.TxIn[0].Witness, 4)
+}
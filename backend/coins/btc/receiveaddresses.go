@@ -0,0 +1,157 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// ReceiveAddress is a derived receive address with its usage status.
+type ReceiveAddress struct {
+	Address    *addresses.AccountAddress
+	ScriptType signing.ScriptType
+	// Index is the position of the address in the receive chain, i.e. the last element of its
+	// keypath.
+	Index uint32
+	// Used is true if the address appears in any transaction.
+	Used bool
+	// Balance is the sum of the unspent outputs of the address, including unconfirmed ones.
+	Balance btcutil.Amount
+	// Label is the label the user set for the address, empty if there is none.
+	Label string
+}
+
+// ReceiveAddresses returns all derived receive addresses of all subaccounts, including the used
+// ones, in the order of the subaccounts and then the derivation order. Any unused address can be
+// handed out instead of the next one offered by GetUnusedReceiveAddresses(), and it can be verified
+// with VerifyAddress().
+func (account *Account) ReceiveAddresses() ([]*ReceiveAddress, error) {
+	if !account.isInitialized() {
+		return nil, errp.New("account must be initialized")
+	}
+	account.Synchronizer.WaitSynchronized()
+	utxos, err := account.transactions.SpendableOutputs()
+	if err != nil {
+		return nil, err
+	}
+	balances := map[blockchain.ScriptHashHex]btcutil.Amount{}
+	for _, utxo := range utxos {
+		balances[blockchain.NewScriptHashHex(utxo.TxOut.PkScript)] += btcutil.Amount(utxo.TxOut.Value)
+	}
+
+	result := []*ReceiveAddress{}
+	for _, subacc := range account.subaccounts {
+		scriptType := subacc.signingConfiguration.ScriptType()
+		for _, address := range subacc.receiveAddresses.Addresses() {
+			used, err := account.isAddressUsed(address)
+			if err != nil {
+				return nil, err
+			}
+			keypath := address.AbsoluteKeypath().ToUInt32()
+			encoded := address.EncodeForHumans()
+			result = append(result, &ReceiveAddress{
+				Address:    address,
+				ScriptType: scriptType,
+				Index:      keypath[len(keypath)-1],
+				Used:       used,
+				Balance:    balances[address.PubkeyScriptHashHex()],
+				Label:      account.AddressNote(encoded),
+			})
+		}
+	}
+	return result, nil
+}
+
+// SetReceiveAddressNote sets the label of a receive address of the account, identified by its
+// encoding.
+func (account *Account) SetReceiveAddressNote(address string, note string) error {
+	if !account.isInitialized() {
+		return errp.New("account must be initialized")
+	}
+	pkScript, err := account.coin.AddressToPkScript(address)
+	if err != nil {
+		return err
+	}
+	scriptHashHex := blockchain.NewScriptHashHex(pkScript)
+	for _, subacc := range account.subaccounts {
+		if accountAddress := subacc.receiveAddresses.LookupByScriptHashHex(scriptHashHex); accountAddress != nil {
+			return account.SetAddressNote(accountAddress.EncodeForHumans(), note)
+		}
+	}
+	return errp.New("unknown address not found")
+}
+
+// PreviewAddress is an unused address returned by PreviewUnusedAddresses().
+type PreviewAddress struct {
+	Address *addresses.AccountAddress
+	// Watched is false if the address lies beyond the gap limit. Funds sent to it are only found
+	// once the addresses before it are used, or after scanning further.
+	Watched bool
+}
+
+// AddressPreview are the next unused receive and change addresses of a subaccount.
+type AddressPreview struct {
+	ScriptType signing.ScriptType
+	Receive    []*PreviewAddress
+	Change     []*PreviewAddress
+}
+
+// PreviewUnusedAddresses returns the next `count` unused receive and change addresses of each
+// subaccount, e.g. to set up recurring payments or the withdrawal address whitelist of an exchange.
+// The addresses are not marked as used or handed out in any way.
+func (account *Account) PreviewUnusedAddresses(count int) ([]*AddressPreview, error) {
+	if !account.isInitialized() {
+		return nil, errp.New("account must be initialized")
+	}
+	if count <= 0 || count > maxGapLimit {
+		return nil, errp.Newf("the number of addresses must be between 1 and %d", maxGapLimit)
+	}
+	account.Synchronizer.WaitSynchronized()
+	preview := func(addressChain *addresses.AddressChain) ([]*PreviewAddress, error) {
+		unusedAddresses, err := addressChain.PreviewUnused(count)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]*PreviewAddress, len(unusedAddresses))
+		for i, address := range unusedAddresses {
+			result[i] = &PreviewAddress{
+				Address: address,
+				Watched: addressChain.LookupByScriptHashHex(address.PubkeyScriptHashHex()) != nil,
+			}
+		}
+		return result, nil
+	}
+	result := []*AddressPreview{}
+	for _, subacc := range account.subaccounts {
+		receive, err := preview(subacc.receiveAddresses)
+		if err != nil {
+			return nil, err
+		}
+		change, err := preview(subacc.changeAddresses)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &AddressPreview{
+			ScriptType: subacc.signingConfiguration.ScriptType(),
+			Receive:    receive,
+			Change:     change,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiveAddresses(t *testing.T) {
+	account := mockAccount(t, nil)
+	_, err := account.ReceiveAddresses()
+	require.Error(t, err)
+
+	require.NoError(t, account.Initialize())
+	defer account.Close()
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+
+	receiveAddresses, err := account.ReceiveAddresses()
+	require.NoError(t, err)
+	require.Len(t, receiveAddresses, 20)
+	for index, receiveAddress := range receiveAddresses {
+		require.Equal(t, uint32(index), receiveAddress.Index)
+		require.Equal(t, signing.ScriptTypeP2WPKH, receiveAddress.ScriptType)
+		require.False(t, receiveAddress.Used)
+		require.Zero(t, receiveAddress.Balance)
+		require.Empty(t, receiveAddress.Label)
+	}
+	// The list matches the unused addresses offered to the user.
+	require.Equal(t,
+		account.GetUnusedReceiveAddresses()[0].Addresses[5].EncodeForHumans(),
+		receiveAddresses[5].Address.EncodeForHumans())
+
+	address := receiveAddresses[5].Address.EncodeForHumans()
+	require.NoError(t, account.SetReceiveAddressNote(address, "donations"))
+	receiveAddresses, err = account.ReceiveAddresses()
+	require.NoError(t, err)
+	require.Equal(t, "donations", receiveAddresses[5].Label)
+	require.Empty(t, receiveAddresses[4].Label)
+
+	// Only receive addresses of the account can be labeled.
+	require.Error(t, account.SetReceiveAddressNote("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", "x"))
+	require.Error(t, account.SetReceiveAddressNote("invalid", "x"))
+}
+
+func TestPreviewUnusedAddresses(t *testing.T) {
+	account := mockAccount(t, nil)
+	_, err := account.PreviewUnusedAddresses(5)
+	require.Error(t, err)
+
+	require.NoError(t, account.Initialize())
+	defer account.Close()
+	require.Eventually(t, account.Synced, time.Second, time.Millisecond*200)
+
+	_, err = account.PreviewUnusedAddresses(0)
+	require.Error(t, err)
+	_, err = account.PreviewUnusedAddresses(maxGapLimit + 1)
+	require.Error(t, err)
+
+	preview, err := account.PreviewUnusedAddresses(25)
+	require.NoError(t, err)
+	require.Len(t, preview, 1)
+	require.Equal(t, signing.ScriptTypeP2WPKH, preview[0].ScriptType)
+	require.Len(t, preview[0].Receive, 25)
+	require.Len(t, preview[0].Change, 25)
+	require.Equal(t, "m/84'/1'/0'/0/24", preview[0].Receive[24].Address.AbsoluteKeypath().Encode())
+	require.Equal(t, "m/84'/1'/0'/1/0", preview[0].Change[0].Address.AbsoluteKeypath().Encode())
+	// The receive gap limit is 20, the change gap limit is 6.
+	require.True(t, preview[0].Receive[19].Watched)
+	require.False(t, preview[0].Receive[20].Watched)
+	require.True(t, preview[0].Change[5].Watched)
+	require.False(t, preview[0].Change[6].Watched)
+
+	// Nothing is derived or marked as used.
+	receiveAddresses, err := account.ReceiveAddresses()
+	require.NoError(t, err)
+	require.Len(t, receiveAddresses, 20)
+	require.Equal(t,
+		preview[0].Receive[0].Address.EncodeForHumans(),
+		account.GetUnusedReceiveAddresses()[0].Addresses[0].EncodeForHumans())
+}
@@ -15,6 +15,7 @@
 package btc
 
 import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
@@ -44,26 +45,59 @@ type ProposedTransaction struct {
 func (p *ProposedTransaction) Finalize() error {
 	for index, input := range p.TXProposal.Transaction.TxIn {
 		address := p.TXProposal.PreviousOutputs[input.PreviousOutPoint].Address
+		if address == nil {
+			// Input of another party, e.g. of a payjoin receiver, which they finalize themselves.
+			continue
+		}
 		signature := p.Signatures[index]
 		if signature == nil {
 			return errp.New("Signature missing")
 		}
+		if multisig := address.Configuration.BitcoinMultisig; multisig != nil {
+			// Only our own signature is available here. This is enough only if the threshold is 1,
+			// otherwise the cosigners need to sign the exported PSBT as well, see ExportPSBT().
+			if multisig.Threshold > 1 {
+				return errp.WithStack(errors.ErrMultisigCosignersRequired)
+			}
+			signatures := make([]*types.Signature, len(multisig.Cosigners))
+			signatures[multisig.OurXPubIndex] = signature
+			witness, err := address.MultisigWitness(signatures)
+			if err != nil {
+				return err
+			}
+			input.SignatureScript, input.Witness = []byte{}, witness
+			continue
+		}
 		input.SignatureScript, input.Witness = address.SignatureScript(*signature)
 	}
 	return nil
 }
 
-// signTransaction signs all inputs. It assumes all outputs spent belong to this
-// wallet. previousOutputs must contain all outputs which are spent by the transaction.
-func (account *Account) signTransaction(
+// needsCosigners returns true if the transaction spends outputs of a multisig address which need
+// the signatures of cosigners in addition to ours, i.e. with a threshold larger than 1.
+func needsCosigners(txProposal *maketx.TxProposal) bool {
+	for _, previousOutput := range txProposal.PreviousOutputs {
+		if previousOutput.Address == nil {
+			continue
+		}
+		if multisig := previousOutput.Address.Configuration.BitcoinMultisig; multisig != nil &&
+			multisig.Threshold > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// keystoreSign signs all inputs with the keystore of the account, without finalizing the
+// transaction. It assumes all outputs spent belong to this wallet.
+func (account *Account) keystoreSign(
 	txProposal *maketx.TxProposal,
 	getPrevTx func(chainhash.Hash) (*wire.MsgTx, error),
-) error {
+) (*ProposedTransaction, error) {
 	signingConfigs := make([]*signing.Configuration, len(account.subaccounts))
 	for i, subacc := range account.subaccounts {
 		signingConfigs[i] = subacc.signingConfiguration
 	}
-	previousOutputs := txProposal.PreviousOutputs
 
 	proposedTransaction := &ProposedTransaction{
 		TXProposal:                   txProposal,
@@ -76,9 +110,29 @@ func (account *Account) signTransaction(
 
 	keystore, err := account.Config().ConnectKeystore()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := keystore.SignTransaction(proposedTransaction); err != nil {
+		return nil, err
+	}
+	return proposedTransaction, nil
+}
+
+// signTransaction signs all inputs. It assumes all outputs spent belong to this
+// wallet. previousOutputs must contain all outputs which are spent by the transaction.
+func (account *Account) signTransaction(
+	txProposal *maketx.TxProposal,
+	getPrevTx func(chainhash.Hash) (*wire.MsgTx, error),
+) error {
+	previousOutputs := txProposal.PreviousOutputs
+
+	// Don't ask the user to sign a transaction which can't be finalized.
+	if needsCosigners(txProposal) {
+		return errp.WithStack(errors.ErrMultisigCosignersRequired)
+	}
+
+	proposedTransaction, err := account.keystoreSign(txProposal, getPrevTx)
+	if err != nil {
 		return err
 	}
 
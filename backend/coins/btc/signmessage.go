@@ -0,0 +1,129 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/signmessage"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// signMessageBIP322 signs the message with the address in the simple BIP-322 format, by signing
+// the virtual `to_sign` transaction with the keystore.
+func (account *Account) signMessageBIP322(address *addresses.AccountAddress, message []byte) (string, error) {
+	switch address.Configuration.ScriptType() {
+	case signing.ScriptTypeP2WPKH, signing.ScriptTypeP2TR:
+	default:
+		return "", errp.Newf("BIP-322 signatures are not supported for %s addresses",
+			address.Configuration.ScriptType())
+	}
+	toSpend, err := signmessage.BIP322ToSpend(address.PubkeyScript(), message)
+	if err != nil {
+		return "", err
+	}
+	toSign := signmessage.BIP322ToSign(toSpend)
+	txProposal := &maketx.TxProposal{
+		Coin:        account.coin,
+		Transaction: toSign,
+		PreviousOutputs: maketx.PreviousOutputs{
+			toSign.TxIn[0].PreviousOutPoint: {TxOut: toSpend.TxOut[0], Address: address},
+		},
+	}
+	getPrevTx := func(hash chainhash.Hash) (*wire.MsgTx, error) {
+		if hash != toSpend.TxHash() {
+			return nil, errp.New("unknown transaction")
+		}
+		return toSpend, nil
+	}
+	proposedTransaction, err := account.keystoreSign(txProposal, getPrevTx)
+	if err != nil {
+		return "", err
+	}
+	if proposedTransaction.Signatures[0] == nil {
+		return "", errp.New("Signature missing")
+	}
+	_, witness := address.SignatureScript(*proposedTransaction.Signatures[0])
+	return signmessage.EncodeBIP322Simple(witness)
+}
+
+// SignMessage signs a message with the private key of an address of the account to prove
+// ownership of the address. The signature is returned in base64 encoding.
+func (account *Account) SignMessage(
+	address string, message string, format signmessage.Format) (string, error) {
+	if !account.isInitialized() {
+		return "", errp.New("account must be initialized")
+	}
+	pkScript, err := account.coin.AddressToPkScript(address)
+	if err != nil {
+		return "", err
+	}
+	accountAddress := account.getAddress(blockchain.NewScriptHashHex(pkScript))
+	if accountAddress == nil {
+		return "", errp.New("The address does not belong to this account")
+	}
+	if accountAddress.Configuration.BitcoinMultisig != nil {
+		return "", errp.New("Signing messages is not supported for multisig addresses")
+	}
+
+	switch format {
+	case signmessage.FormatLegacy:
+		keystore, err := account.Config().ConnectKeystore()
+		if err != nil {
+			return "", err
+		}
+		if !keystore.CanSignMessage(account.coin.Code()) {
+			return "", errp.Newf("The connected device or keystore cannot sign messages for %s",
+				account.coin.Code())
+		}
+		signature, err := keystore.SignBTCMessage(
+			[]byte(message),
+			accountAddress.AbsoluteKeypath(),
+			accountAddress.Configuration.ScriptType(),
+		)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(signature), nil
+	case signmessage.FormatBIP322:
+		return account.signMessageBIP322(accountAddress, []byte(message))
+	default:
+		return "", errp.Newf("Unsupported message signature format: %s", format)
+	}
+}
+
+// VerifyMessage checks a message signature of an address in the legacy or the simple BIP-322
+// format, returning the detected format and whether the signature is valid.
+func (coin *Coin) VerifyMessage(address string, message string, signature string) (
+	signmessage.Format, bool, error) {
+	switch coin.code {
+	case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC:
+	default:
+		return "", false, errp.Newf("Verifying messages is not supported for %s", coin.code)
+	}
+	btcAddress, err := coin.decodeAddress(strings.TrimSpace(address))
+	if err != nil {
+		return "", false, err
+	}
+	return signmessage.Verify(btcAddress, []byte(message), strings.TrimSpace(signature))
+}
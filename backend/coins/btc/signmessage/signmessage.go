@@ -0,0 +1,224 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signmessage contains the message signature formats used to prove ownership of an
+// address: the legacy format of Bitcoin Core's `signmessage` and the generic format of BIP-322.
+package signmessage
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Format is the format of a message signature.
+type Format string
+
+const (
+	// FormatLegacy is the compact signature format of Bitcoin Core's `signmessage`, extended to
+	// segwit addresses as in BIP-137 and Electrum.
+	FormatLegacy Format = "legacy"
+	// FormatBIP322 is the simple signature format of BIP-322. It is only available for segwit
+	// addresses.
+	FormatBIP322 Format = "bip322"
+)
+
+// legacyMagic is prefixed to the message before hashing in the legacy format.
+const legacyMagic = "Bitcoin Signed Message:\n"
+
+// legacySignatureSize is the size of a compact signature with a recovery header byte.
+const legacySignatureSize = 65
+
+// bip322Tag is the tag of the BIP-340 tagged hash of the message.
+const bip322Tag = "BIP0322-signed-message"
+
+// BIP322MessageHash returns the hash of the message committed to in the BIP-322 virtual
+// transactions.
+func BIP322MessageHash(message []byte) chainhash.Hash {
+	return *chainhash.TaggedHash([]byte(bip322Tag), message)
+}
+
+// BIP322ToSpend returns the virtual `to_spend` transaction of BIP-322, whose only output pays to
+// the pkScript of the address signing the message.
+func BIP322ToSpend(pkScript []byte, message []byte) (*wire.MsgTx, error) {
+	messageHash := BIP322MessageHash(message)
+	signatureScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(messageHash[:]).
+		Script()
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	toSpend := wire.NewMsgTx(0)
+	toSpend.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 0xFFFFFFFF},
+		SignatureScript:  signatureScript,
+		Sequence:         0,
+	})
+	toSpend.AddTxOut(wire.NewTxOut(0, pkScript))
+	return toSpend, nil
+}
+
+// BIP322ToSign returns the unsigned virtual `to_sign` transaction of BIP-322 spending the output of
+// `to_spend`. The signature of its input is the message signature.
+func BIP322ToSign(toSpend *wire.MsgTx) *wire.MsgTx {
+	toSign := wire.NewMsgTx(0)
+	toSign.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: toSpend.TxHash(), Index: 0},
+		Sequence:         0,
+	})
+	toSign.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	return toSign
+}
+
+// EncodeBIP322Simple encodes the witness of the signed `to_sign` transaction as a simple BIP-322
+// signature.
+func EncodeBIP322Simple(witness wire.TxWitness) (string, error) {
+	var buf bytes.Buffer
+	if err := wire.WriteVarInt(&buf, 0, uint64(len(witness))); err != nil {
+		return "", errp.WithStack(err)
+	}
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(&buf, 0, item); err != nil {
+			return "", errp.WithStack(err)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// legacyMessageHash returns the hash signed in the legacy format.
+func legacyMessageHash(message []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := wire.WriteVarString(&buf, 0, legacyMagic); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if err := wire.WriteVarBytes(&buf, 0, message); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return chainhash.DoubleHashB(buf.Bytes()), nil
+}
+
+// VerifyLegacy checks a signature in the legacy format. The public key is recovered from the
+// signature and checked against the address, which can be a p2pkh, p2wpkh-p2sh or p2wpkh address.
+// The header byte can be in the BIP-137 or in the Electrum format, which uses the p2pkh header byte
+// for all address types.
+func VerifyLegacy(address btcutil.Address, message []byte, signature []byte) (bool, error) {
+	if len(signature) != legacySignatureSize {
+		return false, errp.New("invalid signature length")
+	}
+	signature = append([]byte{}, signature...)
+	// BIP-137 headers of segwit addresses: 35-38 for p2wpkh-p2sh, 39-42 for p2wpkh. Map them to the
+	// compressed p2pkh headers 31-34 understood by RecoverCompact.
+	if signature[0] >= 35 && signature[0] <= 42 {
+		signature[0] = 31 + (signature[0]-35)%4
+	}
+	hash, err := legacyMessageHash(message)
+	if err != nil {
+		return false, err
+	}
+	publicKey, compressed, err := ecdsa.RecoverCompact(signature, hash)
+	if err != nil {
+		return false, nil
+	}
+	var pubKeyHash []byte
+	if compressed {
+		pubKeyHash = btcutil.Hash160(publicKey.SerializeCompressed())
+	} else {
+		pubKeyHash = btcutil.Hash160(publicKey.SerializeUncompressed())
+	}
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return bytes.Equal(address.ScriptAddress(), pubKeyHash), nil
+	case *btcutil.AddressWitnessPubKeyHash:
+		return compressed && bytes.Equal(address.ScriptAddress(), pubKeyHash), nil
+	case *btcutil.AddressScriptHash:
+		redeemScript := append([]byte{txscript.OP_0, txscript.OP_DATA_20}, pubKeyHash...)
+		return compressed && bytes.Equal(address.ScriptAddress(), btcutil.Hash160(redeemScript)), nil
+	default:
+		return false, errp.Newf("legacy signatures are not supported for %s", address.EncodeAddress())
+	}
+}
+
+// decodeBIP322Simple decodes the witness of a simple BIP-322 signature.
+func decodeBIP322Simple(signature []byte) (wire.TxWitness, error) {
+	reader := bytes.NewReader(signature)
+	count, err := wire.ReadVarInt(reader, 0)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if count > wire.MaxBlockPayload {
+		return nil, errp.New("invalid witness")
+	}
+	witness := wire.TxWitness{}
+	for i := uint64(0); i < count; i++ {
+		item, err := wire.ReadVarBytes(reader, 0, wire.MaxBlockPayload, "witness item")
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+		witness = append(witness, item)
+	}
+	if reader.Len() != 0 {
+		return nil, errp.New("invalid witness")
+	}
+	return witness, nil
+}
+
+// VerifyBIP322Simple checks a simple BIP-322 signature by executing the script of the address
+// with the witness of the signature on the virtual `to_sign` transaction.
+func VerifyBIP322Simple(pkScript []byte, message []byte, signature []byte) (bool, error) {
+	witness, err := decodeBIP322Simple(signature)
+	if err != nil {
+		return false, err
+	}
+	toSpend, err := BIP322ToSpend(pkScript, message)
+	if err != nil {
+		return false, err
+	}
+	toSign := BIP322ToSign(toSpend)
+	toSign.TxIn[0].Witness = witness
+	prevOutputFetcher := txscript.NewCannedPrevOutputFetcher(pkScript, 0)
+	engine, err := txscript.NewEngine(
+		pkScript, toSign, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(toSign, prevOutputFetcher), 0, prevOutputFetcher)
+	if err != nil {
+		return false, nil
+	}
+	return engine.Execute() == nil, nil
+}
+
+// Verify checks a base64 encoded message signature in the legacy or in the simple BIP-322 format.
+// The format is detected from the size of the signature, as legacy signatures have a fixed size
+// which no BIP-322 witness of a single-key address has.
+func Verify(address btcutil.Address, message []byte, signature string) (Format, bool, error) {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return "", false, errp.New("signature is not base64 encoded")
+	}
+	if len(decoded) == legacySignatureSize {
+		valid, err := VerifyLegacy(address, message, decoded)
+		return FormatLegacy, valid, err
+	}
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return "", false, errp.WithStack(err)
+	}
+	valid, err := VerifyBIP322Simple(pkScript, message, decoded)
+	return FormatBIP322, valid, err
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signmessage
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors from BIP-322.
+const bip322Address = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+
+func bip322PkScript(t *testing.T) []byte {
+	t.Helper()
+	address, err := btcutil.DecodeAddress(bip322Address, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(address)
+	require.NoError(t, err)
+	return pkScript
+}
+
+func TestBIP322Transactions(t *testing.T) {
+	for _, test := range []struct {
+		message     string
+		messageHash string
+		toSpend     string
+		toSign      string
+	}{
+		{
+			message:     "",
+			messageHash: "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1",
+			toSpend:     "c5680aa69bb8d860bf82d4e9cd3504b55dde018de765a91bb566283c545a99a7",
+			toSign:      "1e9654e951a5ba44c8604c4de6c67fd78a27e81dcadcfe1edf638ba3aaebaed6",
+		},
+		{
+			message:     "Hello World",
+			messageHash: "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a",
+			toSpend:     "b79d196740ad5217771c1098fc4a4b51e0535c32236c71f1ea4d61a2d603352b",
+			toSign:      "88737ae86f2077145f93cc4b153ae9a1cb8d56afa511988c149c5c8c9d93bddf",
+		},
+	} {
+		messageHash := BIP322MessageHash([]byte(test.message))
+		require.Equal(t, test.messageHash, hex.EncodeToString(messageHash[:]))
+		toSpend, err := BIP322ToSpend(bip322PkScript(t), []byte(test.message))
+		require.NoError(t, err)
+		require.Equal(t, test.toSpend, toSpend.TxHash().String())
+		require.Equal(t, test.toSign, BIP322ToSign(toSpend).TxHash().String())
+	}
+}
+
+func TestEncodeBIP322Simple(t *testing.T) {
+	encoded, err := EncodeBIP322Simple(wire.TxWitness{{1, 2}, {3}})
+	require.NoError(t, err)
+	require.Equal(t, "AgIBAgED", encoded)
+}
+
+func TestVerifyBIP322Simple(t *testing.T) {
+	address, err := btcutil.DecodeAddress(bip322Address, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	signatures := map[string]string{
+		"":            "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+		"Hello World": "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+	}
+	for message, signature := range signatures {
+		format, valid, err := Verify(address, []byte(message), signature)
+		require.NoError(t, err)
+		require.Equal(t, FormatBIP322, format)
+		require.True(t, valid, message)
+	}
+	// Signature of another message.
+	_, valid, err := Verify(address, []byte("Hello World"), signatures[""])
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// Malformed witness.
+	_, _, err = Verify(address, []byte(""), "AgIBAgE=")
+	require.Error(t, err)
+}
+
+func TestVerifyLegacy(t *testing.T) {
+	// Private key 1, see https://en.bitcoin.it/wiki/Wallet_import_format.
+	wif, err := btcutil.DecodeWIF("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	require.NoError(t, err)
+	message := []byte("Hello World")
+	hash, err := legacyMessageHash(message)
+	require.NoError(t, err)
+	signature := ecdsa.SignCompact(wif.PrivKey, hash, true)
+
+	decode := func(address string) btcutil.Address {
+		decoded, err := btcutil.DecodeAddress(address, &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		return decoded
+	}
+	p2pkh := decode("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH")
+	p2wpkhP2SH := decode("3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN")
+	p2wpkh := decode("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+
+	// Electrum format, using the same header byte for all address types.
+	for _, address := range []btcutil.Address{p2pkh, p2wpkhP2SH, p2wpkh} {
+		format, valid, err := Verify(address, message, base64.StdEncoding.EncodeToString(signature))
+		require.NoError(t, err)
+		require.Equal(t, FormatLegacy, format)
+		require.True(t, valid, address.EncodeAddress())
+	}
+
+	// BIP-137 headers.
+	bip137 := append([]byte{}, signature...)
+	bip137[0] += 4
+	valid, err := VerifyLegacy(p2wpkhP2SH, message, bip137)
+	require.NoError(t, err)
+	require.True(t, valid)
+	bip137[0] += 4
+	valid, err = VerifyLegacy(p2wpkh, message, bip137)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// Other message and other address.
+	valid, err = VerifyLegacy(p2pkh, []byte("Hello"), signature)
+	require.NoError(t, err)
+	require.False(t, valid)
+	valid, err = VerifyLegacy(decode(bip322Address), message, signature)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// Uncompressed keys only have p2pkh addresses.
+	uncompressed := ecdsa.SignCompact(wif.PrivKey, hash, false)
+	valid, err = VerifyLegacy(decode("1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm"), message, uncompressed)
+	require.NoError(t, err)
+	require.True(t, valid)
+	valid, err = VerifyLegacy(p2wpkh, message, uncompressed)
+	require.NoError(t, err)
+	require.False(t, valid)
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// SweepResult is the result of sweeping a private key.
+type SweepResult struct {
+	// Amount is the amount received in the account, after deducting the fee.
+	Amount coin.Amount
+	Fee    coin.Amount
+	// TxID is the ID of the broadcasted transaction. Empty if the transaction was not broadcasted.
+	TxID string
+}
+
+// sweepScripts returns the output scripts of all address types of the private key by script type.
+// Segwit addresses only exist for compressed public keys.
+func sweepScripts(wif *btcutil.WIF, net *chaincfg.Params, supportsTaproot bool) (
+	map[signing.ScriptType][]byte, error) {
+	publicKey := wif.SerializePubKey()
+	pubKeyHash := btcutil.Hash160(publicKey)
+	scripts := map[signing.ScriptType][]byte{}
+	p2pkh, err := btcutil.NewAddressPubKeyHash(pubKeyHash, net)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if scripts[signing.ScriptTypeP2PKH], err = util.PkScriptFromAddress(p2pkh); err != nil {
+		return nil, err
+	}
+	if !wif.CompressPubKey {
+		return scripts, nil
+	}
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, net)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if scripts[signing.ScriptTypeP2WPKH], err = util.PkScriptFromAddress(p2wpkh); err != nil {
+		return nil, err
+	}
+	p2wpkhP2SH, err := btcutil.NewAddressScriptHash(scripts[signing.ScriptTypeP2WPKH], net)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if scripts[signing.ScriptTypeP2WPKHP2SH], err = util.PkScriptFromAddress(p2wpkhP2SH); err != nil {
+		return nil, err
+	}
+	if supportsTaproot {
+		outputKey := txscript.ComputeTaprootKeyNoScript(wif.PrivKey.PubKey())
+		p2tr, err := btcutil.NewAddressTaproot(outputKey.SerializeCompressed()[1:], net)
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+		if scripts[signing.ScriptTypeP2TR], err = util.PkScriptFromAddress(p2tr); err != nil {
+			return nil, err
+		}
+	}
+	return scripts, nil
+}
+
+// signSweepTx signs all inputs of the transaction with the private key. previousOutputs must
+// contain the outputs spent by the transaction.
+func signSweepTx(
+	transaction *wire.MsgTx,
+	previousOutputs maketx.PreviousOutputs,
+	wif *btcutil.WIF,
+	scripts map[signing.ScriptType][]byte,
+) error {
+	sigHashes := txscript.NewTxSigHashes(transaction, previousOutputs)
+	for index, txIn := range transaction.TxIn {
+		txOut := previousOutputs[txIn.PreviousOutPoint].TxOut
+		var err error
+		switch {
+		case bytes.Equal(txOut.PkScript, scripts[signing.ScriptTypeP2PKH]):
+			txIn.SignatureScript, err = txscript.SignatureScript(
+				transaction, index, txOut.PkScript, txscript.SigHashAll, wif.PrivKey, wif.CompressPubKey)
+		case bytes.Equal(txOut.PkScript, scripts[signing.ScriptTypeP2WPKH]):
+			txIn.Witness, err = txscript.WitnessSignature(
+				transaction, sigHashes, index, txOut.Value, txOut.PkScript,
+				txscript.SigHashAll, wif.PrivKey, true)
+		case bytes.Equal(txOut.PkScript, scripts[signing.ScriptTypeP2WPKHP2SH]):
+			redeemScript := scripts[signing.ScriptTypeP2WPKH]
+			txIn.Witness, err = txscript.WitnessSignature(
+				transaction, sigHashes, index, txOut.Value, redeemScript,
+				txscript.SigHashAll, wif.PrivKey, true)
+			if err == nil {
+				txIn.SignatureScript, err = txscript.NewScriptBuilder().AddData(redeemScript).Script()
+			}
+		case bytes.Equal(txOut.PkScript, scripts[signing.ScriptTypeP2TR]):
+			txIn.Witness, err = txscript.TaprootWitnessSignature(
+				transaction, sigHashes, index, txOut.Value, txOut.PkScript,
+				txscript.SigHashDefault, wif.PrivKey)
+		default:
+			return errp.Newf("input %d does not belong to the private key", index)
+		}
+		if err != nil {
+			return errp.WithStack(err)
+		}
+	}
+	return TxValidityCheck(transaction, previousOutputs, sigHashes)
+}
+
+// newSweepTx creates a signed transaction spending all previous outputs to the address. The fee is
+// deducted from the swept amount. ErrInsufficientFunds is returned if the remaining amount would
+// be dust.
+func newSweepTx(
+	previousOutputs maketx.PreviousOutputs,
+	wif *btcutil.WIF,
+	scripts map[signing.ScriptType][]byte,
+	address *addresses.AccountAddress,
+	feePerKb btcutil.Amount,
+	rbf bool,
+) (*wire.MsgTx, btcutil.Amount, error) {
+	var total btcutil.Amount
+	transaction := wire.NewMsgTx(wire.TxVersion)
+	for outPoint, utxo := range previousOutputs {
+		txIn := wire.NewTxIn(&outPoint, nil, nil)
+		if rbf {
+			txIn.Sequence = wire.MaxTxInSequenceNum - 2
+		}
+		transaction.AddTxIn(txIn)
+		total += btcutil.Amount(utxo.TxOut.Value)
+	}
+	output := wire.NewTxOut(int64(total), address.PubkeyScript())
+	transaction.AddTxOut(output)
+	// Sign once to determine the size of the transaction.
+	if err := signSweepTx(transaction, previousOutputs, wif, scripts); err != nil {
+		return nil, 0, err
+	}
+	fee := feePerKb * btcutil.Amount(util.VirtualSize(transaction)) / 1000
+	if total <= fee || maketx.IsDust(total-fee, address, feePerKb) {
+		return nil, 0, errp.WithStack(errors.ErrInsufficientFunds)
+	}
+	output.Value = int64(total - fee)
+	if err := signSweepTx(transaction, previousOutputs, wif, scripts); err != nil {
+		return nil, 0, err
+	}
+	return transaction, fee, nil
+}
+
+// sweepOutputs finds the unspent outputs paying to the scripts by scanning their transaction
+// history.
+func (account *Account) sweepOutputs(ctx context.Context, scripts map[signing.ScriptType][]byte) (
+	maketx.PreviousOutputs, error) {
+	chain := account.Blockchain()
+	previousOutputs := maketx.PreviousOutputs{}
+	spent := map[wire.OutPoint]struct{}{}
+	for _, pkScript := range scripts {
+		history, err := chain.ScriptHashGetHistory(ctx, blockchain.NewScriptHashHex(pkScript))
+		if err != nil {
+			return nil, err
+		}
+		for _, txInfo := range history {
+			transaction, err := chain.TransactionGet(ctx, txInfo.TXHash.Hash())
+			if err != nil {
+				return nil, err
+			}
+			for _, txIn := range transaction.TxIn {
+				spent[txIn.PreviousOutPoint] = struct{}{}
+			}
+			for index, txOut := range transaction.TxOut {
+				if bytes.Equal(txOut.PkScript, pkScript) {
+					outPoint := wire.OutPoint{Hash: transaction.TxHash(), Index: uint32(index)}
+					previousOutputs[outPoint] = maketx.UTXO{TxOut: txOut}
+				}
+			}
+		}
+	}
+	for outPoint := range spent {
+		delete(previousOutputs, outPoint)
+	}
+	return previousOutputs, nil
+}
+
+// SweepPrivateKey moves all coins of a private key in WIF format, e.g. of a paper wallet, to an
+// unused receive address of the account. The coins are found by scanning the addresses of all
+// script types of the key. If broadcast is false, the transaction is only created, so the amount
+// and fee can be shown to the user before sweeping.
+func (account *Account) SweepPrivateKey(
+	ctx context.Context,
+	privateKey string,
+	feeTargetCode accounts.FeeTargetCode,
+	customFee string,
+	broadcast bool,
+) (*SweepResult, error) {
+	if !account.isInitialized() {
+		return nil, errp.New("account must be initialized")
+	}
+	wif, err := btcutil.DecodeWIF(strings.TrimSpace(privateKey))
+	if err != nil || !wif.IsForNet(account.coin.Net()) {
+		return nil, errp.WithStack(errors.ErrInvalidPrivateKey)
+	}
+	supportsTaproot := account.coin.Code() != coin.CodeLTC && account.coin.Code() != coin.CodeTLTC
+	scripts, err := sweepScripts(wif, account.coin.Net(), supportsTaproot)
+	if err != nil {
+		return nil, err
+	}
+	previousOutputs, err := account.sweepOutputs(ctx, scripts)
+	if err != nil {
+		return nil, err
+	}
+	if len(previousOutputs) == 0 {
+		return nil, errp.WithStack(errors.ErrInsufficientFunds)
+	}
+	feePerKb, err := account.getFeePerKb(ctx, &accounts.TxProposalArgs{
+		FeeTargetCode: feeTargetCode,
+		CustomFee:     customFee,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	account.Synchronizer.WaitSynchronized()
+	subaccountIndex := account.subaccounts.signingConfigurations().FindScriptType(signing.ScriptTypeP2WPKH)
+	if subaccountIndex < 0 {
+		subaccountIndex = 0
+	}
+	unusedAddresses, err := account.subaccounts[subaccountIndex].receiveAddresses.GetUnused()
+	if err != nil {
+		return nil, err
+	}
+	rbf := account.coin.Code() == coin.CodeBTC ||
+		account.coin.Code() == coin.CodeTBTC ||
+		account.coin.Code() == coin.CodeTSIG ||
+		account.coin.Code() == coin.CodeRBTC
+	transaction, fee, err := newSweepTx(
+		previousOutputs, wif, scripts, unusedAddresses[0], feePerKb, rbf)
+	if err != nil {
+		return nil, err
+	}
+	result := &SweepResult{
+		Amount: coin.NewAmountFromInt64(transaction.TxOut[0].Value),
+		Fee:    coin.NewAmountFromInt64(int64(fee)),
+	}
+	if !broadcast {
+		return result, nil
+	}
+	account.log.Info("Sweep transaction is broadcasted")
+	if err := account.Blockchain().TransactionBroadcast(ctx, transaction); err != nil {
+		return nil, err
+	}
+	result.TxID = transaction.TxHash().String()
+	return result, nil
+}
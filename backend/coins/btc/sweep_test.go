@@ -0,0 +1,106 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
+	addressesTest "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses/test"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepScripts(t *testing.T) {
+	// Private key 1, see https://en.bitcoin.it/wiki/Wallet_import_format.
+	wif, err := btcutil.DecodeWIF("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	require.NoError(t, err)
+	scripts, err := sweepScripts(wif, &chaincfg.MainNetParams, true)
+	require.NoError(t, err)
+	require.Len(t, scripts, 4)
+	for scriptType, expected := range map[signing.ScriptType]string{
+		signing.ScriptTypeP2PKH:      "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		signing.ScriptTypeP2WPKHP2SH: "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN",
+		signing.ScriptTypeP2WPKH:     "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		signing.ScriptTypeP2TR:       "bc1pmfr3p9j00pfxjh0zmgp99y8zftmd3s5pmedqhyptwy6lm87hf5sspknck9",
+	} {
+		address, err := util.AddressFromPkScript(scripts[scriptType], &chaincfg.MainNetParams)
+		require.NoError(t, err)
+		require.Equal(t, expected, address.EncodeAddress(), scriptType)
+	}
+
+	// Without taproot support.
+	scripts, err = sweepScripts(wif, &chaincfg.MainNetParams, false)
+	require.NoError(t, err)
+	require.Len(t, scripts, 3)
+
+	// Uncompressed keys only have legacy addresses.
+	wif, err = btcutil.DecodeWIF("5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ")
+	require.NoError(t, err)
+	scripts, err = sweepScripts(wif, &chaincfg.MainNetParams, true)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+	address, err := util.AddressFromPkScript(scripts[signing.ScriptTypeP2PKH], &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.Equal(t, "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S", address.EncodeAddress())
+}
+
+func TestNewSweepTx(t *testing.T) {
+	wif, err := btcutil.DecodeWIF("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	require.NoError(t, err)
+	scripts, err := sweepScripts(wif, &chaincfg.MainNetParams, true)
+	require.NoError(t, err)
+
+	previousOutputs := maketx.PreviousOutputs{}
+	index := byte(0)
+	for _, pkScript := range scripts {
+		index++
+		previousOutputs[wire.OutPoint{Hash: chainhash.Hash{index}}] = maketx.UTXO{
+			TxOut: wire.NewTxOut(10000, pkScript),
+		}
+	}
+	recipient := addressesTest.GetAddress(signing.ScriptTypeP2WPKH)
+	transaction, fee, err := newSweepTx(previousOutputs, wif, scripts, recipient, 2000, true)
+	require.NoError(t, err)
+	require.Len(t, transaction.TxIn, 4)
+	require.Len(t, transaction.TxOut, 1)
+	// The size of the signatures can differ by a byte between the two signing rounds.
+	require.InDelta(t, 2*util.VirtualSize(transaction), int(fee), 4)
+	require.Equal(t, int64(40000)-int64(fee), transaction.TxOut[0].Value)
+	require.Equal(t, recipient.PubkeyScript(), transaction.TxOut[0].PkScript)
+	require.Equal(t, wire.MaxTxInSequenceNum-2, transaction.TxIn[0].Sequence)
+
+	// The fee can't be paid.
+	_, _, err = newSweepTx(previousOutputs, wif, scripts, recipient, 200000, true)
+	require.Equal(t, errors.ErrInsufficientFunds, errp.Cause(err))
+
+	// The fee can be paid, but the remaining amount is dust.
+	_, _, err = newSweepTx(previousOutputs, wif, scripts, recipient, 70000, true)
+	require.Equal(t, errors.ErrInsufficientFunds, errp.Cause(err))
+
+	// Outputs of other keys can't be swept.
+	previousOutputs[wire.OutPoint{Hash: chainhash.Hash{10}}] = maketx.UTXO{
+		TxOut: wire.NewTxOut(10000, []byte{0x00, 0x14, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}),
+	}
+	_, _, err = newSweepTx(previousOutputs, wif, scripts, recipient, 2000, true)
+	require.Error(t, err)
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package btc
+
+import (
+	"sync/atomic"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/electrum"
+)
+
+// SyncProgress is the progress of syncing an account after it was initialized or reconnected.
+type SyncProgress struct {
+	// SyncedAddresses is the number of address statuses received from the server. It can be
+	// slightly higher than the number of addresses, see `syncedAddressesCount`.
+	SyncedAddresses int `json:"syncedAddresses"`
+	// TotalAddresses is the number of addresses to sync. It grows while syncing when used addresses
+	// are found, as a gap of unused addresses is kept after the last used address.
+	TotalAddresses int `json:"totalAddresses"`
+	// FetchedHistories is the number of address histories downloaded because the address changed
+	// since it was last synced. The other addresses are up to date in the local database.
+	FetchedHistories int `json:"fetchedHistories"`
+	// Percent is the approximate progress from 0 to 100. It is 100 only once the account is synced.
+	Percent int `json:"percent"`
+	// Server is the Electrum server the account is syncing with, or "" if unknown.
+	Server string `json:"server"`
+}
+
+// SyncProgress returns the current sync progress.
+func (account *Account) SyncProgress() SyncProgress {
+	progress := SyncProgress{
+		SyncedAddresses:  int(atomic.LoadUint32(&account.syncedAddressesCount)),
+		FetchedHistories: int(atomic.LoadUint32(&account.fetchedHistoriesCount)),
+	}
+	if account.isInitialized() {
+		for _, subacc := range account.subaccounts {
+			progress.TotalAddresses += subacc.receiveAddresses.Count() + subacc.changeAddresses.Count()
+		}
+		if reporter, ok := account.Blockchain().(electrum.StatusReporter); ok {
+			progress.Server = reporter.Status().ActiveServer
+		}
+	}
+	switch {
+	case account.Synced():
+		progress.Percent = 100
+	case progress.TotalAddresses > 0:
+		// Not synced yet, so at most 99 percent.
+		progress.Percent = min(99, 100*progress.SyncedAddresses/progress.TotalAddresses)
+	}
+	return progress
+}
@@ -16,6 +16,7 @@
 package btc
 
 import (
+	"context"
 	"math/big"
 	"strconv"
 
@@ -24,21 +25,30 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/addresses"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/maketx"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/payjoin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/transactions"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 )
 
 // unitSatoshi is 1 BTC (default unit) in Satoshi.
 const unitSatoshi = 1e8
 
+// isAbsurdFee returns true if the fee is higher than the amount sent. Such fees are rejected for
+// custom fee rates, as they are most likely caused by a typo in the fee rate.
+func isAbsurdFee(fee, amount btcutil.Amount) bool {
+	return fee > amount
+}
+
 // getFeePerKb returns the fee rate to be used in a new transaction. It is deduced from the supplied
 // fee target (priority) if one is given, or the provided args.FeePerKb if the fee taret is
 // `FeeTargetCodeCustom`.
-func (account *Account) getFeePerKb(args *accounts.TxProposalArgs) (btcutil.Amount, error) {
+func (account *Account) getFeePerKb(
+	ctx context.Context, args *accounts.TxProposalArgs) (btcutil.Amount, error) {
 	isPaymentRequest := args.PaymentRequest != nil
 	if args.FeeTargetCode == accounts.FeeTargetCodeCustom && !isPaymentRequest {
 		float, err := strconv.ParseFloat(args.CustomFee, 64)
@@ -48,7 +58,7 @@ func (account *Account) getFeePerKb(args *accounts.TxProposalArgs) (btcutil.Amou
 		// Technically it is vKb (virtual Kb) since fees are computed from a transaction's weight
 		// (measured in weight units or virtual bytes), but we keep the `Kb` unit to be consistent
 		// with the rest of the codebase and Bitcoin Core.
-		minRelayFeeRate, err := account.getMinRelayFeeRate()
+		minRelayFeeRate, err := account.getMinRelayFeeRate(ctx)
 		if err != nil {
 			return 0, err
 		}
@@ -61,9 +71,9 @@ func (account *Account) getFeePerKb(args *accounts.TxProposalArgs) (btcutil.Amou
 
 	var feeTarget *FeeTarget
 	if isPaymentRequest {
-		feeTarget = account.feeTargets().highest()
+		feeTarget = account.feeTargets(ctx).highest()
 	} else {
-		for _, target := range account.feeTargets() {
+		for _, target := range account.feeTargets(ctx) {
 			if target.code == args.FeeTargetCode {
 				feeTarget = target
 				break
@@ -129,24 +139,91 @@ func (account *Account) pickChangeAddress(utxos map[wire.OutPoint]maketx.UTXO) (
 	return unusedAddresses[0], nil
 }
 
+// outputInfo returns the output info for sending to the given address, which can also be a silent
+// payment address.
+func (account *Account) outputInfo(address string) (*maketx.OutputInfo, error) {
+	if err := account.coin.ValidateSilentPaymentAddress(address); err == nil {
+		return maketx.NewOutputInfoSilentPayment(address), nil
+	}
+	pkScript, err := account.coin.AddressToPkScript(address)
+	if err != nil {
+		return nil, err
+	}
+	return maketx.NewOutputInfo(pkScript), nil
+}
+
+// parseSendAmount parses a positive amount given in the format unit of the account into satoshi.
+func (account *Account) parseSendAmount(amount coin.SendAmount) (int64, error) {
+	allowZero := false
+
+	unit := int64(unitSatoshi)
+	if account.coin.formatUnit == coin.BtcUnitSats {
+		unit = 1
+	}
+	parsedAmount, err := amount.Amount(big.NewInt(unit), allowZero)
+	if err != nil {
+		return 0, err
+	}
+	parsedAmountInt64, err := parsedAmount.Int64()
+	if err != nil {
+		return 0, errp.WithStack(errors.ErrInvalidAmount)
+	}
+	return parsedAmountInt64, nil
+}
+
+// batchRecipients validates the recipients of a transaction with multiple recipients. Validation
+// errors are returned as *errors.TxOutputError, identifying the invalid recipient.
+func (account *Account) batchRecipients(txRecipients []accounts.TxRecipient) ([]maketx.Recipient, error) {
+	outputError := func(index int, err error) error {
+		if validationErr, ok := errp.Cause(err).(errors.TxValidationError); ok {
+			return errp.WithStack(&errors.TxOutputError{Index: index, Err: validationErr})
+		}
+		return err
+	}
+	recipients := make([]maketx.Recipient, len(txRecipients))
+	for i, txRecipient := range txRecipients {
+		if txRecipient.Amount.SendAll() {
+			return nil, outputError(i, errors.ErrInvalidAmount)
+		}
+		outputInfo, err := account.outputInfo(txRecipient.Address)
+		if err != nil {
+			return nil, outputError(i, err)
+		}
+		amount, err := account.parseSendAmount(txRecipient.Amount)
+		if err != nil {
+			return nil, outputError(i, err)
+		}
+		recipients[i] = maketx.Recipient{OutputInfo: outputInfo, Amount: amount}
+	}
+	return recipients, nil
+}
+
 // newTx creates a new tx to the given recipient address. It also returns a set of used account
 // outputs, which contains all outputs that spent in the tx. Those are needed to be able to sign the
 // transaction. selectedUTXOs restricts the available coins; if empty, no restriction is applied and
 // all unspent coins can be used.
-func (account *Account) newTx(args *accounts.TxProposalArgs) (
+func (account *Account) newTx(ctx context.Context, args *accounts.TxProposalArgs) (
 	map[wire.OutPoint]*transactions.SpendableOutput, *maketx.TxProposal, error) {
 
 	account.log.Debug("Prepare new transaction")
 
 	var outputInfo *maketx.OutputInfo
-	if err := account.coin.ValidateSilentPaymentAddress(args.RecipientAddress); err == nil {
-		outputInfo = maketx.NewOutputInfoSilentPayment(args.RecipientAddress)
+	var recipients []maketx.Recipient
+	if len(args.Recipients) != 0 {
+		if args.PaymentRequest != nil {
+			return nil, nil, errp.New("Payment Requests do not allow multiple recipients")
+		}
+		var err error
+		recipients, err = account.batchRecipients(args.Recipients)
+		if err != nil {
+			return nil, nil, err
+		}
 	} else {
-		pkScript, err := account.coin.AddressToPkScript(args.RecipientAddress)
+		var err error
+		outputInfo, err = account.outputInfo(args.RecipientAddress)
 		if err != nil {
 			return nil, nil, err
 		}
-		outputInfo = maketx.NewOutputInfo(pkScript)
 	}
 	utxo, err := account.transactions.SpendableOutputs()
 	if err != nil {
@@ -154,11 +231,13 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (
 	}
 	wireUTXO := make(map[wire.OutPoint]maketx.UTXO, len(utxo))
 	for outPoint, txOut := range utxo {
-		// Apply coin control.
+		// Apply coin control. Coins marked as "do not spend" are only spent if explicitly selected.
 		if len(args.SelectedUTXOs) != 0 {
 			if _, ok := args.SelectedUTXOs[outPoint]; !ok {
 				continue
 			}
+		} else if account.UTXOFrozen(outPoint.String()) {
+			continue
 		}
 		wireUTXO[outPoint] = maketx.UTXO{
 			TxOut: txOut.TxOut,
@@ -166,13 +245,38 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (
 				blockchain.NewScriptHashHex(txOut.TxOut.PkScript)),
 		}
 	}
-	feeRatePerKb, err := account.getFeePerKb(args)
+	feeRatePerKb, err := account.getFeePerKb(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	coinSelection := args.CoinSelection
+	if coinSelection == "" {
+		coinSelection = account.Config().Config.CoinSelection
+	}
+	parsedCoinSelection, err := maketx.NewCoinSelection(coinSelection)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var txProposal *maketx.TxProposal
-	if args.Amount.SendAll() {
+	if len(recipients) != 0 {
+		changeAddress, err := account.pickChangeAddress(wireUTXO)
+		if err != nil {
+			return nil, nil, err
+		}
+		txProposal, err = maketx.NewTxMultipleRecipients(
+			account.coin,
+			wireUTXO,
+			recipients,
+			feeRatePerKb,
+			changeAddress,
+			parsedCoinSelection,
+			account.log,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if args.Amount.SendAll() {
 		if args.PaymentRequest != nil {
 			return nil, nil, errp.New("Payment Requests do not allow send-all transaction proposals")
 		}
@@ -187,20 +291,10 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (
 			return nil, nil, err
 		}
 	} else {
-		allowZero := false
-
-		unit := int64(unitSatoshi)
-		if account.coin.formatUnit == coin.BtcUnitSats {
-			unit = 1
-		}
-		parsedAmount, err := args.Amount.Amount(big.NewInt(unit), allowZero)
+		parsedAmountInt64, err := account.parseSendAmount(args.Amount)
 		if err != nil {
 			return nil, nil, err
 		}
-		parsedAmountInt64, err := parsedAmount.Int64()
-		if err != nil {
-			return nil, nil, errp.WithStack(errors.ErrInvalidAmount)
-		}
 		changeAddress, err := account.pickChangeAddress(wireUTXO)
 		if err != nil {
 			return nil, nil, err
@@ -213,6 +307,7 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (
 			parsedAmountInt64,
 			feeRatePerKb,
 			changeAddress,
+			parsedCoinSelection,
 			account.log,
 		)
 		if err != nil {
@@ -224,6 +319,19 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (
 			txProposal.PaymentRequest = args.PaymentRequest
 		}
 	}
+	if args.FeeTargetCode == accounts.FeeTargetCodeCustom && args.PaymentRequest == nil &&
+		isAbsurdFee(txProposal.Fee, txProposal.Amount) {
+		return nil, nil, errp.WithStack(errors.ErrFeeTooHigh)
+	}
+	if args.PayjoinURL != "" {
+		// Payjoin is optional, the recipient also accepts a normal payment.
+		if _, err := payjoin.ValidateEndpoint(args.PayjoinURL); err != nil {
+			account.log.WithError(err).Warn("Ignoring invalid payjoin endpoint")
+		} else if txProposal.SilentPaymentAddress == "" && txProposal.PaymentRequest == nil &&
+			len(recipients) == 0 {
+			txProposal.PayjoinURL = args.PayjoinURL
+		}
+	}
 	account.log.Debugf("creating tx with %d inputs, %d outputs",
 		len(txProposal.Transaction.TxIn), len(txProposal.Transaction.TxOut))
 	return utxo, txProposal, nil
@@ -243,8 +351,16 @@ func (account *Account) getAddress(scriptHashHex blockchain.ScriptHashHex) *addr
 	return nil
 }
 
+// transactionGetter returns a function fetching transactions with the given context, e.g. the
+// previous transactions of the inputs to sign.
+func (account *Account) transactionGetter(ctx context.Context) func(chainhash.Hash) (*wire.MsgTx, error) {
+	return func(txHash chainhash.Hash) (*wire.MsgTx, error) {
+		return account.Blockchain().TransactionGet(ctx, txHash)
+	}
+}
+
 // SendTx implements accounts.Interface.
-func (account *Account) SendTx(txNote string) error {
+func (account *Account) SendTx(ctx context.Context, txNote string) error {
 	unlock := account.activeTxProposalLock.RLock()
 	txProposal := account.activeTxProposal
 	unlock()
@@ -253,16 +369,27 @@ func (account *Account) SendTx(txNote string) error {
 	}
 
 	account.log.Info("Signing and sending transaction")
-	if err := account.signTransaction(txProposal, account.coin.Blockchain().TransactionGet); err != nil {
+	if err := account.signTransaction(txProposal, account.transactionGetter(ctx)); err != nil {
 		return errp.WithMessage(err, "Failed to sign transaction")
 	}
 
+	transaction := txProposal.Transaction
+	if txProposal.PayjoinURL != "" {
+		payjoinTransaction, err := account.payjoin(ctx, txProposal)
+		if err != nil {
+			// The receiver must accept the original transaction in this case.
+			account.log.WithError(err).Warn("Payjoin failed, broadcasting the original transaction")
+		} else {
+			transaction = payjoinTransaction
+		}
+	}
+
 	account.log.Info("Signed transaction is broadcasted")
-	if err := account.coin.Blockchain().TransactionBroadcast(txProposal.Transaction); err != nil {
+	if err := account.Blockchain().TransactionBroadcast(ctx, transaction); err != nil {
 		return err
 	}
 
-	if err := account.SetTxNote(txProposal.Transaction.TxHash().String(), txNote); err != nil {
+	if err := account.SetTxNote(transaction.TxHash().String(), txNote); err != nil {
 		// Not critical.
 		account.log.WithError(err).Error("Failed to save transaction note when sending a tx")
 	}
@@ -273,13 +400,14 @@ func (account *Account) SendTx(txNote string) error {
 // the UI (the output amount and the fee). At the same time, it validates the input. The proposal is
 // stored internally and can be signed and sent with SendTx().
 func (account *Account) TxProposal(
+	ctx context.Context,
 	args *accounts.TxProposalArgs,
 ) (
 	coin.Amount, coin.Amount, coin.Amount, error) {
 	defer account.activeTxProposalLock.Lock()()
 
 	account.log.Debug("Proposing transaction")
-	_, txProposal, err := account.newTx(args)
+	_, txProposal, err := account.newTx(ctx, args)
 	if err != nil {
 		return coin.Amount{}, coin.Amount{}, coin.Amount{}, err
 	}
@@ -291,3 +419,82 @@ func (account *Account) TxProposal(
 		coin.NewAmountFromInt64(int64(txProposal.Fee)),
 		coin.NewAmountFromInt64(int64(txProposal.Total())), nil
 }
+
+// TxProposalCoinSelection returns the algorithm which selected the coins of the last transaction
+// proposal. It is empty if there is no proposal or its coins were not selected by an algorithm.
+func (account *Account) TxProposalCoinSelection() maketx.CoinSelection {
+	defer account.activeTxProposalLock.RLock()()
+	if account.activeTxProposal == nil {
+		return ""
+	}
+	return account.activeTxProposal.CoinSelection
+}
+
+// BumpFee replaces an unconfirmed transaction of this account with one paying a higher fee
+// (BIP-125). The fee rate is determined the same way as for a new transaction. The replacement is
+// signed with the keystore and broadcasted. The ID of the replacement transaction is returned.
+func (account *Account) BumpFee(
+	ctx context.Context, txID string, feeTargetCode accounts.FeeTargetCode, customFee string) (string, error) {
+	txHash, err := chainhash.NewHashFromStr(txID)
+	if err != nil {
+		return "", errp.WithStack(err)
+	}
+	transaction, spentOutputs, err := account.transactions.ReplaceableTransaction(*txHash)
+	if err != nil {
+		return "", err
+	}
+	previousOutputs := make(maketx.PreviousOutputs, len(spentOutputs))
+	for outPoint, txOut := range spentOutputs {
+		previousOutputs[outPoint] = maketx.UTXO{
+			TxOut:   txOut,
+			Address: account.getAddress(blockchain.NewScriptHashHex(txOut.PkScript)),
+		}
+	}
+	var changeAddress *addresses.AccountAddress
+	for _, txOut := range transaction.TxOut {
+		scriptHashHex := blockchain.NewScriptHashHex(txOut.PkScript)
+		if account.IsChange(scriptHashHex) {
+			changeAddress = account.getAddress(scriptHashHex)
+			break
+		}
+	}
+	feePerKb, err := account.getFeePerKb(ctx, &accounts.TxProposalArgs{
+		FeeTargetCode: feeTargetCode,
+		CustomFee:     customFee,
+	})
+	if err != nil {
+		return "", err
+	}
+	minRelayFeeRate, err := account.getMinRelayFeeRate(ctx)
+	if err != nil {
+		return "", err
+	}
+	txProposal, err := maketx.NewTxBumpFee(
+		account.coin,
+		transaction,
+		previousOutputs,
+		changeAddress,
+		feePerKb,
+		minRelayFeeRate,
+		account.log,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	account.log.Info("Signing and sending replacement transaction")
+	if err := account.signTransaction(txProposal, account.transactionGetter(ctx)); err != nil {
+		return "", errp.WithMessage(err, "Failed to sign transaction")
+	}
+	if err := account.Blockchain().TransactionBroadcast(ctx, txProposal.Transaction); err != nil {
+		return "", err
+	}
+	newTxID := txProposal.Transaction.TxHash().String()
+	if note := account.TxNote(txID); note != "" {
+		if err := account.SetTxNote(newTxID, note); err != nil {
+			// Not critical.
+			account.log.WithError(err).Error("Failed to copy transaction note to the replacement")
+		}
+	}
+	return newTxID, nil
+}
@@ -39,6 +39,22 @@ type DBTxInfo struct {
 	TxHash chainhash.Hash `json:"-"`
 }
 
+// DBDroppedTxInfo contains data stored for an unconfirmed wallet transaction which disappeared
+// from the address histories, e.g. because it was evicted from the mempool or double-spent.
+type DBDroppedTxInfo struct {
+	Tx *wire.MsgTx `json:"tx"`
+	// Outputs are the outputs of the tx which belonged to the wallet, by output index. They are
+	// removed from the outputs when the tx is dropped.
+	Outputs          map[uint32]*wire.TxOut `json:"outputs"`
+	CreatedTimestamp *time.Time             `json:"created"`
+	DroppedTimestamp time.Time              `json:"dropped"`
+	// Notified is true if the user was notified about the tx being dropped.
+	Notified bool `json:"notified"`
+
+	// TxHash is the same as Tx.TxHash(). It is not serialized and stored in the database.
+	TxHash chainhash.Hash `json:"-"`
+}
+
 // DBTxInterface needs to be implemented to persist all wallet/transaction related data.
 type DBTxInterface interface {
 	// Commit closes the transaction, writing the changes.
@@ -71,6 +87,22 @@ type DBTxInterface interface {
 	// MarkTxVerified marks a tx as verified. Stores timestamp of the header this tx appears in.
 	MarkTxVerified(txHash chainhash.Hash, headerTimestamp time.Time) error
 
+	// MarkTxUnverified marks a tx as unverified, e.g. after the header it appeared in was
+	// replaced in a chain reorg.
+	MarkTxUnverified(txHash chainhash.Hash) error
+
+	// PutDroppedTx stores an unconfirmed transaction which disappeared from the address histories.
+	PutDroppedTx(txHash chainhash.Hash, droppedTx *DBDroppedTxInfo) error
+
+	// DroppedTx retrieves a dropped transaction. nil is returned if not found.
+	DroppedTx(txHash chainhash.Hash) (*DBDroppedTxInfo, error)
+
+	// DroppedTransactions retrieves all stored transaction hashes of dropped transactions.
+	DroppedTransactions() ([]chainhash.Hash, error)
+
+	// DeleteDroppedTx deletes a dropped transaction (nothing happens if not found).
+	DeleteDroppedTx(txHash chainhash.Hash)
+
 	// PutInput stores a transaction input. It is referenced by the output it spends. The
 	// transaction hash of the transaction this input was found in is recorded. TODO: store slice of
 	// inputs along with the txhash they appear in. If there are more than one, a double spend is
@@ -105,6 +137,12 @@ type DBTxInterface interface {
 	// GapLimits returns the gap limit for receive and change addresses.
 	// If none have been stored before, the default zero value is returned.
 	GapLimits() (types.GapLimits, error)
+
+	// PutLastSynced stores the time the account was last fully synced.
+	PutLastSynced(time.Time) error
+
+	// LastSynced returns the time the account was last fully synced, or nil if it was never synced.
+	LastSynced() (*time.Time, error)
 }
 
 // DBInterface can be implemented by database backends to open database transactions.
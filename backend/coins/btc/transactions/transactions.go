@@ -15,6 +15,9 @@
 package transactions
 
 import (
+	"context"
+	"time"
+
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/headers"
@@ -124,9 +127,11 @@ func (transactions *Transactions) processTxForAddress(
 	if err := transactions.notifier.Put(txHash[:]); err != nil {
 		transactions.log.WithError(err).Error("Failed notifier.Put")
 	}
+	// The tx might have been dropped before and is now back, e.g. because it was rebroadcast.
+	dbTx.DeleteDroppedTx(txHash)
 
-	// Newly confirmed tx. Try to verify it.
-	if txInfo.Height <= 0 && height > 0 {
+	// Newly confirmed tx, or confirmed in a different block. Try to verify it.
+	if height > 0 && txInfo.Height != height {
 		transactions.log.Debug("Try to verify newly confirmed tx")
 		go transactions.verifyTransaction(txHash, height)
 	}
@@ -221,6 +226,54 @@ func (transactions *Transactions) SpendableOutputs() (map[wire.OutPoint]*Spendab
 	})
 }
 
+// ReplaceableTransaction returns an unconfirmed transaction of the wallet which signals
+// replaceability (BIP-125) and whose inputs all spend outputs of the wallet, along with the outputs
+// it spends. An error is returned if the transaction is not found or cannot be replaced by us.
+func (transactions *Transactions) ReplaceableTransaction(txHash chainhash.Hash) (
+	*wire.MsgTx, map[wire.OutPoint]*wire.TxOut, error) {
+	transactions.synchronizer.WaitSynchronized()
+	type result struct {
+		tx           *wire.MsgTx
+		spentOutputs map[wire.OutPoint]*wire.TxOut
+	}
+	res, err := DBView(transactions.db, func(dbTx DBTxInterface) (*result, error) {
+		txInfo, err := dbTx.TxInfo(txHash)
+		if err != nil {
+			return nil, err
+		}
+		if txInfo == nil {
+			return nil, errp.New("transaction not found")
+		}
+		if txInfo.Height > 0 {
+			return nil, errp.New("transaction is already confirmed")
+		}
+		tx := transactions.getTransactionCached(dbTx, txHash)
+		signalsRBF := false
+		spentOutputs := map[wire.OutPoint]*wire.TxOut{}
+		for _, txIn := range tx.TxIn {
+			if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+				signalsRBF = true
+			}
+			txOut, err := dbTx.Output(txIn.PreviousOutPoint)
+			if err != nil {
+				return nil, err
+			}
+			if txOut == nil {
+				return nil, errp.New("transaction spends outputs not belonging to the wallet")
+			}
+			spentOutputs[txIn.PreviousOutPoint] = txOut
+		}
+		if !signalsRBF {
+			return nil, errp.New("transaction does not signal replaceability")
+		}
+		return &result{tx: tx, spentOutputs: spentOutputs}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.tx, res.spentOutputs, nil
+}
+
 func (transactions *Transactions) isInputSpent(dbTx DBTxInterface, outPoint wire.OutPoint) bool {
 	input, err := dbTx.Input(outPoint)
 	if err != nil {
@@ -229,8 +282,10 @@ func (transactions *Transactions) isInputSpent(dbTx DBTxInterface, outPoint wire
 	return input != nil
 }
 
+// removeTxForAddress returns the removed tx if it was unconfirmed and does not touch any of our
+// addresses anymore, i.e. if it was dropped from the mempool.
 func (transactions *Transactions) removeTxForAddress(
-	dbTx DBTxInterface, scriptHashHex blockchain.ScriptHashHex, txHash chainhash.Hash) {
+	dbTx DBTxInterface, scriptHashHex blockchain.ScriptHashHex, txHash chainhash.Hash) *DBDroppedTxInfo {
 	transactions.log.Debug("Remove transaction for address")
 	txInfo, err := dbTx.TxInfo(txHash)
 	if err != nil {
@@ -239,7 +294,7 @@ func (transactions *Transactions) removeTxForAddress(
 	if txInfo == nil {
 		// Not yet indexed.
 		transactions.log.Debug("Transaction hash not listed")
-		return
+		return nil
 	}
 
 	transactions.log.Debug("Deleting transaction address")
@@ -251,23 +306,93 @@ func (transactions *Transactions) removeTxForAddress(
 		// Tx is not touching any of our outputs anymore. Remove.
 
 		for _, txIn := range txInfo.Tx.TxIn {
+			// Keep the input if it is already indexed for a conflicting tx.
+			if conflictingTxHash := transactions.conflictingTx(dbTx, txIn, txHash); conflictingTxHash != nil {
+				continue
+			}
 			transactions.log.Debug("Deleting transaction iput")
 			dbTx.DeleteInput(txIn.PreviousOutPoint)
 		}
 
 		// Remove the outputs added by this tx.
+		ourOutputs := map[uint32]*wire.TxOut{}
 		for index := range txInfo.Tx.TxOut {
-			dbTx.DeleteOutput(wire.OutPoint{
+			outPoint := wire.OutPoint{
 				Hash:  txHash,
 				Index: uint32(index),
-			})
+			}
+			output, err := dbTx.Output(outPoint)
+			if err != nil {
+				transactions.log.WithError(err).Panic("Failed to retrieve output")
+			}
+			if output != nil {
+				ourOutputs[uint32(index)] = output
+			}
+			dbTx.DeleteOutput(outPoint)
 		}
 
 		dbTx.DeleteTx(txHash)
 		if err := transactions.notifier.Delete(txHash[:]); err != nil {
 			transactions.log.WithError(err).Error("Failed notifier.Delete")
 		}
+		if txInfo.Height <= 0 {
+			return &DBDroppedTxInfo{
+				Tx:               txInfo.Tx,
+				Outputs:          ourOutputs,
+				CreatedTimestamp: txInfo.CreatedTimestamp,
+				DroppedTimestamp: time.Now(),
+				TxHash:           txHash,
+			}
+		}
+	}
+	return nil
+}
+
+// conflictingTx returns the hash of the tx indexed as spending the output spent by the given
+// input, if it is not the given tx.
+func (transactions *Transactions) conflictingTx(
+	dbTx DBTxInterface, txIn *wire.TxIn, txHash chainhash.Hash) *chainhash.Hash {
+	spentBy, err := dbTx.Input(txIn.PreviousOutPoint)
+	if err != nil {
+		transactions.log.WithError(err).Panic("Failed to retrieve input from previous outpoint")
+	}
+	if spentBy != nil && *spentBy != txHash {
+		return spentBy
+	}
+	return nil
+}
+
+// doubleSpentBy returns the hash of a tx which spends some of the same outputs as the given tx, or
+// nil if none is known.
+func (transactions *Transactions) doubleSpentBy(
+	dbTx DBTxInterface, tx *wire.MsgTx, txHash chainhash.Hash) *chainhash.Hash {
+	for _, txIn := range tx.TxIn {
+		if conflictingTxHash := transactions.conflictingTx(dbTx, txIn, txHash); conflictingTxHash != nil {
+			return conflictingTxHash
+		}
+	}
+	return nil
+}
+
+// putDroppedTx stores a tx which was dropped from the mempool, unless it was replaced by a tx
+// spending only our own coins, i.e. if we replaced it ourselves, e.g. to bump the fee.
+func (transactions *Transactions) putDroppedTx(dbTx DBTxInterface, droppedTx *DBDroppedTxInfo) error {
+	log := transactions.log.WithField("txHash", droppedTx.TxHash.String())
+	if conflictingTxHash := transactions.doubleSpentBy(dbTx, droppedTx.Tx, droppedTx.TxHash); conflictingTxHash != nil {
+		conflictingTx, err := dbTx.TxInfo(*conflictingTxHash)
+		if err != nil {
+			return err
+		}
+		if conflictingTx.Tx != nil && transactions.allInputsOurs(dbTx, conflictingTx.Tx) {
+			log.Info("Unconfirmed transaction replaced by our own transaction")
+			return nil
+		}
+		log.WithField("conflictingTxHash", conflictingTxHash.String()).
+			Warning("Unconfirmed transaction double-spent")
+	} else {
+		log.Warning("Unconfirmed transaction dropped from the mempool")
 	}
+	return dbTx.PutDroppedTx(droppedTx.TxHash, droppedTx)
 }
 
 // UpdateAddressHistory should be called when initializing a wallet address, or when the history of
@@ -290,6 +415,7 @@ func (transactions *Transactions) UpdateAddressHistory(scriptHashHex blockchain.
 		if err != nil {
 			return err
 		}
+		droppedTxs := []*DBDroppedTxInfo{}
 		for _, entry := range previousHistory {
 			if _, txOK := txsSet[entry.TXHash.Hash()]; txOK {
 				continue
@@ -297,7 +423,9 @@ func (transactions *Transactions) UpdateAddressHistory(scriptHashHex blockchain.
 			// A tx was previously in the address history but is not anymore.  If the tx was already
 			// downloaded and indexed, it will be removed.  If it is currently downloading (enqueued for
 			// indexing), it will not be processed.
-			transactions.removeTxForAddress(dbTx, scriptHashHex, entry.TXHash.Hash())
+			if droppedTx := transactions.removeTxForAddress(dbTx, scriptHashHex, entry.TXHash.Hash()); droppedTx != nil {
+				droppedTxs = append(droppedTxs, droppedTx)
+			}
 		}
 
 		if err := dbTx.PutAddressHistory(scriptHashHex, txs); err != nil {
@@ -309,6 +437,12 @@ func (transactions *Transactions) UpdateAddressHistory(scriptHashHex blockchain.
 			tx := transactions.getTransactionCached(dbTx, txHash)
 			transactions.processTxForAddress(dbTx, scriptHashHex, txHash, tx, height)
 		}
+		// Stored after processing the new history, so that a tx replacing a dropped tx is known.
+		for _, droppedTx := range droppedTxs {
+			if err := transactions.putDroppedTx(dbTx, droppedTx); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -328,7 +462,7 @@ func (transactions *Transactions) getTransactionCached(
 	if txInfo.Tx != nil {
 		return txInfo.Tx
 	}
-	tx, err := transactions.blockchain.TransactionGet(txHash)
+	tx, err := transactions.blockchain.TransactionGet(context.Background(), txHash)
 	if err != nil {
 		transactions.log.WithError(err).Panic("TransactionGet failed")
 	}
@@ -381,14 +515,19 @@ func (transactions *Transactions) txInfo(
 	var sumOurInputs btcutil.Amount
 	var result btcutil.Amount
 	allInputsOurs := true
-	for _, txIn := range txInfo.Tx.TxIn {
+	inputs := make([]accounts.TxInput, len(txInfo.Tx.TxIn))
+	for index, txIn := range txInfo.Tx.TxIn {
 		spentOut, err := dbTx.Output(txIn.PreviousOutPoint)
 		if err != nil {
 			// TODO
 			transactions.log.WithError(err).Panic("Output() failed")
 		}
+		inputs[index].OutPoint = txIn.PreviousOutPoint.String()
 		if spentOut != nil {
 			sumOurInputs += btcutil.Amount(spentOut.Value)
+			inputs[index].Address = transactions.outputToAddress(spentOut.PkScript)
+			inputs[index].Amount = coin.NewAmountFromInt64(spentOut.Value)
+			inputs[index].Ours = true
 		} else {
 			allInputsOurs = false
 		}
@@ -396,6 +535,7 @@ func (transactions *Transactions) txInfo(
 	var sumAllOutputs, sumOurReceive, sumOurChange btcutil.Amount
 	receiveAddresses := []accounts.AddressAndAmount{}
 	sendAddresses := []accounts.AddressAndAmount{}
+	outputs := make([]accounts.AddressAndAmount, len(txInfo.Tx.TxOut))
 	allOutputsOurs := true
 	for index, txOut := range txInfo.Tx.TxOut {
 		sumAllOutputs += btcutil.Amount(txOut.Value)
@@ -412,6 +552,7 @@ func (transactions *Transactions) txInfo(
 			Amount:  coin.NewAmountFromInt64(txOut.Value),
 			Ours:    output != nil,
 		}
+		outputs[index] = addressAndAmount
 		if output != nil {
 			receiveAddresses = append(receiveAddresses, addressAndAmount)
 			if isChange(getScriptHashHex(output)) {
@@ -469,9 +610,12 @@ func (transactions *Transactions) txInfo(
 		numConfirmations = transactions.headersTipHeight - txInfo.Height + 1
 	}
 
+	// The confirmations reported by the server are only trusted once the tx is verified.
+	unverified := txInfo.Height > 0 && (txInfo.Verified == nil || !*txInfo.Verified)
+
 	const numConfirmationsComplete = 6
 	status := accounts.TxStatusPending
-	if numConfirmations >= numConfirmationsComplete {
+	if numConfirmations >= numConfirmationsComplete && !unverified {
 		status = accounts.TxStatusComplete
 	}
 	return &accounts.TransactionData{
@@ -492,6 +636,9 @@ func (transactions *Transactions) txInfo(
 		Size:             int64(txInfo.Tx.SerializeSize()),
 		Weight:           btcdBlockchain.GetTransactionWeight(btcutilTx),
 		CreatedTimestamp: txInfo.CreatedTimestamp,
+		Unverified:       unverified,
+		Inputs:           inputs,
+		Outputs:          outputs,
 		IsErc20:          false,
 	}
 }
@@ -513,6 +660,82 @@ func (transactions *Transactions) Transactions(
 			}
 			txs = append(txs, transactions.txInfo(dbTx, txInfo, isChange))
 		}
+		droppedTxHashes, err := dbTx.DroppedTransactions()
+		if err != nil {
+			return nil, err
+		}
+		for _, txHash := range droppedTxHashes {
+			droppedTx, err := dbTx.DroppedTx(txHash)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, transactions.droppedTxInfo(dbTx, droppedTx, isChange))
+		}
 		return accounts.NewOrderedTransactions(txs), nil
 	})
 }
+
+// droppedTxView serves the outputs of a dropped tx, which were removed from the database.
+type droppedTxView struct {
+	DBTxInterface
+	droppedTx *DBDroppedTxInfo
+}
+
+// Output implements DBTxInterface.
+func (view droppedTxView) Output(outPoint wire.OutPoint) (*wire.TxOut, error) {
+	if outPoint.Hash == view.droppedTx.TxHash {
+		return view.droppedTx.Outputs[outPoint.Index], nil
+	}
+	return view.DBTxInterface.Output(outPoint)
+}
+
+func (transactions *Transactions) droppedTxInfo(
+	dbTx DBTxInterface,
+	droppedTx *DBDroppedTxInfo,
+	isChange func(blockchain.ScriptHashHex) bool) *accounts.TransactionData {
+	txData := transactions.txInfo(
+		droppedTxView{DBTxInterface: dbTx, droppedTx: droppedTx},
+		&DBTxInfo{
+			Tx:               droppedTx.Tx,
+			CreatedTimestamp: droppedTx.CreatedTimestamp,
+			TxHash:           droppedTx.TxHash,
+		},
+		isChange)
+	txData.Status = accounts.TxStatusDropped
+	if conflictingTxHash := transactions.doubleSpentBy(dbTx, droppedTx.Tx, droppedTx.TxHash); conflictingTxHash != nil {
+		txData.Status = accounts.TxStatusDoubleSpent
+		txData.ConflictingTxID = conflictingTxHash.String()
+	}
+	return txData
+}
+
+// MarkDroppedTransactionsNotified marks all dropped transactions as notified and returns the
+// hashes of those which were not notified before.
+func (transactions *Transactions) MarkDroppedTransactionsNotified() ([]chainhash.Hash, error) {
+	result := []chainhash.Hash{}
+	err := DBUpdate(transactions.db, func(dbTx DBTxInterface) error {
+		txHashes, err := dbTx.DroppedTransactions()
+		if err != nil {
+			return err
+		}
+		for _, txHash := range txHashes {
+			droppedTx, err := dbTx.DroppedTx(txHash)
+			if err != nil {
+				return err
+			}
+			if droppedTx.Notified {
+				continue
+			}
+			droppedTx.Notified = true
+			if err := dbTx.PutDroppedTx(txHash, droppedTx); err != nil {
+				return err
+			}
+			result = append(result, txHash)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
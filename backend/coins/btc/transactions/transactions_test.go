@@ -15,6 +15,7 @@
 package transactions_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -65,7 +66,7 @@ func (blockchain *BlockchainMock) RegisterTxs(txs ...*wire.MsgTx) {
 
 // TransactionGet by default automatically calls the callback which processes the tx. Overwrite
 // default behavior by setting the TransactionGetFunc var.
-func (blockchain *BlockchainMock) TransactionGet(txHash chainhash.Hash) (*wire.MsgTx, error) {
+func (blockchain *BlockchainMock) TransactionGet(_ context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
 	tx, ok := blockchain.transactions[txHash]
 	if !ok {
 		panic("you need to first register the transaction with the mock backend")
@@ -187,6 +188,13 @@ func (s *transactionsSuite) TestUpdateAddressHistorySingleTxReceive() {
 	s.Require().NoError(err)
 	s.Require().Len(transactions, 1)
 	s.Require().Equal(expectedHeight, transactions[0].Height)
+	s.Require().Equal([]accounts.TxInput{{OutPoint: tx1.TxIn[0].PreviousOutPoint.String()}},
+		transactions[0].Inputs)
+	s.Require().Equal([]accounts.AddressAndAmount{{
+		Address: address.EncodeForHumans(),
+		Amount:  coin.NewAmountFromInt64(int64(expectedAmount)),
+		Ours:    true,
+	}}, transactions[0].Outputs)
 }
 
 // TestSpendableOutputs checks that the utxo set is correct. Only confirmed (or unconfirmed outputs
@@ -370,3 +378,93 @@ func (s *transactionsSuite) TestRemoveTransaction() {
 	s.Require().NoError(err)
 	s.Require().Len(transactions, 2)
 }
+
+// TestDroppedTransactions checks that unconfirmed txs which disappear from the address histories
+// are kept as dropped or double-spent, unless they were replaced by our own tx.
+func (s *transactionsSuite) TestDroppedTransactions() {
+	addresses, err := s.addressChain.EnsureAddresses()
+	s.Require().NoError(err)
+	address1 := addresses[0]
+	address2 := addresses[1]
+	isChange := func(blockchainpkg.ScriptHashHex) bool { return false }
+	findTx := func(txHash chainhash.Hash) *accounts.TransactionData {
+		transactions, err := s.transactions.Transactions(isChange)
+		s.Require().NoError(err)
+		for _, tx := range transactions {
+			if tx.TxID == txHash.String() {
+				return tx
+			}
+		}
+		return nil
+	}
+
+	tx1 := newTx(chainhash.HashH(nil), 0, address1, 1000)
+	tx2 := newTx(chainhash.HashH(nil), 1, address2, 50)
+	s.blockchainMock.RegisterTxs(tx1, tx2)
+	s.headersMock.On("VerifiedHeaderByHeight", 10).Return(nil, nil).Once()
+	s.updateAddressHistory(address1, []*blockchainpkg.TxInfo{
+		{TXHash: blockchainpkg.TXHash(tx1.TxHash()), Height: 10},
+	})
+	s.updateAddressHistory(address2, []*blockchainpkg.TxInfo{
+		{TXHash: blockchainpkg.TXHash(tx2.TxHash()), Height: 0},
+	})
+	balance, err := s.transactions.Balance()
+	s.Require().NoError(err)
+	s.Require().Equal(newBalance(1000, 50), balance)
+
+	// The incoming tx is evicted from the mempool.
+	tx2Hash := tx2.TxHash()
+	s.notifierMock.On("Delete", tx2Hash[:]).Return(nil)
+	s.updateAddressHistory(address2, []*blockchainpkg.TxInfo{})
+	balance, err = s.transactions.Balance()
+	s.Require().NoError(err)
+	s.Require().Equal(newBalance(1000, 0), balance)
+	dropped := findTx(tx2Hash)
+	s.Require().NotNil(dropped)
+	s.Require().Equal(accounts.TxStatusDropped, dropped.Status)
+	s.Require().Equal(accounts.TxTypeReceive, dropped.Type)
+	s.Require().Equal(coin.NewAmountFromInt64(50), dropped.Amount)
+	s.Require().Empty(dropped.ConflictingTxID)
+	notified, err := s.transactions.MarkDroppedTransactionsNotified()
+	s.Require().NoError(err)
+	s.Require().Equal([]chainhash.Hash{tx2Hash}, notified)
+	notified, err = s.transactions.MarkDroppedTransactionsNotified()
+	s.Require().NoError(err)
+	s.Require().Empty(notified)
+
+	// It is rebroadcast and shows up again.
+	s.updateAddressHistory(address2, []*blockchainpkg.TxInfo{
+		{TXHash: blockchainpkg.TXHash(tx2Hash), Height: 0},
+	})
+	s.Require().Equal(accounts.TxStatusPending, findTx(tx2Hash).Status)
+
+	// The sender double-spends it.
+	tx3 := newTx(chainhash.HashH(nil), 1, address2, 30)
+	s.blockchainMock.RegisterTxs(tx3)
+	s.updateAddressHistory(address2, []*blockchainpkg.TxInfo{
+		{TXHash: blockchainpkg.TXHash(tx3.TxHash()), Height: 0},
+	})
+	doubleSpent := findTx(tx2Hash)
+	s.Require().Equal(accounts.TxStatusDoubleSpent, doubleSpent.Status)
+	s.Require().Equal(tx3.TxHash().String(), doubleSpent.ConflictingTxID)
+	balance, err = s.transactions.Balance()
+	s.Require().NoError(err)
+	s.Require().Equal(newBalance(1000, 30), balance)
+
+	// We replace our own unconfirmed tx, e.g. to bump the fee. The replaced tx is not kept.
+	tx4 := newTx(tx1.TxHash(), 0, address2, 900)
+	tx5 := newTx(tx1.TxHash(), 0, address2, 800)
+	s.blockchainMock.RegisterTxs(tx4, tx5)
+	s.updateAddressHistory(address1, []*blockchainpkg.TxInfo{
+		{TXHash: blockchainpkg.TXHash(tx1.TxHash()), Height: 10},
+		{TXHash: blockchainpkg.TXHash(tx4.TxHash()), Height: 0},
+	})
+	tx4Hash := tx4.TxHash()
+	s.notifierMock.On("Delete", tx4Hash[:]).Return(nil).Once()
+	s.updateAddressHistory(address1, []*blockchainpkg.TxInfo{
+		{TXHash: blockchainpkg.TXHash(tx1.TxHash()), Height: 10},
+		{TXHash: blockchainpkg.TXHash(tx5.TxHash()), Height: 0},
+	})
+	s.Require().Nil(findTx(tx4Hash))
+	s.Require().Equal(accounts.TxStatusPending, findTx(tx5.TxHash()).Status)
+}
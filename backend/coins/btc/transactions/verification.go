@@ -15,8 +15,10 @@
 package transactions
 
 import (
+	"context"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/blockchain"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/headers"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
@@ -28,7 +30,43 @@ func (transactions *Transactions) onHeadersEvent(event headers.Event) {
 		done := transactions.synchronizer.IncRequestsCounter()
 		transactions.headersTipHeight = transactions.headers.TipHeight()
 		done()
+	case headers.EventReorg:
+		if reorg := transactions.headers.LastReorg(); reorg != nil {
+			transactions.onReorg(reorg)
+		}
+	}
+}
+
+// onReorg marks all transactions confirmed in the replaced headers as unverified and verifies them
+// again against the new chain.
+func (transactions *Transactions) onReorg(reorg *headers.Reorg) {
+	done := transactions.synchronizer.IncRequestsCounter()
+	defer done()
+	err := DBUpdate(transactions.db, func(dbTx DBTxInterface) error {
+		txHashes, err := dbTx.Transactions()
+		if err != nil {
+			return err
+		}
+		for _, txHash := range txHashes {
+			txInfo, err := dbTx.TxInfo(txHash)
+			if err != nil {
+				return err
+			}
+			if txInfo.Height < reorg.Height {
+				continue
+			}
+			if err := dbTx.MarkTxUnverified(txHash); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		transactions.log.WithError(err).Error("MarkTxUnverified")
+		return
 	}
+	transactions.headersTipHeight = transactions.headers.TipHeight()
+	transactions.verifyTransactions()
 }
 
 func (transactions *Transactions) unverifiedTransactions() (map[chainhash.Hash]int, error) {
@@ -73,6 +111,7 @@ func (transactions *Transactions) verifyTransactions() {
 }
 
 func (transactions *Transactions) verifyTransaction(txHash chainhash.Hash, height int) {
+	defer crashreport.Recover()
 	if height <= 0 {
 		return
 	}
@@ -90,7 +129,7 @@ func (transactions *Transactions) verifyTransaction(txHash chainhash.Hash, heigh
 	done := transactions.synchronizer.IncRequestsCounter()
 	defer done()
 
-	merkle, err := transactions.blockchain.GetMerkle(txHash, height)
+	merkle, err := transactions.blockchain.GetMerkle(context.Background(), txHash, height)
 	if err != nil {
 		// TODO
 		transactions.log.WithError(err).Error("GetMerkle")
@@ -70,6 +70,12 @@ func AddressFromPkScript(pkScript []byte, net *chaincfg.Params) (btcutil.Address
 	return addresses[0], nil
 }
 
+// VirtualSize returns the virtual size of the transaction in vbytes, see BIP-141.
+func VirtualSize(transaction *wire.MsgTx) int {
+	weight := transaction.SerializeSizeStripped()*3 + transaction.SerializeSize()
+	return (weight + 3) / 4
+}
+
 // FormatBtcAsSat returns true if the btcUnit param is `[t]sat`.
 func FormatBtcAsSat(btcUnit coin.BtcUnit) bool {
 	return btcUnit == coin.BtcUnitSats
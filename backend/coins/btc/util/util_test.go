@@ -21,6 +21,7 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/ltc"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/stretchr/testify/require"
 )
 
@@ -133,3 +134,12 @@ func TestAddressFromPkScript(t *testing.T) {
 	_, err = AddressFromPkScript(pkScript, &ltc.MainNetParams)
 	require.Error(t, err)
 }
+
+func TestVirtualSize(t *testing.T) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{})
+	tx.AddTxOut(wire.NewTxOut(1000, make([]byte, 22)))
+	require.Equal(t, 82, VirtualSize(tx))
+	tx.TxIn[0].Witness = wire.TxWitness{make([]byte, 72), make([]byte, 33)}
+	require.Equal(t, 110, VirtualSize(tx))
+}
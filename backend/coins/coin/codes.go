@@ -22,6 +22,8 @@ const (
 	CodeBTC Code = "btc"
 	// CodeTBTC is Bitcoin Testnet.
 	CodeTBTC Code = "tbtc"
+	// CodeTSIG is Bitcoin Signet.
+	CodeTSIG Code = "tsig"
 	// CodeRBTC is Bitcoin Regtest.
 	CodeRBTC Code = "rbtc"
 	// CodeLTC is Litecoin.
@@ -50,6 +52,7 @@ const (
 // TestnetCoins is the subset of all coins which are available in testnet mode.
 var TestnetCoins = map[Code]struct{}{
 	CodeTBTC:   {},
+	CodeTSIG:   {},
 	CodeTLTC:   {},
 	CodeSEPETH: {},
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/erc20"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/etherscan"
 	ethtypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
@@ -220,8 +221,33 @@ func (account *Account) Initialize() error {
 	return account.BaseAccount.Initialize(accountIdentifier)
 }
 
+// nextPoll returns a channel which fires when the account should be polled next, given the time
+// of the last poll. The interval is throttled by the sync scheduler. nil is returned if background
+// polling is paused.
+func (account *Account) nextPoll(lastPoll time.Time) <-chan time.Time {
+	interval := pollInterval
+	if scheduler := account.Config().SyncScheduler; scheduler != nil {
+		interval = scheduler.Interval(pollInterval)
+	}
+	if interval == 0 {
+		return nil
+	}
+	return time.After(time.Until(lastPoll.Add(interval)))
+}
+
+// scheduleChanged returns a channel which is closed when the sync schedule changes. nil if there
+// is no sync scheduler.
+func (account *Account) scheduleChanged() <-chan struct{} {
+	if scheduler := account.Config().SyncScheduler; scheduler != nil {
+		return scheduler.Changed()
+	}
+	return nil
+}
+
 func (account *Account) poll(initDone func()) {
+	defer crashreport.Recover()
 	timer := time.After(0)
+	var lastPoll time.Time
 	for {
 		select {
 		case <-account.quitChan:
@@ -233,6 +259,11 @@ func (account *Account) poll(initDone func()) {
 			case <-timer:
 			case <-account.enqueueUpdateCh:
 				account.log.Info("extraordinary account update invoked")
+			case <-account.scheduleChanged():
+				if !lastPoll.IsZero() {
+					timer = account.nextPoll(lastPoll)
+				}
+				continue
 			}
 			if err := account.update(); err != nil {
 				account.log.WithError(err).Error("error updating account")
@@ -244,7 +275,8 @@ func (account *Account) poll(initDone func()) {
 				initDone()
 				initDone = nil
 			}
-			timer = time.After(pollInterval)
+			lastPoll = time.Now()
+			timer = account.nextPoll(lastPoll)
 		}
 	}
 }
@@ -253,6 +285,7 @@ func (account *Account) poll(initDone func()) {
 // We update heights for tx with up to 12 confirmations, so re-orgs are taken into account.
 // tipHeight is the current blockchain height.
 func (account *Account) updateOutgoingTransactions(tipHeight uint64) {
+	defer crashreport.Recover()
 	defer account.Synchronizer.IncRequestsCounter()()
 
 	dbTx, err := account.db.Begin()
@@ -311,7 +344,9 @@ func (account *Account) updateOutgoingTransactions(tipHeight uint64) {
 }
 
 // outgoingTransactions gets all locally stored outgoing transactions. It filters out the ones also
-// present from the transactions source.
+// present from the transactions source. Stored pending transactions whose nonce was used by a
+// confirmed transaction, e.g. because they were replaced by a speed up or cancel transaction, are
+// removed from the db.
 func (account *Account) outgoingTransactions(allTxs []*accounts.TransactionData) (
 	[]*ethtypes.TransactionWithMetadata, error) {
 	dbTx, err := account.db.Begin()
@@ -325,8 +360,12 @@ func (account *Account) outgoingTransactions(allTxs []*accounts.TransactionData)
 	}
 
 	allTxHashes := map[string]struct{}{}
+	usedNonces := map[uint64]struct{}{}
 	for _, tx := range allTxs {
 		allTxHashes[tx.TxID] = struct{}{}
+		if tx.Type != accounts.TxTypeReceive && tx.Nonce != nil {
+			usedNonces[*tx.Nonce] = struct{}{}
+		}
 	}
 
 	transactions := []*ethtypes.TransactionWithMetadata{}
@@ -335,8 +374,18 @@ func (account *Account) outgoingTransactions(allTxs []*accounts.TransactionData)
 		if _, ok := allTxHashes[tx.TxID()]; ok {
 			continue
 		}
+		if _, ok := usedNonces[tx.Transaction.Nonce()]; ok && tx.Height == 0 {
+			account.log.Infof("removing replaced outgoing tx with nonce: %d", tx.Transaction.Nonce())
+			if err := dbTx.DeleteOutgoingTransaction(tx.Transaction.Hash()); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		transactions = append(transactions, tx)
 	}
+	if err := dbTx.Commit(); err != nil {
+		return nil, err
+	}
 	return transactions, nil
 }
 
@@ -382,9 +431,9 @@ func (account *Account) update() error {
 	account.nextNonce = nodeNonce
 
 	// In case the nodeNonce is not up to date, we fall back to our stored last nonce to compute the
-	// next nonce.
+	// next nonce. The outgoing transactions are sorted descending by nonce.
 	if len(outgoingTransactions) > 0 {
-		localNonce := outgoingTransactions[len(outgoingTransactions)-1].Transaction.Nonce() + 1
+		localNonce := outgoingTransactions[0].Transaction.Nonce() + 1
 		if localNonce > account.nextNonce {
 			account.nextNonce = localNonce
 		}
@@ -501,13 +550,16 @@ type TxProposal struct {
 	RecipientAddress string
 }
 
-func (account *Account) newTx(args *accounts.TxProposalArgs) (*TxProposal, error) {
+func (account *Account) newTx(ctx context.Context, args *accounts.TxProposalArgs) (*TxProposal, error) {
+	if len(args.Recipients) != 0 {
+		return nil, errp.New("Transactions with multiple recipients are not supported")
+	}
 	if !IsValidEthAddress(args.RecipientAddress) {
 		return nil, errp.WithStack(errors.ErrInvalidAddress)
 	}
 	address := ethcommon.HexToAddress(args.RecipientAddress)
 
-	suggestedGasFeeCap, suggestedGasTipCap, err := account.gasFees(args)
+	suggestedGasFeeCap, suggestedGasTipCap, err := account.gasFees(ctx, args)
 	if err != nil {
 		if _, ok := errp.Cause(err).(errors.TxValidationError); ok {
 			return nil, err
@@ -575,7 +627,7 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (*TxProposal, error
 			}
 		}
 	}
-	gasLimit, err := account.coin.client.EstimateGas(context.TODO(), message)
+	gasLimit, err := account.coin.client.EstimateGas(ctx, message)
 	if err != nil {
 		if strings.Contains(err.Error(), etherscan.ERC20GasErr) {
 			return nil, errp.WithStack(errors.ErrInsufficientFunds)
@@ -618,7 +670,8 @@ func (account *Account) newTx(args *accounts.TxProposalArgs) (*TxProposal, error
 
 	var tx *types.Transaction
 
-	if keystore.SupportsEIP1559() {
+	// Chains without EIP-1559 only accept legacy transactions.
+	if keystore.SupportsEIP1559() && account.coin.net.IsLondon(account.blockNumber) {
 		txData := &types.DynamicFeeTx{
 			Nonce:     account.nextNonce,
 			GasTipCap: suggestedGasTipCap,
@@ -675,7 +728,7 @@ func (account *Account) storePendingOutgoingTransaction(transaction *types.Trans
 }
 
 // SendTx implements accounts.Interface.
-func (account *Account) SendTx(txNote string) error {
+func (account *Account) SendTx(ctx context.Context, txNote string) error {
 	unlock := account.updateLock.RLock()
 	txProposal := account.activeTxProposal
 	unlock()
@@ -695,7 +748,7 @@ func (account *Account) SendTx(txNote string) error {
 	// By experience, at least with the Etherscan backend, this can succeed and still the
 	// transaction will be lost (not in any block explorer, the node does not know about it, etc.).
 	// We do an attempt here and more attempts if needed in `updateOutgoingTransactions()`.
-	if err := account.coin.client.SendTransaction(context.TODO(), txProposal.Tx); err != nil {
+	if err := account.coin.client.SendTransaction(ctx, txProposal.Tx); err != nil {
 		return errp.WithStack(err)
 	}
 	if err := account.storePendingOutgoingTransaction(txProposal.Tx); err != nil {
@@ -712,15 +765,28 @@ func (account *Account) SendTx(txNote string) error {
 
 // feeTargets returns three priorities with fee targets estimated by Etherscan
 // https://docs.etherscan.io/api-endpoints/gas-tracker#get-gas-oracle
-// If the service should not be reachable, we fallback to only one priority, estimated by
-// the ETH RPC eth_gasPrice endpoint.
-func (account *Account) feeTargets() []*ethtypes.FeeTarget {
-	etherscanFeeTargets, err := account.coin.client.FeeTargets(context.TODO())
+// If the service should not be reachable, the three priorities are estimated from the fee history
+// of recent blocks (eth_feeHistory). If that fails too, e.g. because the chain does not support
+// EIP-1559, we fallback to only one priority, estimated by the ETH RPC eth_gasPrice endpoint.
+func (account *Account) feeTargets(ctx context.Context) []*ethtypes.FeeTarget {
+	etherscanFeeTargets, err := account.coin.client.FeeTargets(ctx)
 	if err == nil {
 		return etherscanFeeTargets
 	}
-	account.log.WithError(err).Error("Could not get fee targets from eth gas station, falling back to RPC eth_gasPrice")
-	suggestedGasPrice, err := account.coin.client.SuggestGasPrice(context.TODO())
+	account.log.WithError(err).Error("Could not get fee targets from eth gas station, falling back to RPC eth_feeHistory")
+	feeHistory, err := account.coin.client.FeeHistory(
+		ctx, feeHistoryBlocks, nil, feeHistoryPercentiles)
+	if err == nil {
+		feeHistoryTargets, err := feeTargetsFromFeeHistory(feeHistory)
+		if err == nil {
+			return feeHistoryTargets
+		}
+		account.log.WithError(err).Error("Could not estimate fee targets from the fee history")
+	} else {
+		account.log.WithError(err).Error("Could not get the fee history")
+	}
+	account.log.Info("Falling back to RPC eth_gasPrice")
+	suggestedGasPrice, err := account.coin.client.SuggestGasPrice(ctx)
 	if err != nil {
 		account.log.WithError(err).Error("Fallback to RPC eth_gasPrice failed")
 		return nil
@@ -735,9 +801,9 @@ func (account *Account) feeTargets() []*ethtypes.FeeTarget {
 }
 
 // FeeTargets implements accounts.Interface.
-func (account *Account) FeeTargets() ([]accounts.FeeTarget, accounts.FeeTargetCode) {
+func (account *Account) FeeTargets(ctx context.Context) ([]accounts.FeeTarget, accounts.FeeTargetCode) {
 	feeTargets := []accounts.FeeTarget{}
-	for _, t := range account.feeTargets() {
+	for _, t := range account.feeTargets(ctx) {
 		feeTargets = append(feeTargets, t)
 	}
 	return feeTargets, accounts.DefaultFeeTarget
@@ -746,7 +812,7 @@ func (account *Account) FeeTargets() ([]accounts.FeeTarget, accounts.FeeTargetCo
 // gasFees returns the currently suggested maxFeePerGas and maxPriorityFee for the given fee target, or a custom fee
 // if the fee target is `FeeTargetCodeCustom`. The custom fee sets both maxFeePerGas and maxPriorityFee to the same value.
 // TODO: The UI should have and advanced setting to allow the user to set maxFeePerGas and maxPriorityFee separately.
-func (account *Account) gasFees(args *accounts.TxProposalArgs) (*big.Int, *big.Int, error) {
+func (account *Account) gasFees(ctx context.Context, args *accounts.TxProposalArgs) (*big.Int, *big.Int, error) {
 	if args.FeeTargetCode == accounts.FeeTargetCodeCustom {
 		// Convert from Gwei to Wei.
 		amount, err := coin.NewAmountFromString(args.CustomFee, big.NewInt(1e9))
@@ -759,7 +825,7 @@ func (account *Account) gasFees(args *accounts.TxProposalArgs) (*big.Int, *big.I
 		}
 		return gasPrice, gasPrice, nil
 	}
-	for _, t := range account.feeTargets() {
+	for _, t := range account.feeTargets(ctx) {
 		if t.TargetCode == args.FeeTargetCode {
 			if t.GasTipCap.Cmp(big.NewInt(0)) <= 0 || t.GasFeeCap.Cmp(big.NewInt(0)) <= 0 {
 				return nil, nil, errors.ErrFeeTooLow
@@ -772,10 +838,11 @@ func (account *Account) gasFees(args *accounts.TxProposalArgs) (*big.Int, *big.I
 
 // TxProposal implements accounts.Interface.
 func (account *Account) TxProposal(
+	ctx context.Context,
 	args *accounts.TxProposalArgs,
 ) (coin.Amount, coin.Amount, coin.Amount, error) {
 	defer account.updateLock.Lock()()
-	txProposal, err := account.newTx(args)
+	txProposal, err := account.newTx(ctx, args)
 	if err != nil {
 		return coin.Amount{}, coin.Amount{}, coin.Amount{}, err
 	}
@@ -879,6 +946,7 @@ type WalletConnectArgs struct {
 
 // EthSignWalletConnectTx signs an Ethereum Tx received from WalletConnect.
 func (account *Account) EthSignWalletConnectTx(
+	ctx context.Context,
 	// send: whether transaction should be broadcast after signing
 	send bool,
 	// chainId: allow specifying other IDs than 1 (ETH mainnet) for other EVM networks
@@ -933,7 +1001,7 @@ func (account *Account) EthSignWalletConnectTx(
 		Data:     data,
 	}
 
-	gasLimit, err := account.coin.client.EstimateGas(context.TODO(), message)
+	gasLimit, err := account.coin.client.EstimateGas(ctx, message)
 	if err != nil {
 		if strings.Contains(err.Error(), etherscan.ERC20GasErr) {
 			return "", "", errp.WithStack(errors.ErrInsufficientFunds)
@@ -942,7 +1010,7 @@ func (account *Account) EthSignWalletConnectTx(
 		return "", "", errp.WithStack(errors.TxValidationError(err.Error()))
 	}
 
-	for _, t := range account.feeTargets() {
+	for _, t := range account.feeTargets(ctx) {
 		// TODO Let user choose gas price/priority
 		if t.TargetCode == accounts.FeeTargetCodeNormal {
 			if t.GasFeeCap.Cmp(big.NewInt(0)) <= 0 {
@@ -978,7 +1046,7 @@ func (account *Account) EthSignWalletConnectTx(
 	}
 	txHash := signedTx.Hash()
 	if send {
-		if err := account.coin.client.SendTransaction(context.TODO(), signedTx); err != nil {
+		if err := account.coin.client.SendTransaction(ctx, signedTx); err != nil {
 			return "", "", errp.WithStack(err)
 		}
 	}
@@ -118,7 +118,7 @@ func TestTxProposal(t *testing.T) {
 	acct.Synchronizer.WaitSynchronized()
 
 	t.Run("valid", func(t *testing.T) {
-		value, fee, total, err := acct.TxProposal(&accounts.TxProposalArgs{
+		value, fee, total, err := acct.TxProposal(context.Background(), &accounts.TxProposalArgs{
 			RecipientAddress: "0xa29163852021BF4C139D03Dff59ae763AC73e84e",
 			Amount:           coin.NewSendAmount("0.1"),
 			FeeTargetCode:    accounts.FeeTargetCodeCustom,
@@ -130,7 +130,7 @@ func TestTxProposal(t *testing.T) {
 		require.Equal(t, coin.NewAmountFromInt64(100420000000000000), total)
 	})
 	t.Run("valid-address-lowercase", func(t *testing.T) {
-		_, _, _, err := acct.TxProposal(&accounts.TxProposalArgs{
+		_, _, _, err := acct.TxProposal(context.Background(), &accounts.TxProposalArgs{
 			RecipientAddress: "0xa29163852021bf4c139d03dff59ae763ac73e84e",
 			Amount:           coin.NewSendAmount("0.1"),
 			FeeTargetCode:    accounts.FeeTargetCodeCustom,
@@ -139,7 +139,7 @@ func TestTxProposal(t *testing.T) {
 		require.NoError(t, err)
 	})
 	t.Run("valid-address-uppercase", func(t *testing.T) {
-		_, _, _, err := acct.TxProposal(&accounts.TxProposalArgs{
+		_, _, _, err := acct.TxProposal(context.Background(), &accounts.TxProposalArgs{
 			RecipientAddress: "0XA29163852021BF4C139D03DFF59AE763AC73E84E",
 			Amount:           coin.NewSendAmount("0.1"),
 			FeeTargetCode:    accounts.FeeTargetCodeCustom,
@@ -149,7 +149,7 @@ func TestTxProposal(t *testing.T) {
 	})
 	t.Run("invalid-address-checksum", func(t *testing.T) {
 		// EIP-55 checksum wrong
-		_, _, _, err := acct.TxProposal(&accounts.TxProposalArgs{
+		_, _, _, err := acct.TxProposal(context.Background(), &accounts.TxProposalArgs{
 			RecipientAddress: "0xA29163852021BF4C139D03Dff59ae763AC73e84e",
 			Amount:           coin.NewSendAmount("0.1"),
 			FeeTargetCode:    accounts.FeeTargetCodeCustom,
@@ -159,7 +159,7 @@ func TestTxProposal(t *testing.T) {
 	})
 
 	t.Run("invalid-address", func(t *testing.T) {
-		_, _, _, err := acct.TxProposal(&accounts.TxProposalArgs{
+		_, _, _, err := acct.TxProposal(context.Background(), &accounts.TxProposalArgs{
 			RecipientAddress: "0xa29163852021BF4C1",
 			Amount:           coin.NewSendAmount("0.1"),
 			FeeTargetCode:    accounts.FeeTargetCodeCustom,
@@ -22,6 +22,7 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/jsonp"
+	"github.com/ethereum/go-ethereum/common"
 	"go.etcd.io/bbolt"
 )
 
@@ -89,6 +90,11 @@ func (tx *Tx) PutOutgoingTransaction(transaction *types.TransactionWithMetadata)
 		jsonp.MustMarshal(transaction))
 }
 
+// DeleteOutgoingTransaction implements DBTxInterface.
+func (tx *Tx) DeleteOutgoingTransaction(txHash common.Hash) error {
+	return errp.WithStack(tx.bucketOutgoingTransactions.Delete(txHash.Bytes()))
+}
+
 type byNonce []*types.TransactionWithMetadata
 
 func (txs byNonce) Len() int      { return len(txs) }
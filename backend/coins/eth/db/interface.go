@@ -14,7 +14,10 @@
 
 package db
 
-import "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // TxInterface needs to be implemented to persist all wallet/transaction related data.
 type TxInterface interface {
@@ -27,6 +30,10 @@ type TxInterface interface {
 	// PutOutgoingTransaction stores the transaction in the collection of outgoing transactions.
 	PutOutgoingTransaction(*types.TransactionWithMetadata) error
 
+	// DeleteOutgoingTransaction removes the transaction with the given hash from the collection of
+	// outgoing transactions.
+	DeleteOutgoingTransaction(txHash common.Hash) error
+
 	// OutgoingTransactions returns the stored list of outgoing transactions, sorted descending by
 	// the transaction nonce.
 	OutgoingTransactions() ([]*types.TransactionWithMetadata, error)
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
@@ -563,6 +564,53 @@ func (etherScan *EtherScan) SuggestGasTipCap(ctx context.Context) (*big.Int, err
 	return nil, errp.New("not implemented")
 }
 
+// FeeHistory implements rpc.Interface.
+func (etherScan *EtherScan) FeeHistory(
+	ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64,
+) (*ethereum.FeeHistory, error) {
+	params := url.Values{}
+	params.Set("action", "eth_feeHistory")
+	params.Set("blockCount", hexutil.EncodeUint64(blockCount))
+	if lastBlock == nil {
+		params.Set("newestBlock", "latest")
+	} else {
+		params.Set("newestBlock", hexutil.EncodeBig(lastBlock))
+	}
+	percentiles := make([]string, len(rewardPercentiles))
+	for i, percentile := range rewardPercentiles {
+		percentiles[i] = strconv.FormatFloat(percentile, 'f', -1, 64)
+	}
+	params.Set("rewardPercentiles", "["+strings.Join(percentiles, ",")+"]")
+	var result struct {
+		OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+		Reward       [][]*hexutil.Big `json:"reward"`
+		BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio []float64        `json:"gasUsedRatio"`
+	}
+	if err := etherScan.rpcCall(params, &result); err != nil {
+		return nil, err
+	}
+	if result.OldestBlock == nil {
+		return nil, errp.New("unexpected response from EtherScan")
+	}
+	feeHistory := &ethereum.FeeHistory{
+		OldestBlock:  (*big.Int)(result.OldestBlock),
+		Reward:       make([][]*big.Int, len(result.Reward)),
+		BaseFee:      make([]*big.Int, len(result.BaseFee)),
+		GasUsedRatio: result.GasUsedRatio,
+	}
+	for i, rewards := range result.Reward {
+		feeHistory.Reward[i] = make([]*big.Int, len(rewards))
+		for j, reward := range rewards {
+			feeHistory.Reward[i][j] = (*big.Int)(reward)
+		}
+	}
+	for i, baseFee := range result.BaseFee {
+		feeHistory.BaseFee[i] = (*big.Int)(baseFee)
+	}
+	return feeHistory, nil
+}
+
 // FeeTargets returns three priorities with fee targets estimated by Etherscan
 // https://docs.etherscan.io/api-endpoints/gas-tracker#get-gas-oracle
 // FeeTargets implements rpc.Interface.
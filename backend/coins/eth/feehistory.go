@@ -0,0 +1,88 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eth
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	ethtypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	ethereum "github.com/ethereum/go-ethereum"
+)
+
+// feeHistoryBlocks is the number of recent blocks whose priority fees are used to estimate the fee
+// targets.
+const feeHistoryBlocks = 20
+
+// feeHistoryPercentiles are the priority fee percentiles requested for the fee targets, in the
+// order of feeHistoryTargetCodes.
+var feeHistoryPercentiles = []float64{90, 50, 10}
+
+var feeHistoryTargetCodes = []accounts.FeeTargetCode{
+	accounts.FeeTargetCodeHigh,
+	accounts.FeeTargetCodeNormal,
+	accounts.FeeTargetCodeLow,
+}
+
+// minGasTipCap is the lowest suggested maxPriorityFeePerGas (0.01 Gwei), so that transactions are
+// still picked up if recent blocks were (nearly) empty.
+var minGasTipCap = big.NewInt(1e7)
+
+// errNoBaseFee is returned if the chain does not support EIP-1559.
+var errNoBaseFee = errp.New("fee history contains no base fee")
+
+// feeTargetsFromFeeHistory estimates the fee targets from the fee history of recent blocks. The
+// maxPriorityFeePerGas of each target is the median of the priority fees of the target's
+// percentile in non-empty blocks. The maxFeePerGas leaves room for the base fee to double until the
+// transaction is included.
+func feeTargetsFromFeeHistory(feeHistory *ethereum.FeeHistory) ([]*ethtypes.FeeTarget, error) {
+	if len(feeHistory.BaseFee) == 0 {
+		return nil, errp.WithStack(errNoBaseFee)
+	}
+	// The last base fee is the one of the next block.
+	baseFee := feeHistory.BaseFee[len(feeHistory.BaseFee)-1]
+	if baseFee == nil || baseFee.Sign() <= 0 {
+		return nil, errp.WithStack(errNoBaseFee)
+	}
+	feeTargets := make([]*ethtypes.FeeTarget, len(feeHistoryTargetCodes))
+	for i, targetCode := range feeHistoryTargetCodes {
+		tips := []*big.Int{}
+		for block, rewards := range feeHistory.Reward {
+			if block < len(feeHistory.GasUsedRatio) && feeHistory.GasUsedRatio[block] == 0 {
+				continue
+			}
+			if i < len(rewards) && rewards[i] != nil {
+				tips = append(tips, rewards[i])
+			}
+		}
+		gasTipCap := new(big.Int).Set(minGasTipCap)
+		if len(tips) > 0 {
+			sort.Slice(tips, func(a, b int) bool { return tips[a].Cmp(tips[b]) < 0 })
+			if median := tips[len(tips)/2]; median.Cmp(gasTipCap) > 0 {
+				gasTipCap.Set(median)
+			}
+		}
+		gasFeeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+		gasFeeCap.Add(gasFeeCap, gasTipCap)
+		feeTargets[i] = &ethtypes.FeeTarget{
+			TargetCode: targetCode,
+			GasTipCap:  gasTipCap,
+			GasFeeCap:  gasFeeCap,
+		}
+	}
+	return feeTargets, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+)
+
+func gwei(value int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(value), big.NewInt(1e9))
+}
+
+func TestFeeTargetsFromFeeHistory(t *testing.T) {
+	feeHistory := &ethereum.FeeHistory{
+		OldestBlock: big.NewInt(100),
+		Reward: [][]*big.Int{
+			{gwei(3), gwei(2), gwei(1)},
+			{gwei(5), gwei(2), gwei(1)},
+			// Empty block, ignored.
+			{big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+			{gwei(4), gwei(3), big.NewInt(0)},
+		},
+		BaseFee:      []*big.Int{gwei(8), gwei(9), gwei(9), gwei(10), gwei(11)},
+		GasUsedRatio: []float64{0.4, 0.6, 0, 0.5},
+	}
+	feeTargets, err := feeTargetsFromFeeHistory(feeHistory)
+	require.NoError(t, err)
+	require.Len(t, feeTargets, 3)
+
+	require.Equal(t, accounts.FeeTargetCodeHigh, feeTargets[0].TargetCode)
+	require.Equal(t, gwei(4), feeTargets[0].GasTipCap)
+	require.Equal(t, gwei(26), feeTargets[0].GasFeeCap)
+
+	require.Equal(t, accounts.FeeTargetCodeNormal, feeTargets[1].TargetCode)
+	require.Equal(t, gwei(2), feeTargets[1].GasTipCap)
+	require.Equal(t, gwei(24), feeTargets[1].GasFeeCap)
+
+	require.Equal(t, accounts.FeeTargetCodeLow, feeTargets[2].TargetCode)
+	require.Equal(t, gwei(1), feeTargets[2].GasTipCap)
+	require.Equal(t, gwei(23), feeTargets[2].GasFeeCap)
+
+	// Only empty blocks: the minimum priority fee is used.
+	feeHistory.GasUsedRatio = []float64{0, 0, 0, 0}
+	feeTargets, err = feeTargetsFromFeeHistory(feeHistory)
+	require.NoError(t, err)
+	for _, feeTarget := range feeTargets {
+		require.Equal(t, minGasTipCap, feeTarget.GasTipCap)
+	}
+
+	// Chains without EIP-1559 have no base fee.
+	feeHistory.BaseFee = []*big.Int{big.NewInt(0), big.NewInt(0)}
+	_, err = feeTargetsFromFeeHistory(feeHistory)
+	require.Equal(t, errNoBaseFee, errp.Cause(err))
+	feeHistory.BaseFee = nil
+	_, err = feeTargetsFromFeeHistory(feeHistory)
+	require.Equal(t, errNoBaseFee, errp.Cause(err))
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/errors"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	ethtypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// QueuedTransaction is a pending outgoing transaction, which can be replaced by a transaction with
+// the same nonce and a higher fee.
+type QueuedTransaction struct {
+	TxID  string
+	Nonce uint64
+	// MaxFee is the maximum fee the transaction can pay, the gas limit times GasFeeCap.
+	MaxFee coin.Amount
+	// GasFeeCap is the maxFeePerGas, or the gas price of legacy transactions, in Wei.
+	GasFeeCap *big.Int
+	// GasTipCap is the maxPriorityFeePerGas, or the gas price of legacy transactions, in Wei.
+	GasTipCap *big.Int
+	// BroadcastAttempts is the number of times the transaction was broadcast.
+	BroadcastAttempts uint16
+}
+
+// QueuedTransactions returns the pending outgoing transactions, sorted ascending by nonce.
+func (account *Account) QueuedTransactions() ([]*QueuedTransaction, error) {
+	dbTx, err := account.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer dbTx.Rollback()
+	outgoingTransactions, err := dbTx.OutgoingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	queued := []*QueuedTransaction{}
+	for _, tx := range outgoingTransactions {
+		if tx.Height != 0 {
+			continue
+		}
+		queued = append(queued, &QueuedTransaction{
+			TxID:              tx.TxID(),
+			Nonce:             tx.Transaction.Nonce(),
+			MaxFee:            coin.NewAmount(new(big.Int).Mul(new(big.Int).SetUint64(tx.Transaction.Gas()), tx.Transaction.GasFeeCap())),
+			GasFeeCap:         tx.Transaction.GasFeeCap(),
+			GasTipCap:         tx.Transaction.GasTipCap(),
+			BroadcastAttempts: tx.BroadcastAttempts,
+		})
+	}
+	sort.Slice(queued, func(i, j int) bool { return queued[i].Nonce < queued[j].Nonce })
+	return queued, nil
+}
+
+// pendingOutgoingTransaction returns the stored pending outgoing transaction with the given ID.
+func (account *Account) pendingOutgoingTransaction(txID string) (*types.Transaction, error) {
+	dbTx, err := account.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer dbTx.Rollback()
+	outgoingTransactions, err := dbTx.OutgoingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range outgoingTransactions {
+		if tx.TxID() != txID {
+			continue
+		}
+		if tx.Height != 0 {
+			return nil, errp.New("The transaction is already confirmed")
+		}
+		return tx.Transaction, nil
+	}
+	return nil, errp.Newf("Pending transaction %s not found", txID)
+}
+
+// minReplacementFee returns the lowest fee accepted by nodes for a replacement transaction, which
+// is 10% higher than the fee of the replaced transaction.
+func minReplacementFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(110))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// replacementFees returns the maxFeePerGas and maxPriorityFeePerGas of a transaction replacing a
+// transaction with the given fees. The suggested fees are used if they are high enough for the
+// replacement to be accepted. suggested can be nil if no fee suggestion is available.
+func replacementFees(gasFeeCap, gasTipCap *big.Int, suggested *ethtypes.FeeTarget) (
+	*big.Int, *big.Int) {
+	newGasFeeCap := minReplacementFee(gasFeeCap)
+	newGasTipCap := minReplacementFee(gasTipCap)
+	if suggested != nil {
+		if suggested.GasFeeCap != nil && suggested.GasFeeCap.Cmp(newGasFeeCap) > 0 {
+			newGasFeeCap = new(big.Int).Set(suggested.GasFeeCap)
+		}
+		if suggested.GasTipCap != nil && suggested.GasTipCap.Cmp(newGasTipCap) > 0 {
+			newGasTipCap = new(big.Int).Set(suggested.GasTipCap)
+		}
+	}
+	if newGasTipCap.Cmp(newGasFeeCap) > 0 {
+		newGasFeeCap = new(big.Int).Set(newGasTipCap)
+	}
+	return newGasFeeCap, newGasTipCap
+}
+
+// highestFeeTarget returns the fee target with the highest maxFeePerGas, or nil if there are no fee
+// targets.
+func highestFeeTarget(feeTargets []*ethtypes.FeeTarget) *ethtypes.FeeTarget {
+	var highest *ethtypes.FeeTarget
+	for _, feeTarget := range feeTargets {
+		if highest == nil || feeTarget.GasFeeCap.Cmp(highest.GasFeeCap) > 0 {
+			highest = feeTarget
+		}
+	}
+	return highest
+}
+
+// ReplaceTransaction replaces a pending outgoing transaction by a transaction with the same nonce
+// and a higher fee, so it is mined faster. If cancel is true, the replacement transaction sends
+// nothing to the own address instead, which cancels the original transaction. The new fees are the
+// highest current fee suggestion, but at least 10% higher than the fees of the original
+// transaction. Returns the ID of the replacement transaction.
+func (account *Account) ReplaceTransaction(ctx context.Context, txID string, cancel bool) (string, error) {
+	if !account.isInitialized() {
+		return "", errp.New("account must be initialized")
+	}
+	original, err := account.pendingOutgoingTransaction(txID)
+	if err != nil {
+		return "", err
+	}
+
+	gasFeeCap, gasTipCap := replacementFees(
+		original.GasFeeCap(), original.GasTipCap(), highestFeeTarget(account.feeTargets(ctx)))
+	to := *original.To()
+	value := original.Value()
+	data := original.Data()
+	gasLimit := original.Gas()
+	if cancel {
+		to = account.address.Address
+		value = big.NewInt(0)
+		data = nil
+		gasLimit = params.TxGas
+	}
+	maxFee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasFeeCap)
+	if account.coin.erc20Token == nil {
+		unlock := account.updateLock.RLock()
+		balance := account.balance.BigInt()
+		unlock()
+		if new(big.Int).Add(value, maxFee).Cmp(balance) > 0 {
+			return "", errp.WithStack(errors.ErrInsufficientFunds)
+		}
+	}
+
+	var tx *types.Transaction
+	if original.Type() == types.DynamicFeeTxType {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			Nonce:     original.Nonce(),
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+	} else {
+		tx = types.NewTransaction(original.Nonce(), to, value, gasLimit, gasFeeCap, data)
+	}
+	txProposal := &TxProposal{
+		Coin:             account.coin,
+		Tx:               tx,
+		Fee:              maxFee,
+		Value:            value,
+		Signer:           types.NewLondonSigner(account.coin.net.ChainID),
+		Keypath:          account.signingConfiguration.AbsoluteKeypath(),
+		RecipientAddress: to.Hex(),
+	}
+
+	keystore, err := account.Config().ConnectKeystore()
+	if err != nil {
+		return "", err
+	}
+	account.log.Infof("Signing and sending replacement transaction with nonce: %d", tx.Nonce())
+	if err := keystore.SignTransaction(txProposal); err != nil {
+		return "", err
+	}
+	if err := account.coin.client.SendTransaction(ctx, txProposal.Tx); err != nil {
+		return "", errp.WithStack(err)
+	}
+	dbTx, err := account.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer dbTx.Rollback()
+	if err := dbTx.PutOutgoingTransaction(&ethtypes.TransactionWithMetadata{
+		Transaction:       txProposal.Tx,
+		BroadcastAttempts: 1,
+	}); err != nil {
+		return "", err
+	}
+	// If the original transaction is mined after all, it is shown from the transactions source and
+	// the replacement is removed as its nonce is used.
+	if err := dbTx.DeleteOutgoingTransaction(original.Hash()); err != nil {
+		return "", err
+	}
+	if err := dbTx.Commit(); err != nil {
+		return "", err
+	}
+	account.enqueueUpdateCh <- struct{}{}
+	return txProposal.Tx.Hash().Hex(), nil
+}
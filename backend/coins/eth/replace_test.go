@@ -0,0 +1,71 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	ethtypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplacementFees(t *testing.T) {
+	require.Equal(t, big.NewInt(110), minReplacementFee(big.NewInt(100)))
+	// Rounded up.
+	require.Equal(t, big.NewInt(11), minReplacementFee(big.NewInt(10)))
+	require.Equal(t, big.NewInt(13), minReplacementFee(big.NewInt(11)))
+	require.Equal(t, big.NewInt(2), minReplacementFee(big.NewInt(1)))
+
+	// No suggestion: bumped by 10%.
+	gasFeeCap, gasTipCap := replacementFees(gwei(20), gwei(2), nil)
+	require.Equal(t, gwei(22), gasFeeCap)
+	require.Equal(t, new(big.Int).Mul(big.NewInt(22), big.NewInt(1e8)), gasTipCap)
+
+	// Higher suggestion is used.
+	gasFeeCap, gasTipCap = replacementFees(gwei(20), gwei(2), &ethtypes.FeeTarget{
+		GasFeeCap: gwei(30),
+		GasTipCap: gwei(3),
+	})
+	require.Equal(t, gwei(30), gasFeeCap)
+	require.Equal(t, gwei(3), gasTipCap)
+
+	// Lower suggestion is not used.
+	gasFeeCap, gasTipCap = replacementFees(gwei(20), gwei(2), &ethtypes.FeeTarget{
+		GasFeeCap: gwei(10),
+		GasTipCap: gwei(1),
+	})
+	require.Equal(t, gwei(22), gasFeeCap)
+	require.Equal(t, new(big.Int).Mul(big.NewInt(22), big.NewInt(1e8)), gasTipCap)
+
+	// The maxFeePerGas is at least the maxPriorityFeePerGas.
+	gasFeeCap, gasTipCap = replacementFees(gwei(20), gwei(2), &ethtypes.FeeTarget{
+		GasFeeCap: gwei(21),
+		GasTipCap: gwei(25),
+	})
+	require.Equal(t, gwei(25), gasFeeCap)
+	require.Equal(t, gwei(25), gasTipCap)
+}
+
+func TestHighestFeeTarget(t *testing.T) {
+	require.Nil(t, highestFeeTarget(nil))
+	feeTargets := []*ethtypes.FeeTarget{
+		{TargetCode: accounts.FeeTargetCodeLow, GasFeeCap: gwei(10)},
+		{TargetCode: accounts.FeeTargetCodeHigh, GasFeeCap: gwei(30)},
+		{TargetCode: accounts.FeeTargetCodeNormal, GasFeeCap: gwei(20)},
+	}
+	require.Equal(t, accounts.FeeTargetCodeHigh, highestFeeTarget(feeTargets).TargetCode)
+}
@@ -37,6 +37,9 @@ var _ rpcclient.Interface = &InterfaceMock{}
 //			EstimateGasFunc: func(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
 //				panic("mock out the EstimateGas method")
 //			},
+//			FeeHistoryFunc: func(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+//				panic("mock out the FeeHistory method")
+//			},
 //			FeeTargetsFunc: func(ctx context.Context) ([]*ethtypes.FeeTarget, error) {
 //				panic("mock out the FeeTargets method")
 //			},
@@ -74,6 +77,9 @@ type InterfaceMock struct {
 	// EstimateGasFunc mocks the EstimateGas method.
 	EstimateGasFunc func(ctx context.Context, call ethereum.CallMsg) (uint64, error)
 
+	// FeeHistoryFunc mocks the FeeHistory method.
+	FeeHistoryFunc func(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+
 	// FeeTargetsFunc mocks the FeeTargets method.
 	FeeTargetsFunc func(ctx context.Context) ([]*ethtypes.FeeTarget, error)
 
@@ -120,6 +126,17 @@ type InterfaceMock struct {
 			// Call is the call argument value.
 			Call ethereum.CallMsg
 		}
+		// FeeHistory holds details about calls to the FeeHistory method.
+		FeeHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BlockCount is the blockCount argument value.
+			BlockCount uint64
+			// LastBlock is the lastBlock argument value.
+			LastBlock *big.Int
+			// RewardPercentiles is the rewardPercentiles argument value.
+			RewardPercentiles []float64
+		}
 		// FeeTargets holds details about calls to the FeeTargets method.
 		FeeTargets []struct {
 			// Ctx is the ctx argument value.
@@ -163,6 +180,7 @@ type InterfaceMock struct {
 	lockBlockNumber                       sync.RWMutex
 	lockERC20Balance                      sync.RWMutex
 	lockEstimateGas                       sync.RWMutex
+	lockFeeHistory                        sync.RWMutex
 	lockFeeTargets                        sync.RWMutex
 	lockPendingNonceAt                    sync.RWMutex
 	lockSendTransaction                   sync.RWMutex
@@ -311,6 +329,50 @@ func (mock *InterfaceMock) EstimateGasCalls() []struct {
 	return calls
 }
 
+// FeeHistory calls FeeHistoryFunc.
+func (mock *InterfaceMock) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	if mock.FeeHistoryFunc == nil {
+		panic("InterfaceMock.FeeHistoryFunc: method is nil but Interface.FeeHistory was just called")
+	}
+	callInfo := struct {
+		Ctx               context.Context
+		BlockCount        uint64
+		LastBlock         *big.Int
+		RewardPercentiles []float64
+	}{
+		Ctx:               ctx,
+		BlockCount:        blockCount,
+		LastBlock:         lastBlock,
+		RewardPercentiles: rewardPercentiles,
+	}
+	mock.lockFeeHistory.Lock()
+	mock.calls.FeeHistory = append(mock.calls.FeeHistory, callInfo)
+	mock.lockFeeHistory.Unlock()
+	return mock.FeeHistoryFunc(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
+// FeeHistoryCalls gets all the calls that were made to FeeHistory.
+// Check the length with:
+//
+//	len(mockedInterface.FeeHistoryCalls())
+func (mock *InterfaceMock) FeeHistoryCalls() []struct {
+	Ctx               context.Context
+	BlockCount        uint64
+	LastBlock         *big.Int
+	RewardPercentiles []float64
+} {
+	var calls []struct {
+		Ctx               context.Context
+		BlockCount        uint64
+		LastBlock         *big.Int
+		RewardPercentiles []float64
+	}
+	mock.lockFeeHistory.RLock()
+	calls = mock.calls.FeeHistory
+	mock.lockFeeHistory.RUnlock()
+	return calls
+}
+
 // FeeTargets calls FeeTargetsFunc.
 func (mock *InterfaceMock) FeeTargets(ctx context.Context) ([]*ethtypes.FeeTarget, error) {
 	if mock.FeeTargetsFunc == nil {
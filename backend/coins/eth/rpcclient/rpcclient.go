@@ -51,6 +51,9 @@ type Interface interface {
 	// SuggestGasPrice retrieves the currently suggested gas price to allow a timely
 	// execution of a transaction.
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	// FeeHistory returns the base fees and the priority fees at the given percentiles of the
+	// blockCount blocks up to lastBlock, or up to the latest block if lastBlock is nil.
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
 	// FeeTargets returns EIP-1559 compatible priorities (maxFeePerGas + baseFee)
 	FeeTargets(ctx context.Context) ([]*ethtypes.FeeTarget, error)
 }
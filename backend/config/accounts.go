@@ -57,6 +57,18 @@ type Account struct {
 	// only applies to ETH, and the elements are ERC20 token codes (e.g. "eth-erc20-usdt",
 	// "eth-erc20-bat", etc).
 	ActiveTokens []string `json:"activeTokens,omitempty"`
+	// ElectrumServers, if not empty, are used by this account instead of the Electrum servers of
+	// the coin, e.g. to use a personal server for only one account. Only applies to BTC-based
+	// accounts.
+	ElectrumServers []*ServerInfo `json:"electrumServers,omitempty"`
+	// GapLimit, if not zero, is the minimum gap limit of the receive and change addresses of this
+	// account, for wallets which left large gaps between used addresses. Only applies to BTC-based
+	// accounts.
+	GapLimit uint16 `json:"gapLimit,omitempty"`
+	// CoinSelection is the algorithm selecting the coins spent by new transactions of this
+	// account, see maketx.CoinSelection. If empty, the default algorithm is used. Only applies to
+	// BTC-based accounts.
+	CoinSelection string `json:"coinSelection,omitempty"`
 }
 
 // SetTokenActive activates/deactivates an token on an account. `tokenCode` must be an ERC20 token
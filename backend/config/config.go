@@ -17,12 +17,14 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/rates"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
 )
 
 // ServerInfo holds information about the backend server(s).
@@ -39,9 +41,41 @@ func (s *ServerInfo) String() string {
 	return s.Server + ":p"
 }
 
+// BitcoinCoreConfig holds the connection details of a user-provided Bitcoin Core node.
+type BitcoinCoreConfig struct {
+	// Enabled means the node is used instead of the Electrum servers.
+	Enabled bool `json:"enabled"`
+	// RPCURL is the URL of the JSON-RPC interface of the node, e.g. "http://127.0.0.1:8332".
+	RPCURL      string `json:"rpcURL"`
+	RPCUser     string `json:"rpcUser"`
+	RPCPassword string `json:"rpcPassword"`
+	// ElectrumServer is an Electrum server indexing the node's blockchain, e.g. electrs. Bitcoin
+	// Core does not index transactions by address, so it is needed to find the transactions of the
+	// accounts.
+	ElectrumServer *ServerInfo `json:"electrumServer"`
+}
+
+// RedactedRPCURL returns RPCURL without the user and password it may contain, for logging.
+func (cfg *BitcoinCoreConfig) RedactedRPCURL() string {
+	rpcURL, err := url.Parse(cfg.RPCURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	rpcURL.User = nil
+	return rpcURL.String()
+}
+
 // btcCoinConfig holds configurations specific to a btc-based coin.
 type btcCoinConfig struct {
 	ElectrumServers []*ServerInfo `json:"electrumServers"`
+	// PrioritizeElectrumServers means the Electrum servers are tried in the configured order,
+	// starting with the first. By default, a random server is tried first to spread the load.
+	PrioritizeElectrumServers bool `json:"prioritizeElectrumServers"`
+	// BitcoinCore is only supported for Bitcoin, not Litecoin.
+	BitcoinCore BitcoinCoreConfig `json:"bitcoinCore"`
+	// BlockExplorerTxPrefix is the URL prefix of the block explorer, to which the transaction ID is
+	// appended to link to a transaction. See Backend.BlockExplorerTxPrefix().
+	BlockExplorerTxPrefix string `json:"blockExplorerTxPrefix"`
 }
 
 // ETHTransactionsSource  where to get Ethereum transactions from. See the list of consts
@@ -59,6 +93,15 @@ const (
 // ethCoinConfig holds configurations for ethereum coins.
 type ethCoinConfig struct {
 	DeprecatedActiveERC20Tokens []string `json:"activeERC20Tokens"`
+	// BlockExplorerTxPrefix is the URL prefix of the block explorer, to which the transaction ID is
+	// appended to link to a transaction. See Backend.BlockExplorerTxPrefix().
+	BlockExplorerTxPrefix string `json:"blockExplorerTxPrefix"`
+}
+
+// AppPassword is the argon2id hash of the app password, see Backend.EnableAppPassword().
+type AppPassword struct {
+	Salt []byte `json:"salt"`
+	Hash []byte `json:"hash"`
 }
 
 type proxyConfig struct {
@@ -78,10 +121,13 @@ type Backend struct {
 
 	BTC  btcCoinConfig `json:"btc"`
 	TBTC btcCoinConfig `json:"tbtc"`
+	TSIG btcCoinConfig `json:"tsig"`
 	RBTC btcCoinConfig `json:"rbtc"`
 	LTC  btcCoinConfig `json:"ltc"`
 	TLTC btcCoinConfig `json:"tltc"`
 	ETH  ethCoinConfig `json:"eth"`
+	// SEPETH only holds the block explorer, as ERC20 tokens are not supported on Sepolia.
+	SEPETH ethCoinConfig `json:"sepeth"`
 
 	// Removed in v4.35 - don't reuse these two keys.
 	TETH struct{} `json:"teth"`
@@ -93,6 +139,12 @@ type Backend struct {
 	// MainFiat is the fiat currency used as a default for computing account portfolio data
 	// and transaction amounts.
 	MainFiat string `json:"mainFiat"`
+	// PruneHeaders means only the most recent block headers are kept on disk, to save disk space.
+	PruneHeaders bool `json:"pruneHeaders"`
+
+	// RatesProvider is the preferred source of the latest exchange rates, see the rates.Provider*
+	// constants. The other sources are used if it fails. Empty means the default provider.
+	RatesProvider string `json:"ratesProvider"`
 
 	// UserLanguage is the UI language preferred by the user.
 	// It may be missing from an app config.json if the user never selected one
@@ -106,6 +158,44 @@ type Backend struct {
 	// StartInTestnet represents whether the app should launch in testnet on the next start.
 	// It resets to `false` after the app starts.
 	StartInTestnet bool `json:"startInTestnet"`
+
+	// DustThreshold is the value in satoshi below which a coin received on an already used address
+	// is flagged as a possible dusting attack. 0 disables the detection.
+	DustThreshold int64 `json:"dustThreshold"`
+
+	// HWIPath is the path to the HWI executable used to connect external signers, e.g. Trezor or
+	// Ledger devices. If empty, `hwi` is looked up in PATH. As the executable is run by the backend,
+	// it can only be set by editing the config file, not through the API or a config backup.
+	HWIPath string `json:"hwiPath"`
+
+	// AutoLockMinutes is the number of minutes without API activity after which the registered
+	// keystores are removed. 0 disables the auto-lock.
+	AutoLockMinutes int `json:"autoLockMinutes"`
+
+	// AppPassword is set if the user protects the app with a password, which has to be entered
+	// after each start before accounts and devices can be accessed. nil if no password is set.
+	AppPassword *AppPassword `json:"appPassword"`
+
+	// CrashReports is the user's opt-in to keep reports of crashes, which can be reviewed and sent
+	// to Shift Crypto.
+	CrashReports bool `json:"crashReports"`
+
+	// SyncThrottling configures how background syncing is reduced on battery or metered networks.
+	SyncThrottling SyncThrottling `json:"syncThrottling"`
+}
+
+// SyncThrottling configures how background syncing is reduced while the device runs on battery or
+// uses a metered network. Archived accounts are never synced.
+type SyncThrottling struct {
+	// BatteryFactor multiplies the background polling intervals while on battery. Values up to 1
+	// disable throttling on battery.
+	BatteryFactor int `json:"batteryFactor"`
+	// MeteredFactor multiplies the background polling intervals while on a metered network. Values
+	// up to 1 disable throttling on metered networks.
+	MeteredFactor int `json:"meteredFactor"`
+	// PauseOnMetered pauses background polling while on a metered network. Accounts are still
+	// updated when requested, e.g. after sending a transaction.
+	PauseOnMetered bool `json:"pauseOnMetered"`
 }
 
 // DeprecatedCoinActive returns the Active setting for a coin by code.  This call is should not be
@@ -113,7 +203,7 @@ type Backend struct {
 // kept in the accounts config.
 func (backend Backend) DeprecatedCoinActive(code coin.Code) bool {
 	switch code {
-	case coin.CodeBTC, coin.CodeTBTC, coin.CodeRBTC:
+	case coin.CodeBTC, coin.CodeTBTC, coin.CodeTSIG, coin.CodeRBTC:
 		return backend.DeprecatedBitcoinActive
 	case coin.CodeLTC, coin.CodeTLTC:
 		return backend.DeprecatedLitecoinActive
@@ -124,6 +214,87 @@ func (backend Backend) DeprecatedCoinActive(code coin.Code) bool {
 	}
 }
 
+// BlockExplorerTxPrefix returns the block explorer URL prefix of the transactions of a coin. ERC20
+// tokens use the prefix of ETH. If the configured prefix is not a valid http(s) URL, the default
+// prefix is returned. The empty string is returned if the coin has no block explorer.
+func (backend Backend) BlockExplorerTxPrefix(code coin.Code) string {
+	prefix := func(backend Backend) string {
+		switch code {
+		case coin.CodeBTC:
+			return backend.BTC.BlockExplorerTxPrefix
+		case coin.CodeTBTC:
+			return backend.TBTC.BlockExplorerTxPrefix
+		case coin.CodeTSIG:
+			return backend.TSIG.BlockExplorerTxPrefix
+		case coin.CodeRBTC:
+			return backend.RBTC.BlockExplorerTxPrefix
+		case coin.CodeLTC:
+			return backend.LTC.BlockExplorerTxPrefix
+		case coin.CodeTLTC:
+			return backend.TLTC.BlockExplorerTxPrefix
+		case coin.CodeETH:
+			return backend.ETH.BlockExplorerTxPrefix
+		case coin.CodeSEPETH:
+			return backend.SEPETH.BlockExplorerTxPrefix
+		default:
+			return ""
+		}
+	}
+	if configured := prefix(backend); isValidBlockExplorerTxPrefix(configured) {
+		return configured
+	}
+	return prefix(NewDefaultAppConfig().Backend)
+}
+
+// isValidBlockExplorerTxPrefix returns true if the prefix is an absolute http(s) URL.
+func isValidBlockExplorerTxPrefix(prefix string) bool {
+	parsed, err := url.Parse(prefix)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
+
+// mapCerts replaces the certificate of each Electrum server of the btc-based coins with the result
+// of f. The server infos are copied, so that other copies of the config are not modified. The
+// built-in Shift root CA is kept as is. It is public, and it is also set in the default config and
+// by migrations before the vault can be unlocked.
+func (backend *Backend) mapCerts(f func(pemCert string) (string, error)) error {
+	mapServer := func(server *ServerInfo) (*ServerInfo, error) {
+		if server == nil || server.PEMCert == shiftRootCA {
+			return server, nil
+		}
+		pemCert, err := f(server.PEMCert)
+		if err != nil {
+			return nil, err
+		}
+		mapped := *server
+		mapped.PEMCert = pemCert
+		return &mapped, nil
+	}
+	for _, coinConfig := range []*btcCoinConfig{
+		&backend.BTC, &backend.TBTC, &backend.TSIG, &backend.RBTC, &backend.LTC, &backend.TLTC,
+	} {
+		if coinConfig.ElectrumServers != nil {
+			servers := make([]*ServerInfo, len(coinConfig.ElectrumServers))
+			for i, server := range coinConfig.ElectrumServers {
+				mapped, err := mapServer(server)
+				if err != nil {
+					return err
+				}
+				servers[i] = mapped
+			}
+			coinConfig.ElectrumServers = servers
+		}
+		mapped, err := mapServer(coinConfig.BitcoinCore.ElectrumServer)
+		if err != nil {
+			return err
+		}
+		coinConfig.BitcoinCore.ElectrumServer = mapped
+	}
+	return nil
+}
+
 // AppConfig holds the whole app configuration.
 type AppConfig struct {
 	Backend  Backend     `json:"backend"`
@@ -172,6 +343,7 @@ func NewDefaultAppConfig() AppConfig {
 						PEMCert: shiftRootCA,
 					},
 				},
+				BlockExplorerTxPrefix: "https://blockstream.info/tx/",
 			},
 			TBTC: btcCoinConfig{
 				ElectrumServers: []*ServerInfo{
@@ -186,6 +358,18 @@ func NewDefaultAppConfig() AppConfig {
 						PEMCert: shiftRootCA,
 					},
 				},
+				BlockExplorerTxPrefix: "https://blockstream.info/testnet/tx/",
+			},
+			TSIG: btcCoinConfig{
+				// Public signet servers with certificates signed by a public CA.
+				ElectrumServers: []*ServerInfo{
+					{
+						Server:  "mempool.space:60602",
+						TLS:     true,
+						PEMCert: "",
+					},
+				},
+				BlockExplorerTxPrefix: "https://mempool.space/signet/tx/",
 			},
 			RBTC: btcCoinConfig{
 				ElectrumServers: []*ServerInfo{
@@ -214,6 +398,7 @@ func NewDefaultAppConfig() AppConfig {
 						PEMCert: shiftRootCA,
 					},
 				},
+				BlockExplorerTxPrefix: "https://blockchair.com/litecoin/transaction/",
 			},
 			TLTC: btcCoinConfig{
 				ElectrumServers: []*ServerInfo{
@@ -228,14 +413,24 @@ func NewDefaultAppConfig() AppConfig {
 						PEMCert: shiftRootCA,
 					},
 				},
+				BlockExplorerTxPrefix: "https://sochain.com/tx/LTCTEST/",
 			},
 			ETH: ethCoinConfig{
 				DeprecatedActiveERC20Tokens: []string{},
+				BlockExplorerTxPrefix:       "https://etherscan.io/tx/",
+			},
+			SEPETH: ethCoinConfig{
+				BlockExplorerTxPrefix: "https://sepolia.etherscan.io/tx/",
 			},
 			// Copied from frontend/web/src/components/rates/rates.tsx.
-			FiatList: []string{rates.USD.String(), rates.EUR.String(), rates.CHF.String()},
-			MainFiat: rates.USD.String(),
-			BtcUnit:  coin.BtcUnitDefault,
+			FiatList:      []string{rates.USD.String(), rates.EUR.String(), rates.CHF.String()},
+			MainFiat:      rates.USD.String(),
+			BtcUnit:       coin.BtcUnitDefault,
+			DustThreshold: 1000,
+			SyncThrottling: SyncThrottling{
+				BatteryFactor: 2,
+				MeteredFactor: 4,
+			},
 		},
 		Frontend: make(map[string]interface{}),
 	}
@@ -250,17 +445,26 @@ type Config struct {
 	accountsConfigFilename string
 	accountsConfig         AccountsConfig
 	accountsConfigLock     locker.Locker
+
+	// vault encrypts the accounts config and the certificates of the Electrum servers in the app
+	// config if the encryption is enabled. Can be nil.
+	vault *vault.Vault
 }
 
 // NewConfig creates a new Config, stored in the given location. The filename must be writable, but
-// does not have to exist.
-func NewConfig(appConfigFilename string, accountsConfigFilename string) (*Config, error) {
+// does not have to exist. The accounts config is read and written through the vault, which can be
+// nil. If the vault is locked, the default accounts config is used until ReloadAccountsConfig() is
+// called after unlocking it, and the certificates of the Electrum servers stay encrypted until
+// DecryptServerCerts() is called.
+func NewConfig(appConfigFilename string, accountsConfigFilename string, vault *vault.Vault) (*Config, error) {
 	config := &Config{
 		appConfigFilename: appConfigFilename,
 		appConfig:         NewDefaultAppConfig(),
 
 		accountsConfigFilename: accountsConfigFilename,
 		accountsConfig:         newDefaultAccountsonfig(),
+
+		vault: vault,
 	}
 	config.load()
 	appconf := config.appConfig
@@ -268,15 +472,76 @@ func NewConfig(appConfigFilename string, accountsConfigFilename string) (*Config
 	migrateFiatCode(&appconf)
 	migrateElectrumX(&appconf)
 	migrateUserLanguage(&appconf)
+	if vault.Locked() {
+		// The migrated config is persisted with the next change. A certificate that was not
+		// encrypted can't be persisted before the vault is unlocked, and is rejected then.
+		config.appConfig = appconf
+		return config, nil
+	}
+	if err := appconf.Backend.mapCerts(vault.Codec().DecryptString); err != nil {
+		return nil, err
+	}
 	if err := config.SetAppConfig(appconf); err != nil {
 		return nil, errp.WithStack(err)
 	}
-	if err := config.ModifyAccountsConfig(migrateActiveTokens); err != nil {
-		return nil, errp.WithStack(err)
+	if err := config.ReloadAccountsConfig(); err != nil {
+		return nil, err
 	}
 	return config, nil
 }
 
+// ReloadAccountsConfig reads the accounts config from disk, e.g. after the vault was unlocked.
+func (config *Config) ReloadAccountsConfig() error {
+	defer config.accountsConfigLock.Lock()()
+	accountsConfig := newDefaultAccountsonfig()
+	jsonBytes, err := config.vault.ReadFile(config.accountsConfigFilename)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(jsonBytes, &accountsConfig); err != nil {
+			accountsConfig = newDefaultAccountsonfig()
+		}
+	case !os.IsNotExist(err):
+		return errp.WithStack(err)
+	}
+	config.accountsConfig = accountsConfig
+	if err := migrateActiveTokens(&config.accountsConfig); err != nil {
+		return errp.WithStack(err)
+	}
+	return config.saveAccountsConfig()
+}
+
+// DecryptServerCerts decrypts the certificates of the Electrum servers in the app config after the
+// vault was unlocked. vault.ErrNotEncrypted is returned if a certificate was not encrypted, as it
+// was then not set by the app.
+func (config *Config) DecryptServerCerts() error {
+	defer config.appConfigLock.Lock()()
+	appConfig := config.appConfig
+	if err := appConfig.Backend.mapCerts(config.vault.Codec().DecryptString); err != nil {
+		return err
+	}
+	config.appConfig = appConfig
+	return nil
+}
+
+// AppConfigFile returns the app config file to rewrite when the vault encryption is enabled or
+// disabled. Only the certificates of the Electrum servers are encrypted.
+func (config *Config) AppConfigFile() vault.File {
+	return vault.File{
+		Filename: config.appConfigFilename,
+		Reencode: func(contents []byte, from, to *vault.Codec) ([]byte, error) {
+			// Missing fields get their default values, like in load().
+			appConfig := NewDefaultAppConfig()
+			if err := json.Unmarshal(contents, &appConfig); err != nil {
+				return nil, errp.WithStack(err)
+			}
+			if err := appConfig.Backend.mapCerts(from.DecryptString); err != nil {
+				return nil, err
+			}
+			return encodeAppConfig(appConfig, to)
+		},
+	}
+}
+
 // SetBTCElectrumServers sets the BTC configuration to the provided electrumIP and electrumCert.
 func (config *Config) SetBTCElectrumServers(electrumAddress, electrumCert string) {
 	config.appConfig.Backend.BTC = btcCoinConfig{
@@ -311,13 +576,6 @@ func (config *Config) load() {
 	if err := json.Unmarshal(jsonBytes, &config.appConfig); err != nil {
 		return
 	}
-	jsonBytes, err = os.ReadFile(config.accountsConfigFilename)
-	if err != nil {
-		return
-	}
-	if err := json.Unmarshal(jsonBytes, &config.accountsConfig); err != nil {
-		return
-	}
 }
 
 // AppConfig returns the app config.
@@ -326,21 +584,30 @@ func (config *Config) AppConfig() AppConfig {
 	return config.appConfig
 }
 
-// SetAppConfig sets and persists the app config.
+// SetAppConfig sets and persists the app config. The config is only set if it could be persisted,
+// e.g. a new certificate can't be encrypted while the vault is locked.
 func (config *Config) SetAppConfig(appConfig AppConfig) error {
 	defer config.appConfigLock.Lock()()
+	if err := config.saveAppConfig(appConfig); err != nil {
+		return err
+	}
 	config.appConfig = appConfig
-	return config.save(config.appConfigFilename, config.appConfig)
+	return nil
 }
 
 // ModifyAppConfig calls f with the current config, allowing f to make any changes, and
 // persists the result if f returns nil error.  It propagates the f's error as is.
 func (config *Config) ModifyAppConfig(f func(*AppConfig) error) error {
 	defer config.appConfigLock.Lock()()
-	if err := f(&config.appConfig); err != nil {
+	appConfig := config.appConfig
+	if err := f(&appConfig); err != nil {
+		return err
+	}
+	if err := config.saveAppConfig(appConfig); err != nil {
 		return err
 	}
-	return config.save(config.appConfigFilename, config.appConfig)
+	config.appConfig = appConfig
+	return nil
 }
 
 // AccountsConfig returns the accounts config.
@@ -353,18 +620,46 @@ func (config *Config) AccountsConfig() AccountsConfig {
 // persists the result if f returns nil error.  It propagates the f's error as is.
 func (config *Config) ModifyAccountsConfig(f func(*AccountsConfig) error) error {
 	defer config.accountsConfigLock.Lock()()
+	// The accounts config in memory is not the persisted one while the vault is locked.
+	if config.vault.Locked() {
+		return vault.ErrLocked
+	}
 	if err := f(&config.accountsConfig); err != nil {
 		return err
 	}
-	return config.save(config.accountsConfigFilename, config.accountsConfig)
+	return config.saveAccountsConfig()
+}
+
+// saveAppConfig persists the app config, with the certificates of the Electrum servers encrypted if
+// the vault encryption is enabled. The appConfigLock must be held.
+func (config *Config) saveAppConfig(appConfig AppConfig) error {
+	return errp.WithStack(config.vault.WriteFileEncoded(config.appConfigFilename, 0644,
+		func(codec *vault.Codec) ([]byte, error) {
+			return encodeAppConfig(appConfig, codec)
+		}))
+}
+
+// encodeAppConfig serializes the app config, encrypting the certificates of the Electrum servers
+// with the codec.
+func encodeAppConfig(appConfig AppConfig, codec *vault.Codec) ([]byte, error) {
+	if err := appConfig.Backend.mapCerts(codec.EncryptString); err != nil {
+		return nil, err
+	}
+	jsonBytes, err := json.MarshalIndent(appConfig, "", "    ")
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return jsonBytes, nil
 }
 
-func (config *Config) save(filename string, conf interface{}) error {
-	jsonBytes, err := json.MarshalIndent(conf, "", "    ")
+// saveAccountsConfig persists the accounts config, encrypted if the vault encryption is enabled.
+// The accountsConfigLock must be held.
+func (config *Config) saveAccountsConfig() error {
+	jsonBytes, err := json.MarshalIndent(config.accountsConfig, "", "    ")
 	if err != nil {
 		return errp.WithStack(err)
 	}
-	return errp.WithStack(os.WriteFile(filename, jsonBytes, 0644)) // #nosec G306
+	return errp.WithStack(config.vault.WriteFile(config.accountsConfigFilename, jsonBytes, 0644))
 }
 
 // migrateFiatList moves fiatList from appconf.Frontend to appconf.Backend.
@@ -0,0 +1,127 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
+)
+
+// configBackupVersion is the version of the ConfigBackup format. Backups of a newer version are
+// rejected.
+const configBackupVersion = 1
+
+// ConfigBackup bundles the app configuration (settings and custom servers), the accounts
+// configuration (keystores, account names and metadata) and the notes of all accounts, so they can
+// be moved to another machine.
+type ConfigBackup struct {
+	Version        int                   `json:"version"`
+	AppConfig      config.AppConfig      `json:"appConfig"`
+	AccountsConfig config.AccountsConfig `json:"accountsConfig"`
+	// Notes contains the notes of the accounts, keyed by the notes filename.
+	Notes map[string]*notes.Data `json:"notes"`
+}
+
+// isNotesFilename returns true if name is a notes filename in the notes directory. It prevents
+// restoring files outside of the notes directory.
+func isNotesFilename(name string) bool {
+	return filepath.Ext(name) == ".json" &&
+		filepath.Base(name) == name &&
+		!strings.HasPrefix(name, ".")
+}
+
+// ExportConfigBackup returns the current configuration and notes as one bundle.
+func (backend *Backend) ExportConfigBackup() (*ConfigBackup, error) {
+	allNotes, err := backend.allNotes()
+	if err != nil {
+		return nil, err
+	}
+	backup := &ConfigBackup{
+		Version:        configBackupVersion,
+		AppConfig:      backend.config.AppConfig(),
+		AccountsConfig: backend.config.AccountsConfig(),
+		Notes:          allNotes,
+	}
+	// The salt and hash of the app password would allow guessing the password offline.
+	backup.AppConfig.Backend.AppPassword = nil
+	backend.log.Infof("Exporting config backup with notes of %d accounts", len(backup.Notes))
+	return backup, nil
+}
+
+// RestoreConfigBackup replaces the app configuration and the accounts configuration with the ones
+// in the backup, and replaces the notes of the accounts in the backup. The notes of other accounts
+// are kept, as are the app password and the HWI path. Everything is checked before the first change
+// is applied. The accounts are reinitialized afterwards to load the restored configuration.
+func (backend *Backend) RestoreConfigBackup(backup *ConfigBackup) error {
+	if backup.Version < 1 || backup.Version > configBackupVersion {
+		return errp.Newf("unsupported config backup version: %d", backup.Version)
+	}
+	for name := range backup.Notes {
+		if !isNotesFilename(name) {
+			return errp.Newf("invalid notes filename: %s", name)
+		}
+	}
+	// The accounts config and the notes can't be written while the vault is locked.
+	if backend.vault.Locked() {
+		return errp.WithStack(vault.ErrLocked)
+	}
+	notesDir := backend.arguments.NotesDirectoryPath()
+	accountNotes := map[string]*notes.Notes{}
+	for name, data := range backup.Notes {
+		if data == nil {
+			continue
+		}
+		loaded, err := notes.LoadEncryptedNotes(filepath.Join(notesDir, name), backend.vault)
+		if err != nil {
+			return err
+		}
+		accountNotes[name] = loaded
+	}
+
+	backend.log.Infof("Restoring config backup with notes of %d accounts", len(backup.Notes))
+	previousAppConfig := backend.config.AppConfig()
+	appConfig := backup.AppConfig
+	// The app password is only changed with the /app-password endpoints.
+	appConfig.Backend.AppPassword = previousAppConfig.Backend.AppPassword
+	// The HWI executable is run by the backend, so a backup can't change it.
+	appConfig.Backend.HWIPath = previousAppConfig.Backend.HWIPath
+	if err := backend.config.SetAppConfig(appConfig); err != nil {
+		return err
+	}
+	err := backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		*accountsConfig = backup.AccountsConfig
+		return nil
+	})
+	if err != nil {
+		// Don't leave the backup half applied.
+		if restoreErr := backend.config.SetAppConfig(previousAppConfig); restoreErr != nil {
+			backend.log.WithError(restoreErr).Error("Could not restore the previous app config")
+		}
+		return err
+	}
+	for name, loaded := range accountNotes {
+		if err := loaded.SetData(backup.Notes[name]); err != nil {
+			return err
+		}
+	}
+	// Loaded accounts still hold the previous notes and configuration.
+	backend.ReinitializeAccounts()
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigBackup(t *testing.T) {
+	source := newBackend(t, testnetDisabled, regtestDisabled)
+	defer source.Close()
+
+	require.NoError(t, source.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		appConfig.Backend.MainFiat = "CHF"
+		return nil
+	}))
+	sourceNotes, err := notes.LoadNotes(filepath.Join(source.arguments.NotesDirectoryPath(), "v0-55555555-btc-0.json"))
+	require.NoError(t, err)
+	_, err = sourceNotes.SetTxNote("txid", "rent")
+	require.NoError(t, err)
+
+	require.NoError(t, source.EnableAppPassword("source password"))
+
+	backup, err := source.ExportConfigBackup()
+	require.NoError(t, err)
+	// The app password hash is not exported.
+	require.Nil(t, backup.AppConfig.Backend.AppPassword)
+	require.NotNil(t, source.config.AppConfig().Backend.AppPassword)
+	backupJSON, err := json.Marshal(backup)
+	require.NoError(t, err)
+
+	target := newBackend(t, testnetDisabled, regtestDisabled)
+	defer target.Close()
+	require.NoError(t, target.EnableAppPassword("target password"))
+	targetAppPassword := target.config.AppConfig().Backend.AppPassword
+	var restoredBackup ConfigBackup
+	require.NoError(t, json.Unmarshal(backupJSON, &restoredBackup))
+	restoredBackup.AppConfig.Backend.HWIPath = "/tmp/payload"
+	require.NoError(t, target.RestoreConfigBackup(&restoredBackup))
+
+	require.Equal(t, "CHF", target.config.AppConfig().Backend.MainFiat)
+	// The HWI executable and the app password can't be changed by a backup.
+	require.Empty(t, target.config.AppConfig().Backend.HWIPath)
+	require.Equal(t, targetAppPassword, target.config.AppConfig().Backend.AppPassword)
+	require.Equal(t, source.config.AccountsConfig(), target.config.AccountsConfig())
+	targetNotes, err := notes.LoadNotes(filepath.Join(target.arguments.NotesDirectoryPath(), "v0-55555555-btc-0.json"))
+	require.NoError(t, err)
+	require.Equal(t, "rent", targetNotes.TxNote("txid"))
+
+	// Notes can't be written outside of the notes directory.
+	restoredBackup.Notes["../config.json"] = &notes.Data{}
+	require.Error(t, target.RestoreConfigBackup(&restoredBackup))
+
+	restoredBackup = ConfigBackup{Version: configBackupVersion + 1}
+	require.Error(t, target.RestoreConfigBackup(&restoredBackup))
+
+	// Nothing is applied if the backup can't be restored completely.
+	require.NoError(t, json.Unmarshal(backupJSON, &restoredBackup))
+	restoredBackup.AppConfig.Backend.MainFiat = "EUR"
+	require.NoError(t, target.EnableConfigEncryption("passphrase"))
+	target.vault, err = vault.New(filepath.Join(target.arguments.MainDirectoryPath(), "encryption.json"))
+	require.NoError(t, err)
+	require.Equal(t, vault.ErrLocked, errp.Cause(target.RestoreConfigBackup(&restoredBackup)))
+	require.Equal(t, "CHF", target.config.AppConfig().Backend.MainFiat)
+}
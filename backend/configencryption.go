@@ -0,0 +1,135 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/arguments"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
+)
+
+// ConfigEncryptionStatus describes whether the accounts config, the notes and the certificates of
+// the Electrum servers are encrypted at rest.
+type ConfigEncryptionStatus struct {
+	Enabled bool `json:"enabled"`
+	// Locked is true if the encryption is enabled and the passphrase was not entered yet. Until
+	// then, no accounts are loaded.
+	Locked bool `json:"locked"`
+}
+
+// ConfigEncryptionStatus returns the current encryption status.
+func (backend *Backend) ConfigEncryptionStatus() ConfigEncryptionStatus {
+	return ConfigEncryptionStatus{
+		Enabled: backend.vault.Enabled(),
+		Locked:  backend.vault.Locked(),
+	}
+}
+
+// UnlockConfig unlocks the encrypted config with the passphrase and loads the accounts.
+func (backend *Backend) UnlockConfig(passphrase string) error {
+	if err := backend.vault.Unlock(passphrase); err != nil {
+		return err
+	}
+	if err := backend.config.DecryptServerCerts(); err != nil {
+		return err
+	}
+	if err := backend.config.ReloadAccountsConfig(); err != nil {
+		return err
+	}
+	backend.log.Info("Config unlocked")
+	backend.ReinitializeAccounts()
+	return nil
+}
+
+// allNotes reads the notes of all accounts, keyed by the notes filename.
+func (backend *Backend) allNotes() (map[string]*notes.Data, error) {
+	notesDir := backend.arguments.NotesDirectoryPath()
+	entries, err := os.ReadDir(notesDir)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	result := map[string]*notes.Data{}
+	for _, entry := range entries {
+		if entry.IsDir() || !isNotesFilename(entry.Name()) {
+			continue
+		}
+		accountNotes, err := notes.LoadEncryptedNotes(filepath.Join(notesDir, entry.Name()), backend.vault)
+		if err != nil {
+			return nil, err
+		}
+		result[entry.Name()] = accountNotes.Data()
+	}
+	return result, nil
+}
+
+// encryptedFilenames returns the files that are encrypted if the config encryption is enabled: the
+// accounts config and the notes of all accounts.
+func encryptedFilenames(arguments *arguments.Arguments) ([]string, error) {
+	filenames := []string{arguments.AccountsConfigFilename()}
+	notesDir := arguments.NotesDirectoryPath()
+	entries, err := os.ReadDir(notesDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errp.WithStack(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !isNotesFilename(entry.Name()) {
+			continue
+		}
+		filenames = append(filenames, filepath.Join(notesDir, entry.Name()))
+	}
+	return filenames, nil
+}
+
+// encryptedFiles returns the files rewritten when enabling or disabling the config encryption: the
+// app config, in which the certificates of the Electrum servers are encrypted, and the files of
+// encryptedFilenames().
+func (backend *Backend) encryptedFiles() ([]vault.File, error) {
+	filenames, err := encryptedFilenames(backend.arguments)
+	if err != nil {
+		return nil, err
+	}
+	files := []vault.File{backend.config.AppConfigFile()}
+	for _, filename := range filenames {
+		files = append(files, vault.File{Filename: filename})
+	}
+	return files, nil
+}
+
+// EnableConfigEncryption encrypts the accounts config, the notes of all accounts and the
+// certificates of the Electrum servers with the passphrase. The passphrase has to be entered after
+// each start to load the accounts.
+func (backend *Backend) EnableConfigEncryption(passphrase string) error {
+	files, err := backend.encryptedFiles()
+	if err != nil {
+		return err
+	}
+	backend.log.Infof("Encrypting %d config files", len(files))
+	return backend.vault.Enable(passphrase, files...)
+}
+
+// DisableConfigEncryption decrypts the accounts config, the notes of all accounts and the
+// certificates of the Electrum servers. The config must be unlocked.
+func (backend *Backend) DisableConfigEncryption(passphrase string) error {
+	files, err := backend.encryptedFiles()
+	if err != nil {
+		return err
+	}
+	backend.log.Infof("Decrypting %d config files", len(files))
+	return backend.vault.Disable(passphrase, files...)
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigEncryption(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	require.NoError(t, b.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		accountsConfig.Accounts = append(accountsConfig.Accounts, &config.Account{Name: "watch-only"})
+		return nil
+	}))
+	const pemCert = "-----BEGIN CERTIFICATE-----\ncustom\n-----END CERTIFICATE-----"
+	require.NoError(t, b.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		appConfig.Backend.BTC.ElectrumServers = []*config.ServerInfo{
+			{Server: "electrum.example.com:50002", TLS: true, PEMCert: pemCert},
+		}
+		return nil
+	}))
+	btcCert := func() string { return b.config.AppConfig().Backend.BTC.ElectrumServers[0].PEMCert }
+	notesFilename := filepath.Join(b.arguments.NotesDirectoryPath(), "v0-55555555-btc-0.json")
+	accountNotes, err := notes.LoadEncryptedNotes(notesFilename, b.vault)
+	require.NoError(t, err)
+	_, err = accountNotes.SetTxNote("txid", "rent")
+	require.NoError(t, err)
+
+	require.Equal(t, ConfigEncryptionStatus{}, b.ConfigEncryptionStatus())
+	// Notes written while the files are encrypted are not lost.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := accountNotes.SetTxNote("txid2", "groceries")
+		require.NoError(t, err)
+	}()
+	require.NoError(t, b.EnableConfigEncryption("passphrase"))
+	wg.Wait()
+	require.Equal(t, ConfigEncryptionStatus{Enabled: true}, b.ConfigEncryptionStatus())
+	accountNotes, err = notes.LoadEncryptedNotes(notesFilename, b.vault)
+	require.NoError(t, err)
+	require.Equal(t, "groceries", accountNotes.TxNote("txid2"))
+
+	for _, filename := range []string{b.arguments.AccountsConfigFilename(), notesFilename} {
+		raw, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		require.NotContains(t, string(raw), "watch-only")
+		require.NotContains(t, string(raw), "rent")
+	}
+	// Only the certificates are encrypted in the app config.
+	raw, err := os.ReadFile(b.arguments.AppConfigFilename())
+	require.NoError(t, err)
+	require.Contains(t, string(raw), "electrum.example.com:50002")
+	require.NotContains(t, string(raw), "custom")
+	require.Equal(t, "watch-only", b.config.AccountsConfig().Accounts[0].Name)
+	require.Equal(t, pemCert, btcCert())
+
+	// After a restart, the config is locked until the passphrase is entered.
+	filenames, err := encryptedFilenames(b.arguments)
+	require.NoError(t, err)
+	paramsFilename := filepath.Join(b.arguments.MainDirectoryPath(), "encryption.json")
+	b.vault, err = vault.New(paramsFilename, filenames...)
+	require.NoError(t, err)
+	b.config, err = config.NewConfig(
+		b.arguments.AppConfigFilename(), b.arguments.AccountsConfigFilename(), b.vault)
+	require.NoError(t, err)
+	require.Equal(t, ConfigEncryptionStatus{Enabled: true, Locked: true}, b.ConfigEncryptionStatus())
+	require.Empty(t, b.config.AccountsConfig().Accounts)
+	require.NotEqual(t, pemCert, btcCert())
+	require.Equal(t, vault.ErrLocked, b.config.ModifyAccountsConfig(
+		func(*config.AccountsConfig) error { return nil }))
+	require.Error(t, b.DisableConfigEncryption("passphrase"))
+
+	require.Equal(t, vault.ErrWrongPassphrase, b.UnlockConfig("wrong"))
+	require.NoError(t, b.UnlockConfig("passphrase"))
+	require.Equal(t, "watch-only", b.config.AccountsConfig().Accounts[0].Name)
+	require.Equal(t, pemCert, btcCert())
+
+	require.NoError(t, b.DisableConfigEncryption("passphrase"))
+	require.Equal(t, ConfigEncryptionStatus{}, b.ConfigEncryptionStatus())
+	raw, err = os.ReadFile(b.arguments.AppConfigFilename())
+	require.NoError(t, err)
+	require.Contains(t, string(raw), "custom")
+	accountNotes, err = notes.LoadNotes(notesFilename)
+	require.NoError(t, err)
+	require.Equal(t, "rent", accountNotes.TxNote("txid"))
+	require.Equal(t, "groceries", accountNotes.TxNote("txid2"))
+}
+
+func TestConfigEncryptionParamsMissing(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	require.NoError(t, b.EnableConfigEncryption("passphrase"))
+	paramsFilename := filepath.Join(b.arguments.MainDirectoryPath(), "encryption.json")
+	require.NoError(t, os.Remove(paramsFilename))
+
+	// The encrypted accounts config remains, so the encryption can't be turned off by deleting the
+	// params.
+	filenames, err := encryptedFilenames(b.arguments)
+	require.NoError(t, err)
+	b.vault, err = vault.New(paramsFilename, filenames...)
+	require.NoError(t, err)
+	require.Equal(t, ConfigEncryptionStatus{Enabled: true, Locked: true}, b.ConfigEncryptionStatus())
+	require.Equal(t, vault.ErrParamsMissing, b.UnlockConfig("passphrase"))
+}
+
+func TestConfigEncryptionPlaintextCert(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	require.NoError(t, b.EnableConfigEncryption("passphrase"))
+
+	// A certificate written to the app config in plaintext was not set by the app.
+	appConfig := b.config.AppConfig()
+	appConfig.Backend.BTC.ElectrumServers = []*config.ServerInfo{
+		{Server: "electrum.example.com:50002", TLS: true, PEMCert: "plaintext"},
+	}
+	jsonBytes, err := json.Marshal(appConfig)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(b.arguments.AppConfigFilename(), jsonBytes, 0600))
+
+	filenames, err := encryptedFilenames(b.arguments)
+	require.NoError(t, err)
+	b.vault, err = vault.New(filepath.Join(b.arguments.MainDirectoryPath(), "encryption.json"), filenames...)
+	require.NoError(t, err)
+	b.config, err = config.NewConfig(
+		b.arguments.AppConfigFilename(), b.arguments.AccountsConfigFilename(), b.vault)
+	require.NoError(t, err)
+	require.Equal(t, vault.ErrNotEncrypted, errp.Cause(b.UnlockConfig("passphrase")))
+}
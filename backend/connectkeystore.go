@@ -19,6 +19,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
@@ -100,6 +101,7 @@ func (c *connectKeystore) connect(
 	defer cancelTimeout()
 
 	go func() {
+		defer crashreport.Recover()
 		defer c.Lock()()
 		c.cancelFunc = cancel
 		c.connectKeystoreCallback = func(ks keystore.Keystore) {
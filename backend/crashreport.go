@@ -0,0 +1,72 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable/action"
+)
+
+// ReportCrash keeps a report of a crash for the user to review, if the user opted in to crash
+// reports. Nothing is sent until the user sends the report with SendCrashReport().
+func (backend *Backend) ReportCrash(message, stack string) {
+	if !backend.config.AppConfig().Backend.CrashReports {
+		return
+	}
+	report, err := backend.crashReports.Add(Version.String(), message, stack)
+	if err != nil {
+		backend.log.WithError(err).Error("Could not store crash report")
+		return
+	}
+	backend.log.Infof("Stored crash report %s", report.ID)
+	backend.Notify(observable.Event{
+		Subject: "crash-reports",
+		Action:  action.Reload,
+	})
+}
+
+// CrashReports returns the reports pending review.
+func (backend *Backend) CrashReports() []*crashreport.Report {
+	return backend.crashReports.Pending()
+}
+
+// SendCrashReport sends the report with the given ID to Shift Crypto and removes it.
+func (backend *Backend) SendCrashReport(id string) error {
+	if !backend.config.AppConfig().Backend.CrashReports {
+		return errp.New("crash reports are disabled")
+	}
+	if err := backend.crashReports.Send(backend.httpClient, crashreport.ReportURL, id); err != nil {
+		return err
+	}
+	backend.Notify(observable.Event{
+		Subject: "crash-reports",
+		Action:  action.Reload,
+	})
+	return nil
+}
+
+// DeleteCrashReport removes the report with the given ID without sending it.
+func (backend *Backend) DeleteCrashReport(id string) error {
+	if err := backend.crashReports.Delete(id); err != nil {
+		return err
+	}
+	backend.Notify(observable.Event{
+		Subject: "crash-reports",
+		Action:  action.Reload,
+	})
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crashreport keeps reports of crashes, which the user can review and then send to Shift
+// Crypto. Addresses and extended public keys are removed from the reports before they are stored.
+package crashreport
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// ReportURL is the endpoint the reports are sent to.
+const ReportURL = "https://bitboxapp.shiftcrypto.io/crash-report"
+
+// maxReports limits the number of pending reports. The oldest reports are dropped first.
+const maxReports = 10
+
+// scrubbed replaces the addresses and extended public keys in a report.
+const scrubbed = "<scrubbed>"
+
+var scrubPatterns = []*regexp.Regexp{
+	// Extended keys, e.g. xpub, zpub, tpub, vpub, Ltub and Mtub.
+	regexp.MustCompile(`\b[xyzXYZtuvUV]p(ub|rv)[1-9A-HJ-NP-Za-km-z]{100,112}\b`),
+	regexp.MustCompile(`\b[LM]t(ub|pv)[1-9A-HJ-NP-Za-km-z]{100,112}\b`),
+	// Bech32 addresses.
+	regexp.MustCompile(`(?i)\b(bc|tb|bcrt|ltc|tltc|rltc)1[02-9ac-hj-np-z]{6,87}\b`),
+	// Base58 addresses.
+	regexp.MustCompile(`\b[123mnLM][1-9A-HJ-NP-Za-km-z]{25,34}\b`),
+	// Ethereum addresses.
+	regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`),
+}
+
+var (
+	handler   func(message, stack string)
+	handlerMu sync.RWMutex
+)
+
+// SetHandler sets the function which is called with panics recovered by Recover. The handler must
+// store the report before it returns, as the app crashes afterwards.
+func SetHandler(f func(message, stack string)) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	handler = f
+}
+
+// Recover reports a panic of the calling goroutine to the handler set with SetHandler and then
+// panics again, so the app still crashes. Panics of background goroutines are not caught by the API
+// handlers. Use it as the first deferred call of a goroutine:
+//
+//	go func() {
+//		defer crashreport.Recover()
+//		...
+//	}()
+func Recover() {
+	panicValue := recover()
+	if panicValue == nil {
+		return
+	}
+	handlerMu.RLock()
+	f := handler
+	handlerMu.RUnlock()
+	if f != nil {
+		f(fmt.Sprint(panicValue), string(debug.Stack()))
+	}
+	panic(panicValue)
+}
+
+// Scrub removes addresses and extended public keys from text.
+func Scrub(text string) string {
+	for _, pattern := range scrubPatterns {
+		text = pattern.ReplaceAllString(text, scrubbed)
+	}
+	return text
+}
+
+// Report is a crash report.
+type Report struct {
+	// ID uniquely identifies the report. It is assigned when the report is added.
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Version string    `json:"version"`
+	OS      string    `json:"os"`
+	Arch    string    `json:"arch"`
+	Message string    `json:"message"`
+	Stack   string    `json:"stack"`
+}
+
+// Reports is a high level helper to read and modify the pending reports. All reports are kept in
+// RAM and written to the file on every change.
+type Reports struct {
+	filename string
+	reports  []*Report
+	mu       sync.RWMutex
+}
+
+// Load makes a new Reports instance, loading the pending reports from the file. If the file does
+// not exist, no error is returned.
+func Load(filename string) (*Reports, error) {
+	reports := []*Report{}
+	contents, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errp.WithStack(err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(contents, &reports); err != nil {
+			return nil, errp.WithStack(err)
+		}
+	}
+	return &Reports{filename: filename, reports: reports}, nil
+}
+
+// write must be called with mu held.
+func (reports *Reports) write() error {
+	contents, err := json.Marshal(reports.reports)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	return errp.WithStack(os.WriteFile(reports.filename, contents, 0600))
+}
+
+// Add stores a new report of a crash in the given app version. The message and stack are scrubbed.
+func (reports *Reports) Add(version, message, stack string) (*Report, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	report := &Report{
+		ID:      hex.EncodeToString(id),
+		Time:    time.Now().UTC(),
+		Version: version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Message: Scrub(message),
+		Stack:   Scrub(stack),
+	}
+	reports.mu.Lock()
+	defer reports.mu.Unlock()
+	reports.reports = append(reports.reports, report)
+	if len(reports.reports) > maxReports {
+		reports.reports = reports.reports[len(reports.reports)-maxReports:]
+	}
+	return report, reports.write()
+}
+
+// Pending returns the reports which were not sent or deleted yet, oldest first.
+func (reports *Reports) Pending() []*Report {
+	reports.mu.RLock()
+	defer reports.mu.RUnlock()
+	return append([]*Report{}, reports.reports...)
+}
+
+func (reports *Reports) lookup(id string) *Report {
+	reports.mu.RLock()
+	defer reports.mu.RUnlock()
+	for _, report := range reports.reports {
+		if report.ID == id {
+			return report
+		}
+	}
+	return nil
+}
+
+// Delete removes the report with the given ID.
+func (reports *Reports) Delete(id string) error {
+	reports.mu.Lock()
+	defer reports.mu.Unlock()
+	for i, report := range reports.reports {
+		if report.ID == id {
+			reports.reports = append(reports.reports[:i], reports.reports[i+1:]...)
+			return reports.write()
+		}
+	}
+	return errp.Newf("report %s not found", id)
+}
+
+// Send uploads the report with the given ID to url and deletes it afterwards.
+func (reports *Reports) Send(client *http.Client, url string, id string) error {
+	report := reports.lookup(id)
+	if report == nil {
+		return errp.Newf("report %s not found", id)
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	response, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return errp.Newf("sending the report failed with status %d", response.StatusCode)
+	}
+	return reports.Delete(id)
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crashreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrub(t *testing.T) {
+	for _, secret := range []string{
+		"xpub6CUGRUonZSQ4TWtTMmzXdrXDtypWKiKrhko4egpiMZbpiaQL2jkwSB1icqYh2cfDfVxdx4df189oLKnC5fSwqPfgyP3hooxujYzAu3fDVmz",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+	} {
+		text := fmt.Sprintf("panic: invalid address %s in account", secret)
+		require.Equal(t, "panic: invalid address <scrubbed> in account", Scrub(text))
+	}
+	stack := "github.com/BitBoxSwiss/bitbox-wallet-app/backend.(*Backend).Accounts(0xc000123456)"
+	require.Equal(t, stack, Scrub(stack))
+}
+
+func TestReports(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "crashreports.json")
+	reports, err := Load(filename)
+	require.NoError(t, err)
+	require.Empty(t, reports.Pending())
+
+	report, err := reports.Add("4.47.1", "panic: bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "stack")
+	require.NoError(t, err)
+	require.Equal(t, "panic: <scrubbed>", report.Message)
+
+	// The reports are persisted.
+	reports, err = Load(filename)
+	require.NoError(t, err)
+	require.Len(t, reports.Pending(), 1)
+	require.Equal(t, report.ID, reports.Pending()[0].ID)
+
+	for i := 0; i < maxReports; i++ {
+		_, err := reports.Add("4.47.1", fmt.Sprintf("panic %d", i), "stack")
+		require.NoError(t, err)
+	}
+	pending := reports.Pending()
+	require.Len(t, pending, maxReports)
+	require.Equal(t, "panic 0", pending[0].Message)
+
+	var received Report
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	status = http.StatusInternalServerError
+	require.Error(t, reports.Send(server.Client(), server.URL, pending[0].ID))
+	require.Len(t, reports.Pending(), maxReports)
+
+	status = http.StatusOK
+	require.NoError(t, reports.Send(server.Client(), server.URL, pending[0].ID))
+	require.Equal(t, *pending[0], received)
+	require.Len(t, reports.Pending(), maxReports-1)
+	require.Error(t, reports.Send(server.Client(), server.URL, pending[0].ID))
+
+	require.NoError(t, reports.Delete(pending[1].ID))
+	require.Len(t, reports.Pending(), maxReports-2)
+	require.Error(t, reports.Delete(pending[1].ID))
+}
+
+func TestRecover(t *testing.T) {
+	var message, stack string
+	SetHandler(func(m, s string) {
+		message, stack = m, s
+	})
+	defer SetHandler(nil)
+
+	done := make(chan interface{})
+	go func() {
+		defer func() { done <- recover() }()
+		defer Recover()
+		panic("verification failed")
+	}()
+	// The panic is passed on after it has been reported.
+	require.Equal(t, "verification failed", <-done)
+	require.Equal(t, "verification failed", message)
+	require.Contains(t, stack, "TestRecover")
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCrash(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	// Without opt-in, nothing is kept.
+	b.ReportCrash("boom", "stack")
+	require.Empty(t, b.CrashReports())
+
+	require.NoError(t, b.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		appConfig.Backend.CrashReports = true
+		return nil
+	}))
+	b.ReportCrash("boom", "stack")
+	reports := b.CrashReports()
+	require.Len(t, reports, 1)
+	require.Equal(t, "boom", reports[0].Message)
+	require.Equal(t, Version.String(), reports[0].Version)
+
+	require.NoError(t, b.DeleteCrashReport(reports[0].ID))
+	require.Empty(t, b.CrashReports())
+}
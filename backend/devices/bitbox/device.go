@@ -30,6 +30,7 @@ import (
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox/relay"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device/event"
 	keystoreInterface "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
@@ -1298,6 +1299,7 @@ func (dbb *Device) PingMobile() error {
 //
 // It is run in a separate goroutine in NewDevice and dbb.FinishPairing.
 func (dbb *Device) listenForMobile() {
+	defer crashreport.Recover()
 	for {
 		dbb.mu.RLock()
 		ok := !dbb.closed && dbb.channel != nil
@@ -17,6 +17,7 @@ package bitbox
 import (
 	"time"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox/relay"
 )
 
@@ -60,6 +61,7 @@ func (device *Device) handlePairingError(err error, message string) {
 
 // processPairing processes the pairing after the channel has been displayed as a QR code.
 func (device *Device) processPairing(channel *relay.Channel) {
+	defer crashreport.Recover()
 	status, err := channel.WaitForScanningSuccess(2 * time.Minute)
 	if err != nil {
 		device.handlePairingError(err, "scanning success message")
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	event "github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device/event"
 	keystoreInterface "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
@@ -75,6 +76,16 @@ func NewDevice(
 	device.Device.SetOnEvent(func(ev firmware.Event, meta interface{}) {
 		device.fireEvent(event.Event(ev))
 		switch ev {
+		case firmware.EventAttestationCheckDone:
+			status := device.AttestationStatus()
+			if status == AttestationFailed {
+				log.Warning("Attestation check failed. The device might not be genuine.")
+			}
+			device.Notify(observable.Event{
+				Subject: fmt.Sprintf("devices/bitbox02/%s/attestation-status", device.deviceID),
+				Action:  action.Replace,
+				Object:  status,
+			})
 		case firmware.EventStatusChanged:
 			switch device.Device.Status() {
 			case firmware.StatusInitialized:
@@ -85,6 +96,36 @@ func NewDevice(
 	return device
 }
 
+// AttestationStatus is the result of the attestation check, which verifies that the device was
+// produced by Shift Crypto.
+type AttestationStatus string
+
+const (
+	// AttestationGenuine means the device passed the attestation check.
+	AttestationGenuine AttestationStatus = "genuine"
+	// AttestationUnknown means the attestation check has not been completed (yet).
+	AttestationUnknown AttestationStatus = "unknown"
+	// AttestationFailed means the device failed the attestation check and might be counterfeit.
+	AttestationFailed AttestationStatus = "failed"
+)
+
+func attestationStatus(attestation *bool) AttestationStatus {
+	switch {
+	case attestation == nil:
+		return AttestationUnknown
+	case *attestation:
+		return AttestationGenuine
+	default:
+		return AttestationFailed
+	}
+}
+
+// AttestationStatus returns the result of the attestation check performed when the device is
+// connected. A change is notified on the `devices/bitbox02/<deviceID>/attestation-status` subject.
+func (device *Device) AttestationStatus() AttestationStatus {
+	return attestationStatus(device.Device.Attestation())
+}
+
 // Init implements device.Device.
 func (device *Device) Init(testing bool) error {
 	device.init()
@@ -93,6 +134,7 @@ func (device *Device) Init(testing bool) error {
 
 func (device *Device) init() {
 	go func() {
+		defer crashreport.Recover()
 		if err := device.Device.Init(); err != nil {
 			device.log.Error("unknown IO error (most likely the device was unplugged)", err)
 		}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02bootloader"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	bitbox02common "github.com/BitBoxSwiss/bitbox02-api-go/api/common"
@@ -48,6 +49,7 @@ type BitBox02 interface {
 	SetMnemonicPassphraseEnabled(bool) error
 	UpgradeFirmware() error
 	Attestation() *bool
+	AttestationStatus() bitbox02.AttestationStatus
 	Reset() error
 	ShowMnemonic() error
 	RestoreFromMnemonic() error
@@ -72,6 +74,7 @@ func NewHandlers(
 
 	handleFunc("/status", handlers.getStatusHandler).Methods("GET")
 	handleFunc("/attestation", handlers.getAttestationHandler).Methods("GET")
+	handleFunc("/attestation-status", handlers.getAttestationStatusHandler).Methods("GET")
 	handleFunc("/channel-hash", handlers.getChannelHash).Methods("GET")
 	handleFunc("/channel-hash-verify", handlers.postChannelHashVerify).Methods("POST")
 	handleFunc("/info", handlers.getDeviceInfo).Methods("GET")
@@ -131,6 +134,10 @@ func (handlers *Handlers) getAttestationHandler(_ *http.Request) interface{} {
 	return handlers.device.Attestation()
 }
 
+func (handlers *Handlers) getAttestationStatusHandler(_ *http.Request) interface{} {
+	return handlers.device.AttestationStatus()
+}
+
 func (handlers *Handlers) getDeviceInfo(_ *http.Request) interface{} {
 	handlers.log.Debug("Get Device Info")
 	deviceInfo, err := handlers.device.DeviceInfo()
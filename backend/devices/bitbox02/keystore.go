@@ -17,7 +17,9 @@ package bitbox02
 
 import (
 	"bytes"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
@@ -108,12 +110,21 @@ func (keystore *keystore) SupportsAccount(coin coinpkg.Coin, meta interface{}) b
 		if scriptType == signing.ScriptTypeP2TR {
 			// Taproot available since v9.10.0.
 			switch coin.Code() {
-			case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeRBTC:
+			case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC:
 				return keystore.device.Version().AtLeast(semver.NewSemVer(9, 10, 0))
 			default:
 				return false
 			}
 		}
+		if scriptType == signing.ScriptTypeP2WSH {
+			// Multisig is only available for Bitcoin.
+			switch coin.Code() {
+			case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC:
+				return true
+			default:
+				return false
+			}
+		}
 		return scriptType != signing.ScriptTypeP2PKH
 	default:
 		return true
@@ -155,14 +166,23 @@ func (keystore *keystore) VerifyAddress(
 	}
 	switch specificCoin := coin.(type) {
 	case *btc.Coin:
-		msgScriptType, ok := btcMsgScriptTypeMap[configuration.ScriptType()]
-		if !ok {
-			panic("unsupported scripttype")
+		msgCoin := btcMsgCoinMap[coin.Code()]
+		scriptConfig, _, err := btcScriptConfig(configuration)
+		if err != nil {
+			return err
+		}
+		if configuration.BitcoinMultisig != nil {
+			// The account keypath is the address keypath without the change and address index.
+			keypath := configuration.AbsoluteKeypath().ToUInt32()
+			if err := keystore.ensureScriptConfigRegistered(
+				msgCoin, scriptConfig, keypath[:len(keypath)-2]); err != nil {
+				return err
+			}
 		}
 		_, err = keystore.device.BTCAddress(
-			btcMsgCoinMap[coin.Code()],
+			msgCoin,
 			configuration.AbsoluteKeypath().ToUInt32(),
-			firmware.NewBTCScriptConfigSimple(msgScriptType),
+			scriptConfig,
 			true,
 		)
 		if firmware.IsErrorAbort(err) {
@@ -291,22 +311,80 @@ func (keystore *keystore) ExtendedPublicKey(
 	}
 }
 
+// btcScriptConfig returns the script config of an account signing configuration, along with a
+// key identifying it. Two signing configurations with the same key result in the same script
+// config.
+func btcScriptConfig(configuration *signing.Configuration) (*messages.BTCScriptConfig, string, error) {
+	if multisig := configuration.BitcoinMultisig; multisig != nil {
+		xpubs := make([]string, len(multisig.Cosigners))
+		for i, cosigner := range multisig.Cosigners {
+			xpubs[i] = cosigner.ExtendedPublicKey.String()
+		}
+		scriptConfig, err := firmware.NewBTCScriptConfigMultisig(
+			multisig.Threshold, xpubs, multisig.OurXPubIndex)
+		if err != nil {
+			return nil, "", errp.WithStack(err)
+		}
+		key := fmt.Sprintf("multisig;%d;%d;%s",
+			multisig.Threshold, multisig.OurXPubIndex, strings.Join(xpubs, ","))
+		return scriptConfig, key, nil
+	}
+	msgScriptType, ok := btcMsgScriptTypeMap[configuration.ScriptType()]
+	if !ok {
+		return nil, "", errp.Newf("Unsupported script type %s", configuration.ScriptType())
+	}
+	return firmware.NewBTCScriptConfigSimple(msgScriptType), string(configuration.ScriptType()), nil
+}
+
+// ensureScriptConfigRegistered registers a multisig script config on the device if it is not
+// registered yet. The user has to confirm the registration on the device.
+func (keystore *keystore) ensureScriptConfigRegistered(
+	msgCoin messages.BTCCoin,
+	scriptConfig *messages.BTCScriptConfig,
+	keypathAccount []uint32,
+) error {
+	registered, err := keystore.device.BTCIsScriptConfigRegistered(
+		msgCoin, scriptConfig, keypathAccount)
+	if err != nil {
+		return err
+	}
+	if registered {
+		return nil
+	}
+	// With an empty name, the user is asked to enter a name on the device.
+	err = keystore.device.BTCRegisterScriptConfig(msgCoin, scriptConfig, keypathAccount, "")
+	if firmware.IsErrorAbort(err) {
+		return errp.WithStack(keystorePkg.ErrSigningAborted)
+	}
+	return err
+}
+
 func (keystore *keystore) signBTCTransaction(btcProposedTx *btc.ProposedTransaction) error {
 	tx := btcProposedTx.TXProposal.Transaction
 
 	// scriptConfigs represent the script configurations of a specific account and include the
 	// script type (e.g. p2wpkh, p2tr..) and the account keypath
 	scriptConfigs := []*messages.BTCScriptConfigWithKeypath{}
-	// addScriptConfig returns the index of the scriptConfig in scriptConfigs, adding it if it isn't
-	// present. Must be a Simple configuration.
-	addScriptConfig := func(scriptConfig *messages.BTCScriptConfigWithKeypath) int {
-		for i, sc := range scriptConfigs {
-			if sc.ScriptConfig.Config.(*messages.BTCScriptConfig_SimpleType_).SimpleType == scriptConfig.ScriptConfig.Config.(*messages.BTCScriptConfig_SimpleType_).SimpleType {
-				return i
+	// scriptConfigKeys contains the key of each entry in scriptConfigs, see `btcScriptConfig()`.
+	scriptConfigKeys := []string{}
+	// addScriptConfig returns the index of the account's scriptConfig in scriptConfigs, adding it if
+	// it isn't present.
+	addScriptConfig := func(accountConfiguration *signing.Configuration) (int, error) {
+		scriptConfig, key, err := btcScriptConfig(accountConfiguration)
+		if err != nil {
+			return 0, err
+		}
+		for i, scKey := range scriptConfigKeys {
+			if scKey == key {
+				return i, nil
 			}
 		}
-		scriptConfigs = append(scriptConfigs, scriptConfig)
-		return len(scriptConfigs) - 1
+		scriptConfigs = append(scriptConfigs, &messages.BTCScriptConfigWithKeypath{
+			ScriptConfig: scriptConfig,
+			Keypath:      accountConfiguration.AbsoluteKeypath().ToUInt32(),
+		})
+		scriptConfigKeys = append(scriptConfigKeys, key)
+		return len(scriptConfigs) - 1, nil
 	}
 
 	coin := btcProposedTx.TXProposal.Coin.(*btc.Coin)
@@ -325,16 +403,14 @@ func (keystore *keystore) signBTCTransaction(btcProposedTx *btc.ProposedTransact
 		}
 
 		inputAddress := prevOut.Address
+		if inputAddress == nil {
+			return errp.New("The BitBox02 can only sign transactions spending inputs of the account.")
+		}
 
-		accountConfiguration := inputAddress.AccountConfiguration
-		msgScriptType, ok := btcMsgScriptTypeMap[accountConfiguration.ScriptType()]
-		if !ok {
-			return errp.Newf("Unsupported script type %s", accountConfiguration.ScriptType())
+		scriptConfigIndex, err := addScriptConfig(inputAddress.AccountConfiguration)
+		if err != nil {
+			return err
 		}
-		scriptConfigIndex := addScriptConfig(&messages.BTCScriptConfigWithKeypath{
-			ScriptConfig: firmware.NewBTCScriptConfigSimple(msgScriptType),
-			Keypath:      accountConfiguration.AbsoluteKeypath().ToUInt32(),
-		})
 
 		var bip352Pubkey []byte
 		if btcProposedTx.TXProposal.SilentPaymentAddress != "" {
@@ -413,15 +489,11 @@ func (keystore *keystore) signBTCTransaction(btcProposedTx *btc.ProposedTransact
 		var scriptConfigIndex int
 		if isOurs {
 			keypath = outputAccountAddress.Configuration.AbsoluteKeypath().ToUInt32()
-			accountConfiguration := outputAccountAddress.AccountConfiguration
-			msgScriptType, ok := btcMsgScriptTypeMap[accountConfiguration.ScriptType()]
-			if !ok {
-				return errp.Newf("Unsupported script type %s", accountConfiguration.ScriptType())
+			var err error
+			scriptConfigIndex, err = addScriptConfig(outputAccountAddress.AccountConfiguration)
+			if err != nil {
+				return err
 			}
-			scriptConfigIndex = addScriptConfig(&messages.BTCScriptConfigWithKeypath{
-				ScriptConfig: firmware.NewBTCScriptConfigSimple(msgScriptType),
-				Keypath:      accountConfiguration.AbsoluteKeypath().ToUInt32(),
-			})
 		}
 		outputs[index] = &messages.BTCSignOutputRequest{
 			Ours:              isOurs,
@@ -484,6 +556,16 @@ func (keystore *keystore) signBTCTransaction(btcProposedTx *btc.ProposedTransact
 		outputs[btcProposedTx.TXProposal.OutIndex].PaymentRequestIndex = &prIndex
 	}
 
+	// Multisig accounts need to be registered on the device before they can be used for signing.
+	for _, scriptConfig := range scriptConfigs {
+		if _, ok := scriptConfig.ScriptConfig.Config.(*messages.BTCScriptConfig_Multisig_); ok {
+			if err := keystore.ensureScriptConfigRegistered(
+				msgCoin, scriptConfig.ScriptConfig, scriptConfig.Keypath); err != nil {
+				return err
+			}
+		}
+	}
+
 	signatures, generatedOutputs, err := keystore.device.BTCSign(
 		msgCoin,
 		scriptConfigs,
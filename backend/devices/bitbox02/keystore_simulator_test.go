@@ -390,6 +390,7 @@ func makeTx(t *testing.T, device *Device, recipient *maketx.OutputInfo) *btc.Pro
 		outputAmount,
 		feePerKb,
 		changeAddress,
+		maketx.CoinSelectionLargestFirst,
 		log,
 	)
 	require.NoError(t, err)
@@ -26,6 +26,8 @@ import (
 var btcMsgCoinMap = map[coinpkg.Code]messages.BTCCoin{
 	coinpkg.CodeBTC:  messages.BTCCoin_BTC,
 	coinpkg.CodeTBTC: messages.BTCCoin_TBTC,
+	// Signet uses the same coin type and address format as testnet.
+	coinpkg.CodeTSIG: messages.BTCCoin_TBTC,
 	coinpkg.CodeLTC:  messages.BTCCoin_LTC,
 	coinpkg.CodeTLTC: messages.BTCCoin_TLTC,
 }
@@ -20,8 +20,10 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"sync"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device/event"
 	keystoreInterface "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
@@ -108,6 +110,7 @@ func (device *Device) Init(testing bool) error {
 		if fwInfo.monotonicVersion+1 == currentFirmwareVersion {
 			device.log.Infof("continuing upgrade on %d", currentFirmwareVersion)
 			go func() {
+				defer crashreport.Recover()
 				if err := device.UpgradeFirmware(); err != nil {
 					device.log.WithError(err).Error("upgrade continuation failed")
 				}
@@ -223,6 +226,73 @@ func (device *Device) UpgradeFirmware() error {
 	return device.Device.UpgradeFirmware(signedBinary)
 }
 
+// LatestReleaseInfo contains information about the latest published firmware release.
+type LatestReleaseInfo struct {
+	Release *FirmwareRelease `json:"release"`
+	// CanUpgrade is true if the release is newer than the installed firmware.
+	CanUpgrade bool `json:"canUpgrade"`
+}
+
+// LatestRelease checks the installed firmware version against the latest published release. The
+// client should route through the configured proxy.
+func (device *Device) LatestRelease(client *http.Client) (*LatestReleaseInfo, error) {
+	release, err := fetchLatestRelease(client, firmwareReleasesURL, device.Device.Product())
+	if err != nil {
+		return nil, err
+	}
+	erased, err := device.Device.Erased()
+	if err != nil {
+		return nil, err
+	}
+	currentFirmwareVersion, _, err := device.Device.Versions()
+	if err != nil {
+		return nil, err
+	}
+	return &LatestReleaseInfo{
+		Release:    release,
+		CanUpgrade: erased || release.MonotonicVersion > currentFirmwareVersion,
+	}, nil
+}
+
+// UpgradeFirmwareToLatestRelease downloads the latest published firmware release and uploads it to
+// the device. Progress is reported via the status events, like for the bundled firmware. Pending
+// intermediate upgrades are only bundled, so they are performed first using UpgradeFirmware(). The
+// same applies if the bundled firmware is not older than the latest release.
+func (device *Device) UpgradeFirmwareToLatestRelease(client *http.Client) error {
+	product := device.Device.Product()
+
+	firmwareBootRequired, err := device.firmwareBootRequired()
+	if err != nil {
+		return err
+	}
+	nextFw, err := device.nextFirmware()
+	if err != nil {
+		return err
+	}
+	latestFw, err := bundledFirmware(product)
+	if err != nil {
+		return err
+	}
+	if firmwareBootRequired || nextFw.monotonicVersion < latestFw.monotonicVersion {
+		return device.UpgradeFirmware()
+	}
+
+	release, err := fetchLatestRelease(client, firmwareReleasesURL, product)
+	if err != nil {
+		return err
+	}
+	if release.MonotonicVersion <= latestFw.monotonicVersion {
+		return device.UpgradeFirmware()
+	}
+	device.log.Infof("downloading firmware: %s, %s", product, release.Version)
+	signedBinary, err := release.download(client, product)
+	if err != nil {
+		return err
+	}
+	device.log.Infof("upgrading firmware: %s, %s", product, release.Version)
+	return device.Device.UpgradeFirmware(signedBinary)
+}
+
 // VersionInfo contains version information about the upgrade.
 type VersionInfo struct {
 	Erased     bool `json:"erased"`
@@ -29,6 +29,8 @@ import (
 type BitBox02Bootloader interface {
 	Status() *bootloader.Status
 	UpgradeFirmware() error
+	LatestRelease(client *http.Client) (*bitbox02bootloader.LatestReleaseInfo, error)
+	UpgradeFirmwareToLatestRelease(client *http.Client) error
 	Reboot() error
 	ShowFirmwareHashEnabled() (bool, error)
 	SetShowFirmwareHashEnabled(bool) error
@@ -39,18 +41,26 @@ type BitBox02Bootloader interface {
 // Handlers provides a web API to the Bitbox.
 type Handlers struct {
 	device BitBox02Bootloader
-	log    *logrus.Entry
+	// httpClient is used to download firmware releases.
+	httpClient *http.Client
+	log        *logrus.Entry
 }
 
 // NewHandlers creates a new Handlers instance.
 func NewHandlers(
 	handleFunc func(string, func(*http.Request) (interface{}, error)) *mux.Route,
+	httpClient *http.Client,
 	log *logrus.Entry,
 ) *Handlers {
-	handlers := &Handlers{log: log.WithField("device", "bitbox02-bootloader")}
+	handlers := &Handlers{
+		httpClient: httpClient,
+		log:        log.WithField("device", "bitbox02-bootloader"),
+	}
 
 	handleFunc("/status", handlers.getStatusHandler).Methods("GET")
 	handleFunc("/upgrade-firmware", handlers.postUpgradeFirmwareHandler).Methods("POST")
+	handleFunc("/latest-release", handlers.getLatestReleaseHandler).Methods("GET")
+	handleFunc("/upgrade-firmware-latest-release", handlers.postUpgradeFirmwareLatestReleaseHandler).Methods("POST")
 	handleFunc("/reboot", handlers.postRebootHandler).Methods("POST")
 	handleFunc("/show-firmware-hash-enabled", handlers.getShowFirmwareHashEnabledHandler).Methods("GET")
 	handleFunc("/set-firmware-hash-enabled", handlers.postSetShowFirmwareHashEnabledHandler).Methods("POST")
@@ -81,6 +91,14 @@ func (handlers *Handlers) postUpgradeFirmwareHandler(_ *http.Request) (interface
 	return nil, handlers.device.UpgradeFirmware()
 }
 
+func (handlers *Handlers) getLatestReleaseHandler(_ *http.Request) (interface{}, error) {
+	return handlers.device.LatestRelease(handlers.httpClient)
+}
+
+func (handlers *Handlers) postUpgradeFirmwareLatestReleaseHandler(_ *http.Request) (interface{}, error) {
+	return nil, handlers.device.UpgradeFirmwareToLatestRelease(handlers.httpClient)
+}
+
 func (handlers *Handlers) postRebootHandler(_ *http.Request) (interface{}, error) {
 	return nil, handlers.device.Reboot()
 }
@@ -0,0 +1,123 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbox02bootloader
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox02-api-go/api/bootloader"
+	bitbox02common "github.com/BitBoxSwiss/bitbox02-api-go/api/common"
+	"github.com/BitBoxSwiss/bitbox02-api-go/util/semver"
+)
+
+// firmwareReleasesURL points to the index of the latest signed firmware release of each product.
+const firmwareReleasesURL = "https://bitboxapp.shiftcrypto.io/bitbox02-firmware.json"
+
+// maxFirmwareSize limits the download size. The firmware flash area is smaller than this.
+const maxFirmwareSize = 2 * 1024 * 1024
+
+// sigDataVersionOffset is the offset of the monotonic firmware version in the signature data of a
+// signed binary. The bitbox02-api-go lib only exposes parsing it with a connected device.
+const sigDataVersionOffset = 388
+
+// FirmwareRelease describes a signed firmware release which can be downloaded.
+type FirmwareRelease struct {
+	Version          *semver.SemVer `json:"version"`
+	MonotonicVersion uint32         `json:"monotonicVersion"`
+	// URL is the location of the signed binary.
+	URL string `json:"url"`
+	// SHA256 is the hex encoded hash of the signed binary.
+	SHA256 string `json:"sha256"`
+}
+
+func httpGet(client *http.Client, url string) (*http.Response, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, errp.Newf("expected 200 OK, got %d", response.StatusCode)
+	}
+	return response, nil
+}
+
+// fetchLatestRelease retrieves the latest firmware release of the given product from the releases
+// index.
+func fetchLatestRelease(
+	client *http.Client, url string, product bitbox02common.Product) (*FirmwareRelease, error) {
+	response, err := httpGet(client, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	var releases map[bitbox02common.Product]*FirmwareRelease
+	if err := json.NewDecoder(response.Body).Decode(&releases); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	release, ok := releases[product]
+	if !ok || release == nil || release.Version == nil {
+		return nil, errp.Newf("no firmware release found for %s", product)
+	}
+	return release, nil
+}
+
+// download fetches the signed binary of the release and checks that it matches the published hash,
+// the given product and the published monotonic version. The signature itself is verified by the
+// bootloader when flashing.
+func (release *FirmwareRelease) download(
+	client *http.Client, product bitbox02common.Product) ([]byte, error) {
+	response, err := httpGet(client, release.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	signedBinary, err := io.ReadAll(io.LimitReader(response.Body, maxFirmwareSize+1))
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if len(signedBinary) > maxFirmwareSize {
+		return nil, errp.New("firmware binary too large")
+	}
+	hash := sha256.Sum256(signedBinary)
+	if hex.EncodeToString(hash[:]) != release.SHA256 {
+		return nil, errp.New("firmware binary hash mismatch")
+	}
+	binaryProduct, sigData, _, err := bootloader.ParseSignedFirmware(signedBinary)
+	if err != nil {
+		return nil, err
+	}
+	if binaryProduct != product {
+		return nil, errp.Newf("firmware binary is for %s, expected %s", binaryProduct, product)
+	}
+	// The upgrade decision is based on the published monotonic version, so it has to match the one
+	// the bootloader sees.
+	binaryVersion := binary.LittleEndian.Uint32(sigData[sigDataVersionOffset:][:4])
+	if binaryVersion != release.MonotonicVersion {
+		return nil, errp.Newf("firmware binary has monotonic version %d, expected %d",
+			binaryVersion, release.MonotonicVersion)
+	}
+	return signedBinary, nil
+}
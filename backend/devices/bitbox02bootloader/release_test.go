@@ -0,0 +1,79 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbox02bootloader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bitbox02common "github.com/BitBoxSwiss/bitbox02-api-go/api/common"
+	"github.com/BitBoxSwiss/bitbox02-api-go/util/semver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestRelease(t *testing.T) {
+	fw, err := bundledFirmware(bitbox02common.ProductBitBox02Multi)
+	require.NoError(t, err)
+	signedBinary, err := fw.signedBinary()
+	require.NoError(t, err)
+	hash := sha256.Sum256(signedBinary)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/releases.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"bitbox02-multi": {"version": "9.22.0", "monotonicVersion": 42, "url": "%s/firmware.bin", "sha256": "%s"}}`,
+			server.URL, hex.EncodeToString(hash[:]))
+	})
+	mux.HandleFunc("/firmware.bin", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(signedBinary)
+	})
+
+	release, err := fetchLatestRelease(server.Client(), server.URL+"/releases.json", bitbox02common.ProductBitBox02Multi)
+	require.NoError(t, err)
+	require.Equal(t, semver.NewSemVer(9, 22, 0), release.Version)
+	require.Equal(t, uint32(42), release.MonotonicVersion)
+
+	downloaded, err := release.download(server.Client(), bitbox02common.ProductBitBox02Multi)
+	require.NoError(t, err)
+	require.Equal(t, signedBinary, downloaded)
+
+	// Binary for a different product.
+	_, err = release.download(server.Client(), bitbox02common.ProductBitBox02BTCOnly)
+	require.Error(t, err)
+
+	// Monotonic version mismatch.
+	release.MonotonicVersion = 43
+	_, err = release.download(server.Client(), bitbox02common.ProductBitBox02Multi)
+	require.Error(t, err)
+	release.MonotonicVersion = 42
+
+	// Hash mismatch.
+	release.SHA256 = hex.EncodeToString(make([]byte, 32))
+	_, err = release.download(server.Client(), bitbox02common.ProductBitBox02Multi)
+	require.Error(t, err)
+
+	// No release for the product.
+	_, err = fetchLatestRelease(server.Client(), server.URL+"/releases.json", bitbox02common.ProductBitBox02BTCOnly)
+	require.Error(t, err)
+
+	// Missing index.
+	_, err = fetchLatestRelease(server.Client(), server.URL+"/missing.json", bitbox02common.ProductBitBox02Multi)
+	require.Error(t, err)
+}
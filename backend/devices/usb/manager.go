@@ -20,6 +20,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02bootloader"
@@ -272,6 +273,7 @@ func (manager *Manager) Update() {
 }
 
 func (manager *Manager) listen() {
+	defer crashreport.Recover()
 	for {
 		select {
 		case <-manager.quitCh:
@@ -15,6 +15,7 @@
 package exchanges
 
 import (
+	"context"
 	"net/http"
 	"slices"
 
@@ -132,14 +133,15 @@ type ExchangeDealsList struct {
 // For each region, an exchange is enabled if it supports the account coin and it is active in that region.
 // NOTE: if one of the endpoint fails for any reason, the related exchange will be set as available in any
 // region by default (for the supported coins).
-func ListExchangesByRegion(account accounts.Interface, httpClient *http.Client) ExchangeRegionList {
-	moonpayRegions, moonpayError := GetMoonpaySupportedRegions(httpClient)
+func ListExchangesByRegion(
+	ctx context.Context, account accounts.Interface, httpClient *http.Client) ExchangeRegionList {
+	moonpayRegions, moonpayError := GetMoonpaySupportedRegions(ctx, httpClient)
 	log := logging.Get().WithGroup("exchanges")
 	if moonpayError != nil {
 		log.Error(moonpayError)
 	}
 
-	pocketRegions, pocketError := GetPocketSupportedRegions(httpClient)
+	pocketRegions, pocketError := GetPocketSupportedRegions(ctx, httpClient)
 	if pocketError != nil {
 		log.Error(pocketError)
 	}
@@ -174,7 +176,7 @@ func ListExchangesByRegion(account accounts.Interface, httpClient *http.Client)
 }
 
 // GetExchangeDeals returns the exchange deals available for the specified account, region and action.
-func GetExchangeDeals(account accounts.Interface, regionCode string, action ExchangeAction, httpClient *http.Client) ([]*ExchangeDealsList, error) {
+func GetExchangeDeals(ctx context.Context, account accounts.Interface, regionCode string, action ExchangeAction, httpClient *http.Client) ([]*ExchangeDealsList, error) {
 	moonpaySupportsCoin := IsMoonpaySupported(account.Coin().Code()) && action == BuyAction
 	pocketSupportsCoin := IsPocketSupported(account.Coin().Code())
 	btcDirectSupportsCoin := IsBtcDirectSupported(account.Coin().Code()) && action == BuyAction
@@ -185,7 +187,7 @@ func GetExchangeDeals(account accounts.Interface, regionCode string, action Exch
 
 	var userRegion *ExchangeRegion
 	if len(regionCode) > 0 {
-		exchangesByRegion := ListExchangesByRegion(account, httpClient)
+		exchangesByRegion := ListExchangesByRegion(ctx, account, httpClient)
 		for _, region := range exchangesByRegion.Regions {
 			if region.Code == regionCode {
 				// to avoid exporting loop refs
@@ -15,6 +15,7 @@
 package exchanges
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -97,12 +98,12 @@ type BuyMoonpayRegion struct {
 }
 
 // GetMoonpaySupportedRegions query moonpay API and returns a map of regions where buy is allowed.
-func GetMoonpaySupportedRegions(httpClient *http.Client) (map[string]BuyMoonpayRegion, error) {
+func GetMoonpaySupportedRegions(ctx context.Context, httpClient *http.Client) (map[string]BuyMoonpayRegion, error) {
 	regionsMap := make(map[string]BuyMoonpayRegion)
 	var regionsList []BuyMoonpayRegion
 	endpoint := fmt.Sprintf("%s/countries", moonpayAPILiveURL)
 
-	_, err := util.APIGet(httpClient, endpoint, "", 1000000, &regionsList)
+	_, err := util.APIGet(ctx, httpClient, endpoint, "", 1000000, &regionsList)
 	if err != nil {
 		return nil, err
 	}
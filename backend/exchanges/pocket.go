@@ -15,6 +15,7 @@
 package exchanges
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -93,12 +94,12 @@ func PocketDeals() *ExchangeDealsList {
 }
 
 // GetPocketSupportedRegions query pocket API and returns a map of available regions.
-func GetPocketSupportedRegions(httpClient *http.Client) (map[string]PocketRegion, error) {
+func GetPocketSupportedRegions(ctx context.Context, httpClient *http.Client) (map[string]PocketRegion, error) {
 	regionsMap := make(map[string]PocketRegion)
 	var regionsList []PocketRegion
 	endpoint := fmt.Sprintf("%s/availabilities", pocketAPILiveURL)
 
-	_, err := util.APIGet(httpClient, endpoint, "", 1000000, &regionsList)
+	_, err := util.APIGet(ctx, httpClient, endpoint, "", 1000000, &regionsList)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,141 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// Stable error codes returned in the `errorCode` field of error responses, so clients don't have
+// to match error messages.
+const (
+	errorCodeBadRequest       = "badRequest"
+	errorCodeUnauthorized     = "unauthorized"
+	errorCodeNotFound         = "notFound"
+	errorCodeMethodNotAllowed = "methodNotAllowed"
+	errorCodeTooManyRequests  = "tooManyRequests"
+	errorCodeTimeout          = "timeout"
+	errorCodeInternal         = "internal"
+	// errorCodeAppLocked is returned for all endpoints but the ones of the lock screen until the
+	// app password is entered, see appLockScreenRoutes.
+	errorCodeAppLocked = "appLocked"
+)
+
+// errorResponse is the body of all error responses. `error` is a human readable message.
+type errorResponse struct {
+	Error     string `json:"error"`
+	ErrorCode string `json:"errorCode"`
+	// Params are the parameters of the message of errors with a specific error code, see
+	// errp.WithCode().
+	Params errp.Context `json:"params,omitempty"`
+}
+
+// errorResult is the `{ success: false, ... }` response of handlers registered with
+// getAPIRouterNoError. errorMessage is the English message. errorCode and params are set if the
+// error has a code, so the frontend can show a translated message instead.
+type errorResult struct {
+	Success      bool           `json:"success"`
+	ErrorMessage string         `json:"errorMessage"`
+	ErrorCode    errp.ErrorCode `json:"errorCode,omitempty"`
+	Params       errp.Context   `json:"params,omitempty"`
+}
+
+// newErrorResult returns the response of a handler failing with err.
+func newErrorResult(err error) errorResult {
+	code, params, _ := errp.CodeOf(err)
+	return errorResult{Success: false, ErrorMessage: err.Error(), ErrorCode: code, Params: params}
+}
+
+// apiError is an error returned by a handler with the HTTP status and error code to respond with.
+type apiError struct {
+	status int
+	code   string
+	err    error
+}
+
+// Error implements error.
+func (err *apiError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (err *apiError) Unwrap() error {
+	return err.err
+}
+
+// errBadRequest marks err as caused by an invalid request.
+func errBadRequest(err error) error {
+	return &apiError{status: http.StatusBadRequest, code: errorCodeBadRequest, err: err}
+}
+
+// errNotFound marks err as caused by a request for something that does not exist.
+func errNotFound(err error) error {
+	return &apiError{status: http.StatusNotFound, code: errorCodeNotFound, err: err}
+}
+
+// errorStatus returns the HTTP status and error code of an error returned by a handler. Errors
+// decoding the JSON request body are bad requests. Other errors are internal errors, with the
+// error's own code if it has one, see errp.CodeOf().
+func errorStatus(err error) (int, string) {
+	var handlerErr *apiError
+	if errors.As(err, &handlerErr) {
+		return handlerErr.status, handlerErr.code
+	}
+	if code, _, ok := errp.CodeOf(err); ok {
+		return http.StatusInternalServerError, string(code)
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return http.StatusBadRequest, errorCodeBadRequest
+	}
+	return http.StatusInternalServerError, errorCodeInternal
+}
+
+// writeAPIError writes an error response.
+func writeAPIError(w http.ResponseWriter, status int, code string, message string) {
+	writeErrorResponse(w, status, errorResponse{Error: message, ErrorCode: code})
+}
+
+// writeHandlerError writes the error response of a handler failing with err.
+func writeHandlerError(w http.ResponseWriter, err error) {
+	status, code := errorStatus(err)
+	_, params, _ := errp.CodeOf(err)
+	writeErrorResponse(w, status, errorResponse{Error: err.Error(), ErrorCode: code, Params: params})
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, response errorResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	writeJSON(w, response)
+}
+
+// notFoundHandler responds to requests not matching any route.
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	writeAPIError(w, http.StatusNotFound, errorCodeNotFound, "not found: "+r.URL.Path)
+}
+
+// methodNotAllowedHandler responds to requests matching a route with a different method.
+func methodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	writeAPIError(w, http.StatusMethodNotAllowed, errorCodeMethodNotAllowed,
+		"method not allowed: "+r.Method+" "+r.URL.Path)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorStatus(t *testing.T) {
+	decodeErr := json.NewDecoder(strings.NewReader("{")).Decode(&struct{}{})
+	typeErr := json.Unmarshal([]byte(`"text"`), new(int))
+	emptyErr := json.NewDecoder(strings.NewReader("")).Decode(&struct{}{})
+
+	for _, test := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{errp.New("failed"), http.StatusInternalServerError, errorCodeInternal},
+		{errp.WithStack(decodeErr), http.StatusBadRequest, errorCodeBadRequest},
+		{errp.WithStack(typeErr), http.StatusBadRequest, errorCodeBadRequest},
+		{errp.WithStack(emptyErr), http.StatusBadRequest, errorCodeBadRequest},
+		{errBadRequest(errp.New("invalid")), http.StatusBadRequest, errorCodeBadRequest},
+		{errp.WithMessage(errNotFound(errp.New("missing")), "lookup"), http.StatusNotFound, errorCodeNotFound},
+	} {
+		status, code := errorStatus(test.err)
+		require.Equal(t, test.status, status, test.err.Error())
+		require.Equal(t, test.code, code, test.err.Error())
+	}
+}
+
+// crashReportingBackend records the crashes reported by the API middleware.
+type crashReportingBackend struct {
+	Backend
+	crashes []string
+}
+
+func (backend *crashReportingBackend) ReportCrash(message, stack string) {
+	backend.crashes = append(backend.crashes, message)
+}
+
+func TestAPIMiddlewareErrors(t *testing.T) {
+	backend := &crashReportingBackend{}
+	handlers := &Handlers{backend: backend, log: logging.Get().WithGroup("test")}
+	serve := func(h func(*http.Request) (interface{}, error)) (*httptest.ResponseRecorder, errorResponse) {
+		recorder := httptest.NewRecorder()
+		handlers.apiMiddleware(false, h).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/test", nil))
+		var response errorResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		return recorder, response
+	}
+
+	recorder, response := serve(func(*http.Request) (interface{}, error) {
+		return nil, errNotFound(errp.New("account not found"))
+	})
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Equal(t, errorResponse{Error: "account not found", ErrorCode: errorCodeNotFound}, response)
+
+	recorder, response = serve(func(*http.Request) (interface{}, error) {
+		return nil, errp.WithCode(errp.New("connection refused"), "electrum.connectFailed",
+			errp.Context{"host": "example.com:50002"})
+	})
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	require.Equal(t, errorResponse{
+		Error:     "connection refused",
+		ErrorCode: "electrum.connectFailed",
+		Params:    errp.Context{"host": "example.com:50002"},
+	}, response)
+
+	recorder, response = serve(func(*http.Request) (interface{}, error) {
+		panic("boom")
+	})
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	require.Equal(t, errorResponse{Error: "boom", ErrorCode: errorCodeInternal}, response)
+	require.Equal(t, []string{"boom"}, backend.crashes)
+
+	recorder = httptest.NewRecorder()
+	handlers.apiMiddleware(false, func(*http.Request) (interface{}, error) {
+		return "ok", nil
+	}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/test", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "\"ok\"\n", recorder.Body.String())
+}
+
+func TestNewErrorResult(t *testing.T) {
+	require.Equal(t,
+		errorResult{Success: false, ErrorMessage: "failed"},
+		newErrorResult(errp.New("failed")))
+	require.Equal(t,
+		errorResult{
+			Success:      false,
+			ErrorMessage: "invalid",
+			ErrorCode:    "socksproxy.invalidAddress",
+			Params:       errp.Context{"address": "localhost"},
+		},
+		newErrorResult(errp.WithCode(errp.New("invalid"), "socksproxy.invalidAddress",
+			errp.Context{"address": "localhost"})))
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// The range of API versions served. Clients can request a specific version with the `/api/v<n>/`
+// prefix. Requests without a version, i.e. with the `/api/` prefix, are served by the latest
+// version. When making a breaking change, increase apiVersionMax and keep serving the old behavior
+// for requests of older versions until apiVersionMin is increased.
+const (
+	apiVersionMin = 1
+	apiVersionMax = 1
+)
+
+// apiVersionRange is the range of supported API versions, as returned by the version endpoint.
+type apiVersionRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// unsupportedAPIVersionError is the response to requests for an unsupported API version.
+type unsupportedAPIVersionError struct {
+	errorResponse
+	Supported apiVersionRange `json:"supported"`
+}
+
+// errorCodeUnsupportedAPIVersion is the error code of unsupportedAPIVersionError.
+const errorCodeUnsupportedAPIVersion = "unsupportedAPIVersion"
+
+// apiVersionVar is the route variable holding the requested API version.
+const apiVersionVar = "apiVersion"
+
+// versionedAPIPathPrefix matches the API path prefix including the version, e.g. `/api/v1/`.
+const versionedAPIPathPrefix = "/api/v{" + apiVersionVar + ":[0-9]+}/"
+
+// versionedAPIHandler serves requests to a versioned API path like `/api/v1/config` by checking
+// that the version is supported and passing the request with the version removed from the path to
+// handler. Requests for unsupported versions are rejected with an unsupportedAPIVersionError.
+func versionedAPIHandler(handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		versionString := mux.Vars(r)[apiVersionVar]
+		version, err := strconv.Atoi(versionString)
+		if err != nil || version < apiVersionMin || version > apiVersionMax {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, unsupportedAPIVersionError{
+				errorResponse: errorResponse{
+					Error: fmt.Sprintf("API version %s is not supported, supported versions are %d to %d",
+						versionString, apiVersionMin, apiVersionMax),
+					ErrorCode: errorCodeUnsupportedAPIVersion,
+				},
+				Supported: apiVersionRange{Min: apiVersionMin, Max: apiVersionMax},
+			})
+			return
+		}
+		unversioned := r.Clone(r.Context())
+		unversioned.URL.Path = "/api/" + strings.TrimPrefix(r.URL.Path, "/api/v"+versionString+"/")
+		unversioned.URL.RawPath = ""
+		handler.ServeHTTP(w, unversioned)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedAPIHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.PathPrefix(versionedAPIPathPrefix).Handler(versionedAPIHandler(router))
+	router.HandleFunc("/api/config", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	response := get("/api/config")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "/api/config", response.Body.String())
+
+	response = get("/api/v1/config")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "/api/config", response.Body.String())
+
+	response = get("/api/v2/config")
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	var errorResponse unsupportedAPIVersionError
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	require.Equal(t, "unsupportedAPIVersion", errorResponse.ErrorCode)
+	require.Equal(t, apiVersionRange{Min: apiVersionMin, Max: apiVersionMax}, errorResponse.Supported)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAuthFailures is the number of unauthorized requests a remote address can make before it
+	// is locked out.
+	maxAuthFailures = 5
+	// authLockoutBase is the lockout after maxAuthFailures unauthorized requests. It doubles with
+	// each further unauthorized request, up to authLockoutMax.
+	authLockoutBase = time.Second
+	authLockoutMax  = 5 * time.Minute
+	// authFailuresExpiry is the time after which the unauthorized requests of a remote address are
+	// forgotten if there are no new ones.
+	authFailuresExpiry = 15 * time.Minute
+)
+
+type authFailures struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// authLimiter throttles remote addresses making unauthorized API requests, so the API token can't
+// be brute-forced. A nil *authLimiter does not throttle.
+type authLimiter struct {
+	lock     sync.Mutex
+	failures map[string]*authFailures
+	// exemptLocalSockets disables throttling for clients connected over a Unix domain socket or
+	// named pipe. They all have the same remote address, so one misbehaving client would lock out
+	// all others, and only the current user can connect to them anyway.
+	exemptLocalSockets bool
+	// now is time.Now, replaceable in tests.
+	now func() time.Time
+}
+
+func newAuthLimiter(exemptLocalSockets bool) *authLimiter {
+	return &authLimiter{
+		failures:           map[string]*authFailures{},
+		exemptLocalSockets: exemptLocalSockets,
+		now:                time.Now,
+	}
+}
+
+// remoteHost returns the host of a remote address like `127.0.0.1:51234`. Clients connected over
+// a Unix domain socket or named pipe share the same remote address.
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// isLocalSocket returns true if the host returned by remoteHost is not an IP address, i.e. the
+// client is connected over a Unix domain socket or named pipe.
+func isLocalSocket(host string) bool {
+	_, err := netip.ParseAddr(host)
+	return err != nil
+}
+
+// exempt returns true if the remote host is not throttled.
+func (limiter *authLimiter) exempt(host string) bool {
+	return limiter == nil || (limiter.exemptLocalSockets && isLocalSocket(host))
+}
+
+// lockedOut returns how long the remote host is still locked out, or 0 if it is not.
+func (limiter *authLimiter) lockedOut(host string) time.Duration {
+	if limiter.exempt(host) {
+		return 0
+	}
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	failures, ok := limiter.failures[host]
+	if !ok {
+		return 0
+	}
+	if remaining := failures.lockedUntil.Sub(limiter.now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// fail records an unauthorized request of the remote host.
+func (limiter *authLimiter) fail(host string) {
+	if limiter.exempt(host) {
+		return
+	}
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	now := limiter.now()
+	for otherHost, failures := range limiter.failures {
+		if now.Sub(failures.last) > authFailuresExpiry {
+			delete(limiter.failures, otherHost)
+		}
+	}
+	failures, ok := limiter.failures[host]
+	if !ok {
+		failures = &authFailures{}
+		limiter.failures[host] = failures
+	}
+	failures.count++
+	failures.last = now
+	if failures.count >= maxAuthFailures {
+		lockout := authLockoutMax
+		if shift := failures.count - maxAuthFailures; shift < 20 {
+			lockout = min(authLockoutBase<<shift, authLockoutMax)
+		}
+		failures.lockedUntil = now.Add(lockout)
+	}
+}
+
+// succeed forgets the unauthorized requests of the remote host after an authorized request.
+func (limiter *authLimiter) succeed(host string) {
+	if limiter.exempt(host) {
+		return
+	}
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	delete(limiter.failures, host)
+}
+
+// writeTooManyRequests responds to a request of a locked out remote address.
+func writeTooManyRequests(w http.ResponseWriter, lockout time.Duration) {
+	retryAfter := (lockout + time.Second - 1) / time.Second
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+	writeAPIError(w, http.StatusTooManyRequests, errorCodeTooManyRequests, "too many unauthorized requests")
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthLimiter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newAuthLimiter(true)
+	limiter.now = func() time.Time { return now }
+
+	const host = "127.0.0.1"
+	for i := 0; i < maxAuthFailures-1; i++ {
+		limiter.fail(host)
+		require.Zero(t, limiter.lockedOut(host))
+	}
+	limiter.fail(host)
+	require.Equal(t, authLockoutBase, limiter.lockedOut(host))
+	// Other hosts are not affected.
+	require.Zero(t, limiter.lockedOut("127.0.0.2"))
+
+	// The lockout doubles with each further failure.
+	limiter.fail(host)
+	require.Equal(t, 2*authLockoutBase, limiter.lockedOut(host))
+	now = now.Add(2 * authLockoutBase)
+	require.Zero(t, limiter.lockedOut(host))
+
+	for i := 0; i < 30; i++ {
+		limiter.fail(host)
+	}
+	require.Equal(t, authLockoutMax, limiter.lockedOut(host))
+
+	limiter.succeed(host)
+	require.Zero(t, limiter.lockedOut(host))
+
+	// Failures are forgotten after a while.
+	for i := 0; i < maxAuthFailures-1; i++ {
+		limiter.fail(host)
+	}
+	now = now.Add(authFailuresExpiry + time.Second)
+	limiter.fail(host)
+	require.Zero(t, limiter.lockedOut(host))
+
+	// Clients connected over a Unix domain socket or named pipe are not throttled.
+	for i := 0; i < maxAuthFailures; i++ {
+		limiter.fail("@")
+	}
+	require.Zero(t, limiter.lockedOut("@"))
+	localLimiter := newAuthLimiter(false)
+	localLimiter.now = limiter.now
+	for i := 0; i < maxAuthFailures; i++ {
+		localLimiter.fail("@")
+	}
+	require.Equal(t, authLockoutBase, localLimiter.lockedOut("@"))
+
+	var nilLimiter *authLimiter
+	nilLimiter.fail(host)
+	require.Zero(t, nilLimiter.lockedOut(host))
+}
+
+func TestRemoteHost(t *testing.T) {
+	require.Equal(t, "127.0.0.1", remoteHost("127.0.0.1:51234"))
+	require.Equal(t, "::1", remoteHost("[::1]:51234"))
+	require.Equal(t, "@", remoteHost("@"))
+}
+
+func TestIsLocalSocket(t *testing.T) {
+	require.False(t, isLocalSocket("127.0.0.1"))
+	require.False(t, isLocalSocket("::1"))
+	require.False(t, isLocalSocket("fe80::1%eth0"))
+	require.True(t, isLocalSocket("@"))
+	require.True(t, isLocalSocket(""))
+	require.True(t, isLocalSocket(`\\.\pipe\bitbox-walletd`))
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sync"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/jsonp"
+	"github.com/sirupsen/logrus"
+)
+
+// eventHubClientBufferSize is the number of events buffered per client. Events are dropped for
+// clients which fall behind further.
+const eventHubClientBufferSize = 1000
+
+// eventHub broadcasts every backend event to all connected clients, e.g. all open websockets.
+type eventHub struct {
+	lock    sync.Mutex
+	events  *eventLog
+	clients map[chan loggedEvent]struct{}
+	log     *logrus.Entry
+}
+
+func newEventHub(events *eventLog, log *logrus.Entry) *eventHub {
+	return &eventHub{
+		events:  events,
+		clients: map[chan loggedEvent]struct{}{},
+		log:     log,
+	}
+}
+
+// run broadcasts the events received on the events channel until it is closed.
+func (hub *eventHub) run(events <-chan interface{}) {
+	defer crashreport.Recover()
+	for event := range events {
+		hub.broadcast(jsonp.MustMarshal(event))
+	}
+}
+
+// broadcast assigns the next sequence number to the JSON serialized event and sends it to all
+// clients.
+func (hub *eventHub) broadcast(eventJSON []byte) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+	event := hub.events.add(eventJSON)
+	for client := range hub.clients {
+		select {
+		case client <- event:
+		default:
+			hub.log.WithField("eventID", event.id).Error("Event client is too slow. Dropping event.")
+		}
+	}
+}
+
+// subscribe registers a new client. The returned channel receives all events broadcast from now
+// on. lastID is the ID of the last event broadcast before the client was registered. The returned
+// function unregisters the client and must be called when the client disconnects.
+func (hub *eventHub) subscribe() (events <-chan loggedEvent, lastID uint64, unsubscribe func()) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+	client := make(chan loggedEvent, eventHubClientBufferSize)
+	hub.clients[client] = struct{}{}
+	return client, hub.events.lastEventID(), func() {
+		hub.lock.Lock()
+		defer hub.lock.Unlock()
+		delete(hub.clients, client)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHub(t *testing.T) {
+	hub := newEventHub(newEventLogWithEpoch(eventLogSize, 0), logging.Get().WithGroup("test"))
+	hub.broadcast([]byte(`{"data":"before"}`))
+
+	client1, lastID1, unsubscribe1 := hub.subscribe()
+	client2, lastID2, unsubscribe2 := hub.subscribe()
+	defer unsubscribe2()
+	require.Equal(t, uint64(1), lastID1)
+	require.Equal(t, uint64(1), lastID2)
+
+	hub.broadcast([]byte(`{"data":"first"}`))
+	for _, client := range []<-chan loggedEvent{client1, client2} {
+		event := <-client
+		require.Equal(t, uint64(2), event.id)
+		require.Equal(t, `{"eventID":2,"data":"first"}`, string(event.json))
+	}
+
+	unsubscribe1()
+	hub.broadcast([]byte(`{"data":"second"}`))
+	require.Equal(t, uint64(3), (<-client2).id)
+	require.Empty(t, client1)
+}
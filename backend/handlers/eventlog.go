@@ -0,0 +1,151 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"sync"
+)
+
+const (
+	// eventLogSize is the number of recent events kept for replaying them to reconnecting clients.
+	eventLogSize = 1000
+	// eventIDEpochShift is the position of the epoch in the event IDs, see eventLog. Epochs are
+	// below 1<<(53-eventIDEpochShift), so the IDs are exact as JavaScript numbers.
+	eventIDEpochShift = 32
+)
+
+// replayMessage is sent by websocket clients after reconnecting to receive the events they missed,
+// e.g. `{"action": "replay", "lastEventID": 42}`.
+type replayMessage struct {
+	Action      string `json:"action"`
+	LastEventID uint64 `json:"lastEventID"`
+}
+
+// replayIncompleteEvent is sent to a client requesting a replay if some of the events it missed are
+// not available anymore.
+var replayIncompleteEvent = []byte(`{"type":"backend","data":"replayIncomplete"}`)
+
+// parseReplayMessage returns the last event ID seen by the client if msg is a replay message.
+func parseReplayMessage(msg []byte) (uint64, bool) {
+	var message replayMessage
+	if err := json.Unmarshal(msg, &message); err != nil || message.Action != "replay" {
+		return 0, false
+	}
+	return message.LastEventID, true
+}
+
+// loggedEvent is a JSON serialized event with its sequence number.
+type loggedEvent struct {
+	id   uint64
+	json []byte
+}
+
+// eventLog assigns monotonically increasing sequence numbers to events and keeps the most recent
+// events, so that a client reconnecting to the events websocket can request the events it missed.
+//
+// The upper bits of the sequence numbers are a random epoch chosen at startup, so that a client
+// which reconnects after the backend was restarted does not get the events of the new process
+// replayed as if they followed the ones it has seen.
+type eventLog struct {
+	lock   sync.Mutex
+	size   int
+	epoch  uint64
+	lastID uint64
+	// events is a ring buffer of the most recent events. The oldest one is at events[start].
+	events []loggedEvent
+	start  int
+}
+
+func newEventLog(size int) *eventLog {
+	return newEventLogWithEpoch(size, uint64(rand.Int63n(1<<(53-eventIDEpochShift))))
+}
+
+func newEventLogWithEpoch(size int, epoch uint64) *eventLog {
+	return &eventLog{
+		size:   size,
+		epoch:  epoch,
+		lastID: epoch << eventIDEpochShift,
+		events: make([]loggedEvent, 0, size),
+	}
+}
+
+// withEventID adds the `eventID` field to a JSON serialized event object.
+func withEventID(eventJSON []byte, id uint64) []byte {
+	if len(eventJSON) < 2 || eventJSON[0] != '{' {
+		return eventJSON
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"eventID":`)
+	buf.WriteString(strconv.FormatUint(id, 10))
+	rest := bytes.TrimSpace(eventJSON[1:])
+	if len(rest) > 0 && rest[0] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(rest)
+	return buf.Bytes()
+}
+
+// add assigns the next sequence number to the JSON serialized event and stores it. The event is
+// returned with its sequence number in the `eventID` field.
+func (log *eventLog) add(eventJSON []byte) loggedEvent {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	log.lastID++
+	event := loggedEvent{id: log.lastID, json: withEventID(eventJSON, log.lastID)}
+	if len(log.events) == log.size {
+		log.events[log.start] = event
+		log.start = (log.start + 1) % log.size
+	} else {
+		log.events = append(log.events, event)
+	}
+	return event
+}
+
+// lastEventID returns the sequence number of the last added event. If none were added yet, it is
+// below the sequence number of the first event.
+func (log *eventLog) lastEventID() uint64 {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	return log.lastID
+}
+
+// since returns the stored events with an id higher than lastSeenID, or all stored events if
+// lastSeenID is 0. complete is false if events after lastSeenID were already dropped from the log,
+// or if lastSeenID is from before a restart of the backend, so the client has to reload its state.
+func (log *eventLog) since(lastSeenID uint64) (events []loggedEvent, complete bool) {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	if lastSeenID == 0 {
+		lastSeenID = log.epoch << eventIDEpochShift
+	}
+	if lastSeenID>>eventIDEpochShift != log.epoch || lastSeenID > log.lastID {
+		return nil, false
+	}
+	if lastSeenID == log.lastID {
+		return nil, true
+	}
+	for i := range log.events {
+		event := log.events[(log.start+i)%len(log.events)]
+		if event.id > lastSeenID {
+			events = append(events, event)
+		}
+	}
+	complete = len(events) > 0 && events[0].id == lastSeenID+1
+	return events, complete
+}
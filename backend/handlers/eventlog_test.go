@@ -0,0 +1,102 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEventID(t *testing.T) {
+	require.Equal(t, `{"eventID":3,"type":"backend"}`, string(withEventID([]byte(`{"type":"backend"}`), 3)))
+	require.Equal(t, `{"eventID":3}`, string(withEventID([]byte(`{}`), 3)))
+	require.Equal(t, `"text"`, string(withEventID([]byte(`"text"`), 3)))
+}
+
+func TestEventLog(t *testing.T) {
+	log := newEventLogWithEpoch(3, 0)
+	events, complete := log.since(0)
+	require.True(t, complete)
+	require.Empty(t, events)
+
+	for i := 1; i <= 5; i++ {
+		event := log.add([]byte(`{}`))
+		require.Equal(t, uint64(i), event.id)
+	}
+
+	ids := func(events []loggedEvent) []uint64 {
+		result := []uint64{}
+		for _, event := range events {
+			result = append(result, event.id)
+		}
+		return result
+	}
+
+	events, complete = log.since(2)
+	require.True(t, complete)
+	require.Equal(t, []uint64{3, 4, 5}, ids(events))
+
+	events, complete = log.since(4)
+	require.True(t, complete)
+	require.Equal(t, []uint64{5}, ids(events))
+
+	events, complete = log.since(5)
+	require.True(t, complete)
+	require.Empty(t, events)
+
+	// Events 1 and 2 were dropped.
+	events, complete = log.since(0)
+	require.False(t, complete)
+	require.Equal(t, []uint64{3, 4, 5}, ids(events))
+}
+
+func TestEventLogEpoch(t *testing.T) {
+	log := newEventLogWithEpoch(3, 7)
+	require.Equal(t, uint64(7)<<eventIDEpochShift, log.lastEventID())
+	first := log.add([]byte(`{}`))
+	require.Equal(t, uint64(7)<<eventIDEpochShift+1, first.id)
+	log.add([]byte(`{}`))
+
+	events, complete := log.since(first.id)
+	require.True(t, complete)
+	require.Len(t, events, 1)
+	events, complete = log.since(0)
+	require.True(t, complete)
+	require.Len(t, events, 2)
+
+	// IDs seen before a restart of the backend, lower or higher than the current ones.
+	for _, lastSeenID := range []uint64{1, 5, uint64(6)<<eventIDEpochShift + 100, uint64(8) << eventIDEpochShift} {
+		events, complete = log.since(lastSeenID)
+		require.False(t, complete)
+		require.Empty(t, events)
+	}
+	// An ID of this epoch that was not handed out yet.
+	events, complete = log.since(log.lastEventID() + 1)
+	require.False(t, complete)
+	require.Empty(t, events)
+
+	// The epoch keeps the IDs exact as JavaScript numbers.
+	require.Less(t, newEventLog(1).lastEventID(), uint64(1)<<53)
+}
+
+func TestParseReplayMessage(t *testing.T) {
+	lastEventID, ok := parseReplayMessage([]byte(`{"action":"replay","lastEventID":42}`))
+	require.True(t, ok)
+	require.Equal(t, uint64(42), lastEventID)
+
+	_, ok = parseReplayMessage([]byte(`{"action":"subscribe","topics":["rates"]}`))
+	require.False(t, ok)
+}
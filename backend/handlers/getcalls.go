@@ -0,0 +1,59 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sync"
+)
+
+// getCall is a running call of a GET handler. It can outlive the requests waiting for it if they
+// time out.
+type getCall struct {
+	// done is closed when the handler returned.
+	done   chan struct{}
+	result handlerResult
+}
+
+// getCalls deduplicates the calls of a GET handler per request URI. A request for a URI whose
+// handler call is still running waits for that call instead of calling the handler again. This
+// way, a handler which hangs, e.g. because a server does not respond, runs only once no matter how
+// often the frontend repeats the request after it timed out.
+type getCalls struct {
+	lock  sync.Mutex
+	calls map[string]*getCall
+}
+
+func newGetCalls() *getCalls {
+	return &getCalls{calls: map[string]*getCall{}}
+}
+
+// call returns the running call for the request URI, or starts a new one calling f.
+func (calls *getCalls) call(requestURI string, f func() handlerResult) *getCall {
+	calls.lock.Lock()
+	defer calls.lock.Unlock()
+	if running, ok := calls.calls[requestURI]; ok {
+		return running
+	}
+	newCall := &getCall{done: make(chan struct{})}
+	calls.calls[requestURI] = newCall
+	go func() {
+		newCall.result = f()
+		calls.lock.Lock()
+		delete(calls.calls, requestURI)
+		calls.lock.Unlock()
+		close(newCall.done)
+	}()
+	return newCall
+}
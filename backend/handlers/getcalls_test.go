@@ -0,0 +1,49 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCalls(t *testing.T) {
+	calls := newGetCalls()
+	var count atomic.Int32
+	release := make(chan struct{})
+	f := func() handlerResult {
+		count.Add(1)
+		<-release
+		return handlerResult{value: "result"}
+	}
+
+	// Requests for the same URI share the running call.
+	first := calls.call("/api/a", f)
+	require.Same(t, first, calls.call("/api/a", f))
+	other := calls.call("/api/b", f)
+	require.NotSame(t, first, other)
+
+	close(release)
+	<-first.done
+	<-other.done
+	require.Equal(t, "result", first.result.value)
+	require.Equal(t, int32(2), count.Load())
+
+	// Finished calls are not reused.
+	<-calls.call("/api/a", f).done
+	require.Equal(t, int32(3), count.Load())
+}
@@ -16,7 +16,8 @@
 package handlers
 
 import (
-	"bytes"
+	"context"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -26,19 +27,25 @@ import (
 	"net/http"
 	"os"
 	"runtime/debug"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
 	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/addressbook"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/banners"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/bitsurance"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/electrum"
 	accountHandlers "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/handlers"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/util"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/eth"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox"
 	bitboxHandlers "github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox/handlers"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02"
@@ -49,14 +56,21 @@ import (
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/exchanges"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/hwi"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/software"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/notifications"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/rates"
-	utilConfig "github.com/BitBoxSwiss/bitbox-wallet-app/util/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/syncscheduler"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/jsonp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/metrics"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/socksproxy"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/ur"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -75,7 +89,13 @@ type Backend interface {
 	Accounts() backend.AccountsList
 	AccountsByKeystore() (backend.KeystoresAccountsListMap, error)
 	Keystore() keystore.Keystore
+	Keystores() []keystore.Keystore
+	HWIDevices() ([]*hwi.Device, error)
+	ConnectHWIDevice(fingerprint string) error
+	DisconnectHWIDevice(fingerprint string) error
+	KeystoreByRootFingerprint(rootFingerprint []byte) keystore.Keystore
 	AccountsTotalBalanceByKeystore() (map[string]backend.KeystoreTotalAmount, error)
+	Portfolio() (*backend.Portfolio, error)
 	OnAccountInit(f func(accounts.Interface))
 	OnAccountUninit(f func(accounts.Interface))
 	OnDeviceInit(f func(device.Interface))
@@ -86,9 +106,12 @@ type Backend interface {
 	Register(device device.Interface) error
 	Deregister(deviceID string)
 	RatesUpdater() *rates.RateUpdater
-	DownloadCert(string) (string, error)
-	CheckElectrumServer(*config.ServerInfo) error
+	DownloadCert(context.Context, string) (string, error)
+	CheckElectrumServer(context.Context, *config.ServerInfo) error
 	RegisterTestKeystore(string)
+	CreateTestSimulator(pin string)
+	TestSimulator() *software.Simulator
+	UnlockTestSimulator(pin string) error
 	NotifyUser(string)
 	SystemOpen(string) error
 	ReinitializeAccounts()
@@ -96,29 +119,73 @@ type Backend interface {
 	Banners() *banners.Banners
 	Environment() backend.Environment
 	ExportLogs() error
+	ExportDebugBundle() error
+	ReportCrash(message, stack string)
+	CrashReports() []*crashreport.Report
+	SendCrashReport(id string) error
+	DeleteCrashReport(id string) error
+	Notifications() []notifications.Notification
+	MarkNotificationRead(id string) error
+	MarkAllNotificationsRead() error
+	DeleteNotification(id string) error
 	ExportNotes() error
 	ImportNotes(jsonLines []byte) (*backend.ImportNotesResult, error)
+	ExportConfigBackup() (*backend.ConfigBackup, error)
+	RestoreConfigBackup(*backend.ConfigBackup) error
+	ConfigEncryptionStatus() backend.ConfigEncryptionStatus
+	UnlockConfig(passphrase string) error
+	EnableConfigEncryption(passphrase string) error
+	DisableConfigEncryption(passphrase string) error
 	ChartData() (*backend.Chart, error)
 	SupportedCoins(keystore.Keystore) []coinpkg.Code
 	CanAddAccount(coinpkg.Code, keystore.Keystore) (string, bool)
 	CreateAndPersistAccountConfig(coinCode coinpkg.Code, name string, keystore keystore.Keystore) (accountsTypes.Code, error)
+	CreateAndPersistMultisigAccountConfig(coinCode coinpkg.Code, name string, threshold uint32, cosignerKeys []string, keystore keystore.Keystore) (accountsTypes.Code, error)
+	CreateAndPersistDescriptorAccountConfig(coinCode coinpkg.Code, name string, descriptor string) (accountsTypes.Code, error)
+	CreateAndPersistExtendedPublicKeyAccountConfig(coinCode coinpkg.Code, name string, extendedPublicKey string, scriptType signing.ScriptType) (accountsTypes.Code, error)
+	CreateAndPersistWalletFileAccountConfig(coinCode coinpkg.Code, name string, contents []byte) (*backend.ImportWalletFileResult, error)
+	CreateAndPersistCustomKeypathAccountConfig(coinCode coinpkg.Code, name string, keypath signing.AbsoluteKeypath, scriptType signing.ScriptType, keystore keystore.Keystore) (accountsTypes.Code, error)
 	SetAccountActive(accountCode accountsTypes.Code, active bool) error
 	SetTokenActive(accountCode accountsTypes.Code, tokenCode string, active bool) error
 	RenameAccount(accountCode accountsTypes.Code, name string) error
+	SetAccountGapLimit(accountCode accountsTypes.Code, gapLimit uint16) error
+	SetAccountCoinSelection(accountCode accountsTypes.Code, coinSelection string) error
+	RescanAccount(accountCode accountsTypes.Code) error
+	AddressBook() *addressbook.AddressBook
+	AddContact(contact addressbook.Contact) (*addressbook.Contact, error)
+	UpdateContact(contact addressbook.Contact) error
+	DeleteContact(id string) error
 	AOPP() backend.AOPP
 	AOPPCancel()
+	HandleURI(uri string)
+	PaymentURI() *backend.PaymentURI
+	PaymentURIClear()
 	AOPPApprove()
 	AOPPChooseAccount(code accountsTypes.Code)
 	GetAccountFromCode(code accountsTypes.Code) (accounts.Interface, error)
 	HTTPClient() *http.Client
-	LookupInsuredAccounts(accountCode accountsTypes.Code) ([]bitsurance.AccountDetails, error)
+	LookupInsuredAccounts(ctx context.Context, accountCode accountsTypes.Code) ([]bitsurance.AccountDetails, error)
 	Authenticate(force bool)
 	TriggerAuth()
 	ForceAuth()
 	CancelConnectKeystore()
+	RecordActivity()
+	Lock()
+	UserLanguage() string
+	SetUserLanguage(lang string) error
+	AppPasswordStatus() backend.AppPasswordStatus
+	AppLocked() bool
+	UnlockApp(password string) error
+	EnableAppPassword(password string) error
+	DisableAppPassword(password string) error
 	SetWatchonly(rootFingerprint []byte, watchonly bool) error
 	LookupEthAccountCode(address string) (accountsTypes.Code, string, error)
 	Bluetooth() *bluetooth.Bluetooth
+	SetSyncState(state syncscheduler.State)
+	SyncState() syncscheduler.State
+	PinnedCerts() []*backend.PinnedCert
+	PinnedCert(fingerprint string) (*backend.PinnedCert, error)
+	RemovePinnedCert(fingerprint string) error
 }
 
 // Handlers provides a web api to the backend.
@@ -128,10 +195,18 @@ type Handlers struct {
 	// apiData consists of the port on which this API will run and the authorization token, generated by the
 	// backend to secure the API call. The data is fed into the static javascript app
 	// that is served, so the client knows where and how to connect to.
-	apiData           *ConnectionData
-	backendEvents     chan interface{}
-	websocketUpgrader websocket.Upgrader
-	log               *logrus.Entry
+	apiData       *ConnectionData
+	backendEvents chan interface{}
+	// eventHub broadcasts the backend events to all clients and keeps the recent ones for
+	// replaying.
+	eventHub *eventHub
+	// appPasswordLimiter throttles remote addresses entering wrong app passwords. It is separate
+	// from the authLimiter of the API token, which forgets failures after each authorized request.
+	// Unlike the API token, the app password protects against someone using the app of the current
+	// user, so clients connected over a Unix domain socket or named pipe are throttled too.
+	appPasswordLimiter *authLimiter
+	websocketUpgrader  websocket.Upgrader
+	log                *logrus.Entry
 }
 
 // ConnectionData contains the port and authorization token for communication with the backend.
@@ -139,16 +214,45 @@ type ConnectionData struct {
 	port    int
 	token   string
 	devMode bool
+	// authLimiter throttles remote addresses making unauthorized requests.
+	authLimiter *authLimiter
+	// sessions is nil if requests are authorized with the token directly. Otherwise, the token is
+	// only accepted to log in, and requests are authorized with the issued session tokens.
+	sessions *sessions
 }
 
 // NewConnectionData creates a connection data struct which holds the port and token for the API.
 // If the port is -1 or the token is empty, we assume dev-mode.
+//
+// If the API is served over the network, i.e. the port is not -1 and the token is not empty,
+// clients log in with the token at `/api/session/login` and authorize their requests with the
+// expiring session token they receive.
 func NewConnectionData(port int, token string) *ConnectionData {
-	return &ConnectionData{
-		port:    port,
-		token:   token,
-		devMode: len(token) == 0,
+	connectionData := &ConnectionData{
+		port:        port,
+		token:       token,
+		devMode:     len(token) == 0,
+		authLimiter: newAuthLimiter(true),
+	}
+	if port != -1 && token != "" {
+		connectionData.sessions = newSessions(sessionLifetime)
+	}
+	return connectionData
+}
+
+// isBasicToken compares the value of the Authorization header with `Basic <token>` in constant
+// time, so the token can't be guessed from the response times.
+func isBasicToken(authorization string, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(authorization), []byte("Basic "+token)) == 1
+}
+
+// isAuthorized returns true if the value of the Authorization header authorizes an API request.
+func (connectionData *ConnectionData) isAuthorized(authorization string) bool {
+	if connectionData.sessions == nil {
+		return isBasicToken(authorization, connectionData.token)
 	}
+	token, ok := bearerToken(authorization)
+	return ok && connectionData.sessions.valid(token)
 }
 
 func (connectionData *ConnectionData) isDev() bool {
@@ -162,11 +266,16 @@ func NewHandlers(
 ) *Handlers {
 	log := logging.Get().WithGroup("handlers")
 	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(notFoundHandler)
+	router.MethodNotAllowedHandler = http.HandlerFunc(methodNotAllowedHandler)
+	router.Use(requestLogMiddleware(log))
 	handlers := &Handlers{
-		Router:        router,
-		backend:       backend,
-		apiData:       connData,
-		backendEvents: make(chan interface{}, 1000),
+		Router:             router,
+		backend:            backend,
+		apiData:            connData,
+		backendEvents:      make(chan interface{}, 1000),
+		eventHub:           newEventHub(newEventLog(eventLogSize), log),
+		appPasswordLimiter: newAuthLimiter(false),
 		websocketUpgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -177,7 +286,8 @@ func NewHandlers(
 
 	getAPIRouter := func(subrouter *mux.Router) func(string, func(*http.Request) (interface{}, error)) *mux.Route {
 		return func(path string, f func(*http.Request) (interface{}, error)) *mux.Route {
-			return subrouter.Handle(path, ensureAPITokenValid(handlers.apiMiddleware(connData.isDev(), f),
+			return subrouter.Handle(path, ensureAPITokenValid(
+				handlers.ensureAppUnlocked(handlers.recordActivity(handlers.apiMiddleware(connData.isDev(), f))),
 				connData, log))
 		}
 	}
@@ -188,20 +298,35 @@ func NewHandlers(
 			return subrouter.Handle(
 				path,
 				ensureAPITokenValid(
-					handlers.apiMiddleware(
+					handlers.ensureAppUnlocked(handlers.recordActivity(handlers.apiMiddleware(
 						connData.isDev(),
 						func(r *http.Request) (interface{}, error) {
 							return f(r), nil
-						}),
+						}))),
 					connData, log))
 		}
 	}
 
+	// Requests to `/api/v<n>/...` are served by the routes below after checking the version.
+	router.PathPrefix(versionedAPIPathPrefix).Handler(versionedAPIHandler(router))
 	apiRouter := router.PathPrefix("/api").Subrouter()
 	getAPIRouterNoError(apiRouter)("/qr", handlers.getQRCode).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/ur/encode", handlers.postUREncode).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/ur/decode", handlers.postURDecode).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/config", handlers.getAppConfig).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/config/default", handlers.getDefaultConfig).Methods("GET")
 	getAPIRouter(apiRouter)("/config", handlers.postAppConfig).Methods("POST")
+	getAPIRouter(apiRouter)("/config/language", handlers.postUserLanguage).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/config/backup", handlers.getConfigBackup).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/config/restore", handlers.postRestoreConfigBackup).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/config/encryption", handlers.getConfigEncryption).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/config/encryption/unlock", handlers.postConfigEncryption(handlers.backend.UnlockConfig)).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/config/encryption/enable", handlers.postConfigEncryption(handlers.backend.EnableConfigEncryption)).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/config/encryption/disable", handlers.postConfigEncryption(handlers.backend.DisableConfigEncryption)).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/app-password", handlers.getAppPassword).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/unlock", handlers.postAppPassword(handlers.backend.UnlockApp)).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/app-password/enable", handlers.postAppPassword(handlers.backend.EnableAppPassword)).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/app-password/disable", handlers.postAppPassword(handlers.backend.DisableAppPassword)).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/native-locale", handlers.getNativeLocale).Methods("GET")
 	getAPIRouter(apiRouter)("/notify-user", handlers.postNotify).Methods("POST")
 	getAPIRouter(apiRouter)("/open", handlers.postOpen).Methods("POST")
@@ -214,33 +339,60 @@ func NewHandlers(
 	getAPIRouter(apiRouter)("/set-dark-theme", handlers.postDarkTheme).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/detect-dark-theme", handlers.getDetectDarkTheme).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/version", handlers.getVersion).Methods("GET")
+	getAPIRouter(apiRouter)("/schema", handlers.getSchema).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/testing", handlers.getTesting).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/dev-servers", handlers.getDevServers).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/account-add", handlers.postAddAccount).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/account-add-multisig", handlers.postAddMultisigAccount).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/keystores", handlers.getKeystores).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/accounts", handlers.getAccounts).Methods("GET")
 	getAPIRouter(apiRouter)("/accounts/balance", handlers.getAccountsBalance).Methods("GET")
 	getAPIRouter(apiRouter)("/accounts/coins-balance", handlers.getCoinsTotalBalance).Methods("GET")
 	getAPIRouter(apiRouter)("/accounts/total-balance", handlers.getAccountsTotalBalance).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/portfolio", handlers.getPortfolio).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/set-account-active", handlers.postSetAccountActive).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/set-token-active", handlers.postSetTokenActive).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/rename-account", handlers.postRenameAccount).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/account/{code}/rename", handlers.postAccountRename).Methods("POST")
+	getAPIRouter(apiRouter)("/account/{code}/gap-limit", handlers.postAccountGapLimit).Methods("POST")
+	getAPIRouter(apiRouter)("/account/{code}/coin-selection", handlers.postAccountCoinSelection).Methods("POST")
+	getAPIRouter(apiRouter)("/account/{code}/rescan", handlers.postAccountRescan).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/account/{code}/archive", handlers.postAccountArchive(true)).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/account/{code}/unarchive", handlers.postAccountArchive(false)).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/accounts/reinitialize", handlers.postAccountsReinitialize).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/account-summary", handlers.getAccountSummary).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/supported-coins", handlers.getSupportedCoins).Methods("GET")
 	getAPIRouter(apiRouter)("/test/register", handlers.postRegisterTestKeystore).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/test/deregister", handlers.postDeregisterTestKeystore).Methods("POST")
+	getAPIRouter(apiRouter)("/hwi/devices", handlers.getHWIDevices).Methods("GET")
+	getAPIRouter(apiRouter)("/hwi/connect", handlers.postHWIConnect).Methods("POST")
+	getAPIRouter(apiRouter)("/hwi/disconnect", handlers.postHWIDisconnect).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/test/simulator", handlers.getTestSimulator).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/test/simulator", handlers.postCreateTestSimulator).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/test/simulator/unlock", handlers.postUnlockTestSimulator).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/test/simulator/settings", handlers.postTestSimulatorSettings).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/test/simulator/respond", handlers.postTestSimulatorRespond).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/rates", handlers.getRates).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/rates/history", handlers.getRatesHistory).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/coins/convert-to-plain-fiat", handlers.getConvertToPlainFiat).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/coins/convert-from-fiat", handlers.getConvertFromFiat).Methods("GET")
 	getAPIRouter(apiRouter)("/coins/tltc/headers/status", handlers.getHeadersStatus(coinpkg.CodeTLTC)).Methods("GET")
 	getAPIRouter(apiRouter)("/coins/tbtc/headers/status", handlers.getHeadersStatus(coinpkg.CodeTBTC)).Methods("GET")
+	getAPIRouter(apiRouter)("/coins/tsig/headers/status", handlers.getHeadersStatus(coinpkg.CodeTSIG)).Methods("GET")
 	getAPIRouter(apiRouter)("/coins/ltc/headers/status", handlers.getHeadersStatus(coinpkg.CodeLTC)).Methods("GET")
 	getAPIRouter(apiRouter)("/coins/btc/headers/status", handlers.getHeadersStatus(coinpkg.CodeBTC)).Methods("GET")
+	getAPIRouter(apiRouter)("/coins/{code}/electrum-status", handlers.getElectrumStatus).Methods("GET")
+	getAPIRouter(apiRouter)("/coins/{code}/servers", handlers.getCoinServers).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/coins/{code}/verify-message", handlers.postVerifyMessage).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/coins/{code}/broadcast", handlers.postBroadcastRawTransaction).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/coins/{code}/decode-transaction", handlers.postDecodeTransaction).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/coins/btc/set-unit", handlers.postBtcFormatUnit).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/coins/btc/parse-external-amount", handlers.getBTCParseExternalAmount).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/certs/download", handlers.postCertsDownload).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/electrum/check", handlers.postElectrumCheck).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/certs/pinned", handlers.getPinnedCerts).Methods("GET")
+	getAPIRouter(apiRouter)("/certs/pinned/{fingerprint}", handlers.getPinnedCert).Methods("GET")
+	getAPIRouter(apiRouter)("/certs/pinned/remove", handlers.postRemovePinnedCert).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/socksproxy/check", handlers.postSocksProxyCheck).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/exchange/region-codes", handlers.getExchangeRegionCodes).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/exchange/deals/{action}/{code}", handlers.getExchangeDeals).Methods("GET")
@@ -256,14 +408,36 @@ func NewHandlers(
 	getAPIRouterNoError(apiRouter)("/aopp/cancel", handlers.postAOPPCancel).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/aopp/approve", handlers.postAOPPApprove).Methods("POST")
 	getAPIRouter(apiRouter)("/aopp/choose-account", handlers.postAOPPChooseAccount).Methods("POST")
+	getAPIRouter(apiRouter)("/handle-uri", handlers.postHandleURI).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/payment-uri", handlers.getPaymentURI).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/payment-uri/clear", handlers.postPaymentURIClear).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/cancel-connect-keystore", handlers.postCancelConnectKeystore).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/lock", handlers.postLock).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/set-watchonly", handlers.postSetWatchonly).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/sync-state", handlers.getSyncState).Methods("GET")
+	getAPIRouter(apiRouter)("/sync-state", handlers.postSyncState).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/on-auth-setting-changed", handlers.postOnAuthSettingChanged).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/export-log", handlers.postExportLog).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/debug/export", handlers.postExportDebugBundle).Methods("POST")
+	if connData.isDev() {
+		apiRouter.HandleFunc("/metrics", handlers.getMetrics).Methods("GET")
+	}
+	getAPIRouter(apiRouter)("/crash-reports", handlers.getCrashReports).Methods("GET")
+	getAPIRouter(apiRouter)("/crash-reports/send", handlers.postID(handlers.backend.SendCrashReport)).Methods("POST")
+	getAPIRouter(apiRouter)("/crash-reports/delete", handlers.postID(handlers.backend.DeleteCrashReport)).Methods("POST")
+	getAPIRouter(apiRouter)("/notifications", handlers.getNotifications).Methods("GET")
+	getAPIRouter(apiRouter)("/notifications/read", handlers.postID(handlers.backend.MarkNotificationRead)).Methods("POST")
+	getAPIRouter(apiRouter)("/notifications/read-all", handlers.postMarkAllNotificationsRead).Methods("POST")
+	getAPIRouter(apiRouter)("/notifications/delete", handlers.postID(handlers.backend.DeleteNotification)).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/accounts/eth-account-code", handlers.lookupEthAccountCode).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/notes/export", handlers.postExportNotes).Methods("POST")
 	getAPIRouterNoError(apiRouter)("/notes/import", handlers.postImportNotes).Methods("POST")
 
+	getAPIRouterNoError(apiRouter)("/addressbook", handlers.getAddressBook).Methods("GET")
+	getAPIRouterNoError(apiRouter)("/addressbook/add", handlers.postAddressBookAdd).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/addressbook/update", handlers.postAddressBookUpdate).Methods("POST")
+	getAPIRouterNoError(apiRouter)("/addressbook/delete", handlers.postAddressBookDelete).Methods("POST")
+
 	getAPIRouterNoError(apiRouter)("/bluetooth/state", handlers.getBluetoothState).Methods("GET")
 	getAPIRouterNoError(apiRouter)("/bluetooth/connect", handlers.postBluetoothConnect).Methods("POST")
 
@@ -278,7 +452,7 @@ func NewHandlers(
 		if _, ok := accountHandlersMap[accountCode]; !ok {
 			accountHandlersMap[accountCode] = accountHandlers.NewHandlers(getAPIRouter(
 				apiRouter.PathPrefix(fmt.Sprintf("/account/%s", accountCode)).Subrouter(),
-			), log)
+			), backend.AddressBook(), log)
 		}
 		accHandlers := accountHandlersMap[accountCode]
 		log.WithField("account-handlers", accHandlers).Debug("Account handlers")
@@ -321,7 +495,7 @@ func NewHandlers(
 		if _, ok := bitbox02BootloaderHandlersMap[deviceID]; !ok {
 			bitbox02BootloaderHandlersMap[deviceID] = bitbox02bootloaderHandlers.NewHandlers(getAPIRouter(
 				apiRouter.PathPrefix(fmt.Sprintf("/devices/bitbox02-bootloader/%s", deviceID)).Subrouter(),
-			), log)
+			), backend.HTTPClient(), log)
 		}
 		return bitbox02BootloaderHandlersMap[deviceID]
 	}
@@ -337,10 +511,27 @@ func NewHandlers(
 		}
 	})
 	backend.OnDeviceUninit(func(deviceID string) {
-		getDeviceHandlers(deviceID).Uninit()
+		// Only uninit the handlers that were created for this device. Calling the getters here
+		// would register routes for device types the device does not belong to.
+		unlock := handlersMapLock.Lock()
+		bitboxDeviceHandlers, isBitBox := deviceHandlersMap[deviceID]
+		bitbox02DeviceHandlers, isBitBox02 := bitbox02HandlersMap[deviceID]
+		bootloaderDeviceHandlers, isBootloader := bitbox02BootloaderHandlersMap[deviceID]
+		unlock()
+		switch {
+		case isBitBox:
+			bitboxDeviceHandlers.Uninit()
+		case isBitBox02:
+			bitbox02DeviceHandlers.Uninit()
+		case isBootloader:
+			bootloaderDeviceHandlers.Uninit()
+		}
 	})
 
-	apiRouter.HandleFunc("/events", handlers.eventsHandler)
+	apiRouter.HandleFunc("/events", handlers.eventsHandler).Methods("GET")
+	apiRouter.HandleFunc("/session/login", handlers.postSessionLogin).Methods("POST")
+	apiRouter.HandleFunc("/session/renew", handlers.postSessionRenew).Methods("POST")
+	apiRouter.HandleFunc("/session/logout", handlers.postSessionLogout).Methods("POST")
 
 	// The backend relays events in two ways:
 	// a) old school through the channel returned by Start()
@@ -353,13 +544,29 @@ func NewHandlers(
 		}
 	}()
 	backend.Observe(func(event observable.Event) { handlers.backendEvents <- event })
+	go handlers.eventHub.run(handlers.backendEvents)
 
 	return handlers
 }
 
-// Events returns the push notifications channel.
+// Events returns a new push notifications channel, receiving all events. The events are JSON
+// serialized already. The recent events emitted before calling Events() are received first.
 func (handlers *Handlers) Events() <-chan interface{} {
-	return handlers.backendEvents
+	events, subscribedAfterID, _ := handlers.eventHub.subscribe()
+	result := make(chan interface{})
+	go func() {
+		previous, _ := handlers.eventHub.events.since(0)
+		for _, event := range previous {
+			if event.id > subscribedAfterID {
+				break
+			}
+			result <- json.RawMessage(event.json)
+		}
+		for event := range events {
+			result <- json.RawMessage(event.json)
+		}
+	}()
+	return result
 }
 
 func writeJSON(w io.Writer, value interface{}) {
@@ -449,8 +656,81 @@ func (handlers *Handlers) getQRCode(r *http.Request) interface{} {
 	}
 }
 
+// defaultURFragmentLen is the maximum fragment length of animated UR QR codes if not specified. It
+// keeps each frame at a QR version that is easy to scan.
+const defaultURFragmentLen = 200
+
+// postUREncode encodes data as a sequence of UR strings, to be displayed as animated QR code.
+func (handlers *Handlers) postUREncode(r *http.Request) interface{} {
+	type result struct {
+		Success      bool     `json:"success"`
+		ErrorMessage string   `json:"errorMessage,omitempty"`
+		Parts        []string `json:"parts,omitempty"`
+	}
+	var request struct {
+		// Type is the UR type, e.g. "crypto-psbt" or "bytes".
+		Type string `json:"type"`
+		// Data is base64 encoded.
+		Data           string `json:"data"`
+		MaxFragmentLen int    `json:"maxFragmentLen"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}
+	}
+	data, err := base64.StdEncoding.DecodeString(request.Data)
+	if err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}
+	}
+	if request.MaxFragmentLen == 0 {
+		request.MaxFragmentLen = defaultURFragmentLen
+	}
+	parts, err := ur.Encode(request.Type, data, request.MaxFragmentLen, 0)
+	if err != nil {
+		handlers.log.WithError(err).Error("postUREncode")
+		return result{Success: false, ErrorMessage: err.Error()}
+	}
+	return result{Success: true, Parts: parts}
+}
+
+// postURDecode reassembles the data from the scanned UR strings. It is called with all parts
+// scanned so far until the result is complete.
+func (handlers *Handlers) postURDecode(r *http.Request) interface{} {
+	type result struct {
+		Success      bool    `json:"success"`
+		ErrorMessage string  `json:"errorMessage,omitempty"`
+		Complete     bool    `json:"complete"`
+		Progress     float64 `json:"progress"`
+		Type         string  `json:"type,omitempty"`
+		// Data is base64 encoded and only set if complete is true.
+		Data string `json:"data,omitempty"`
+	}
+	var request struct {
+		Parts []string `json:"parts"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}
+	}
+	decoded, err := ur.Decode(request.Parts)
+	if err != nil {
+		return result{Success: false, ErrorMessage: err.Error()}
+	}
+	res := result{
+		Success:  true,
+		Complete: decoded.Complete,
+		Progress: decoded.Progress,
+		Type:     decoded.Type,
+	}
+	if decoded.Complete {
+		res.Data = base64.StdEncoding.EncodeToString(decoded.Payload)
+	}
+	return res
+}
+
 func (handlers *Handlers) getAppConfig(*http.Request) interface{} {
-	return handlers.backend.Config().AppConfig()
+	appConfig := handlers.backend.Config().AppConfig()
+	// The config is served while the app is locked, so the password hash is not exposed.
+	appConfig.Backend.AppPassword = nil
+	return appConfig
 }
 
 func (handlers *Handlers) getDefaultConfig(*http.Request) interface{} {
@@ -462,9 +742,129 @@ func (handlers *Handlers) postAppConfig(r *http.Request) (interface{}, error) {
 	if err := json.NewDecoder(r.Body).Decode(&appConfig); err != nil {
 		return nil, errp.WithStack(err)
 	}
+	current := handlers.backend.Config().AppConfig()
+	// The app password is only changed with the /app-password endpoints.
+	appConfig.Backend.AppPassword = current.Backend.AppPassword
+	// The HWI executable is run by the backend, so it can't be changed through the API.
+	appConfig.Backend.HWIPath = current.Backend.HWIPath
 	return nil, handlers.backend.Config().SetAppConfig(appConfig)
 }
 
+// postUserLanguage sets the UI language. An empty language resets it to the native locale.
+func (handlers *Handlers) postUserLanguage(r *http.Request) (interface{}, error) {
+	var language string
+	if err := json.NewDecoder(r.Body).Decode(&language); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return nil, handlers.backend.SetUserLanguage(language)
+}
+
+// getConfigBackup returns the app configuration, the accounts configuration and the notes of all
+// accounts as one bundle, which can be restored with /config/restore.
+func (handlers *Handlers) getConfigBackup(*http.Request) interface{} {
+	type result struct {
+		Success bool                  `json:"success"`
+		Message string                `json:"message,omitempty"`
+		Backup  *backend.ConfigBackup `json:"backup,omitempty"`
+	}
+	backup, err := handlers.backend.ExportConfigBackup()
+	if err != nil {
+		handlers.log.WithError(err).Error("Error exporting config backup")
+		return result{Success: false, Message: err.Error()}
+	}
+	return result{Success: true, Backup: backup}
+}
+
+func (handlers *Handlers) postRestoreConfigBackup(r *http.Request) interface{} {
+	type result struct {
+		Success bool   `json:"success"`
+		Message string `json:"message,omitempty"`
+	}
+	var backup backend.ConfigBackup
+	if err := json.NewDecoder(r.Body).Decode(&backup); err != nil {
+		return result{Success: false, Message: err.Error()}
+	}
+	if err := handlers.backend.RestoreConfigBackup(&backup); err != nil {
+		handlers.log.WithError(err).Error("Error restoring config backup")
+		return result{Success: false, Message: err.Error()}
+	}
+	return result{Success: true}
+}
+
+func (handlers *Handlers) getConfigEncryption(*http.Request) interface{} {
+	return handlers.backend.ConfigEncryptionStatus()
+}
+
+// postConfigEncryption returns a handler calling f with the passphrase in the request body, used
+// to unlock, enable and disable the config encryption.
+func (handlers *Handlers) postConfigEncryption(f func(passphrase string) error) func(*http.Request) interface{} {
+	return func(r *http.Request) interface{} {
+		type result struct {
+			Success bool   `json:"success"`
+			Message string `json:"message,omitempty"`
+			// ErrorCode is "wrongPassphrase" if the passphrase did not match, and "paramsMissing"
+			// if the encryption params were deleted while encrypted files remain.
+			ErrorCode string `json:"errorCode,omitempty"`
+		}
+		var passphrase string
+		if err := json.NewDecoder(r.Body).Decode(&passphrase); err != nil {
+			return result{Success: false, Message: err.Error()}
+		}
+		if err := f(passphrase); err != nil {
+			switch errp.Cause(err) {
+			case vault.ErrWrongPassphrase:
+				return result{Success: false, ErrorCode: "wrongPassphrase"}
+			case vault.ErrParamsMissing:
+				return result{Success: false, ErrorCode: "paramsMissing"}
+			}
+			handlers.log.WithError(err).Error("Error changing the config encryption")
+			return result{Success: false, Message: err.Error()}
+		}
+		return result{Success: true}
+	}
+}
+
+func (handlers *Handlers) getAppPassword(*http.Request) interface{} {
+	return handlers.backend.AppPasswordStatus()
+}
+
+// postAppPassword returns a handler calling f with the password in the request body, used to
+// unlock the app and to enable and disable the app password.
+func (handlers *Handlers) postAppPassword(f func(password string) error) func(*http.Request) interface{} {
+	return func(r *http.Request) interface{} {
+		type result struct {
+			Success bool   `json:"success"`
+			Message string `json:"message,omitempty"`
+			// ErrorCode is "wrongPassword" if the password did not match, and "tooManyAttempts" if
+			// too many wrong passwords were entered, see appPasswordLimiter.
+			ErrorCode string `json:"errorCode,omitempty"`
+		}
+		host := remoteHost(r.RemoteAddr)
+		if lockout := handlers.appPasswordLimiter.lockedOut(host); lockout > 0 {
+			handlers.log.Error("Too many wrong app passwords. WARNING: this could be an attack on the app password")
+			return result{
+				Success:   false,
+				Message:   fmt.Sprintf("too many wrong passwords, try again in %s", lockout.Round(time.Second)),
+				ErrorCode: "tooManyAttempts",
+			}
+		}
+		var password string
+		if err := json.NewDecoder(r.Body).Decode(&password); err != nil {
+			return result{Success: false, Message: err.Error()}
+		}
+		if err := f(password); err != nil {
+			if errp.Cause(err) == backend.ErrWrongAppPassword {
+				handlers.appPasswordLimiter.fail(host)
+				return result{Success: false, ErrorCode: "wrongPassword"}
+			}
+			handlers.log.WithError(err).Error("Error with the app password")
+			return result{Success: false, Message: err.Error()}
+		}
+		handlers.appPasswordLimiter.succeed(host)
+		return result{Success: true}
+	}
+}
+
 // getNativeLocaleHandler returns user preferred UI language as reported
 // by the native app layer.
 // The response value may be invalid or unsupported by the app.
@@ -540,7 +940,18 @@ func (handlers *Handlers) getDetectDarkTheme(r *http.Request) interface{} {
 }
 
 func (handlers *Handlers) getVersion(*http.Request) interface{} {
-	return backend.Version.String()
+	return struct {
+		Version    string          `json:"version"`
+		APIVersion apiVersionRange `json:"apiVersion"`
+	}{
+		Version:    backend.Version.String(),
+		APIVersion: apiVersionRange{Min: apiVersionMin, Max: apiVersionMax},
+	}
+}
+
+// getSchema returns an OpenAPI description of all registered routes.
+func (handlers *Handlers) getSchema(*http.Request) (interface{}, error) {
+	return buildAPISchema(handlers.Router, backend.Version.String())
 }
 
 func (handlers *Handlers) getTesting(*http.Request) interface{} {
@@ -555,6 +966,22 @@ func (handlers *Handlers) postAddAccount(r *http.Request) interface{} {
 	var jsonBody struct {
 		CoinCode coinpkg.Code `json:"coinCode"`
 		Name     string       `json:"name"`
+		// Keypath is optional. If set, the account is created at this keypath instead of the next
+		// standard account keypath.
+		Keypath    string             `json:"keypath"`
+		ScriptType signing.ScriptType `json:"scriptType"`
+		// Descriptor is optional. If set, a watch-only account is created from this output
+		// descriptor, and no keystore is needed.
+		Descriptor string `json:"descriptor"`
+		// ExtendedPublicKey is optional. If set, a watch-only account is created from this
+		// account-level key, e.g. `zpub...` or `[d34db33f/84'/0'/0']zpub...`. ScriptType is
+		// inferred from its version if not set.
+		ExtendedPublicKey string `json:"extendedPublicKey"`
+		// WalletFile is optional. If set, a watch-only account is created from this hex encoded
+		// Electrum wallet file or Wasabi wallet JSON file.
+		WalletFile string `json:"walletFile"`
+		// RootFingerprint is optional and selects the keystore if more than one is connected.
+		RootFingerprint jsonp.HexBytes `json:"rootFingerprint"`
 	}
 
 	type response struct {
@@ -562,20 +989,106 @@ func (handlers *Handlers) postAddAccount(r *http.Request) interface{} {
 		AccountCode  accountsTypes.Code `json:"accountCode,omitempty"`
 		ErrorMessage string             `json:"errorMessage,omitempty"`
 		ErrorCode    string             `json:"errorCode,omitempty"`
+		// WalletFileImport is set if the account was created from a wallet file.
+		WalletFileImport *backend.ImportWalletFileResult `json:"walletFileImport,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&jsonBody); err != nil {
 		return response{Success: false, ErrorMessage: err.Error()}
 	}
 
-	keystore := handlers.backend.Keystore()
+	handleError := func(err error) interface{} {
+		handlers.log.WithError(err).Error("Could not add account")
+		if errCode, ok := errp.Cause(err).(errp.ErrorCode); ok {
+			return response{Success: false, ErrorCode: string(errCode)}
+		}
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+
+	if jsonBody.Descriptor != "" {
+		accountCode, err := handlers.backend.CreateAndPersistDescriptorAccountConfig(
+			jsonBody.CoinCode, jsonBody.Name, jsonBody.Descriptor)
+		if err != nil {
+			return handleError(err)
+		}
+		return response{Success: true, AccountCode: accountCode}
+	}
+	if jsonBody.ExtendedPublicKey != "" {
+		accountCode, err := handlers.backend.CreateAndPersistExtendedPublicKeyAccountConfig(
+			jsonBody.CoinCode, jsonBody.Name, jsonBody.ExtendedPublicKey, jsonBody.ScriptType)
+		if err != nil {
+			return handleError(err)
+		}
+		return response{Success: true, AccountCode: accountCode}
+	}
+	if jsonBody.WalletFile != "" {
+		walletFile, err := hex.DecodeString(jsonBody.WalletFile)
+		if err != nil {
+			return response{Success: false, ErrorMessage: err.Error()}
+		}
+		result, err := handlers.backend.CreateAndPersistWalletFileAccountConfig(
+			jsonBody.CoinCode, jsonBody.Name, walletFile)
+		if err != nil {
+			return handleError(err)
+		}
+		return response{Success: true, AccountCode: result.AccountCode, WalletFileImport: result}
+	}
+
+	keystore := handlers.keystore(jsonBody.RootFingerprint)
 	if keystore == nil {
 		return response{Success: false, ErrorMessage: "Keystore not found"}
 	}
 
-	accountCode, err := handlers.backend.CreateAndPersistAccountConfig(jsonBody.CoinCode, jsonBody.Name, keystore)
+	var accountCode accountsTypes.Code
+	var err error
+	if jsonBody.Keypath != "" {
+		keypath, keypathErr := signing.NewAbsoluteKeypath(jsonBody.Keypath)
+		if keypathErr != nil {
+			return response{Success: false, ErrorMessage: keypathErr.Error()}
+		}
+		accountCode, err = handlers.backend.CreateAndPersistCustomKeypathAccountConfig(
+			jsonBody.CoinCode, jsonBody.Name, keypath, jsonBody.ScriptType, keystore)
+	} else {
+		accountCode, err = handlers.backend.CreateAndPersistAccountConfig(jsonBody.CoinCode, jsonBody.Name, keystore)
+	}
 	if err != nil {
-		handlers.log.WithError(err).Error("Could not add account")
+		return handleError(err)
+	}
+	return response{Success: true, AccountCode: accountCode}
+}
+
+func (handlers *Handlers) postAddMultisigAccount(r *http.Request) interface{} {
+	var jsonBody struct {
+		CoinCode  coinpkg.Code `json:"coinCode"`
+		Name      string       `json:"name"`
+		Threshold uint32       `json:"threshold"`
+		// Cosigners are the keys of the other cosigners, including the key origin, e.g.
+		// `[d34db33f/48'/0'/0'/2']xpub...`.
+		Cosigners []string `json:"cosigners"`
+		// RootFingerprint is optional and selects the keystore if more than one is connected.
+		RootFingerprint jsonp.HexBytes `json:"rootFingerprint"`
+	}
+
+	type response struct {
+		Success      bool               `json:"success"`
+		AccountCode  accountsTypes.Code `json:"accountCode,omitempty"`
+		ErrorMessage string             `json:"errorMessage,omitempty"`
+		ErrorCode    string             `json:"errorCode,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&jsonBody); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+
+	keystore := handlers.keystore(jsonBody.RootFingerprint)
+	if keystore == nil {
+		return response{Success: false, ErrorMessage: "Keystore not found"}
+	}
+
+	accountCode, err := handlers.backend.CreateAndPersistMultisigAccountConfig(
+		jsonBody.CoinCode, jsonBody.Name, jsonBody.Threshold, jsonBody.Cosigners, keystore)
+	if err != nil {
+		handlers.log.WithError(err).Error("Could not add multisig account")
 		if errCode, ok := errp.Cause(err).(errp.ErrorCode); ok {
 			return response{Success: false, ErrorCode: string(errCode)}
 		}
@@ -584,29 +1097,52 @@ func (handlers *Handlers) postAddAccount(r *http.Request) interface{} {
 	return response{Success: true, AccountCode: accountCode}
 }
 
+// keystore returns the connected keystore with the given root fingerprint. If rootFingerprint is
+// empty, the keystore that was connected first is returned. Returns nil if there is no such
+// keystore.
+func (handlers *Handlers) keystore(rootFingerprint []byte) keystore.Keystore {
+	if len(rootFingerprint) == 0 {
+		return handlers.backend.Keystore()
+	}
+	return handlers.backend.KeystoreByRootFingerprint(rootFingerprint)
+}
+
 func (handlers *Handlers) getKeystores(*http.Request) interface{} {
 	type json struct {
-		Type keystore.Type `json:"type"`
+		Type            keystore.Type  `json:"type"`
+		RootFingerprint jsonp.HexBytes `json:"rootFingerprint"`
 	}
 	keystores := []*json{}
 
-	keystore := handlers.backend.Keystore()
-	if keystore != nil {
+	for _, keystore := range handlers.backend.Keystores() {
+		rootFingerprint, err := keystore.RootFingerprint()
+		if err != nil {
+			handlers.log.WithError(err).Error("Could not retrieve rootFingerprint")
+			continue
+		}
 		keystores = append(keystores, &json{
-			Type: keystore.Type(),
+			Type:            keystore.Type(),
+			RootFingerprint: rootFingerprint,
 		})
 	}
 	return keystores
 }
 
-func (handlers *Handlers) getAccounts(*http.Request) interface{} {
+// getAccounts returns the loaded accounts. The optional `archived` query param ("true" or "false")
+// returns only archived or only active accounts.
+func (handlers *Handlers) getAccounts(r *http.Request) interface{} {
 	persistedAccounts := handlers.backend.Config().AccountsConfig()
+	archivedFilter := r.URL.Query().Get("archived")
 
 	accounts := []*accountJSON{}
 	for _, account := range handlers.backend.Accounts() {
 		if account.Config().Config.HiddenBecauseUnused {
 			continue
 		}
+		archived := account.Config().Config.Inactive
+		if (archivedFilter == "true" && !archived) || (archivedFilter == "false" && archived) {
+			continue
+		}
 		var activeTokens []activeToken
 
 		persistedAccount := account.Config().Config
@@ -630,15 +1166,7 @@ func (handlers *Handlers) getAccounts(*http.Request) interface{} {
 			continue
 		}
 
-		keystoreConnected := false
-		if connectedKeystore := handlers.backend.Keystore(); connectedKeystore != nil {
-			connectedKeystoreRootFingerprint, err := connectedKeystore.RootFingerprint()
-			if err != nil {
-				handlers.log.WithError(err).Error("Could not retrieve rootFingerprint")
-			} else {
-				keystoreConnected = bytes.Equal(rootFingerprint, connectedKeystoreRootFingerprint)
-			}
-		}
+		keystoreConnected := handlers.backend.KeystoreByRootFingerprint(rootFingerprint) != nil
 
 		accounts = append(accounts, newAccountJSON(*keystore, account, activeTokens, keystoreConnected))
 	}
@@ -701,6 +1229,12 @@ func (handlers *Handlers) postBtcFormatUnit(r *http.Request) interface{} {
 	}
 	btcCoin.(*btc.Coin).SetFormatUnit(unit)
 
+	btcCoin, err = handlers.backend.Coin(coinpkg.CodeTSIG)
+	if err != nil {
+		return response{Success: false}
+	}
+	btcCoin.(*btc.Coin).SetFormatUnit(unit)
+
 	// update BTC format unit for fiat conversions
 	for _, account := range handlers.backend.Accounts() {
 		account.Config().BtcCurrencyUnit = unit
@@ -849,6 +1383,25 @@ func (handlers *Handlers) getAccountsTotalBalance(*http.Request) (interface{}, e
 	return response{Success: true, TotalBalance: totalBalance}, nil
 }
 
+// getPortfolio returns the balances of all active accounts per coin and per account, and their
+// total value in the main fiat currency.
+func (handlers *Handlers) getPortfolio(*http.Request) interface{} {
+	type response struct {
+		Success      bool               `json:"success"`
+		ErrorCode    string             `json:"errorCode,omitempty"`
+		ErrorMessage string             `json:"errorMessage,omitempty"`
+		Portfolio    *backend.Portfolio `json:"portfolio,omitempty"`
+	}
+	portfolio, err := handlers.backend.Portfolio()
+	if err != nil {
+		if errp.Cause(err) == rates.ErrRatesNotAvailable {
+			return response{Success: false, ErrorCode: err.Error()}
+		}
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true, Portfolio: portfolio}
+}
+
 func (handlers *Handlers) postSetAccountActive(r *http.Request) interface{} {
 	var jsonBody struct {
 		AccountCode accountsTypes.Code `json:"accountCode"`
@@ -869,6 +1422,22 @@ func (handlers *Handlers) postSetAccountActive(r *http.Request) interface{} {
 	return response{Success: true}
 }
 
+// postAccountArchive archives or unarchives the account given by the `code` path variable.
+// Archived accounts are inactive and not synced.
+func (handlers *Handlers) postAccountArchive(archive bool) func(*http.Request) interface{} {
+	return func(r *http.Request) interface{} {
+		type response struct {
+			Success      bool   `json:"success"`
+			ErrorMessage string `json:"errorMessage,omitempty"`
+		}
+		accountCode := accountsTypes.Code(mux.Vars(r)["code"])
+		if err := handlers.backend.SetAccountActive(accountCode, !archive); err != nil {
+			return response{Success: false, ErrorMessage: err.Error()}
+		}
+		return response{Success: true}
+	}
+}
+
 func (handlers *Handlers) postSetTokenActive(r *http.Request) interface{} {
 	var jsonBody struct {
 		AccountCode accountsTypes.Code `json:"accountCode"`
@@ -890,28 +1459,67 @@ func (handlers *Handlers) postSetTokenActive(r *http.Request) interface{} {
 	return response{Success: true}
 }
 
+type renameAccountResponse struct {
+	Success      bool   `json:"success"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	ErrorCode    string `json:"errorCode,omitempty"`
+}
+
+func (handlers *Handlers) renameAccount(accountCode accountsTypes.Code, name string) renameAccountResponse {
+	if err := handlers.backend.RenameAccount(accountCode, name); err != nil {
+		if errCode, ok := errp.Cause(err).(errp.ErrorCode); ok {
+			return renameAccountResponse{Success: false, ErrorCode: string(errCode)}
+		}
+		return renameAccountResponse{Success: false, ErrorMessage: err.Error()}
+	}
+	return renameAccountResponse{Success: true}
+}
+
 func (handlers *Handlers) postRenameAccount(r *http.Request) interface{} {
 	var jsonBody struct {
 		AccountCode accountsTypes.Code `json:"accountCode"`
 		Name        string             `json:"name"`
 	}
-
-	type response struct {
-		Success      bool   `json:"success"`
-		ErrorMessage string `json:"errorMessage,omitempty"`
-		ErrorCode    string `json:"errorCode,omitempty"`
+	if err := json.NewDecoder(r.Body).Decode(&jsonBody); err != nil {
+		return renameAccountResponse{Success: false, ErrorMessage: err.Error()}
 	}
+	return handlers.renameAccount(jsonBody.AccountCode, jsonBody.Name)
+}
 
+// postAccountRename renames the account given by the `code` path variable. The new name is
+// persisted and the accounts are reloaded in the frontend.
+func (handlers *Handlers) postAccountRename(r *http.Request) interface{} {
+	var jsonBody struct {
+		Name string `json:"name"`
+	}
 	if err := json.NewDecoder(r.Body).Decode(&jsonBody); err != nil {
-		return response{Success: false, ErrorMessage: err.Error()}
+		return renameAccountResponse{Success: false, ErrorMessage: err.Error()}
 	}
-	if err := handlers.backend.RenameAccount(jsonBody.AccountCode, jsonBody.Name); err != nil {
-		if errCode, ok := errp.Cause(err).(errp.ErrorCode); ok {
-			return response{Success: false, ErrorCode: string(errCode)}
-		}
-		return response{Success: false, ErrorMessage: err.Error()}
+	return handlers.renameAccount(accountsTypes.Code(mux.Vars(r)["code"]), jsonBody.Name)
+}
+
+// postAccountGapLimit sets the minimum gap limit of the account given by the `code` path variable.
+func (handlers *Handlers) postAccountGapLimit(r *http.Request) (interface{}, error) {
+	var gapLimit uint16
+	if err := json.NewDecoder(r.Body).Decode(&gapLimit); err != nil {
+		return nil, errp.WithStack(err)
 	}
-	return response{Success: true}
+	return nil, handlers.backend.SetAccountGapLimit(accountsTypes.Code(mux.Vars(r)["code"]), gapLimit)
+}
+
+// postAccountCoinSelection sets the coin selection algorithm of the account given by the `code`
+// path variable.
+func (handlers *Handlers) postAccountCoinSelection(r *http.Request) (interface{}, error) {
+	var coinSelection string
+	if err := json.NewDecoder(r.Body).Decode(&coinSelection); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return nil, handlers.backend.SetAccountCoinSelection(accountsTypes.Code(mux.Vars(r)["code"]), coinSelection)
+}
+
+// postAccountRescan scans the account given by the `code` path variable again from scratch.
+func (handlers *Handlers) postAccountRescan(r *http.Request) (interface{}, error) {
+	return nil, handlers.backend.RescanAccount(accountsTypes.Code(mux.Vars(r)["code"]))
 }
 
 func (handlers *Handlers) postAccountsReinitialize(*http.Request) interface{} {
@@ -940,45 +1548,255 @@ func (handlers *Handlers) postRegisterTestKeystore(r *http.Request) (interface{}
 	return nil, nil
 }
 
+func (handlers *Handlers) getHWIDevices(*http.Request) (interface{}, error) {
+	return handlers.backend.HWIDevices()
+}
+
+func (handlers *Handlers) postHWIConnect(r *http.Request) (interface{}, error) {
+	var fingerprint string
+	if err := json.NewDecoder(r.Body).Decode(&fingerprint); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return nil, handlers.backend.ConnectHWIDevice(fingerprint)
+}
+
+func (handlers *Handlers) postHWIDisconnect(r *http.Request) (interface{}, error) {
+	var fingerprint string
+	if err := json.NewDecoder(r.Body).Decode(&fingerprint); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return nil, handlers.backend.DisconnectHWIDevice(fingerprint)
+}
+
 func (handlers *Handlers) postDeregisterTestKeystore(*http.Request) interface{} {
 	handlers.backend.DeregisterKeystore()
 	return nil
 }
 
-func (handlers *Handlers) getRates(*http.Request) interface{} {
-	return handlers.backend.RatesUpdater().LatestPrice()
+// testSimulatorResponse is the response of all simulator endpoints.
+type testSimulatorResponse struct {
+	Success      bool   `json:"success"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// ErrorCode is "wrongPIN" or "simulatorReset" if unlocking failed.
+	ErrorCode string `json:"errorCode,omitempty"`
+	// Status is nil if no simulator was created.
+	Status *software.SimulatorStatus `json:"status"`
 }
 
-func (handlers *Handlers) getBTCParseExternalAmount(r *http.Request) interface{} {
-	type response struct {
-		Success bool   `json:"success"`
-		Amount  string `json:"amount"`
+func (handlers *Handlers) testSimulatorResponse(err error) testSimulatorResponse {
+	var status *software.SimulatorStatus
+	if simulator := handlers.backend.TestSimulator(); simulator != nil {
+		simulatorStatus := simulator.Status()
+		status = &simulatorStatus
 	}
-
-	amount := r.URL.Query().Get("amount")
-	amountRat, valid := new(big.Rat).SetString(amount)
-	if !valid {
-		return response{
-			Success: false,
+	if err != nil {
+		response := testSimulatorResponse{Success: false, ErrorMessage: err.Error(), Status: status}
+		switch errp.Cause(err) {
+		case software.ErrWrongPIN:
+			response.ErrorCode = "wrongPIN"
+		case software.ErrSimulatorReset:
+			response.ErrorCode = "simulatorReset"
 		}
+		return response
 	}
+	return testSimulatorResponse{Success: true, Status: status}
+}
 
-	btcCoin, err := handlers.backend.Coin(coinpkg.CodeBTC)
-	if err != nil {
-		handlers.log.WithError(err).Error("Could not get coin " + coinpkg.CodeBTC)
-		return response{
-			Success: false,
-		}
+// testSimulator returns the simulated device, or an error if testing is disabled or none was
+// created.
+func (handlers *Handlers) testSimulator() (*software.Simulator, error) {
+	if !handlers.backend.Testing() {
+		return nil, errp.New("Test simulator not available")
 	}
+	simulator := handlers.backend.TestSimulator()
+	if simulator == nil {
+		return nil, errp.New("no test simulator")
+	}
+	return simulator, nil
+}
 
-	coinAmount := btcCoin.SetAmount(amountRat, false)
-	return response{
-		Success: true,
-		Amount:  btcCoin.FormatAmount(coinAmount, false),
+func (handlers *Handlers) getTestSimulator(*http.Request) interface{} {
+	return handlers.testSimulatorResponse(nil)
+}
+
+// postCreateTestSimulator creates a simulated device. All fields except the PIN are optional.
+func (handlers *Handlers) postCreateTestSimulator(r *http.Request) interface{} {
+	if !handlers.backend.Testing() {
+		return handlers.testSimulatorResponse(errp.New("Test simulator not available"))
+	}
+	var request struct {
+		PIN         string               `json:"pin"`
+		ConfirmMode software.ConfirmMode `json:"confirmMode"`
+		LatencyMS   int64                `json:"latencyMs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return handlers.testSimulatorResponse(errp.WithStack(err))
+	}
+	handlers.backend.CreateTestSimulator(request.PIN)
+	simulator := handlers.backend.TestSimulator()
+	if request.ConfirmMode != "" {
+		if err := simulator.SetConfirmMode(request.ConfirmMode); err != nil {
+			return handlers.testSimulatorResponse(err)
+		}
 	}
+	simulator.SetLatency(time.Duration(request.LatencyMS) * time.Millisecond)
+	return handlers.testSimulatorResponse(nil)
 }
 
-func (handlers *Handlers) getConvertToPlainFiat(r *http.Request) interface{} {
+func (handlers *Handlers) postUnlockTestSimulator(r *http.Request) interface{} {
+	if _, err := handlers.testSimulator(); err != nil {
+		return handlers.testSimulatorResponse(err)
+	}
+	var pin string
+	if err := json.NewDecoder(r.Body).Decode(&pin); err != nil {
+		return handlers.testSimulatorResponse(errp.WithStack(err))
+	}
+	return handlers.testSimulatorResponse(handlers.backend.UnlockTestSimulator(pin))
+}
+
+// postTestSimulatorSettings changes the confirm mode and the latency. Omitted fields are not
+// changed.
+func (handlers *Handlers) postTestSimulatorSettings(r *http.Request) interface{} {
+	simulator, err := handlers.testSimulator()
+	if err != nil {
+		return handlers.testSimulatorResponse(err)
+	}
+	var request struct {
+		ConfirmMode *software.ConfirmMode `json:"confirmMode"`
+		LatencyMS   *int64                `json:"latencyMs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return handlers.testSimulatorResponse(errp.WithStack(err))
+	}
+	if request.ConfirmMode != nil {
+		if err := simulator.SetConfirmMode(*request.ConfirmMode); err != nil {
+			return handlers.testSimulatorResponse(err)
+		}
+	}
+	if request.LatencyMS != nil {
+		simulator.SetLatency(time.Duration(*request.LatencyMS) * time.Millisecond)
+	}
+	return handlers.testSimulatorResponse(nil)
+}
+
+// postTestSimulatorRespond accepts or rejects the pending prompt. The body is a JSON boolean.
+func (handlers *Handlers) postTestSimulatorRespond(r *http.Request) interface{} {
+	simulator, err := handlers.testSimulator()
+	if err != nil {
+		return handlers.testSimulatorResponse(err)
+	}
+	var accept bool
+	if err := json.NewDecoder(r.Body).Decode(&accept); err != nil {
+		return handlers.testSimulatorResponse(errp.WithStack(err))
+	}
+	return handlers.testSimulatorResponse(simulator.Respond(accept))
+}
+
+// getRates returns the latest exchange rates. If they could not be updated, e.g. because the app
+// is offline, the last known rates are returned and flagged as stale.
+func (handlers *Handlers) getRates(*http.Request) interface{} {
+	type response struct {
+		Rates map[string]map[string]float64 `json:"rates"`
+		Stale bool                          `json:"stale"`
+		// Updated is the unix timestamp at which the rates were fetched, zero if never.
+		Updated int64 `json:"updated"`
+	}
+	ratesUpdater := handlers.backend.RatesUpdater()
+	stale, updated := ratesUpdater.LatestPriceStatus()
+	result := response{Rates: ratesUpdater.LatestPrice(), Stale: stale}
+	if !updated.IsZero() {
+		result.Updated = updated.Unix()
+	}
+	return result
+}
+
+// getRatesHistory returns the cached historical exchange rates of a coin/fiat pair, e.g. to draw a
+// portfolio chart. Query params: `coin` (coin code), `fiat`, optional `from` and `to` (unix
+// timestamps in seconds) and optional `interval` (`hourly` or `daily`).
+func (handlers *Handlers) getRatesHistory(r *http.Request) interface{} {
+	type rate struct {
+		Time  int64   `json:"time"`
+		Value float64 `json:"value"`
+	}
+	type response struct {
+		Success      bool   `json:"success"`
+		Rates        []rate `json:"rates"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	query := r.URL.Query()
+	coinCode := query.Get("coin")
+	fiat := query.Get("fiat")
+	if coinCode == "" || fiat == "" {
+		return response{Success: false, ErrorMessage: "coin and fiat are required"}
+	}
+	parseTime := func(key string, defaultValue time.Time) (time.Time, error) {
+		value := query.Get(key)
+		if value == "" {
+			return defaultValue, nil
+		}
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, errp.Newf("invalid %s: %s", key, value)
+		}
+		return time.Unix(seconds, 0), nil
+	}
+	from, err := parseTime("from", time.Time{})
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	to, err := parseTime("to", time.Now())
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	var interval time.Duration
+	switch query.Get("interval") {
+	case "":
+	case "hourly":
+		interval = time.Hour
+	case "daily":
+		interval = 24 * time.Hour
+	default:
+		return response{Success: false, ErrorMessage: "invalid interval"}
+	}
+
+	historicalRates := handlers.backend.RatesUpdater().HistoricalRates(coinCode, fiat, from, to, interval)
+	result := make([]rate, len(historicalRates))
+	for i, historicalRate := range historicalRates {
+		result[i] = rate{Time: historicalRate.Timestamp.Unix(), Value: historicalRate.Value}
+	}
+	return response{Success: true, Rates: result}
+}
+
+func (handlers *Handlers) getBTCParseExternalAmount(r *http.Request) interface{} {
+	type response struct {
+		Success bool   `json:"success"`
+		Amount  string `json:"amount"`
+	}
+
+	amount := r.URL.Query().Get("amount")
+	amountRat, valid := new(big.Rat).SetString(amount)
+	if !valid {
+		return response{
+			Success: false,
+		}
+	}
+
+	btcCoin, err := handlers.backend.Coin(coinpkg.CodeBTC)
+	if err != nil {
+		handlers.log.WithError(err).Error("Could not get coin " + coinpkg.CodeBTC)
+		return response{
+			Success: false,
+		}
+	}
+
+	coinAmount := btcCoin.SetAmount(amountRat, false)
+	return response{
+		Success: true,
+		Amount:  btcCoin.FormatAmount(coinAmount, false),
+	}
+}
+
+func (handlers *Handlers) getConvertToPlainFiat(r *http.Request) interface{} {
 	coinCode := r.URL.Query().Get("from")
 	currency := r.URL.Query().Get("to")
 	amount := r.URL.Query().Get("amount")
@@ -1062,6 +1880,149 @@ func (handlers *Handlers) getHeadersStatus(coinCode coinpkg.Code) func(*http.Req
 	}
 }
 
+// getElectrumStatus returns the configured Electrum servers of a BTC-based coin and the one
+// currently in use.
+func (handlers *Handlers) getElectrumStatus(r *http.Request) (interface{}, error) {
+	coinCode := coinpkg.Code(mux.Vars(r)["code"])
+	coin, err := handlers.backend.Coin(coinCode)
+	if err != nil {
+		return nil, err
+	}
+	btcCoin, ok := coin.(*btc.Coin)
+	if !ok {
+		return nil, errp.Newf("coin %s does not use Electrum servers", coinCode)
+	}
+	reporter, ok := btcCoin.Blockchain().(electrum.StatusReporter)
+	if !ok {
+		// The coin is not initialized yet.
+		return &electrum.Status{Servers: []string{}}, nil
+	}
+	return reporter.Status(), nil
+}
+
+// getCoinServers returns the latency and tip height last measured for each Electrum server of a
+// BTC-based coin, e.g. for choosing a server. Servers are not measured if the coin only has one
+// server or if the servers are prioritized, in which case only the server in use is marked active.
+func (handlers *Handlers) getCoinServers(r *http.Request) (interface{}, error) {
+	coinCode := coinpkg.Code(mux.Vars(r)["code"])
+	coin, err := handlers.backend.Coin(coinCode)
+	if err != nil {
+		return nil, err
+	}
+	btcCoin, ok := coin.(*btc.Coin)
+	if !ok {
+		return nil, errp.Newf("coin %s does not use Electrum servers", coinCode)
+	}
+	switch blockchain := btcCoin.Blockchain().(type) {
+	case electrum.ServerMeasurementsReporter:
+		return blockchain.ServerMeasurements(), nil
+	case electrum.StatusReporter:
+		status := blockchain.Status()
+		measurements := make([]*electrum.ServerMeasurement, len(status.Servers))
+		for i, server := range status.Servers {
+			measurements[i] = &electrum.ServerMeasurement{
+				Server: server,
+				Active: server == status.ActiveServer,
+			}
+		}
+		return measurements, nil
+	default:
+		// The coin is not initialized yet.
+		return []*electrum.ServerMeasurement{}, nil
+	}
+}
+
+// postVerifyMessage checks a message signature of an address.
+func (handlers *Handlers) postVerifyMessage(r *http.Request) interface{} {
+	type response struct {
+		Success      bool   `json:"success"`
+		Valid        bool   `json:"valid"`
+		Format       string `json:"format,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var request struct {
+		Address   string `json:"address"`
+		Message   string `json:"message"`
+		Signature string `json:"signature"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	coinCode := coinpkg.Code(mux.Vars(r)["code"])
+	coin, err := handlers.backend.Coin(coinCode)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	btcCoin, ok := coin.(*btc.Coin)
+	if !ok {
+		return response{Success: false, ErrorMessage: "The coin must be BTC based to verify messages."}
+	}
+	format, valid, err := btcCoin.VerifyMessage(request.Address, request.Message, request.Signature)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true, Valid: valid, Format: string(format)}
+}
+
+// postBroadcastRawTransaction relays a hex encoded transaction signed elsewhere.
+func (handlers *Handlers) postBroadcastRawTransaction(r *http.Request) interface{} {
+	type response struct {
+		Success      bool   `json:"success"`
+		TxID         string `json:"txID,omitempty"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var request struct {
+		RawTx string `json:"rawTx"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	coinCode := coinpkg.Code(mux.Vars(r)["code"])
+	coin, err := handlers.backend.Coin(coinCode)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	btcCoin, ok := coin.(*btc.Coin)
+	if !ok {
+		return response{Success: false, ErrorMessage: "The coin must be BTC based to broadcast raw transactions."}
+	}
+	txID, err := btcCoin.BroadcastRawTransaction(r.Context(), request.RawTx)
+	if err != nil {
+		handlers.log.WithError(err).Error("Broadcasting the raw transaction failed")
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true, TxID: txID}
+}
+
+// postDecodeTransaction decodes a raw transaction or PSBT so the user can review it.
+func (handlers *Handlers) postDecodeTransaction(r *http.Request) interface{} {
+	type response struct {
+		Success      bool                    `json:"success"`
+		Transaction  *btc.DecodedTransaction `json:"transaction,omitempty"`
+		ErrorMessage string                  `json:"errorMessage,omitempty"`
+	}
+	var request struct {
+		Transaction string `json:"transaction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	coinCode := coinpkg.Code(mux.Vars(r)["code"])
+	coin, err := handlers.backend.Coin(coinCode)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	btcCoin, ok := coin.(*btc.Coin)
+	if !ok {
+		return response{Success: false, ErrorMessage: "The coin must be BTC based to decode transactions."}
+	}
+	transaction, err := btcCoin.DecodeTransaction(request.Transaction)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true, Transaction: transaction}
+}
+
 func (handlers *Handlers) postCertsDownload(r *http.Request) interface{} {
 	var server string
 	if err := json.NewDecoder(r.Body).Decode(&server); err != nil {
@@ -1070,12 +2031,11 @@ func (handlers *Handlers) postCertsDownload(r *http.Request) interface{} {
 			"errorMessage": err.Error(),
 		}
 	}
-	pemCert, err := handlers.backend.DownloadCert(server)
+	ctx, cancel := context.WithTimeout(r.Context(), electrumRequestTimeout)
+	defer cancel()
+	pemCert, err := handlers.backend.DownloadCert(ctx, server)
 	if err != nil {
-		return map[string]interface{}{
-			"success":      false,
-			"errorMessage": err.Error(),
-		}
+		return newErrorResult(err)
 	}
 	return map[string]interface{}{
 		"success": true,
@@ -1092,15 +2052,14 @@ func (handlers *Handlers) postElectrumCheck(r *http.Request) interface{} {
 		}
 	}
 
-	if err := handlers.backend.CheckElectrumServer(&serverInfo); err != nil {
+	ctx, cancel := context.WithTimeout(r.Context(), electrumRequestTimeout)
+	defer cancel()
+	if err := handlers.backend.CheckElectrumServer(ctx, &serverInfo); err != nil {
 		handlers.log.
 			WithError(err).
 			WithField("server-info", serverInfo.String()).
 			Info("checking electrum connection failed")
-		return map[string]interface{}{
-			"success":      false,
-			"errorMessage": err.Error(),
-		}
+		return newErrorResult(err)
 	}
 	handlers.log.
 		WithField("server-info", serverInfo.String()).
@@ -1110,6 +2069,22 @@ func (handlers *Handlers) postElectrumCheck(r *http.Request) interface{} {
 	}
 }
 
+func (handlers *Handlers) getPinnedCerts(*http.Request) interface{} {
+	return handlers.backend.PinnedCerts()
+}
+
+func (handlers *Handlers) getPinnedCert(r *http.Request) (interface{}, error) {
+	return handlers.backend.PinnedCert(mux.Vars(r)["fingerprint"])
+}
+
+func (handlers *Handlers) postRemovePinnedCert(r *http.Request) (interface{}, error) {
+	var fingerprint string
+	if err := json.NewDecoder(r.Body).Decode(&fingerprint); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return nil, handlers.backend.RemovePinnedCert(fingerprint)
+}
+
 func (handlers *Handlers) postSocksProxyCheck(r *http.Request) interface{} {
 	type response struct {
 		Success      bool   `json:"success"`
@@ -1126,10 +2101,7 @@ func (handlers *Handlers) postSocksProxyCheck(r *http.Request) interface{} {
 
 	err := socksproxy.NewSocksProxy(true, endpoint).Validate()
 	if err != nil {
-		return response{
-			Success:      false,
-			ErrorMessage: err.Error(),
-		}
+		return newErrorResult(err)
 	}
 	return response{
 		Success: true,
@@ -1137,13 +2109,52 @@ func (handlers *Handlers) postSocksProxyCheck(r *http.Request) interface{} {
 }
 
 func (handlers *Handlers) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if lockout := handlers.apiData.authLimiter.lockedOut(remoteHost(r.RemoteAddr)); lockout > 0 {
+		handlers.log.Error("Too many unauthorized websocket connections. WARNING: this could be an attack on the API")
+		writeTooManyRequests(w, lockout)
+		return
+	}
+	// The events carry account and device data, so they are only sent after the app was unlocked.
+	if handlers.backend.AppLocked() {
+		writeAPIError(w, http.StatusForbidden, errorCodeAppLocked, "app locked")
+		return
+	}
 	conn, err := handlers.websocketUpgrader.Upgrade(w, r, nil)
 	if err != nil {
 		panic(err)
 	}
 
-	sendChan, quitChan := runWebsocket(conn, handlers.apiData, handlers.log)
+	subscriptions := newSubscriptions()
+	// Buffered so the read loop does not block. A client requests a replay once after connecting.
+	replayChan := make(chan uint64, 1)
+	onMessage := func(msg []byte) error {
+		if lastEventID, ok := parseReplayMessage(msg); ok {
+			select {
+			case replayChan <- lastEventID:
+			default:
+			}
+			return nil
+		}
+		return subscriptions.handleMessage(msg)
+	}
+	sendChan, quitChan := runWebsocket(conn, handlers.apiData, onMessage, handlers.log)
+	events, subscribedAfterID, unsubscribe := handlers.eventHub.subscribe()
+	websocketClientsMetric.Inc()
 	go func() {
+		defer websocketClientsMetric.Dec()
+		defer unsubscribe()
+		send := func(eventJSON []byte) bool {
+			// Events dropped while the app is locked can't be replayed later either.
+			if handlers.backend.AppLocked() && !sentWhileAppLocked(eventJSON) {
+				return true
+			}
+			select {
+			case <-quitChan:
+				return false
+			case sendChan <- eventJSON:
+				return true
+			}
+		}
 		for {
 			select {
 			case <-quitChan:
@@ -1152,8 +2163,24 @@ func (handlers *Handlers) eventsHandler(w http.ResponseWriter, r *http.Request)
 				select {
 				case <-quitChan:
 					return
-				case event := <-handlers.backendEvents:
-					sendChan <- jsonp.MustMarshal(event)
+				case lastEventID := <-replayChan:
+					replayed, complete := handlers.eventHub.events.since(lastEventID)
+					if !complete && !send(replayIncompleteEvent) {
+						return
+					}
+					for _, event := range replayed {
+						// Later events are received from the hub.
+						if event.id > subscribedAfterID {
+							break
+						}
+						if subscriptions.matches(event.json) && !send(event.json) {
+							return
+						}
+					}
+				case event := <-events:
+					if subscriptions.matches(event.json) && !send(event.json) {
+						return
+					}
 				}
 			}
 		}
@@ -1172,15 +2199,24 @@ func isAPITokenValid(w http.ResponseWriter, r *http.Request, apiData *Connection
 		return true
 	}
 
+	host := remoteHost(r.RemoteAddr)
+	if lockout := apiData.authLimiter.lockedOut(host); lockout > 0 {
+		methodLogEntry.Error("Too many unauthorized API requests. WARNING: this could be an attack on the API")
+		writeTooManyRequests(w, lockout)
+		return false
+	}
 	if len(r.Header.Get("Authorization")) == 0 {
 		methodLogEntry.Error("Missing token in API request. WARNING: this could be an attack on the API")
-		http.Error(w, "missing token "+r.URL.Path, http.StatusUnauthorized)
+		apiData.authLimiter.fail(host)
+		writeAPIError(w, http.StatusUnauthorized, errorCodeUnauthorized, "missing token "+r.URL.Path)
 		return false
-	} else if len(r.Header.Get("Authorization")) != 0 && r.Header.Get("Authorization") != "Basic "+apiData.token {
+	} else if !apiData.isAuthorized(r.Header.Get("Authorization")) {
 		methodLogEntry.Error("Incorrect token in API request. WARNING: this could be an attack on the API")
-		http.Error(w, "incorrect token", http.StatusUnauthorized)
+		apiData.authLimiter.fail(host)
+		writeAPIError(w, http.StatusUnauthorized, errorCodeUnauthorized, "incorrect token")
 		return false
 	}
+	apiData.authLimiter.succeed(host)
 	return true
 }
 
@@ -1193,16 +2229,118 @@ func ensureAPITokenValid(h http.Handler, apiData *ConnectionData, log *logrus.En
 	})
 }
 
-func (handlers *Handlers) apiMiddleware(devMode bool, h func(*http.Request) (interface{}, error)) http.Handler {
+// appLockScreenRoutes are the API routes served while the app is locked with the app password,
+// with their allowed methods. They are only what the lock screen needs to render and to unlock the
+// app. All other routes respond with the `appLocked` error code until the app is unlocked.
+var appLockScreenRoutes = map[string][]string{
+	"/api/unlock":            {http.MethodPost},
+	"/api/app-password":      {http.MethodGet},
+	"/api/config":            {http.MethodGet},
+	"/api/version":           {http.MethodGet},
+	"/api/native-locale":     {http.MethodGet},
+	"/api/detect-dark-theme": {http.MethodGet},
+	"/api/set-dark-theme":    {http.MethodPost},
+}
+
+// servedWhileAppLocked returns true if the request is allowed while the app is locked, see
+// appLockScreenRoutes.
+func servedWhileAppLocked(r *http.Request) bool {
+	return slices.Contains(appLockScreenRoutes[r.URL.Path], r.Method)
+}
+
+// ensureAppUnlocked wraps the given handler to respond with an error to all requests but the ones
+// of the lock screen while the app is locked, see appLockScreenRoutes.
+func (handlers *Handlers) ensureAppUnlocked(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			// recover from all panics and log error before panicking again
-			if r := recover(); r != nil {
-				handlers.log.WithField("panic", true).Errorf("%v\n%s", r, string(debug.Stack()))
-				writeJSON(w, map[string]string{"error": fmt.Sprintf("%v", r)})
-			}
-		}()
+		if handlers.backend.AppLocked() && !servedWhileAppLocked(r) {
+			writeAPIError(w, http.StatusForbidden, errorCodeAppLocked, "app locked")
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// sentWhileAppLocked returns true if the event is sent to websocket clients while the app is
+// locked. Only the events of the backend itself are, e.g. the `locked` event, so clients connected
+// before the app was locked don't receive account and device data.
+func sentWhileAppLocked(event []byte) bool {
+	var fields struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(event, &fields) == nil && fields.Type == "backend"
+}
+
+// recordActivity wraps the given handler to restart the auto-lock timer on each API request.
+func (handlers *Handlers) recordActivity(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlers.backend.RecordActivity()
+		h.ServeHTTP(w, r)
+	})
+}
 
+var (
+	handlerDurationMetric = metrics.NewSummaryVec(
+		"api_handler_duration_seconds", "Duration of API handler calls.", "route")
+	websocketClientsMetric = metrics.NewGauge(
+		"websocket_clients", "Number of connected websocket clients.")
+)
+
+// getMetrics serves the metrics in the Prometheus text format. It is only available in dev mode.
+func (handlers *Handlers) getMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := metrics.WriteText(w); err != nil {
+		handlers.log.WithError(err).Error("Error writing metrics")
+	}
+}
+
+// routeName returns the path template of the route handling the request, so that requests to e.g.
+// different accounts are measured together.
+func routeName(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
+
+// getRequestTimeout is the time after which GET requests are answered with a timeout error if the
+// handler did not return yet, e.g. because a server does not respond. POST requests have no
+// timeout, as they can wait for the user to confirm on the device.
+const getRequestTimeout = 60 * time.Second
+
+// electrumRequestTimeout limits requests connecting to an Electrum server chosen by the user.
+const electrumRequestTimeout = 30 * time.Second
+
+// handlerResult is the result of an API handler call.
+type handlerResult struct {
+	value interface{}
+	err   error
+	// panicValue is the value the handler panicked with, nil if it did not panic.
+	panicValue interface{}
+	// panicStack is the stack trace of the panic.
+	panicStack string
+}
+
+// callHandler calls the API handler, recovering from panics.
+func callHandler(h func(*http.Request) (interface{}, error), r *http.Request) (result handlerResult) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			stack := string(debug.Stack())
+			result = handlerResult{panicValue: fmt.Sprintf("%v\n%s", panicValue, stack), panicStack: stack}
+			result.err = fmt.Errorf("%v", panicValue)
+		}
+	}()
+	value, err := h(r)
+	return handlerResult{value: value, err: err}
+}
+
+// apiMiddleware serves the value returned by an API handler as JSON. The request context passed
+// to the handler has a deadline for GET requests, see getRequestTimeout. Concurrent GET requests
+// for the same URI share one handler call, see getCalls.
+func (handlers *Handlers) apiMiddleware(devMode bool, h func(*http.Request) (interface{}, error)) http.Handler {
+	calls := newGetCalls()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		if devMode {
 			// This enables us to run a server on a different port serving just the UI, while still
@@ -1213,13 +2351,49 @@ func (handlers *Handlers) apiMiddleware(devMode bool, h func(*http.Request) (int
 			}
 			w.Header().Set("Access-Control-Allow-Origin", fmt.Sprintf("http://localhost:%s", vitePort))
 		}
-		value, err := h(r)
-		if err != nil {
-			handlers.log.WithError(err).Error("endpoint failed")
-			writeJSON(w, map[string]string{"error": err.Error()})
+		log := logging.WithContext(handlers.log, r.Context())
+		start := time.Now()
+		defer func() {
+			handlerDurationMetric.Observe(routeName(r), time.Since(start).Seconds())
+		}()
+
+		var result handlerResult
+		if r.Method == http.MethodGet {
+			// The call can be shared with later requests, so it is not canceled with this one.
+			running := calls.call(r.URL.RequestURI(), func() handlerResult {
+				ctx, cancel := context.WithTimeout(
+					context.WithoutCancel(r.Context()), getRequestTimeout)
+				defer cancel()
+				return callHandler(h, r.WithContext(ctx))
+			})
+			timer := time.NewTimer(getRequestTimeout)
+			defer timer.Stop()
+			select {
+			case <-running.done:
+				result = running.result
+			case <-r.Context().Done():
+				return
+			case <-timer.C:
+				log.Error("endpoint timed out")
+				writeAPIError(w, http.StatusGatewayTimeout, errorCodeTimeout, "request timed out")
+				return
+			}
+		} else {
+			result = callHandler(h, r)
+		}
+
+		if result.panicValue != nil {
+			log.WithField("panic", true).Error(result.panicValue)
+			handlers.backend.ReportCrash(result.err.Error(), result.panicStack)
+			writeAPIError(w, http.StatusInternalServerError, errorCodeInternal, result.err.Error())
 			return
 		}
-		writeJSON(w, value)
+		if result.err != nil {
+			log.WithError(result.err).Error("endpoint failed")
+			writeHandlerError(w, result.err)
+			return
+		}
+		writeJSON(w, result.value)
 	})
 }
 
@@ -1238,15 +2412,20 @@ func (handlers *Handlers) getAccountSummary(*http.Request) interface{} {
 }
 
 // getSupportedCoinsHandler returns an array of coin codes for which you can add an account.
-// Exactly one keystore must be connected, otherwise an empty array is returned.
-func (handlers *Handlers) getSupportedCoins(*http.Request) interface{} {
+// The optional `rootFingerprint` query param (hex) selects the keystore if more than one is
+// connected. If no keystore is connected, an empty array is returned.
+func (handlers *Handlers) getSupportedCoins(r *http.Request) interface{} {
 	type element struct {
 		CoinCode             coinpkg.Code `json:"coinCode"`
 		Name                 string       `json:"name"`
 		CanAddAccount        bool         `json:"canAddAccount"`
 		SuggestedAccountName string       `json:"suggestedAccountName"`
 	}
-	keystore := handlers.backend.Keystore()
+	rootFingerprint, err := hex.DecodeString(r.URL.Query().Get("rootFingerprint"))
+	if err != nil {
+		return []string{}
+	}
+	keystore := handlers.keystore(rootFingerprint)
 	if keystore == nil {
 		return []string{}
 	}
@@ -1286,7 +2465,7 @@ func (handlers *Handlers) postBitsuranceLookup(r *http.Request) interface{} {
 		handlers.log.Error(err)
 		return response{Success: false, ErrorMessage: err.Error()}
 	}
-	insuredAccounts, err := handlers.backend.LookupInsuredAccounts(request.AccountCode)
+	insuredAccounts, err := handlers.backend.LookupInsuredAccounts(r.Context(), request.AccountCode)
 	if err != nil {
 		handlers.log.Error(err)
 		return response{Success: false, ErrorMessage: err.Error()}
@@ -1296,12 +2475,7 @@ func (handlers *Handlers) postBitsuranceLookup(r *http.Request) interface{} {
 }
 
 func (handlers *Handlers) getBitsuranceURL(r *http.Request) interface{} {
-	lang := handlers.backend.Config().AppConfig().Backend.UserLanguage
-	if len(lang) == 0 {
-		// userLanguage config is empty if the set locale matches the system locale, so we have
-		// to retrieve that.
-		lang = utilConfig.MainLocaleFromNative(handlers.backend.Environment().NativeLocale())
-	}
+	lang := handlers.backend.UserLanguage()
 
 	return bitsurance.WidgetURL(handlers.backend.DevServers(), lang)
 }
@@ -1335,7 +2509,7 @@ func (handlers *Handlers) getExchangeDeals(r *http.Request) interface{} {
 	}
 
 	regionCode := r.URL.Query().Get("region")
-	exchangeDealsLists, err := exchanges.GetExchangeDeals(acct, regionCode, action, handlers.backend.HTTPClient())
+	exchangeDealsLists, err := exchanges.GetExchangeDeals(r.Context(), acct, regionCode, action, handlers.backend.HTTPClient())
 	if err != nil {
 		return errorResult{Success: false, ErrorCode: err.Error()}
 	}
@@ -1409,12 +2583,7 @@ func (handlers *Handlers) getExchangeMoonpayBuyInfo(r *http.Request) (interface{
 		return nil, err
 	}
 
-	lang := handlers.backend.Config().AppConfig().Backend.UserLanguage
-	if len(lang) == 0 {
-		// userLanguage config is empty if the set locale matches the system locale, so we have
-		// to retrieve that.
-		lang = utilConfig.MainLocaleFromNative(handlers.backend.Environment().NativeLocale())
-	}
+	lang := handlers.backend.UserLanguage()
 	params := exchanges.BuyMoonpayParams{
 		Fiat: handlers.backend.Config().AppConfig().Backend.MainFiat,
 		Lang: lang,
@@ -1461,12 +2630,7 @@ func (handlers *Handlers) getExchangeBtcDirectInfo(r *http.Request) interface{}
 }
 
 func (handlers *Handlers) getExchangePocketURL(r *http.Request) interface{} {
-	lang := handlers.backend.Config().AppConfig().Backend.UserLanguage
-	if len(lang) == 0 {
-		// userLanguage config is empty if the set locale matches the system locale, so we have
-		// to retrieve that.
-		lang = utilConfig.MainLocaleFromNative(handlers.backend.Environment().NativeLocale())
-	}
+	lang := handlers.backend.UserLanguage()
 
 	action, err := exchanges.ParseAction(mux.Vars(r)["action"])
 	if err != nil {
@@ -1538,6 +2702,26 @@ func (handlers *Handlers) postAOPPCancel(r *http.Request) interface{} {
 	return nil
 }
 
+// postHandleURI is called by the app shells when the operating system opens a URI of a registered
+// scheme with the app, e.g. `bitcoin:...`, if they can't call the backend directly.
+func (handlers *Handlers) postHandleURI(r *http.Request) (interface{}, error) {
+	var uri string
+	if err := json.NewDecoder(r.Body).Decode(&uri); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	handlers.backend.HandleURI(uri)
+	return nil, nil
+}
+
+func (handlers *Handlers) getPaymentURI(*http.Request) interface{} {
+	return handlers.backend.PaymentURI()
+}
+
+func (handlers *Handlers) postPaymentURIClear(*http.Request) interface{} {
+	handlers.backend.PaymentURIClear()
+	return nil
+}
+
 func (handlers *Handlers) postAOPPApprove(r *http.Request) interface{} {
 	handlers.backend.AOPPApprove()
 	return nil
@@ -1548,6 +2732,11 @@ func (handlers *Handlers) postCancelConnectKeystore(r *http.Request) interface{}
 	return nil
 }
 
+func (handlers *Handlers) postLock(r *http.Request) interface{} {
+	handlers.backend.Lock()
+	return nil
+}
+
 func (handlers *Handlers) postSetWatchonly(r *http.Request) interface{} {
 	type response struct {
 		Success bool `json:"success"`
@@ -1565,6 +2754,20 @@ func (handlers *Handlers) postSetWatchonly(r *http.Request) interface{} {
 	return response{Success: true}
 }
 
+func (handlers *Handlers) getSyncState(*http.Request) interface{} {
+	return handlers.backend.SyncState()
+}
+
+// postSyncState is called by the frontend when the power or network state of the device changes.
+func (handlers *Handlers) postSyncState(r *http.Request) (interface{}, error) {
+	var state syncscheduler.State
+	if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	handlers.backend.SetSyncState(state)
+	return nil, nil
+}
+
 func (handlers *Handlers) postOnAuthSettingChanged(r *http.Request) interface{} {
 	handlers.backend.Environment().OnAuthSettingChanged(
 		handlers.backend.Config().AppConfig().Backend.Authentication)
@@ -1583,6 +2786,46 @@ func (handlers *Handlers) postExportLog(r *http.Request) interface{} {
 	return result{Success: true}
 }
 
+func (handlers *Handlers) postExportDebugBundle(r *http.Request) interface{} {
+	type result struct {
+		Success bool   `json:"success"`
+		Message string `json:"message,omitempty"`
+		Aborted bool   `json:"aborted"`
+	}
+	if err := handlers.backend.ExportDebugBundle(); err != nil {
+		if errp.Cause(err) == errp.ErrUserAbort {
+			return result{Success: false, Aborted: true}
+		}
+		handlers.log.WithError(err).Error("Error exporting debug bundle")
+		return result{Success: false, Message: err.Error()}
+	}
+	return result{Success: true}
+}
+
+func (handlers *Handlers) getCrashReports(*http.Request) (interface{}, error) {
+	return handlers.backend.CrashReports(), nil
+}
+
+func (handlers *Handlers) getNotifications(*http.Request) (interface{}, error) {
+	return handlers.backend.Notifications(), nil
+}
+
+func (handlers *Handlers) postMarkAllNotificationsRead(*http.Request) (interface{}, error) {
+	return nil, handlers.backend.MarkAllNotificationsRead()
+}
+
+// postID returns a handler calling f with the ID in the request body, used e.g. to send and delete
+// crash reports or to mark notifications as read.
+func (handlers *Handlers) postID(f func(id string) error) func(*http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		var id string
+		if err := json.NewDecoder(r.Body).Decode(&id); err != nil {
+			return nil, errp.WithStack(err)
+		}
+		return nil, f(id)
+	}
+}
+
 func (handlers *Handlers) postExportNotes(r *http.Request) interface{} {
 	type result struct {
 		Success bool   `json:"success"`
@@ -1622,6 +2865,59 @@ func (handlers *Handlers) postImportNotes(r *http.Request) interface{} {
 	return result{Success: true, Data: data}
 }
 
+func (handlers *Handlers) getAddressBook(*http.Request) interface{} {
+	return handlers.backend.AddressBook().Contacts()
+}
+
+func (handlers *Handlers) postAddressBookAdd(r *http.Request) interface{} {
+	type response struct {
+		Success      bool                 `json:"success"`
+		Contact      *addressbook.Contact `json:"contact,omitempty"`
+		ErrorMessage string               `json:"errorMessage,omitempty"`
+	}
+	var contact addressbook.Contact
+	if err := json.NewDecoder(r.Body).Decode(&contact); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	added, err := handlers.backend.AddContact(contact)
+	if err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true, Contact: added}
+}
+
+func (handlers *Handlers) postAddressBookUpdate(r *http.Request) interface{} {
+	type response struct {
+		Success      bool   `json:"success"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var contact addressbook.Contact
+	if err := json.NewDecoder(r.Body).Decode(&contact); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	if err := handlers.backend.UpdateContact(contact); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true}
+}
+
+func (handlers *Handlers) postAddressBookDelete(r *http.Request) interface{} {
+	type response struct {
+		Success      bool   `json:"success"`
+		ErrorMessage string `json:"errorMessage,omitempty"`
+	}
+	var jsonBody struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jsonBody); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	if err := handlers.backend.DeleteContact(jsonBody.ID); err != nil {
+		return response{Success: false, ErrorMessage: err.Error()}
+	}
+	return response{Success: true}
+}
+
 func (handlers *Handlers) getBluetoothState(r *http.Request) interface{} {
 	return handlers.backend.Bluetooth().State()
 }
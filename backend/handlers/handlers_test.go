@@ -94,6 +94,149 @@ func TestGetNativeLocale(t *testing.T) {
 	}
 }
 
+func TestAppPassword(t *testing.T) {
+	args := arguments.NewArguments(
+		test.TstTempDir("apppassword"),
+		true,  // testing
+		false, // regtest
+		true,  // devservers
+		nil,   // gap limits
+	)
+	back, err := backend.NewBackend(args, &backendEnv{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer back.Close()
+	if err := back.EnableAppPassword("password"); err != nil {
+		t.Fatal(err)
+	}
+	back.Lock()
+
+	h := handlers.NewHandlers(back, handlers.NewConnectionData(0, ""))
+	serve := func(method, path, body string) *http.Response {
+		w := httptest.NewRecorder()
+		h.Router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w.Result()
+	}
+	type unlockResult struct {
+		Success   bool   `json:"success"`
+		ErrorCode string `json:"errorCode"`
+	}
+	unlock := func(password string) unlockResult {
+		var result unlockResult
+		test.DecodeHandlerResponse(t, &result, serve(http.MethodPost, "/api/unlock", password).Body)
+		return result
+	}
+
+	// Only the routes of the lock screen are served while locked.
+	for _, route := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/accounts", http.StatusForbidden},
+		{http.MethodGet, "/api/events", http.StatusForbidden},
+		{http.MethodPost, "/api/config", http.StatusForbidden},
+		{http.MethodPost, "/api/export-log", http.StatusForbidden},
+		{http.MethodGet, "/api/config", http.StatusOK},
+		{http.MethodGet, "/api/v1/config", http.StatusOK},
+		{http.MethodGet, "/api/native-locale", http.StatusOK},
+		{http.MethodGet, "/api/version", http.StatusOK},
+	} {
+		if status := serve(route.method, route.path, "").StatusCode; status != route.status {
+			t.Errorf("%s %s status = %d; want %d", route.method, route.path, status, route.status)
+		}
+	}
+	if result := unlock(`"wrong"`); result.Success || result.ErrorCode != "wrongPassword" {
+		t.Errorf("unlock with the wrong password = %+v", result)
+	}
+	if !unlock(`"password"`).Success {
+		t.Error("could not unlock with the password")
+	}
+	if status := serve(http.MethodGet, "/api/accounts", "").StatusCode; status != http.StatusOK {
+		t.Errorf("accounts status = %d; want %d", status, http.StatusOK)
+	}
+
+	// Wrong passwords are throttled.
+	back.Lock()
+	for i := 0; i < 5; i++ {
+		if unlock(`"wrong"`).Success {
+			t.Fatal("unlocked with the wrong password")
+		}
+	}
+	if result := unlock(`"password"`); result.Success || result.ErrorCode != "tooManyAttempts" {
+		t.Errorf("unlock after too many wrong passwords = %+v", result)
+	}
+}
+
+func TestPostAppConfig(t *testing.T) {
+	args := arguments.NewArguments(
+		test.TstTempDir("postappconfig"),
+		true,  // testing
+		false, // regtest
+		true,  // devservers
+		nil,   // gap limits
+	)
+	back, err := backend.NewBackend(args, &backendEnv{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer back.Close()
+
+	h := handlers.NewHandlers(back, handlers.NewConnectionData(0, ""))
+	body := `{"backend": {"mainFiat": "CHF", "hwiPath": "/tmp/payload"}}`
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/config", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want %d", w.Code, http.StatusOK)
+	}
+	appConfig := back.Config().AppConfig()
+	if appConfig.Backend.MainFiat != "CHF" {
+		t.Errorf("mainFiat = %q; want %q", appConfig.Backend.MainFiat, "CHF")
+	}
+	// The HWI executable can't be changed through the API.
+	if appConfig.Backend.HWIPath != "" {
+		t.Errorf("hwiPath = %q; want it unchanged", appConfig.Backend.HWIPath)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	serve := func(connectionData *handlers.ConnectionData, paths ...string) *httptest.ResponseRecorder {
+		args := arguments.NewArguments(
+			test.TstTempDir("metrics"),
+			true,  // testing
+			false, // regtest
+			true,  // devservers
+			nil,   // gap limits
+		)
+		back, err := backend.NewBackend(args, &backendEnv{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer back.Close()
+		h := handlers.NewHandlers(back, connectionData)
+		var w *httptest.ResponseRecorder
+		for _, path := range paths {
+			w = httptest.NewRecorder()
+			h.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		return w
+	}
+
+	w := serve(handlers.NewConnectionData(0, ""), "/api/native-locale", "/api/metrics")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want %d", w.Code, http.StatusOK)
+	}
+	if want := `api_handler_duration_seconds_count{route="/api/native-locale"}`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("metrics do not contain %q:\n%s", want, w.Body.String())
+	}
+
+	// Not available outside of dev mode.
+	if w := serve(handlers.NewConnectionData(8082, "token"), "/api/metrics"); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d; want %d", w.Code, http.StatusNotFound)
+	}
+}
+
 // List all routes with `go test backend/handlers/handlers_test.go -v`.
 func TestListRoutes(t *testing.T) {
 	const skip = true
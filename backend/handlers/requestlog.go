@@ -0,0 +1,115 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bufio"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/random"
+	"github.com/sirupsen/logrus"
+)
+
+// requestIDHeader is the header carrying the request ID. Clients can set it to correlate their own
+// logs with the backend logs, otherwise a random ID is assigned. It is returned in the response.
+const requestIDHeader = "X-Request-ID"
+
+// slowRequestThreshold is the duration after which a request is logged as slow.
+const slowRequestThreshold = 2 * time.Second
+
+// validRequestID matches request IDs accepted from clients.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// statusRecorder records the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (recorder *statusRecorder) Write(b []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	return recorder.ResponseWriter.Write(b)
+}
+
+// Hijack implements http.Hijacker, needed to upgrade to a websocket.
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errp.New("hijacking not supported")
+	}
+	if recorder.status == 0 {
+		recorder.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// requestLogMiddleware assigns an ID to each request, which is added to the request context and
+// can be retrieved with logging.RequestID(). Each request is logged with its method, path, status
+// and duration when it is done.
+func requestLogMiddleware(log *logrus.Entry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Requests to versioned paths pass through the router twice.
+			if logging.RequestID(r.Context()) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			start := time.Now()
+			requestID := r.Header.Get(requestIDHeader)
+			if !validRequestID.MatchString(requestID) {
+				requestID = hex.EncodeToString(random.BytesOrPanic(8))
+			}
+			w.Header().Set(requestIDHeader, requestID)
+			r = r.WithContext(logging.WithRequestID(r.Context(), requestID))
+			recorder := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				duration := time.Since(start)
+				entry := log.WithFields(logrus.Fields{
+					"requestID": requestID,
+					"method":    r.Method,
+					"path":      r.URL.Path,
+					"status":    recorder.status,
+					"duration":  duration.String(),
+				})
+				switch {
+				case recorder.status >= http.StatusInternalServerError:
+					entry.Error("request failed")
+				case duration > slowRequestThreshold:
+					entry.Warn("slow request")
+				default:
+					entry.Debug("request")
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(requestLogMiddleware(logging.Get().WithGroup("test")))
+	router.PathPrefix(versionedAPIPathPrefix).Handler(versionedAPIHandler(router))
+	var requestIDs []string
+	router.HandleFunc("/api/test", func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, logging.RequestID(r.Context()))
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	serve := func(path string, requestID string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		if requestID != "" {
+			request.Header.Set(requestIDHeader, requestID)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	response := serve("/api/test", "client-id-1")
+	require.Equal(t, http.StatusTeapot, response.Code)
+	require.Equal(t, "client-id-1", response.Header().Get(requestIDHeader))
+	require.Equal(t, []string{"client-id-1"}, requestIDs)
+
+	// Invalid IDs are replaced by a random one.
+	response = serve("/api/test", "invalid id\n")
+	generated := response.Header().Get(requestIDHeader)
+	require.Len(t, generated, 16)
+	require.Equal(t, generated, requestIDs[1])
+
+	// Versioned requests keep their ID when passing through the router again.
+	response = serve("/api/v1/test", "")
+	require.Equal(t, requestIDs[2], response.Header().Get(requestIDHeader))
+	require.Len(t, requestIDs[2], 16)
+}
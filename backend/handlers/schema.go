@@ -0,0 +1,157 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// schemaPathPatterns replace the account codes and device IDs in the paths of the routes registered
+// per account and device by a path parameter, so each of these routes is listed once in the schema.
+// The first matching pattern is applied.
+var schemaPathPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`^/api/account/[^/{}]+/`), "/api/account/{code}/"},
+	{regexp.MustCompile(`^/api/devices/bitbox02-bootloader/[^/{}]+/`), "/api/devices/bitbox02-bootloader/{deviceID}/"},
+	{regexp.MustCompile(`^/api/devices/bitbox02/[^/{}]+/`), "/api/devices/bitbox02/{deviceID}/"},
+	{regexp.MustCompile(`^/api/devices/[^/{}]+/`), "/api/devices/{deviceID}/"},
+}
+
+// routeVariablePattern matches a route variable with an optional pattern, e.g. `{code}` or
+// `{apiVersion:[0-9]+}`.
+var routeVariablePattern = regexp.MustCompile(`\{([^:{}]+)(:[^{}]+)?\}`)
+
+// openAPIPath converts a mux path template to an OpenAPI path and returns the names of its path
+// parameters.
+func openAPIPath(pathTemplate string) (string, []string) {
+	for _, pathPattern := range schemaPathPatterns {
+		if pathPattern.pattern.MatchString(pathTemplate) {
+			pathTemplate = pathPattern.pattern.ReplaceAllString(pathTemplate, pathPattern.replacement)
+			break
+		}
+	}
+	path := routeVariablePattern.ReplaceAllString(pathTemplate, "{$1}")
+	var parameters []string
+	for _, match := range routeVariablePattern.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, match[1])
+	}
+	return path, parameters
+}
+
+type openAPIParameter struct {
+	Name     string            `json:"name"`
+	In       string            `json:"in"`
+	Required bool              `json:"required"`
+	Schema   map[string]string `json:"schema"`
+}
+
+type openAPIMediaType struct {
+	Schema map[string]interface{} `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIOperation struct {
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// openAPISchema is an OpenAPI 3 description of the API.
+type openAPISchema struct {
+	OpenAPI string                                 `json:"openapi"`
+	Info    openAPIInfo                            `json:"info"`
+	Paths   map[string]map[string]openAPIOperation `json:"paths"`
+}
+
+// jsonContent is the content of requests and responses. Their shapes are not described as the
+// handlers decode requests and encode responses themselves.
+var jsonContent = map[string]openAPIMediaType{
+	"application/json": {Schema: map[string]interface{}{}},
+}
+
+// newOpenAPIOperation returns the description of an API endpoint with the given path parameters.
+func newOpenAPIOperation(method string, parameters []string) openAPIOperation {
+	operation := openAPIOperation{
+		Responses: map[string]openAPIResponse{
+			"200": {Description: "JSON encoded response", Content: jsonContent},
+		},
+	}
+	for _, parameter := range parameters {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:     parameter,
+			In:       "path",
+			Required: true,
+			Schema:   map[string]string{"type": "string"},
+		})
+	}
+	if method == "POST" {
+		operation.RequestBody = &openAPIRequestBody{Content: jsonContent}
+	}
+	return operation
+}
+
+// buildAPISchema describes all routes registered in router which are restricted to HTTP methods.
+// This includes the routes of the accounts and devices registered so far.
+func buildAPISchema(router *mux.Router, version string) (*openAPISchema, error) {
+	schema := &openAPISchema{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "BitBoxApp API", Version: version},
+		Paths:   map[string]map[string]openAPIOperation{},
+	}
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		pathTemplate, err := route.GetPathTemplate()
+		if err != nil {
+			// E.g. subrouters matching the host only.
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Path prefixes of subrouters.
+			return nil
+		}
+		path, parameters := openAPIPath(pathTemplate)
+		if _, ok := schema.Paths[path]; !ok {
+			schema.Paths[path] = map[string]openAPIOperation{}
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			schema.Paths[path][strings.ToLower(method)] = newOpenAPIOperation(method, parameters)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
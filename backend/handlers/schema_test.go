@@ -0,0 +1,68 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIPath(t *testing.T) {
+	for pathTemplate, expected := range map[string]struct {
+		path       string
+		parameters []string
+	}{
+		"/api/config":                                {"/api/config", nil},
+		"/api/coins/{code}/headers/status":           {"/api/coins/{code}/headers/status", []string{"code"}},
+		"/api/v{apiVersion:[0-9]+}/":                 {"/api/v{apiVersion}/", []string{"apiVersion"}},
+		"/api/account/v0-abc-btc-0/status":           {"/api/account/{code}/status", []string{"code"}},
+		"/api/devices/registered":                    {"/api/devices/registered", nil},
+		"/api/devices/bitbox02/dev1/info":            {"/api/devices/bitbox02/{deviceID}/info", []string{"deviceID"}},
+		"/api/devices/bitbox02-bootloader/dev1/info": {"/api/devices/bitbox02-bootloader/{deviceID}/info", []string{"deviceID"}},
+		"/api/devices/dev1/info":                     {"/api/devices/{deviceID}/info", []string{"deviceID"}},
+	} {
+		path, parameters := openAPIPath(pathTemplate)
+		require.Equal(t, expected.path, path, pathTemplate)
+		require.Equal(t, expected.parameters, parameters, pathTemplate)
+	}
+}
+
+func TestBuildAPISchema(t *testing.T) {
+	handler := func(http.ResponseWriter, *http.Request) {}
+	router := mux.NewRouter()
+	router.PathPrefix(versionedAPIPathPrefix).HandlerFunc(handler)
+	apiRouter := router.PathPrefix("/api").Subrouter()
+	apiRouter.HandleFunc("/config", handler).Methods("GET")
+	apiRouter.HandleFunc("/config", handler).Methods("POST")
+	for _, code := range []string{"v0-abc-btc-0", "v0-abc-ltc-0"} {
+		accountRouter := apiRouter.PathPrefix("/account/" + code).Subrouter()
+		accountRouter.HandleFunc("/status", handler).Methods("GET")
+	}
+
+	schema, err := buildAPISchema(router, "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", schema.Info.Version)
+	require.Len(t, schema.Paths, 2)
+	require.Contains(t, schema.Paths["/api/config"], "get")
+	require.Contains(t, schema.Paths["/api/config"], "post")
+	require.Nil(t, schema.Paths["/api/config"]["get"].RequestBody)
+	require.NotNil(t, schema.Paths["/api/config"]["post"].RequestBody)
+	status := schema.Paths["/api/account/{code}/status"]["get"]
+	require.Len(t, status.Parameters, 1)
+	require.Equal(t, "code", status.Parameters[0].Name)
+}
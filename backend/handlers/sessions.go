@@ -0,0 +1,182 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/random"
+)
+
+// sessionLifetime is the time after which a session token expires unless it is renewed.
+const sessionLifetime = 15 * time.Minute
+
+// sessions manages the session tokens handed out to clients logging in with the API token.
+type sessions struct {
+	lock     sync.Mutex
+	lifetime time.Duration
+	// tokens maps the valid session tokens to their expiry time.
+	tokens map[string]time.Time
+	// now is time.Now, replaceable in tests.
+	now func() time.Time
+}
+
+func newSessions(lifetime time.Duration) *sessions {
+	return &sessions{
+		lifetime: lifetime,
+		tokens:   map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// create issues a new session token.
+func (s *sessions) create() (string, time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.createLocked()
+}
+
+func (s *sessions) createLocked() (string, time.Time) {
+	// Drop the expired tokens, so they do not accumulate.
+	now := s.now()
+	for token, expiresAt := range s.tokens {
+		if !now.Before(expiresAt) {
+			delete(s.tokens, token)
+		}
+	}
+	token := hex.EncodeToString(random.BytesOrPanic(32))
+	expiresAt := now.Add(s.lifetime)
+	s.tokens[token] = expiresAt
+	return token, expiresAt
+}
+
+// findLocked returns the issued token equal to the given token. The tokens are compared in
+// constant time instead of looking the token up in the map, so the response time does not reveal
+// how much of a guessed token is correct.
+func (s *sessions) findLocked(token string) (string, bool) {
+	found := ""
+	for issued := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(issued), []byte(token)) == 1 {
+			found = issued
+		}
+	}
+	return found, found != ""
+}
+
+// valid returns true if the token was issued, not revoked and is not expired.
+func (s *sessions) valid(token string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	issued, ok := s.findLocked(token)
+	return ok && s.now().Before(s.tokens[issued])
+}
+
+// renew revokes the token and issues a new one. ok is false if the token is not valid.
+func (s *sessions) renew(token string) (newToken string, expiresAt time.Time, ok bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	issued, ok := s.findLocked(token)
+	if !ok || !s.now().Before(s.tokens[issued]) {
+		return "", time.Time{}, false
+	}
+	delete(s.tokens, issued)
+	newToken, expiresAt = s.createLocked()
+	return newToken, expiresAt, true
+}
+
+// revoke invalidates the token.
+func (s *sessions) revoke(token string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if issued, ok := s.findLocked(token); ok {
+		delete(s.tokens, issued)
+	}
+}
+
+// bearerToken returns the token of an `Authorization: Bearer <token>` header value.
+func bearerToken(authorization string) (string, bool) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	return token, ok && token != ""
+}
+
+type sessionResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// postSessionLogin issues a session token to a client authorized with the API token.
+func (handlers *Handlers) postSessionLogin(w http.ResponseWriter, r *http.Request) {
+	apiData := handlers.apiData
+	if apiData.sessions == nil {
+		writeAPIError(w, http.StatusNotFound, errorCodeNotFound, "sessions are not used")
+		return
+	}
+	host := remoteHost(r.RemoteAddr)
+	if lockout := apiData.authLimiter.lockedOut(host); lockout > 0 {
+		handlers.log.Error("Too many unauthorized login requests. WARNING: this could be an attack on the API")
+		writeTooManyRequests(w, lockout)
+		return
+	}
+	if !isBasicToken(r.Header.Get("Authorization"), apiData.token) {
+		handlers.log.Error("Incorrect token in login request. WARNING: this could be an attack on the API")
+		apiData.authLimiter.fail(host)
+		writeAPIError(w, http.StatusUnauthorized, errorCodeUnauthorized, "incorrect token")
+		return
+	}
+	apiData.authLimiter.succeed(host)
+	token, expiresAt := apiData.sessions.create()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writeJSON(w, sessionResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+// postSessionRenew replaces the session token the request is authorized with by a new one.
+func (handlers *Handlers) postSessionRenew(w http.ResponseWriter, r *http.Request) {
+	apiData := handlers.apiData
+	if apiData.sessions == nil {
+		writeAPIError(w, http.StatusNotFound, errorCodeNotFound, "sessions are not used")
+		return
+	}
+	token, ok := bearerToken(r.Header.Get("Authorization"))
+	if !ok {
+		writeAPIError(w, http.StatusUnauthorized, errorCodeUnauthorized, "missing session token")
+		return
+	}
+	newToken, expiresAt, ok := apiData.sessions.renew(token)
+	if !ok {
+		writeAPIError(w, http.StatusUnauthorized, errorCodeUnauthorized, "invalid or expired session token")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writeJSON(w, sessionResponse{Token: newToken, ExpiresAt: expiresAt})
+}
+
+// postSessionLogout revokes the session token the request is authorized with.
+func (handlers *Handlers) postSessionLogout(w http.ResponseWriter, r *http.Request) {
+	apiData := handlers.apiData
+	if apiData.sessions == nil {
+		writeAPIError(w, http.StatusNotFound, errorCodeNotFound, "sessions are not used")
+		return
+	}
+	if token, ok := bearerToken(r.Header.Get("Authorization")); ok {
+		apiData.sessions.revoke(token)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writeJSON(w, map[string]bool{"success": true})
+}
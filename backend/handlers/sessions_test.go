@@ -0,0 +1,65 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newSessions(time.Minute)
+	s.now = func() time.Time { return now }
+
+	token, expiresAt := s.create()
+	require.Equal(t, now.Add(time.Minute), expiresAt)
+	require.True(t, s.valid(token))
+	require.False(t, s.valid("unknown"))
+
+	newToken, _, ok := s.renew(token)
+	require.True(t, ok)
+	require.NotEqual(t, token, newToken)
+	require.False(t, s.valid(token))
+	require.True(t, s.valid(newToken))
+	_, _, ok = s.renew(token)
+	require.False(t, ok)
+
+	s.revoke(newToken)
+	require.False(t, s.valid(newToken))
+
+	token, _ = s.create()
+	now = now.Add(time.Minute)
+	require.False(t, s.valid(token))
+	_, _, ok = s.renew(token)
+	require.False(t, ok)
+}
+
+func TestConnectionDataIsAuthorized(t *testing.T) {
+	// In-process bridge: the token authorizes requests directly.
+	bridge := NewConnectionData(-1, "api-token")
+	require.True(t, bridge.isAuthorized("Basic api-token"))
+	require.False(t, bridge.isAuthorized("Basic wrong"))
+
+	// Served over the network: only session tokens authorize requests.
+	served := NewConnectionData(0, "api-token")
+	require.False(t, served.isAuthorized("Basic api-token"))
+	token, _ := served.sessions.create()
+	require.True(t, served.isAuthorized("Bearer "+token))
+	require.False(t, served.isAuthorized("Bearer "))
+	require.False(t, served.isAuthorized(token))
+}
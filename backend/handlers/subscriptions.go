@@ -0,0 +1,125 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/rates"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// Event topics a websocket client can subscribe to. Events of a specific account or device can be
+// subscribed to with "account/<code>" and "device/<deviceID>".
+const (
+	// topicAll matches all events. Clients are subscribed to it when connecting.
+	topicAll     = "*"
+	topicAccount = "account"
+	topicDevice  = "device"
+	topicRates   = "rates"
+	topicHeaders = "headers"
+	// topicOther matches all events not matching any of the other topics, e.g. backend events.
+	topicOther = "other"
+)
+
+// subscriptionMessage is sent by websocket clients to change the topics they receive events for,
+// e.g. `{"action": "subscribe", "topics": ["account/v0-abc-btc-0", "rates"]}`.
+type subscriptionMessage struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// subscriptions are the event topics a websocket client is subscribed to.
+type subscriptions struct {
+	lock   sync.RWMutex
+	topics map[string]struct{}
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{topics: map[string]struct{}{topicAll: {}}}
+}
+
+// handleMessage processes a subscribe or unsubscribe message of a websocket client.
+func (s *subscriptions) handleMessage(msg []byte) error {
+	var message subscriptionMessage
+	if err := json.Unmarshal(msg, &message); err != nil {
+		return errp.WithStack(err)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch message.Action {
+	case "subscribe":
+		for _, topic := range message.Topics {
+			s.topics[topic] = struct{}{}
+		}
+	case "unsubscribe":
+		for _, topic := range message.Topics {
+			delete(s.topics, topic)
+		}
+	default:
+		return errp.Newf("unknown action: %s", message.Action)
+	}
+	return nil
+}
+
+// eventTopics returns the topics of a JSON serialized event.
+func eventTopics(event []byte) []string {
+	var fields struct {
+		Type     string `json:"type"`
+		Code     string `json:"code"`
+		DeviceID string `json:"deviceID"`
+		Subject  string `json:"subject"`
+	}
+	if err := json.Unmarshal(event, &fields); err != nil {
+		return []string{topicOther}
+	}
+	subjectParts := strings.Split(fields.Subject, "/")
+	switch {
+	case fields.Type == "account":
+		return []string{topicAccount, topicAccount + "/" + fields.Code}
+	case fields.Type == "device":
+		return []string{topicDevice, topicDevice + "/" + fields.DeviceID}
+	case subjectParts[0] == "account" && len(subjectParts) > 2:
+		return []string{topicAccount, topicAccount + "/" + subjectParts[1]}
+	case subjectParts[0] == "devices" && len(subjectParts) > 3:
+		// e.g. devices/bitbox02/<deviceID>/backups/list
+		return []string{topicDevice, topicDevice + "/" + subjectParts[2]}
+	case subjectParts[0] == "devices":
+		return []string{topicDevice}
+	case fields.Subject == rates.RatesEventSubject:
+		return []string{topicRates}
+	case subjectParts[0] == "coins" && len(subjectParts) == 4 && subjectParts[2] == "headers":
+		return []string{topicHeaders}
+	default:
+		return []string{topicOther}
+	}
+}
+
+// matches returns true if the client is subscribed to a topic of the JSON serialized event.
+func (s *subscriptions) matches(event []byte) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if _, ok := s.topics[topicAll]; ok {
+		return true
+	}
+	for _, topic := range eventTopics(event) {
+		if _, ok := s.topics[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
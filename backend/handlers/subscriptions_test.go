@@ -0,0 +1,66 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTopics(t *testing.T) {
+	for event, topics := range map[string][]string{
+		`{"type":"account","code":"v0-abc-btc-0","data":"syncdone"}`:                   {"account", "account/v0-abc-btc-0"},
+		`{"subject":"account/v0-abc-btc-0/synced-addresses-count","action":"replace"}`: {"account", "account/v0-abc-btc-0"},
+		`{"type":"device","deviceID":"dev1","data":"statusChanged"}`:                   {"device", "device/dev1"},
+		`{"subject":"devices/bitbox02/dev1/backups/list","action":"reload"}`:           {"device", "device/dev1"},
+		`{"subject":"devices/registered","action":"reload"}`:                           {"device"},
+		`{"subject":"rates","action":"replace"}`:                                       {"rates"},
+		`{"subject":"coins/btc/headers/status","action":"replace"}`:                    {"headers"},
+		`{"type":"backend","data":"newTxs"}`:                                           {"other"},
+		`{"subject":"accounts","action":"reload"}`:                                     {"other"},
+		`"invalid"`: {"other"},
+	} {
+		require.Equal(t, topics, eventTopics([]byte(event)), event)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	accountEvent := []byte(`{"type":"account","code":"acct1","data":"syncdone"}`)
+	otherAccountEvent := []byte(`{"type":"account","code":"acct2","data":"syncdone"}`)
+	ratesEvent := []byte(`{"subject":"rates","action":"replace"}`)
+	headersEvent := []byte(`{"subject":"coins/btc/headers/status","action":"replace"}`)
+
+	s := newSubscriptions()
+	// Subscribed to everything by default.
+	for _, event := range [][]byte{accountEvent, otherAccountEvent, ratesEvent, headersEvent} {
+		require.True(t, s.matches(event))
+	}
+
+	require.NoError(t, s.handleMessage([]byte(`{"action":"unsubscribe","topics":["*"]}`)))
+	require.NoError(t, s.handleMessage([]byte(`{"action":"subscribe","topics":["account/acct1","rates"]}`)))
+	require.True(t, s.matches(accountEvent))
+	require.False(t, s.matches(otherAccountEvent))
+	require.True(t, s.matches(ratesEvent))
+	require.False(t, s.matches(headersEvent))
+
+	require.NoError(t, s.handleMessage([]byte(`{"action":"subscribe","topics":["account"]}`)))
+	require.True(t, s.matches(otherAccountEvent))
+	require.NoError(t, s.handleMessage([]byte(`{"action":"unsubscribe","topics":["rates"]}`)))
+	require.False(t, s.matches(ratesEvent))
+
+	require.Error(t, s.handleMessage([]byte(`{"action":"unknown"}`)))
+	require.Error(t, s.handleMessage([]byte(`invalid`)))
+}
@@ -15,10 +15,13 @@
 package handlers
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 )
 
 // runWebsocket sets up loops for sending/receiving, abstracting away the low level details about
@@ -26,10 +29,18 @@ import (
 // It returns two channels: one to send messages to the client, and one which notifies
 // when the connection was closed.
 //
+// Messages received from the client after it is authorized are passed to onMessage, which can be
+// nil. The connection is closed if onMessage returns an error.
+//
 // Closing msg makes runWebsocket's goroutines quit.
 // The goroutines close conn upon exit, due to a send/receive error or when msg is closed.
 // runWebsocket never closes msg.
-func runWebsocket(conn *websocket.Conn, apiData *ConnectionData, log *logrus.Entry) (msg chan<- []byte, quit <-chan struct{}) {
+func runWebsocket(
+	conn *websocket.Conn,
+	apiData *ConnectionData,
+	onMessage func([]byte) error,
+	log *logrus.Entry,
+) (msg chan<- []byte, quit <-chan struct{}) {
 	// Time allowed to read the next pong message from the peer.
 	const pongWait = 60 * time.Second
 	// Send pings to peer with this period. Must be less than pongWait.
@@ -37,13 +48,15 @@ func runWebsocket(conn *websocket.Conn, apiData *ConnectionData, log *logrus.Ent
 	// Time allowed to write a message to the peer.
 	const writeWait = 10 * time.Second
 
-	const maxMessageSize = 512
+	// Large enough for subscription messages listing many topics.
+	const maxMessageSize = 4096
 
 	quitChan := make(chan struct{})
 	sendChan := make(chan []byte)
 	authorizedChan := make(chan struct{}, 1)
 
 	readLoop := func() {
+		defer crashreport.Recover()
 		defer func() {
 			close(quitChan)
 			_ = conn.Close()
@@ -54,6 +67,7 @@ func runWebsocket(conn *websocket.Conn, apiData *ConnectionData, log *logrus.Ent
 			_ = conn.SetReadDeadline(time.Now().Add(pongWait))
 			return nil
 		})
+		authorized := false
 		for {
 			_, msg, err := conn.ReadMessage()
 			if err != nil {
@@ -62,12 +76,26 @@ func runWebsocket(conn *websocket.Conn, apiData *ConnectionData, log *logrus.Ent
 				}
 				break
 			}
-			if string(msg) != "Authorization: Basic "+apiData.token {
+			authorization, isAuthorization := strings.CutPrefix(string(msg), "Authorization: ")
+			isAuthorization = isAuthorization && apiData.isAuthorized(authorization)
+			switch {
+			case isAuthorization && !authorized:
+				authorized = true
+				authorizedChan <- struct{}{}
+			case isAuthorization:
+				// Already authorized.
+			case authorized && onMessage != nil:
+				if err := onMessage(msg); err != nil {
+					log.WithError(err).Error("Invalid websocket message. Closing websocket.")
+					_ = conn.Close()
+					return
+				}
+			default:
+				apiData.authLimiter.fail(remoteHost(conn.RemoteAddr().String()))
 				log.Error("Expected authorization token as first message. Closing websocket.")
 				_ = conn.Close()
 				return
 			}
-			authorizedChan <- struct{}{}
 		}
 	}
 
@@ -77,6 +105,7 @@ func runWebsocket(conn *websocket.Conn, apiData *ConnectionData, log *logrus.Ent
 	}
 
 	writeLoop := func() {
+		defer crashreport.Recover()
 		ticker := time.NewTicker(pingPeriod)
 		defer func() {
 			ticker.Stop()
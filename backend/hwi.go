@@ -0,0 +1,74 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/hwi"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// hwiRun returns the runner used to call HWI.
+func (backend *Backend) hwiRun() hwi.Runner {
+	if backend.tstHWIRunner != nil {
+		return backend.tstHWIRunner
+	}
+	return hwi.NewCommandRunner(backend.config.AppConfig().Backend.HWIPath)
+}
+
+// HWIDevices lists the external signers found by HWI.
+func (backend *Backend) HWIDevices() ([]*hwi.Device, error) {
+	return hwi.Enumerate(backend.hwiRun())
+}
+
+// ConnectHWIDevice registers the keystore of the external signer with the given hex encoded root
+// fingerprint, alongside any other registered keystores.
+func (backend *Backend) ConnectHWIDevice(fingerprint string) error {
+	run := backend.hwiRun()
+	devices, err := hwi.Enumerate(run)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if !strings.EqualFold(device.Fingerprint, fingerprint) {
+			continue
+		}
+		keystore, err := hwi.NewKeystore(run, device)
+		if err != nil {
+			return err
+		}
+		if backend.registerKeystore(keystore) == nil {
+			return errp.New("could not register keystore")
+		}
+		return nil
+	}
+	return errp.Newf("device %s not found", fingerprint)
+}
+
+// DisconnectHWIDevice deregisters the keystore of the external signer with the given hex encoded
+// root fingerprint.
+func (backend *Backend) DisconnectHWIDevice(fingerprint string) error {
+	rootFingerprint, err := hex.DecodeString(fingerprint)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	if _, ok := backend.KeystoreByRootFingerprint(rootFingerprint).(*hwi.Keystore); !ok {
+		return errp.Newf("device %s not connected", fingerprint)
+	}
+	backend.deregisterKeystores([][]byte{rootFingerprint})
+	return nil
+}
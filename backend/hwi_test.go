@@ -0,0 +1,58 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore/hwi"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHWIDevice(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	xpub, err := test.TstMustXKey("xprv9s21ZrQH143K3gie3VFLgx8JcmqZNsBcBc6vAdJrsf4bPRhx69U8qZe3EYAyvRWyQdEfz7ZpyYtL8jW2d2Lfkfh6g2zivq8JdZPQqxoxLwB").Neuter()
+	require.NoError(t, err)
+	b.tstHWIRunner = func(args ...string) ([]byte, error) {
+		switch args[len(args)-1] {
+		case "enumerate":
+			return []byte(`[{"type": "trezor", "model": "trezor_t", "path": "webusb:001:4", "fingerprint": "9a6a2580"}]`), nil
+		default:
+			// getxpub. The same xpub is returned for all keypaths, which is enough for this test.
+			return []byte(fmt.Sprintf(`{"xpub": "%s"}`, xpub)), nil
+		}
+	}
+
+	devices, err := b.HWIDevices()
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+
+	require.Error(t, b.ConnectHWIDevice("01020304"))
+	require.NoError(t, b.ConnectHWIDevice("9A6A2580"))
+	ks, ok := b.KeystoreByRootFingerprint([]byte{0x9a, 0x6a, 0x25, 0x80}).(*hwi.Keystore)
+	require.True(t, ok)
+	require.Equal(t, ks, b.Keystore())
+	// Only the BTC account is added, HWI does not support other coins.
+	require.NotNil(t, b.Accounts().lookup("v0-9a6a2580-btc-0"))
+	require.Nil(t, b.Accounts().lookup("v0-9a6a2580-ltc-0"))
+
+	require.NoError(t, b.DisconnectHWIDevice("9a6a2580"))
+	require.Nil(t, b.Keystore())
+	require.Error(t, b.DisconnectHWIDevice("9a6a2580"))
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hwi implements a keystore for external signers such as Trezor, Ledger or Coldcard
+// devices, using the JSON interface of the HWI command line tool
+// (https://github.com/bitcoin-core/HWI).
+package hwi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// errCodeActionCanceled is the HWI error code returned if the user rejects an action on the device.
+const errCodeActionCanceled = -14
+
+// commandTimeout limits how long a single HWI call can take, which includes the user confirming on
+// the device.
+const commandTimeout = 5 * time.Minute
+
+// Runner runs HWI with the given arguments and returns its output.
+type Runner func(args ...string) ([]byte, error)
+
+// NewCommandRunner returns a Runner which executes the HWI binary at the given path. If path is
+// empty, `hwi` is looked up in PATH.
+func NewCommandRunner(path string) Runner {
+	if path == "" {
+		path = "hwi"
+	}
+	return func(args ...string) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, path, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			// HWI reports most errors as JSON on stdout with a non-zero exit code.
+			if stdout.Len() > 0 {
+				return stdout.Bytes(), nil
+			}
+			return nil, errp.Newf("hwi failed: %v: %s", err, stderr.String())
+		}
+		return stdout.Bytes(), nil
+	}
+}
+
+// Error is an error reported by HWI.
+type Error struct {
+	Message string `json:"error"`
+	Code    int    `json:"code"`
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// call runs HWI and decodes its JSON output into result. Errors reported by HWI are returned as
+// *Error.
+func call(run Runner, result interface{}, args ...string) error {
+	output, err := run(args...)
+	if err != nil {
+		return err
+	}
+	var hwiErr Error
+	if err := json.Unmarshal(output, &hwiErr); err == nil && hwiErr.Message != "" {
+		return errp.WithStack(&hwiErr)
+	}
+	if err := json.Unmarshal(output, result); err != nil {
+		return errp.WithStack(err)
+	}
+	return nil
+}
+
+// Device is a device found by HWI.
+type Device struct {
+	// Type is the device type, e.g. "trezor", "ledger" or "coldcard".
+	Type  string `json:"type"`
+	Model string `json:"model"`
+	Path  string `json:"path"`
+	// Fingerprint is the hex encoded root fingerprint. It is empty if the device is locked.
+	Fingerprint         string `json:"fingerprint"`
+	NeedsPinSent        bool   `json:"needs_pin_sent"`
+	NeedsPassphraseSent bool   `json:"needs_passphrase_sent"`
+	// Error is set if HWI could not communicate with the device.
+	Error string `json:"error,omitempty"`
+}
+
+// Enumerate lists the connected devices.
+func Enumerate(run Runner) ([]*Device, error) {
+	devices := []*Device{}
+	if err := call(run, &devices, "enumerate"); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
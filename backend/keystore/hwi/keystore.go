@@ -0,0 +1,339 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hwi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	keystorePkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// addrTypes maps the supported script types to the HWI address types.
+var addrTypes = map[signing.ScriptType]string{
+	signing.ScriptTypeP2WPKHP2SH: "sh_wit",
+	signing.ScriptTypeP2WPKH:     "wit",
+}
+
+// Keystore implements keystore.Keystore for a device accessed through HWI. Only Bitcoin is
+// supported. Transactions are signed by passing a PSBT to the device.
+type Keystore struct {
+	run             Runner
+	device          *Device
+	rootFingerprint []byte
+	log             *logrus.Entry
+}
+
+// NewKeystore creates a keystore for the given device, as returned by Enumerate(). The device must
+// be unlocked, so that its root fingerprint is known.
+func NewKeystore(run Runner, device *Device) (*Keystore, error) {
+	if device.Error != "" {
+		return nil, errp.New(device.Error)
+	}
+	rootFingerprint, err := hex.DecodeString(device.Fingerprint)
+	if err != nil || len(rootFingerprint) != 4 {
+		return nil, errp.Newf("invalid fingerprint of %s, the device might be locked", device.Model)
+	}
+	return &Keystore{
+		run:             run,
+		device:          device,
+		rootFingerprint: rootFingerprint,
+		log: logging.Get().WithGroup("hwi").
+			WithField("model", device.Model).
+			WithField("rootFingerprint", device.Fingerprint),
+	}, nil
+}
+
+// chain returns the HWI chain argument for the network.
+func chain(net *chaincfg.Params) string {
+	switch net.Net {
+	case chaincfg.MainNetParams.Net:
+		return "main"
+	case chaincfg.RegressionNetParams.Net:
+		return "regtest"
+	case chaincfg.SigNetParams.Net:
+		return "signet"
+	default:
+		return "test"
+	}
+}
+
+// call runs a HWI command on this device.
+func (keystore *Keystore) call(net *chaincfg.Params, result interface{}, args ...string) error {
+	args = append([]string{"--fingerprint", keystore.device.Fingerprint, "--chain", chain(net)}, args...)
+	err := call(keystore.run, result, args...)
+	if hwiErr, ok := errp.Cause(err).(*Error); ok && hwiErr.Code == errCodeActionCanceled {
+		return errp.WithStack(keystorePkg.ErrSigningAborted)
+	}
+	return err
+}
+
+// asBitcoin returns the coin if it is a Bitcoin coin, as HWI does not support any other coins.
+func asBitcoin(coinInstance coin.Coin) (*btc.Coin, bool) {
+	btcCoin, ok := coinInstance.(*btc.Coin)
+	if !ok {
+		return nil, false
+	}
+	switch btcCoin.Code() {
+	case coin.CodeBTC, coin.CodeTBTC, coin.CodeTSIG, coin.CodeRBTC:
+		return btcCoin, true
+	default:
+		return nil, false
+	}
+}
+
+// Type implements keystore.Keystore.
+func (keystore *Keystore) Type() keystorePkg.Type {
+	return keystorePkg.TypeHardware
+}
+
+// Name implements keystore.Keystore.
+func (keystore *Keystore) Name() (string, error) {
+	return fmt.Sprintf("%s %s", keystore.device.Model, keystore.device.Fingerprint), nil
+}
+
+// RootFingerprint implements keystore.Keystore.
+func (keystore *Keystore) RootFingerprint() ([]byte, error) {
+	return keystore.rootFingerprint, nil
+}
+
+// SupportsCoin implements keystore.Keystore.
+func (keystore *Keystore) SupportsCoin(coinInstance coin.Coin) bool {
+	_, ok := asBitcoin(coinInstance)
+	return ok
+}
+
+// SupportsAccount implements keystore.Keystore.
+func (keystore *Keystore) SupportsAccount(coinInstance coin.Coin, meta interface{}) bool {
+	if !keystore.SupportsCoin(coinInstance) {
+		return false
+	}
+	scriptType, ok := meta.(signing.ScriptType)
+	if !ok {
+		return false
+	}
+	_, ok = addrTypes[scriptType]
+	return ok
+}
+
+// SupportsUnifiedAccounts implements keystore.Keystore.
+func (keystore *Keystore) SupportsUnifiedAccounts() bool {
+	return true
+}
+
+// SupportsMultipleAccounts implements keystore.Keystore.
+func (keystore *Keystore) SupportsMultipleAccounts() bool {
+	return true
+}
+
+// CanVerifyAddress implements keystore.Keystore.
+func (keystore *Keystore) CanVerifyAddress(coinInstance coin.Coin) (bool, bool, error) {
+	return keystore.SupportsCoin(coinInstance), true, nil
+}
+
+// VerifyAddress implements keystore.Keystore.
+func (keystore *Keystore) VerifyAddress(configuration *signing.Configuration, coinInstance coin.Coin) error {
+	btcCoin, ok := asBitcoin(coinInstance)
+	if !ok {
+		return errp.New("unsupported coin")
+	}
+	addrType, ok := addrTypes[configuration.ScriptType()]
+	if !ok {
+		return errp.Newf("unsupported script type %s", configuration.ScriptType())
+	}
+	var result struct {
+		Address string `json:"address"`
+	}
+	return keystore.call(btcCoin.Net(), &result,
+		"displayaddress",
+		"--path", configuration.AbsoluteKeypath().Encode(),
+		"--addr-type", addrType)
+}
+
+// CanVerifyExtendedPublicKey implements keystore.Keystore.
+func (keystore *Keystore) CanVerifyExtendedPublicKey() bool {
+	return false
+}
+
+// VerifyExtendedPublicKey implements keystore.Keystore.
+func (keystore *Keystore) VerifyExtendedPublicKey(coin.Coin, *signing.Configuration) error {
+	return keystorePkg.ErrUnsupportedFeature
+}
+
+// ExtendedPublicKey implements keystore.Keystore.
+func (keystore *Keystore) ExtendedPublicKey(
+	coinInstance coin.Coin, keypath signing.AbsoluteKeypath) (*hdkeychain.ExtendedKey, error) {
+	btcCoin, ok := asBitcoin(coinInstance)
+	if !ok {
+		return nil, errp.New("unsupported coin")
+	}
+	var result struct {
+		XPub string `json:"xpub"`
+	}
+	if err := keystore.call(btcCoin.Net(), &result, "getxpub", keypath.Encode()); err != nil {
+		return nil, err
+	}
+	xpub, err := hdkeychain.NewKeyFromString(result.XPub)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if xpub.IsPrivate() {
+		return nil, errp.New("expected an xpub")
+	}
+	return xpub, nil
+}
+
+// CanSignMessage implements keystore.Keystore.
+func (keystore *Keystore) CanSignMessage(coin.Code) bool {
+	return false
+}
+
+// SignBTCMessage implements keystore.Keystore.
+func (keystore *Keystore) SignBTCMessage([]byte, signing.AbsoluteKeypath, signing.ScriptType) ([]byte, error) {
+	return nil, keystorePkg.ErrUnsupportedFeature
+}
+
+// SignETHMessage implements keystore.Keystore.
+func (keystore *Keystore) SignETHMessage([]byte, signing.AbsoluteKeypath) ([]byte, error) {
+	return nil, keystorePkg.ErrUnsupportedFeature
+}
+
+// SignETHTypedMessage implements keystore.Keystore.
+func (keystore *Keystore) SignETHTypedMessage(uint64, []byte, signing.AbsoluteKeypath) ([]byte, error) {
+	return nil, keystorePkg.ErrUnsupportedFeature
+}
+
+// SignETHWalletConnectTransaction implements keystore.Keystore.
+func (keystore *Keystore) SignETHWalletConnectTransaction(
+	uint64, *ethTypes.Transaction, signing.AbsoluteKeypath) ([]byte, error) {
+	return nil, keystorePkg.ErrUnsupportedFeature
+}
+
+// SupportsEIP1559 implements keystore.Keystore.
+func (keystore *Keystore) SupportsEIP1559() bool {
+	return false
+}
+
+// SupportsPaymentRequests implements keystore.Keystore.
+func (keystore *Keystore) SupportsPaymentRequests() error {
+	return keystorePkg.ErrUnsupportedFeature
+}
+
+// SignTransaction implements keystore.Keystore. The transaction is passed to the device as a PSBT
+// and the signatures are read from the signed PSBT.
+func (keystore *Keystore) SignTransaction(proposedTransaction interface{}) error {
+	btcProposedTx, ok := proposedTransaction.(*btc.ProposedTransaction)
+	if !ok {
+		return errp.New("only Bitcoin transactions are supported")
+	}
+	txProposal := btcProposedTx.TXProposal
+	btcCoin, ok := asBitcoin(txProposal.Coin)
+	if !ok {
+		return errp.New("unsupported coin")
+	}
+	if txProposal.SilentPaymentAddress != "" {
+		return keystorePkg.ErrUnsupportedFeature
+	}
+	packet, err := btc.NewPSBT(txProposal, btcProposedTx.GetPrevTx)
+	if err != nil {
+		return err
+	}
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	keystore.log.Info("Sign transaction.")
+	var result struct {
+		PSBT string `json:"psbt"`
+	}
+	if err := keystore.call(btcCoin.Net(), &result, "signtx", encoded); err != nil {
+		return err
+	}
+	signedPacket, err := psbt.NewFromRawBytes(strings.NewReader(result.PSBT), true)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	signatures, err := keystore.signatures(btcProposedTx, signedPacket)
+	if err != nil {
+		return err
+	}
+	btcProposedTx.Signatures = signatures
+	return nil
+}
+
+// signatures extracts the signatures of this keystore from the signed PSBT, one per input. Inputs
+// of other parties, e.g. of a payjoin receiver, have no signature.
+func (keystore *Keystore) signatures(
+	btcProposedTx *btc.ProposedTransaction, signedPacket *psbt.Packet) ([]*types.Signature, error) {
+	txProposal := btcProposedTx.TXProposal
+	if len(signedPacket.Inputs) != len(txProposal.Transaction.TxIn) {
+		return nil, errp.New("signed PSBT does not match the transaction")
+	}
+	signatures := make([]*types.Signature, len(txProposal.Transaction.TxIn))
+	for index, txIn := range txProposal.Transaction.TxIn {
+		address := txProposal.PreviousOutputs[txIn.PreviousOutPoint].Address
+		if address == nil {
+			continue
+		}
+		input := signedPacket.Inputs[index]
+		if address.Configuration.ScriptType() == signing.ScriptTypeP2TR {
+			if len(input.TaprootKeySpendSig) < 64 {
+				return nil, errp.Newf("input %d was not signed", index)
+			}
+			signatures[index] = &types.Signature{
+				R: new(big.Int).SetBytes(input.TaprootKeySpendSig[:32]),
+				S: new(big.Int).SetBytes(input.TaprootKeySpendSig[32:64]),
+			}
+			continue
+		}
+		publicKey := address.Configuration.PublicKey().SerializeCompressed()
+		for _, partialSig := range input.PartialSigs {
+			if !bytes.Equal(partialSig.PubKey, publicKey) || len(partialSig.Signature) == 0 {
+				continue
+			}
+			// The last byte is the sighash type.
+			signature, err := ecdsa.ParseDERSignature(
+				partialSig.Signature[:len(partialSig.Signature)-1])
+			if err != nil {
+				return nil, errp.WithStack(err)
+			}
+			r, s := signature.R(), signature.S()
+			rBytes, sBytes := r.Bytes(), s.Bytes()
+			signatures[index] = &types.Signature{
+				R: new(big.Int).SetBytes(rBytes[:]),
+				S: new(big.Int).SetBytes(sBytes[:]),
+			}
+		}
+		if signatures[index] == nil {
+			return nil, errp.Newf("input %d was not signed", index)
+		}
+	}
+	return signatures, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hwi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	keystorePkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/socksproxy"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+const xpub = "xpub6CUGRUonZSQ4TWtTMmzXdrXDtypWKiKrhko4egpiMZbpiaQL2jkwSB1icqYh2cfDfVxdx4df189oLKnC5fSwqPfgyP3hooxujYzAu3fDVmz"
+
+var (
+	btcCoin = btc.NewCoin(coin.CodeBTC, "Bitcoin", "BTC", coin.BtcUnitDefault, &chaincfg.MainNetParams, ".", []*config.ServerInfo{}, "", socksproxy.NewSocksProxy(false, ""))
+	ltcCoin = btc.NewCoin(coin.CodeLTC, "Litecoin", "LTC", coin.BtcUnitDefault, &chaincfg.MainNetParams, ".", []*config.ServerInfo{}, "", socksproxy.NewSocksProxy(false, ""))
+)
+
+// runnerMock returns the given output and records the arguments of the last call.
+func runnerMock(output string, lastArgs *[]string) Runner {
+	return func(args ...string) ([]byte, error) {
+		*lastArgs = args
+		return []byte(output), nil
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	var args []string
+	devices, err := Enumerate(runnerMock(`[
+  {"type": "trezor", "model": "trezor_t", "path": "webusb:001:4", "needs_pin_sent": false, "needs_passphrase_sent": false, "fingerprint": "9a6a2580"},
+  {"type": "coldcard", "model": "coldcard", "path": "0001:0005:00", "needs_pin_sent": false, "needs_passphrase_sent": false, "fingerprint": ""}
+]`, &args))
+	require.NoError(t, err)
+	require.Equal(t, []string{"enumerate"}, args)
+	require.Len(t, devices, 2)
+	require.Equal(t, "trezor_t", devices[0].Model)
+	require.Equal(t, "9a6a2580", devices[0].Fingerprint)
+
+	_, err = NewKeystore(nil, devices[1])
+	require.Error(t, err)
+
+	_, err = Enumerate(runnerMock(`{"error": "Not supported", "code": -8}`, &args))
+	require.Error(t, err)
+	hwiErr, ok := errp.Cause(err).(*Error)
+	require.True(t, ok)
+	require.Equal(t, -8, hwiErr.Code)
+}
+
+func TestKeystore(t *testing.T) {
+	var args []string
+	device := &Device{Type: "trezor", Model: "trezor_t", Fingerprint: "9a6a2580"}
+	keystore, err := NewKeystore(runnerMock(fmt.Sprintf(`{"xpub": "%s"}`, xpub), &args), device)
+	require.NoError(t, err)
+
+	rootFingerprint, err := keystore.RootFingerprint()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x9a, 0x6a, 0x25, 0x80}, rootFingerprint)
+
+	require.True(t, keystore.SupportsCoin(btcCoin))
+	require.False(t, keystore.SupportsCoin(ltcCoin))
+	require.True(t, keystore.SupportsAccount(btcCoin, signing.ScriptTypeP2WPKH))
+	require.False(t, keystore.SupportsAccount(btcCoin, signing.ScriptTypeP2WSH))
+
+	keypath, err := signing.NewAbsoluteKeypath("m/84'/0'/0'")
+	require.NoError(t, err)
+	extendedPublicKey, err := keystore.ExtendedPublicKey(btcCoin, keypath)
+	require.NoError(t, err)
+	require.Equal(t, xpub, extendedPublicKey.String())
+	require.Equal(t,
+		[]string{"--fingerprint", "9a6a2580", "--chain", "main", "getxpub", "m/84'/0'/0'"},
+		args)
+
+	// Rejecting on the device aborts.
+	keystore.run = runnerMock(`{"error": "Action canceled by user", "code": -14}`, &args)
+	_, err = keystore.ExtendedPublicKey(btcCoin, keypath)
+	require.Equal(t, keystorePkg.ErrSigningAborted, errp.Cause(err))
+}
@@ -0,0 +1,308 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	keystorePkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+)
+
+// ConfirmMode defines how the simulator answers confirmation prompts.
+type ConfirmMode string
+
+const (
+	// ConfirmModeAccept accepts all prompts.
+	ConfirmModeAccept ConfirmMode = "accept"
+	// ConfirmModeReject rejects all prompts, as if the user aborted on the device.
+	ConfirmModeReject ConfirmMode = "reject"
+	// ConfirmModeManual keeps prompts pending until they are answered with Respond().
+	ConfirmModeManual ConfirmMode = "manual"
+)
+
+const (
+	// simulatorPINAttempts is the number of wrong PIN attempts after which the simulator is reset,
+	// like the BitBox02.
+	simulatorPINAttempts = 10
+	// simulatorPromptTimeout is the time after which a pending prompt is rejected if it is not
+	// answered, so a forgotten prompt does not block the caller forever.
+	simulatorPromptTimeout = 5 * time.Minute
+)
+
+var (
+	// ErrWrongPIN is returned by Unlock() if the PIN is wrong.
+	ErrWrongPIN = errors.New("wrong PIN")
+	// ErrSimulatorReset is returned by Unlock() after too many wrong PIN attempts.
+	ErrSimulatorReset = errors.New("simulator reset after too many wrong PIN attempts")
+)
+
+// Prompt is a confirmation the simulated device asks the user for.
+type Prompt struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// SimulatorStatus is the state of the simulated device.
+type SimulatorStatus struct {
+	Unlocked          bool        `json:"unlocked"`
+	RemainingAttempts int         `json:"remainingAttempts"`
+	ConfirmMode       ConfirmMode `json:"confirmMode"`
+	LatencyMS         int64       `json:"latencyMs"`
+	// Prompt is the pending confirmation in ConfirmModeManual, nil if there is none.
+	Prompt *Prompt `json:"prompt"`
+}
+
+// Simulator is a software keystore that behaves like a hardware wallet for end-to-end tests: it
+// has to be unlocked with a PIN, asks for confirmations which are answered according to the
+// configured ConfirmMode, and can add latency to each device operation.
+type Simulator struct {
+	*Keystore
+
+	pin      string
+	onChange func()
+
+	lock              locker.Locker
+	unlocked          bool
+	remainingAttempts int
+	confirmMode       ConfirmMode
+	latency           time.Duration
+	prompt            *Prompt
+	response          chan bool
+	// closed is closed by Close(), rejecting the pending prompt.
+	closed chan struct{}
+	// promptTimeout is simulatorPromptTimeout, replaceable in tests.
+	promptTimeout time.Duration
+}
+
+// NewSimulator creates a locked simulator with the keys of NewKeystoreFromPIN(pin). onChange is
+// called whenever the status changes.
+func NewSimulator(pin string, onChange func()) *Simulator {
+	return &Simulator{
+		Keystore:          NewKeystoreFromPIN(pin),
+		pin:               pin,
+		onChange:          onChange,
+		remainingAttempts: simulatorPINAttempts,
+		confirmMode:       ConfirmModeAccept,
+		closed:            make(chan struct{}),
+		promptTimeout:     simulatorPromptTimeout,
+	}
+}
+
+// Close rejects the pending prompt, if any, and makes all further prompts fail. It is called when
+// the simulator is replaced.
+func (simulator *Simulator) Close() {
+	defer simulator.lock.Lock()()
+	select {
+	case <-simulator.closed:
+	default:
+		close(simulator.closed)
+	}
+}
+
+// Unlock unlocks the simulator if the PIN is correct.
+func (simulator *Simulator) Unlock(pin string) error {
+	defer simulator.onChange()
+	defer simulator.lock.Lock()()
+	if simulator.remainingAttempts == 0 {
+		return errp.WithStack(ErrSimulatorReset)
+	}
+	if pin != simulator.pin {
+		simulator.remainingAttempts--
+		if simulator.remainingAttempts == 0 {
+			return errp.WithStack(ErrSimulatorReset)
+		}
+		return errp.WithStack(ErrWrongPIN)
+	}
+	simulator.remainingAttempts = simulatorPINAttempts
+	simulator.unlocked = true
+	return nil
+}
+
+// SetConfirmMode sets how prompts are answered. Changing the mode does not affect a pending
+// prompt.
+func (simulator *Simulator) SetConfirmMode(mode ConfirmMode) error {
+	switch mode {
+	case ConfirmModeAccept, ConfirmModeReject, ConfirmModeManual:
+	default:
+		return errp.Newf("unknown confirm mode %q", mode)
+	}
+	defer simulator.onChange()
+	defer simulator.lock.Lock()()
+	simulator.confirmMode = mode
+	return nil
+}
+
+// SetLatency sets the time each device operation takes.
+func (simulator *Simulator) SetLatency(latency time.Duration) {
+	defer simulator.onChange()
+	defer simulator.lock.Lock()()
+	simulator.latency = latency
+}
+
+// Respond answers the pending prompt.
+func (simulator *Simulator) Respond(accept bool) error {
+	defer simulator.lock.Lock()()
+	if simulator.prompt == nil {
+		return errp.New("no pending prompt")
+	}
+	simulator.prompt = nil
+	simulator.response <- accept
+	go simulator.onChange()
+	return nil
+}
+
+// Status returns the current state of the simulator.
+func (simulator *Simulator) Status() SimulatorStatus {
+	defer simulator.lock.RLock()()
+	return SimulatorStatus{
+		Unlocked:          simulator.unlocked,
+		RemainingAttempts: simulator.remainingAttempts,
+		ConfirmMode:       simulator.confirmMode,
+		LatencyMS:         simulator.latency.Milliseconds(),
+		Prompt:            simulator.prompt,
+	}
+}
+
+// wait simulates the time the device needs for an operation.
+func (simulator *Simulator) wait() {
+	unlock := simulator.lock.RLock()
+	latency := simulator.latency
+	unlock()
+	time.Sleep(latency)
+}
+
+// confirm asks for a confirmation and returns keystore.ErrSigningAborted if it is rejected.
+func (simulator *Simulator) confirm(title string, body string) error {
+	simulator.wait()
+	unlock := simulator.lock.Lock()
+	select {
+	case <-simulator.closed:
+		unlock()
+		return errp.New("simulator was closed")
+	default:
+	}
+	if !simulator.unlocked {
+		unlock()
+		return errp.New("simulator is locked")
+	}
+	var accepted bool
+	switch simulator.confirmMode {
+	case ConfirmModeAccept:
+		unlock()
+		accepted = true
+	case ConfirmModeReject:
+		unlock()
+		accepted = false
+	case ConfirmModeManual:
+		if simulator.prompt != nil {
+			unlock()
+			return errp.New("another prompt is pending")
+		}
+		response := make(chan bool, 1)
+		prompt := &Prompt{Title: title, Body: body}
+		simulator.prompt = prompt
+		simulator.response = response
+		timeout := simulator.promptTimeout
+		unlock()
+		simulator.onChange()
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case accepted = <-response:
+		case <-timer.C:
+			simulator.clearPrompt(prompt)
+			return errp.WithStack(keystorePkg.ErrSigningAborted)
+		case <-simulator.closed:
+			simulator.clearPrompt(prompt)
+			return errp.New("simulator was closed")
+		}
+	}
+	if !accepted {
+		return errp.WithStack(keystorePkg.ErrSigningAborted)
+	}
+	return nil
+}
+
+// clearPrompt removes the prompt if it is still pending after it was given up.
+func (simulator *Simulator) clearPrompt(prompt *Prompt) {
+	unlock := simulator.lock.Lock()
+	cleared := simulator.prompt == prompt
+	if cleared {
+		simulator.prompt = nil
+	}
+	unlock()
+	if cleared {
+		simulator.onChange()
+	}
+}
+
+// Name implements keystore.Keystore.
+func (simulator *Simulator) Name() (string, error) {
+	fingerprint, err := simulator.RootFingerprint()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Simulated BitBox %x", fingerprint), nil
+}
+
+// CanVerifyAddress implements keystore.Keystore.
+func (simulator *Simulator) CanVerifyAddress(coin.Coin) (bool, bool, error) {
+	return true, false, nil
+}
+
+// VerifyAddress implements keystore.Keystore.
+func (simulator *Simulator) VerifyAddress(configuration *signing.Configuration, coin coin.Coin) error {
+	return simulator.confirm("Verify address", configuration.AbsoluteKeypath().Encode())
+}
+
+// CanVerifyExtendedPublicKey implements keystore.Keystore.
+func (simulator *Simulator) CanVerifyExtendedPublicKey() bool {
+	return true
+}
+
+// VerifyExtendedPublicKey implements keystore.Keystore.
+func (simulator *Simulator) VerifyExtendedPublicKey(coin coin.Coin, configuration *signing.Configuration) error {
+	return simulator.confirm("Verify extended public key", configuration.AbsoluteKeypath().Encode())
+}
+
+// ExtendedPublicKey implements keystore.Keystore.
+func (simulator *Simulator) ExtendedPublicKey(
+	coin coin.Coin, absoluteKeypath signing.AbsoluteKeypath,
+) (*hdkeychain.ExtendedKey, error) {
+	simulator.wait()
+	return simulator.Keystore.ExtendedPublicKey(coin, absoluteKeypath)
+}
+
+// SignTransaction implements keystore.Keystore.
+func (simulator *Simulator) SignTransaction(proposedTransaction interface{}) error {
+	btcProposedTx, ok := proposedTransaction.(*btc.ProposedTransaction)
+	if !ok {
+		return errp.WithStack(keystorePkg.ErrUnsupportedFeature)
+	}
+	txProposal := btcProposedTx.TXProposal
+	body := fmt.Sprintf("Send %d sat with a fee of %d sat", int64(txProposal.Amount), int64(txProposal.Fee))
+	if err := simulator.confirm("Sign transaction", body); err != nil {
+		return err
+	}
+	return simulator.Keystore.SignTransaction(proposedTransaction)
+}
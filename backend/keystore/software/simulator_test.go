@@ -0,0 +1,108 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"testing"
+	"time"
+
+	keystorePkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatorUnlock(t *testing.T) {
+	simulator := NewSimulator("1234", func() {})
+	require.False(t, simulator.Status().Unlocked)
+	require.Equal(t, ErrWrongPIN, errp.Cause(simulator.Unlock("0000")))
+	require.Equal(t, simulatorPINAttempts-1, simulator.Status().RemainingAttempts)
+	require.NoError(t, simulator.Unlock("1234"))
+	require.True(t, simulator.Status().Unlocked)
+	require.Equal(t, simulatorPINAttempts, simulator.Status().RemainingAttempts)
+
+	// Same keys as the plain test keystore.
+	fingerprint, err := simulator.RootFingerprint()
+	require.NoError(t, err)
+	expected, err := NewKeystoreFromPIN("1234").RootFingerprint()
+	require.NoError(t, err)
+	require.Equal(t, expected, fingerprint)
+
+	simulator = NewSimulator("1234", func() {})
+	for i := 0; i < simulatorPINAttempts-1; i++ {
+		require.Equal(t, ErrWrongPIN, errp.Cause(simulator.Unlock("0000")))
+	}
+	require.Equal(t, ErrSimulatorReset, errp.Cause(simulator.Unlock("0000")))
+	require.Equal(t, ErrSimulatorReset, errp.Cause(simulator.Unlock("1234")))
+}
+
+func TestSimulatorConfirm(t *testing.T) {
+	changes := make(chan struct{}, 100)
+	simulator := NewSimulator("1234", func() { changes <- struct{}{} })
+	require.Error(t, simulator.confirm("title", "body"))
+	require.NoError(t, simulator.Unlock("1234"))
+
+	require.NoError(t, simulator.confirm("title", "body"))
+	require.NoError(t, simulator.SetConfirmMode(ConfirmModeReject))
+	require.Equal(t, keystorePkg.ErrSigningAborted, errp.Cause(simulator.confirm("title", "body")))
+	require.Error(t, simulator.SetConfirmMode("unknown"))
+
+	require.NoError(t, simulator.SetConfirmMode(ConfirmModeManual))
+	require.Error(t, simulator.Respond(true))
+	for _, accept := range []bool{true, false} {
+		result := make(chan error)
+		go func() { result <- simulator.confirm("title", "body") }()
+		require.Eventually(t, func() bool { return simulator.Status().Prompt != nil }, time.Second, time.Millisecond)
+		require.Equal(t, &Prompt{Title: "title", Body: "body"}, simulator.Status().Prompt)
+		require.NoError(t, simulator.Respond(accept))
+		err := <-result
+		if accept {
+			require.NoError(t, err)
+		} else {
+			require.Equal(t, keystorePkg.ErrSigningAborted, errp.Cause(err))
+		}
+		require.Nil(t, simulator.Status().Prompt)
+	}
+
+	// Unanswered prompts are rejected after a while.
+	simulator.promptTimeout = 10 * time.Millisecond
+	require.Equal(t, keystorePkg.ErrSigningAborted, errp.Cause(simulator.confirm("title", "body")))
+	require.Nil(t, simulator.Status().Prompt)
+	simulator.promptTimeout = simulatorPromptTimeout
+
+	require.NoError(t, simulator.SetConfirmMode(ConfirmModeAccept))
+	simulator.SetLatency(50 * time.Millisecond)
+	require.Equal(t, int64(50), simulator.Status().LatencyMS)
+	start := time.Now()
+	require.NoError(t, simulator.confirm("title", "body"))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestSimulatorClose(t *testing.T) {
+	simulator := NewSimulator("1234", func() {})
+	require.NoError(t, simulator.Unlock("1234"))
+	require.NoError(t, simulator.SetConfirmMode(ConfirmModeManual))
+
+	result := make(chan error)
+	go func() { result <- simulator.confirm("title", "body") }()
+	require.Eventually(t, func() bool { return simulator.Status().Prompt != nil }, time.Second, time.Millisecond)
+	simulator.Close()
+	require.Error(t, <-result)
+	require.Nil(t, simulator.Status().Prompt)
+
+	// Closing twice is fine, and no new prompts are shown.
+	simulator.Close()
+	require.Error(t, simulator.confirm("title", "body"))
+	require.Nil(t, simulator.Status().Prompt)
+}
@@ -127,7 +127,8 @@ func (keystore *Keystore) SupportsAccount(coin coin.Coin, meta interface{}) bool
 		return scriptType == signing.ScriptTypeP2PKH ||
 			scriptType == signing.ScriptTypeP2WPKHP2SH ||
 			scriptType == signing.ScriptTypeP2WPKH ||
-			scriptType == signing.ScriptTypeP2TR
+			scriptType == signing.ScriptTypeP2TR ||
+			scriptType == signing.ScriptTypeP2WSH
 
 	default:
 		return false
@@ -198,6 +199,10 @@ func (keystore *Keystore) SignTransaction(
 			keystore.log.Error("There needs to be exactly one output being spent per input.")
 			return errp.New("There needs to be exactly one output being spent per input.")
 		}
+		if spentOutput.Address == nil {
+			// Input of another party, e.g. of a payjoin receiver.
+			continue
+		}
 		address := btcProposedTx.GetAccountAddress(spentOutput.Address.PubkeyScriptHashHex())
 
 		xprv, err := address.Configuration.AbsoluteKeypath().Derive(keystore.master)
@@ -0,0 +1,108 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bytes"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/keystore"
+)
+
+// registeredKeystore is a keystore registered at the backend. The root fingerprint identifies it
+// and is stored so it does not have to be queried from the device again.
+type registeredKeystore struct {
+	rootFingerprint []byte
+	keystore        keystore.Keystore
+}
+
+// keystoreRegistry holds the registered keystores in the order in which they were registered.
+type keystoreRegistry []registeredKeystore
+
+// lookup returns the keystore with the given root fingerprint, or nil if it is not registered.
+func (registry keystoreRegistry) lookup(rootFingerprint []byte) keystore.Keystore {
+	for _, registered := range registry {
+		if bytes.Equal(registered.rootFingerprint, rootFingerprint) {
+			return registered.keystore
+		}
+	}
+	return nil
+}
+
+// owner returns the registered keystore the account belongs to, or nil if it is not registered.
+func (registry keystoreRegistry) owner(account *config.Account) keystore.Keystore {
+	for _, registered := range registry {
+		if account.SigningConfigurations.ContainsRootFingerprint(registered.rootFingerprint) {
+			return registered.keystore
+		}
+	}
+	return nil
+}
+
+// main returns the keystore that was registered first, or nil if there is none. It is used
+// where only one keystore can be handled, e.g. for AOPP requests.
+func (registry keystoreRegistry) main() keystore.Keystore {
+	if len(registry) == 0 {
+		return nil
+	}
+	return registry[0].keystore
+}
+
+// add registers the keystore. A previously registered keystore with the same root fingerprint is
+// replaced.
+func (registry keystoreRegistry) add(rootFingerprint []byte, ks keystore.Keystore) keystoreRegistry {
+	for i, registered := range registry {
+		if bytes.Equal(registered.rootFingerprint, rootFingerprint) {
+			registry[i].keystore = ks
+			return registry
+		}
+	}
+	return append(registry, registeredKeystore{rootFingerprint: rootFingerprint, keystore: ks})
+}
+
+// remove deregisters the keystore with the given root fingerprint.
+func (registry keystoreRegistry) remove(rootFingerprint []byte) keystoreRegistry {
+	result := keystoreRegistry{}
+	for _, registered := range registry {
+		if !bytes.Equal(registered.rootFingerprint, rootFingerprint) {
+			result = append(result, registered)
+		}
+	}
+	return result
+}
+
+// Keystore returns the keystore that was registered first, or nil if no keystore is registered.
+// Use Keystores() or KeystoreByRootFingerprint() if more than one keystore can be handled.
+func (backend *Backend) Keystore() keystore.Keystore {
+	defer backend.accountsAndKeystoreLock.RLock()()
+	return backend.keystores.main()
+}
+
+// Keystores returns all registered keystores in the order in which they were registered.
+func (backend *Backend) Keystores() []keystore.Keystore {
+	defer backend.accountsAndKeystoreLock.RLock()()
+	result := make([]keystore.Keystore, len(backend.keystores))
+	for i, registered := range backend.keystores {
+		result[i] = registered.keystore
+	}
+	return result
+}
+
+// KeystoreByRootFingerprint returns the registered keystore with the given root fingerprint, or nil
+// if it is not registered.
+func (backend *Backend) KeystoreByRootFingerprint(rootFingerprint []byte) keystore.Keystore {
+	defer backend.accountsAndKeystoreLock.RLock()()
+	return backend.keystores.lookup(rootFingerprint)
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"regexp"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	utilConfig "github.com/BitBoxSwiss/bitbox-wallet-app/util/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// languageTagRegexp matches language tags such as `de`, `pt-BR` or `zh_Hans`.
+var languageTagRegexp = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})*$`)
+
+// UserLanguage returns the UI language chosen by the user. If the user did not choose one, the main
+// language of the native locale is returned.
+func (backend *Backend) UserLanguage() string {
+	lang := backend.config.AppConfig().Backend.UserLanguage
+	if len(lang) == 0 {
+		// userLanguage config is empty if the set locale matches the system locale, so we have
+		// to retrieve that.
+		lang = utilConfig.MainLocaleFromNative(backend.environment.NativeLocale())
+	}
+	return lang
+}
+
+// SetUserLanguage persists the UI language chosen by the user. An empty language resets it to the
+// native locale. A `languageChanged` event with the resulting UserLanguage() is sent so all
+// frontends can switch to it.
+func (backend *Backend) SetUserLanguage(lang string) error {
+	if lang != "" && !languageTagRegexp.MatchString(lang) {
+		return errp.Newf("invalid language: %s", lang)
+	}
+	err := backend.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		appConfig.Backend.UserLanguage = lang
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	backend.events <- backendEvent{Type: "backend", Data: "languageChanged", Meta: map[string]interface{}{
+		"language": backend.UserLanguage(),
+	}}
+	return nil
+}
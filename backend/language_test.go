@@ -0,0 +1,56 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUserLanguage(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	languageChanged := func() interface{} {
+		var language interface{}
+		for len(b.events) > 0 {
+			if event, ok := (<-b.events).(backendEvent); ok && event.Data == "languageChanged" {
+				language = event.Meta.(map[string]interface{})["language"]
+			}
+		}
+		return language
+	}
+
+	require.Equal(t, "", b.UserLanguage())
+
+	require.NoError(t, b.SetUserLanguage("de"))
+	require.Equal(t, "de", b.UserLanguage())
+	require.Equal(t, "de", b.config.AppConfig().Backend.UserLanguage)
+	require.Equal(t, "de", languageChanged())
+
+	require.NoError(t, b.SetUserLanguage("pt_BR"))
+	require.Equal(t, "pt_BR", b.UserLanguage())
+	require.Equal(t, "pt_BR", languageChanged())
+
+	require.Error(t, b.SetUserLanguage("../de"))
+	require.Equal(t, "pt_BR", b.UserLanguage())
+	require.Nil(t, languageChanged())
+
+	// Reset to the native locale, which is empty in tests.
+	require.NoError(t, b.SetUserLanguage(""))
+	require.Equal(t, "", b.UserLanguage())
+	require.Equal(t, "", languageChanged())
+}
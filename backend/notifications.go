@@ -0,0 +1,96 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/bitbox02"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device"
+	deviceevent "github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/device/event"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/notifications"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable/action"
+	"github.com/BitBoxSwiss/bitbox02-api-go/api/firmware"
+)
+
+func (backend *Backend) notifyNotificationsChanged() {
+	backend.Notify(observable.Event{
+		Subject: "notifications",
+		Action:  action.Reload,
+	})
+}
+
+// addNotification stores a notification for the notification center. Errors are only logged, as
+// the notification is not essential.
+func (backend *Backend) addNotification(typ notifications.Type, params map[string]string) {
+	added, err := backend.notifications.Add(typ, params)
+	if err != nil {
+		backend.log.WithError(err).Error("Could not store notification")
+		return
+	}
+	if added {
+		backend.notifyNotificationsChanged()
+	}
+}
+
+// notifyDeviceWarning adds a notification if the device event reveals a problem with the device.
+func (backend *Backend) notifyDeviceWarning(theDevice device.Interface, event deviceevent.Event) {
+	bb02, ok := theDevice.(*bitbox02.Device)
+	if !ok {
+		return
+	}
+	params := map[string]string{"productName": theDevice.ProductName()}
+	switch event {
+	case deviceevent.Event(firmware.EventAttestationCheckDone):
+		if bb02.AttestationStatus() == bitbox02.AttestationFailed {
+			backend.addNotification(notifications.TypeAttestationFailed, params)
+		}
+	case deviceevent.Event(firmware.EventStatusChanged):
+		if bb02.Status() == firmware.StatusRequireFirmwareUpgrade {
+			backend.addNotification(notifications.TypeFirmwareUpgradeRequired, params)
+		}
+	}
+}
+
+// Notifications returns the stored notifications, newest first.
+func (backend *Backend) Notifications() []notifications.Notification {
+	return backend.notifications.List()
+}
+
+// MarkNotificationRead marks the notification with the given ID as read.
+func (backend *Backend) MarkNotificationRead(id string) error {
+	if err := backend.notifications.MarkRead(id); err != nil {
+		return err
+	}
+	backend.notifyNotificationsChanged()
+	return nil
+}
+
+// MarkAllNotificationsRead marks all notifications as read.
+func (backend *Backend) MarkAllNotificationsRead() error {
+	if err := backend.notifications.MarkAllRead(); err != nil {
+		return err
+	}
+	backend.notifyNotificationsChanged()
+	return nil
+}
+
+// DeleteNotification removes the notification with the given ID.
+func (backend *Backend) DeleteNotification(id string) error {
+	if err := backend.notifications.Delete(id); err != nil {
+		return err
+	}
+	backend.notifyNotificationsChanged()
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notifications keeps noteworthy events, such as incoming transactions, device warnings or
+// available updates, so the user can see what happened while they were away.
+package notifications
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// maxNotifications limits the number of stored notifications. The oldest notifications are
+// dropped first.
+const maxNotifications = 100
+
+// Type is the kind of event a notification is about. The frontend shows a translated message
+// for each type, filled in with the params of the notification.
+type Type string
+
+const (
+	// TypeIncomingTx is a transaction received in an account. Params: accountCode, accountName,
+	// coinCode, txID, amount and unit.
+	TypeIncomingTx Type = "incomingTx"
+	// TypeTxDropped is an unconfirmed transaction which was dropped from the mempool or
+	// double-spent. Params: accountCode, accountName, txID and status.
+	TypeTxDropped Type = "txDropped"
+	// TypeAttestationFailed is a device which failed the attestation check and might not be
+	// genuine. Params: productName.
+	TypeAttestationFailed Type = "attestationFailed"
+	// TypeFirmwareUpgradeRequired is a device which has to be upgraded before it can be used.
+	// Params: productName.
+	TypeFirmwareUpgradeRequired Type = "firmwareUpgradeRequired"
+	// TypeUpdateAvailable is a new version of the app. Params: version.
+	TypeUpdateAvailable Type = "updateAvailable"
+	// TypePinnedCertExpiring is a cert pinned for a custom Electrum server which is about to expire.
+	// Params: server, fingerprint and notAfter.
+	TypePinnedCertExpiring Type = "pinnedCertExpiring"
+)
+
+// Notification is a stored event.
+type Notification struct {
+	// ID uniquely identifies the notification. It is assigned when the notification is added.
+	ID     string            `json:"id"`
+	Type   Type              `json:"type"`
+	Time   time.Time         `json:"time"`
+	Params map[string]string `json:"params,omitempty"`
+	Read   bool              `json:"read"`
+}
+
+// Notifications is a high level helper to read and modify the stored notifications. All
+// notifications are kept in RAM and written to the file on every change.
+type Notifications struct {
+	filename      string
+	notifications []*Notification
+	mu            sync.RWMutex
+}
+
+// Load makes a new Notifications instance, loading the notifications from the file. If the file
+// does not exist, no error is returned.
+func Load(filename string) (*Notifications, error) {
+	notifications := []*Notification{}
+	contents, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errp.WithStack(err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(contents, &notifications); err != nil {
+			return nil, errp.WithStack(err)
+		}
+	}
+	return &Notifications{filename: filename, notifications: notifications}, nil
+}
+
+// write must be called with mu held.
+func (notifications *Notifications) write() error {
+	contents, err := json.Marshal(notifications.notifications)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	return errp.WithStack(os.WriteFile(notifications.filename, contents, 0600))
+}
+
+// Add stores a new unread notification. If a notification of the same type with the same params
+// is already stored, e.g. for an update which was already announced in an earlier session, nothing
+// is added and false is returned.
+func (notifications *Notifications) Add(typ Type, params map[string]string) (bool, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return false, errp.WithStack(err)
+	}
+	notifications.mu.Lock()
+	defer notifications.mu.Unlock()
+	for _, notification := range notifications.notifications {
+		if notification.Type == typ && maps.Equal(notification.Params, params) {
+			return false, nil
+		}
+	}
+	notifications.notifications = append(notifications.notifications, &Notification{
+		ID:     hex.EncodeToString(id),
+		Type:   typ,
+		Time:   time.Now().UTC(),
+		Params: params,
+	})
+	if len(notifications.notifications) > maxNotifications {
+		notifications.notifications = notifications.notifications[len(notifications.notifications)-maxNotifications:]
+	}
+	return true, notifications.write()
+}
+
+// List returns all stored notifications, newest first.
+func (notifications *Notifications) List() []Notification {
+	notifications.mu.RLock()
+	defer notifications.mu.RUnlock()
+	result := make([]Notification, len(notifications.notifications))
+	for i, notification := range notifications.notifications {
+		result[len(result)-1-i] = *notification
+	}
+	return result
+}
+
+// MarkRead marks the notification with the given ID as read.
+func (notifications *Notifications) MarkRead(id string) error {
+	notifications.mu.Lock()
+	defer notifications.mu.Unlock()
+	for _, notification := range notifications.notifications {
+		if notification.ID == id {
+			notification.Read = true
+			return notifications.write()
+		}
+	}
+	return errp.Newf("notification %s not found", id)
+}
+
+// MarkAllRead marks all notifications as read.
+func (notifications *Notifications) MarkAllRead() error {
+	notifications.mu.Lock()
+	defer notifications.mu.Unlock()
+	for _, notification := range notifications.notifications {
+		notification.Read = true
+	}
+	return notifications.write()
+}
+
+// Delete removes the notification with the given ID.
+func (notifications *Notifications) Delete(id string) error {
+	notifications.mu.Lock()
+	defer notifications.mu.Unlock()
+	for i, notification := range notifications.notifications {
+		if notification.ID == id {
+			notifications.notifications = append(
+				notifications.notifications[:i], notifications.notifications[i+1:]...)
+			return notifications.write()
+		}
+	}
+	return errp.Newf("notification %s not found", id)
+}
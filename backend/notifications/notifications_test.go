@@ -0,0 +1,72 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "notifications.json")
+	notifications, err := Load(filename)
+	require.NoError(t, err)
+	require.Empty(t, notifications.List())
+
+	added, err := notifications.Add(TypeUpdateAvailable, map[string]string{"version": "4.48.0"})
+	require.NoError(t, err)
+	require.True(t, added)
+	// Already announced.
+	added, err = notifications.Add(TypeUpdateAvailable, map[string]string{"version": "4.48.0"})
+	require.NoError(t, err)
+	require.False(t, added)
+	added, err = notifications.Add(TypeAttestationFailed, map[string]string{"productName": "bitbox02"})
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// Newest first, and persisted.
+	notifications, err = Load(filename)
+	require.NoError(t, err)
+	list := notifications.List()
+	require.Len(t, list, 2)
+	require.Equal(t, TypeAttestationFailed, list[0].Type)
+	require.Equal(t, TypeUpdateAvailable, list[1].Type)
+	require.Equal(t, "4.48.0", list[1].Params["version"])
+	require.False(t, list[1].Read)
+
+	require.NoError(t, notifications.MarkRead(list[1].ID))
+	require.True(t, notifications.List()[1].Read)
+	require.False(t, notifications.List()[0].Read)
+	require.Error(t, notifications.MarkRead("unknown"))
+
+	require.NoError(t, notifications.MarkAllRead())
+	require.True(t, notifications.List()[0].Read)
+
+	require.NoError(t, notifications.Delete(list[0].ID))
+	require.Len(t, notifications.List(), 1)
+	require.Error(t, notifications.Delete(list[0].ID))
+
+	for i := 0; i < maxNotifications; i++ {
+		_, err := notifications.Add(TypeIncomingTx, map[string]string{"txID": fmt.Sprintf("tx%d", i)})
+		require.NoError(t, err)
+	}
+	list = notifications.List()
+	require.Len(t, list, maxNotifications)
+	require.Equal(t, "tx99", list[0].Params["txID"])
+	require.Equal(t, "tx0", list[maxNotifications-1].Params["txID"])
+}
@@ -145,6 +145,25 @@ func (notifier *notifierForAccount) UnnotifiedCount() (int, error) {
 	return unnotified, nil
 }
 
+// UnnotifiedIDs implements accounts.Notifier.
+func (notifier *notifierForAccount) UnnotifiedIDs() ([][]byte, error) {
+	ids := [][]byte{}
+	err := notifier.read(func(bucketUnnotified, bucketSeen *bbolt.Bucket) {
+		if bucketUnnotified == nil {
+			return
+		}
+		cursor := bucketUnnotified.Cursor()
+		for id, _ := cursor.First(); id != nil; id, _ = cursor.Next() {
+			// The key is only valid during the db transaction.
+			ids = append(ids, append([]byte{}, id...))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // MarkAllNotified implements accounts.Notifier.
 func (notifier *notifierForAccount) MarkAllNotified() error {
 	return notifier.write(func(bucketUnnotified, bucketSeen *bbolt.Bucket) error {
@@ -0,0 +1,63 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifierUnnotifiedIDs(t *testing.T) {
+	notifier, err := NewNotifier(filepath.Join(test.TstTempDir("notifier"), "notifier.db"))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, notifier.Close()) }()
+
+	accountNotifier := notifier.ForAccount("account")
+	ids, err := accountNotifier.UnnotifiedIDs()
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	require.NoError(t, accountNotifier.Put([]byte("a")))
+	require.NoError(t, accountNotifier.Put([]byte("b")))
+	ids, err = accountNotifier.UnnotifiedIDs()
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("a"), []byte("b")}, ids)
+
+	require.NoError(t, accountNotifier.MarkAllNotified())
+	require.NoError(t, accountNotifier.Put([]byte("a")))
+	require.NoError(t, accountNotifier.Put([]byte("c")))
+	ids, err = accountNotifier.UnnotifiedIDs()
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("c")}, ids)
+
+	// Other accounts are separate.
+	ids, err = notifier.ForAccount("other").UnnotifiedIDs()
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func TestNotifierIDs(t *testing.T) {
+	txID := "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	hash, err := chainhash.NewHashFromStr(txID)
+	require.NoError(t, err)
+	require.Equal(t, []string{txID, string(hash[:])}, notifierIDs(txID))
+
+	ethTxID := "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+	require.Equal(t, []string{ethTxID}, notifierIDs(ethTxID))
+}
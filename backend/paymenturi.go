@@ -0,0 +1,190 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable/action"
+)
+
+const (
+	// errPaymentURIInvalid is returned if the URI is not a valid BIP-21 payment request.
+	errPaymentURIInvalid errp.ErrorCode = "paymentURIInvalid"
+	// errPaymentURINoAccounts is returned if there is no account which can pay to the address.
+	errPaymentURINoAccounts errp.ErrorCode = "paymentURINoAccounts"
+)
+
+// paymentURICoins are the coins which can pay a payment request, per URI scheme.
+var paymentURICoins = map[string][]coinpkg.Code{
+	"bitcoin":  {coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeRBTC, coinpkg.CodeTSIG},
+	"litecoin": {coinpkg.CodeLTC, coinpkg.CodeTLTC},
+}
+
+// PaymentURI is a payment request of a `bitcoin:` or `litecoin:` URI (BIP-21) the user opened with
+// the app, to be paid in a prefilled send screen.
+type PaymentURI struct {
+	// ErrorCode is set if the URI can't be paid. The fields below are empty then.
+	ErrorCode errp.ErrorCode `json:"errorCode,omitempty"`
+	Address   string         `json:"address"`
+	// Amount is the requested amount formatted in the unit configured for the coin, e.g. BTC or
+	// sat. Empty if the URI has no amount.
+	Amount string `json:"amount"`
+	Label  string `json:"label"`
+	// Message is the description of the payment.
+	Message string `json:"message"`
+	// PayjoinURL is the Payjoin (BIP-78) endpoint of the receiver, if any.
+	PayjoinURL string `json:"payjoinURL"`
+	// AccountCode is the account picked to pay from. It is the first account with enough available
+	// funds, or the first account which can pay to the address.
+	AccountCode accountsTypes.Code `json:"accountCode"`
+	// Accounts are all accounts which can pay to the address, so the user can choose another one.
+	Accounts []account `json:"accounts"`
+}
+
+// PaymentURI returns the payment request of the last opened `bitcoin:` or `litecoin:` URI, or nil
+// if there is none.
+func (backend *Backend) PaymentURI() *PaymentURI {
+	defer backend.paymentURILock.RLock()()
+	return backend.paymentURI
+}
+
+// setPaymentURI replaces the current payment request and sends it to the frontend, which opens the
+// send screen of the picked account.
+func (backend *Backend) setPaymentURI(paymentURI *PaymentURI) {
+	defer backend.paymentURILock.Lock()()
+	backend.paymentURI = paymentURI
+	backend.Notify(observable.Event{
+		Subject: "payment-uri",
+		Action:  action.Replace,
+		Object:  paymentURI,
+	})
+}
+
+// PaymentURIClear is called when the user dismissed the payment request or filled in the send
+// screen with it.
+func (backend *Backend) PaymentURIClear() {
+	backend.setPaymentURI(nil)
+}
+
+// parsePaymentURI parses a BIP-21 URI, returning its address and query params. The amount is not
+// validated.
+func parsePaymentURI(uri url.URL) (string, url.Values, error) {
+	// `bitcoin:<address>` is parsed as an opaque URI, but some apps use `bitcoin://<address>`.
+	address := uri.Opaque
+	if address == "" {
+		address = uri.Host
+	}
+	if address == "" {
+		return "", nil, errp.New("address missing")
+	}
+	params := uri.Query()
+	for param := range params {
+		// Required params which are not understood make the URI invalid, see BIP-21.
+		if strings.HasPrefix(param, "req-") {
+			return "", nil, errp.Newf("unsupported required param %s", param)
+		}
+	}
+	return address, params, nil
+}
+
+// handlePaymentURI handles a `bitcoin:` or `litecoin:` URI the operating system passed to the app.
+// It picks an account which can pay the request and notifies the frontend to open a prefilled send
+// screen.
+func (backend *Backend) handlePaymentURI(uri url.URL) {
+	log := backend.log.WithField("payment-uri", uri.String())
+	address, params, err := parsePaymentURI(uri)
+	if err != nil {
+		log.WithError(err).Error("Invalid payment URI")
+		backend.setPaymentURI(&PaymentURI{ErrorCode: errPaymentURIInvalid})
+		return
+	}
+	var amount *big.Rat
+	if amountParam := params.Get("amount"); amountParam != "" {
+		// The amount is always in BTC/LTC, as a decimal number.
+		amount, _ = new(big.Rat).SetString(amountParam)
+		if amount == nil || amount.Sign() <= 0 || strings.ContainsAny(amountParam, "eE/") {
+			log.Error("Invalid amount")
+			backend.setPaymentURI(&PaymentURI{ErrorCode: errPaymentURIInvalid})
+			return
+		}
+	}
+
+	paymentURI := &PaymentURI{
+		Address:    address,
+		Label:      params.Get("label"),
+		Message:    params.Get("message"),
+		PayjoinURL: params.Get("pj"),
+		Accounts:   []account{},
+	}
+	coinCodes := paymentURICoins[uri.Scheme]
+	var candidates []accounts.Interface
+	for _, acct := range backend.Accounts() {
+		config := acct.Config().Config
+		if config.Inactive || config.HiddenBecauseUnused {
+			continue
+		}
+		btcCoin, ok := acct.Coin().(*btc.Coin)
+		if !ok || !slices.Contains(coinCodes, btcCoin.Code()) {
+			continue
+		}
+		// The address also tells the network, e.g. mainnet or testnet.
+		if _, err := btcCoin.AddressToPkScript(address); err != nil {
+			continue
+		}
+		candidates = append(candidates, acct)
+		paymentURI.Accounts = append(paymentURI.Accounts, account{Name: config.Name, Code: config.Code})
+	}
+	if len(candidates) == 0 {
+		log.Error("No account can pay to the address")
+		backend.setPaymentURI(&PaymentURI{ErrorCode: errPaymentURINoAccounts})
+		return
+	}
+	picked := candidates[0]
+	if amount != nil {
+		for _, acct := range candidates {
+			if hasEnoughFunds(acct, amount) {
+				picked = acct
+				break
+			}
+		}
+		paymentURI.Amount = picked.Coin().FormatAmount(picked.Coin().SetAmount(amount, false), false)
+	}
+	paymentURI.AccountCode = picked.Config().Config.Code
+	log.WithField("account", paymentURI.AccountCode).Info("Opening payment request")
+	backend.setPaymentURI(paymentURI)
+}
+
+// hasEnoughFunds returns true if the available balance of the account covers the amount, given in
+// the main unit of the coin, e.g. BTC. Returns false if the account is not synced yet.
+func hasEnoughFunds(acct accounts.Interface, amount *big.Rat) bool {
+	if !acct.Synced() {
+		return false
+	}
+	balance, err := acct.Balance()
+	if err != nil {
+		return false
+	}
+	return balance.Available().BigInt().Cmp(acct.Coin().SetAmount(amount, false).BigInt()) >= 0
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts"
+	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/btc/types"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlePaymentURI(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+	// Available balance in satoshi per account.
+	balances := map[accountsTypes.Code]int64{
+		"v0-55555555-btc-0": 50000,
+		"v0-55555555-btc-1": 200000,
+	}
+	b.makeBtcAccount = func(config *accounts.AccountConfig, coin *btc.Coin, gapLimits *types.GapLimits, log *logrus.Entry) accounts.Interface {
+		accountMock := MockBtcAccount(t, config, coin, gapLimits, log)
+		accountMock.SyncedFunc = func() bool { return true }
+		accountMock.BalanceFunc = func() (*accounts.Balance, error) {
+			return accounts.NewBalance(
+				coinpkg.NewAmountFromInt64(balances[config.Config.Code]), coinpkg.NewAmountFromInt64(0)), nil
+		}
+		return accountMock
+	}
+
+	require.Nil(t, b.PaymentURI())
+	const address = "bc1qxp6xr63t098rl9udlynrktq00un6vqduzjgua3"
+
+	// No accounts yet.
+	b.HandleURI("bitcoin:" + address)
+	require.Equal(t, &PaymentURI{ErrorCode: errPaymentURINoAccounts}, b.PaymentURI())
+
+	ks := makeBitBox02Multi()
+	b.registerKeystore(ks)
+	_, err := b.CreateAndPersistAccountConfig(coinpkg.CodeBTC, "Bitcoin 2", ks)
+	require.NoError(t, err)
+	expectedAccounts := []account{
+		{Name: "Bitcoin", Code: "v0-55555555-btc-0"},
+		{Name: "Bitcoin 2", Code: "v0-55555555-btc-1"},
+	}
+
+	b.HandleURI("bitcoin:" + address + "?label=Shop&message=Order%2042&pj=https://example.com/pj")
+	require.Equal(t, &PaymentURI{
+		Address:     address,
+		Label:       "Shop",
+		Message:     "Order 42",
+		PayjoinURL:  "https://example.com/pj",
+		AccountCode: "v0-55555555-btc-0",
+		Accounts:    expectedAccounts,
+	}, b.PaymentURI())
+
+	// The first account with enough funds is picked.
+	b.HandleURI("bitcoin:" + address + "?amount=0.001")
+	require.Equal(t, "0.00100000", b.PaymentURI().Amount)
+	require.Equal(t, accountsTypes.Code("v0-55555555-btc-1"), b.PaymentURI().AccountCode)
+
+	// If no account has enough funds, the first one is picked.
+	b.HandleURI("BITCOIN:" + address + "?amount=1")
+	require.Equal(t, "1.00000000", b.PaymentURI().Amount)
+	require.Equal(t, accountsTypes.Code("v0-55555555-btc-0"), b.PaymentURI().AccountCode)
+
+	b.PaymentURIClear()
+	require.Nil(t, b.PaymentURI())
+
+	for _, uri := range []string{
+		"bitcoin:",
+		"bitcoin:" + address + "?amount=-1",
+		"bitcoin:" + address + "?amount=1e-3",
+		"bitcoin:" + address + "?amount=abc",
+		"bitcoin:" + address + "?req-somethingyoudontunderstand=50",
+	} {
+		b.HandleURI(uri)
+		require.Equal(t, &PaymentURI{ErrorCode: errPaymentURIInvalid}, b.PaymentURI(), uri)
+	}
+
+	// Testnet and Litecoin addresses can't be paid from the Bitcoin accounts.
+	b.HandleURI("bitcoin:tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx")
+	require.Equal(t, &PaymentURI{ErrorCode: errPaymentURINoAccounts}, b.PaymentURI())
+	b.HandleURI("litecoin:" + address)
+	require.Equal(t, &PaymentURI{ErrorCode: errPaymentURINoAccounts}, b.PaymentURI())
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/notifications"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable/action"
+)
+
+// pinnedCertExpiryWarning is how long before its expiry a pinned cert is reported as expiring.
+const pinnedCertExpiryWarning = 30 * 24 * time.Hour
+
+// PinnedCert is a PEM certificate pinned for a custom Electrum server.
+type PinnedCert struct {
+	// CoinCode is set if the server is configured for all accounts of a coin in the app config.
+	CoinCode coinpkg.Code `json:"coinCode,omitempty"`
+	// AccountCode is set if the server is configured for a single account in the accounts config.
+	AccountCode accountsTypes.Code `json:"accountCode,omitempty"`
+	Server      string             `json:"server"`
+	// Fingerprint is the hex encoded SHA-256 hash of the DER encoded certificate.
+	Fingerprint string    `json:"fingerprint"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	NotBefore   time.Time `json:"notBefore"`
+	NotAfter    time.Time `json:"notAfter"`
+	// Expiring is true if the cert expires within pinnedCertExpiryWarning or already expired.
+	Expiring bool `json:"expiring"`
+	// Error is set if the PEM cert could not be parsed. Only Server and the codes are set then.
+	Error string `json:"error,omitempty"`
+}
+
+// parsePinnedCert fills in the details of the first certificate in the PEM data.
+func parsePinnedCert(pinnedCert *PinnedCert, pemCert string, now time.Time) {
+	block, _ := pem.Decode([]byte(pemCert))
+	if block == nil || block.Type != "CERTIFICATE" {
+		pinnedCert.Error = "no PEM certificate found"
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		pinnedCert.Error = err.Error()
+		return
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	pinnedCert.Fingerprint = hex.EncodeToString(fingerprint[:])
+	pinnedCert.Subject = cert.Subject.String()
+	pinnedCert.Issuer = cert.Issuer.String()
+	pinnedCert.NotBefore = cert.NotBefore
+	pinnedCert.NotAfter = cert.NotAfter
+	pinnedCert.Expiring = now.Add(pinnedCertExpiryWarning).After(cert.NotAfter)
+}
+
+// coinElectrumServers returns the Electrum servers of all BTC-based coins in the app config, so
+// they can be modified in place.
+func coinElectrumServers(backendConfig *config.Backend) map[coinpkg.Code]*[]*config.ServerInfo {
+	return map[coinpkg.Code]*[]*config.ServerInfo{
+		coinpkg.CodeBTC:  &backendConfig.BTC.ElectrumServers,
+		coinpkg.CodeTBTC: &backendConfig.TBTC.ElectrumServers,
+		coinpkg.CodeTSIG: &backendConfig.TSIG.ElectrumServers,
+		coinpkg.CodeRBTC: &backendConfig.RBTC.ElectrumServers,
+		coinpkg.CodeLTC:  &backendConfig.LTC.ElectrumServers,
+		coinpkg.CodeTLTC: &backendConfig.TLTC.ElectrumServers,
+	}
+}
+
+// PinnedCerts lists the certs pinned for the Electrum servers of the coins and of single accounts,
+// the coin servers first, ordered by code and server. Certs which can't be parsed are included with
+// their Error set.
+func (backend *Backend) PinnedCerts() []*PinnedCert {
+	now := time.Now()
+	result := []*PinnedCert{}
+	appConfig := backend.config.AppConfig()
+	for coinCode, servers := range coinElectrumServers(&appConfig.Backend) {
+		for _, server := range *servers {
+			if server.PEMCert == "" {
+				continue
+			}
+			pinnedCert := &PinnedCert{CoinCode: coinCode, Server: server.Server}
+			parsePinnedCert(pinnedCert, server.PEMCert, now)
+			result = append(result, pinnedCert)
+		}
+	}
+	for _, account := range backend.config.AccountsConfig().Accounts {
+		for _, server := range account.ElectrumServers {
+			if server.PEMCert == "" {
+				continue
+			}
+			pinnedCert := &PinnedCert{AccountCode: account.Code, Server: server.Server}
+			parsePinnedCert(pinnedCert, server.PEMCert, now)
+			result = append(result, pinnedCert)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		// The account code is empty for the coin servers, which are listed first.
+		if result[i].AccountCode != result[j].AccountCode {
+			return result[i].AccountCode < result[j].AccountCode
+		}
+		if result[i].CoinCode != result[j].CoinCode {
+			return result[i].CoinCode < result[j].CoinCode
+		}
+		return result[i].Server < result[j].Server
+	})
+	return result
+}
+
+// PinnedCert returns the pinned cert with the given hex encoded SHA-256 fingerprint. If it is
+// pinned for several servers, the first one as ordered by PinnedCerts() is returned.
+func (backend *Backend) PinnedCert(fingerprint string) (*PinnedCert, error) {
+	for _, pinnedCert := range backend.PinnedCerts() {
+		if pinnedCert.Error == "" && strings.EqualFold(pinnedCert.Fingerprint, fingerprint) {
+			return pinnedCert, nil
+		}
+	}
+	return nil, errp.Newf("pinned cert %s not found", fingerprint)
+}
+
+// RemovePinnedCert unpins the cert with the given hex encoded SHA-256 fingerprint from all
+// servers it is pinned for. The servers are kept and verified against the system's root
+// certificates instead. Like other changes to the servers, this applies after a restart.
+func (backend *Backend) RemovePinnedCert(fingerprint string) error {
+	matches := func(server *config.ServerInfo) bool {
+		if server.PEMCert == "" {
+			return false
+		}
+		var pinnedCert PinnedCert
+		parsePinnedCert(&pinnedCert, server.PEMCert, time.Now())
+		return pinnedCert.Error == "" && strings.EqualFold(pinnedCert.Fingerprint, fingerprint)
+	}
+	// The servers are copied instead of modified in place, as the connections opened at startup
+	// might still reference them.
+	removed := false
+	unpin := func(servers []*config.ServerInfo) []*config.ServerInfo {
+		if !slices.ContainsFunc(servers, matches) {
+			return servers
+		}
+		removed = true
+		result := make([]*config.ServerInfo, len(servers))
+		for i, server := range servers {
+			result[i] = server
+			if matches(server) {
+				result[i] = &config.ServerInfo{Server: server.Server, TLS: server.TLS}
+			}
+		}
+		return result
+	}
+	err := backend.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		for _, servers := range coinElectrumServers(&appConfig.Backend) {
+			*servers = unpin(*servers)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Only modify the accounts config if needed, as it can't be modified while it is locked.
+	for _, account := range backend.config.AccountsConfig().Accounts {
+		if !slices.ContainsFunc(account.ElectrumServers, matches) {
+			continue
+		}
+		err := backend.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+			for _, account := range accountsConfig.Accounts {
+				account.ElectrumServers = unpin(account.ElectrumServers)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		break
+	}
+	if !removed {
+		return errp.Newf("pinned cert %s not found", fingerprint)
+	}
+	backend.log.WithField("fingerprint", fingerprint).Info("removed pinned cert")
+	backend.Notify(observable.Event{
+		Subject: "certs/pinned",
+		Action:  action.Reload,
+	})
+	return nil
+}
+
+// checkPinnedCerts adds a notification for each pinned cert which is about to expire, as the
+// connection to its server fails once it expired.
+func (backend *Backend) checkPinnedCerts() {
+	for _, pinnedCert := range backend.PinnedCerts() {
+		if pinnedCert.Error != "" || !pinnedCert.Expiring {
+			continue
+		}
+		backend.log.
+			WithField("server", pinnedCert.Server).
+			WithField("notAfter", pinnedCert.NotAfter).
+			Warn("pinned cert is about to expire")
+		backend.addNotification(notifications.TypePinnedCertExpiring, map[string]string{
+			"server":      pinnedCert.Server,
+			"fingerprint": pinnedCert.Fingerprint,
+			"notAfter":    pinnedCert.NotAfter.Format(time.DateOnly),
+		})
+	}
+}
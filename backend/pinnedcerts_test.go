@@ -0,0 +1,129 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/notifications"
+	"github.com/stretchr/testify/require"
+)
+
+// makeTestCert returns a self-signed PEM cert expiring at notAfter and its fingerprint.
+func makeTestCert(t *testing.T, commonName string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	fingerprint := sha256.Sum256(der)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		hex.EncodeToString(fingerprint[:])
+}
+
+func TestPinnedCerts(t *testing.T) {
+	b := newBackend(t, testnetDisabled, regtestDisabled)
+	defer b.Close()
+
+	// The default servers have the Shift Crypto root CA pinned.
+	defaultCerts := b.PinnedCerts()
+	require.NotEmpty(t, defaultCerts)
+	for _, pinnedCert := range defaultCerts {
+		require.Empty(t, pinnedCert.Error)
+		require.False(t, pinnedCert.Expiring)
+	}
+
+	expiringCert, expiringFingerprint := makeTestCert(t, "expiring", time.Now().Add(7*24*time.Hour))
+	validCert, validFingerprint := makeTestCert(t, "valid", time.Now().Add(365*24*time.Hour))
+	require.NoError(t, b.config.ModifyAppConfig(func(appConfig *config.AppConfig) error {
+		appConfig.Backend.BTC.ElectrumServers = append(appConfig.Backend.BTC.ElectrumServers,
+			&config.ServerInfo{Server: "expiring.example.com:50002", TLS: true, PEMCert: expiringCert},
+			&config.ServerInfo{Server: "invalid.example.com:50002", TLS: true, PEMCert: "invalid"},
+		)
+		return nil
+	}))
+	require.NoError(t, b.config.ModifyAccountsConfig(func(accountsConfig *config.AccountsConfig) error {
+		accountsConfig.Accounts = append(accountsConfig.Accounts, &config.Account{
+			CoinCode: coinpkg.CodeBTC,
+			Code:     "v0-test-btc-0",
+			ElectrumServers: []*config.ServerInfo{
+				{Server: "valid.example.com:50002", TLS: true, PEMCert: validCert},
+				{Server: "expiring.example.com:50002", TLS: true, PEMCert: expiringCert},
+			},
+		})
+		return nil
+	}))
+
+	pinnedCerts := b.PinnedCerts()
+	require.Len(t, pinnedCerts, len(defaultCerts)+4)
+
+	pinnedCert, err := b.PinnedCert(validFingerprint)
+	require.NoError(t, err)
+	require.Equal(t, "v0-test-btc-0", string(pinnedCert.AccountCode))
+	require.Equal(t, "valid.example.com:50002", pinnedCert.Server)
+	require.Equal(t, "CN=valid", pinnedCert.Subject)
+	require.False(t, pinnedCert.Expiring)
+
+	// Lookups are case insensitive. The coin server is ordered before the account server.
+	pinnedCert, err = b.PinnedCert(strings.ToUpper(expiringFingerprint))
+	require.NoError(t, err)
+	require.Equal(t, coinpkg.CodeBTC, pinnedCert.CoinCode)
+	require.True(t, pinnedCert.Expiring)
+
+	_, err = b.PinnedCert("00")
+	require.Error(t, err)
+
+	b.checkPinnedCerts()
+	expiringNotifications := 0
+	for _, notification := range b.Notifications() {
+		if notification.Type == notifications.TypePinnedCertExpiring {
+			expiringNotifications++
+			require.Equal(t, expiringFingerprint, notification.Params["fingerprint"])
+		}
+	}
+	// The server is configured for the coin and the account, but only reported once.
+	require.Equal(t, 1, expiringNotifications)
+
+	// The cert is unpinned from both the coin and the account server, the servers are kept.
+	require.NoError(t, b.RemovePinnedCert(expiringFingerprint))
+	_, err = b.PinnedCert(expiringFingerprint)
+	require.Error(t, err)
+	require.Len(t, b.PinnedCerts(), len(defaultCerts)+2)
+	btcServers := b.config.AppConfig().Backend.BTC.ElectrumServers
+	require.Equal(t,
+		&config.ServerInfo{Server: "expiring.example.com:50002", TLS: true},
+		btcServers[len(btcServers)-2])
+
+	require.Error(t, b.RemovePinnedCert(expiringFingerprint))
+}
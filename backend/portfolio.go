@@ -0,0 +1,128 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"math/big"
+
+	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+)
+
+// PortfolioCoin is the total balance of all accounts of one coin.
+type PortfolioCoin struct {
+	CoinCode coinpkg.Code `json:"coinCode"`
+	CoinName string       `json:"coinName"`
+	// Amount is the total balance formatted in Unit.
+	Amount string `json:"amount"`
+	Unit   string `json:"unit"`
+	// FiatValue is the total balance converted to the main fiat currency, formatted for the
+	// frontend.
+	FiatValue string `json:"fiatValue"`
+}
+
+// PortfolioAccount is the balance of one account.
+type PortfolioAccount struct {
+	Code     accountsTypes.Code `json:"code"`
+	Name     string             `json:"name"`
+	CoinCode coinpkg.Code       `json:"coinCode"`
+	// Amount is the balance formatted in Unit.
+	Amount string `json:"amount"`
+	Unit   string `json:"unit"`
+	// FiatValue is the balance converted to the main fiat currency, formatted for the frontend.
+	FiatValue string `json:"fiatValue"`
+}
+
+// Portfolio summarizes the balances of all active accounts, per coin and per account, and their
+// total value in the main fiat currency using the latest rates.
+type Portfolio struct {
+	FiatUnit string `json:"fiatUnit"`
+	// Total is the value of all accounts in FiatUnit, formatted for the frontend.
+	Total string `json:"total"`
+	// Coins are ordered by the first account of each coin.
+	Coins    []PortfolioCoin    `json:"coins"`
+	Accounts []PortfolioAccount `json:"accounts"`
+}
+
+// Portfolio returns the balances of all active accounts. Inactive, hidden and failed accounts are
+// skipped. rates.ErrRatesNotAvailable is returned if there are no rates for a coin yet.
+func (backend *Backend) Portfolio() (*Portfolio, error) {
+	fiat := backend.Config().AppConfig().Backend.MainFiat
+	portfolio := &Portfolio{
+		FiatUnit: fiat,
+		Coins:    []PortfolioCoin{},
+		Accounts: []PortfolioAccount{},
+	}
+	type coinTotal struct {
+		coin      coinpkg.Coin
+		amount    *big.Int
+		fiatValue *big.Rat
+	}
+	var coinTotals []*coinTotal
+	coinTotalsByCode := map[coinpkg.Code]*coinTotal{}
+	total := new(big.Rat)
+
+	for _, account := range backend.Accounts() {
+		if account.Config().Config.Inactive || account.Config().Config.HiddenBecauseUnused {
+			continue
+		}
+		if account.FatalError() {
+			continue
+		}
+		if err := account.Initialize(); err != nil {
+			return nil, err
+		}
+		balance, err := account.Balance()
+		if err != nil {
+			return nil, err
+		}
+		fiatValue, err := backend.accountFiatBalance(account, fiat)
+		if err != nil {
+			return nil, err
+		}
+		accountCoin := account.Coin()
+		amount := balance.Available()
+		portfolio.Accounts = append(portfolio.Accounts, PortfolioAccount{
+			Code:      account.Config().Config.Code,
+			Name:      account.Config().Config.Name,
+			CoinCode:  accountCoin.Code(),
+			Amount:    accountCoin.FormatAmount(amount, false),
+			Unit:      accountCoin.GetFormatUnit(false),
+			FiatValue: coinpkg.FormatAsCurrency(fiatValue, fiat),
+		})
+
+		ct, ok := coinTotalsByCode[accountCoin.Code()]
+		if !ok {
+			ct = &coinTotal{coin: accountCoin, amount: new(big.Int), fiatValue: new(big.Rat)}
+			coinTotalsByCode[accountCoin.Code()] = ct
+			coinTotals = append(coinTotals, ct)
+		}
+		ct.amount.Add(ct.amount, amount.BigInt())
+		ct.fiatValue.Add(ct.fiatValue, fiatValue)
+		total.Add(total, fiatValue)
+	}
+
+	for _, ct := range coinTotals {
+		portfolio.Coins = append(portfolio.Coins, PortfolioCoin{
+			CoinCode:  ct.coin.Code(),
+			CoinName:  ct.coin.Name(),
+			Amount:    ct.coin.FormatAmount(coinpkg.NewAmount(ct.amount), false),
+			Unit:      ct.coin.GetFormatUnit(false),
+			FiatValue: coinpkg.FormatAsCurrency(ct.fiatValue, fiat),
+		})
+	}
+	portfolio.Total = coinpkg.FormatAsCurrency(total, fiat)
+	return portfolio, nil
+}
@@ -2,6 +2,7 @@ package rates
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"math"
 	"path/filepath"
 	"time"
@@ -9,6 +10,16 @@ import (
 	"go.etcd.io/bbolt"
 )
 
+// latestBucket is the DB bucket holding the most recently fetched latest rates. The history buckets
+// are keyed by coin+fiat pairs, so there is no conflict.
+const latestBucket = "latest"
+
+// latestRecord is how the latest rates are stored in the latestBucket.
+type latestRecord struct {
+	Timestamp int64                         `json:"timestamp"`
+	Rates     map[string]map[string]float64 `json:"rates"`
+}
+
 func openRatesDB(dir string) (*bbolt.DB, error) {
 	opt := &bbolt.Options{Timeout: 5 * time.Second} // network disks may take long
 	return bbolt.Open(filepath.Join(dir, "rates.db"), 0600, opt)
@@ -57,3 +68,40 @@ func (updater *RateUpdater) dumpHistoryBucket(key string, rates []exchangeRate)
 		return nil
 	})
 }
+
+// loadLatest loads the most recently fetched latest rates and the time at which they were fetched.
+// nil is returned if none were stored yet.
+func (updater *RateUpdater) loadLatest() (map[string]map[string]float64, time.Time, error) {
+	var record *latestRecord
+	err := updater.historyDB.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(latestBucket))
+		if bucket == nil {
+			return nil
+		}
+		value := bucket.Get([]byte(latestBucket))
+		if value == nil {
+			return nil
+		}
+		record = &latestRecord{}
+		return json.Unmarshal(value, record)
+	})
+	if err != nil || record == nil {
+		return nil, time.Time{}, err
+	}
+	return record.Rates, time.Unix(record.Timestamp, 0), nil
+}
+
+// dumpLatest stores the latest rates, replacing the previously stored ones.
+func (updater *RateUpdater) dumpLatest(rates map[string]map[string]float64, updated time.Time) error {
+	value, err := json.Marshal(latestRecord{Timestamp: updated.Unix(), Rates: rates})
+	if err != nil {
+		return err
+	}
+	return updater.historyDB.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(latestBucket))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(latestBucket), value)
+	})
+}
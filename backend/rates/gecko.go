@@ -99,6 +99,16 @@ var (
 		"SEK": "sek",
 		"PLN": "pln",
 		"CZK": "czk",
+		"DKK": "dkk",
+		"HUF": "huf",
+		"INR": "inr",
+		"MXN": "mxn",
+		"NZD": "nzd",
+		"PHP": "php",
+		"THB": "thb",
+		"TRY": "try",
+		"TWD": "twd",
+		"ZAR": "zar",
 		// Satoshi rates are converted manually in the backend using Bitcoin.
 		"sat": "btc",
 	}
@@ -125,5 +135,15 @@ var (
 		"sek": "SEK",
 		"pln": "PLN",
 		"czk": "CZK",
+		"dkk": "DKK",
+		"huf": "HUF",
+		"inr": "INR",
+		"mxn": "MXN",
+		"nzd": "NZD",
+		"php": "PHP",
+		"thb": "THB",
+		"try": "TRY",
+		"twd": "TWD",
+		"zar": "ZAR",
 	}
 )
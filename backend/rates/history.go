@@ -11,6 +11,8 @@ import (
 	"sort"
 	"strconv"
 	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 )
 
 // ReconfigureHistory resets all currently running historical rates goroutines.
@@ -67,6 +69,7 @@ func (updater *RateUpdater) stopAllHistory() {
 // forward in time starting with the last fetched timestamp in a loop.
 // It returns when the context is done.
 func (updater *RateUpdater) historyUpdateLoop(ctx context.Context, coin, fiat string) {
+	defer crashreport.Recover()
 	updater.log.Printf("started historyUpdateLoop for %s/%s", coin, fiat)
 	for {
 		// When to update next, after this loop iteration is done.
@@ -116,6 +119,7 @@ func (updater *RateUpdater) historyUpdateLoop(ctx context.Context, coin, fiat st
 // It does so in a loop and returns only after all data is backfilled or the context is done.
 // Callers are expected to run this in a separate goroutine.
 func (updater *RateUpdater) backfillHistory(ctx context.Context, coin, fiat string) {
+	defer crashreport.Recover()
 	updater.log.Printf("started backfillHistory for %s/%s", coin, fiat)
 	for {
 		// When to update next, after this loop iteration is done.
@@ -252,6 +256,43 @@ func (updater *RateUpdater) HistoryLatestTimestampCoin(coin string) time.Time {
 	return result
 }
 
+// HistoricalRate is an exchange rate at a point in time.
+type HistoricalRate struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// HistoricalRates returns the known historical exchange rates for the given coin/fiat pair within
+// the [from, to] time range, in ascending order. If interval is positive, at most one rate per
+// interval is returned, e.g. one per day if interval is 24 hours. Only the pairs activated with
+// ReconfigureHistory are available, an empty slice is returned for all others.
+func (updater *RateUpdater) HistoricalRates(
+	coin, fiat string, from, to time.Time, interval time.Duration) []HistoricalRate {
+	updater.historyMu.RLock()
+	defer updater.historyMu.RUnlock()
+	data := updater.history[coin+fiat]
+	// Find an index of the first entry not older than the from timestamp.
+	idx := sort.Search(len(data), func(i int) bool {
+		return !data[i].timestamp.Before(from)
+	})
+	result := []HistoricalRate{}
+	var lastBucket time.Time
+	for _, rate := range data[idx:] {
+		if rate.timestamp.After(to) {
+			break
+		}
+		if interval > 0 {
+			bucket := rate.timestamp.Truncate(interval)
+			if len(result) > 0 && bucket.Equal(lastBucket) {
+				continue
+			}
+			lastBucket = bucket
+		}
+		result = append(result, HistoricalRate{Timestamp: rate.timestamp, Value: rate.value})
+	}
+	return result
+}
+
 type fetchTimeRange struct {
 	start time.Time
 	end   func() time.Time
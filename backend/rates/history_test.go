@@ -161,6 +161,30 @@ func TestHistoryEarliestLatest(t *testing.T) {
 	assert.Zero(t, updater.HistoryLatestTimestampFiat([]string{"foo", "btc"}, "USD"))
 }
 
+func TestHistoricalRates(t *testing.T) {
+	updater := MockRateUpdater()
+	defer updater.Stop()
+
+	all := updater.HistoricalRates("btc", "USD", time.Time{}, time.Now(), 0)
+	assert.Equal(t, []HistoricalRate{
+		{Timestamp: time.Unix(1598832062, 0), Value: 1},
+		{Timestamp: time.Unix(1598918700, 0), Value: 2},
+		{Timestamp: time.Unix(1598922501, 0), Value: 3},
+		{Timestamp: time.Unix(1599091262, 0), Value: 4},
+	}, all)
+
+	// Bounds are inclusive.
+	assert.Equal(t, all[1:3],
+		updater.HistoricalRates("btc", "USD", time.Unix(1598918700, 0), time.Unix(1598922501, 0), 0))
+
+	// One rate per day.
+	assert.Equal(t, []HistoricalRate{all[0], all[1], all[3]},
+		updater.HistoricalRates("btc", "USD", time.Time{}, time.Now(), 24*time.Hour))
+
+	assert.Empty(t, updater.HistoricalRates("btc", "USD", time.Unix(1599091263, 0), time.Now(), 0))
+	assert.Empty(t, updater.HistoricalRates("foo", "USD", time.Time{}, time.Now(), 0))
+}
+
 // TestLoadDumpBucketUnusableDB ensures no panic when the RateUpdater.historyDB is unusable.
 func TestLoadDumpBucketUnusableDB(t *testing.T) {
 	updater := NewRateUpdater(nil, "/dev/null")
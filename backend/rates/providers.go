@@ -0,0 +1,260 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// Names of the supported providers of the latest exchange rates.
+const (
+	// ProviderCoinGecko is the default provider.
+	ProviderCoinGecko = "coingecko"
+	// ProviderCryptoCompare fetches rates from the CryptoCompare API.
+	ProviderCryptoCompare = "cryptocompare"
+	// ProviderKraken fetches rates from the Kraken exchange. Only BTC, ETH and LTC and a few fiat
+	// currencies are supported.
+	ProviderKraken = "kraken"
+)
+
+const (
+	cryptoCompareAPI = "https://min-api.cryptocompare.com/data"
+	krakenAPI        = "https://api.kraken.com/0/public"
+)
+
+// latestProvider fetches the latest exchange rates from an external API.
+type latestProvider interface {
+	// name is one of the Provider* constants.
+	name() string
+	// fetchLatest returns the latest rates for the given fiat codes (e.g. "USD") keyed by coin unit
+	// (e.g. "BTC"), with values mapped by fiat code.
+	fetchLatest(ctx context.Context, fiats []string) (map[string]map[string]float64, error)
+}
+
+// orderedProviders returns the providers to try in order, starting with the preferred one.
+func orderedProviders(providers []latestProvider, preferred string) []latestProvider {
+	result := make([]latestProvider, 0, len(providers))
+	for _, provider := range providers {
+		if provider.name() == preferred {
+			result = append(result, provider)
+		}
+	}
+	for _, provider := range providers {
+		if provider.name() != preferred {
+			result = append(result, provider)
+		}
+	}
+	return result
+}
+
+// getJSON performs a GET request and unmarshals the response, which must not be longer than
+// maxSize bytes.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, maxSize int64, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return errp.Newf("bad response code %d", res.StatusCode)
+	}
+	responseBody, err := io.ReadAll(io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	if int64(len(responseBody)) > maxSize {
+		return errp.Newf("rates response too long (> %d bytes)", maxSize)
+	}
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		return errp.WithMessage(err,
+			fmt.Sprintf("could not parse rates response: %s", string(responseBody)))
+	}
+	return nil
+}
+
+// geckoProvider fetches the latest rates from CoinGecko, or the configured mirror.
+type geckoProvider struct {
+	updater *RateUpdater
+}
+
+func (provider *geckoProvider) name() string {
+	return ProviderCoinGecko
+}
+
+func (provider *geckoProvider) fetchLatest(ctx context.Context, fiats []string) (map[string]map[string]float64, error) {
+	updater := provider.updater
+	geckoFiats := make([]string, len(fiats))
+	for i, fiat := range fiats {
+		geckoFiats[i] = toGeckoFiat[fiat]
+	}
+	param := url.Values{
+		"ids":           {simplePriceAllIDs},
+		"vs_currencies": {strings.Join(geckoFiats, ",")},
+	}
+	endpoint := fmt.Sprintf("%s/simple/price?%s", updater.coingeckoURL, param.Encode())
+	var geckoRates map[string]map[string]float64
+	err := updater.geckoLimiter.Call(ctx, "updateLast", func() error {
+		return getJSON(ctx, updater.httpClient, endpoint, 10240, &geckoRates)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Convert the map with coingecko coin/fiat codes to a map of coin/fiat units.
+	rates := map[string]map[string]float64{}
+	for coin, val := range geckoRates {
+		coinUnit := geckoCoinToUnit[coin]
+		if coinUnit == "" {
+			updater.log.Errorf("unsupported CoinGecko coin: %s", coin)
+			continue
+		}
+		newVal := map[string]float64{}
+		for geckoFiat, rate := range val {
+			fiat, ok := fromGeckoFiat[geckoFiat]
+			if !ok {
+				updater.log.Errorf("unsupported fiat: %s", geckoFiat)
+				continue
+			}
+			newVal[fiat] = rate
+		}
+		rates[coinUnit] = newVal
+	}
+	return rates, nil
+}
+
+// cryptoCompareProvider fetches the latest rates from CryptoCompare.
+type cryptoCompareProvider struct {
+	httpClient *http.Client
+	apiURL     string
+}
+
+func (provider *cryptoCompareProvider) name() string {
+	return ProviderCryptoCompare
+}
+
+func (provider *cryptoCompareProvider) fetchLatest(ctx context.Context, fiats []string) (map[string]map[string]float64, error) {
+	coinUnits := make([]string, 0, len(geckoCoinToUnit))
+	for _, coinUnit := range geckoCoinToUnit {
+		coinUnits = append(coinUnits, coinUnit)
+	}
+	param := url.Values{
+		"fsyms": {strings.Join(coinUnits, ",")},
+		"tsyms": {strings.Join(fiats, ",")},
+	}
+	endpoint := fmt.Sprintf("%s/pricemulti?%s", provider.apiURL, param.Encode())
+	// The coin units and fiat codes are the same as in the app.
+	var rates map[string]map[string]float64
+	if err := getJSON(ctx, provider.httpClient, endpoint, 10240, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// krakenProvider fetches the latest rates from the Kraken exchange.
+type krakenProvider struct {
+	httpClient *http.Client
+	apiURL     string
+}
+
+var (
+	// krakenPairs are the pairs (Kraken asset names) for which rates are fetched.
+	krakenPairs = []string{
+		"XBTUSD", "XBTEUR", "XBTGBP", "XBTCHF", "XBTCAD", "XBTJPY", "XBTAUD",
+		"ETHUSD", "ETHEUR", "ETHGBP", "ETHCHF", "ETHCAD", "ETHJPY", "ETHAUD",
+		"LTCUSD", "LTCEUR", "LTCGBP",
+	}
+	// krakenAssetToUnit maps the Kraken asset names, as returned in pair names of the response,
+	// to coin units.
+	krakenAssetToUnit = map[string]string{
+		"XXBT": "BTC",
+		"XBT":  "BTC",
+		"XETH": "ETH",
+		"ETH":  "ETH",
+		"XLTC": "LTC",
+		"LTC":  "LTC",
+	}
+)
+
+func (provider *krakenProvider) name() string {
+	return ProviderKraken
+}
+
+func (provider *krakenProvider) fetchLatest(ctx context.Context, fiats []string) (map[string]map[string]float64, error) {
+	var pairs []string
+	for _, pair := range krakenPairs {
+		for _, fiat := range fiats {
+			if strings.HasSuffix(pair, fiat) {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, errp.New("kraken does not support the selected fiat currencies")
+	}
+	endpoint := fmt.Sprintf("%s/Ticker?pair=%s", provider.apiURL, strings.Join(pairs, ","))
+	var response struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			// Last trade closed: [price, lot volume].
+			LastTrade []string `json:"c"`
+		} `json:"result"`
+	}
+	if err := getJSON(ctx, provider.httpClient, endpoint, 20480, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Error) > 0 {
+		return nil, errp.Newf("kraken error: %s", strings.Join(response.Error, ", "))
+	}
+	rates := map[string]map[string]float64{}
+	for pair, ticker := range response.Result {
+		// Pairs are returned as e.g. "XXBTZUSD" or "XBTCHF".
+		if len(pair) < 6 || len(ticker.LastTrade) == 0 {
+			continue
+		}
+		fiat := pair[len(pair)-3:]
+		asset := pair[:len(pair)-3]
+		if len(asset) == 5 {
+			asset = strings.TrimSuffix(asset, "Z")
+		}
+		coinUnit, ok := krakenAssetToUnit[asset]
+		if !ok {
+			continue
+		}
+		price, err := strconv.ParseFloat(ticker.LastTrade[0], 64)
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+		if rates[coinUnit] == nil {
+			rates[coinUnit] = map[string]float64{}
+		}
+		rates[coinUnit][fiat] = price
+	}
+	return rates, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoCompareProvider(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/pricemulti", r.URL.Path)
+		require.Contains(t, r.URL.Query().Get("fsyms"), "BTC")
+		require.Equal(t, "CHF,USD", r.URL.Query().Get("tsyms"))
+		_, _ = w.Write([]byte(`{"BTC":{"USD":60000.5,"CHF":55000},"ETH":{"USD":3000}}`))
+	}))
+	defer ts.Close()
+
+	provider := &cryptoCompareProvider{httpClient: http.DefaultClient, apiURL: ts.URL}
+	rates, err := provider.fetchLatest(context.Background(), []string{"CHF", "USD"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]float64{
+		"BTC": {"USD": 60000.5, "CHF": 55000},
+		"ETH": {"USD": 3000},
+	}, rates)
+}
+
+func TestKrakenProvider(t *testing.T) {
+	response := `{"error":[],"result":{
+		"XXBTZUSD":{"c":["60000.10000","0.1"]},
+		"XBTCHF":{"c":["55000.0","0.1"]},
+		"XETHZEUR":{"c":["2800.5","1"]},
+		"LTCGBP":{"c":["70","1"]},
+		"FOOUSD":{"c":["1","1"]}
+	}}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/Ticker", r.URL.Path)
+		require.NotContains(t, r.URL.Query().Get("pair"), "JPY")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer ts.Close()
+
+	fiats := []string{"CHF", "EUR", "GBP", "USD"}
+	provider := &krakenProvider{httpClient: http.DefaultClient, apiURL: ts.URL}
+	rates, err := provider.fetchLatest(context.Background(), fiats)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]float64{
+		"BTC": {"USD": 60000.1, "CHF": 55000},
+		"ETH": {"EUR": 2800.5},
+		"LTC": {"GBP": 70},
+	}, rates)
+
+	response = `{"error":["EQuery:Unknown asset pair"]}`
+	_, err = provider.fetchLatest(context.Background(), fiats)
+	require.Error(t, err)
+
+	// No pairs for the selected fiat currencies.
+	_, err = provider.fetchLatest(context.Background(), []string{"CZK"})
+	require.Error(t, err)
+}
+
+func TestLatestFiats(t *testing.T) {
+	updater := NewRateUpdater(http.DefaultClient, "/dev/null")
+	defer updater.Stop()
+	require.Len(t, updater.latestFiats(), len(fromGeckoFiat))
+
+	updater.SetFiats(func() []string { return []string{"USD", "sat", "CHF", "USD", "XYZ"} })
+	require.Equal(t, []string{"BTC", "CHF", "USD"}, updater.latestFiats())
+
+	var vsCurrencies string
+	gecko := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vsCurrencies = r.URL.Query().Get("vs_currencies")
+		_, _ = w.Write([]byte(`{"bitcoin":{"usd":60000,"chf":55000,"btc":1}}`))
+	}))
+	defer gecko.Close()
+	updater.SetCoingeckoURL(gecko.URL)
+	updater.updateLast(context.Background())
+	require.Equal(t, "btc,chf,usd", vsCurrencies)
+	require.Equal(t, 55000., updater.LatestPrice()["BTC"]["CHF"])
+}
+
+func TestUpdateLastFailover(t *testing.T) {
+	var geckoCalled atomic.Bool
+	gecko := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		geckoCalled.Store(true)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer gecko.Close()
+	cryptoCompare := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"BTC":{"USD":60000,"BTC":1}}`))
+	}))
+	defer cryptoCompare.Close()
+	kraken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"c":["50000","1"]}}}`))
+	}))
+	defer kraken.Close()
+
+	updater := NewRateUpdater(http.DefaultClient, "/dev/null")
+	defer updater.Stop()
+	updater.SetCoingeckoURL(gecko.URL)
+	updater.providers = []latestProvider{
+		&geckoProvider{updater: updater},
+		&cryptoCompareProvider{httpClient: http.DefaultClient, apiURL: cryptoCompare.URL},
+		&krakenProvider{httpClient: http.DefaultClient, apiURL: kraken.URL},
+	}
+
+	// CoinGecko is tried first by default, and CryptoCompare is used as it fails.
+	updater.updateLast(context.Background())
+	require.True(t, geckoCalled.Load())
+	require.Equal(t, 60000., updater.LatestPrice()["BTC"]["USD"])
+	require.Equal(t, 60000., updater.LatestPrice()["TBTC"]["USD"])
+	require.Equal(t, 1e8, updater.LatestPrice()["BTC"]["sat"])
+	require.Equal(t, 60000./1e8, updater.LatestPrice()["sat"]["USD"])
+
+	// The preferred provider is tried first.
+	geckoCalled.Store(false)
+	updater.SetPreferredProvider(func() string { return ProviderKraken })
+	updater.updateLast(context.Background())
+	require.False(t, geckoCalled.Load())
+	require.Equal(t, 50000., updater.LatestPrice()["BTC"]["USD"])
+}
+
+func TestLatestPersisted(t *testing.T) {
+	var fail atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"BTC":{"USD":60000}}`))
+	}))
+	defer ts.Close()
+	dbdir := test.TstTempDir("TestLatestPersisted")
+	defer os.RemoveAll(dbdir)
+
+	updater1 := NewRateUpdater(http.DefaultClient, dbdir)
+	stale, updated := updater1.LatestPriceStatus()
+	require.False(t, stale)
+	require.True(t, updated.IsZero())
+	updater1.providers = []latestProvider{
+		&cryptoCompareProvider{httpClient: http.DefaultClient, apiURL: ts.URL},
+	}
+	updater1.updateLast(context.Background())
+	stale, updated = updater1.LatestPriceStatus()
+	require.False(t, stale)
+	require.False(t, updated.IsZero())
+	require.Equal(t, 60000., updater1.LatestPrice()["BTC"]["USD"])
+	updater1.Stop() // close dbdir so updater2 can load
+
+	// The rates are available, but stale, after a restart.
+	updater2 := NewRateUpdater(http.DefaultClient, dbdir)
+	defer updater2.Stop()
+	require.Equal(t, 60000., updater2.LatestPrice()["BTC"]["USD"])
+	stale, updated2 := updater2.LatestPriceStatus()
+	require.True(t, stale)
+	require.Equal(t, updated.Unix(), updated2.Unix())
+
+	// The stale rates are still served if the update fails.
+	fail.Store(true)
+	updater2.providers = []latestProvider{
+		&cryptoCompareProvider{httpClient: http.DefaultClient, apiURL: ts.URL},
+	}
+	updater2.updateLast(context.Background())
+	require.Equal(t, 60000., updater2.LatestPrice()["BTC"]["USD"])
+	stale, _ = updater2.LatestPriceStatus()
+	require.True(t, stale)
+
+	fail.Store(false)
+	updater2.updateLast(context.Background())
+	stale, _ = updater2.LatestPriceStatus()
+	require.False(t, stale)
+}
@@ -17,16 +17,13 @@ package rates
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"reflect"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/crashreport"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/observable"
@@ -37,9 +34,8 @@ import (
 )
 
 const (
-	// Latest rates are fetched for all these (coin, fiat) pairs.
-	simplePriceAllIDs        = "bitcoin,litecoin,ethereum,basic-attention-token,dai,chainlink,maker,usd-coin,tether,0x,wrapped-bitcoin,pax-gold"
-	simplePriceAllCurrencies = "usd,eur,chf,gbp,jpy,krw,cny,rub,cad,aud,ils,btc,sgd,hkd,brl,nok,sek,pln,czk"
+	// Latest rates are fetched for all these coins, see also RateUpdater.SetFiats.
+	simplePriceAllIDs = "bitcoin,litecoin,ethereum,basic-attention-token,dai,chainlink,maker,usd-coin,tether,0x,wrapped-bitcoin,pax-gold"
 	// RatesEventSubject is the Subject of the event generated by new rates fetching.
 	RatesEventSubject = "rates"
 
@@ -73,18 +69,28 @@ const (
 	CHF Fiat = "CHF"
 	CNY Fiat = "CNY"
 	CZK Fiat = "CZK"
+	DKK Fiat = "DKK"
 	EUR Fiat = "EUR"
 	GBP Fiat = "GBP"
 	HKD Fiat = "HKD"
+	HUF Fiat = "HUF"
 	ILS Fiat = "ILS"
+	INR Fiat = "INR"
 	JPY Fiat = "JPY"
 	KRW Fiat = "KRW"
+	MXN Fiat = "MXN"
 	NOK Fiat = "NOK"
+	NZD Fiat = "NZD"
+	PHP Fiat = "PHP"
 	PLN Fiat = "PLN"
 	RUB Fiat = "RUB"
 	SEK Fiat = "SEK"
 	SGD Fiat = "SGD"
+	THB Fiat = "THB"
+	TRY Fiat = "TRY"
+	TWD Fiat = "TWD"
 	USD Fiat = "USD"
+	ZAR Fiat = "ZAR"
 	BTC Fiat = "BTC"
 	SAT Fiat = "sat"
 )
@@ -96,8 +102,14 @@ type RateUpdater struct {
 	httpClient *http.Client
 	log        *logrus.Entry
 
+	lastMu sync.RWMutex // guards last, lastUpdated and lastStale
 	// last contains most recent conversion to fiat, keyed by a coin.
 	last map[string]map[string]float64
+	// lastUpdated is the time at which last was fetched.
+	lastUpdated time.Time
+	// lastStale is true if last could not be updated, e.g. because the app is offline. last
+	// then contains the previously fetched rates, possibly loaded from the database cache.
+	lastStale bool
 	// stopLastUpdateLoop is the cancel function of the lastUpdateLoop context.
 	stopLastUpdateLoop context.CancelFunc
 
@@ -120,6 +132,14 @@ type RateUpdater struct {
 	coingeckoURL string
 	// All requests to coingeckoURL are rate-limited using geckoLimiter.
 	geckoLimiter *ratelimit.LimitedCall
+
+	// providers are the sources of the latest rates, tried in order until one succeeds.
+	providers []latestProvider
+	// preferredProvider returns the name of the provider to try first. Can be nil.
+	preferredProvider func() string
+	// fiats returns the fiat currencies selected by the user, for which the latest rates are
+	// fetched. Can be nil, in which case the rates for all supported fiat currencies are fetched.
+	fiats func() []string
 }
 
 // NewRateUpdater returns a new rates updater.
@@ -129,8 +149,9 @@ type RateUpdater struct {
 // To stay within acceptable rate limits defined by CoinGeckoRateLimit, callers can
 // use util/ratelimit package.
 //
-// Both Last and PriceAt of the newly created updater always return zero values
-// until data is fetched from the external APIs. To make the updater start fetching data
+// Both Last and PriceAt of the newly created updater return zero values until data is
+// fetched from the external APIs, except that the last fetched rates are loaded from the
+// database cache and returned as stale rates, see LatestPriceStatus. To make the updater start fetching data
 // the caller can use StartCurrentRates and ReconfigureHistory, respectively.
 //
 // The caller is advised to always call Stop as soon as the updater is no longer needed
@@ -147,7 +168,7 @@ func NewRateUpdater(client *http.Client, dbdir string) *RateUpdater {
 		db = &bbolt.DB{}
 	}
 	apiURL := shiftGeckoMirrorAPIV3
-	return &RateUpdater{
+	updater := &RateUpdater{
 		last:         make(map[string]map[string]float64),
 		history:      make(map[string][]exchangeRate),
 		historyGo:    make(map[string]context.CancelFunc),
@@ -157,6 +178,67 @@ func NewRateUpdater(client *http.Client, dbdir string) *RateUpdater {
 		coingeckoURL: apiURL,
 		geckoLimiter: ratelimit.NewLimitedCall(apiRateLimit(apiURL)),
 	}
+	updater.providers = []latestProvider{
+		&geckoProvider{updater: updater},
+		&cryptoCompareProvider{httpClient: client, apiURL: cryptoCompareAPI},
+		&krakenProvider{httpClient: client, apiURL: krakenAPI},
+	}
+	last, updated, err := updater.loadLatest()
+	switch {
+	case err == bbolt.ErrDatabaseNotOpen:
+		// Already logged above.
+	case err != nil:
+		// Non-critical: can continue without database cache.
+		log.WithError(err).Error("loadLatest")
+	case last != nil:
+		updater.last = last
+		updater.lastUpdated = updated
+		updater.lastStale = true
+	}
+	return updater
+}
+
+// SetPreferredProvider sets a function returning the name of the provider of the latest rates
+// which is tried first, e.g. ProviderCoinGecko. If it fails, the other providers are used as a
+// fallback.
+func (updater *RateUpdater) SetPreferredProvider(preferred func() string) {
+	updater.preferredProvider = preferred
+}
+
+// SetFiats sets a function returning the fiat currencies the user selected, e.g. "USD" or "sat".
+// Only the latest rates of these currencies are fetched, to reduce the load on the rates APIs.
+func (updater *RateUpdater) SetFiats(fiats func() []string) {
+	updater.fiats = fiats
+}
+
+// latestFiats returns the sorted fiat codes for which the latest rates are fetched. Unsupported
+// currencies are skipped. All supported currencies are returned if none are selected.
+func (updater *RateUpdater) latestFiats() []string {
+	var selected []string
+	if updater.fiats != nil {
+		selected = updater.fiats()
+	}
+	fiats := map[string]struct{}{}
+	for _, fiat := range selected {
+		if fiat == SAT.String() {
+			// Satoshi rates are converted from Bitcoin rates.
+			fiat = BTC.String()
+		}
+		if _, ok := toGeckoFiat[fiat]; ok {
+			fiats[fiat] = struct{}{}
+		}
+	}
+	if len(fiats) == 0 {
+		for _, fiat := range fromGeckoFiat {
+			fiats[fiat] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(fiats))
+	for fiat := range fiats {
+		result = append(result, fiat)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // SetCoingeckoURL overrides the default URL the rates updater connects to. Useful for testing.
@@ -168,9 +250,20 @@ func (updater *RateUpdater) SetCoingeckoURL(url string) {
 // The returned map is keyed by a crypto coin with values mapped by fiat rates.
 // RateUpdater assumes the returned value is never modified by the callers.
 func (updater *RateUpdater) LatestPrice() map[string]map[string]float64 {
+	updater.lastMu.RLock()
+	defer updater.lastMu.RUnlock()
 	return updater.last
 }
 
+// LatestPriceStatus returns whether the rates returned by LatestPrice are stale, i.e. they could not
+// be updated recently, and the time at which they were fetched. The time is zero if no rates were
+// fetched yet.
+func (updater *RateUpdater) LatestPriceStatus() (bool, time.Time) {
+	updater.lastMu.RLock()
+	defer updater.lastMu.RUnlock()
+	return updater.lastStale, updater.lastUpdated
+}
+
 // LatestPriceForPair returns the conversion rate for the given (coin, fiat) pair. Returns an error
 // if the rates have not been fetched yet. `coinUnit` values are the same as `coin.Unit`.
 func (updater *RateUpdater) LatestPriceForPair(coinUnit, fiat string) (float64, error) {
@@ -250,6 +343,7 @@ func (updater *RateUpdater) Stop() {
 // lastUpdateLoop periodically updates most recent exchange rates.
 // It never returns until the context is done.
 func (updater *RateUpdater) lastUpdateLoop(ctx context.Context) {
+	defer crashreport.Recover()
 	for {
 		updater.updateLast(ctx)
 		select {
@@ -262,70 +356,38 @@ func (updater *RateUpdater) lastUpdateLoop(ctx context.Context) {
 }
 
 func (updater *RateUpdater) updateLast(ctx context.Context) {
-	param := url.Values{
-		"ids":           {simplePriceAllIDs},
-		"vs_currencies": {simplePriceAllCurrencies},
-	}
-	endpoint := fmt.Sprintf("%s/simple/price?%s", updater.coingeckoURL, param.Encode())
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
-	if err != nil {
-		updater.log.WithError(err).Error("could not create request")
-		updater.last = nil
-		return
+	preferred := ProviderCoinGecko
+	if updater.preferredProvider != nil {
+		preferred = updater.preferredProvider()
 	}
-
-	var geckoRates map[string]map[string]float64
-	callErr := updater.geckoLimiter.Call(ctx, "updateLast", func() error {
-		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		defer cancel()
-		res, err := updater.httpClient.Do(req.WithContext(ctx))
-		if err != nil {
-			return errp.WithStack(err)
-		}
-		defer res.Body.Close() //nolint:errcheck
-		if res.StatusCode != http.StatusOK {
-			return errp.Newf("bad response code %d", res.StatusCode)
+	fiats := updater.latestFiats()
+	var rates map[string]map[string]float64
+	for _, provider := range orderedProviders(updater.providers, preferred) {
+		var err error
+		rates, err = provider.fetchLatest(ctx, fiats)
+		if err == nil {
+			break
 		}
-		const max = 10240
-		responseBody, err := io.ReadAll(io.LimitReader(res.Body, max+1))
-		if err != nil {
-			return errp.WithStack(err)
-		}
-		if len(responseBody) > max {
-			return errp.Newf("rates response too long (> %d bytes)", max)
-		}
-		if err := json.Unmarshal(responseBody, &geckoRates); err != nil {
-			return errp.WithMessage(err,
-				fmt.Sprintf("could not parse rates response: %s", string(responseBody)))
+		updater.log.WithError(err).Errorf("updateLast: provider %s failed", provider.name())
+		rates = nil
+	}
+	if rates == nil {
+		updater.lastMu.Lock()
+		defer updater.lastMu.Unlock()
+		if updater.lastUpdated.IsZero() {
+			updater.last = nil
+		} else {
+			// Keep serving the previous rates, flagged as stale.
+			updater.lastStale = true
 		}
-		return nil
-	})
-	if callErr != nil {
-		updater.log.WithError(callErr).Errorf("updateLast")
-		updater.last = nil
 		return
 	}
-	// Convert the map with coingecko coin/fiat codes to a map of coin/fiat units.
-	rates := map[string]map[string]float64{}
-	for coin, val := range geckoRates {
-		coinUnit := geckoCoinToUnit[coin]
-		if coinUnit == "" {
-			updater.log.Errorf("unsupported CoinGecko coin: %s", coin)
-			continue
-		}
-		newVal := map[string]float64{}
-		for geckoFiat, rates := range val {
-			fiat, ok := fromGeckoFiat[geckoFiat]
-			if !ok {
-				updater.log.Errorf("unsupported fiat: %s", geckoFiat)
-				continue
-			}
-			if fiat == BTC.String() {
-				newVal[SAT.String()] = rates * unitSatoshi
-			}
-			newVal[fiat] = rates
+
+	// Provide rates in sat for all coins having a rate in BTC.
+	for _, val := range rates {
+		if rate, ok := val[BTC.String()]; ok {
+			val[SAT.String()] = rate * unitSatoshi
 		}
-		rates[coinUnit] = newVal
 	}
 
 	// Create sat rates from BTC
@@ -336,8 +398,10 @@ func (updater *RateUpdater) updateLast(ctx context.Context) {
 	rates[SAT.String()] = sat
 
 	// Provide conversion rates for testnets as well, useful for testing.
-	for _, testnetUnit := range []string{"TBTC", "RBTC", "TLTC", "SEPETH"} {
+	for _, testnetUnit := range []string{"TBTC", "TSIG", "RBTC", "TLTC", "SEPETH"} {
 		switch testnetUnit {
+		case "TSIG":
+			rates[testnetUnit] = rates[BTC.String()]
 		case "SEPETH":
 			rates[testnetUnit] = rates[testnetUnit[3:]]
 		default:
@@ -345,10 +409,20 @@ func (updater *RateUpdater) updateLast(ctx context.Context) {
 		}
 	}
 
+	now := time.Now()
+	if err := updater.dumpLatest(rates, now); err != nil && err != bbolt.ErrDatabaseNotOpen {
+		// Non-critical: can continue without database cache.
+		updater.log.WithError(err).Error("dumpLatest")
+	}
+	updater.lastMu.Lock()
+	updater.lastUpdated = now
+	updater.lastStale = false
 	if reflect.DeepEqual(rates, updater.last) {
+		updater.lastMu.Unlock()
 		return
 	}
 	updater.last = rates
+	updater.lastMu.Unlock()
 	updater.Notify(observable.Event{
 		Subject: RatesEventSubject,
 		Action:  action.Replace,
@@ -20,12 +20,17 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 )
 
+// maxMultisigCosigners is the maximum number of cosigners in a multisig configuration, matching
+// the limit of the BitBox02.
+const maxMultisigCosigners = 15
+
 // KeyInfo contains information about the key and where it is coming from.
 type KeyInfo struct {
 	// The root fingerprint is the first 32 bits of the hash160 of the pubkey at the keypath m/.
@@ -39,6 +44,42 @@ func (ki KeyInfo) String() string {
 	return fmt.Sprintf("keypath=%s", ki.AbsoluteKeypath.Encode())
 }
 
+// NewKeyInfoFromString parses a key with origin information, as used in output descriptors, e.g.
+// `[d34db33f/48'/0'/0'/2']xpub...`. Hardened derivation steps can also be marked with `h`. SLIP-132
+// encoded keys such as `Zpub...` are accepted as well, see ParseExtendedPublicKey().
+func NewKeyInfoFromString(key string) (*KeyInfo, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(key, "[") {
+		return nil, errp.New("key origin missing")
+	}
+	end := strings.Index(key, "]")
+	if end == -1 {
+		return nil, errp.New("invalid key origin")
+	}
+	origin := strings.SplitN(key[1:end], "/", 2)
+	rootFingerprint, err := hex.DecodeString(origin[0])
+	if err != nil || len(rootFingerprint) != 4 {
+		return nil, errp.New("invalid root fingerprint")
+	}
+	keypathString := "m/"
+	if len(origin) == 2 {
+		keypathString += strings.ReplaceAll(origin[1], "h", hardenedKeySymbol)
+	}
+	absoluteKeypath, err := NewAbsoluteKeypath(keypathString)
+	if err != nil {
+		return nil, err
+	}
+	extendedPublicKey, _, err := ParseExtendedPublicKey(key[end+1:])
+	if err != nil {
+		return nil, err
+	}
+	return &KeyInfo{
+		RootFingerprint:   rootFingerprint,
+		AbsoluteKeypath:   absoluteKeypath,
+		ExtendedPublicKey: extendedPublicKey,
+	}, nil
+}
+
 type keyInfoEncoding struct {
 	RootFingerprint string          `json:"rootFingerprint"`
 	Keypath         AbsoluteKeypath `json:"keypath"`
@@ -80,6 +121,18 @@ type BitcoinSimple struct {
 	ScriptType ScriptType `json:"scriptType"`
 }
 
+// BitcoinMultisig represents an m-of-n Bitcoin multisig signing configuration. The cosigner keys
+// are combined into a sorted (BIP-67) OP_CHECKMULTISIG script.
+type BitcoinMultisig struct {
+	// Threshold is the number of signatures needed to spend (the m in m-of-n).
+	Threshold uint32 `json:"threshold"`
+	// Cosigners contains the key info of all cosigners, including our own.
+	Cosigners  []KeyInfo  `json:"cosigners"`
+	ScriptType ScriptType `json:"scriptType"`
+	// OurXPubIndex is the index of our own key in Cosigners.
+	OurXPubIndex uint32 `json:"ourXPubIndex"`
+}
+
 // EthereumSimple represents a simple (standard single-sig, no exotic signing methods) Ethereum
 // signing configuration.
 type EthereumSimple struct {
@@ -90,8 +143,9 @@ type EthereumSimple struct {
 type Configuration struct {
 	// Poor man's union type: only one of the below can be non-nil.
 
-	BitcoinSimple  *BitcoinSimple  `json:"bitcoinSimple,omitempty"`
-	EthereumSimple *EthereumSimple `json:"ethereumSimple,omitempty"`
+	BitcoinSimple   *BitcoinSimple   `json:"bitcoinSimple,omitempty"`
+	BitcoinMultisig *BitcoinMultisig `json:"bitcoinMultisig,omitempty"`
+	EthereumSimple  *EthereumSimple  `json:"ethereumSimple,omitempty"`
 }
 
 // NewBitcoinConfiguration creates a new configuration.
@@ -116,6 +170,47 @@ func NewBitcoinConfiguration(
 	}
 }
 
+// NewBitcoinMultisigConfiguration creates a new m-of-n multisig configuration. The cosigners must
+// include our own key at index ourXPubIndex.
+func NewBitcoinMultisigConfiguration(
+	scriptType ScriptType,
+	threshold uint32,
+	cosigners []KeyInfo,
+	ourXPubIndex uint32,
+) (*Configuration, error) {
+	if scriptType != ScriptTypeP2WSH {
+		return nil, errp.Newf("unsupported multisig script type: %s", scriptType)
+	}
+	if len(cosigners) < 2 || len(cosigners) > maxMultisigCosigners {
+		return nil, errp.Newf("invalid number of cosigners: %d", len(cosigners))
+	}
+	if threshold == 0 || threshold > uint32(len(cosigners)) {
+		return nil, errp.Newf("invalid threshold %d for %d cosigners", threshold, len(cosigners))
+	}
+	if ourXPubIndex >= uint32(len(cosigners)) {
+		return nil, errp.Newf("invalid index of our xpub: %d", ourXPubIndex)
+	}
+	xpubs := map[string]struct{}{}
+	for _, cosigner := range cosigners {
+		if cosigner.ExtendedPublicKey.IsPrivate() {
+			panic("An extended key is private! Only extended public keys are accepted.")
+		}
+		xpub := cosigner.ExtendedPublicKey.String()
+		if _, ok := xpubs[xpub]; ok {
+			return nil, errp.New("duplicate cosigner xpub")
+		}
+		xpubs[xpub] = struct{}{}
+	}
+	return &Configuration{
+		BitcoinMultisig: &BitcoinMultisig{
+			Threshold:    threshold,
+			Cosigners:    cosigners,
+			ScriptType:   scriptType,
+			OurXPubIndex: ourXPubIndex,
+		},
+	}, nil
+}
+
 // NewEthereumConfiguration creates a new configuration.
 func NewEthereumConfiguration(
 	rootFingerprint []byte,
@@ -136,30 +231,43 @@ func NewEthereumConfiguration(
 	}
 }
 
+// ourKeyInfo returns our own key info. For multisig configurations, this is the key info of the
+// cosigner at OurXPubIndex.
+func (configuration *Configuration) ourKeyInfo() KeyInfo {
+	switch {
+	case configuration.BitcoinSimple != nil:
+		return configuration.BitcoinSimple.KeyInfo
+	case configuration.BitcoinMultisig != nil:
+		return configuration.BitcoinMultisig.Cosigners[configuration.BitcoinMultisig.OurXPubIndex]
+	default:
+		return configuration.EthereumSimple.KeyInfo
+	}
+}
+
 // ScriptType returns the configuration's keypath.
 func (configuration *Configuration) ScriptType() ScriptType {
+	if configuration.BitcoinMultisig != nil {
+		return configuration.BitcoinMultisig.ScriptType
+	}
 	return configuration.BitcoinSimple.ScriptType
 }
 
-// AbsoluteKeypath returns the configuration's keypath.
+// AbsoluteKeypath returns the configuration's keypath. For multisig configurations, this is the
+// keypath of our own key.
 func (configuration *Configuration) AbsoluteKeypath() AbsoluteKeypath {
-	if configuration.BitcoinSimple != nil {
-		return configuration.BitcoinSimple.KeyInfo.AbsoluteKeypath
-	}
-	return configuration.EthereumSimple.KeyInfo.AbsoluteKeypath
+	return configuration.ourKeyInfo().AbsoluteKeypath
 }
 
-// ExtendedPublicKey returns the configuration's extended public key.
+// ExtendedPublicKey returns the configuration's extended public key. For multisig
+// configurations, this is our own extended public key.
 func (configuration *Configuration) ExtendedPublicKey() *hdkeychain.ExtendedKey {
-	if configuration.BitcoinSimple != nil {
-		return configuration.BitcoinSimple.KeyInfo.ExtendedPublicKey
-	}
-	return configuration.EthereumSimple.KeyInfo.ExtendedPublicKey
+	return configuration.ourKeyInfo().ExtendedPublicKey
 }
 
 // AccountNumber returns the account number as present in the BIP44 keypath.
 // The configuration keypath must be a BIP44 keypath:
 // m/purpose'/coin'/account' for Bitcoin-based coins.
+// m/48'/coin'/account'/scriptType' for Bitcoin multisig (BIP-48).
 // m/44'/coin'/0'/0/account for Ethereum.
 // For invalid keypaths, zero is returned for the account number, along with an error.
 func (configuration *Configuration) AccountNumber() (uint16, error) {
@@ -170,6 +278,13 @@ func (configuration *Configuration) AccountNumber() (uint16, error) {
 		}
 		return uint16(keypath[2] - hdkeychain.HardenedKeyStart), nil
 	}
+	if configuration.BitcoinMultisig != nil {
+		keypath := configuration.AbsoluteKeypath().ToUInt32()
+		if len(keypath) != 4 || keypath[2] < hdkeychain.HardenedKeyStart {
+			return 0, errp.Newf("unexpected bitcoin multisig keypath: %v", keypath)
+		}
+		return uint16(keypath[2] - hdkeychain.HardenedKeyStart), nil
+	}
 	if configuration.EthereumSimple != nil {
 		keypath := configuration.EthereumSimple.KeyInfo.AbsoluteKeypath.ToUInt32()
 		if len(keypath) != 5 || keypath[4] >= hdkeychain.HardenedKeyStart {
@@ -189,6 +304,23 @@ func (configuration *Configuration) PublicKey() *btcec.PublicKey {
 	return publicKey
 }
 
+// CosignerPublicKeys returns the public keys of all cosigners of a multisig configuration, in the
+// order of the cosigners.
+func (configuration *Configuration) CosignerPublicKeys() ([]*btcec.PublicKey, error) {
+	if configuration.BitcoinMultisig == nil {
+		return nil, errp.New("Can only call this on a bitcoin multisig configuration")
+	}
+	publicKeys := make([]*btcec.PublicKey, len(configuration.BitcoinMultisig.Cosigners))
+	for i, cosigner := range configuration.BitcoinMultisig.Cosigners {
+		publicKey, err := cosigner.ExtendedPublicKey.ECPubKey()
+		if err != nil {
+			return nil, errp.WithStack(err)
+		}
+		publicKeys[i] = publicKey
+	}
+	return publicKeys, nil
+}
+
 // Derive derives a subkeypath from the configuration's base absolute keypath.
 func (configuration *Configuration) Derive(relativeKeypath RelativeKeypath) (*Configuration, error) {
 	btc := configuration.BitcoinSimple
@@ -209,6 +341,33 @@ func (configuration *Configuration) Derive(relativeKeypath RelativeKeypath) (*Co
 		), nil
 	}
 
+	multisig := configuration.BitcoinMultisig
+	if multisig != nil {
+		if relativeKeypath.Hardened() {
+			return nil, errp.New("A configuration can only be derived with a non-hardened relative keypath.")
+		}
+		cosigners := make([]KeyInfo, len(multisig.Cosigners))
+		for i, cosigner := range multisig.Cosigners {
+			derivedPublicKey, err := relativeKeypath.Derive(cosigner.ExtendedPublicKey)
+			if err != nil {
+				return nil, err
+			}
+			cosigners[i] = KeyInfo{
+				RootFingerprint:   cosigner.RootFingerprint,
+				AbsoluteKeypath:   cosigner.AbsoluteKeypath.Append(relativeKeypath),
+				ExtendedPublicKey: derivedPublicKey,
+			}
+		}
+		return &Configuration{
+			BitcoinMultisig: &BitcoinMultisig{
+				Threshold:    multisig.Threshold,
+				Cosigners:    cosigners,
+				ScriptType:   multisig.ScriptType,
+				OurXPubIndex: multisig.OurXPubIndex,
+			},
+		}, nil
+	}
+
 	return nil, errp.New("Can only call this on a bitcoin configuration")
 }
 
@@ -218,6 +377,13 @@ func (configuration *Configuration) String() string {
 		return fmt.Sprintf("bitcoinSimple;scriptType=%s;%s",
 			configuration.BitcoinSimple.ScriptType, configuration.BitcoinSimple.KeyInfo)
 	}
+	if configuration.BitcoinMultisig != nil {
+		return fmt.Sprintf("bitcoinMultisig;scriptType=%s;%d-of-%d;%s",
+			configuration.BitcoinMultisig.ScriptType,
+			configuration.BitcoinMultisig.Threshold,
+			len(configuration.BitcoinMultisig.Cosigners),
+			configuration.ourKeyInfo())
+	}
 	return fmt.Sprintf("ethereumSimple;%s", configuration.EthereumSimple.KeyInfo)
 }
 
@@ -233,6 +399,9 @@ func (configs Configurations) RootFingerprint() ([]byte, error) {
 		if config.BitcoinSimple != nil {
 			return config.BitcoinSimple.KeyInfo.RootFingerprint, nil
 		}
+		if config.BitcoinMultisig != nil {
+			return config.ourKeyInfo().RootFingerprint, nil
+		}
 		if config.EthereumSimple != nil {
 			return config.EthereumSimple.KeyInfo.RootFingerprint, nil
 		}
@@ -248,6 +417,11 @@ func (configs Configurations) ContainsRootFingerprint(rootFingerprint []byte) bo
 				return true
 			}
 		}
+		if config.BitcoinMultisig != nil {
+			if bytes.Equal(config.ourKeyInfo().RootFingerprint, rootFingerprint) {
+				return true
+			}
+		}
 		if config.EthereumSimple != nil {
 			if bytes.Equal(config.EthereumSimple.KeyInfo.RootFingerprint, rootFingerprint) {
 				return true
@@ -264,6 +438,19 @@ func (configs Configurations) FindScriptType(scriptType ScriptType) int {
 		if config.BitcoinSimple != nil && config.BitcoinSimple.ScriptType == scriptType {
 			return idx
 		}
+		if config.BitcoinMultisig != nil && config.BitcoinMultisig.ScriptType == scriptType {
+			return idx
+		}
 	}
 	return -1
 }
+
+// IsMultisig returns true if the configurations belong to a multisig account.
+func (configs Configurations) IsMultisig() bool {
+	for _, config := range configs {
+		if config.BitcoinMultisig != nil {
+			return true
+		}
+	}
+	return false
+}
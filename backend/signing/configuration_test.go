@@ -148,3 +148,89 @@ func TestAccountNumber(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, uint16(0), num)
 }
+
+func TestNewKeyInfoFromString(t *testing.T) {
+	const xpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+	for _, key := range []string{
+		"[d34db33f/48'/0'/0'/2']" + xpub,
+		" [d34db33f/48h/0h/0h/2h]" + xpub + " ",
+	} {
+		keyInfo, err := NewKeyInfoFromString(key)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0xd3, 0x4d, 0xb3, 0x3f}, keyInfo.RootFingerprint)
+		require.Equal(t, "m/48'/0'/0'/2'", keyInfo.AbsoluteKeypath.Encode())
+		require.Equal(t, xpub, keyInfo.ExtendedPublicKey.String())
+	}
+
+	for _, key := range []string{
+		xpub,
+		"[d34db33f/48'/0'/0'/2'" + xpub,
+		"[d34db3/48'/0'/0'/2']" + xpub,
+		"[d34db33f/48'/x'/0'/2']" + xpub,
+		"[d34db33f/48'/0'/0'/2']xpub",
+	} {
+		_, err := NewKeyInfoFromString(key)
+		require.Error(t, err, key)
+	}
+}
+
+func TestBitcoinMultisigConfiguration(t *testing.T) {
+	keypath := mustKeypath("m/48'/1'/3'/2'")
+	var cosigners []KeyInfo
+	for i := byte(0); i < 3; i++ {
+		seed := make([]byte, 32)
+		seed[0] = i
+		xprv, err := hdkeychain.NewMaster(seed, &chaincfg.TestNet3Params)
+		require.NoError(t, err)
+		xpub, err := xprv.Neuter()
+		require.NoError(t, err)
+		cosigners = append(cosigners, KeyInfo{
+			RootFingerprint:   []byte{1, 2, 3, i},
+			AbsoluteKeypath:   keypath,
+			ExtendedPublicKey: xpub,
+		})
+	}
+
+	_, err := NewBitcoinMultisigConfiguration(ScriptTypeP2WPKH, 2, cosigners, 1)
+	require.Error(t, err)
+	_, err = NewBitcoinMultisigConfiguration(ScriptTypeP2WSH, 0, cosigners, 1)
+	require.Error(t, err)
+	_, err = NewBitcoinMultisigConfiguration(ScriptTypeP2WSH, 4, cosigners, 1)
+	require.Error(t, err)
+	_, err = NewBitcoinMultisigConfiguration(ScriptTypeP2WSH, 2, cosigners, 3)
+	require.Error(t, err)
+	_, err = NewBitcoinMultisigConfiguration(ScriptTypeP2WSH, 1, cosigners[:1], 0)
+	require.Error(t, err)
+	_, err = NewBitcoinMultisigConfiguration(
+		ScriptTypeP2WSH, 2, []KeyInfo{cosigners[0], cosigners[1], cosigners[0]}, 0)
+	require.Error(t, err)
+
+	cfg, err := NewBitcoinMultisigConfiguration(ScriptTypeP2WSH, 2, cosigners, 1)
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WSH, cfg.ScriptType())
+	require.Equal(t, cosigners[1].ExtendedPublicKey, cfg.ExtendedPublicKey())
+	accountNumber, err := cfg.AccountNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint16(3), accountNumber)
+	require.True(t, Configurations{cfg}.IsMultisig())
+	require.True(t, Configurations{cfg}.ContainsRootFingerprint([]byte{1, 2, 3, 1}))
+	require.False(t, Configurations{cfg}.ContainsRootFingerprint([]byte{1, 2, 3, 0}))
+
+	jsonBytes, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	var cfgDecoded Configuration
+	require.NoError(t, json.Unmarshal(jsonBytes, &cfgDecoded))
+	require.Nil(t, cfgDecoded.BitcoinSimple)
+	require.NotNil(t, cfgDecoded.BitcoinMultisig)
+	require.Equal(t, uint32(2), cfgDecoded.BitcoinMultisig.Threshold)
+	require.Equal(t, uint32(1), cfgDecoded.BitcoinMultisig.OurXPubIndex)
+	require.Len(t, cfgDecoded.BitcoinMultisig.Cosigners, 3)
+
+	derived, err := cfg.Derive(NewEmptyRelativeKeypath().Child(0, NonHardened).Child(5, NonHardened))
+	require.NoError(t, err)
+	require.Equal(t, "m/48'/1'/3'/2'/0/5", derived.AbsoluteKeypath().Encode())
+	publicKeys, err := derived.CosignerPublicKeys()
+	require.NoError(t, err)
+	require.Len(t, publicKeys, 3)
+	require.Equal(t, derived.PublicKey(), publicKeys[1])
+}
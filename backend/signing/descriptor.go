@@ -0,0 +1,249 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+const (
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+func descriptorPolymod(c uint64, value uint64) uint64 {
+	c0 := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ value
+	for i, generator := range []uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd} {
+		if (c0>>i)&1 == 1 {
+			c ^= generator
+		}
+	}
+	return c
+}
+
+// DescriptorChecksum computes the 8 character checksum of an output descriptor, see BIP-380.
+func DescriptorChecksum(descriptor string) (string, error) {
+	c := uint64(1)
+	class := uint64(0)
+	classCount := 0
+	for _, char := range descriptor {
+		position := strings.IndexRune(descriptorInputCharset, char)
+		if position == -1 {
+			return "", errp.Newf("invalid character in descriptor: %q", char)
+		}
+		c = descriptorPolymod(c, uint64(position&31))
+		class = class*3 + uint64(position>>5)
+		classCount++
+		if classCount == 3 {
+			c = descriptorPolymod(c, class)
+			class = 0
+			classCount = 0
+		}
+	}
+	if classCount > 0 {
+		c = descriptorPolymod(c, class)
+	}
+	for range 8 {
+		c = descriptorPolymod(c, 0)
+	}
+	c ^= 1
+	checksum := make([]byte, 8)
+	for j := range checksum {
+		checksum[j] = descriptorChecksumCharset[(c>>(5*(7-j)))&31]
+	}
+	return string(checksum), nil
+}
+
+// stripDescriptorChecksum removes the checksum from the descriptor, if present, after verifying it.
+func stripDescriptorChecksum(descriptor string) (string, error) {
+	descriptor = strings.TrimSpace(descriptor)
+	index := strings.LastIndex(descriptor, "#")
+	if index == -1 {
+		return descriptor, nil
+	}
+	checksum, err := DescriptorChecksum(descriptor[:index])
+	if err != nil {
+		return "", err
+	}
+	if descriptor[index+1:] != checksum {
+		return "", errp.New("invalid descriptor checksum")
+	}
+	return descriptor[:index], nil
+}
+
+// unwrapDescriptor returns the argument of `function(...)`, or false if the descriptor is not of
+// this form.
+func unwrapDescriptor(descriptor string, function string) (string, bool) {
+	if !strings.HasPrefix(descriptor, function+"(") || !strings.HasSuffix(descriptor, ")") {
+		return "", false
+	}
+	return descriptor[len(function)+1 : len(descriptor)-1], true
+}
+
+// parseDescriptorKey parses a key expression with origin info and a derivation suffix. The suffix
+// must be the receive (`/0/*`) or change (`/1/*`) chain, or both as a multipath (`/<0;1>/*`), as
+// this is how the app derives addresses from the account-level xpub.
+func parseDescriptorKey(key string) (*KeyInfo, error) {
+	key = strings.TrimSpace(key)
+	for _, suffix := range []string{"/<0;1>/*", "/0/*", "/1/*"} {
+		if strings.HasSuffix(key, suffix) {
+			return NewKeyInfoFromString(strings.TrimSuffix(key, suffix))
+		}
+	}
+	return nil, errp.Newf("unsupported key derivation, expected /<0;1>/*: %s", key)
+}
+
+// parseDescriptor parses a single descriptor without checksum.
+func parseDescriptor(descriptor string) (*Configuration, error) {
+	singleSig := func(scriptType ScriptType, key string) (*Configuration, error) {
+		keyInfo, err := parseDescriptorKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return NewBitcoinConfiguration(
+			scriptType, keyInfo.RootFingerprint, keyInfo.AbsoluteKeypath, keyInfo.ExtendedPublicKey), nil
+	}
+	if inner, ok := unwrapDescriptor(descriptor, "sh"); ok {
+		if key, ok := unwrapDescriptor(inner, "wpkh"); ok {
+			return singleSig(ScriptTypeP2WPKHP2SH, key)
+		}
+		return nil, errp.Newf("unsupported descriptor: %s", descriptor)
+	}
+	if key, ok := unwrapDescriptor(descriptor, "wpkh"); ok {
+		return singleSig(ScriptTypeP2WPKH, key)
+	}
+	if key, ok := unwrapDescriptor(descriptor, "pkh"); ok {
+		return singleSig(ScriptTypeP2PKH, key)
+	}
+	if key, ok := unwrapDescriptor(descriptor, "tr"); ok {
+		if strings.Contains(key, ",") {
+			return nil, errp.New("taproot descriptors with script paths are not supported")
+		}
+		return singleSig(ScriptTypeP2TR, key)
+	}
+	if inner, ok := unwrapDescriptor(descriptor, "wsh"); ok {
+		if _, ok := unwrapDescriptor(inner, "multi"); ok {
+			return nil, errp.New("only sortedmulti() multisig descriptors are supported")
+		}
+		multi, ok := unwrapDescriptor(inner, "sortedmulti")
+		if !ok {
+			return nil, errp.Newf("unsupported descriptor: %s", descriptor)
+		}
+		args := strings.Split(multi, ",")
+		threshold, err := strconv.ParseUint(strings.TrimSpace(args[0]), 10, 32)
+		if err != nil {
+			return nil, errp.New("invalid multisig threshold")
+		}
+		cosigners := make([]KeyInfo, len(args)-1)
+		for i, key := range args[1:] {
+			keyInfo, err := parseDescriptorKey(key)
+			if err != nil {
+				return nil, err
+			}
+			cosigners[i] = *keyInfo
+		}
+		return NewBitcoinMultisigConfiguration(ScriptTypeP2WSH, uint32(threshold), cosigners, 0)
+	}
+	return nil, errp.Newf("unsupported descriptor: %s", descriptor)
+}
+
+// NewConfigurationFromDescriptor creates a Bitcoin signing configuration from an output
+// descriptor. Supported are `pkh(KEY)`, `sh(wpkh(KEY))`, `wpkh(KEY)`, `tr(KEY)` and
+// `wsh(sortedmulti(k,KEY,...))`, where KEY is an xpub with origin info, e.g.
+// `[d34db33f/84'/0'/0']xpub.../<0;1>/*`. The checksum is verified if present.
+//
+// Instead of a multipath descriptor, the receive and change descriptors can be provided separated
+// by a newline. For multisig, the first cosigner is considered our own key.
+func NewConfigurationFromDescriptor(descriptor string) (*Configuration, error) {
+	var result *Configuration
+	for _, line := range strings.Split(strings.TrimSpace(descriptor), "\n") {
+		line, err := stripDescriptorChecksum(line)
+		if err != nil {
+			return nil, err
+		}
+		configuration, err := parseDescriptor(strings.ReplaceAll(line, " ", ""))
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			resultJSON, _ := json.Marshal(result)
+			configurationJSON, _ := json.Marshal(configuration)
+			if !bytes.Equal(resultJSON, configurationJSON) {
+				return nil, errp.New("the descriptors must only differ in the receive/change derivation")
+			}
+		}
+		result = configuration
+	}
+	return result, nil
+}
+
+// descriptorKey encodes the key info as a descriptor key expression for the given chain (0 for
+// receive, 1 for change), e.g. `[d34db33f/84'/0'/0']xpub.../0/*`.
+func descriptorKey(keyInfo KeyInfo, chain uint32) string {
+	return fmt.Sprintf("[%x%s]%s/%d/*",
+		keyInfo.RootFingerprint,
+		// The keypath is empty if the key origin is unknown.
+		strings.TrimSuffix(strings.TrimPrefix(keyInfo.AbsoluteKeypath.Encode(), "m"), "/"),
+		keyInfo.ExtendedPublicKey,
+		chain)
+}
+
+// Descriptor returns the output descriptor with checksum of the Bitcoin configuration for the
+// given chain (0 for receive addresses, 1 for change addresses).
+func (configuration *Configuration) Descriptor(chain uint32) (string, error) {
+	var descriptor string
+	switch {
+	case configuration.BitcoinSimple != nil:
+		key := descriptorKey(configuration.BitcoinSimple.KeyInfo, chain)
+		switch configuration.BitcoinSimple.ScriptType {
+		case ScriptTypeP2PKH:
+			descriptor = fmt.Sprintf("pkh(%s)", key)
+		case ScriptTypeP2WPKHP2SH:
+			descriptor = fmt.Sprintf("sh(wpkh(%s))", key)
+		case ScriptTypeP2WPKH:
+			descriptor = fmt.Sprintf("wpkh(%s)", key)
+		case ScriptTypeP2TR:
+			descriptor = fmt.Sprintf("tr(%s)", key)
+		default:
+			return "", errp.Newf("unsupported script type: %s", configuration.BitcoinSimple.ScriptType)
+		}
+	case configuration.BitcoinMultisig != nil:
+		multisig := configuration.BitcoinMultisig
+		if multisig.ScriptType != ScriptTypeP2WSH {
+			return "", errp.Newf("unsupported multisig script type: %s", multisig.ScriptType)
+		}
+		keys := make([]string, len(multisig.Cosigners))
+		for i, cosigner := range multisig.Cosigners {
+			keys[i] = descriptorKey(cosigner, chain)
+		}
+		descriptor = fmt.Sprintf("wsh(sortedmulti(%d,%s))", multisig.Threshold, strings.Join(keys, ","))
+	default:
+		return "", errp.New("descriptors are only available for bitcoin configurations")
+	}
+	checksum, err := DescriptorChecksum(descriptor)
+	if err != nil {
+		return "", err
+	}
+	return descriptor + "#" + checksum, nil
+}
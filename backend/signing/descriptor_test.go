@@ -0,0 +1,118 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	descriptorXpub1 = "xpub6DJ2dNUysrn5Vt36jH2KLBT2i1auw1tTSSomg8PhqNiUtx8QX2SvC9nrHu81fT41fvDUnhMjEzQgXnQjKEu3oaqMSzhSrHMxyyoEAmUHQbY"
+	descriptorXpub2 = "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL"
+)
+
+func TestDescriptorChecksum(t *testing.T) {
+	checksum, err := DescriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	require.Equal(t, "89f8spxm", checksum)
+
+	checksum, err = DescriptorChecksum("wpkh([d34db33f/84h/0h/0h]" + descriptorXpub1 + "/0/*)")
+	require.NoError(t, err)
+	require.Equal(t, "cjjspncu", checksum)
+
+	_, err = DescriptorChecksum("wpkh(ä)")
+	require.Error(t, err)
+}
+
+func TestNewConfigurationFromDescriptor(t *testing.T) {
+	cfg, err := NewConfigurationFromDescriptor(
+		"wpkh([d34db33f/84h/0h/0h]" + descriptorXpub1 + "/0/*)#cjjspncu")
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WPKH, cfg.ScriptType())
+	require.Equal(t, []byte{0xd3, 0x4d, 0xb3, 0x3f}, cfg.BitcoinSimple.KeyInfo.RootFingerprint)
+	require.Equal(t, "m/84'/0'/0'", cfg.AbsoluteKeypath().Encode())
+	require.Equal(t, descriptorXpub1, cfg.ExtendedPublicKey().String())
+
+	_, err = NewConfigurationFromDescriptor(
+		"wpkh([d34db33f/84h/0h/0h]" + descriptorXpub1 + "/0/*)#cjjspncx")
+	require.Error(t, err)
+
+	cfg, err = NewConfigurationFromDescriptor("sh(wpkh([d34db33f/49'/0'/0']" + descriptorXpub1 + "/<0;1>/*))")
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WPKHP2SH, cfg.ScriptType())
+
+	cfg, err = NewConfigurationFromDescriptor("tr([d34db33f/86'/0'/0']" + descriptorXpub1 + "/<0;1>/*)")
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2TR, cfg.ScriptType())
+
+	// Receive and change descriptors.
+	cfg, err = NewConfigurationFromDescriptor(
+		"pkh([d34db33f/44'/0'/0']" + descriptorXpub1 + "/0/*)\npkh([d34db33f/44'/0'/0']" + descriptorXpub1 + "/1/*)")
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2PKH, cfg.ScriptType())
+	_, err = NewConfigurationFromDescriptor(
+		"pkh([d34db33f/44'/0'/0']" + descriptorXpub1 + "/0/*)\npkh([d34db33f/44'/0'/1']" + descriptorXpub1 + "/1/*)")
+	require.Error(t, err)
+
+	cfg, err = NewConfigurationFromDescriptor(
+		"wsh(sortedmulti(2,[d34db33f/48'/0'/0'/2']" + descriptorXpub1 + "/<0;1>/*,[deadbeef/48'/0'/0'/2']" + descriptorXpub2 + "/<0;1>/*))")
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WSH, cfg.ScriptType())
+	require.Equal(t, uint32(2), cfg.BitcoinMultisig.Threshold)
+	require.Len(t, cfg.BitcoinMultisig.Cosigners, 2)
+	require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, cfg.BitcoinMultisig.Cosigners[1].RootFingerprint)
+
+	for _, unsupported := range []string{
+		"wsh(multi(2,[d34db33f/48'/0'/0'/2']" + descriptorXpub1 + "/<0;1>/*,[deadbeef/48'/0'/0'/2']" + descriptorXpub2 + "/<0;1>/*))",
+		"wpkh(" + descriptorXpub1 + "/0/*)",
+		"wpkh([d34db33f/84'/0'/0']" + descriptorXpub1 + "/0/5)",
+		"sh(multi(1,[d34db33f/84'/0'/0']" + descriptorXpub1 + "/0/*))",
+		"addr(bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq)",
+	} {
+		_, err := NewConfigurationFromDescriptor(unsupported)
+		require.Error(t, err, unsupported)
+	}
+}
+
+func TestConfigurationDescriptor(t *testing.T) {
+	for _, descriptor := range []string{
+		"pkh([d34db33f/44'/0'/0']" + descriptorXpub1 + "/0/*)",
+		"sh(wpkh([d34db33f/49'/0'/0']" + descriptorXpub1 + "/0/*))",
+		"wpkh([d34db33f/84'/0'/0']" + descriptorXpub1 + "/0/*)",
+		"tr([d34db33f/86'/0'/0']" + descriptorXpub1 + "/0/*)",
+		"wsh(sortedmulti(1,[d34db33f/48'/0'/0'/2']" + descriptorXpub1 + "/0/*,[deadbeef/48'/0'/0'/2']" + descriptorXpub2 + "/0/*))",
+	} {
+		cfg, err := NewConfigurationFromDescriptor(descriptor)
+		require.NoError(t, err)
+		checksum, err := DescriptorChecksum(descriptor)
+		require.NoError(t, err)
+		receive, err := cfg.Descriptor(0)
+		require.NoError(t, err)
+		require.Equal(t, descriptor+"#"+checksum, receive)
+		change, err := cfg.Descriptor(1)
+		require.NoError(t, err)
+		changeCfg, err := NewConfigurationFromDescriptor(change)
+		require.NoError(t, err)
+		require.Equal(t, cfg.String(), changeCfg.String())
+	}
+
+	xpub, err := hdkeychain.NewKeyFromString(descriptorXpub1)
+	require.NoError(t, err)
+	_, err = NewEthereumConfiguration([]byte{1, 2, 3, 4}, mustKeypath("m/44'/60'/0'/0/0"), xpub).Descriptor(0)
+	require.Error(t, err)
+}
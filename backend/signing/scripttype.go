@@ -29,4 +29,8 @@ const (
 
 	// ScriptTypeP2TR is a BIP-86 segwit v1 PayToTaproot output.
 	ScriptTypeP2TR ScriptType = "p2tr"
+
+	// ScriptTypeP2WSH is a segwit v0 PayToWitnessScriptHash output. It is used for m-of-n
+	// multisig with a sorted (BIP-67) OP_CHECKMULTISIG witness script.
+	ScriptTypeP2WSH ScriptType = "p2wsh"
 )
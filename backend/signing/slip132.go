@@ -0,0 +1,143 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+)
+
+// slip132Version is the version of an extended public key encoding, see SLIP-132.
+type slip132Version struct {
+	// prefix is the prefix of the encoded key, e.g. "zpub".
+	prefix  string
+	version [4]byte
+	// scriptType is the script type implied by the version. It is empty for the versions used for
+	// all script types.
+	scriptType ScriptType
+	testnet    bool
+}
+
+// slip132Versions are the supported extended public key versions. The first version of each
+// network is its standard version, used internally for all keys.
+var slip132Versions = []slip132Version{
+	{prefix: "xpub", version: [4]byte{0x04, 0x88, 0xb2, 0x1e}},
+	{prefix: "ypub", version: [4]byte{0x04, 0x9d, 0x7c, 0xb2}, scriptType: ScriptTypeP2WPKHP2SH},
+	{prefix: "zpub", version: [4]byte{0x04, 0xb2, 0x47, 0x46}, scriptType: ScriptTypeP2WPKH},
+	{prefix: "Zpub", version: [4]byte{0x02, 0xaa, 0x7e, 0xd3}, scriptType: ScriptTypeP2WSH},
+	{prefix: "Ltub", version: [4]byte{0x01, 0x9d, 0xa4, 0x62}, scriptType: ScriptTypeP2PKH},
+	{prefix: "Mtub", version: [4]byte{0x01, 0xb2, 0x6e, 0xf6}, scriptType: ScriptTypeP2WPKHP2SH},
+	{prefix: "tpub", version: [4]byte{0x04, 0x35, 0x87, 0xcf}, testnet: true},
+	{prefix: "upub", version: [4]byte{0x04, 0x4a, 0x52, 0x62}, scriptType: ScriptTypeP2WPKHP2SH, testnet: true},
+	{prefix: "vpub", version: [4]byte{0x04, 0x5f, 0x1c, 0xf6}, scriptType: ScriptTypeP2WPKH, testnet: true},
+	{prefix: "Vpub", version: [4]byte{0x02, 0x57, 0x54, 0x83}, scriptType: ScriptTypeP2WSH, testnet: true},
+}
+
+// standardVersion returns the standard version of the network, xpub or tpub.
+func standardVersion(testnet bool) slip132Version {
+	for _, version := range slip132Versions {
+		if version.testnet == testnet {
+			return version
+		}
+	}
+	panic("missing standard version")
+}
+
+// ParseExtendedPublicKey parses an extended public key in any of the SLIP-132 encodings, e.g.
+// xpub, ypub, zpub, tpub, vpub, Ltub or Mtub. The key is returned with the standard version of its
+// network (xpub or tpub). The script type implied by the version is returned as well. It is empty
+// for xpub and tpub keys, which are used for all script types.
+func ParseExtendedPublicKey(key string) (*hdkeychain.ExtendedKey, ScriptType, error) {
+	extendedPublicKey, err := hdkeychain.NewKeyFromString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, "", errp.Wrap(err, "Could not read an extended public key.")
+	}
+	if extendedPublicKey.IsPrivate() {
+		return nil, "", errp.New("An extended key is private! Only extended public keys are accepted.")
+	}
+	for _, version := range slip132Versions {
+		if !bytes.Equal(version.version[:], extendedPublicKey.Version()) {
+			continue
+		}
+		standard := standardVersion(version.testnet)
+		if version.version == standard.version {
+			return extendedPublicKey, "", nil
+		}
+		extendedPublicKey, err = extendedPublicKey.CloneWithVersion(standard.version[:])
+		if err != nil {
+			return nil, "", errp.WithStack(err)
+		}
+		return extendedPublicKey, version.scriptType, nil
+	}
+	return nil, "", errp.Newf("unknown extended public key version %x", extendedPublicKey.Version())
+}
+
+// EncodeExtendedPublicKey encodes the extended public key using the SLIP-132 version with the
+// given prefix, e.g. "zpub". The version must belong to the network of the key, which can be
+// encoded in any SLIP-132 version.
+func EncodeExtendedPublicKey(extendedPublicKey *hdkeychain.ExtendedKey, prefix string) (string, error) {
+	testnet := false
+	for _, version := range slip132Versions {
+		if bytes.Equal(version.version[:], extendedPublicKey.Version()) {
+			testnet = version.testnet
+		}
+	}
+	for _, version := range slip132Versions {
+		if version.prefix != prefix {
+			continue
+		}
+		if version.testnet != testnet {
+			return "", errp.Newf("%s is not a version of this network", prefix)
+		}
+		encoded, err := extendedPublicKey.CloneWithVersion(version.version[:])
+		if err != nil {
+			return "", errp.WithStack(err)
+		}
+		return encoded.String(), nil
+	}
+	return "", errp.Newf("unknown extended public key version %s", prefix)
+}
+
+// NewKeyInfoFromExtendedPublicKey parses an account-level extended public key in any SLIP-132
+// encoding, see ParseExtendedPublicKey(), optionally with origin info as in
+// NewKeyInfoFromString(). Without origin info, the root fingerprint is set to 00000000 and the
+// keypath is empty, as they are unknown. The script type implied by the version is returned as
+// well.
+func NewKeyInfoFromExtendedPublicKey(key string) (*KeyInfo, ScriptType, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(key, "[") {
+		extendedPublicKey, scriptType, err := ParseExtendedPublicKey(key)
+		if err != nil {
+			return nil, "", err
+		}
+		return &KeyInfo{
+			RootFingerprint:   make([]byte, 4),
+			AbsoluteKeypath:   NewEmptyAbsoluteKeypath(),
+			ExtendedPublicKey: extendedPublicKey,
+		}, scriptType, nil
+	}
+	keyInfo, err := NewKeyInfoFromString(key)
+	if err != nil {
+		return nil, "", err
+	}
+	_, scriptType, err := ParseExtendedPublicKey(key[strings.Index(key, "]")+1:])
+	if err != nil {
+		return nil, "", err
+	}
+	return keyInfo, scriptType, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLIP132(t *testing.T) {
+	const xpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+	extendedPublicKey, scriptType, err := ParseExtendedPublicKey(xpub)
+	require.NoError(t, err)
+	require.Equal(t, ScriptType(""), scriptType)
+	require.Equal(t, xpub, extendedPublicKey.String())
+
+	for prefix, expectedScriptType := range map[string]ScriptType{
+		"ypub": ScriptTypeP2WPKHP2SH,
+		"zpub": ScriptTypeP2WPKH,
+		"Zpub": ScriptTypeP2WSH,
+		"Ltub": ScriptTypeP2PKH,
+		"Mtub": ScriptTypeP2WPKHP2SH,
+	} {
+		encoded, err := EncodeExtendedPublicKey(extendedPublicKey, prefix)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(encoded, prefix), encoded)
+		// Parsing converts the key back to the standard version.
+		parsed, scriptType, err := ParseExtendedPublicKey(encoded)
+		require.NoError(t, err)
+		require.Equal(t, expectedScriptType, scriptType)
+		require.Equal(t, xpub, parsed.String())
+		// The network is determined from any version.
+		_, err = EncodeExtendedPublicKey(parsed, "vpub")
+		require.Error(t, err)
+	}
+
+	tpub, err := EncodeExtendedPublicKey(extendedPublicKey, "tpub")
+	require.Error(t, err, tpub)
+	testnetKey, err := extendedPublicKey.CloneWithVersion([]byte{0x04, 0x35, 0x87, 0xcf})
+	require.NoError(t, err)
+	vpub, err := EncodeExtendedPublicKey(testnetKey, "vpub")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(vpub, "vpub"), vpub)
+	parsed, scriptType, err := ParseExtendedPublicKey(vpub)
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WPKH, scriptType)
+	require.Equal(t, testnetKey.String(), parsed.String())
+
+	_, err = EncodeExtendedPublicKey(extendedPublicKey, "abcd")
+	require.Error(t, err)
+	_, _, err = ParseExtendedPublicKey("zpub")
+	require.Error(t, err)
+}
+
+func TestNewKeyInfoFromExtendedPublicKey(t *testing.T) {
+	const xpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+	extendedPublicKey, _, err := ParseExtendedPublicKey(xpub)
+	require.NoError(t, err)
+	zpub, err := EncodeExtendedPublicKey(extendedPublicKey, "zpub")
+	require.NoError(t, err)
+
+	keyInfo, scriptType, err := NewKeyInfoFromExtendedPublicKey(" " + zpub)
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WPKH, scriptType)
+	require.Equal(t, []byte{0, 0, 0, 0}, keyInfo.RootFingerprint)
+	require.Equal(t, "m/", keyInfo.AbsoluteKeypath.Encode())
+	require.Equal(t, xpub, keyInfo.ExtendedPublicKey.String())
+	descriptor, err := NewBitcoinConfiguration(
+		scriptType, keyInfo.RootFingerprint, keyInfo.AbsoluteKeypath, keyInfo.ExtendedPublicKey,
+	).Descriptor(0)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(descriptor, "wpkh([00000000]"+xpub+"/0/*)#"), descriptor)
+
+	keyInfo, scriptType, err = NewKeyInfoFromExtendedPublicKey("[d34db33f/84'/0'/0']" + zpub)
+	require.NoError(t, err)
+	require.Equal(t, ScriptTypeP2WPKH, scriptType)
+	require.Equal(t, []byte{0xd3, 0x4d, 0xb3, 0x3f}, keyInfo.RootFingerprint)
+	require.Equal(t, "m/84'/0'/0'", keyInfo.AbsoluteKeypath.Encode())
+	require.Equal(t, xpub, keyInfo.ExtendedPublicKey.String())
+
+	keyInfo, scriptType, err = NewKeyInfoFromExtendedPublicKey("[d34db33f/86'/0'/0']" + xpub)
+	require.NoError(t, err)
+	require.Equal(t, ScriptType(""), scriptType)
+	require.Equal(t, xpub, keyInfo.ExtendedPublicKey.String())
+
+	_, _, err = NewKeyInfoFromExtendedPublicKey("[d34db33f/84'/0'/0'" + zpub)
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syncscheduler reduces background syncing while the device runs on battery or uses a
+// metered network, as reported by the frontend.
+package syncscheduler
+
+import (
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
+)
+
+// State is the power and network state of the device.
+type State struct {
+	// OnBattery is true if the device is not charging.
+	OnBattery bool `json:"onBattery"`
+	// Metered is true if the network connection is metered, e.g. mobile data.
+	Metered bool `json:"metered"`
+}
+
+// Scheduler decides how often accounts poll their blockchain backend in the background.
+type Scheduler struct {
+	// config returns the current throttling configuration.
+	config func() config.SyncThrottling
+
+	state State
+	// changed is closed and replaced when the state changes, see Changed().
+	changed chan struct{}
+	lock    locker.Locker
+}
+
+// NewScheduler creates a new scheduler. The state is unthrottled until reported otherwise.
+func NewScheduler(config func() config.SyncThrottling) *Scheduler {
+	return &Scheduler{
+		config:  config,
+		changed: make(chan struct{}),
+	}
+}
+
+// SetState updates the power and network state.
+func (scheduler *Scheduler) SetState(state State) {
+	defer scheduler.lock.Lock()()
+	if state == scheduler.state {
+		return
+	}
+	scheduler.state = state
+	close(scheduler.changed)
+	scheduler.changed = make(chan struct{})
+}
+
+// State returns the current power and network state.
+func (scheduler *Scheduler) State() State {
+	defer scheduler.lock.RLock()()
+	return scheduler.state
+}
+
+// Changed returns a channel which is closed at the next state change, so that pollers can
+// reschedule.
+func (scheduler *Scheduler) Changed() <-chan struct{} {
+	defer scheduler.lock.RLock()()
+	return scheduler.changed
+}
+
+// Interval returns the interval between two background polls, given the interval used when not
+// throttled. 0 is returned if background polling is paused.
+func (scheduler *Scheduler) Interval(interval time.Duration) time.Duration {
+	state := scheduler.State()
+	cfg := scheduler.config()
+	factor := 1
+	if state.Metered {
+		if cfg.PauseOnMetered {
+			return 0
+		}
+		factor = max(factor, cfg.MeteredFactor)
+	}
+	if state.OnBattery {
+		factor = max(factor, cfg.BatteryFactor)
+	}
+	return interval * time.Duration(factor)
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncscheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterval(t *testing.T) {
+	cfg := config.SyncThrottling{BatteryFactor: 2, MeteredFactor: 4}
+	scheduler := NewScheduler(func() config.SyncThrottling { return cfg })
+
+	require.Equal(t, State{}, scheduler.State())
+	require.Equal(t, time.Minute, scheduler.Interval(time.Minute))
+
+	scheduler.SetState(State{OnBattery: true})
+	require.Equal(t, 2*time.Minute, scheduler.Interval(time.Minute))
+
+	// The larger factor applies.
+	scheduler.SetState(State{OnBattery: true, Metered: true})
+	require.Equal(t, 4*time.Minute, scheduler.Interval(time.Minute))
+
+	cfg.PauseOnMetered = true
+	require.Equal(t, time.Duration(0), scheduler.Interval(time.Minute))
+
+	// Factors up to 1 disable throttling.
+	cfg = config.SyncThrottling{BatteryFactor: 0, MeteredFactor: 1}
+	require.Equal(t, time.Minute, scheduler.Interval(time.Minute))
+}
+
+func TestChanged(t *testing.T) {
+	scheduler := NewScheduler(func() config.SyncThrottling { return config.SyncThrottling{} })
+
+	changed := scheduler.Changed()
+	// Setting the same state is not a change.
+	scheduler.SetState(State{})
+	select {
+	case <-changed:
+		require.Fail(t, "unexpected change")
+	default:
+	}
+
+	scheduler.SetState(State{Metered: true})
+	select {
+	case <-changed:
+	default:
+		require.Fail(t, "expected change")
+	}
+	require.Equal(t, State{Metered: true}, scheduler.State())
+
+	// A new channel is returned for the next change.
+	select {
+	case <-scheduler.Changed():
+		require.Fail(t, "unexpected change")
+	default:
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/syncscheduler"
+)
+
+// SetSyncState sets the power and network state of the device as reported by the frontend, which
+// throttles background syncing according to the app config.
+func (backend *Backend) SetSyncState(state syncscheduler.State) {
+	backend.log.WithField("onBattery", state.OnBattery).WithField("metered", state.Metered).
+		Info("sync state changed")
+	backend.syncScheduler.SetState(state)
+}
+
+// SyncState returns the power and network state of the device last reported by the frontend.
+func (backend *Backend) SyncState() syncscheduler.State {
+	return backend.syncScheduler.State()
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/notifications"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
 	"github.com/BitBoxSwiss/bitbox02-api-go/util/semver"
@@ -81,5 +82,10 @@ func (backend *Backend) CheckForUpdateIgnoringErrors() *UpdateFile {
 		logging.Get().WithGroup("update").WithError(err).Warn("Check for update failed.")
 		return nil
 	}
+	if updateFile != nil {
+		backend.addNotification(notifications.TypeUpdateAvailable, map[string]string{
+			"version": updateFile.NewVersion.String(),
+		})
+	}
 	return updateFile
 }
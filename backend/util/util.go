@@ -27,14 +27,15 @@ import (
 
 // APIGet performs a HTTP Get call to a given endpoint and unmarshal the result populating a given object.
 // Input params:
+// - `ctx` which can cancel the call, which times out after 30 seconds in any case
 // - `httpClient` which is used to perform the call
 // - `endpoint` the url for the endpoint to fetch
 // - `apikey` if not empty it is used as X-API-KEY header value
 // - `maxSize` indicates the max expected response size. If it exceeds the function returns an error
 // - `result` object that should be used to unmarshal the response body
 // Returns the error code (if available) and possibly an error.
-func APIGet(httpClient *http.Client, endpoint string, apiKey string, maxSize int64, result interface{}) (int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func APIGet(ctx context.Context, httpClient *http.Client, endpoint string, apiKey string, maxSize int64, result interface{}) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
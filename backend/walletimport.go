@@ -0,0 +1,265 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/notes"
+	accountsTypes "github.com/BitBoxSwiss/bitbox-wallet-app/backend/accounts/types"
+	coinpkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend/coins/coin"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/signing"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/util"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// WalletFileType is the software a wallet file was made by.
+type WalletFileType string
+
+const (
+	// WalletFileTypeElectrum is a wallet file of Electrum.
+	WalletFileTypeElectrum WalletFileType = "electrum"
+	// WalletFileTypeWasabi is the wallet JSON file of Wasabi Wallet.
+	WalletFileTypeWasabi WalletFileType = "wasabi"
+)
+
+// walletFile is the account metadata read from a wallet file of other wallet software.
+type walletFile struct {
+	typ                   WalletFileType
+	signingConfigurations signing.Configurations
+	// txLabels maps transaction IDs to their labels.
+	txLabels map[string]string
+	// ignoredLabels is the number of labels of other things than transactions, e.g. addresses,
+	// which can't be imported.
+	ignoredLabels int
+}
+
+// isTxID returns true if the string looks like a transaction ID rather than an address.
+func isTxID(s string) bool {
+	decoded, err := hex.DecodeString(s)
+	return err == nil && len(decoded) == 32
+}
+
+// newWalletFileConfiguration makes the signing configuration of an account-level extended public
+// key found in a wallet file. rootFingerprint and keypath are the origin of the key, e.g. "d34db33f"
+// and "84'/0'/0'", and can be empty if unknown. If the script type is not implied by the version
+// of the key, defaultScriptType is used.
+func newWalletFileConfiguration(
+	extendedPublicKey string,
+	rootFingerprint string,
+	keypath string,
+	defaultScriptType signing.ScriptType,
+) (*signing.Configuration, error) {
+	key := extendedPublicKey
+	if rootFingerprint != "" {
+		key = fmt.Sprintf("[%s/%s]%s",
+			rootFingerprint, strings.TrimPrefix(strings.TrimPrefix(keypath, "m"), "/"), extendedPublicKey)
+	}
+	keyInfo, scriptType, err := signing.NewKeyInfoFromExtendedPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if scriptType == "" {
+		scriptType = defaultScriptType
+	}
+	return signing.NewBitcoinConfiguration(
+		scriptType, keyInfo.RootFingerprint, keyInfo.AbsoluteKeypath, keyInfo.ExtendedPublicKey), nil
+}
+
+// parseElectrumWalletFile reads a standard, i.e. single-sig, Electrum wallet file. Electrum tells
+// the script type by the version of the xpub. The labels are keyed by transaction ID or address.
+func parseElectrumWalletFile(contents []byte) (*walletFile, error) {
+	var electrumFile struct {
+		WalletType string `json:"wallet_type"`
+		Keystore   *struct {
+			XPub            string `json:"xpub"`
+			Derivation      string `json:"derivation"`
+			RootFingerprint string `json:"root_fingerprint"`
+		} `json:"keystore"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal(contents, &electrumFile); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	if electrumFile.WalletType != "standard" || electrumFile.Keystore == nil {
+		return nil, errp.Newf("unsupported Electrum wallet type %q", electrumFile.WalletType)
+	}
+	if electrumFile.Keystore.XPub == "" {
+		return nil, errp.New("the Electrum wallet has no extended public key")
+	}
+	signingConfiguration, err := newWalletFileConfiguration(
+		electrumFile.Keystore.XPub,
+		electrumFile.Keystore.RootFingerprint,
+		electrumFile.Keystore.Derivation,
+		// Electrum uses xpub/tpub for legacy wallets.
+		signing.ScriptTypeP2PKH,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &walletFile{
+		typ:                   WalletFileTypeElectrum,
+		signingConfigurations: signing.Configurations{signingConfiguration},
+		txLabels:              map[string]string{},
+	}
+	for ref, label := range electrumFile.Labels {
+		if isTxID(ref) {
+			result.txLabels[ref] = label
+		} else {
+			result.ignoredLabels++
+		}
+	}
+	return result, nil
+}
+
+// parseWasabiWalletFile reads the wallet JSON file of Wasabi Wallet, which has a native segwit
+// account and, since Wasabi 2.0, a Taproot account. Wasabi labels addresses, not transactions.
+func parseWasabiWalletFile(contents []byte) (*walletFile, error) {
+	var wasabiFile struct {
+		MasterFingerprint     string `json:"MasterFingerprint"`
+		ExtPubKey             string `json:"ExtPubKey"`
+		AccountKeyPath        string `json:"AccountKeyPath"`
+		TaprootExtPubKey      string `json:"TaprootExtPubKey"`
+		TaprootAccountKeyPath string `json:"TaprootAccountKeyPath"`
+		HdPubKeys             []struct {
+			Label string `json:"Label"`
+		} `json:"HdPubKeys"`
+	}
+	if err := json.Unmarshal(contents, &wasabiFile); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	signingConfiguration, err := newWalletFileConfiguration(
+		wasabiFile.ExtPubKey,
+		wasabiFile.MasterFingerprint,
+		wasabiFile.AccountKeyPath,
+		signing.ScriptTypeP2WPKH,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &walletFile{
+		typ:                   WalletFileTypeWasabi,
+		signingConfigurations: signing.Configurations{signingConfiguration},
+		txLabels:              map[string]string{},
+	}
+	if wasabiFile.TaprootExtPubKey != "" {
+		taprootConfiguration, err := newWalletFileConfiguration(
+			wasabiFile.TaprootExtPubKey,
+			wasabiFile.MasterFingerprint,
+			wasabiFile.TaprootAccountKeyPath,
+			signing.ScriptTypeP2TR,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result.signingConfigurations = append(result.signingConfigurations, taprootConfiguration)
+	}
+	for _, hdPubKey := range wasabiFile.HdPubKeys {
+		if strings.TrimSpace(hdPubKey.Label) != "" {
+			result.ignoredLabels++
+		}
+	}
+	return result, nil
+}
+
+// parseWalletFile reads an Electrum wallet file or a Wasabi wallet JSON file.
+func parseWalletFile(contents []byte) (*walletFile, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &fields); err != nil {
+		// Electrum wallet files encrypted with a password are not JSON.
+		return nil, errp.New("unknown wallet file format. Encrypted wallet files are not supported.")
+	}
+	switch {
+	case fields["wallet_type"] != nil:
+		return parseElectrumWalletFile(contents)
+	case fields["ExtPubKey"] != nil:
+		return parseWasabiWalletFile(contents)
+	default:
+		return nil, errp.New("unknown wallet file format")
+	}
+}
+
+// ImportWalletFileResult is the result of adding an account from a wallet file.
+type ImportWalletFileResult struct {
+	AccountCode accountsTypes.Code `json:"accountCode"`
+	Type        WalletFileType     `json:"type"`
+	// TransactionCount is the number of transaction labels imported as transaction notes.
+	TransactionCount int `json:"transactionCount"`
+	// IgnoredLabelCount is the number of labels which could not be imported, e.g. address labels.
+	IgnoredLabelCount int `json:"ignoredLabelCount"`
+}
+
+// CreateAndPersistWalletFileAccountConfig adds a watch-only BTC/LTC account from a wallet file of
+// other wallet software, to ease migrating to the BitBoxApp. Supported are standard (single-sig)
+// Electrum wallet files and Wasabi Wallet JSON files. The transaction labels of the file are
+// imported as transaction notes of the account.
+//
+// `name` is the account name, shown to the user. If empty, a default name will be set.
+func (backend *Backend) CreateAndPersistWalletFileAccountConfig(
+	coinCode coinpkg.Code,
+	name string,
+	contents []byte,
+) (*ImportWalletFileResult, error) {
+	switch coinCode {
+	case coinpkg.CodeBTC, coinpkg.CodeTBTC, coinpkg.CodeTSIG, coinpkg.CodeRBTC, coinpkg.CodeLTC, coinpkg.CodeTLTC:
+	default:
+		return nil, errp.Newf("wallet files are not supported for %s", coinCode)
+	}
+	coin, err := backend.Coin(coinCode)
+	if err != nil {
+		return nil, err
+	}
+	walletFile, err := parseWalletFile(contents)
+	if err != nil {
+		return nil, err
+	}
+	accountCode, err := backend.persistWatchonlyAccountConfig(coin, name, walletFile.signingConfigurations)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportWalletFileResult{
+		AccountCode:       accountCode,
+		Type:              walletFile.typ,
+		IgnoredLabelCount: walletFile.ignoredLabels,
+	}
+	if len(walletFile.txLabels) == 0 {
+		return result, nil
+	}
+	account := backend.Accounts().lookup(accountCode)
+	if account == nil {
+		return nil, errp.Newf("could not find the added account %s", accountCode)
+	}
+	// So `account.Notes()` is ready to use.
+	if err := account.Initialize(); err != nil {
+		return nil, err
+	}
+	for txID, label := range walletFile.txLabels {
+		label = util.TruncateString(strings.TrimSpace(label), notes.MaxNoteLen)
+		if label == "" {
+			continue
+		}
+		changed, err := account.Notes().SetTxNote(txID, label)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			result.TransactionCount++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// bitbox-wallet-cli talks to the API of a running bitbox-walletd, e.g. to check balances and
+// broadcast transactions from scripts.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/cmd/internal/walletd"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+const usage = `Usage: bitbox-wallet-cli [flags] <command> [arguments]
+
+Commands:
+  version                                     print the app version
+  accounts                                    list the accounts
+  balance <account-code>                      print the balance of an account
+  send <account-code> <address> <amount|all> [fee-target]
+                                              create, sign and broadcast a transaction. The
+                                              transaction has to be confirmed on the device.
+  send-psbt <account-code> <psbt>             broadcast a signed PSBT (base64)
+
+Flags:
+`
+
+// client calls the API of a running daemon.
+type client struct {
+	baseURL string
+	// authorization is the value of the Authorization header, the session token after logging in.
+	authorization string
+	httpClient    *http.Client
+}
+
+func (c *client) call(method, path string, body interface{}, result interface{}) error {
+	var requestBody io.Reader
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
+		if err != nil {
+			return errp.WithStack(err)
+		}
+		requestBody = bytes.NewReader(jsonBytes)
+	}
+	request, err := http.NewRequest(method, c.baseURL+path, requestBody)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	request.Header.Set("Authorization", c.authorization)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	var apiError struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"errorCode"`
+	}
+	if json.Unmarshal(responseBody, &apiError) == nil && apiError.Error != "" {
+		return errp.Newf("%s %s: %s (%s)", method, path, apiError.Error, apiError.ErrorCode)
+	}
+	if response.StatusCode != http.StatusOK {
+		return errp.Newf("%s %s: %s: %s", method, path, response.Status, strings.TrimSpace(string(responseBody)))
+	}
+	return errp.WithStack(json.Unmarshal(responseBody, result))
+}
+
+// checkSuccess returns an error for the `{success: false, ...}` responses of the API.
+func checkSuccess(response map[string]interface{}) error {
+	if success, ok := response["success"].(bool); !ok || success {
+		return nil
+	}
+	if aborted, _ := response["aborted"].(bool); aborted {
+		return errp.New("aborted on the device")
+	}
+	for _, key := range []string{"errorMessage", "errorCode"} {
+		if message, ok := response[key].(string); ok && message != "" {
+			return errp.New(message)
+		}
+	}
+	return errp.New("request failed")
+}
+
+type amount struct {
+	Amount string `json:"amount"`
+	Unit   string `json:"unit"`
+}
+
+func (a amount) String() string {
+	return a.Amount + " " + a.Unit
+}
+
+// login exchanges the API token for a session token, used for the following requests.
+func (c *client) login(apiToken string) error {
+	c.authorization = "Basic " + apiToken
+	var session struct {
+		Token string `json:"token"`
+	}
+	if err := c.call("POST", "session/login", nil, &session); err != nil {
+		return err
+	}
+	c.authorization = "Bearer " + session.Token
+	return nil
+}
+
+// logout revokes the session token.
+func (c *client) logout() error {
+	var result map[string]interface{}
+	return c.call("POST", "session/logout", nil, &result)
+}
+
+func (c *client) version() error {
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := c.call("GET", "version", nil, &version); err != nil {
+		return err
+	}
+	fmt.Println(version.Version)
+	return nil
+}
+
+func (c *client) accounts() error {
+	var accounts []struct {
+		Code     string `json:"code"`
+		Name     string `json:"name"`
+		CoinCode string `json:"coinCode"`
+		Active   bool   `json:"active"`
+	}
+	if err := c.call("GET", "accounts", nil, &accounts); err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if !account.Active {
+			continue
+		}
+		fmt.Printf("%s\t%s\t%s\n", account.Code, account.CoinCode, account.Name)
+	}
+	return nil
+}
+
+func (c *client) balance(accountCode string) error {
+	var balance struct {
+		Available amount `json:"available"`
+		Incoming  amount `json:"incoming"`
+	}
+	if err := c.call("GET", "account/"+accountCode+"/balance", nil, &balance); err != nil {
+		return err
+	}
+	fmt.Printf("available: %s\nincoming: %s\n", balance.Available, balance.Incoming)
+	return nil
+}
+
+func (c *client) send(accountCode, address, sendAmount, feeTarget string) error {
+	request := map[string]interface{}{
+		"address":   address,
+		"feeTarget": feeTarget,
+	}
+	if sendAmount == "all" {
+		request["sendAll"] = "yes"
+	} else {
+		request["amount"] = sendAmount
+	}
+	var proposal map[string]interface{}
+	if err := c.call("POST", "account/"+accountCode+"/tx-proposal", request, &proposal); err != nil {
+		return err
+	}
+	if err := checkSuccess(proposal); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Confirm the transaction on the device.")
+	var result map[string]interface{}
+	if err := c.call("POST", "account/"+accountCode+"/sendtx", "", &result); err != nil {
+		return err
+	}
+	if err := checkSuccess(result); err != nil {
+		return err
+	}
+	fmt.Println("sent")
+	return nil
+}
+
+func (c *client) sendPSBT(accountCode, psbt string) error {
+	var result map[string]interface{}
+	if err := c.call("POST", "account/"+accountCode+"/psbt-send",
+		map[string]string{"psbt": psbt}, &result); err != nil {
+		return err
+	}
+	if err := checkSuccess(result); err != nil {
+		return err
+	}
+	fmt.Println(result["txId"])
+	return nil
+}
+
+// errUsage is returned by run if the command or its arguments are invalid.
+var errUsage = errors.New("invalid command")
+
+func run(c *client, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "version":
+		return c.version()
+	case len(args) == 1 && args[0] == "accounts":
+		return c.accounts()
+	case len(args) == 2 && args[0] == "balance":
+		return c.balance(args[1])
+	case len(args) == 4 && args[0] == "send":
+		return c.send(args[1], args[2], args[3], "normal")
+	case len(args) == 5 && args[0] == "send":
+		return c.send(args[1], args[2], args[3], args[4])
+	case len(args) == 3 && args[0] == "send-psbt":
+		return c.sendPSBT(args[1], args[2])
+	default:
+		return errUsage
+	}
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	appDir := flag.String("appdir", "", "app folder of bitbox-walletd, defaults to the folder of the BitBoxApp")
+	flag.Parse()
+
+	if *appDir != "" {
+		config.SetAppDir(*appDir)
+	}
+	info, err := walletd.ReadConnectionInfo(config.AppDir())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	c := &client{
+		// The host is ignored as the connection is made by the dialer below.
+		baseURL: "http://walletd/api/v1/",
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return walletd.Dial(ctx, info.Address)
+				},
+			},
+			// Sending waits for the confirmation on the device.
+			Timeout: 10 * time.Minute,
+		},
+	}
+	if err := c.login(info.Token); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = run(c, flag.Args())
+	if logoutErr := c.logout(); logoutErr != nil {
+		fmt.Fprintln(os.Stderr, logoutErr)
+	}
+	if errors.Is(err, errUsage) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// bitbox-walletd runs the wallet backend and its API without a GUI. By default, the API is served
+// over a Unix domain socket in the app folder, or a named pipe on Windows, accessible by the
+// current user only. The API token and address are stored in walletd.json in the app folder, where
+// bitbox-wallet-cli picks them up.
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	backendPkg "github.com/BitBoxSwiss/bitbox-wallet-app/backend"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/arguments"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/backend/devices/usb"
+	backendHandlers "github.com/BitBoxSwiss/bitbox-wallet-app/backend/handlers"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/cmd/internal/walletd"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/config"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/random"
+	"github.com/sirupsen/logrus"
+)
+
+var backend *backendPkg.Backend
+
+// headlessEnvironment implements backend.Environment.
+type headlessEnvironment struct {
+}
+
+// NotifyUser implements backend.Environment.
+func (headlessEnvironment) NotifyUser(text string) {
+	logging.Get().WithGroup("walletd").Infof("NotifyUser: %s", text)
+}
+
+// DeviceInfos implements backend.Environment.
+func (headlessEnvironment) DeviceInfos() []usb.DeviceInfo {
+	return usb.DeviceInfos()
+}
+
+// SystemOpen implements backend.Environment.
+func (headlessEnvironment) SystemOpen(url string) error {
+	logging.Get().WithGroup("walletd").Infof("Open: %s", url)
+	return nil
+}
+
+// UsingMobileData implements backend.Environment.
+func (headlessEnvironment) UsingMobileData() bool {
+	return false
+}
+
+// Auth implements backend.Environment. There is no user interface to authenticate with, so the
+// access to the API is protected by the API token only.
+func (headlessEnvironment) Auth() {
+	if backend != nil {
+		backend.AuthResult(true)
+	}
+}
+
+// OnAuthSettingChanged implements backend.Environment.
+func (headlessEnvironment) OnAuthSettingChanged(enabled bool) {
+}
+
+// BluetoothConnect implements backend.Environment.
+func (headlessEnvironment) BluetoothConnect(identifier string) {
+}
+
+// NativeLocale implements backend.Environment.
+func (headlessEnvironment) NativeLocale() string {
+	v := os.Getenv("LC_ALL")
+	if v == "" {
+		v = os.Getenv("LANG")
+	}
+	return strings.Split(v, ".")[0]
+}
+
+// GetSaveFilename implements backend.Environment.
+func (headlessEnvironment) GetSaveFilename(suggestedFilename string) string {
+	return suggestedFilename
+}
+
+// SetDarkTheme implements backend.Environment.
+func (headlessEnvironment) SetDarkTheme(isDark bool) {
+}
+
+// DetectDarkTheme implements backend.Environment.
+func (headlessEnvironment) DetectDarkTheme() bool {
+	return false
+}
+
+func main() {
+	listen := flag.String("listen", "",
+		"address to serve the API at: a TCP host:port, unix:<socket path> or pipe:<Windows pipe name>. "+
+			"Defaults to a Unix domain socket in the app folder, or a named pipe on Windows.")
+	appDir := flag.String("appdir", "", "app folder, defaults to the folder of the BitBoxApp")
+	testnet := flag.Bool("testnet", false, "use testnet instead of mainnet coins")
+	regtest := flag.Bool("regtest", false, "use regtest instead of mainnet coins")
+	regtestElectrum := flag.String("regtestElectrum", "",
+		"comma separated plain TCP Electrum servers (host:port) for regtest, e.g. a local electrs at 127.0.0.1:60401")
+	flag.Parse()
+
+	if *appDir != "" {
+		config.SetAppDir(*appDir)
+	}
+	logging.Set(&logging.Configuration{Output: "STDERR", Level: logrus.InfoLevel})
+	log := logging.Get().WithGroup("walletd")
+	log.Info("--------------- Started daemon --------------")
+
+	if *listen == "" {
+		*listen = walletd.DefaultAddress(config.AppDir())
+	}
+	if host, _, err := net.SplitHostPort(*listen); err == nil {
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			log.WithField("address", *listen).Warn("Serving the API on a non-loopback address")
+		}
+	}
+
+	token := hex.EncodeToString(random.BytesOrPanic(32))
+	backendArguments := arguments.NewArguments(
+		config.AppDir(),
+		*testnet || *regtest,
+		*regtest,
+		false,
+		nil,
+	)
+	if *regtestElectrum != "" {
+		if !*regtest {
+			log.Fatal("-regtestElectrum requires -regtest")
+		}
+		backendArguments.SetRegtestElectrumServers(strings.Split(*regtestElectrum, ","))
+	}
+	newBackend, err := backendPkg.NewBackend(backendArguments, headlessEnvironment{})
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create the backend")
+	}
+	backend = newBackend
+	// The port is only used to tell dev mode apart, which is disabled by a non-empty token.
+	handlers := backendHandlers.NewHandlers(backend, backendHandlers.NewConnectionData(0, token))
+
+	listener, err := walletd.Listen(*listen)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to listen")
+	}
+	address := walletd.ListenerAddress(*listen, listener)
+	if err := walletd.WriteConnectionInfo(config.AppDir(), &walletd.ConnectionInfo{
+		Address: address,
+		Token:   token,
+	}); err != nil {
+		log.WithError(err).Fatal("Failed to store the connection info")
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-quit
+		log.Info("Shutting down")
+		if err := walletd.RemoveConnectionInfo(config.AppDir()); err != nil {
+			log.WithError(err).Error("Failed to remove the connection info")
+		}
+		if err := walletd.CloseListener(*listen, listener); err != nil {
+			log.WithError(err).Error("Failed to close the listener")
+		}
+		if err := backend.Close(); err != nil {
+			log.WithError(err).Error("backend.Close failed")
+		}
+		os.Exit(0)
+	}()
+
+	log.WithField("address", address).Info("Serving the API")
+	if err := http.Serve(listener, handlers.Router); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.WithError(err).Fatal("Failed to serve the API")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package walletd contains what is shared between the headless wallet daemon and its CLI client.
+package walletd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// connectionFilename is the name of the file in the app folder in which the daemon stores how to
+// connect to its API.
+const connectionFilename = "walletd.json"
+
+// ConnectionInfo describes how to connect to the API of a running daemon.
+type ConnectionInfo struct {
+	// Address is the host:port the API is served at.
+	Address string `json:"address"`
+	// Token is the API token expected in the Authorization header.
+	Token string `json:"token"`
+}
+
+// WriteConnectionInfo stores the connection info in the given app folder, readable only by the
+// current user.
+func WriteConnectionInfo(appDir string, info *ConnectionInfo) error {
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	filename := filepath.Join(appDir, connectionFilename)
+	// Remove a stale file first, so the permissions of the new file apply.
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return errp.WithStack(err)
+	}
+	return errp.WithStack(os.WriteFile(filename, jsonBytes, 0600))
+}
+
+// RemoveConnectionInfo removes the connection info from the given app folder.
+func RemoveConnectionInfo(appDir string) error {
+	err := os.Remove(filepath.Join(appDir, connectionFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return errp.WithStack(err)
+	}
+	return nil
+}
+
+// ReadConnectionInfo loads the connection info of the daemon running with the given app folder.
+func ReadConnectionInfo(appDir string) (*ConnectionInfo, error) {
+	jsonBytes, err := os.ReadFile(filepath.Join(appDir, connectionFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errp.Newf("%s not found in %s, is bitbox-walletd running?", connectionFilename, appDir)
+		}
+		return nil, errp.WithStack(err)
+	}
+	var info ConnectionInfo
+	if err := json.Unmarshal(jsonBytes, &info); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return &info, nil
+}
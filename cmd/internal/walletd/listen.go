@@ -0,0 +1,96 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walletd
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// Addresses starting with these prefixes refer to a Unix domain socket path or a Windows named
+// pipe, e.g. `unix:/home/user/.config/bitbox/walletd.sock` or `pipe:\\.\pipe\bitbox-walletd`. All
+// other addresses are TCP host:port addresses.
+const (
+	unixPrefix = "unix:"
+	pipePrefix = "pipe:"
+)
+
+// Listen listens at the given address, see unixPrefix and pipePrefix. Unix domain sockets and
+// named pipes are accessible by the current user only.
+func Listen(address string) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(address, unixPrefix):
+		path := strings.TrimPrefix(address, unixPrefix)
+		// Remove the socket of a previous run which was not shut down cleanly. Anything else at the
+		// path is left alone, in case the address was mistyped.
+		if fi, err := os.Lstat(path); err == nil {
+			if fi.Mode()&os.ModeSocket == 0 {
+				return nil, errp.Newf("%s exists and is not a socket", path)
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, errp.WithStack(err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, errp.WithStack(err)
+		}
+		return listenUnix(path)
+	case strings.HasPrefix(address, pipePrefix):
+		return listenPipe(strings.TrimPrefix(address, pipePrefix))
+	default:
+		listener, err := net.Listen("tcp", address)
+		return listener, errp.WithStack(err)
+	}
+}
+
+// Dial connects to the given address, see unixPrefix and pipePrefix.
+func Dial(ctx context.Context, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	switch {
+	case strings.HasPrefix(address, unixPrefix):
+		conn, err := dialer.DialContext(ctx, "unix", strings.TrimPrefix(address, unixPrefix))
+		return conn, errp.WithStack(err)
+	case strings.HasPrefix(address, pipePrefix):
+		return dialPipe(ctx, strings.TrimPrefix(address, pipePrefix))
+	default:
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		return conn, errp.WithStack(err)
+	}
+}
+
+// ListenerAddress returns the address to connect to the listener returned by Listen(address).
+func ListenerAddress(address string, listener net.Listener) string {
+	if strings.HasPrefix(address, unixPrefix) || strings.HasPrefix(address, pipePrefix) {
+		return address
+	}
+	return listener.Addr().String()
+}
+
+// CloseListener closes the listener and removes the Unix domain socket, if any.
+func CloseListener(address string, listener net.Listener) error {
+	if err := listener.Close(); err != nil {
+		return errp.WithStack(err)
+	}
+	if strings.HasPrefix(address, unixPrefix) {
+		err := os.Remove(strings.TrimPrefix(address, unixPrefix))
+		if err != nil && !os.IsNotExist(err) {
+			return errp.WithStack(err)
+		}
+	}
+	return nil
+}
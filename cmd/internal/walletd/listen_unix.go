@@ -0,0 +1,49 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package walletd
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"syscall"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// DefaultAddress returns the address the daemon listens at by default, a Unix domain socket in the
+// app folder.
+func DefaultAddress(appDir string) string {
+	return unixPrefix + filepath.Join(appDir, "walletd.sock")
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	// Create the socket accessible by the current user only, without a window in which others
+	// could connect before changing its permissions.
+	oldMask := syscall.Umask(0177)
+	defer syscall.Umask(oldMask)
+	listener, err := net.Listen("unix", path)
+	return listener, errp.WithStack(err)
+}
+
+func listenPipe(name string) (net.Listener, error) {
+	return nil, errp.New("named pipes are only supported on Windows")
+}
+
+func dialPipe(ctx context.Context, name string) (net.Conn, error) {
+	return nil, errp.New("named pipes are only supported on Windows")
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package walletd
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnix(t *testing.T) {
+	address := DefaultAddress(t.TempDir())
+	path := address[len(unixPrefix):]
+	// A stale socket is replaced.
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+	_, err = os.Lstat(path)
+	require.NoError(t, err)
+
+	listener, err := Listen(address)
+	require.NoError(t, err)
+	require.Equal(t, address, ListenerAddress(address, listener))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	accepted := make(chan []byte)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 5)
+		_, _ = conn.Read(buf)
+		accepted <- buf
+	}()
+	conn, err := Dial(context.Background(), address)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), <-accepted)
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, CloseListener(address, listener))
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+	require.Equal(t, "walletd.sock", filepath.Base(path))
+}
+
+func TestListenUnixNotASocket(t *testing.T) {
+	address := DefaultAddress(t.TempDir())
+	path := address[len(unixPrefix):]
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+
+	_, err := Listen(address)
+	require.Error(t, err)
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), contents)
+}
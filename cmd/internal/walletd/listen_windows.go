@@ -0,0 +1,48 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walletd
+
+import (
+	"context"
+	"net"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/Microsoft/go-winio"
+)
+
+// ownerOnlySecurityDescriptor grants access to the owner of the named pipe only.
+const ownerOnlySecurityDescriptor = "D:P(A;;GA;;;OW)"
+
+// DefaultAddress returns the address the daemon listens at by default, a named pipe.
+func DefaultAddress(appDir string) string {
+	return pipePrefix + `\\.\pipe\bitbox-walletd`
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	return listener, errp.WithStack(err)
+}
+
+func listenPipe(name string) (net.Listener, error) {
+	listener, err := winio.ListenPipe(name, &winio.PipeConfig{
+		SecurityDescriptor: ownerOnlySecurityDescriptor,
+	})
+	return listener, errp.WithStack(err)
+}
+
+func dialPipe(ctx context.Context, name string) (net.Conn, error) {
+	conn, err := winio.DialPipeContext(ctx, name)
+	return conn, errp.WithStack(err)
+}
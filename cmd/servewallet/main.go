@@ -135,6 +135,8 @@ func main() {
 
 	mainnet := flag.Bool("mainnet", false, "switch to mainnet instead of testnet coins")
 	regtest := flag.Bool("regtest", false, "use regtest instead of testnet coins")
+	regtestElectrum := flag.String("regtestElectrum", "",
+		"comma separated plain TCP Electrum servers (host:port) for regtest, e.g. a local electrs at 127.0.0.1:60401")
 	devservers := flag.Bool("devservers", true, "switch to dev servers")
 	gapLimitsReceive := flag.Uint("gapLimitReceive", 0, "gap limit for receive addresses")
 	gapLimitsChange := flag.Uint("gapLimitChange", 0, "gap limit for change addresses")
@@ -161,15 +163,20 @@ func main() {
 	log.Info("--------------- Started application --------------")
 	// since we are in dev-mode, we can drop the authorization token
 	connectionData := backendHandlers.NewConnectionData(-1, "")
-	newBackend, err := backendPkg.NewBackend(
-		arguments.NewArguments(
-			config.AppDir(),
-			!*mainnet,
-			*regtest,
-			*devservers,
-			gapLimits,
-		),
-		webdevEnvironment{})
+	backendArguments := arguments.NewArguments(
+		config.AppDir(),
+		!*mainnet,
+		*regtest,
+		*devservers,
+		gapLimits,
+	)
+	if *regtestElectrum != "" {
+		if !*regtest {
+			log.Fatal("-regtestElectrum requires -regtest")
+		}
+		backendArguments.SetRegtestElectrumServers(strings.Split(*regtestElectrum, ","))
+	}
+	newBackend, err := backendPkg.NewBackend(backendArguments, webdevEnvironment{})
 	if err != nil {
 		log.WithField("error", err).Panic(err)
 	}
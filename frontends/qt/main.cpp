@@ -301,7 +301,7 @@ int main(int argc, char *argv[])
     }
 
     BitBoxApp a(argc, argv);
-    // The URI scheme handler for aopp is handled via OS events on macOS. The other platforms invoke
+    // The URI scheme handlers for aopp, bitcoin and litecoin are handled via OS events on macOS. The other platforms invoke
     // the process with the uri as a command line param.
 #if defined(Q_OS_MACOS)
     UrlHandler url_handler;
@@ -23,10 +23,12 @@ UrlHandler::UrlHandler(QObject *parent) : QObject(parent) {
 
 void UrlHandler::setup() {
     // This is only supported on macOS and is used to handle URIs that are opened with
-    // the BitBoxApp using "aopp:..." links. The event is received and handled both
-    // if the BitBoxApp is launched and also when it is already running, in which case
-    // it is brought to the foreground automatically.
+    // the BitBoxApp using "aopp:...", "bitcoin:..." and "litecoin:..." links. The event is
+    // received and handled both if the BitBoxApp is launched and also when it is already
+    // running, in which case it is brought to the foreground automatically.
     QDesktopServices::setUrlHandler("aopp", this, "handleUrlSlot");
+    QDesktopServices::setUrlHandler("bitcoin", this, "handleUrlSlot");
+    QDesktopServices::setUrlHandler("litecoin", this, "handleUrlSlot");
 }
 
 void UrlHandler::handleUrlSlot(const QUrl &url) {
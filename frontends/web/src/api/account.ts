@@ -17,18 +17,19 @@
 import type { LineData } from 'lightweight-charts';
 import { apiGet, apiPost } from '@/utils/request';
 import type { TDetailStatus } from './bitsurance';
+import type { TElectrumStatus } from './coins';
 import type { SuccessResponse } from './response';
 import { Slip24 } from 'request-address';
 
-export type NativeCoinCode = 'btc' | 'tbtc' | 'rbtc' | 'ltc' | 'tltc' | 'eth' | 'sepeth';
+export type NativeCoinCode = 'btc' | 'tbtc' | 'tsig' | 'rbtc' | 'ltc' | 'tltc' | 'eth' | 'sepeth';
 
 export type AccountCode = string;
 
-export type Fiat = 'AUD' | 'BRL' | 'BTC' | 'CAD' | 'CHF' | 'CNY' | 'CZK' | 'EUR' | 'GBP' | 'HKD' | 'ILS' | 'JPY' | 'KRW' | 'NOK' | 'PLN' | 'RUB' | 'sat' | 'SEK' | 'SGD' | 'USD';
+export type Fiat = 'AUD' | 'BRL' | 'BTC' | 'CAD' | 'CHF' | 'CNY' | 'CZK' | 'DKK' | 'EUR' | 'GBP' | 'HKD' | 'HUF' | 'ILS' | 'INR' | 'JPY' | 'KRW' | 'MXN' | 'NOK' | 'NZD' | 'PHP' | 'PLN' | 'RUB' | 'sat' | 'SEK' | 'SGD' | 'THB' | 'TRY' | 'TWD' | 'USD' | 'ZAR';
 
 export type ConversionUnit = Fiat | 'sat'
 
-export type CoinUnit = 'BTC' | 'sat' | 'LTC' | 'ETH' | 'TBTC' | 'tsat' | 'TLTC' | 'SEPETH';
+export type CoinUnit = 'BTC' | 'sat' | 'LTC' | 'ETH' | 'TBTC' | 'TSIG' | 'RBTC' | 'tsat' | 'TLTC' | 'SEPETH';
 
 export type ERC20TokenUnit = 'USDT' | 'USDC' | 'LINK' | 'BAT' | 'MKR' | 'ZRX' | 'WBTC' | 'PAXG' | 'DAI';
 
@@ -75,8 +76,8 @@ export interface IAccount {
   bitsuranceStatus?: TDetailStatus;
 }
 
-export const getAccounts = (): Promise<IAccount[]> => {
-  return apiGet('accounts');
+export const getAccounts = (archived?: boolean): Promise<IAccount[]> => {
+  return apiGet(archived === undefined ? 'accounts' : `accounts?archived=${archived}`);
 };
 
 export type TAccountsBalanceByCoin = {
@@ -113,6 +114,43 @@ export const getAccountsTotalBalance = (): Promise<TAccountsTotalBalanceResponse
   return apiGet('accounts/total-balance');
 };
 
+export type TPortfolioCoin = {
+  coinCode: CoinCode;
+  coinName: string;
+  amount: string;
+  unit: string;
+  fiatValue: string;
+};
+
+export type TPortfolioAccount = {
+  code: AccountCode;
+  name: string;
+  coinCode: CoinCode;
+  amount: string;
+  unit: string;
+  fiatValue: string;
+};
+
+export type TPortfolio = {
+  fiatUnit: Fiat;
+  total: string;
+  coins: TPortfolioCoin[];
+  accounts: TPortfolioAccount[];
+};
+
+export type TPortfolioResponse = {
+  success: true;
+  portfolio: TPortfolio;
+} | {
+  success: false;
+  errorCode?: 'ratesNotAvailable';
+  errorMessage?: string;
+};
+
+export const getPortfolio = (): Promise<TPortfolioResponse> => {
+  return apiGet('portfolio');
+};
+
 type CoinFormattedAmount = {
   coinCode: CoinCode;
   coinName: string;
@@ -139,8 +177,23 @@ export interface IStatus {
     synced: boolean;
     fatalError: boolean;
     offlineError: string | null;
+    electrum?: TElectrumStatus;
+    lastSynced: string | null;
+    syncProgress?: TSyncProgress;
 }
 
+/**
+ * Progress of syncing a BTC-based account after it was initialized or reconnected.
+ * `totalAddresses` grows while syncing when used addresses are found.
+ */
+export type TSyncProgress = {
+  syncedAddresses: number;
+  totalAddresses: number;
+  fetchedHistories: number;
+  percent: number;
+  server: string;
+};
+
 export const getStatus = (code: AccountCode): Promise<IStatus> => {
   return apiGet(`account/${code}/status`);
 };
@@ -182,6 +235,24 @@ export const getInfo = (code: AccountCode) => {
   };
 };
 
+export type TDescriptor = {
+  scriptType: ScriptType | 'p2wsh';
+  receive: string;
+  change: string;
+};
+
+export type TDescriptorResponse = {
+  success: true;
+  descriptors: TDescriptor[];
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+export const getDescriptor = (code: AccountCode): Promise<TDescriptorResponse> => {
+  return apiGet(`account/${code}/descriptor`);
+};
+
 export const init = (code: AccountCode): Promise<null> => {
   return apiPost(`account/${code}/init`);
 };
@@ -231,13 +302,35 @@ export interface IBalance {
     available: IAmount;
     hasIncoming: boolean;
     incoming: IAmount;
+    // frozen is the part of the available balance marked as "do not spend", spendable the rest.
+    hasFrozen?: boolean;
+    frozen?: IAmount;
+    spendable?: IAmount;
 }
 
 export const getBalance = (code: AccountCode): Promise<IBalance> => {
   return apiGet(`account/${code}/balance`);
 };
 
-export type TTransactionStatus = 'complete' | 'pending' | 'failed';
+export type TBalanceHistoryEntry = {
+  time: number;
+  amount: IAmount;
+};
+
+export type TBalanceHistory = {
+  success: boolean;
+  dataMissing: boolean;
+  entries: TBalanceHistoryEntry[];
+};
+
+export const getBalanceHistory = (
+  code: AccountCode,
+  interval: 'daily' | 'hourly' = 'daily',
+): Promise<TBalanceHistory> => {
+  return apiGet(`account/${code}/balance-history?interval=${interval}`);
+};
+
+export type TTransactionStatus = 'complete' | 'pending' | 'failed' | 'dropped' | 'doubleSpent';
 export type TTransactionType = 'send' | 'receive' | 'send_to_self';
 
 export interface ITransaction {
@@ -258,11 +351,31 @@ export interface ITransaction {
     time: string | null;
     type: TTransactionType;
     txID: string;
+    // unverified is true for BTC/LTC transactions confirmed according to the server, but not
+    // verified against the block headers yet.
+    unverified: boolean;
+    // conflictingTxID is the ID of the transaction which replaced this one if the status is
+    // 'doubleSpent'.
+    conflictingTxID?: string;
     vsize: number;
     weight: number;
 }
 
-export type TTransactions = { success: false } | { success: true; list: ITransaction[]; };
+export type TTransactions = { success: false; errorMessage?: string; } | { success: true; list: ITransaction[]; total: number; };
+
+export type TTransactionsQuery = {
+  offset?: number;
+  limit?: number;
+  type?: TTransactionType[];
+  // RFC3339 timestamps.
+  from?: string;
+  to?: string;
+  // Amounts in the format unit of the account.
+  minAmount?: string;
+  maxAmount?: string;
+  status?: TTransactionStatus;
+  note?: string;
+};
 
 export interface INoteTx {
     internalTxID: string;
@@ -276,22 +389,70 @@ export const postNotesTx = (code: AccountCode, {
   return apiPost(`account/${code}/notes/tx`, { internalTxID, note });
 };
 
-export const getTransactionList = (code: AccountCode): Promise<TTransactions> => {
-  return apiGet(`account/${code}/transactions`);
+export const getTransactionList = (
+  code: AccountCode,
+  query: TTransactionsQuery = {},
+): Promise<TTransactions> => {
+  const params = new URLSearchParams();
+  Object.entries(query).forEach(([name, value]) => {
+    if (value !== undefined && value !== '') {
+      params.set(name, Array.isArray(value) ? value.join(',') : String(value));
+    }
+  });
+  const queryString = params.toString();
+  return apiGet(`account/${code}/transactions${queryString ? `?${queryString}` : ''}`);
 };
 
 export const getTransaction = (code: AccountCode, id: ITransaction['internalID']): Promise<ITransaction | null> => {
   return apiGet(`account/${code}/transaction?id=${id}`);
 };
 
+export type TTransactionInput = {
+  outPoint: string;
+  // address and amount are only set if the input spends a coin of the account.
+  address?: string;
+  amount?: IAmount;
+  ours: boolean;
+};
+
+export type TTransactionOutput = {
+  address: string;
+  amount: IAmount;
+  ours: boolean;
+};
+
+export type TTransactionDetail = ITransaction & {
+  height: number;
+  blockTime: string | null;
+  inputs: TTransactionInput[];
+  outputs: TTransactionOutput[];
+};
+
+/**
+ * Returns the transaction including all its inputs and outputs. Fiat values are those at the
+ * time the transaction was confirmed.
+ */
+export const getTransactionDetail = (
+  code: AccountCode,
+  txID: ITransaction['txID'],
+): Promise<TTransactionDetail> => {
+  return apiGet(`account/${code}/transaction/${txID}`);
+};
+
 export interface IExport {
     success: boolean;
     path: string;
     errorMessage: string;
 }
 
-export const exportAccount = (code: AccountCode): Promise<IExport | null> => {
-  return apiPost(`account/${code}/export`);
+export type TExportFormat = '' | 'cointracking' | 'koinly';
+
+export const exportAccount = (
+  code: AccountCode,
+  format: TExportFormat = '',
+): Promise<IExport | null> => {
+  const query = format ? `?format=${format}` : '';
+  return apiPost(`account/${code}/export${query}`);
 };
 
 export const verifyXPub = (
@@ -301,9 +462,79 @@ export const verifyXPub = (
   return apiPost(`account/${code}/verify-extended-public-key`, { signingConfigIndex });
 };
 
+export type TExportXPub = {
+  success: true;
+  scriptType: ScriptType;
+  keypath: string;
+  xpub: string;
+  qrCode: string;
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+// SLIP-132 versions of extended public keys. Ltub and Mtub are only used for Litecoin, tpub, upub
+// and vpub for testnet.
+export type TXPubEncoding = 'xpub' | 'ypub' | 'zpub' | 'Ltub' | 'Mtub' | 'tpub' | 'upub' | 'vpub';
+
+export const exportXPub = (
+  code: AccountCode,
+  signingConfigIndex: number,
+  verify: boolean,
+  // The version of the exported key. Defaults to the version matching the script type.
+  encoding?: TXPubEncoding,
+): Promise<TExportXPub> => {
+  return apiPost(`account/${code}/xpub-export`, { signingConfigIndex, verify, encoding });
+};
+
+export type TExportElectrumWallet = {
+  success: true;
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+/**
+ * Saves a watch-only Electrum wallet file of a single-sig signing configuration, including the
+ * transaction notes as labels. Resolves with null if the user cancels choosing the file.
+ */
+export const exportElectrumWallet = (
+  code: AccountCode,
+  signingConfigIndex: number,
+): Promise<TExportElectrumWallet | null> => {
+  return apiPost(`account/${code}/electrum-wallet-export`, { signingConfigIndex });
+};
+
+export type TSweepPrivateKey = {
+  success: true;
+  amount: IAmount;
+  fee: IAmount;
+  txId?: string;
+} | {
+  success: false;
+  errorMessage?: string;
+  errorCode?: string;
+};
+
+export const sweepPrivateKey = (
+  code: AccountCode,
+  privateKey: string,
+  feeTarget: FeeTargetCode,
+  customFee: string,
+  broadcast: boolean,
+): Promise<TSweepPrivateKey> => {
+  return apiPost(`account/${code}/sweep`, { privateKey, feeTarget, customFee, broadcast });
+};
+
 export interface IReceiveAddress {
     addressID: string;
     address: string;
+    // the fields below are only set by getAllReceiveAddresses().
+    keypath?: string;
+    index?: number;
+    used?: boolean;
+    balance?: IAmount;
+    label?: string;
 }
 
 export interface ReceiveAddressList {
@@ -317,6 +548,46 @@ export const getReceiveAddressList = (code: AccountCode) => {
   };
 };
 
+/**
+ * Returns all derived receive addresses of a BTC based account, including the used ones, with their
+ * usage status, balance and label. Any unused address can be picked and verified using
+ * verifyAddress().
+ */
+export const getAllReceiveAddresses = (code: AccountCode): Promise<ReceiveAddressList[]> => {
+  return apiGet(`account/${code}/receive-addresses?all=true`);
+};
+
+export type TPreviewAddress = {
+  address: string;
+  addressID: string;
+  keypath: string;
+  // false if the address is beyond the gap limit, so incoming funds are not detected until the
+  // addresses before it are used.
+  watched: boolean;
+};
+
+export type TAddressPreview = {
+  scriptType: ScriptType;
+  receive: TPreviewAddress[];
+  change: TPreviewAddress[];
+};
+
+/**
+ * Returns the next `count` unused receive and change addresses of a BTC based account per script
+ * type, without marking them used, e.g. for an exchange withdrawal whitelist.
+ */
+export const getAddressPreview = (code: AccountCode, count: number): Promise<TAddressPreview[]> => {
+  return apiGet(`account/${code}/address-preview?count=${count}`);
+};
+
+export const setAddressNote = (
+  code: AccountCode,
+  address: string,
+  note: string,
+): Promise<{ success: true } | { success: false; errorMessage: string }> => {
+  return apiPost(`account/${code}/notes/address`, { address, note });
+};
+
 export type TTxInput = {
   address: string;
   amount: string;
@@ -325,15 +596,37 @@ export type TTxInput = {
   sendAll: 'yes' | 'no';
   selectedUTXOs: string[];
   paymentRequest: Slip24 | null;
+  contactID?: string;
+  payjoinURL?: string;
+  // Pays multiple recipients in one transaction, `address` and `amount` are ignored if set.
+  recipients?: TTxRecipient[];
+  // Overrides the coin selection algorithm of the account. Only applies to BTC/LTC.
+  coinSelection?: TCoinSelection;
+};
+
+// Algorithms selecting the coins spent by a BTC/LTC transaction. `branchAndBound` avoids a change
+// output if possible, `singleAddress` does not combine coins of different addresses.
+export type TCoinSelection = 'largestFirst' | 'branchAndBound' | 'singleAddress';
+
+export type TTxRecipient = {
+  address: string;
+  amount: string;
 };
 
 export type TTxProposalResult = {
   amount: IAmount;
   fee: IAmount;
+  // The fee as a percentage of the amount, null if it can't be computed (e.g. ERC20 tokens).
+  feePercentage: number | null;
   success: true;
   total: IAmount;
+  // The coin selection algorithm used, only for BTC/LTC. It can differ from the requested one, as
+  // `branchAndBound` falls back to `largestFirst`.
+  coinSelection?: TCoinSelection;
 } | {
   errorCode: string;
+  // The index of the invalid recipient if `recipients` was set.
+  outputIndex?: number;
   success: false;
 };
 
@@ -394,10 +687,83 @@ export const getFeeTargetList = (code: AccountCode): Promise<IFeeTargetList> =>
   return apiGet(`account/${code}/fee-targets`);
 };
 
+export type TFeeTier = 'low' | 'medium' | 'high' | 'custom';
+
+export type TFeeEstimate = {
+  tier: TFeeTier;
+  // The fee target the estimate is based on, missing for the custom tier.
+  code?: string;
+  // Expected number of blocks until confirmation, missing for the custom tier.
+  blocks?: number;
+  feeRateInfo: string;
+  // For the custom tier, this is the minimum fee rate, or null if unknown.
+  feeRatePerKb: IAmount | null;
+};
+
+export type TFeeEstimates = {
+  success: true;
+  feeEstimates: TFeeEstimate[];
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+export const getFeeEstimates = (code: AccountCode): Promise<TFeeEstimates> => {
+  return apiGet(`account/${code}/fee-estimates`);
+};
+
+export type TConsolidationInput = {
+  // Outpoints of the coins to consolidate, all coins if empty.
+  selectedUTXOs: string[];
+  // Only consolidate coins up to this amount, in the format unit of the account.
+  maxAmount?: string;
+  feeTarget: FeeTargetCode;
+  customFee: string;
+};
+
+export type TConsolidationProposal = {
+  success: true;
+  numInputs: number;
+  amount: IAmount;
+  fee: IAmount;
+  futureFeeRateInfo: string;
+  futureFeeSavings: IAmount;
+} | {
+  success: false;
+  errorMessage?: string;
+  errorCode?: string;
+};
+
+// The proposal is sent with sendTx(), like a regular tx proposal.
+export const proposeConsolidation = (
+  code: AccountCode,
+  input: TConsolidationInput,
+): Promise<TConsolidationProposal> => {
+  return apiPost(`account/${code}/consolidation-proposal`, input);
+};
+
 export const verifyAddress = (code: AccountCode, addressID: string): Promise<boolean> => {
   return apiPost(`account/${code}/verify-address`, addressID);
 };
 
+/**
+ * Displays a previously derived receive address on the device, given by the address itself or by
+ * its keypath, e.g. `m/84'/0'/0'/0/5`. Only supported for bitcoin based accounts. Resolves to
+ * false if the device cannot display addresses.
+ */
+export const verifyReceiveAddress = (code: AccountCode, addressOrKeypath: string): Promise<boolean> => {
+  return apiPost(`account/${code}/verify-receive-address`, addressOrKeypath);
+};
+
+/**
+ * Scans the given number of addresses beyond the gap limit of a bitcoin based account, e.g. to find
+ * funds of an imported wallet with large gaps between used addresses. The extension lasts until the
+ * accounts are reinitialized, use `setAccountGapLimit()` to keep it.
+ */
+export const scanFurther = (code: AccountCode, addresses: number): Promise<null> => {
+  return apiPost(`account/${code}/scan-further`, addresses);
+};
+
 export type TUTXO = {
   outPoint: string;
   txId: string;
@@ -408,12 +774,22 @@ export type TUTXO = {
   scriptType: ScriptType;
   addressReused: boolean;
   isChange: boolean;
+  dust: boolean;
+  frozen: boolean;
 };
 
 export const getUTXOs = (code: AccountCode): Promise<TUTXO[]> => {
   return apiGet(`account/${code}/utxos`);
 };
 
+export const setUTXOFrozen = (
+  code: AccountCode,
+  outPoint: string,
+  frozen: boolean,
+): Promise<{ success: true } | { success: false; errorMessage: string }> => {
+  return apiPost(`account/${code}/utxo-frozen`, { outPoint, frozen });
+};
+
 type TSecureOutput = {
     hasSecureOutput: boolean;
     optional: boolean;
@@ -435,17 +811,83 @@ export const hasPaymentRequest = (code: AccountCode): Promise<THasPaymentRequest
   return apiGet(`account/${code}/has-payment-request`);
 };
 
+export type TWalletFileImport = {
+  accountCode: string;
+  type: 'electrum' | 'wasabi';
+  transactionCount: number;
+  ignoredLabelCount: number;
+};
+
 export type TAddAccount = {
   success: boolean;
   accountCode?: string;
   errorCode?: 'accountAlreadyExists' | 'accountLimitReached';
   errorMessage?: string;
+  walletFileImport?: TWalletFileImport;
 }
 
-export const addAccount = (coinCode: string, name: string): Promise<TAddAccount> => {
+export const addAccount = (
+  coinCode: string,
+  name: string,
+  keypath?: string,
+  scriptType?: ScriptType,
+  // selects the keystore if more than one is connected.
+  rootFingerprint?: string,
+): Promise<TAddAccount> => {
+  return apiPost('account-add', {
+    coinCode,
+    name,
+    keypath,
+    scriptType,
+    rootFingerprint,
+  });
+};
+
+export const addDescriptorAccount = (
+  coinCode: string,
+  name: string,
+  descriptor: string,
+): Promise<TAddAccount> => {
+  return apiPost('account-add', {
+    coinCode,
+    name,
+    descriptor,
+  });
+};
+
+/**
+ * Adds a watch-only account from an account-level extended public key in any SLIP-132 encoding,
+ * optionally with origin info, e.g. `[d34db33f/84'/0'/0']zpub...`. The script type is inferred from
+ * ypub/zpub/Ltub/Mtub keys and is required for xpub keys.
+ */
+export const addExtendedPublicKeyAccount = (
+  coinCode: string,
+  name: string,
+  extendedPublicKey: string,
+  scriptType?: ScriptType,
+): Promise<TAddAccount> => {
+  return apiPost('account-add', {
+    coinCode,
+    name,
+    extendedPublicKey,
+    scriptType,
+  });
+};
+
+/**
+ * Adds a watch-only account from a standard Electrum wallet file or a Wasabi wallet JSON file.
+ * Transaction labels of the file are imported as transaction notes.
+ * @param walletFile hex encoded contents of the file.
+ */
+export const addWalletFileAccount = (
+  coinCode: string,
+  name: string,
+  walletFile: string,
+): Promise<TAddAccount> => {
   return apiPost('account-add', {
     coinCode,
     name,
+    walletFile,
   });
 };
 
@@ -477,6 +919,37 @@ export const ethSignWalletConnectTx = (code: AccountCode, send: boolean, chainId
   return apiPost(`account/${code}/eth-sign-wallet-connect-tx`, { send, chainId, tx });
 };
 
+export type TEthQueuedTransaction = {
+  txID: string;
+  nonce: number;
+  maxFee: IAmount;
+  maxFeePerGas: string;
+  maxPriorityFeePerGas: string;
+  broadcastAttempts: number;
+};
+
+export const getEthQueuedTransactions = (code: AccountCode): Promise<TEthQueuedTransaction[]> => {
+  return apiGet(`account/${code}/eth-queued-txs`);
+};
+
+export type TEthReplaceTransaction = {
+  success: true;
+  txID: string;
+} | {
+  success: false;
+  aborted?: boolean;
+  errorMessage?: string;
+  errorCode?: string;
+};
+
+export const ethReplaceTransaction = (
+  code: AccountCode,
+  txID: string,
+  cancel: boolean,
+): Promise<TEthReplaceTransaction> => {
+  return apiPost(`account/${code}/eth-replace-tx`, { txID, cancel });
+};
+
 export type AddressSignResponse = {
   success: true;
   signature: string;
@@ -490,3 +963,23 @@ export type AddressSignResponse = {
 export const signAddress = (format: ScriptType | '', msg: string, code: AccountCode): Promise<AddressSignResponse> => {
   return apiPost(`account/${code}/sign-address`, { format, msg, code });
 };
+
+export type TMessageSignatureFormat = 'legacy' | 'bip322';
+
+export type TSignBTCMessage = {
+  success: true;
+  signature: string;
+} | {
+  success: false;
+  errorMessage?: string;
+  errorCode?: 'userAbort';
+};
+
+export const signMessage = (
+  code: AccountCode,
+  address: string,
+  message: string,
+  format: TMessageSignatureFormat,
+): Promise<TSignBTCMessage> => {
+  return apiPost(`account/${code}/sign-message`, { address, message, format });
+};
@@ -47,6 +47,46 @@ export const syncAddressesCount = (code: accountAPI.AccountCode) => {
   };
 };
 
+/**
+ * Returns a function that subscribes a callback on a "account/<CODE>/sync-progress"
+ * event to receive the detailed progress of syncing a BTC-based account.
+ * Meant to be used with `useSubscribe`.
+ */
+export const syncProgress = (code: accountAPI.AccountCode) => {
+  return (
+    cb: TSubscriptionCallback<accountAPI.TSyncProgress>
+  ) => {
+    return subscribeEndpoint(`account/${code}/sync-progress`, (
+      progress: accountAPI.TSyncProgress,
+    ) => {
+      cb(progress);
+    });
+  };
+};
+
+export type TReorg = {
+  height: number;
+  depth: number;
+};
+
+/**
+ * Returns a function that subscribes a callback on a "account/<CODE>/reorg"
+ * event, fired when synced headers were replaced by a competing chain.
+ * Confirmations of the affected transactions are verified again.
+ * Meant to be used with `useSubscribe`.
+ */
+export const syncReorg = (code: accountAPI.AccountCode) => {
+  return (
+    cb: TSubscriptionCallback<TReorg>
+  ) => {
+    return subscribeEndpoint(`account/${code}/reorg`, (
+      reorg: TReorg,
+    ) => {
+      cb(reorg);
+    });
+  };
+};
+
 /**
  * Fired when status of an account changed, mostly
  * used as event to call accountAPI.getStatus(code).
@@ -0,0 +1,52 @@
+/**
+ * Copyright 2025 Shift Crypto AG
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+import type { CoinCode } from './account';
+import type { ISuccess } from './backend';
+import { apiGet, apiPost } from '@/utils/request';
+
+export type TContact = {
+  id: string;
+  name: string;
+  coinCode: CoinCode;
+  // A receive address or an xpub.
+  address: string;
+  note: string;
+};
+
+export type TAddContact = {
+  success: true;
+  contact: TContact;
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+export const getContacts = (): Promise<TContact[]> => {
+  return apiGet('addressbook');
+};
+
+export const addContact = (contact: Omit<TContact, 'id'>): Promise<TAddContact> => {
+  return apiPost('addressbook/add', contact);
+};
+
+export const updateContact = (contact: TContact): Promise<ISuccess> => {
+  return apiPost('addressbook/update', contact);
+};
+
+export const deleteContact = (id: string): Promise<ISuccess> => {
+  return apiPost('addressbook/delete', { id });
+};
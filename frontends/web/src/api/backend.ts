@@ -14,10 +14,11 @@
  * limitations under the License.
  */
 
-import type { AccountCode, CoinCode, ERC20CoinCode } from './account';
-import type { FailResponse, SuccessResponse } from './response';
+import type { AccountCode, CoinCode, ERC20CoinCode, TCoinSelection } from './account';
+import type { ErrorResponse, FailResponse, SuccessResponse } from './response';
 import { apiGet, apiPost } from '@/utils/request';
 import { TSubscriptionCallback, subscribeEndpoint } from './subscribe';
+import { subscribe as subscribeLegacy } from '@/utils/event-legacy';
 
 export interface ICoin {
     coinCode: CoinCode;
@@ -32,7 +33,11 @@ export interface ISuccess {
     errorCode?: string;
 }
 
-export const getSupportedCoins = (): Promise<ICoin[]> => {
+// rootFingerprint selects the keystore if more than one is connected.
+export const getSupportedCoins = (rootFingerprint?: string): Promise<ICoin[]> => {
+  if (rootFingerprint) {
+    return apiGet(`supported-coins?rootFingerprint=${rootFingerprint}`);
+  }
   return apiGet('supported-coins');
 };
 
@@ -40,6 +45,14 @@ export const setAccountActive = (accountCode: AccountCode, active: boolean): Pro
   return apiPost('set-account-active', { accountCode, active });
 };
 
+export const archiveAccount = (accountCode: AccountCode): Promise<ISuccess> => {
+  return apiPost(`account/${accountCode}/archive`);
+};
+
+export const unarchiveAccount = (accountCode: AccountCode): Promise<ISuccess> => {
+  return apiPost(`account/${accountCode}/unarchive`);
+};
+
 export const setTokenActive = (
   accountCode: AccountCode,
   tokenCode: ERC20CoinCode,
@@ -49,7 +62,34 @@ export const setTokenActive = (
 };
 
 export const renameAccount = (accountCode: AccountCode, name: string): Promise<ISuccess> => {
-  return apiPost('rename-account', { accountCode, name });
+  return apiPost(`account/${accountCode}/rename`, { name });
+};
+
+/**
+ * Sets the minimum gap limit of a bitcoin based account and reloads the accounts. 0 restores the
+ * default gap limits.
+ */
+export const setAccountGapLimit = (accountCode: AccountCode, gapLimit: number): Promise<null> => {
+  return apiPost(`account/${accountCode}/gap-limit`, gapLimit);
+};
+
+/**
+ * Sets the coin selection algorithm used for new transactions of a bitcoin based account. An empty
+ * string restores the default.
+ */
+export const setAccountCoinSelection = (
+  accountCode: AccountCode,
+  coinSelection: TCoinSelection | '',
+): Promise<null> => {
+  return apiPost(`account/${accountCode}/coin-selection`, coinSelection);
+};
+
+/**
+ * Drops the transactions cached for a bitcoin based account and scans it again from scratch. The
+ * progress is reported like for the initial sync, see `syncAddressesCount()`.
+ */
+export const rescanAccount = (accountCode: AccountCode): Promise<null> => {
+  return apiPost(`account/${accountCode}/rescan`);
 };
 
 export const reinitializeAccounts = (): Promise<null> => {
@@ -72,11 +112,46 @@ export const getQRCode = (data: string) => {
   };
 };
 
+export type TURType = 'crypto-psbt' | 'bytes';
+
+export type TUREncodeResponse = {
+  success: false;
+  errorMessage?: string;
+} | (SuccessResponse & { parts: string[]; });
+
+/**
+ * Encodes base64 data as UR parts, to be shown as an animated QR code if there is more than one.
+ */
+export const urEncode = (
+  type: TURType,
+  data: string,
+  maxFragmentLen?: number,
+): Promise<TUREncodeResponse> => {
+  return apiPost('ur/encode', { type, data, maxFragmentLen });
+};
+
+export type TURDecodeResponse = {
+  success: false;
+  errorMessage?: string;
+} | (SuccessResponse & {
+  complete: boolean;
+  progress: number;
+  type: string;
+  data?: string;
+});
+
+/**
+ * Reassembles the base64 data from all UR parts scanned so far.
+ */
+export const urDecode = (parts: string[]): Promise<TURDecodeResponse> => {
+  return apiPost('ur/decode', { parts });
+};
+
 export const getDefaultConfig = (): Promise<any> => {
   return apiGet('config/default');
 };
 
-export const socksProxyCheck = (proxyAddress: string): Promise<ISuccess> => {
+export const socksProxyCheck = (proxyAddress: string): Promise<SuccessResponse | ErrorResponse> => {
   return apiPost('socksproxy/check', proxyAddress);
 };
 
@@ -111,6 +186,41 @@ export const cancelConnectKeystore = (): Promise<void> => {
   return apiPost('cancel-connect-keystore');
 };
 
+/**
+ * Removes all keystores until the devices are connected again. A `locked` backend event is sent
+ * when the keystores are removed, either by this call or by the auto-lock after inactivity.
+ */
+export const lock = (): Promise<void> => {
+  return apiPost('lock');
+};
+
+export const syncLocked = (cb: () => void) => {
+  return subscribeLegacy('locked', event => {
+    if (event.type === 'backend') {
+      cb();
+    }
+  });
+};
+
+/**
+ * Sets the UI language. An empty language resets it to the native locale.
+ */
+export const setUserLanguage = (language: string): Promise<null> => {
+  return apiPost('config/language', language);
+};
+
+/**
+ * Calls back with the UI language when it was changed with `setUserLanguage()`. It is the main
+ * language of the native locale if the language was reset.
+ */
+export const syncUserLanguage = (cb: (language: string) => void) => {
+  return subscribeLegacy('languageChanged', event => {
+    if (event.type === 'backend') {
+      cb(event.meta.language);
+    }
+  });
+};
+
 export const setWatchonly = (rootFingerprint: string, watchonly: boolean): Promise<ISuccess> => {
   return apiPost('set-watchonly', { rootFingerprint, watchonly });
 };
@@ -141,6 +251,46 @@ export const exportLogs = (): Promise<ISuccess> => {
   return apiPost('export-log');
 };
 
+/**
+ * Saves a zip file with the recent logs, versions, config without secrets and connection status
+ * to the downloads folder, to be sent to support.
+ */
+export const exportDebugBundle = (): Promise<(FailResponse & { aborted: boolean; }) | SuccessResponse> => {
+  return apiPost('debug/export');
+};
+
+export type TCrashReport = {
+  id: string;
+  time: string;
+  version: string;
+  os: string;
+  arch: string;
+  message: string;
+  stack: string;
+};
+
+/**
+ * Returns the crash reports for the user to review. Reports are only kept if the user opted in
+ * with the `crashReports` backend setting. Addresses and xpubs are already removed.
+ */
+export const getCrashReports = (): Promise<TCrashReport[]> => {
+  return apiGet('crash-reports');
+};
+
+export const subscribeCrashReports = (
+  cb: (reports: TCrashReport[]) => void
+) => {
+  return subscribeEndpoint('crash-reports', cb);
+};
+
+export const sendCrashReport = (id: string): Promise<null> => {
+  return apiPost('crash-reports/send', id);
+};
+
+export const deleteCrashReport = (id: string): Promise<null> => {
+  return apiPost('crash-reports/delete', id);
+};
+
 export const exportNotes = (): Promise<(FailResponse & { aborted: boolean; }) | SuccessResponse> => {
   return apiPost('notes/export');
 };
@@ -156,3 +306,93 @@ export const importNotes = (fileContents: ArrayBuffer): Promise<FailResponse | (
     .join('');
   return apiPost('notes/import', hexString);
 };
+
+export type TConfigBackup = {
+  version: number;
+  appConfig: unknown;
+  accountsConfig: unknown;
+  notes: Record<string, unknown>;
+};
+
+export const getConfigBackup = (): Promise<FailResponse | (SuccessResponse & { backup: TConfigBackup; })> => {
+  return apiGet('config/backup');
+};
+
+export const restoreConfigBackup = (backup: TConfigBackup): Promise<FailResponse | SuccessResponse> => {
+  return apiPost('config/restore', backup);
+};
+
+export type TConfigEncryptionStatus = {
+  enabled: boolean;
+  locked: boolean;
+};
+
+export type TConfigEncryptionResponse = SuccessResponse | {
+  success: false;
+  message?: string;
+  errorCode?: 'wrongPassphrase' | 'paramsMissing';
+};
+
+export const getConfigEncryptionStatus = (): Promise<TConfigEncryptionStatus> => {
+  return apiGet('config/encryption');
+};
+
+export const unlockConfig = (passphrase: string): Promise<TConfigEncryptionResponse> => {
+  return apiPost('config/encryption/unlock', passphrase);
+};
+
+export const enableConfigEncryption = (passphrase: string): Promise<TConfigEncryptionResponse> => {
+  return apiPost('config/encryption/enable', passphrase);
+};
+
+export const disableConfigEncryption = (passphrase: string): Promise<TConfigEncryptionResponse> => {
+  return apiPost('config/encryption/disable', passphrase);
+};
+
+export type TAppPasswordStatus = {
+  enabled: boolean;
+  locked: boolean;
+};
+
+export type TAppPasswordResponse = SuccessResponse | {
+  success: false;
+  message?: string;
+  errorCode?: 'wrongPassword' | 'tooManyAttempts';
+};
+
+export const getAppPasswordStatus = (): Promise<TAppPasswordStatus> => {
+  return apiGet('app-password');
+};
+
+/**
+ * Unlocks the app with the app password. Until then, all endpoints but the ones of the lock screen
+ * respond with the `appLocked` error code. After too many wrong passwords, unlocking is refused
+ * for a while with the `tooManyAttempts` error code.
+ */
+export const unlockApp = (password: string): Promise<TAppPasswordResponse> => {
+  return apiPost('unlock', password);
+};
+
+export const enableAppPassword = (password: string): Promise<TAppPasswordResponse> => {
+  return apiPost('app-password/enable', password);
+};
+
+export const disableAppPassword = (password: string): Promise<TAppPasswordResponse> => {
+  return apiPost('app-password/disable', password);
+};
+
+/**
+ * The power and network state of the device, used by the backend to throttle background syncing.
+ */
+export type TSyncState = {
+  onBattery: boolean;
+  metered: boolean;
+};
+
+export const getSyncState = (): Promise<TSyncState> => {
+  return apiGet('sync-state');
+};
+
+export const setSyncState = (state: TSyncState): Promise<null> => {
+  return apiPost('sync-state', state);
+};
@@ -16,6 +16,7 @@
 
 import { apiGet, apiPost } from '@/utils/request';
 import { SuccessResponse, FailResponse } from './response';
+import { subscribeEndpoint, TSubscriptionCallback } from './subscribe';
 
 // BitBox02 error codes.
 export const errUserAbort = 104;
@@ -101,6 +102,24 @@ export const verifyAttestation = (
   return apiGet(`devices/bitbox02/${deviceID}/attestation`);
 };
 
+// 'unknown' until the attestation check performed on connect is done. 'failed' means the device
+// might be counterfeit.
+export type TAttestationStatus = 'genuine' | 'unknown' | 'failed';
+
+export const getAttestationStatus = (
+  deviceID: string,
+): Promise<TAttestationStatus> => {
+  return apiGet(`devices/bitbox02/${deviceID}/attestation-status`);
+};
+
+export const syncAttestationStatus = (deviceID: string) => {
+  return (
+    cb: TSubscriptionCallback<TAttestationStatus>
+  ) => {
+    return subscribeEndpoint(`devices/bitbox02/${deviceID}/attestation-status`, cb);
+  };
+};
+
 export const checkBackup = (
   deviceID: string,
   silent: boolean,
@@ -61,6 +61,32 @@ export const upgradeFirmware = (
   return apiPost(`devices/bitbox02-bootloader/${deviceID}/upgrade-firmware`);
 };
 
+export type TFirmwareRelease = {
+  version: string;
+  monotonicVersion: number;
+  url: string;
+  sha256: string;
+};
+
+export type TLatestReleaseInfo = {
+  release: TFirmwareRelease;
+  // Indicates whether the release is newer than the installed firmware.
+  canUpgrade: boolean;
+};
+
+export const getLatestRelease = (
+  deviceID: string,
+): Promise<TLatestReleaseInfo> => {
+  return apiGet(`devices/bitbox02-bootloader/${deviceID}/latest-release`);
+};
+
+// Downloads the latest firmware release and installs it. The progress is reported via syncStatus.
+export const upgradeFirmwareToLatestRelease = (
+  deviceID: string,
+): Promise<void> => {
+  return apiPost(`devices/bitbox02-bootloader/${deviceID}/upgrade-firmware-latest-release`);
+};
+
 export const reboot = (
   deviceID: string,
 ): Promise<void> => {
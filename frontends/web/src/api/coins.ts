@@ -34,6 +34,120 @@ export const subscribeCoinHeaders = (coinCode: CoinCode) => (
   )
 );
 
+export type TElectrumStatus = {
+  servers: string[];
+  activeServer: string;
+  // The servers of each connection if the coin connects to several servers at the same time.
+  activeServers?: string[];
+};
+
+export const getElectrumStatus = (coinCode: CoinCode): Promise<TElectrumStatus> => {
+  return apiGet(`coins/${coinCode}/electrum-status`);
+};
+
+/**
+ * The latency and tip height last measured for an Electrum server. The servers are measured
+ * periodically, and the fastest servers which agree on the tip are preferred when connecting.
+ */
+export type TServerMeasurement = {
+  server: string;
+  latencyMs: number;
+  tipHeight: number;
+  inConsensus: boolean;
+  active: boolean;
+  error?: string;
+  // Zero time if the server was not measured yet.
+  time: string;
+};
+
+export const getCoinServers = (coinCode: CoinCode): Promise<TServerMeasurement[]> => {
+  return apiGet(`coins/${coinCode}/servers`);
+};
+
+export type TVerifyMessage = {
+  success: true;
+  valid: boolean;
+  format: 'legacy' | 'bip322';
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+export const verifyMessage = (
+  coinCode: CoinCode,
+  address: string,
+  message: string,
+  signature: string,
+): Promise<TVerifyMessage> => {
+  return apiPost(`coins/${coinCode}/verify-message`, { address, message, signature });
+};
+
+export type TBroadcastRawTransaction = {
+  success: true;
+  txID: string;
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+/**
+ * Relays a hex encoded transaction signed elsewhere, e.g. on an air-gapped device.
+ */
+export const broadcastRawTransaction = (
+  coinCode: CoinCode,
+  rawTx: string,
+): Promise<TBroadcastRawTransaction> => {
+  return apiPost(`coins/${coinCode}/broadcast`, { rawTx });
+};
+
+export type TDecodedInput = {
+  previousOutPoint: string;
+  sequence: number;
+  // Only known for PSBT inputs which include the spent output.
+  address?: string;
+  amount?: string;
+  signed: boolean;
+};
+
+export type TDecodedOutput = {
+  // Empty for outputs which do not pay to an address, e.g. OP_RETURN outputs.
+  address?: string;
+  amount: string;
+  pkScript: string;
+};
+
+export type TDecodedTransaction = {
+  txID: string;
+  version: number;
+  lockTime: number;
+  rbf: boolean;
+  inputs: TDecodedInput[];
+  outputs: TDecodedOutput[];
+  // Empty if not all spent outputs are known.
+  fee?: string;
+  unit: string;
+  vsize?: number;
+  psbt: boolean;
+};
+
+export type TDecodeTransaction = {
+  success: true;
+  transaction: TDecodedTransaction;
+} | {
+  success: false;
+  errorMessage: string;
+};
+
+/**
+ * Decodes a hex encoded raw transaction, or a hex or base64 encoded PSBT.
+ */
+export const decodeTransaction = (
+  coinCode: CoinCode,
+  transaction: string,
+): Promise<TDecodeTransaction> => {
+  return apiPost(`coins/${coinCode}/decode-transaction`, { transaction });
+};
+
 export const setBtcUnit = (unit: BtcUnit): Promise<ISuccess> => {
   return apiPost('coins/btc/set-unit', { unit });
 };
@@ -19,7 +19,7 @@ import { apiGet, apiPost } from '@/utils/request';
 
 export type { TUnsubscribe };
 
-type TKeystore = { type: 'hardware' | 'software' };
+type TKeystore = { type: 'hardware' | 'software'; rootFingerprint: string; };
 export type TKeystores = TKeystore[];
 
 export const subscribeKeystores = (
@@ -39,3 +39,71 @@ export const registerTest = (pin: string): Promise<null> => {
 export const deregisterTest = (): Promise<null> => {
   return apiPost('test/deregister');
 };
+
+export type TSimulatorConfirmMode = 'accept' | 'reject' | 'manual';
+
+export type TSimulatorStatus = {
+  unlocked: boolean;
+  remainingAttempts: number;
+  confirmMode: TSimulatorConfirmMode;
+  latencyMs: number;
+  prompt: { title: string; body: string } | null;
+};
+
+export type TSimulatorResponse = {
+  success: boolean;
+  errorMessage?: string;
+  errorCode?: 'wrongPIN' | 'simulatorReset';
+  status: TSimulatorStatus | null;
+};
+
+export const getTestSimulator = (): Promise<TSimulatorResponse> => {
+  return apiGet('test/simulator');
+};
+
+export const createTestSimulator = (
+  pin: string,
+  confirmMode?: TSimulatorConfirmMode,
+  latencyMs?: number,
+): Promise<TSimulatorResponse> => {
+  return apiPost('test/simulator', { pin, confirmMode, latencyMs });
+};
+
+export const unlockTestSimulator = (pin: string): Promise<TSimulatorResponse> => {
+  return apiPost('test/simulator/unlock', pin);
+};
+
+export const setTestSimulatorSettings = (settings: {
+  confirmMode?: TSimulatorConfirmMode;
+  latencyMs?: number;
+}): Promise<TSimulatorResponse> => {
+  return apiPost('test/simulator/settings', settings);
+};
+
+export const respondTestSimulator = (accept: boolean): Promise<TSimulatorResponse> => {
+  return apiPost('test/simulator/respond', accept);
+};
+
+// An external signer, e.g. a Trezor, Ledger or Coldcard device, found by HWI.
+export type THWIDevice = {
+  type: string;
+  model: string;
+  path: string;
+  // empty if the device is locked.
+  fingerprint: string;
+  needs_pin_sent: boolean;
+  needs_passphrase_sent: boolean;
+  error?: string;
+};
+
+export const getHWIDevices = (): Promise<THWIDevice[]> => {
+  return apiGet('hwi/devices');
+};
+
+export const connectHWIDevice = (fingerprint: string): Promise<null> => {
+  return apiPost('hwi/connect', fingerprint);
+};
+
+export const disconnectHWIDevice = (fingerprint: string): Promise<null> => {
+  return apiPost('hwi/disconnect', fingerprint);
+};
@@ -14,16 +14,14 @@
  * limitations under the License.
  */
 
-import { apiPost } from '@/utils/request';
-import { SuccessResponse } from './response';
+import { apiGet, apiPost } from '@/utils/request';
+import { ErrorResponse, SuccessResponse } from './response';
+import { subscribeEndpoint } from './subscribe';
 
 type TCertResponse = {
   success: true;
   pemCert: string;
-} | {
-  success: false;
-  errorMessage: string;
-};
+} | ErrorResponse;
 
 export const downloadCert = (electrumServer: string): Promise<TCertResponse> => {
   return apiPost('certs/download', electrumServer);
@@ -35,11 +33,44 @@ export type TElectrumServer = {
   pemCert: string;
 };
 
-type TCheckElectrumResponse = SuccessResponse | {
-  success: false;
-  errorMessage: string;
-};
+type TCheckElectrumResponse = SuccessResponse | ErrorResponse;
 
 export const checkElectrum = (server: TElectrumServer): Promise<TCheckElectrumResponse> => {
   return apiPost('electrum/check', server);
 };
+
+/**
+ * A certificate pinned for a custom Electrum server, either for all accounts of a coin or for a single account.
+ * If the certificate can't be parsed, only the server, the codes and `error` are set.
+ */
+export type TPinnedCert = {
+  coinCode?: string;
+  accountCode?: string;
+  server: string;
+  fingerprint: string;
+  subject: string;
+  issuer: string;
+  notBefore: string;
+  notAfter: string;
+  expiring: boolean;
+  error?: string;
+};
+
+export const getPinnedCerts = (): Promise<TPinnedCert[]> => {
+  return apiGet('certs/pinned');
+};
+
+export const getPinnedCert = (fingerprint: string): Promise<TPinnedCert> => {
+  return apiGet(`certs/pinned/${fingerprint}`);
+};
+
+/**
+ * Unpins the certificate from all servers it is pinned for. Applies after a restart.
+ */
+export const removePinnedCert = (fingerprint: string): Promise<null> => {
+  return apiPost('certs/pinned/remove', fingerprint);
+};
+
+export const subscribePinnedCerts = (cb: (pinnedCerts: TPinnedCert[]) => void) => {
+  return subscribeEndpoint('certs/pinned', cb);
+};
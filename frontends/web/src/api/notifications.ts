@@ -0,0 +1,58 @@
+/**
+ * Copyright 2025 Shift Crypto AG
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+import { apiGet, apiPost } from '@/utils/request';
+import { subscribeEndpoint } from './subscribe';
+
+export type TNotificationType = 'incomingTx' | 'txDropped' | 'attestationFailed' | 'firmwareUpgradeRequired' | 'updateAvailable' | 'pinnedCertExpiring';
+
+/**
+ * A stored notification. The message is translated with `notification.center.<type>` filled in with the
+ * params, e.g. the amount and account name of an incoming transaction.
+ */
+export type TNotification = {
+  id: string;
+  type: TNotificationType;
+  time: string;
+  params?: Record<string, string>;
+  read: boolean;
+};
+
+/**
+ * Returns the stored notifications, newest first.
+ */
+export const getNotifications = (): Promise<TNotification[]> => {
+  return apiGet('notifications');
+};
+
+export const subscribeNotifications = (
+  cb: (notifications: TNotification[]) => void
+) => {
+  return subscribeEndpoint('notifications', cb);
+};
+
+export const markNotificationRead = (id: string): Promise<null> => {
+  return apiPost('notifications/read', id);
+};
+
+export const markAllNotificationsRead = (): Promise<null> => {
+  return apiPost('notifications/read-all');
+};
+
+export const deleteNotification = (id: string): Promise<null> => {
+  return apiPost('notifications/delete', id);
+};
@@ -0,0 +1,61 @@
+/**
+ * Copyright 2025 Shift Crypto AG
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+import type { AccountCode } from './account';
+import type { TUnsubscribe } from '@/utils/transport-common';
+import { apiGet, apiPost } from '@/utils/request';
+import { subscribeEndpoint } from './subscribe';
+
+/**
+ * Payment request of a `bitcoin:` or `litecoin:` URI opened with the app. The send screen of
+ * `accountCode` should be opened, prefilled with the request.
+ */
+export type TPaymentURI = {
+  errorCode: 'paymentURIInvalid' | 'paymentURINoAccounts';
+} | {
+  errorCode?: undefined;
+  address: string;
+  // formatted in the unit configured for the coin, empty if the URI has no amount.
+  amount: string;
+  label: string;
+  message: string;
+  payjoinURL: string;
+  accountCode: AccountCode;
+  // all accounts which can pay the request.
+  accounts: { name: string; code: AccountCode }[];
+};
+
+export const getPaymentURI = (): Promise<TPaymentURI | null> => {
+  return apiGet('payment-uri');
+};
+
+export const clearPaymentURI = (): Promise<null> => {
+  return apiPost('payment-uri/clear');
+};
+
+/**
+ * Passes a URI of a registered scheme, e.g. `bitcoin:...`, to the backend, for app shells which
+ * can't call the backend directly.
+ */
+export const handleURI = (uri: string): Promise<null> => {
+  return apiPost('handle-uri', uri);
+};
+
+export const subscribePaymentURI = (
+  cb: (paymentURI: TPaymentURI | null) => void
+): TUnsubscribe => {
+  return subscribeEndpoint('payment-uri', cb);
+};
@@ -24,3 +24,14 @@ export type FailResponse = {
     message?: string;
     success: false;
 }
+
+/**
+ * Failed response of endpoints which return a stable error code and the parameters of its
+ * message, so the failure can be shown translated, see `localizeError()`.
+ */
+export type ErrorResponse = {
+    success: false;
+    errorMessage: string;
+    errorCode?: string;
+    params?: Record<string, string>;
+}
@@ -30,3 +30,43 @@ export const syncNewTxs = (
     }
   });
 };
+
+export type TIncomingTx = {
+  accountCode: string;
+  accountName: string;
+  coinCode: string;
+  txID: string;
+  amount: string;
+  unit: string;
+  pending: boolean;
+};
+
+export const syncIncomingTx = (
+  cb: (meta: TIncomingTx) => void,
+) => {
+  return subscribeLegacy('incomingTx', event => {
+    if (event.type === 'backend') {
+      cb(event.meta);
+    }
+  });
+};
+
+export type TTxDropped = {
+  accountCode: string;
+  accountName: string;
+  txID: string;
+  status: 'dropped' | 'doubleSpent';
+};
+
+/**
+ * Fired for each unconfirmed transaction which was dropped from the mempool or double-spent.
+ */
+export const syncTxDropped = (
+  cb: (meta: TTxDropped) => void,
+) => {
+  return subscribeLegacy('txDropped', event => {
+    if (event.type === 'backend') {
+      cb(event.meta);
+    }
+  });
+};
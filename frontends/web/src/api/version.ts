@@ -25,10 +25,24 @@ export type TUpdateFile = {
     description: string;
 }
 
-export const getVersion = (): Promise<string> => {
+export type TAppVersionInfo = {
+  version: string;
+  // The range of supported API versions, which can be requested with the `/api/v<n>/` prefix.
+  apiVersion: {
+    min: number;
+    max: number;
+  };
+};
+
+export const getAppVersionInfo = (): Promise<TAppVersionInfo> => {
   return apiGet('version');
 };
 
+export const getVersion = async (): Promise<string> => {
+  const { version } = await getAppVersionInfo();
+  return version;
+};
+
 export const getUpdate = (): Promise<TUpdateFile | null> => {
   return apiGet('update');
 };
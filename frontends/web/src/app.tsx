@@ -23,6 +23,7 @@ import { useDefault } from './hooks/default';
 import { usePrevious } from './hooks/previous';
 import { useIgnoreDrop } from './hooks/drop';
 import { usePlatformClass } from './hooks/platform';
+import { useSyncStateReporter } from './hooks/syncstate';
 import { AppRouter } from './routes/router';
 import { Wizard as BitBox02Wizard } from './routes/device/bitbox02/wizard';
 import { getAccounts } from './api/account';
@@ -46,6 +47,7 @@ import styles from './app.module.css';
 
 export const App = () => {
   usePlatformClass();
+  useSyncStateReporter();
   const { t } = useTranslation();
   const navigate = useNavigate();
   useIgnoreDrop();
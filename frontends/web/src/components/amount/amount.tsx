@@ -141,6 +141,8 @@ export const FormattedAmount = ({
   switch (unit) {
   case 'BTC':
   case 'TBTC':
+  case 'TSIG':
+  case 'RBTC':
   case 'LTC':
   case 'TLTC':
     if (removeBtcTrailingZeroes && amount.includes('.')) {
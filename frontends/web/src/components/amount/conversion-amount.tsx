@@ -29,7 +29,7 @@ type TConversionAmountProps = {
   type: TTransactionType;
 };
 
-const btcUnits: Readonly<string[]> = ['BTC', 'TBTC', 'sat', 'tsat'];
+const btcUnits: Readonly<string[]> = ['BTC', 'TBTC', 'TSIG', 'RBTC', 'sat', 'tsat'];
 
 /**
  * Renders a formattted conversion amount optionally with send-to-self icon or estimate symbol
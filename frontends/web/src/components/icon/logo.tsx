@@ -77,6 +77,7 @@ type LogoMap = {
 const logoMap: LogoMap = {
   'btc': [BTC, BTC_GREY],
   'tbtc': [BTC, BTC_GREY],
+  'tsig': [BTC, BTC_GREY],
   'rbtc': [BTC, BTC_GREY],
   'ltc': [LTC, LTC_GREY],
   'tltc': [LTC, LTC_GREY],
@@ -0,0 +1,76 @@
+/**
+ * Copyright 2025 Shift Crypto AG
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+import { useEffect } from 'react';
+import { setSyncState } from '@/api/backend';
+
+// Battery Status API, not available in all browsers.
+type TBatteryManager = EventTarget & {
+  charging: boolean;
+};
+
+// Network Information API, not available in all browsers.
+type TNetworkInformation = EventTarget & {
+  saveData?: boolean;
+  type?: string;
+};
+
+type TNavigator = Navigator & {
+  getBattery?: () => Promise<TBatteryManager>;
+  connection?: TNetworkInformation;
+};
+
+/**
+ * useSyncStateReporter reports to the backend whether the device runs on battery or uses a
+ * metered network, so it can throttle background syncing. Nothing is reported if the browser
+ * does not provide this information.
+ */
+export const useSyncStateReporter = () => {
+  useEffect(() => {
+    const nav = navigator as TNavigator;
+    const connection = nav.connection;
+    let battery: TBatteryManager | undefined;
+    let unmounted = false;
+
+    const report = () => {
+      if (unmounted) {
+        return;
+      }
+      setSyncState({
+        onBattery: battery !== undefined && !battery.charging,
+        metered: connection !== undefined && (!!connection.saveData || connection.type === 'cellular'),
+      }).catch(console.error);
+    };
+
+    connection?.addEventListener('change', report);
+    if (nav.getBattery) {
+      nav.getBattery()
+        .then(batteryManager => {
+          battery = batteryManager;
+          battery.addEventListener('chargingchange', report);
+          report();
+        })
+        .catch(console.error);
+    } else if (connection) {
+      report();
+    }
+    return () => {
+      unmounted = true;
+      connection?.removeEventListener('change', report);
+      battery?.removeEventListener('chargingchange', report);
+    };
+  }, []);
+};
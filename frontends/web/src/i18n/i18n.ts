@@ -38,7 +38,7 @@ import appTranslationsHE from '@/locales/he/app.json';
 import appTranslationsIT from '@/locales/it/app.json';
 import { languageFromConfig } from './config';
 import { localeMainLanguage } from './utils';
-import { setConfig } from '@/utils/config';
+import { setUserLanguage, syncUserLanguage } from '@/api/backend';
 
 const locizeProjectID = 'fe4e5a24-e4a2-4903-96fc-3d62c11fc502';
 
@@ -93,10 +93,6 @@ i18n.on('languageChanged', (lng) => {
   // the newly selected language lng to make the app use native-locale again.
   // This also covers partial matches. For example, if native locale is pt_BR
   // and the app has only pt translation, assume they match.
-  //
-  // Since userLanguage is stored in the backend config as a string,
-  // setting it to null here in JS turns it into an empty string "" in Go backend.
-  // This is ok since we're just checking for a truthy value in the language detector.
   return getNativeLocale().then((nativeLocale) => {
     let match = lng === nativeLocale;
     if (!match) {
@@ -106,9 +102,15 @@ i18n.on('languageChanged', (lng) => {
       const localeLang = localeMainLanguage(nativeLocale);
       match = lngLang === localeLang;
     }
-    const uiLang = match ? null : lng;
-    return setConfig({ backend: { userLanguage: uiLang } });
+    return setUserLanguage(match ? '' : lng);
   });
 });
 
+// Follow language changes made through the API, e.g. by another frontend.
+syncUserLanguage((language) => {
+  if (language && localeMainLanguage(language) !== localeMainLanguage(i18n.language)) {
+    i18n.changeLanguage(language);
+  }
+});
+
 export { i18n };
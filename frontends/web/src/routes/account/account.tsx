@@ -142,7 +142,8 @@ export const Account = ({
     if (!code || status === undefined || status.fatalError) {
       return;
     }
-    if (status.synced && status.offlineError === null) {
+    // The balance and transactions persisted during the last sync are shown while syncing again.
+    if ((status.synced || status.lastSynced !== null) && status.offlineError === null) {
       const currentCode = code;
       Promise.all([
         accountApi.getBalance(currentCode).then(newBalance => {
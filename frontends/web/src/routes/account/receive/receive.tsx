@@ -200,7 +200,7 @@ export const Receive = ({
 
   let uriPrefix = '';
   if (account) {
-    if (account.coinCode === 'btc' || account.coinCode === 'tbtc') {
+    if (account.coinCode === 'btc' || account.coinCode === 'tbtc' || account.coinCode === 'tsig' || account.coinCode === 'rbtc') {
       uriPrefix = 'bitcoin:';
     } else if (account.coinCode === 'ltc' || account.coinCode === 'tltc') {
       uriPrefix = 'litecoin:';
@@ -69,6 +69,7 @@ export type State = {
     isUpdatingProposal: boolean;
     errorHandling: TProposalError;
     note: string;
+    payjoinURL: string;
 }
 
 class Send extends Component<Props, State> {
@@ -88,6 +89,7 @@ class Send extends Component<Props, State> {
     isConfirming: false,
     isUpdatingProposal: false,
     note: '',
+    payjoinURL: '',
     customFee: '',
     errorHandling: {},
   };
@@ -123,6 +125,7 @@ class Send extends Component<Props, State> {
       fiatAmount: '',
       amount: '',
       note: '',
+      payjoinURL: '',
       customFee: '',
     });
     this.selectedUTXOs = {};
@@ -147,7 +150,7 @@ class Send extends Component<Props, State> {
     }
   };
 
-  private getValidTxInputData = (): Required<accountApi.TTxInput> | false => {
+  private getValidTxInputData = (): accountApi.TTxInput | false => {
     if (
       !this.state.recipientAddress
       || this.state.feeTarget === undefined
@@ -164,6 +167,7 @@ class Send extends Component<Props, State> {
       sendAll: (this.state.sendAll ? 'yes' : 'no'),
       selectedUTXOs: Object.keys(this.selectedUTXOs),
       paymentRequest: null,
+      ...(this.state.payjoinURL ? { payjoinURL: this.state.payjoinURL } : {}),
     };
   };
 
@@ -290,6 +294,7 @@ class Send extends Component<Props, State> {
   private parseQRResult = async (uri: string) => {
     let address;
     let amount = '';
+    let payjoinURL = '';
     try {
       const url = new URL(uri);
       if (url.protocol !== 'bitcoin:' && url.protocol !== 'litecoin:' && url.protocol !== 'ethereum:') {
@@ -299,19 +304,22 @@ class Send extends Component<Props, State> {
       address = url.pathname;
       if (isBitcoinBased(this.props.account.coinCode)) {
         amount = url.searchParams.get('amount') || '';
+        // Payjoin (BIP-78) endpoint of the receiver.
+        payjoinURL = url.searchParams.get('pj') || '';
       }
     } catch {
       address = uri;
     }
     let updateState = {
       recipientAddress: address,
+      payjoinURL,
       sendAll: false,
       fiatAmount: ''
     } as Pick<State, keyof State>;
 
     const coinCode = this.props.account.coinCode;
     if (amount) {
-      if (coinCode === 'btc' || coinCode === 'tbtc') {
+      if (coinCode === 'btc' || coinCode === 'tbtc' || coinCode === 'tsig' || coinCode === 'rbtc') {
         const result = await parseExternalBtcAmount(amount);
         if (result.success) {
           updateState['amount'] = result.amount;
@@ -332,7 +340,7 @@ class Send extends Component<Props, State> {
   };
 
   private onReceiverAddressInputChange = (recipientAddress: string) => {
-    this.setState({ recipientAddress }, () => {
+    this.setState({ recipientAddress, payjoinURL: '' }, () => {
       this.validateAndDisplayFee(true);
     });
   };
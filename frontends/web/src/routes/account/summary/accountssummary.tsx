@@ -136,7 +136,11 @@ export const AccountsSummary = ({
       return;
     }
     if (!status.synced) {
-      return accountApi.init(code);
+      await accountApi.init(code);
+      // The balance persisted during the last sync is shown while syncing again.
+      if (status.lastSynced === null) {
+        return;
+      }
     }
     const balance = await accountApi.getBalance(code);
     if (!mounted.current) {
@@ -17,12 +17,13 @@
 import { useNavigate } from 'react-router-dom';
 import { useTranslation } from 'react-i18next';
 import { AccountCode, CoinCode, IBalance } from '@/api/account';
-import { syncAddressesCount } from '@/api/accountsync';
+import { syncAddressesCount, syncProgress } from '@/api/accountsync';
 import { useSubscribe } from '@/hooks/api';
 import { useMediaQuery } from '@/hooks/mediaquery';
 import { Logo } from '@/components/icon/logo';
 import { Amount } from '@/components/amount/amount';
 import { AsciiSpinner } from '@/components/spinner/ascii';
+import { ProgressRing } from '@/components/progressRing/progressRing';
 import { FiatConversion } from '@/components/rates/rates';
 import style from './accountssummary.module.css';
 
@@ -60,6 +61,7 @@ export const BalanceRow = (
 ) => {
   const { t } = useTranslation();
   const syncStatus = useSubscribe(syncAddressesCount(code));
+  const progress = useSubscribe(syncProgress(code));
   const navigate = useNavigate();
   const isMobile = useMediaQuery('(max-width: 768px)');
   const handleClick = () => navigate(`/account/${code}`);
@@ -91,11 +93,24 @@ export const BalanceRow = (
     <tr key={`${code}_syncing`}>
       <NameCell name={name} coinCode={coinCode} />
       <td colSpan={2} className={style.syncText}>
-        { t('account.syncedAddressesCount', {
-          count: syncStatus,
-          defaultValue: 0,
-        }) }
-        <AsciiSpinner />
+        {progress ? (
+          <>
+            <ProgressRing width={14} value={progress.percent} />{' '}
+            { t('account.syncProgress', {
+              synced: progress.syncedAddresses,
+              total: progress.totalAddresses,
+              percent: progress.percent,
+            }) }
+          </>
+        ) : (
+          <>
+            { t('account.syncedAddressesCount', {
+              count: syncStatus,
+              defaultValue: 0,
+            }) }
+            <AsciiSpinner />
+          </>
+        )}
       </td>
     </tr>
   );
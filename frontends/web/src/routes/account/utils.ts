@@ -28,18 +28,22 @@ export const isBitcoinOnly = (coinCode: CoinCode): boolean => {
   switch (coinCode) {
   case 'btc':
   case 'tbtc':
+  case 'tsig':
+  case 'rbtc':
     return true;
   default:
     return false;
   }
 };
 
-export const isBitcoinCoin = (coin: CoinUnit) => (coin === 'BTC') || (coin === 'TBTC') || (coin === 'sat') || (coin === 'tsat');
+export const isBitcoinCoin = (coin: CoinUnit) => (coin === 'BTC') || (coin === 'TBTC') || (coin === 'TSIG') || (coin === 'RBTC') || (coin === 'sat') || (coin === 'tsat');
 
 export const isBitcoinBased = (coinCode: CoinCode): boolean => {
   switch (coinCode) {
   case 'btc':
   case 'tbtc':
+  case 'tsig':
+  case 'rbtc':
   case 'ltc':
   case 'tltc':
     return true;
@@ -56,6 +60,8 @@ export const getCoinCode = (coinCode: CoinCode): CoinCode | undefined => {
   switch (coinCode) {
   case 'btc':
   case 'tbtc':
+  case 'tsig':
+  case 'rbtc':
     return 'btc';
   case 'ltc':
   case 'tltc':
@@ -21,6 +21,7 @@ import { Toggle } from '@/components/toggle/toggle';
 import { Button, Input } from '@/components/forms';
 import { setConfig } from '@/utils/config';
 import { socksProxyCheck } from '@/api/backend';
+import { localizeError } from '@/utils/error';
 import { alertUser } from '@/components/alert/Alert';
 import { TConfig, TProxyConfig } from '@/routes/settings/advanced-settings';
 
@@ -51,12 +52,11 @@ export const TorProxyDialog = ({ open, proxyConfig, onCloseDialog, onChangeConfi
     proxy.proxyAddress = proxyAddress.trim();
 
     const result = await socksProxyCheck(proxy.proxyAddress);
-    const { success, errorMessage } = result;
 
-    if (success) {
+    if (result.success) {
       await setProxyConfig(proxy);
     } else {
-      alertUser(errorMessage || t('account.fatalError'));
+      alertUser(localizeError(result) || t('account.fatalError'));
     }
   };
 
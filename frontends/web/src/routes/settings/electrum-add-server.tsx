@@ -17,6 +17,7 @@
 import { useState } from 'react';
 import { useTranslation } from 'react-i18next';
 import { checkElectrum, downloadCert, TElectrumServer } from '@/api/node';
+import { localizeError } from '@/utils/error';
 import { Button, Input } from '@/components/forms';
 import { alertUser } from '@/components/alert/Alert';
 import style from './electrum.module.css';
@@ -55,7 +56,7 @@ export const ElectrumAddServer = ({
     if (data.success) {
       setElectrumCert(data.pemCert);
     } else {
-      alertUser(data.errorMessage);
+      alertUser(localizeError(data));
     }
     setLoadingCert(false);
   };
@@ -66,7 +67,7 @@ export const ElectrumAddServer = ({
     if (response.success) {
       alertUser(t('settings.electrum.checkSuccess', { host: electrumServer }));
     } else {
-      alertUser(t('settings.electrum.checkFailed') + ':\n' + response.errorMessage);
+      alertUser(t('settings.electrum.checkFailed') + ':\n' + localizeError(response));
     }
     setValid(response.success);
     setLoadingCheck(false);
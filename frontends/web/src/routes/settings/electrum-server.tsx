@@ -17,6 +17,7 @@
 import { useState } from 'react';
 import { useTranslation } from 'react-i18next';
 import { TElectrumServer, checkElectrum } from '@/api/node';
+import { localizeError } from '@/utils/error';
 import { alertUser } from '@/components/alert/Alert';
 import style from './electrum.module.css';
 
@@ -42,7 +43,7 @@ export const ElectrumServer = ({
     if (response.success) {
       alertUser(t('settings.electrum.checkSuccess', { host: server.server }));
     } else {
-      alertUser(t('settings.electrum.checkFailed') + ':\n' + response.errorMessage);
+      alertUser(t('settings.electrum.checkFailed') + ':\n' + localizeError(response));
     }
     setLoadingCheck(false);
   };
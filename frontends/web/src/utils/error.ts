@@ -0,0 +1,30 @@
+/**
+ * Copyright 2025 Shift Crypto AG
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+import { i18n } from '@/i18n/i18n';
+import type { ErrorResponse } from '@/api/response';
+
+/**
+ * Returns the translated message of a backend error with an error code, e.g.
+ * `error.electrum.connectFailed`. Falls back to the English message of the backend if the error has
+ * no code or the code has no translation yet.
+ */
+export const localizeError = ({ errorMessage, errorCode, params }: ErrorResponse): string => {
+  if (!errorCode) {
+    return errorMessage;
+  }
+  return i18n.t(`error.${errorCode}`, { ...params, defaultValue: errorMessage });
+};
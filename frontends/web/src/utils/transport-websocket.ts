@@ -22,30 +22,53 @@ let socket: WebSocket | undefined;
 
 const currentListeners: TMsgCallback[] = [];
 
-export const webSubscribePushNotifications = (msgCallback: TMsgCallback): TUnsubscribe => {
-  currentListeners.push(msgCallback);
-  if (!socket) {
-    socket = new WebSocket((isTLS() ? 'wss://' : 'ws://') + 'localhost:' + apiPort + '/api/events');
+// The ID of the last event received, used to request the missed events when reconnecting.
+let lastEventID: number | undefined;
 
-    socket.onopen = () => {
-      if (socket) {
-        socket.send('Authorization: Basic ' + apiToken);
+// The delay before connecting again if the backend refused the connection.
+const reconnectDelay = 2000;
+
+const connect = () => {
+  let opened = false;
+  socket = new WebSocket((isTLS() ? 'wss://' : 'ws://') + 'localhost:' + apiPort + '/api/events');
+
+  socket.onopen = () => {
+    opened = true;
+    if (socket) {
+      socket.send('Authorization: Basic ' + apiToken);
+      if (lastEventID !== undefined) {
+        socket.send(JSON.stringify({ action: 'replay', lastEventID }));
       }
-    };
+    }
+  };
+
+  socket.onerror = (event) => {
+    console.error('websocket error', event);
+  };
 
-    socket.onerror = (event) => {
-      console.error('websocket error', event);
-    };
+  // Listen for messages
+  socket.onmessage = (event) => {
+    const payload = JSON.parse(event.data);
+    if (typeof payload.eventID === 'number') {
+      lastEventID = payload.eventID;
+    }
+    currentListeners.forEach(listener => listener(payload));
+  };
 
-    // Listen for messages
-    socket.onmessage = (event) => {
-      const payload = JSON.parse(event.data);
-      currentListeners.forEach(listener => listener(payload));
-    };
+  socket.onclose = () => {
+    if (!opened) {
+      // The backend refuses the connection while the app is locked with the app password.
+      setTimeout(connect, reconnectDelay);
+      return;
+    }
+    currentListeners.forEach(listener => listener({ subject: 'backend/connected', action: 'replace', object: false }));
+  };
+};
 
-    socket.onclose = () => {
-      currentListeners.forEach(listener => listener({ subject: 'backend/connected', action: 'replace', object: false }));
-    };
+export const webSubscribePushNotifications = (msgCallback: TMsgCallback): TUnsubscribe => {
+  currentListeners.push(msgCallback);
+  if (!socket) {
+    connect();
   }
   return () => {
     if (!currentListeners.includes(msgCallback)) {
@@ -58,3 +81,14 @@ export const webSubscribePushNotifications = (msgCallback: TMsgCallback): TUnsub
     }
   };
 };
+
+/**
+ * Changes the event topics the websocket receives events for, e.g. 'rates', 'headers', 'account',
+ * 'account/<code>', 'device' or 'device/<deviceID>'. All events are received by default, i.e.
+ * the client is subscribed to '*'.
+ */
+export const webChangeSubscriptions = (action: 'subscribe' | 'unsubscribe', topics: string[]) => {
+  if (socket && socket.readyState === WebSocket.OPEN) {
+    socket.send(JSON.stringify({ action, topics }));
+  }
+};
@@ -17,6 +17,8 @@
 package errp
 
 import (
+	stderrors "errors"
+
 	"github.com/pkg/errors"
 )
 
@@ -68,3 +70,43 @@ const (
 	// ErrUserAbort is returned if the user aborted the current operation.
 	ErrUserAbort ErrorCode = "userAbort"
 )
+
+// CodedError is an error with an error code and parameters for the message, e.g. the host that
+// could not be reached, so the frontend can show a translated message.
+type CodedError struct {
+	Code   ErrorCode
+	Params Context
+	Err    error
+}
+
+func (codedError *CodedError) Error() string {
+	return codedError.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (codedError *CodedError) Unwrap() error {
+	return codedError.Err
+}
+
+// Cause returns the wrapped error, so Cause(err) is not changed by adding a code.
+func (codedError *CodedError) Cause() error {
+	return codedError.Err
+}
+
+// WithCode adds an error code and the parameters of its message to err.
+func WithCode(err error, code ErrorCode, params Context) error {
+	return &CodedError{Code: code, Params: params, Err: err}
+}
+
+// CodeOf returns the error code and the parameters of err if it is or wraps an ErrorCode or a
+// CodedError. ok is false if err has no code.
+func CodeOf(err error) (code ErrorCode, params Context, ok bool) {
+	var codedError *CodedError
+	if stderrors.As(err, &codedError) {
+		return codedError.Code, codedError.Params, true
+	}
+	if stderrors.As(err, &code) {
+		return code, nil, true
+	}
+	return "", nil, false
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOf(t *testing.T) {
+	_, _, ok := CodeOf(New("error"))
+	require.False(t, ok)
+
+	code, params, ok := CodeOf(WithStack(ErrUserAbort))
+	require.True(t, ok)
+	require.Equal(t, ErrUserAbort, code)
+	require.Nil(t, params)
+
+	cause := New("connection refused")
+	err := WithMessage(WithCode(cause, "electrum.connectFailed", Context{"host": "example.com:50002"}), "check")
+	code, params, ok = CodeOf(err)
+	require.True(t, ok)
+	require.Equal(t, ErrorCode("electrum.connectFailed"), code)
+	require.Equal(t, Context{"host": "example.com:50002"}, params)
+	require.Equal(t, "check: connection refused", err.Error())
+	require.Equal(t, cause, Cause(err))
+}
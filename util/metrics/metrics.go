@@ -0,0 +1,237 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics collects counters and durations to measure the performance of the app during
+// development. They are served in the Prometheus text format, see WriteText().
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
+)
+
+// metric is implemented by all metric types.
+type metric interface {
+	name() string
+	writeText(w io.Writer) error
+}
+
+var (
+	registryLock locker.Locker
+	registry     = map[string]metric{}
+)
+
+func register(m metric) {
+	defer registryLock.Lock()()
+	if _, ok := registry[m.name()]; ok {
+		panic(fmt.Sprintf("metric %s registered twice", m.name()))
+	}
+	registry[m.name()] = m
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format.
+func WriteText(w io.Writer) error {
+	unlock := registryLock.RLock()
+	metrics := make([]metric, 0, len(registry))
+	for _, m := range registry {
+		metrics = append(metrics, m)
+	}
+	unlock()
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+	for _, m := range metrics {
+		if err := m.writeText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// labels formats a label, e.g. `{method="server.ping"}`. It is empty if name is empty.
+func labels(name, value string) string {
+	if name == "" {
+		return ""
+	}
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return fmt.Sprintf(`{%s="%s"}`, name, value)
+}
+
+// sortedKeys returns the label values in a stable order.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec is a counter per value of one label.
+type CounterVec struct {
+	metricName string
+	help       string
+	label      string
+	lock       locker.Locker
+	values     map[string]float64
+}
+
+// NewCounterVec creates and registers a counter with one label.
+func NewCounterVec(name, help, label string) *CounterVec {
+	counter := &CounterVec{metricName: name, help: help, label: label, values: map[string]float64{}}
+	register(counter)
+	return counter
+}
+
+func (counter *CounterVec) name() string { return counter.metricName }
+
+// Inc increments the counter of the label value by one.
+func (counter *CounterVec) Inc(labelValue string) {
+	defer counter.lock.Lock()()
+	counter.values[labelValue]++
+}
+
+// Value returns the counter of the label value.
+func (counter *CounterVec) Value(labelValue string) float64 {
+	defer counter.lock.RLock()()
+	return counter.values[labelValue]
+}
+
+func (counter *CounterVec) writeText(w io.Writer) error {
+	defer counter.lock.RLock()()
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n",
+		counter.metricName, counter.help, counter.metricName); err != nil {
+		return err
+	}
+	for _, labelValue := range sortedKeys(counter.values) {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", counter.metricName, labels(counter.label, labelValue),
+			formatFloat(counter.values[labelValue])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	metricName string
+	help       string
+	lock       locker.Locker
+	value      float64
+}
+
+// NewGauge creates and registers a gauge.
+func NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{metricName: name, help: help}
+	register(gauge)
+	return gauge
+}
+
+func (gauge *Gauge) name() string { return gauge.metricName }
+
+// Inc increments the gauge by one.
+func (gauge *Gauge) Inc() {
+	defer gauge.lock.Lock()()
+	gauge.value++
+}
+
+// Dec decrements the gauge by one.
+func (gauge *Gauge) Dec() {
+	defer gauge.lock.Lock()()
+	gauge.value--
+}
+
+// Value returns the current value.
+func (gauge *Gauge) Value() float64 {
+	defer gauge.lock.RLock()()
+	return gauge.value
+}
+
+func (gauge *Gauge) writeText(w io.Writer) error {
+	defer gauge.lock.RLock()()
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n",
+		gauge.metricName, gauge.help, gauge.metricName, gauge.metricName, formatFloat(gauge.value))
+	return err
+}
+
+// summaryValue holds the observations of one label value.
+type summaryValue struct {
+	count uint64
+	sum   float64
+}
+
+// SummaryVec counts observations, e.g. durations, and sums them up per value of one label. The
+// average is sum/count.
+type SummaryVec struct {
+	metricName string
+	help       string
+	label      string
+	lock       locker.Locker
+	values     map[string]*summaryValue
+}
+
+// NewSummaryVec creates and registers a summary with one label.
+func NewSummaryVec(name, help, label string) *SummaryVec {
+	summary := &SummaryVec{metricName: name, help: help, label: label, values: map[string]*summaryValue{}}
+	register(summary)
+	return summary
+}
+
+func (summary *SummaryVec) name() string { return summary.metricName }
+
+// Observe adds an observation for the label value.
+func (summary *SummaryVec) Observe(labelValue string, value float64) {
+	defer summary.lock.Lock()()
+	observations, ok := summary.values[labelValue]
+	if !ok {
+		observations = &summaryValue{}
+		summary.values[labelValue] = observations
+	}
+	observations.count++
+	observations.sum += value
+}
+
+// Count returns the number of observations of the label value.
+func (summary *SummaryVec) Count(labelValue string) uint64 {
+	defer summary.lock.RLock()()
+	if observations, ok := summary.values[labelValue]; ok {
+		return observations.count
+	}
+	return 0
+}
+
+func (summary *SummaryVec) writeText(w io.Writer) error {
+	defer summary.lock.RLock()()
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s summary\n",
+		summary.metricName, summary.help, summary.metricName); err != nil {
+		return err
+	}
+	for _, labelValue := range sortedKeys(summary.values) {
+		observations := summary.values[labelValue]
+		label := labels(summary.label, labelValue)
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+			summary.metricName, label, formatFloat(observations.sum),
+			summary.metricName, label, observations.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
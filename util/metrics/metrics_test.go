@@ -0,0 +1,57 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	counter := NewCounterVec("test_requests_total", "Number of requests.", "method")
+	gauge := NewGauge("test_clients", "Number of clients.")
+	summary := NewSummaryVec("test_duration_seconds", "Duration.", "path")
+
+	counter.Inc("b")
+	counter.Inc("a")
+	counter.Inc("b")
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+	summary.Observe(`/api/"x"`, 0.5)
+	summary.Observe(`/api/"x"`, 1.25)
+	require.Equal(t, float64(2), counter.Value("b"))
+	require.Equal(t, float64(1), gauge.Value())
+	require.Equal(t, uint64(2), summary.Count(`/api/"x"`))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf))
+	require.Equal(t, `# HELP test_clients Number of clients.
+# TYPE test_clients gauge
+test_clients 1
+# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds summary
+test_duration_seconds_sum{path="/api/\"x\""} 1.75
+test_duration_seconds_count{path="/api/\"x\""} 2
+# HELP test_requests_total Number of requests.
+# TYPE test_requests_total counter
+test_requests_total{method="a"} 1
+test_requests_total{method="b"} 2
+`, buf.String())
+
+	require.Panics(t, func() { NewGauge("test_clients", "") })
+}
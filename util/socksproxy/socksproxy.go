@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
 	"github.com/BitBoxSwiss/bitbox-wallet-app/util/logging"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/proxy"
@@ -34,6 +35,10 @@ type SocksProxy struct {
 
 const defaultProxyAddress = "127.0.0.1:9050"
 
+// ErrInvalidAddress is the code of the error returned by Validate(). The `address` parameter is
+// the proxy address.
+const ErrInvalidAddress errp.ErrorCode = "socksproxy.invalidAddress"
+
 // NewSocksProxy returns a new socks proxy instance. If proxyAddress is the empty string, the default
 // address '127.0.0.1:9050' will be used.
 func NewSocksProxy(useProxy bool, proxyAddress string) SocksProxy {
@@ -57,11 +62,13 @@ func (socksProxy SocksProxy) Validate() error {
 		return nil
 	}
 	tbProxyURL, err := url.Parse(socksProxy.fullProxyAddress)
+	if err == nil {
+		_, err = proxy.FromURL(tbProxyURL, proxy.Direct)
+	}
 	if err != nil {
-		return err
+		return errp.WithCode(err, ErrInvalidAddress, errp.Context{"address": socksProxy.proxyAddress})
 	}
-	_, err = proxy.FromURL(tbProxyURL, proxy.Direct)
-	return err
+	return nil
 }
 
 // GetTCPProxyDialer returns a tcp connection. The connection is proxied, if useProxy is true.
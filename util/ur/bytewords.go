@@ -0,0 +1,106 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ur
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// bytewords is the list of words of the Bytewords encoding (BCR-2020-012), one per byte value.
+var bytewords = strings.Fields(`
+able acid also apex aqua arch atom aunt away axis back bald barn belt beta bias
+blue body brag brew bulb buzz calm cash cats chef city claw code cola cook cost
+crux curl cusp cyan dark data days deli dice diet door down draw drop drum dull
+duty each easy echo edge epic even exam exit eyes fact fair fern figs film fish
+fizz flap flew flux foxy free frog fuel fund gala game gear gems gift girl glow
+good gray grim guru gush gyro half hang hard hawk heat help high hill holy hope
+horn huts iced idea idle inch inky into iris iron item jade jazz join jolt jowl
+judo jugs jump junk jury keep keno kept keys kick kiln king kite kiwi knob lamb
+lava lazy leaf legs liar limp lion list logo loud love luau luck lung main many
+math maze memo menu meow mild mint miss monk nail navy need news next noon note
+numb obey oboe omit onyx open oval owls paid part peck play plus poem pool pose
+puff puma purr quad quiz race ramp real redo rich road rock roof ruby ruin runs
+rust safe saga scar sets silk skew slot soap solo song stub surf swan taco task
+taxi tent tied time tiny toil tomb toys trip tuna twin ugly undo unit urge user
+vast very veto vial vibe view visa void vows wall wand warm wasp wave waxy webs
+what when whiz wolf work yank yawn yell yoga yurt zaps zero zest zinc zone zoom
+`)
+
+// minimalBytewords maps the minimal form of each word (its first and last letter) to its byte.
+var minimalBytewords = func() map[string]byte {
+	result := make(map[string]byte, len(bytewords))
+	for i, word := range bytewords {
+		result[word[:1]+word[3:]] = byte(i)
+	}
+	return result
+}()
+
+func crc32Checksum(data []byte) uint32 {
+	return crc32.ChecksumIEEE(data)
+}
+
+// appendChecksum appends the big-endian CRC32 checksum of data.
+func appendChecksum(data []byte) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, data...), crc32Checksum(data))
+}
+
+// encodeBytewords encodes data followed by its checksum as space separated words.
+func encodeBytewords(data []byte) string {
+	words := []string{}
+	for _, b := range appendChecksum(data) {
+		words = append(words, bytewords[b])
+	}
+	return strings.Join(words, " ")
+}
+
+// encodeBytewordsMinimal encodes data followed by its checksum using two letters per byte, which
+// is the form used in URs.
+func encodeBytewordsMinimal(data []byte) string {
+	var result strings.Builder
+	for _, b := range appendChecksum(data) {
+		word := bytewords[b]
+		result.WriteByte(word[0])
+		result.WriteByte(word[3])
+	}
+	return result.String()
+}
+
+// decodeBytewordsMinimal decodes the minimal Bytewords encoding and verifies the checksum.
+func decodeBytewordsMinimal(encoded string) ([]byte, error) {
+	encoded = strings.ToLower(encoded)
+	if len(encoded)%2 != 0 {
+		return nil, errp.New("invalid bytewords length")
+	}
+	data := make([]byte, 0, len(encoded)/2)
+	for i := 0; i < len(encoded); i += 2 {
+		b, ok := minimalBytewords[encoded[i:i+2]]
+		if !ok {
+			return nil, errp.Newf("invalid byteword %q", encoded[i:i+2])
+		}
+		data = append(data, b)
+	}
+	if len(data) < 5 {
+		return nil, errp.New("bytewords too short")
+	}
+	payload, checksum := data[:len(data)-4], data[len(data)-4:]
+	if binary.BigEndian.Uint32(checksum) != crc32Checksum(payload) {
+		return nil, errp.New("invalid bytewords checksum")
+	}
+	return payload, nil
+}
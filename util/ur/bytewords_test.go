@@ -0,0 +1,52 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ur
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytewords(t *testing.T) {
+	require.Len(t, bytewords, 256)
+	require.Len(t, minimalBytewords, 256)
+
+	data := []byte{0, 1, 2, 128, 255}
+	require.Equal(t, "able acid also lava zoom jade need echo taxi", encodeBytewords(data))
+	require.Equal(t, "aeadaolazmjendeoti", encodeBytewordsMinimal(data))
+
+	decoded, err := decodeBytewordsMinimal("AEADAOLAZMJENDEOTI")
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	_, err = decodeBytewordsMinimal("aeadaolazmjendeotj")
+	require.Error(t, err)
+	_, err = decodeBytewordsMinimal("aeadaolazmjendeota")
+	require.Error(t, err)
+}
+
+func TestCBOR(t *testing.T) {
+	for _, value := range []uint64{0, 23, 24, 255, 256, 65535, 65536, 0xffffffff, 0x100000000} {
+		encoded := cborAppendHead(nil, cborUint, value)
+		decoded, rest, err := cborReadUint(encoded)
+		require.NoError(t, err)
+		require.Equal(t, value, decoded)
+		require.Empty(t, rest)
+	}
+	require.Equal(t, []byte{0x43, 1, 2, 3}, cborAppendBytes(nil, []byte{1, 2, 3}))
+	_, _, err := cborReadBytes([]byte{0x43, 1, 2})
+	require.Error(t, err)
+}
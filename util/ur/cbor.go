@@ -0,0 +1,107 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ur
+
+import (
+	"encoding/binary"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+// Only the subset of CBOR needed for URs is implemented: unsigned integers, byte strings and
+// arrays.
+const (
+	cborUint  = 0
+	cborBytes = 2
+	cborArray = 4
+)
+
+func cborAppendHead(buf []byte, major byte, value uint64) []byte {
+	major <<= 5
+	switch {
+	case value < 24:
+		return append(buf, major|byte(value))
+	case value <= 0xff:
+		return append(buf, major|24, byte(value))
+	case value <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(value))
+	case value <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), value)
+	}
+}
+
+func cborAppendBytes(buf []byte, data []byte) []byte {
+	return append(cborAppendHead(buf, cborBytes, uint64(len(data))), data...)
+}
+
+// cborReadHead reads the major type and argument of the next item and returns the remaining
+// data.
+func cborReadHead(data []byte) (byte, uint64, []byte, error) {
+	if len(data) == 0 {
+		return 0, 0, nil, errp.New("unexpected end of CBOR data")
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), data, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, nil, errp.New("unsupported CBOR item")
+	}
+	if len(data) < size {
+		return 0, 0, nil, errp.New("unexpected end of CBOR data")
+	}
+	var value uint64
+	for _, b := range data[:size] {
+		value = value<<8 | uint64(b)
+	}
+	return major, value, data[size:], nil
+}
+
+func cborReadUint(data []byte) (uint64, []byte, error) {
+	major, value, rest, err := cborReadHead(data)
+	if err != nil {
+		return 0, nil, err
+	}
+	if major != cborUint {
+		return 0, nil, errp.New("expected CBOR unsigned integer")
+	}
+	return value, rest, nil
+}
+
+func cborReadBytes(data []byte) ([]byte, []byte, error) {
+	major, length, rest, err := cborReadHead(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if major != cborBytes {
+		return nil, nil, errp.New("expected CBOR byte string")
+	}
+	if uint64(len(rest)) < length {
+		return nil, nil, errp.New("unexpected end of CBOR data")
+	}
+	return rest[:length], rest[length:], nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ur
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+)
+
+// xoshiro256 is the Xoshiro256** PRNG seeded as specified in BCR-2020-005, so that the fragments
+// mixed into each part match other implementations.
+type xoshiro256 [4]uint64
+
+func newXoshiro256(seed []byte) *xoshiro256 {
+	digest := sha256.Sum256(seed)
+	var rng xoshiro256
+	for i := range rng {
+		rng[i] = binary.BigEndian.Uint64(digest[i*8:])
+	}
+	return &rng
+}
+
+func (s *xoshiro256) next() uint64 {
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+func (s *xoshiro256) nextDouble() float64 {
+	return float64(s.next()) / (1 << 64)
+}
+
+// nextInt returns a number in [low, high].
+func (s *xoshiro256) nextInt(low, high int) int {
+	return int(uint64(s.nextDouble()*float64(high-low+1))) + low
+}
+
+// shuffled returns the items in a random order.
+func shuffled(items []int, rng *xoshiro256) []int {
+	remaining := append([]int{}, items...)
+	result := make([]int, 0, len(items))
+	for len(remaining) > 0 {
+		index := rng.nextInt(0, len(remaining)-1)
+		result = append(result, remaining[index])
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+	return result
+}
+
+// randomSampler picks indexes with the given weights using Vose's alias method.
+type randomSampler struct {
+	probs   []float64
+	aliases []int
+}
+
+func newRandomSampler(weights []float64) *randomSampler {
+	n := len(weights)
+	var sum float64
+	for _, weight := range weights {
+		sum += weight
+	}
+	probs := make([]float64, n)
+	for i, weight := range weights {
+		probs[i] = weight * float64(n) / sum
+	}
+	var small, large []int
+	for i := n - 1; i >= 0; i-- {
+		if probs[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	sampler := &randomSampler{probs: make([]float64, n), aliases: make([]int, n)}
+	for len(small) > 0 && len(large) > 0 {
+		a := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+		sampler.probs[a] = probs[a]
+		sampler.aliases[a] = g
+		probs[g] += probs[a] - 1
+		if probs[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	for _, i := range large {
+		sampler.probs[i] = 1
+	}
+	for _, i := range small {
+		sampler.probs[i] = 1
+	}
+	return sampler
+}
+
+func (sampler *randomSampler) next(rng *xoshiro256) int {
+	r1 := rng.nextDouble()
+	r2 := rng.nextDouble()
+	i := int(float64(len(sampler.probs)) * r1)
+	if r2 < sampler.probs[i] {
+		return i
+	}
+	return sampler.aliases[i]
+}
+
+// chooseFragments returns the indexes of the fragments that are mixed into the part with the given
+// sequence number. The first seqLen parts contain one fragment each, in order.
+func chooseFragments(seqNum uint32, seqLen int, checksum uint32) []int {
+	if int(seqNum) <= seqLen {
+		return []int{int(seqNum) - 1}
+	}
+	seed := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, seqNum), checksum)
+	rng := newXoshiro256(seed)
+	weights := make([]float64, seqLen)
+	indexes := make([]int, seqLen)
+	for i := range weights {
+		weights[i] = 1 / float64(i+1)
+		indexes[i] = i
+	}
+	degree := newRandomSampler(weights).next(rng) + 1
+	return shuffled(indexes, rng)[:degree]
+}
+
+// fragmentLength returns the smallest fragment length not above maxFragmentLen that splits the
+// message into equally long fragments.
+func fragmentLength(messageLen, minFragmentLen, maxFragmentLen int) int {
+	maxCount := messageLen / minFragmentLen
+	fragmentLen := messageLen
+	for count := 1; count <= maxCount; count++ {
+		fragmentLen = (messageLen + count - 1) / count
+		if fragmentLen <= maxFragmentLen {
+			break
+		}
+	}
+	if fragmentLen == 0 {
+		return messageLen
+	}
+	return fragmentLen
+}
+
+func xorInto(target, source []byte) {
+	for i := range target {
+		target[i] ^= source[i]
+	}
+}
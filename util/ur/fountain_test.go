@@ -0,0 +1,53 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ur
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestXoshiro256(t *testing.T) {
+	rng := newXoshiro256([]byte("Wolf"))
+	numbers := []uint64{}
+	for i := 0; i < 10; i++ {
+		numbers = append(numbers, rng.next()%100)
+	}
+	require.Equal(t, []uint64{42, 81, 85, 8, 82, 84, 76, 73, 70, 88}, numbers)
+}
+
+func TestRandomSampler(t *testing.T) {
+	sampler := newRandomSampler([]float64{1, 2, 4, 8})
+	rng := newXoshiro256([]byte("Wolf"))
+	samples := []int{}
+	for i := 0; i < 20; i++ {
+		samples = append(samples, sampler.next(rng))
+	}
+	require.Equal(t, []int{3, 3, 3, 3, 3, 3, 3, 0, 2, 3, 3, 3, 3, 1, 2, 2, 1, 3, 3, 2}, samples)
+}
+
+func TestShuffled(t *testing.T) {
+	rng := newXoshiro256([]byte("Wolf"))
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	require.Equal(t, []int{6, 4, 9, 3, 10, 5, 7, 8, 1, 2}, shuffled(items, rng))
+	require.Equal(t, []int{10, 8, 6, 5, 1, 2, 3, 9, 7, 4}, shuffled(items, rng))
+}
+
+func TestFragmentLength(t *testing.T) {
+	require.Equal(t, 1764, fragmentLength(12345, 1005, 1955))
+	require.Equal(t, 12345, fragmentLength(12345, 1005, 30000))
+	require.Equal(t, 5, fragmentLength(5, 10, 100))
+}
@@ -0,0 +1,319 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ur implements Uniform Resources (BCR-2020-005), which encode binary data like PSBTs as
+// a sequence of QR codes. Payloads that do not fit into one QR code are split into fragments with
+// a fountain code, so that the receiver can reassemble them from any sufficiently large subset of
+// the displayed frames.
+package ur
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+)
+
+const (
+	// TypePSBT is the UR type of a PSBT.
+	TypePSBT = "crypto-psbt"
+	// TypeBytes is the UR type of arbitrary data, e.g. an output descriptor as text.
+	TypeBytes = "bytes"
+
+	minFragmentLen = 10
+)
+
+var typeRegexp = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// part is one fragment of a multipart UR, possibly a mix of several fragments.
+type part struct {
+	seqNum     uint32
+	seqLen     int
+	messageLen int
+	checksum   uint32
+	data       []byte
+}
+
+func (p *part) encode() []byte {
+	buf := cborAppendHead(nil, cborArray, 5)
+	buf = cborAppendHead(buf, cborUint, uint64(p.seqNum))
+	buf = cborAppendHead(buf, cborUint, uint64(p.seqLen))
+	buf = cborAppendHead(buf, cborUint, uint64(p.messageLen))
+	buf = cborAppendHead(buf, cborUint, uint64(p.checksum))
+	return cborAppendBytes(buf, p.data)
+}
+
+func decodePart(data []byte) (*part, error) {
+	major, length, data, err := cborReadHead(data)
+	if err != nil {
+		return nil, err
+	}
+	if major != cborArray || length != 5 {
+		return nil, errp.New("invalid UR part")
+	}
+	var values [4]uint64
+	for i := range values {
+		values[i], data, err = cborReadUint(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	fragment, _, err := cborReadBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if values[0] == 0 || values[0] > 0xffffffff || values[1] == 0 || values[1] > values[2] ||
+		values[2] > 1<<24 || values[3] > 0xffffffff || uint64(len(fragment))*values[1] < values[2] {
+		return nil, errp.New("invalid UR part")
+	}
+	return &part{
+		seqNum:     uint32(values[0]),
+		seqLen:     int(values[1]),
+		messageLen: int(values[2]),
+		checksum:   uint32(values[3]),
+		data:       fragment,
+	}, nil
+}
+
+// Encode encodes the payload as UR of the given type. If the payload fits into maxFragmentLen
+// bytes, a single part is returned. Otherwise, count parts are returned, or twice the number of
+// fragments if count is smaller. Displaying the parts in a loop lets the receiver recover from
+// missed frames.
+func Encode(urType string, payload []byte, maxFragmentLen int, count int) ([]string, error) {
+	if !typeRegexp.MatchString(urType) {
+		return nil, errp.Newf("invalid UR type %q", urType)
+	}
+	if maxFragmentLen < minFragmentLen {
+		return nil, errp.Newf("the maximum fragment length must be at least %d", minFragmentLen)
+	}
+	message := cborAppendBytes(nil, payload)
+	fragmentLen := fragmentLength(len(message), minFragmentLen, maxFragmentLen)
+	seqLen := (len(message) + fragmentLen - 1) / fragmentLen
+	if seqLen == 1 {
+		return []string{fmt.Sprintf("ur:%s/%s", urType, encodeBytewordsMinimal(message))}, nil
+	}
+	padded := make([]byte, seqLen*fragmentLen)
+	copy(padded, message)
+	checksum := crc32Checksum(message)
+	if count < 2*seqLen {
+		count = 2 * seqLen
+	}
+	parts := make([]string, count)
+	for i := range parts {
+		seqNum := uint32(i + 1)
+		data := make([]byte, fragmentLen)
+		for _, index := range chooseFragments(seqNum, seqLen, checksum) {
+			xorInto(data, padded[index*fragmentLen:(index+1)*fragmentLen])
+		}
+		p := &part{
+			seqNum:     seqNum,
+			seqLen:     seqLen,
+			messageLen: len(message),
+			checksum:   checksum,
+			data:       data,
+		}
+		parts[i] = fmt.Sprintf("ur:%s/%d-%d/%s", urType, seqNum, seqLen, encodeBytewordsMinimal(p.encode()))
+	}
+	return parts, nil
+}
+
+// Decoded is the result of decoding scanned UR parts.
+type Decoded struct {
+	Type string
+	// Complete is true if the payload could be reassembled.
+	Complete bool
+	// Progress is the ratio of fragments recovered so far, between 0 and 1.
+	Progress float64
+	// Payload is only set if Complete is true.
+	Payload []byte
+}
+
+// mixedPart is a part whose fragments are not all known yet.
+type mixedPart struct {
+	indexes map[int]struct{}
+	data    []byte
+}
+
+// Decode reassembles the payload from the parts scanned so far. The parts can be in any order and
+// may contain duplicates, but all need to belong to the same UR. Call it again with more parts
+// until the result is complete.
+func Decode(parts []string) (*Decoded, error) {
+	if len(parts) == 0 {
+		return nil, errp.New("no UR parts")
+	}
+	var urType string
+	var first *part
+	fragments := map[int][]byte{}
+	var mixed []*mixedPart
+	for _, encoded := range parts {
+		partType, components, err := parseUR(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if urType == "" {
+			urType = partType
+		} else if partType != urType {
+			return nil, errp.New("UR parts of different types")
+		}
+		if len(components) == 1 {
+			if len(parts) != 1 {
+				return nil, errp.New("single part UR mixed with other parts")
+			}
+			message, err := decodeBytewordsMinimal(components[0])
+			if err != nil {
+				return nil, err
+			}
+			return decodeMessage(urType, message)
+		}
+		p, err := parseMultipart(components)
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = p
+		} else if p.seqLen != first.seqLen || p.messageLen != first.messageLen ||
+			p.checksum != first.checksum || len(p.data) != len(first.data) {
+			return nil, errp.New("UR parts of different messages")
+		}
+		indexes := map[int]struct{}{}
+		for _, index := range chooseFragments(p.seqNum, p.seqLen, p.checksum) {
+			indexes[index] = struct{}{}
+		}
+		mixed = append(mixed, &mixedPart{indexes: indexes, data: p.data})
+	}
+
+	mixed = reduceParts(mixed, fragments)
+	if len(fragments) < first.seqLen {
+		return &Decoded{
+			Type:     urType,
+			Progress: float64(len(fragments)) / float64(first.seqLen),
+		}, nil
+	}
+	message := make([]byte, 0, first.seqLen*len(first.data))
+	for i := 0; i < first.seqLen; i++ {
+		message = append(message, fragments[i]...)
+	}
+	message = message[:first.messageLen]
+	if crc32Checksum(message) != first.checksum {
+		return nil, errp.New("invalid UR checksum")
+	}
+	return decodeMessage(urType, message)
+}
+
+// reduceParts moves all fragments that can be recovered from the mixed parts into fragments and
+// returns the parts that are still mixed.
+func reduceParts(mixed []*mixedPart, fragments map[int][]byte) []*mixedPart {
+	for changed := true; changed; {
+		changed = false
+		var remaining []*mixedPart
+		for _, p := range mixed {
+			for index := range p.indexes {
+				if fragment, ok := fragments[index]; ok {
+					xorInto(p.data, fragment)
+					delete(p.indexes, index)
+				}
+			}
+			switch len(p.indexes) {
+			case 0:
+				changed = true
+			case 1:
+				for index := range p.indexes {
+					fragments[index] = p.data
+				}
+				changed = true
+			default:
+				remaining = append(remaining, p)
+			}
+		}
+		mixed = remaining
+		// A part that contains all fragments of another part can be reduced by the other part.
+		for _, a := range mixed {
+			for _, b := range mixed {
+				if a == b || len(a.indexes) == 0 || len(a.indexes) >= len(b.indexes) || !isSubset(a.indexes, b.indexes) {
+					continue
+				}
+				xorInto(b.data, a.data)
+				for index := range a.indexes {
+					delete(b.indexes, index)
+				}
+				changed = true
+			}
+		}
+	}
+	return mixed
+}
+
+func isSubset(a, b map[int]struct{}) bool {
+	for index := range a {
+		if _, ok := b[index]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseUR splits a UR into its type and the remaining path components.
+func parseUR(encoded string) (string, []string, error) {
+	encoded = strings.ToLower(strings.TrimSpace(encoded))
+	if !strings.HasPrefix(encoded, "ur:") {
+		return "", nil, errp.New("not a UR")
+	}
+	components := strings.Split(strings.TrimPrefix(encoded, "ur:"), "/")
+	if len(components) < 2 || len(components) > 3 || !typeRegexp.MatchString(components[0]) {
+		return "", nil, errp.New("invalid UR")
+	}
+	return components[0], components[1:], nil
+}
+
+// parseMultipart parses the "<seqNum>-<seqLen>" and body components of a multipart UR.
+func parseMultipart(components []string) (*part, error) {
+	seq := strings.Split(components[0], "-")
+	if len(seq) != 2 {
+		return nil, errp.New("invalid UR sequence")
+	}
+	seqNum, err := strconv.ParseUint(seq[0], 10, 32)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	seqLen, err := strconv.ParseUint(seq[1], 10, 32)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	body, err := decodeBytewordsMinimal(components[1])
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodePart(body)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(p.seqNum) != seqNum || uint64(p.seqLen) != seqLen {
+		return nil, errp.New("UR sequence does not match its body")
+	}
+	return p, nil
+}
+
+// decodeMessage unwraps the CBOR byte string containing the payload.
+func decodeMessage(urType string, message []byte) (*Decoded, error) {
+	payload, rest, err := cborReadBytes(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errp.New("unexpected data after the UR payload")
+	}
+	return &Decoded{Type: urType, Complete: true, Progress: 1, Payload: payload}, nil
+}
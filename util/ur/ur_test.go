@@ -0,0 +1,91 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ur
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeSinglePart(t *testing.T) {
+	parts, err := Encode(TypeBytes, []byte{1, 2, 3}, 100, 0)
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	require.True(t, strings.HasPrefix(parts[0], "ur:bytes/"))
+
+	decoded, err := Decode([]string{strings.ToUpper(parts[0])})
+	require.NoError(t, err)
+	require.True(t, decoded.Complete)
+	require.Equal(t, TypeBytes, decoded.Type)
+	require.Equal(t, []byte{1, 2, 3}, decoded.Payload)
+}
+
+func TestEncodeDecodeMultipart(t *testing.T) {
+	payload := bytes.Repeat([]byte("psbt payload "), 40)
+	parts, err := Encode(TypePSBT, payload, 50, 30)
+	require.NoError(t, err)
+	require.Len(t, parts, 30)
+	require.True(t, strings.HasPrefix(parts[0], "ur:crypto-psbt/1-11/"))
+
+	// The first fragment alone is not enough.
+	decoded, err := Decode(parts[:1])
+	require.NoError(t, err)
+	require.False(t, decoded.Complete)
+	require.InDelta(t, 1.0/11, decoded.Progress, 0.0001)
+	require.Nil(t, decoded.Payload)
+
+	// All pure fragments in any order.
+	reversed := []string{}
+	for i := 10; i >= 0; i-- {
+		reversed = append(reversed, parts[i])
+	}
+	decoded, err = Decode(reversed)
+	require.NoError(t, err)
+	require.True(t, decoded.Complete)
+	require.Equal(t, payload, decoded.Payload)
+
+	// Missed the first frames and only saw mixed parts.
+	decoded, err = Decode(append(parts[3:11], parts[11:]...))
+	require.NoError(t, err)
+	require.True(t, decoded.Complete)
+	require.Equal(t, TypePSBT, decoded.Type)
+	require.Equal(t, payload, decoded.Payload)
+
+	decoded, err = Decode(parts[11:])
+	require.NoError(t, err)
+	require.True(t, decoded.Complete)
+	require.Equal(t, payload, decoded.Payload)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	parts, err := Encode(TypePSBT, bytes.Repeat([]byte{7}, 100), 20, 0)
+	require.NoError(t, err)
+	otherParts, err := Encode(TypePSBT, bytes.Repeat([]byte{8}, 100), 20, 0)
+	require.NoError(t, err)
+
+	_, err = Decode(nil)
+	require.Error(t, err)
+	_, err = Decode([]string{"bitcoin:abc"})
+	require.Error(t, err)
+	_, err = Decode([]string{parts[0], otherParts[1]})
+	require.Error(t, err)
+	_, err = Decode([]string{strings.Replace(parts[0], "/1-", "/2-", 1)})
+	require.Error(t, err)
+	_, err = Encode("Crypto PSBT", []byte{1}, 20, 0)
+	require.Error(t, err)
+}
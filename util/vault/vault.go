@@ -0,0 +1,493 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vault encrypts files at rest with keys derived from a user passphrase.
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/crypto"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/errp"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/locker"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/random"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrLocked is returned when an encrypted file is accessed before the vault is unlocked.
+var ErrLocked = errors.New("vault locked")
+
+// ErrWrongPassphrase is returned if the passphrase does not match the one the vault was enabled
+// with.
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// ErrNotEncrypted is returned when a plaintext file is read while the encryption is enabled. All
+// files are rewritten encrypted when the encryption is enabled, so a plaintext file was put there
+// by someone else.
+var ErrNotEncrypted = errors.New("file not encrypted")
+
+// ErrParamsMissing is returned when unlocking a vault whose params file was deleted while encrypted
+// files remain. The files can't be decrypted without it.
+var ErrParamsMissing = errors.New("encryption params missing")
+
+// encryptedFilePrefix marks an encrypted file. Files without it are only read while the encryption
+// is disabled.
+var encryptedFilePrefix = []byte("BBAPPVAULT1")
+
+// checkPlaintext is encrypted with the derived keys to be able to verify a passphrase.
+var checkPlaintext = []byte("bitbox-wallet-app vault")
+
+const (
+	// scrypt parameters, see https://pkg.go.dev/golang.org/x/crypto/scrypt.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	keySize  = 32
+	saltSize = 32
+)
+
+// params is persisted while the encryption is enabled.
+type params struct {
+	Salt []byte `json:"salt"`
+	// Check is checkPlaintext encrypted with the derived keys.
+	Check []byte `json:"check"`
+}
+
+type keys struct {
+	encryptionKey     []byte
+	authenticationKey []byte
+}
+
+func deriveKeys(passphrase string, salt []byte) (*keys, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 2*keySize)
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	return &keys{encryptionKey: key[:keySize], authenticationKey: key[keySize:]}, nil
+}
+
+func (keys *keys) encrypt(plaintext []byte) ([]byte, error) {
+	return crypto.EncryptThenMAC(plaintext, keys.encryptionKey, keys.authenticationKey)
+}
+
+func (keys *keys) decrypt(ciphertext []byte) ([]byte, error) {
+	// IV, at least one block and the MAC.
+	if len(ciphertext) < 2*aes.BlockSize+sha256.Size {
+		return nil, errp.New("ciphertext too short")
+	}
+	// MACThenDecrypt decrypts in place.
+	ciphertext = append([]byte{}, ciphertext...)
+	return crypto.MACThenDecrypt(ciphertext, keys.encryptionKey, keys.authenticationKey)
+}
+
+// Codec encrypts and decrypts file contents according to an encryption state of the vault.
+type Codec struct {
+	// enabled is true if the contents must be encrypted.
+	enabled bool
+	// keys is nil if the vault is locked.
+	keys *keys
+}
+
+// Encrypt encrypts data if the encryption is enabled. ErrLocked is returned if the encryption is
+// enabled and the vault is locked.
+func (codec *Codec) Encrypt(data []byte) ([]byte, error) {
+	if !codec.enabled {
+		return data, nil
+	}
+	if codec.keys == nil {
+		return nil, ErrLocked
+	}
+	ciphertext, err := codec.keys.encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, encryptedFilePrefix...), ciphertext...), nil
+}
+
+// Decrypt decrypts contents returned by Encrypt(). ErrLocked is returned if the contents are
+// encrypted and the vault is locked, and ErrNotEncrypted if the contents are plaintext but the
+// encryption is enabled.
+func (codec *Codec) Decrypt(contents []byte) ([]byte, error) {
+	if !bytes.HasPrefix(contents, encryptedFilePrefix) {
+		if codec.enabled {
+			return nil, ErrNotEncrypted
+		}
+		return contents, nil
+	}
+	if codec.keys == nil {
+		return nil, ErrLocked
+	}
+	return codec.keys.decrypt(contents[len(encryptedFilePrefix):])
+}
+
+// EncryptString is like Encrypt(), but for strings stored in plaintext files, e.g. in a JSON field.
+// The encrypted string is base64 encoded. Empty strings and strings that are already encrypted are
+// returned unchanged.
+func (codec *Codec) EncryptString(s string) (string, error) {
+	if !codec.enabled || s == "" || isEncryptedString(s) {
+		return s, nil
+	}
+	contents, err := codec.Encrypt([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(contents), nil
+}
+
+// DecryptString decrypts a string returned by EncryptString(). Empty strings are returned
+// unchanged. The errors are the same as the ones of Decrypt().
+func (codec *Codec) DecryptString(s string) (string, error) {
+	if s == "" {
+		return s, nil
+	}
+	contents := []byte(s)
+	if isEncryptedString(s) {
+		contents, _ = base64.StdEncoding.DecodeString(s)
+	}
+	data, err := codec.Decrypt(contents)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// isEncryptedString returns true if s was encrypted by EncryptString().
+func isEncryptedString(s string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	return err == nil && bytes.HasPrefix(decoded, encryptedFilePrefix)
+}
+
+// File is a file that is rewritten when the encryption is enabled or disabled.
+type File struct {
+	Filename string
+	// Reencode returns the contents for the new encryption state, given the current contents and
+	// the codecs of the current and the new state. If nil, the whole file is decrypted and
+	// encrypted again, as written by WriteFile().
+	Reencode func(contents []byte, from, to *Codec) ([]byte, error)
+}
+
+// Vault reads and writes files, encrypting them if the encryption is enabled. The encryption is
+// enabled with a passphrase, and the vault must be unlocked with the same passphrase after each
+// start before encrypted files can be read or written.
+//
+// A nil *Vault reads and writes plaintext files.
+type Vault struct {
+	paramsFilename string
+	params         *params
+	keys           *keys
+	// paramsMissing is true if the params file does not exist, but encrypted files do. The
+	// encryption then stays enabled, but the vault can't be unlocked.
+	paramsMissing bool
+	// lock is held for reading while a file is encoded and written, and for writing while the
+	// files are rewritten when enabling or disabling the encryption, so that no write is lost.
+	lock locker.Locker
+}
+
+// New creates a vault whose parameters are persisted in paramsFilename. If the file exists, the
+// encryption is enabled and the vault is locked. If it does not exist, but one of the given files is
+// encrypted, the params file was deleted. The vault then remains locked and Unlock() returns
+// ErrParamsMissing, so that deleting the params file does not turn off the rejection of plaintext
+// files.
+func New(paramsFilename string, filenames ...string) (*Vault, error) {
+	vault := &Vault{paramsFilename: paramsFilename}
+	jsonBytes, err := os.ReadFile(paramsFilename)
+	if os.IsNotExist(err) {
+		for _, filename := range filenames {
+			contents, err := os.ReadFile(filename)
+			if err == nil && bytes.HasPrefix(contents, encryptedFilePrefix) {
+				vault.paramsMissing = true
+				break
+			}
+		}
+		return vault, nil
+	}
+	if err != nil {
+		return nil, errp.WithStack(err)
+	}
+	var p params
+	if err := json.Unmarshal(jsonBytes, &p); err != nil {
+		return nil, errp.WithStack(err)
+	}
+	vault.params = &p
+	return vault, nil
+}
+
+// Codec returns the codec of the current encryption state, e.g. to decrypt strings read from a
+// plaintext file.
+func (vault *Vault) Codec() *Codec {
+	if vault == nil {
+		return &Codec{}
+	}
+	defer vault.lock.RLock()()
+	return vault.codec()
+}
+
+// codec returns the codec of the current encryption state. The lock must be held.
+func (vault *Vault) codec() *Codec {
+	if vault == nil {
+		return &Codec{}
+	}
+	return &Codec{enabled: vault.params != nil || vault.paramsMissing, keys: vault.keys}
+}
+
+// Enabled returns true if new files are written encrypted.
+func (vault *Vault) Enabled() bool {
+	if vault == nil {
+		return false
+	}
+	defer vault.lock.RLock()()
+	return vault.params != nil || vault.paramsMissing
+}
+
+// Locked returns true if the encryption is enabled and the vault was not unlocked yet.
+func (vault *Vault) Locked() bool {
+	if vault == nil {
+		return false
+	}
+	defer vault.lock.RLock()()
+	return vault.codec().enabled && vault.keys == nil
+}
+
+// Unlock derives the keys from the passphrase, so encrypted files can be read and written.
+// ErrWrongPassphrase is returned if the passphrase does not match.
+func (vault *Vault) Unlock(passphrase string) error {
+	defer vault.lock.Lock()()
+	if vault.paramsMissing {
+		return ErrParamsMissing
+	}
+	if vault.params == nil {
+		return errp.New("encryption not enabled")
+	}
+	keys, err := vault.verifyPassphrase(passphrase)
+	if err != nil {
+		return err
+	}
+	vault.keys = keys
+	return nil
+}
+
+// verifyPassphrase returns the keys derived from the passphrase, or ErrWrongPassphrase if the
+// passphrase does not match. The lock must be held and the encryption must be enabled.
+func (vault *Vault) verifyPassphrase(passphrase string) (*keys, error) {
+	keys, err := deriveKeys(passphrase, vault.params.Salt)
+	if err != nil {
+		return nil, err
+	}
+	check, err := keys.decrypt(vault.params.Check)
+	if err != nil || !bytes.Equal(check, checkPlaintext) {
+		return nil, ErrWrongPassphrase
+	}
+	return keys, nil
+}
+
+// Enable enables the encryption with the given passphrase and encrypts the given files. Files that
+// do not exist are skipped. Files written afterwards are encrypted.
+func (vault *Vault) Enable(passphrase string, files ...File) error {
+	defer vault.lock.Lock()()
+	if vault.codec().enabled {
+		return errp.New("encryption already enabled")
+	}
+	if passphrase == "" {
+		return errp.New("passphrase must not be empty")
+	}
+	salt := random.BytesOrPanic(saltSize)
+	keys, err := deriveKeys(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	check, err := keys.encrypt(checkPlaintext)
+	if err != nil {
+		return err
+	}
+	p := &params{Salt: salt, Check: check}
+	jsonBytes, err := json.Marshal(p)
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	err = vault.rewrite(files, &Codec{enabled: true, keys: keys}, func() error {
+		return writeFileAtomic(vault.paramsFilename, jsonBytes, 0600)
+	})
+	if err != nil {
+		return err
+	}
+	vault.params = p
+	vault.keys = keys
+	return nil
+}
+
+// Disable disables the encryption and decrypts the given files. Files that do not exist are
+// skipped. Files written afterwards are plaintext. The vault must be unlocked.
+func (vault *Vault) Disable(passphrase string, files ...File) error {
+	defer vault.lock.Lock()()
+	if vault.params == nil {
+		return errp.New("encryption not enabled")
+	}
+	if vault.keys == nil {
+		return ErrLocked
+	}
+	if _, err := vault.verifyPassphrase(passphrase); err != nil {
+		return err
+	}
+	err := vault.rewrite(files, &Codec{}, func() error {
+		return errp.WithStack(os.Remove(vault.paramsFilename))
+	})
+	if err != nil {
+		return err
+	}
+	vault.params = nil
+	vault.keys = nil
+	return nil
+}
+
+// rewrite re-encodes the given files from the current encryption state to the new one, and then
+// calls switchParams to persist the new state. All files are decoded before the first one is
+// replaced, and the replaced files are restored if a later step fails, so that they always match
+// the persisted state. The lock must be held for writing.
+func (vault *Vault) rewrite(files []File, to *Codec, switchParams func() error) error {
+	type rewrittenFile struct {
+		filename  string
+		perm      os.FileMode
+		original  []byte
+		reencoded []byte
+	}
+	from := vault.codec()
+	var rewritten []rewrittenFile
+	for _, file := range files {
+		info, err := os.Stat(file.Filename)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return errp.WithStack(err)
+		}
+		original, err := os.ReadFile(file.Filename)
+		if err != nil {
+			return errp.WithStack(err)
+		}
+		reencode := file.Reencode
+		if reencode == nil {
+			reencode = reencodeFile
+		}
+		reencoded, err := reencode(original, from, to)
+		if err != nil {
+			return errp.WithMessage(err, file.Filename)
+		}
+		rewritten = append(rewritten, rewrittenFile{
+			filename:  file.Filename,
+			perm:      info.Mode().Perm(),
+			original:  original,
+			reencoded: reencoded,
+		})
+	}
+	restore := func(files []rewrittenFile) {
+		for _, file := range files {
+			// Best effort, the error that caused the rollback is returned.
+			_ = writeFileAtomic(file.filename, file.original, file.perm)
+		}
+	}
+	for i, file := range rewritten {
+		if err := writeFileAtomic(file.filename, file.reencoded, file.perm); err != nil {
+			restore(rewritten[:i])
+			return err
+		}
+	}
+	if err := switchParams(); err != nil {
+		restore(rewritten)
+		return err
+	}
+	return nil
+}
+
+// reencodeFile decrypts a whole file with the current state and encrypts it with the new one.
+func reencodeFile(contents []byte, from, to *Codec) ([]byte, error) {
+	data, err := from.Decrypt(contents)
+	if err != nil {
+		return nil, err
+	}
+	return to.Encrypt(data)
+}
+
+// ReadFile reads a file, decrypting it if it is encrypted. ErrLocked is returned if the file is
+// encrypted and the vault is locked, and ErrNotEncrypted if the file is plaintext but the
+// encryption is enabled. Like os.ReadFile, an error satisfying os.IsNotExist() is returned if the
+// file does not exist.
+func (vault *Vault) ReadFile(filename string) ([]byte, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if vault != nil {
+		defer vault.lock.RLock()()
+	}
+	return vault.codec().Decrypt(contents)
+}
+
+// WriteFile writes a file, encrypting it if the encryption is enabled. ErrLocked is returned if the
+// encryption is enabled and the vault is locked, so that encrypted files are not overwritten. The
+// file is replaced atomically.
+func (vault *Vault) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	return vault.WriteFileEncoded(filename, perm, func(codec *Codec) ([]byte, error) {
+		return codec.Encrypt(data)
+	})
+}
+
+// WriteFileEncoded writes the contents returned by encode, which can use the codec to encrypt
+// parts of them. The encryption is not enabled or disabled while the file is encoded and written.
+// The file is replaced atomically.
+func (vault *Vault) WriteFileEncoded(
+	filename string, perm os.FileMode, encode func(codec *Codec) ([]byte, error)) error {
+	if vault != nil {
+		defer vault.lock.RLock()()
+	}
+	contents, err := encode(vault.codec())
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filename, contents, perm)
+}
+
+// writeFileAtomic writes the data to a temporary file, which then replaces the file, so that the
+// file is not left partially written.
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return errp.WithStack(err)
+	}
+	err = func() error {
+		defer tmpFile.Close() //nolint:errcheck
+		if err := tmpFile.Chmod(perm); err != nil {
+			return errp.WithStack(err)
+		}
+		if _, err := tmpFile.Write(data); err != nil {
+			return errp.WithStack(err)
+		}
+		return errp.WithStack(tmpFile.Sync())
+	}()
+	if err == nil {
+		err = errp.WithStack(os.Rename(tmpFile.Name(), filename))
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Shift Crypto AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/test"
+	"github.com/BitBoxSwiss/bitbox-wallet-app/util/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault(t *testing.T) {
+	dir := test.TstTempDir("vault")
+	paramsFile := filepath.Join(dir, "encryption.json")
+	file := filepath.Join(dir, "accounts.json")
+
+	v, err := vault.New(paramsFile, file)
+	require.NoError(t, err)
+	require.False(t, v.Enabled())
+	require.NoError(t, v.WriteFile(file, []byte("plaintext"), 0600))
+
+	// The given files are encrypted when enabling the encryption. Missing files are skipped.
+	require.NoError(t, v.Enable("passphrase", vault.File{Filename: file}, vault.File{Filename: filepath.Join(dir, "missing.json")}))
+	require.True(t, v.Enabled())
+	require.False(t, v.Locked())
+	raw, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "plaintext")
+	contents, err := v.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), contents)
+
+	require.NoError(t, v.WriteFile(file, []byte("secret"), 0600))
+	raw, err = os.ReadFile(file)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "secret")
+
+	// After a restart, the vault is locked.
+	v, err = vault.New(paramsFile, file)
+	require.NoError(t, err)
+	require.True(t, v.Locked())
+	_, err = v.ReadFile(file)
+	require.Equal(t, vault.ErrLocked, err)
+	require.Equal(t, vault.ErrLocked, v.WriteFile(file, []byte("overwrite"), 0600))
+	require.Equal(t, vault.ErrLocked, v.Disable("passphrase", vault.File{Filename: file}))
+
+	require.Equal(t, vault.ErrWrongPassphrase, v.Unlock("wrong"))
+	require.NoError(t, v.Unlock("passphrase"))
+	contents, err = v.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), contents)
+
+	// Plaintext files are rejected while the encryption is enabled.
+	plaintextFile := filepath.Join(dir, "plaintext.json")
+	require.NoError(t, os.WriteFile(plaintextFile, []byte("plaintext"), 0600))
+	_, err = v.ReadFile(plaintextFile)
+	require.Equal(t, vault.ErrNotEncrypted, err)
+
+	require.Equal(t, vault.ErrWrongPassphrase, v.Disable("wrong", vault.File{Filename: file}))
+	require.NoError(t, v.Disable("passphrase", vault.File{Filename: file}))
+	require.False(t, v.Enabled())
+	require.NoFileExists(t, paramsFile)
+	raw, err = os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), raw)
+
+	// A nil vault reads and writes plaintext.
+	var nilVault *vault.Vault
+	require.NoError(t, nilVault.WriteFile(file, []byte("plaintext"), 0600))
+	contents, err = nilVault.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), contents)
+}
+
+func TestVaultRollback(t *testing.T) {
+	dir := test.TstTempDir("vault-rollback")
+	paramsFile := filepath.Join(dir, "encryption.json")
+	file := filepath.Join(dir, "accounts.json")
+	require.NoError(t, os.WriteFile(file, []byte("plaintext"), 0600))
+
+	v, err := vault.New(paramsFile, file)
+	require.NoError(t, err)
+	// The params file can't be written, as a directory is in its place.
+	require.NoError(t, os.Mkdir(paramsFile, 0700))
+	require.Error(t, v.Enable("passphrase", vault.File{Filename: file}))
+	require.False(t, v.Enabled())
+	raw, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), raw)
+
+	// Nothing is rewritten if one of the files can't be decoded.
+	require.NoError(t, os.Remove(paramsFile))
+	v, err = vault.New(paramsFile)
+	require.NoError(t, err)
+	require.NoError(t, v.Enable("passphrase"))
+	encryptedFile := filepath.Join(dir, "notes.json")
+	require.NoError(t, v.WriteFile(encryptedFile, []byte("secret"), 0600))
+	encrypted, err := os.ReadFile(encryptedFile)
+	require.NoError(t, err)
+	require.Error(t, v.Disable("passphrase", vault.File{Filename: encryptedFile}, vault.File{Filename: file}))
+	require.True(t, v.Enabled())
+	raw, err = os.ReadFile(encryptedFile)
+	require.NoError(t, err)
+	require.Equal(t, encrypted, raw)
+}
+
+func TestVaultParamsMissing(t *testing.T) {
+	dir := test.TstTempDir("vault-params-missing")
+	paramsFile := filepath.Join(dir, "encryption.json")
+	file := filepath.Join(dir, "accounts.json")
+
+	v, err := vault.New(paramsFile)
+	require.NoError(t, err)
+	require.NoError(t, v.Enable("passphrase"))
+	require.NoError(t, v.WriteFile(file, []byte("secret"), 0600))
+
+	// Deleting the params file does not disable the encryption while encrypted files remain.
+	require.NoError(t, os.Remove(paramsFile))
+	v, err = vault.New(paramsFile, file)
+	require.NoError(t, err)
+	require.True(t, v.Enabled())
+	require.True(t, v.Locked())
+	require.Equal(t, vault.ErrParamsMissing, v.Unlock("passphrase"))
+	require.Error(t, v.Enable("passphrase"))
+	require.NoError(t, os.WriteFile(file, []byte("plaintext"), 0600))
+	_, err = v.ReadFile(file)
+	require.Equal(t, vault.ErrNotEncrypted, err)
+}
+
+func TestVaultStrings(t *testing.T) {
+	dir := test.TstTempDir("vault-strings")
+	paramsFile := filepath.Join(dir, "encryption.json")
+	file := filepath.Join(dir, "config.json")
+
+	v, err := vault.New(paramsFile)
+	require.NoError(t, err)
+	// Only the second line of the file is encrypted.
+	reencode := func(contents []byte, from, to *vault.Codec) ([]byte, error) {
+		lines := strings.Split(string(contents), "\n")
+		secret, err := from.DecryptString(lines[1])
+		if err != nil {
+			return nil, err
+		}
+		lines[1], err = to.EncryptString(secret)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+	require.NoError(t, os.WriteFile(file, []byte("public\nsecret"), 0600))
+
+	require.NoError(t, v.Enable("passphrase", vault.File{Filename: file, Reencode: reencode}))
+	raw, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(string(raw), "\n")
+	require.Equal(t, "public", lines[0])
+	require.NotEqual(t, "secret", lines[1])
+	secret, err := v.Codec().DecryptString(lines[1])
+	require.NoError(t, err)
+	require.Equal(t, "secret", secret)
+	// Plaintext strings are rejected while the encryption is enabled.
+	_, err = v.Codec().DecryptString("secret")
+	require.Equal(t, vault.ErrNotEncrypted, err)
+	// Encrypted strings are not encrypted twice, e.g. if they were read while the vault was locked.
+	encrypted, err := v.Codec().EncryptString(lines[1])
+	require.NoError(t, err)
+	require.Equal(t, lines[1], encrypted)
+
+	require.NoError(t, v.WriteFileEncoded(file, 0600, func(codec *vault.Codec) ([]byte, error) {
+		encrypted, err := codec.EncryptString("other secret")
+		return []byte("public\n" + encrypted), err
+	}))
+	require.NoError(t, v.Disable("passphrase", vault.File{Filename: file, Reencode: reencode}))
+	raw, err = os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "public\nother secret", string(raw))
+}